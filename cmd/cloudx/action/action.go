@@ -0,0 +1,261 @@
+package action
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-jsonnet"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+const (
+	timingBefore = "before"
+	timingAfter  = "after"
+
+	hookWebHook = "web_hook"
+)
+
+var (
+	flows   = []string{"login", "registration", "settings", "recovery", "verification"}
+	timings = []string{timingBefore, timingAfter}
+	hooks   = []string{hookWebHook, "session", "revoke_active_sessions", "require_verified_address", "show_verification_ui"}
+)
+
+// action is a hook configured in the self-service flows of the identity config.
+type action struct {
+	Index int    `json:"index"`
+	ID    string `json:"id"`
+	Flow  string `json:"flow"`
+	// Timing is either before or after.
+	Timing string `json:"timing"`
+	// AuthMethod is set if the hook is only executed after the flow was completed using this method.
+	AuthMethod string `json:"auth_method,omitempty"`
+	Hook       string `json:"hook"`
+	URL        string `json:"url,omitempty"`
+	Method     string `json:"method,omitempty"`
+	// Auth describes the authentication of a web hook. It never contains the secret itself.
+	Auth string `json:"auth,omitempty"`
+	// Path is the JSON pointer of the hook in the identity config.
+	Path string `json:"path"`
+
+	// config is the configuration of the hook including its secrets.
+	config map[string]interface{}
+}
+
+func oneOf(value, name string, valid []string) error {
+	for _, v := range valid {
+		if v == value {
+			return nil
+		}
+	}
+	return errors.Errorf("unknown %s %q, expected one of: %s", name, value, strings.Join(valid, ", "))
+}
+
+func lookup(config map[string]interface{}, path ...string) map[string]interface{} {
+	for _, p := range path {
+		next, ok := config[p].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		config = next
+	}
+	return config
+}
+
+// collectActions returns all hooks of the self-service flows in the identity config in a stable order.
+func collectActions(config map[string]interface{}) []action {
+	var actions []action
+	add := func(flow, timing, method string, container map[string]interface{}) {
+		hookList, _ := container["hooks"].([]interface{})
+		for k, raw := range hookList {
+			hook, _ := raw.(map[string]interface{})
+			a := action{Flow: flow, Timing: timing, AuthMethod: method}
+			a.Hook, _ = hook["hook"].(string)
+
+			path := []string{"", "selfservice", "flows", flow, timing}
+			if method != "" {
+				path = append(path, method)
+			}
+			a.Path = strings.Join(append(path, "hooks", strconv.Itoa(k)), "/")
+
+			if c, ok := hook["config"].(map[string]interface{}); ok {
+				a.config = c
+				a.URL, _ = c["url"].(string)
+				a.Method, _ = c["method"].(string)
+				if auth, ok := c["auth"].(map[string]interface{}); ok {
+					a.Auth = describeAuth(auth)
+				}
+			}
+
+			sum := sha256.Sum256([]byte(strings.Join([]string{a.Path, a.Hook, a.Method, a.URL}, "\n")))
+			a.ID = hex.EncodeToString(sum[:])[:8]
+			a.Index = len(actions)
+			actions = append(actions, a)
+		}
+	}
+
+	for _, flow := range flows {
+		for _, timing := range timings {
+			container := lookup(config, "selfservice", "flows", flow, timing)
+			if container == nil {
+				continue
+			}
+			add(flow, timing, "", container)
+			if timing == timingBefore {
+				continue
+			}
+
+			methods := make([]string, 0, len(container))
+			for method := range container {
+				if _, ok := container[method].(map[string]interface{}); ok {
+					methods = append(methods, method)
+				}
+			}
+			sort.Strings(methods)
+			for _, method := range methods {
+				add(flow, timing, method, lookup(container, method))
+			}
+		}
+	}
+	return actions
+}
+
+// lastAction returns the last action of the flow, timing, and method, i.e. the one added last, or nil if there is none.
+func lastAction(actions []action, flow, timing, method string) *action {
+	for k := len(actions) - 1; k >= 0; k-- {
+		if a := actions[k]; a.Flow == flow && a.Timing == timing && a.AuthMethod == method {
+			return &actions[k]
+		}
+	}
+	return nil
+}
+
+func describeAuth(auth map[string]interface{}) string {
+	t, _ := auth["type"].(string)
+	c, _ := auth["config"].(map[string]interface{})
+	switch t {
+	case "api_key":
+		in, _ := c["in"].(string)
+		name, _ := c["name"].(string)
+		return fmt.Sprintf("api_key (%s %s)", in, name)
+	case "basic_auth":
+		user, _ := c["user"].(string)
+		return fmt.Sprintf("basic_auth (user %s)", user)
+	}
+	return t
+}
+
+// findAction finds an action by its index or ID.
+func findAction(actions []action, ref string) (*action, error) {
+	for k := range actions {
+		if actions[k].ID == ref {
+			return &actions[k], nil
+		}
+	}
+	if idx, err := strconv.Atoi(ref); err == nil {
+		if idx < 0 || idx >= len(actions) {
+			return nil, errors.Errorf("action index %d is out of range, there are %d actions", idx, len(actions))
+		}
+		return &actions[idx], nil
+	}
+	return nil, errors.Errorf("unable to find an action with index or ID %q", ref)
+}
+
+type webHookOptions struct {
+	URL            string
+	Method         string
+	BodyFile       string
+	AuthHeaderName string
+	AuthHeaderEnv  string
+}
+
+// newHook builds the hook configuration. The body is validated as Jsonnet and embedded into the configuration
+// and the authentication secret is read from the environment.
+func newHook(cmd *cobra.Command, hook string, o *webHookOptions) (map[string]interface{}, error) {
+	if err := oneOf(hook, "hook", hooks); err != nil {
+		return nil, err
+	}
+
+	if hook != hookWebHook {
+		if o.URL != "" || o.BodyFile != "" || o.AuthHeaderEnv != "" {
+			return nil, errors.Errorf("only hooks of type %s can be configured with a URL, body, or authentication", hookWebHook)
+		}
+		return map[string]interface{}{"hook": hook}, nil
+	}
+
+	if o.URL == "" {
+		return nil, errors.Errorf("hooks of type %s require a URL", hookWebHook)
+	}
+
+	config := map[string]interface{}{
+		"url":    o.URL,
+		"method": o.Method,
+	}
+
+	if o.BodyFile != "" {
+		body, err := client.ReadInputFile(cmd, o.BodyFile)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := jsonnet.SnippetToAST(o.BodyFile, string(body)); err != nil {
+			return nil, client.NewValidationError(errors.Wrapf(err, "the body %s is not valid Jsonnet", o.BodyFile), nil)
+		}
+		config["body"] = "base64://" + base64.StdEncoding.EncodeToString(body)
+	}
+
+	if o.AuthHeaderEnv != "" {
+		secret := os.Getenv(o.AuthHeaderEnv)
+		if secret == "" {
+			return nil, errors.Errorf("the environment variable %s is empty or not set", o.AuthHeaderEnv)
+		}
+		config["auth"] = map[string]interface{}{
+			"type": "api_key",
+			"config": map[string]interface{}{
+				"name":  o.AuthHeaderName,
+				"value": secret,
+				"in":    "header",
+			},
+		}
+	}
+
+	return map[string]interface{}{"hook": hook, "config": config}, nil
+}
+
+// withHook returns the configuration of the flow with the hook appended. The result can be used to replace
+// the whole flow, which also works if the flow does not have any hooks yet.
+func withHook(config map[string]interface{}, flow, timing, method string, hook map[string]interface{}) (json.RawMessage, error) {
+	flowConfig := lookup(config, "selfservice", "flows", flow)
+	if flowConfig == nil {
+		flowConfig = map[string]interface{}{}
+	}
+
+	container, ok := flowConfig[timing].(map[string]interface{})
+	if !ok {
+		container = map[string]interface{}{}
+		flowConfig[timing] = container
+	}
+	if method != "" {
+		next, ok := container[method].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			container[method] = next
+		}
+		container = next
+	}
+
+	hookList, _ := container["hooks"].([]interface{})
+	container["hooks"] = append(hookList, hook)
+
+	out, err := json.Marshal(flowConfig)
+	return out, errors.WithStack(err)
+}
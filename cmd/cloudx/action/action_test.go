@@ -0,0 +1,134 @@
+package action
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+const identityConfig = `{
+  "selfservice": {
+    "flows": {
+      "registration": {
+        "before": {"hooks": [{"hook": "web_hook", "config": {"url": "https://example.com/before", "method": "GET"}}]},
+        "after": {
+          "hooks": [{"hook": "show_verification_ui"}],
+          "password": {"hooks": [
+            {"hook": "session"},
+            {"hook": "web_hook", "config": {"url": "https://example.com/x", "method": "POST", "auth": {"type": "api_key", "config": {"name": "Authorization", "value": "secret", "in": "header"}}}}
+          ]}
+        }
+      },
+      "settings": {"after": {}}
+    }
+  }
+}`
+
+func decodeConfig(t *testing.T, raw string) map[string]interface{} {
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &config))
+	return config
+}
+
+func TestCollectActions(t *testing.T) {
+	actions := collectActions(decodeConfig(t, identityConfig))
+	require.Len(t, actions, 4)
+
+	paths := make([]string, len(actions))
+	for k, a := range actions {
+		assert.Equal(t, k, a.Index)
+		assert.Len(t, a.ID, 8)
+		paths[k] = a.Path
+	}
+	assert.Equal(t, []string{
+		"/selfservice/flows/registration/before/hooks/0",
+		"/selfservice/flows/registration/after/hooks/0",
+		"/selfservice/flows/registration/after/password/hooks/0",
+		"/selfservice/flows/registration/after/password/hooks/1",
+	}, paths)
+
+	assert.Equal(t, "password", actions[3].AuthMethod)
+	assert.Equal(t, "https://example.com/x", actions[3].URL)
+	assert.Equal(t, "api_key (header Authorization)", actions[3].Auth)
+
+	out, err := json.Marshal(actions)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "secret")
+}
+
+func TestFindAction(t *testing.T) {
+	actions := collectActions(decodeConfig(t, identityConfig))
+
+	a, err := findAction(actions, "2")
+	require.NoError(t, err)
+	assert.Equal(t, "session", a.Hook)
+
+	a, err = findAction(actions, actions[3].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, a.Index)
+
+	_, err = findAction(actions, "4")
+	assert.Error(t, err)
+	_, err = findAction(actions, "unknown")
+	assert.Error(t, err)
+}
+
+func TestNewHook(t *testing.T) {
+	t.Run("case=web hook", func(t *testing.T) {
+		body := filepath.Join(t.TempDir(), "body.jsonnet")
+		require.NoError(t, os.WriteFile(body, []byte(`function(ctx) { email: ctx.identity.traits.email }`), 0600))
+		t.Setenv("HOOK_TOKEN", "secret")
+
+		hook, err := newHook(&cobra.Command{}, hookWebHook, &webHookOptions{URL: "https://example.com", Method: "POST", BodyFile: body, AuthHeaderName: "Authorization", AuthHeaderEnv: "HOOK_TOKEN"})
+		require.NoError(t, err)
+		out, err := json.Marshal(hook)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"hook":"web_hook","config":{"url":"https://example.com","method":"POST","body":"base64://ZnVuY3Rpb24oY3R4KSB7IGVtYWlsOiBjdHguaWRlbnRpdHkudHJhaXRzLmVtYWlsIH0=","auth":{"type":"api_key","config":{"name":"Authorization","value":"secret","in":"header"}}}}`, string(out))
+	})
+
+	t.Run("case=invalid jsonnet", func(t *testing.T) {
+		body := filepath.Join(t.TempDir(), "body.jsonnet")
+		require.NoError(t, os.WriteFile(body, []byte(`function(ctx) {`), 0600))
+		_, err := newHook(&cobra.Command{}, hookWebHook, &webHookOptions{URL: "https://example.com", BodyFile: body})
+		assert.ErrorContains(t, err, "not valid Jsonnet")
+	})
+
+	t.Run("case=missing secret", func(t *testing.T) {
+		_, err := newHook(&cobra.Command{}, hookWebHook, &webHookOptions{URL: "https://example.com", AuthHeaderEnv: "ORY_CLI_TEST_UNSET_ENV"})
+		assert.ErrorContains(t, err, "ORY_CLI_TEST_UNSET_ENV")
+	})
+
+	t.Run("case=other hooks", func(t *testing.T) {
+		hook, err := newHook(&cobra.Command{}, "session", &webHookOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"hook": "session"}, hook)
+
+		_, err = newHook(&cobra.Command{}, "session", &webHookOptions{URL: "https://example.com"})
+		assert.Error(t, err)
+		_, err = newHook(&cobra.Command{}, "unknown", &webHookOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestWithHook(t *testing.T) {
+	config := decodeConfig(t, identityConfig)
+	hook := map[string]interface{}{"hook": "session"}
+
+	out, err := withHook(config, "settings", timingAfter, "password", hook)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"after":{"password":{"hooks":[{"hook":"session"}]}}}`, string(out))
+
+	out, err = withHook(config, "login", timingBefore, "", hook)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"before":{"hooks":[{"hook":"session"}]}}`, string(out))
+
+	out, err = withHook(config, "registration", timingAfter, "", hook)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"hook":"show_verification_ui"},{"hook":"session"}]`, gjson.GetBytes(out, "after.hooks").Raw)
+}
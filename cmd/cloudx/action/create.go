@@ -0,0 +1,114 @@
+package action
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	flowFlag              = "flow"
+	timingFlag            = "timing"
+	authMethodFlag        = "auth-method"
+	hookFlag              = "hook"
+	urlFlag               = "url"
+	methodFlag            = "method"
+	bodyJsonnetFlag       = "body-jsonnet"
+	authHeaderNameFlag    = "auth-header-name"
+	authHeaderFromEnvFlag = "auth-header-from-env"
+)
+
+func NewCreateActionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "action",
+		Aliases: []string{"webhook"},
+		Args:    client.NoArgs,
+		Short:   "Add an action to a self-service flow of an Ory Cloud project",
+		Long: `Add an action (hook) which is executed before or after a self-service flow of the selected project.
+
+The body of web hooks is a Jsonnet file which is checked for syntax errors before it is uploaded. To authenticate
+web hooks, pass the name of an environment variable containing the secret using --auth-header-from-env. The secret
+is stored in the project's configuration and is not shown by ` + "`ory list actions`" + `.
+
+Use --auth-method to only execute the action if the flow was completed using a specific method, e.g. password.`,
+		Example: `$ ory create action --flow registration --hook web_hook --url https://hooks.example.com/x --method POST \
+	--body-jsonnet ./body.jsonnet --auth-header-from-env HOOK_TOKEN
+
+$ ory create action --flow login --timing after --hook session --project my-project --format json
+
+$ ory delete action "$(ory create action --flow login --hook session --id-only)"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			flow, timing := flagx.MustGetString(cmd, flowFlag), flagx.MustGetString(cmd, timingFlag)
+			if err := oneOf(flow, "flow", flows); err != nil {
+				return err
+			} else if err := oneOf(timing, "timing", timings); err != nil {
+				return err
+			}
+
+			method := flagx.MustGetString(cmd, authMethodFlag)
+			if method != "" && timing != timingAfter {
+				return errors.Errorf("--%s can only be used with actions executed after the flow", authMethodFlag)
+			}
+
+			hook, err := newHook(cmd, flagx.MustGetString(cmd, hookFlag), &webHookOptions{
+				URL:            flagx.MustGetString(cmd, urlFlag),
+				Method:         flagx.MustGetString(cmd, methodFlag),
+				BodyFile:       flagx.MustGetString(cmd, bodyJsonnetFlag),
+				AuthHeaderName: flagx.MustGetString(cmd, authHeaderNameFlag),
+				AuthHeaderEnv:  flagx.MustGetString(cmd, authHeaderFromEnvFlag),
+			})
+			if err != nil {
+				return err
+			}
+
+			id, err := h.ProjectID()
+			if err != nil {
+				return err
+			}
+
+			p, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			flowConfig, err := withHook(p.Services.Identity.Config, flow, timing, method, hook)
+			if err != nil {
+				return err
+			}
+
+			res, err := h.PatchProject(id, nil, []string{"/services/identity/config/selfservice/flows/" + flow + "=" + string(flowConfig)}, nil, nil)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			actions := collectActions(res.Project.Services.Identity.Config)
+			client.PrintTable(cmd, outputActions(actions))
+			if a := lastAction(actions, flow, timing, method); a != nil {
+				client.PrintIDs(cmd, a.ID)
+			}
+			return h.PrintUpdateProjectWarnings(res)
+		},
+	}
+
+	cmd.Flags().String(flowFlag, "", "The self-service flow (login, registration, settings, recovery, verification).")
+	cmd.Flags().String(timingFlag, timingAfter, "Whether to execute the action before or after the flow.")
+	cmd.Flags().String(authMethodFlag, "", "Only execute the action if the flow was completed using this method, e.g. password.")
+	cmd.Flags().String(hookFlag, hookWebHook, "The type of the hook.")
+	cmd.Flags().String(urlFlag, "", "The URL of the web hook.")
+	cmd.Flags().String(methodFlag, http.MethodPost, "The HTTP method of the web hook.")
+	cmd.Flags().String(bodyJsonnetFlag, "", "Path to the Jsonnet file used to render the body of the web hook. Use - to read from stdin.")
+	cmd.Flags().String(authHeaderNameFlag, "Authorization", "The name of the header used to authenticate the web hook.")
+	cmd.Flags().String(authHeaderFromEnvFlag, "", "The name of the environment variable containing the value of the authentication header.")
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterIDOnlyFlag(cmd.Flags())
+	return client.MarkDryRunCapable(cmd)
+}
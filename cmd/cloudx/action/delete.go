@@ -0,0 +1,65 @@
+package action
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func NewDeleteActionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "action <index|id>",
+		Aliases: []string{"webhook"},
+		Args:    client.ExactArgs(1),
+		Short:   "Remove an action from a self-service flow of an Ory Cloud project",
+		Long: `Remove an action (hook) from the self-service flows of the selected project. The action is identified by
+its index or ID as shown by ` + "`ory list actions`" + `. The removal needs to be confirmed or requires --yes.`,
+		Example: `$ ory delete action 9b1d0e7a --yes
+
+$ ory delete action 1 --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			id, err := h.ProjectID()
+			if err != nil {
+				return err
+			}
+
+			p, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			a, err := findAction(collectActions(p.Services.Identity.Config), args[0])
+			if err != nil {
+				return err
+			}
+
+			summary := []client.SummaryLine{
+				{Key: "ID", Value: a.ID},
+				{Key: "Flow", Value: a.Timing + " " + a.Flow},
+				{Key: "Hook", Value: a.Hook},
+			}
+			if a.URL != "" {
+				summary = append(summary, client.SummaryLine{Key: "URL", Value: a.Method + " " + a.URL})
+			}
+			if err := h.ConfirmDestruction(&client.Destruction{Action: "remove the action", Summary: summary}); err != nil {
+				return err
+			}
+
+			res, err := h.PatchProject(id, nil, nil, nil, []string{"/services/identity/config" + a.Path})
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			client.PrintTable(cmd, outputActions(collectActions(res.Project.Services.Identity.Config)))
+			return h.PrintUpdateProjectWarnings(res)
+		},
+	}
+
+	client.RegisterProjectFlag(cmd.Flags())
+	return client.MarkDryRunCapable(cmd)
+}
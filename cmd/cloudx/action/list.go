@@ -0,0 +1,50 @@
+package action
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func NewListActionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "actions",
+		Aliases:     []string{"action", "webhooks"},
+		Args:        client.NoArgs,
+		Short:       "List the actions of an Ory Cloud project",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "url"},
+		Long: `List the actions (hooks) executed before or after the self-service flows of the selected project.
+
+Secrets used to authenticate web hooks are never shown.`,
+		Example: `$ ory list actions
+
+INDEX	ID		FLOW		TIMING	AUTH METHOD	HOOK		METHOD	URL				AUTH
+0	4f2a9c1e	registration	after	password	session
+1	9b1d0e7a	registration	after	password	web_hook	POST	https://hooks.example.com/x	api_key (header Authorization)
+
+$ ory list actions --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			id, err := h.ProjectID()
+			if err != nil {
+				return err
+			}
+
+			p, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			client.PrintTable(cmd, outputActions(collectActions(p.Services.Identity.Config)))
+			return nil
+		},
+	}
+
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterSortFlags(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,58 @@
+package action
+
+import (
+	"fmt"
+	"time"
+)
+
+type outputActions []action
+
+func (outputActions) Header() []string {
+	return []string{"INDEX", "ID", "FLOW", "TIMING", "AUTH METHOD", "HOOK", "METHOD", "URL", "AUTH"}
+}
+
+func (a outputActions) Table() [][]string {
+	rows := make([][]string, len(a))
+	for k, action := range a {
+		rows[k] = []string{
+			fmt.Sprintf("%d", action.Index),
+			action.ID,
+			action.Flow,
+			action.Timing,
+			action.AuthMethod,
+			action.Hook,
+			action.Method,
+			action.URL,
+			action.Auth,
+		}
+	}
+	return rows
+}
+
+func (a outputActions) Interface() interface{} {
+	return a
+}
+
+func (a outputActions) Len() int {
+	return len(a)
+}
+
+type testResult struct {
+	Method     string        `json:"method"`
+	URL        string        `json:"url"`
+	StatusCode int           `json:"status_code"`
+	Latency    time.Duration `json:"latency"`
+	Body       string        `json:"body"`
+}
+
+func (*testResult) Header() []string {
+	return []string{"METHOD", "URL", "STATUS CODE", "LATENCY", "RESPONSE BODY"}
+}
+
+func (r *testResult) Columns() []string {
+	return []string{r.Method, r.URL, fmt.Sprintf("%d", r.StatusCode), r.Latency.Round(time.Millisecond).String(), r.Body}
+}
+
+func (r *testResult) Interface() interface{} {
+	return r
+}
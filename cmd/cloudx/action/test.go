@@ -0,0 +1,239 @@
+package action
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-jsonnet"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	hookIndexFlag    = "hook-index"
+	identityFileFlag = "identity-file"
+
+	redacted = "[REDACTED]"
+)
+
+var sampleIdentity = json.RawMessage(`{
+  "id": "9f425a8d-7efc-4768-8f23-7647a74fdf13",
+  "schema_id": "default",
+  "state": "active",
+  "traits": {"email": "jane@example.com"},
+  "verifiable_addresses": [{"value": "jane@example.com", "verified": false, "via": "email", "status": "pending"}],
+  "recovery_addresses": [{"value": "jane@example.com", "via": "email"}]
+}`)
+
+// webHookRequest is a web hook request rendered from an action's configuration.
+type webHookRequest struct {
+	Method string
+	URL    string
+	Body   []byte
+	Header http.Header
+	// secrets contains the values used to authenticate the request.
+	secrets []string
+}
+
+// redact replaces all secrets of the request in s.
+func (r *webHookRequest) redact(s string) string {
+	for _, secret := range r.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
+
+// renderWebHook renders the body of the web hook using the identity and builds the request the same way the project
+// would.
+func renderWebHook(a *action, identity json.RawMessage) (*webHookRequest, error) {
+	if a.Hook != hookWebHook || a.config == nil {
+		return nil, errors.Errorf("only actions of type %s can be tested but action %d is of type %s", hookWebHook, a.Index, a.Hook)
+	}
+
+	r := &webHookRequest{Method: a.Method, URL: a.URL, Header: http.Header{"Content-Type": {"application/json"}}}
+	if r.Method == "" {
+		r.Method = http.MethodPost
+	}
+
+	if body, _ := a.config["body"].(string); body != "" {
+		if !strings.HasPrefix(body, "base64://") {
+			return nil, errors.Errorf("the body of the action is loaded from %q and can not be rendered locally", body)
+		}
+		snippet, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(body, "base64://"))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to decode the body of the action")
+		}
+
+		ctx, err := json.Marshal(map[string]interface{}{
+			"identity":       identity,
+			"flow":           map[string]interface{}{"type": "api", "ui": map[string]interface{}{}},
+			"request_method": r.Method,
+			"request_url":    r.URL,
+			"request_headers": map[string][]string{
+				"User-Agent": {"Ory CLI"},
+			},
+		})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		vm := jsonnet.MakeVM()
+		vm.TLACode("ctx", string(ctx))
+		rendered, err := vm.EvaluateAnonymousSnippet("body.jsonnet", string(snippet))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to render the body of the action")
+		}
+		r.Body = []byte(rendered)
+	}
+
+	if auth, ok := a.config["auth"].(map[string]interface{}); ok {
+		c, _ := auth["config"].(map[string]interface{})
+		switch t, _ := auth["type"].(string); t {
+		case "api_key":
+			name, _ := c["name"].(string)
+			value, _ := c["value"].(string)
+			switch in, _ := c["in"].(string); in {
+			case "cookie":
+				r.Header.Add("Cookie", (&http.Cookie{Name: name, Value: value}).String())
+			default:
+				r.Header.Set(name, value)
+			}
+			r.secrets = append(r.secrets, value)
+		case "basic_auth":
+			user, _ := c["user"].(string)
+			password, _ := c["password"].(string)
+			encoded := base64.StdEncoding.EncodeToString([]byte(user + ":" + password))
+			r.Header.Set("Authorization", "Basic "+encoded)
+			r.secrets = append(r.secrets, password, encoded)
+		default:
+			return nil, errors.Errorf("unsupported authentication type %q", t)
+		}
+	}
+
+	return r, nil
+}
+
+func (r *webHookRequest) send(ctx context.Context, c *http.Client) (*testResult, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header = r.Header
+
+	start := time.Now()
+	res, err := c.Do(req)
+	if err != nil {
+		return nil, errors.New(r.redact(err.Error()))
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	latency := time.Since(start)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &testResult{
+		Method:     r.Method,
+		URL:        r.URL,
+		StatusCode: res.StatusCode,
+		Latency:    latency,
+		Body:       r.redact(string(body)),
+	}, nil
+}
+
+func NewTestActionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "action",
+		Aliases: []string{"webhook"},
+		Args:    client.NoArgs,
+		Short:   "Send a test request to the web hook of an action",
+		Long: `Send a test request to the web hook of an action configured in the selected project and report the status code,
+latency, and response body.
+
+The Jsonnet body of the web hook is rendered locally using a sample identity or the identity in --identity-file. The
+request is sent with the configured method and authentication directly to the web hook. Nothing is changed in the
+project. Authentication secrets are redacted in all output.
+
+The action is selected using its index among the actions of the flow, in the order shown by ` + "`ory list actions`" + `.`,
+		Example: `$ ory test action --flow registration --hook-index 0 --identity-file sample.json
+
+METHOD	URL				STATUS CODE	LATENCY	RESPONSE BODY
+POST	https://hooks.example.com/x	200		142ms	{"ok":true}
+
+$ ory test action --flow login --hook-index 1 --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			flow := flagx.MustGetString(cmd, flowFlag)
+			if err := oneOf(flow, "flow", flows); err != nil {
+				return err
+			}
+
+			identity := sampleIdentity
+			if file := flagx.MustGetString(cmd, identityFileFlag); file != "" {
+				contents, err := client.ReadInputFile(cmd, file)
+				if err != nil {
+					return err
+				} else if !json.Valid(contents) {
+					return errors.Errorf("the identity in %s is not valid JSON", file)
+				}
+				identity = contents
+			}
+
+			id, err := h.ProjectID()
+			if err != nil {
+				return err
+			}
+
+			p, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			var flowActions []action
+			for _, a := range collectActions(p.Services.Identity.Config) {
+				if a.Flow == flow {
+					flowActions = append(flowActions, a)
+				}
+			}
+			idx := flagx.MustGetInt(cmd, hookIndexFlag)
+			if idx < 0 || idx >= len(flowActions) {
+				return errors.Errorf("the %s flow has %d actions, --%s must be between 0 and %d", flow, len(flowActions), hookIndexFlag, len(flowActions)-1)
+			}
+
+			r, err := renderWebHook(&flowActions[idx], identity)
+			if err != nil {
+				return err
+			}
+
+			result, err := r.send(cmd.Context(), &http.Client{Timeout: 30 * time.Second})
+			if err != nil {
+				return err
+			}
+
+			client.PrintRow(cmd, result)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flowFlag, "", "The self-service flow of the action (login, registration, settings, recovery, verification).")
+	cmd.Flags().Int(hookIndexFlag, 0, "The index of the action among the actions of the flow.")
+	cmd.Flags().String(identityFileFlag, "", "Path to a JSON file containing the identity to render the body with. Use - to read from stdin.")
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,68 @@
+package action
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWebHook(t *testing.T) {
+	t.Run("case=renders body", func(t *testing.T) {
+		body := base64.StdEncoding.EncodeToString([]byte(`function(ctx) { email: ctx.identity.traits.email, method: ctx.request_method }`))
+		r, err := renderWebHook(&action{Hook: hookWebHook, URL: "https://example.com", config: map[string]interface{}{"body": "base64://" + body}}, sampleIdentity)
+		require.NoError(t, err)
+		assert.Equal(t, "POST", r.Method)
+		assert.JSONEq(t, `{"email":"jane@example.com","method":"POST"}`, string(r.Body))
+	})
+
+	t.Run("case=remote body", func(t *testing.T) {
+		_, err := renderWebHook(&action{Hook: hookWebHook, config: map[string]interface{}{"body": "https://example.com/body.jsonnet"}}, sampleIdentity)
+		assert.ErrorContains(t, err, "can not be rendered locally")
+	})
+
+	t.Run("case=other hooks", func(t *testing.T) {
+		_, err := renderWebHook(&action{Hook: "session"}, sampleIdentity)
+		assert.ErrorContains(t, err, "only actions of type web_hook")
+	})
+
+	t.Run("case=authentication", func(t *testing.T) {
+		r, err := renderWebHook(&action{Hook: hookWebHook, Method: "PUT", config: map[string]interface{}{
+			"auth": map[string]interface{}{"type": "api_key", "config": map[string]interface{}{"name": "X-Token", "value": "secret", "in": "header"}},
+		}}, sampleIdentity)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		r, err = renderWebHook(&action{Hook: hookWebHook, config: map[string]interface{}{
+			"auth": map[string]interface{}{"type": "basic_auth", "config": map[string]interface{}{"user": "jane", "password": "secret"}},
+		}}, sampleIdentity)
+		require.NoError(t, err)
+		assert.Equal(t, "Basic amFuZTpzZWNyZXQ=", r.Header.Get("Authorization"))
+		assert.Equal(t, "Basic [REDACTED]", r.redact(r.Header.Get("Authorization")))
+	})
+}
+
+func TestSendWebHook(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(r.Method + " " + string(body) + " " + r.Header.Get("X-Token")))
+	}))
+	t.Cleanup(ts.Close)
+
+	r, err := renderWebHook(&action{Hook: hookWebHook, URL: ts.URL, Method: "PUT", config: map[string]interface{}{
+		"auth": map[string]interface{}{"type": "api_key", "config": map[string]interface{}{"name": "X-Token", "value": "secret", "in": "header"}},
+	}}, sampleIdentity)
+	require.NoError(t, err)
+
+	result, err := r.send(context.Background(), ts.Client())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, result.StatusCode)
+	assert.Equal(t, "PUT  [REDACTED]", result.Body)
+	assert.NotZero(t, result.Latency)
+}
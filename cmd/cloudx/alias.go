@@ -0,0 +1,8 @@
+package cloudx
+
+// verbAliases are common synonyms of the verbs, e.g. `ory ls projects` or `ory describe project`.
+var verbAliases = map[string][]string{
+	"list":   {"ls"},
+	"delete": {"rm"},
+	"get":    {"describe"},
+}
@@ -0,0 +1,119 @@
+package cloudx
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	dataFlag    = "data"
+	headerFlag  = "header"
+	consoleFlag = "console"
+)
+
+var httpMethod = regexp.MustCompile(`^[A-Z]+$`)
+
+func NewAPICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api METHOD PATH",
+		Args:  client.ExactArgs(2),
+		Short: "Send an authenticated request to the API of a project",
+		Long: `Send an authenticated request to the API of the project set by --project, or of the selected project, e.g. to
+use endpoints which the CLI has no commands for yet. Use --console to send the request to the Ory Console API
+instead. Requests are authenticated using your Ory Cloud session.
+
+The response body is printed to stdout and the status to stderr. The command fails if the status code is not 2xx.`,
+		Example: `$ ory api GET '/admin/identities?page_size=1'
+
+$ ory api POST /admin/identities --data @identity.json
+
+$ ory api PATCH /admin/relation-tuples --data - --header 'Content-Type: application/json' < patch.json
+
+$ ory api GET /projects --console`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method, target := strings.ToUpper(args[0]), args[1]
+			if !httpMethod.MatchString(method) {
+				return client.NewArgsError(cmd, "invalid HTTP method "+args[0])
+			} else if !strings.HasPrefix(target, "/") {
+				return client.NewArgsError(cmd, "the path must start with /, e.g. /admin/identities")
+			}
+
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			header := http.Header{}
+			values, _ := cmd.Flags().GetStringArray(headerFlag)
+			for _, v := range values {
+				parts := strings.SplitN(v, ":", 2)
+				if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+					return client.NewValidationError(errors.Errorf("headers must be in the format `Name: value` but got: %s", v), nil)
+				}
+				header.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+			}
+
+			var body io.Reader
+			if data, _ := cmd.Flags().GetString(dataFlag); data != "" {
+				if strings.HasPrefix(data, "@") || data == client.StdinFile {
+					f, err := client.OpenInputFile(cmd, data)
+					if err != nil {
+						return err
+					}
+					defer f.Close()
+					body = f
+				} else {
+					body = strings.NewReader(data)
+				}
+				if header.Get("Content-Type") == "" {
+					header.Set("Content-Type", "application/json")
+				}
+			}
+			if header.Get("Accept") == "" {
+				header.Set("Accept", "application/json")
+			}
+
+			var api *client.ProjectAPI
+			if console, _ := cmd.Flags().GetBool(consoleFlag); console {
+				api, err = h.NewConsoleAPI()
+			} else {
+				api, err = h.NewProjectAPI()
+			}
+			if err != nil {
+				return err
+			}
+
+			res, err := api.SendRaw(h.Ctx, method, target, header, body)
+			if err != nil {
+				return err
+			}
+			defer res.Body.Close()
+
+			h.Log.Infof("%s %s", res.Proto, res.Status)
+			if _, err := io.Copy(cmd.OutOrStdout(), res.Body); err != nil {
+				return errors.Wrap(err, "unable to read the response")
+			}
+			if res.StatusCode < 200 || res.StatusCode > 299 {
+				return client.FailSilently(cmd, client.ExitCode(&client.APIError{StatusCode: res.StatusCode}))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(dataFlag, "d", "", "The request body. Use @FILE to read it from a file, or - to read it from stdin.")
+	cmd.Flags().StringArrayP(headerFlag, "H", nil, "Add a header to the request, e.g. 'Content-Type: application/json'. Can be repeated.")
+	cmd.Flags().Bool(consoleFlag, false, "Send the request to the Ory Console API instead of the API of the project.")
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	return cmd
+}
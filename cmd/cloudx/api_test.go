@@ -0,0 +1,67 @@
+package cloudx_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+func TestAPI(t *testing.T) {
+	fake := newMockBackend()
+	fake.HandleFunc("/admin/identities", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, `{"method":%q,"host":%q,"query":%q,"authorization":%q,"content_type":%q,"x_test":%q,"body":%q}`,
+			r.Method, r.Host, r.URL.RawQuery, r.Header.Get("Authorization"), r.Header.Get("Content-Type"), r.Header.Get("X-Test"), body)
+	})
+	fake.HandleFunc("/admin/identities/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"error":{"code":404,"status":"Not Found","message":"Unable to locate the resource"}}`)
+	})
+	cloudxtest.Serve(t, fake)
+
+	t.Run("case=sends authenticated requests to the project", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "api", "get", "/admin/identities?page_size=1", "--project", mockedProjectID)
+		require.NoError(t, err, stderr)
+		assert.JSONEq(t, `{"method":"GET","host":"good-wright-t7kzy3vugf.projects.console.ory.test","query":"page_size=1","authorization":"Bearer mocked","content_type":"","x_test":"","body":""}`, stdout)
+		assert.Contains(t, stderr, "200 OK")
+	})
+
+	t.Run("case=sends the body and headers", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "identity.json")
+		require.NoError(t, os.WriteFile(file, []byte(`{"schema_id":"default"}`), 0600))
+
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "api", "POST", "/admin/identities", "--project", mockedProjectID, "--data", "@"+file, "-H", "X-Test: yes")
+		require.NoError(t, err, stderr)
+		assert.JSONEq(t, `{"method":"POST","host":"good-wright-t7kzy3vugf.projects.console.ory.test","query":"","authorization":"Bearer mocked","content_type":"application/json","x_test":"yes","body":"{\"schema_id\":\"default\"}"}`, stdout)
+	})
+
+	t.Run("case=--console", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "api", "GET", "/projects", "--console")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, `"slug":"good-wright-t7kzy3vugf"`)
+	})
+
+	t.Run("case=fails on error responses", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "api", "GET", "/admin/identities/missing", "--project", mockedProjectID)
+		assert.Equal(t, client.ExitNotFound, client.ExitCode(err))
+		assert.Contains(t, stdout, "Unable to locate the resource")
+		assert.Contains(t, stderr, "404 Not Found")
+	})
+
+	t.Run("case=validates the arguments", func(t *testing.T) {
+		_, _, err := newMockedCmd(t).Exec(nil, "api", "GET", "admin/identities", "--project", mockedProjectID)
+		assert.ErrorContains(t, err, "the path must start with /")
+
+		_, _, err = newMockedCmd(t).Exec(nil, "api", "GET", "/admin/identities", "--project", mockedProjectID, "-H", "X-Test")
+		assert.ErrorContains(t, err, "headers must be in the format")
+	})
+}
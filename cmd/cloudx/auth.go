@@ -10,24 +10,30 @@ import (
 func NewAuthCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "auth",
+		Args:  client.NoArgs,
 		Short: "Create an or sign into your Ory Cloud account",
+		Example: `$ ory auth
+
+$ ory auth --format json
+
+$ ory auth logout`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			h, err := client.NewCommandHelper(cmd)
 			if err != nil {
 				return err
 			}
-			ac, err := h.Authenticate()
+			ac, err := h.SignIn()
 			if err != nil {
 				return err
 			}
-			cmdx.PrintRow(cmd, ac)
+			client.PrintRow(cmd, ac)
 			return nil
 		},
 	}
 	client.RegisterConfigFlag(cmd.PersistentFlags())
 	client.RegisterYesFlag(cmd.PersistentFlags())
 	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
-	cmdx.RegisterFormatFlags(cmd.Flags())
-	cmd.AddCommand(NewLogoutCmd())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	client.AddGroupedCommands(cmd, client.GroupAuth, NewLogoutCmd(), NewAuthEnvCmd())
 	return cmd
 }
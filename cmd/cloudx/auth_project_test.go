@@ -0,0 +1,111 @@
+package cloudx_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+// TestAuthSelectsProject checks that signing in with `ory auth` selects the project which commands use by default.
+func TestAuthSelectsProject(t *testing.T) {
+	const email, password = "dev@ory.sh", "s3cr3t-password"
+	serve := func(t *testing.T, projects ...string) []string {
+		fake := cloudxtest.NewBackend()
+		fake.AddAccount(email, password)
+		ids := make([]string, len(projects))
+		for k, name := range projects {
+			ids[k] = fake.AddProject(cloudxtest.Project{Name: name}).ID
+		}
+		cloudxtest.Serve(t, fake)
+		return ids
+	}
+	signIn := func(t *testing.T, selection string) (string, uuid.UUID) {
+		config := testhelpers.NewConfigDir(t)
+		var r bytes.Buffer
+		_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
+		_, _ = r.WriteString(email + "\n") // Email:
+		_, _ = r.WriteString("\n")         // Email: dev@ory.sh — correct? [Y/n]:
+		_, _ = r.WriteString(selection)
+		_, stderr, err := testhelpers.ConfigPasswordAwareCmd(config, password).Exec(&r, "auth")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "You are now signed in as: "+email)
+		return stderr, testhelpers.ReadConfig(t, config).SelectedProject
+	}
+
+	t.Run("case=no project", func(t *testing.T) {
+		serve(t)
+		stderr, selected := signIn(t, "")
+		assert.Equal(t, uuid.Nil, selected)
+		assert.Contains(t, stderr, "create one using `ory create project`")
+	})
+
+	t.Run("case=one project", func(t *testing.T) {
+		ids := serve(t, "Only Project")
+		stderr, selected := signIn(t, "")
+		assert.Equal(t, ids[0], selected.String())
+		assert.Contains(t, stderr, `Selected your project "Only Project"`)
+		assert.NotContains(t, stderr, "Select the project")
+	})
+
+	t.Run("case=malformed project ID", func(t *testing.T) {
+		fake := cloudxtest.NewBackend()
+		fake.AddAccount(email, password)
+		fake.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"id": "not-a-uuid", "name": "Broken Project", "slug": "broken", "state": "running", "created_at": "2022-06-01T12:00:00Z", "updated_at": "2022-06-01T12:00:00Z"}]`))
+		})
+		cloudxtest.Serve(t, fake)
+
+		var r bytes.Buffer
+		_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
+		_, _ = r.WriteString(email + "\n") // Email:
+		_, _ = r.WriteString("\n")         // Email: dev@ory.sh — correct? [Y/n]:
+		config := testhelpers.NewConfigDir(t)
+		_, stderr, err := testhelpers.ConfigPasswordAwareCmd(config, password).Exec(&r, "auth")
+		require.NoError(t, err, "the user is signed in even though no project was selected")
+		assert.Contains(t, stderr, `the API returned the project ID "not-a-uuid", which is not a valid UUID`)
+		assert.Contains(t, stderr, "select one using `ory use project`")
+		assert.Equal(t, uuid.Nil, testhelpers.ReadConfig(t, config).SelectedProject)
+	})
+
+	t.Run("case=listing the projects fails", func(t *testing.T) {
+		fake := cloudxtest.NewBackend()
+		fake.AddAccount(email, password)
+		fake.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		cloudxtest.Serve(t, fake)
+
+		stderr, selected := signIn(t, "")
+		assert.Equal(t, uuid.Nil, selected)
+		assert.Contains(t, stderr, "Unable to list your projects, select one using `ory use project`")
+	})
+
+	t.Run("case=several projects", func(t *testing.T) {
+		ids := serve(t, "First Project", "Second Project")
+		stderr, selected := signIn(t, "2\n") // Select the project which commands use by default: 2
+		assert.Contains(t, stderr, "Select the project which commands use by default:")
+		assert.Equal(t, ids[1], selected.String())
+	})
+	t.Run("case=commands which sign in do not select a project", func(t *testing.T) {
+		serve(t, "First Project", "Second Project")
+		config := testhelpers.NewConfigDir(t)
+		var r bytes.Buffer
+		_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
+		_, _ = r.WriteString(email + "\n") // Email:
+		_, _ = r.WriteString("\n")         // Email: dev@ory.sh — correct? [Y/n]:
+		stdout, stderr, err := testhelpers.ConfigPasswordAwareCmd(config, password).Exec(&r, "list", "projects", "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, "Second Project")
+		assert.Contains(t, stderr, "You are now signed in as: "+email)
+		assert.NotContains(t, stderr, "Select the project")
+		assert.Equal(t, uuid.Nil, testhelpers.ReadConfig(t, config).SelectedProject)
+	})
+}
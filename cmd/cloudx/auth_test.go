@@ -37,6 +37,7 @@ func TestAuthenticator(t *testing.T) {
 
 		_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
 		_, _ = r.WriteString(email + "\n") // Email: FakeEmail()
+		_, _ = r.WriteString("\n")         // Email: FakeEmail() — correct? [Y/n]:
 
 		return cmd.Exec(&r, "auth")
 	}
@@ -53,6 +54,7 @@ func TestAuthenticator(t *testing.T) {
 		_, _ = r.WriteString("n\n")        // Please inform me about platform and security updates? [y/n]: n
 		_, _ = r.WriteString("n\n")        // I accept the Terms of Service [y/n]: n
 		_, _ = r.WriteString("y\n")        // I accept the Terms of Service [y/n]: y
+		_, _ = r.WriteString("\n")         // Email: FakeEmail(), Name: FakeName() — correct? [Y/n]:
 
 		stdout, stderr, err := cmd.Exec(&r, "auth")
 		require.NoError(t, err)
@@ -149,6 +151,7 @@ func TestAuthenticator(t *testing.T) {
 
 				_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
 				_, _ = r.WriteString(email + "\n") // Email: FakeEmail()
+				_, _ = r.WriteString("\n")         // Email: FakeEmail() — correct? [Y/n]:
 
 				stdout, stderr, err := cmd.Exec(&r, "auth")
 				require.Error(t, err, stdout)
@@ -167,6 +170,7 @@ func TestAuthenticator(t *testing.T) {
 				require.NoError(t, err)
 				_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
 				_, _ = r.WriteString(email + "\n") // Email: FakeEmail()
+				_, _ = r.WriteString("\n")         // Email: FakeEmail() — correct? [Y/n]:
 				_, _ = r.WriteString(code + "\n")  // TOTP code
 
 				stdout, stderr, err := cmd.Exec(&r, "auth")
@@ -189,6 +193,7 @@ func TestAuthenticator(t *testing.T) {
 		_, _ = r.WriteString(testhelpers.FakeName() + "\n") // Name: FakeName()
 		_, _ = r.WriteString("n\n")                         // Please inform me about platform and security updates? [y/n]: n
 		_, _ = r.WriteString("y\n")                         // I accept the Terms of Service [y/n]: y
+		_, _ = r.WriteString("\n")                          // Email: not-an-email, Name: FakeName() — correct? [Y/n]:
 
 		// Redo the flow
 		email := testhelpers.FakeEmail()
@@ -197,6 +202,7 @@ func TestAuthenticator(t *testing.T) {
 		_, _ = r.WriteString(name + "\n")  // Name: FakeName()
 		_, _ = r.WriteString("y\n")        // Please inform me about platform and security updates? [y/n]: n
 		_, _ = r.WriteString("y\n")        // I accept the Terms of Service [y/n]: y
+		_, _ = r.WriteString("\n")         // Email: FakeEmail(), Name: FakeName() — correct? [Y/n]:
 
 		stdout, stderr, err := cmd.Exec(&r, "auth", "--"+client.ConfigFlag, configDir)
 		require.NoError(t, err)
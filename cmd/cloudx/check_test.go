@@ -0,0 +1,169 @@
+package cloudx_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+func TestIsAllowedExitCode(t *testing.T) {
+	fake := newMockBackend()
+	fake.HandleFunc("/relation-tuples/check", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("subject_id") {
+		case "user:alice":
+			_, _ = fmt.Fprint(w, `{"allowed":true}`)
+		case "user:bob":
+			_, _ = fmt.Fprint(w, `{"allowed":false}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"error":{"code":400,"message":"unknown namespace"}}`)
+		}
+	})
+	cloudxtest.Serve(t, fake)
+	cmd := newMockedCmd(t)
+
+	for _, tc := range []struct {
+		subject string
+		stdout  string
+		code    int
+	}{
+		{subject: "user:alice", stdout: "Allowed\n", code: client.ExitSuccess},
+		{subject: "user:bob", stdout: "Denied\n", code: client.ExitFailure},
+		{subject: "user:mallory", code: client.ExitValidation},
+	} {
+		t.Run("subject="+tc.subject, func(t *testing.T) {
+			stdout, stderr, err := cmd.Exec(nil, "is", "allowed", tc.subject, "viewer", "documents", "doc-1", "--project", mockedProjectID)
+			assert.Equal(t, tc.code, client.ExitCode(err), stderr)
+			assert.Equal(t, tc.stdout, stdout)
+			if tc.code == client.ExitValidation {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unknown namespace", "API errors are not reported as denied")
+			}
+		})
+	}
+}
+
+func TestIsAllowed(t *testing.T) {
+	var mu sync.Mutex
+	var queries []url.Values
+	checked := make(chan struct{}, 100)
+	fake := newMockBackend()
+	fake.HandleFunc("/relation-tuples/check", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+		defer func() { checked <- struct{}{} }()
+
+		switch r.URL.Query().Get("subject_id") {
+		case "user:alice":
+			_, _ = fmt.Fprint(w, `{"allowed":true,"debug":{"depth":2}}`)
+		case "user:bob":
+			// Older versions of the check API deny with 403 Forbidden.
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"allowed":false}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `{"error":{"code":403,"message":"the API key is not allowed to check permissions"}}`)
+		}
+	})
+	cloudxtest.Serve(t, fake)
+	cmd := newMockedCmd(t)
+	isAllowed := func(subject string) []string {
+		return []string{"is", "allowed", subject, "viewer", "documents", "doc-1", "--project", mockedProjectID}
+	}
+	lastQuery := func() url.Values {
+		mu.Lock()
+		defer mu.Unlock()
+		return queries[len(queries)-1]
+	}
+
+	t.Run("case=prints the response as is", func(t *testing.T) {
+		stdout, stderr, err := cmd.Exec(nil, append(isAllowed("user:alice"), "--format", "json")...)
+		require.NoError(t, err, stderr)
+		assert.Equal(t, `{"allowed":true,"debug":{"depth":2}}`+"\n", stdout)
+	})
+
+	t.Run("case=denied by an older version of the API", func(t *testing.T) {
+		stdout, _, err := cmd.Exec(nil, isAllowed("user:bob")...)
+		assert.Equal(t, client.ExitFailure, client.ExitCode(err))
+		assert.Equal(t, "Denied\n", stdout)
+
+		stdout, _, err = cmd.Exec(nil, append(isAllowed("user:bob"), "--format", "json")...)
+		assert.Equal(t, client.ExitFailure, client.ExitCode(err))
+		assert.Equal(t, `{"allowed":false}`+"\n", stdout)
+	})
+
+	t.Run("case=other forbidden responses are errors", func(t *testing.T) {
+		stdout, _, err := cmd.Exec(nil, isAllowed("user:mallory")...)
+		require.Error(t, err)
+		assert.Equal(t, client.ExitPermissionDenied, client.ExitCode(err))
+		assert.Contains(t, err.Error(), "the API key is not allowed to check permissions")
+		assert.Empty(t, stdout)
+	})
+
+	t.Run("case=max depth", func(t *testing.T) {
+		_, stderr, err := cmd.Exec(nil, isAllowed("user:alice")...)
+		require.NoError(t, err, stderr)
+		assert.NotContains(t, lastQuery(), "max-depth", "the server's default applies")
+
+		_, stderr, err = cmd.Exec(nil, append(isAllowed("user:alice"), "--max-depth", "3")...)
+		require.NoError(t, err, stderr)
+		assert.Equal(t, "3", lastQuery().Get("max-depth"))
+	})
+
+	t.Run("case=watch until canceled", func(t *testing.T) {
+		for len(checked) > 0 {
+			<-checked
+		}
+		watch := *cmd
+		var cancel context.CancelFunc
+		watch.Ctx, cancel = context.WithCancel(cmd.Ctx)
+		defer cancel()
+
+		var stdout, stderr bytes.Buffer
+		done := make(chan error)
+		go func() {
+			done <- watch.ExecBackground(nil, &stdout, &stderr, append(isAllowed("user:alice"), "--watch", "--interval", "10ms", "--format", "json")...).Wait()
+		}()
+
+		for i := 0; i < 3; i++ {
+			select {
+			case <-checked:
+			case <-time.After(10 * time.Second):
+				t.Fatal("the permission was not checked repeatedly")
+			}
+		}
+		cancel()
+		select {
+		case err := <-done:
+			require.NoError(t, err, stderr.String())
+		case <-time.After(10 * time.Second):
+			t.Fatal("watching did not stop after the context was canceled")
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		require.GreaterOrEqual(t, len(lines), 2, stdout.String())
+		for _, line := range lines {
+			var check struct {
+				CheckedAt time.Time       `json:"checked_at"`
+				Response  json.RawMessage `json:"response"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(line), &check), line)
+			assert.False(t, check.CheckedAt.IsZero(), line)
+			assert.JSONEq(t, `{"allowed":true,"debug":{"depth":2}}`, string(check.Response))
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// FormatGitHub prints the default human readable output, but reports errors as GitHub Actions workflow commands, see
+// GitHubAnnotations.
+const FormatGitHub = "github"
+
+// githubActionsEnv is set to "true" by GitHub Actions in every step.
+const githubActionsEnv = "GITHUB_ACTIONS"
+
+// The levels of an Annotation.
+const (
+	AnnotationError   = "error"
+	AnnotationWarning = "warning"
+	AnnotationNotice  = "notice"
+)
+
+// Annotation is a GitHub Actions workflow command which shows a message in the summary of the run and, if File is set,
+// next to the line of the file in pull requests. Lines and columns start at 1, zero values are omitted.
+type Annotation struct {
+	Level     string `json:"level"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	Column    int    `json:"column,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	EndColumn int    `json:"end_column,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Message   string `json:"message"`
+}
+
+// String returns the workflow command, e.g. "::error file=namespaces.ts,line=12,col=5::expected \"=>\"".
+func (a Annotation) String() string {
+	var props []string
+	add := func(name, value string) {
+		if value != "" {
+			props = append(props, name+"="+escapeAnnotationProperty(value))
+		}
+	}
+	addInt := func(name string, value int) {
+		if value > 0 {
+			add(name, strconv.Itoa(value))
+		}
+	}
+	add("file", a.File)
+	addInt("line", a.Line)
+	addInt("col", a.Column)
+	addInt("endLine", a.EndLine)
+	addInt("endColumn", a.EndColumn)
+	add("title", a.Title)
+
+	level := a.Level
+	if level == "" {
+		level = AnnotationError
+	}
+	if len(props) == 0 {
+		return "::" + level + "::" + escapeAnnotationData(a.Message)
+	}
+	return "::" + level + " " + strings.Join(props, ",") + "::" + escapeAnnotationData(a.Message)
+}
+
+// escapeAnnotationData escapes the message of a workflow command like the @actions/core toolkit does.
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeAnnotationProperty escapes a property of a workflow command, which additionally must not contain the
+// separators of properties.
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// Annotator is implemented by errors, and by the details of ValidationErrors, which refer to locations in input files,
+// e.g. syntax errors.
+type Annotator interface {
+	Annotations() []Annotation
+}
+
+// GitHubAnnotations returns true if errors are reported as workflow commands, which is the case with --format github,
+// or when running in GitHub Actions and the output format is not machine readable. Machine readable formats keep their
+// output, including the error envelopes on stderr, so that it can still be parsed.
+func GitHubAnnotations(cmd *cobra.Command) bool {
+	switch format := OutputFormat(cmd); {
+	case format == FormatGitHub:
+		return true
+	case IsMachineReadableFormat(cmd), format == FormatNone, format == FormatTemplate:
+		return false
+	}
+	return os.Getenv(githubActionsEnv) == "true"
+}
+
+// PrintAnnotations prints the annotations to stderr. GitHub Actions reads workflow commands from both stdout and
+// stderr, and stdout is left to the output of the command.
+func PrintAnnotations(cmd *cobra.Command, annotations []Annotation) {
+	for _, a := range annotations {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), a.String())
+	}
+}
+
+// ErrorAnnotations maps the error of a failed command onto annotations. Errors implementing Annotator, or
+// ValidationErrors with details implementing it, are reported at their locations. All other errors are reported as a
+// single annotation titled with the command and the error code.
+func ErrorAnnotations(cmd *cobra.Command, err error) []Annotation {
+	var annotator Annotator
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &annotator):
+		if as := annotator.Annotations(); len(as) > 0 {
+			return as
+		}
+	case errors.As(err, &validationErr):
+		if a, ok := validationErr.Details.(Annotator); ok && len(a.Annotations()) > 0 {
+			return a.Annotations()
+		}
+	}
+
+	title := cmd.CommandPath() + " failed"
+	if code := NewErrorEnvelope(err).Error.Code; code != ErrorCodeUnknown {
+		title += " (" + code + ")"
+	}
+	return []Annotation{{Level: AnnotationError, Title: title, Message: err.Error()}}
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// DiffAnnotations annotates every hunk of a unified diff at the lines it changes in file. The message of each
+// annotation is the hunk itself.
+func DiffAnnotations(diff, file, level, title string) []Annotation {
+	var annotations []Annotation
+	var hunk []string
+	var current *Annotation
+	flush := func() {
+		if current != nil {
+			current.Message = strings.Join(hunk, "\n")
+			annotations = append(annotations, *current)
+		}
+		current, hunk = nil, nil
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		if m := hunkHeader.FindStringSubmatch(line); m != nil {
+			flush()
+			start, _ := strconv.Atoi(m[1])
+			length := 1
+			if m[2] != "" {
+				length, _ = strconv.Atoi(m[2])
+			}
+			// Hunks which only remove lines have no lines in the new file, they are shown at the line before.
+			end := start + length - 1
+			if start == 0 {
+				start = 1
+			}
+			if end < start {
+				end = start
+			}
+			current = &Annotation{Level: level, File: file, Line: start, EndLine: end, Title: title}
+		}
+		if current != nil {
+			hunk = append(hunk, line)
+		}
+	}
+	flush()
+	return annotations
+}
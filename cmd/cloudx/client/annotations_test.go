@@ -0,0 +1,139 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+)
+
+type annotatedDetails []Annotation
+
+func (d annotatedDetails) Annotations() []Annotation {
+	return d
+}
+
+func TestAnnotationString(t *testing.T) {
+	for _, tc := range []struct {
+		a        Annotation
+		expected string
+	}{
+		{a: Annotation{Message: "failed"}, expected: "::error::failed"},
+		{
+			a:        Annotation{Level: AnnotationWarning, File: "namespaces.ts", Line: 12, Column: 5, EndColumn: 6, Message: `expected "=>"`},
+			expected: `::warning file=namespaces.ts,line=12,col=5,endColumn=6::expected "=>"`,
+		},
+		{
+			a:        Annotation{Title: "ory update opl: failed, 100%", Message: "line 1\nline 2: 50%\r"},
+			expected: "::error title=ory update opl%3A failed%2C 100%25::line 1%0Aline 2: 50%25%0D",
+		},
+	} {
+		assert.Equal(t, tc.expected, tc.a.String())
+	}
+}
+
+func TestErrorAnnotations(t *testing.T) {
+	cmd := &cobra.Command{Use: "validate"}
+	located := annotatedDetails{{File: "a.ts", Line: 1, Message: "first"}, {File: "a.ts", Line: 3, Message: "second"}}
+
+	t.Run("case=validation error with located details", func(t *testing.T) {
+		assert.Equal(t, []Annotation(located), ErrorAnnotations(cmd, NewValidationError(errors.New("invalid"), located)))
+	})
+
+	t.Run("case=validation error without located details", func(t *testing.T) {
+		assert.Equal(t, []Annotation{{Level: AnnotationError, Title: "validate failed (validation_failed)", Message: "invalid"}},
+			ErrorAnnotations(cmd, NewValidationError(errors.New("invalid"), map[string]string{"field": "name"})))
+	})
+
+	t.Run("case=generic error", func(t *testing.T) {
+		assert.Equal(t, []Annotation{{Level: AnnotationError, Title: "validate failed", Message: "boom: line 1\nline 2"}},
+			ErrorAnnotations(cmd, errors.Wrap(errors.New("line 1\nline 2"), "boom")))
+	})
+
+	t.Run("case=known error code", func(t *testing.T) {
+		assert.Equal(t, "validate failed (aborted)", ErrorAnnotations(cmd, errors.WithStack(ErrAborted))[0].Title)
+	})
+}
+
+func TestDiffAnnotations(t *testing.T) {
+	diff := `--- deployed
++++ namespaces.ts
+@@ -1,3 +1,4 @@
+ class User implements Namespace {}
++class Group implements Namespace {}
+ class File implements Namespace {}
+ class Folder implements Namespace {}
+@@ -10 +11,0 @@
+-class Obsolete implements Namespace {}
+`
+	annotations := DiffAnnotations(diff, "namespaces.ts", AnnotationNotice, "Changes")
+	require.Len(t, annotations, 2)
+	assert.Equal(t, Annotation{
+		Level: AnnotationNotice, File: "namespaces.ts", Line: 1, EndLine: 4, Title: "Changes",
+		Message: "@@ -1,3 +1,4 @@\n class User implements Namespace {}\n+class Group implements Namespace {}\n class File implements Namespace {}\n class Folder implements Namespace {}",
+	}, annotations[0])
+	assert.Equal(t, Annotation{
+		Level: AnnotationNotice, File: "namespaces.ts", Line: 11, EndLine: 11, Title: "Changes",
+		Message: "@@ -10 +11,0 @@\n-class Obsolete implements Namespace {}",
+	}, annotations[1])
+
+	assert.Empty(t, DiffAnnotations("", "namespaces.ts", AnnotationNotice, "Changes"))
+}
+
+func TestGitHubAnnotations(t *testing.T) {
+	exec := func(env string, args ...string) (stdout, stderr string, err error) {
+		t.Setenv(githubActionsEnv, env)
+		cmd := &cobra.Command{
+			Use: "fail",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				PrintJSONAble(cmd, map[string]int{"a": 1})
+				return NewValidationError(errors.New("invalid"), annotatedDetails{{File: "a.ts", Line: 2, Message: "unexpected token"}})
+			},
+		}
+		RegisterFormatFlag(cmd.Flags())
+		EnableStructuredErrors(cmd)
+
+		var out, errOut bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		cmd.SetArgs(args)
+		err = cmd.Execute()
+		return out.String(), errOut.String(), err
+	}
+
+	t.Run("case=format github", func(t *testing.T) {
+		stdout, stderr, err := exec("", "--format", "github")
+		require.ErrorIs(t, err, cmdx.ErrNoPrintButFail)
+		assert.Equal(t, ExitValidation, ExitCode(err))
+		assert.Equal(t, "::error file=a.ts,line=2::unexpected token\n", stderr)
+		assert.Equal(t, "{\n  \"a\": 1\n}\n", stdout, "the output is human readable")
+	})
+
+	t.Run("case=detected in GitHub Actions", func(t *testing.T) {
+		_, stderr, err := exec("true")
+		require.ErrorIs(t, err, cmdx.ErrNoPrintButFail)
+		assert.Equal(t, ExitValidation, ExitCode(err))
+		assert.Equal(t, "::error file=a.ts,line=2::unexpected token\n", stderr)
+	})
+
+	t.Run("case=machine readable output is not changed in GitHub Actions", func(t *testing.T) {
+		stdout, stderr, err := exec("true", "--format", "json")
+		require.Error(t, err)
+		assert.Equal(t, ExitValidation, ExitCode(err))
+		assert.JSONEq(t, `{"a":1}`, stdout)
+		assert.JSONEq(t, `{"error":{"code":"validation_failed","message":"invalid","details":[{"level":"","file":"a.ts","line":2,"message":"unexpected token"}]}}`, stderr)
+	})
+
+	t.Run("case=not in GitHub Actions", func(t *testing.T) {
+		_, stderr, err := exec("")
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, cmdx.ErrNoPrintButFail))
+		assert.Equal(t, ExitValidation, ExitCode(err))
+		assert.NotContains(t, stderr, "::error")
+	})
+}
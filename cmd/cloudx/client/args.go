@@ -0,0 +1,186 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// The validators of positional arguments. Unlike the validators of cobra, their errors are validation errors which
+// name the expected form of the arguments and include an example invocation of the command.
+
+// ArgValidator checks the form of a single argument and describes the expected form if the argument is invalid.
+type ArgValidator func(arg string) error
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ProjectIDOrSlug accepts project IDs and slugs, e.g. ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 or good-wright-t7kzy3vugf.
+func ProjectIDOrSlug(arg string) error {
+	if _, err := uuid.FromString(arg); err == nil || slugPattern.MatchString(arg) {
+		return nil
+	}
+	return errors.Errorf("expected a project ID (UUID) or slug, e.g. ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 or good-wright-t7kzy3vugf%s", looksLikePath(arg))
+}
+
+// UUID accepts UUIDs. The name describes the ID, e.g. "an identity ID".
+func UUID(name string) ArgValidator {
+	return func(arg string) error {
+		if _, err := uuid.FromString(arg); err == nil {
+			return nil
+		}
+		return errors.Errorf("expected %s (UUID), e.g. 9f425a8d-7efc-4768-8f23-7647a74fdf13%s", name, looksLikePath(arg))
+	}
+}
+
+// OneOf accepts the given values.
+func OneOf(values ...string) ArgValidator {
+	return func(arg string) error {
+		for _, v := range values {
+			if arg == v {
+				return nil
+			}
+		}
+		return errors.Errorf("expected one of %s", strings.Join(values, ", "))
+	}
+}
+
+// looksLikePath explains that the argument is a file path, which is a common mistake.
+func looksLikePath(arg string) string {
+	if strings.ContainsAny(arg, `/\`) || strings.HasSuffix(arg, ".json") || strings.HasSuffix(arg, ".yaml") || strings.HasSuffix(arg, ".yml") {
+		return ", not a file path"
+	}
+	return ""
+}
+
+// NoArgs fails if any arguments are given.
+func NoArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return NewArgsError(cmd, fmt.Sprintf("%q does not accept arguments, but got %q", cmd.CommandPath(), args))
+	}
+	return nil
+}
+
+// ExactArgs fails unless exactly n arguments are given which pass the validators.
+func ExactArgs(n int, validators ...ArgValidator) cobra.PositionalArgs {
+	return RangeArgs(n, n, validators...)
+}
+
+// MinimumNArgs fails unless at least n arguments are given which pass the validators.
+func MinimumNArgs(n int, validators ...ArgValidator) cobra.PositionalArgs {
+	return RangeArgs(n, -1, validators...)
+}
+
+// MaximumNArgs fails if more than n arguments are given or any of them fails the validators.
+func MaximumNArgs(n int, validators ...ArgValidator) cobra.PositionalArgs {
+	return RangeArgs(0, n, validators...)
+}
+
+// RangeArgs fails unless between min and max arguments are given which pass the validators. A negative max allows any
+// number of arguments.
+func RangeArgs(min, max int, validators ...ArgValidator) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < min || (max >= 0 && len(args) > max) {
+			return NewArgsError(cmd, fmt.Sprintf("%q %s, but got %d", cmd.CommandPath(), expectedArgs(min, max), len(args)))
+		}
+		for _, arg := range args {
+			for _, validate := range validators {
+				if err := validate(arg); err != nil {
+					return NewArgsError(cmd, fmt.Sprintf("invalid argument %q: %s", arg, err))
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func expectedArgs(min, max int) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+
+	switch {
+	case min == max:
+		return "requires exactly " + plural(min)
+	case max < 0:
+		return "requires at least " + plural(min)
+	case min == 0:
+		return "accepts at most " + plural(max)
+	}
+	return fmt.Sprintf("accepts between %d and %s", min, plural(max))
+}
+
+// NewArgsError returns a validation error for invalid arguments, which includes an example invocation of the command.
+func NewArgsError(cmd *cobra.Command, msg string) error {
+	return NewValidationError(errors.Errorf("%s\n\nExample:\n  %s", msg, ExampleInvocation(cmd)), nil)
+}
+
+// ExampleInvocation returns the first example of the command or, if it has none, its command path. Examples are
+// either prefixed with "$ " or, if none is, the first line of the examples is used. Line continuations are joined.
+func ExampleInvocation(cmd *cobra.Command) string {
+	lines := strings.Split(strings.TrimSpace(cmd.Example), "\n")
+	start := -1
+	for k, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "$ ") {
+			start = k
+			break
+		}
+	}
+	if start < 0 && lines[0] != "" {
+		start = 0
+	}
+	if start < 0 {
+		return cmd.CommandPath()
+	}
+
+	var parts []string
+	for _, line := range lines[start:] {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "$ ")
+		parts = append(parts, strings.TrimSpace(strings.TrimSuffix(line, "\\")))
+		if !strings.HasSuffix(line, "\\") {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// suggestionDistance is the maximum Levenshtein distance of suggested sub commands.
+const suggestionDistance = 2
+
+// EnableSuggestions makes commands, which only group sub commands, fail with suggestions if the sub command is
+// unknown, e.g. `ory get identiy`. Cobra only does this for the root command and shows the help otherwise.
+func EnableSuggestions(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableSuggestions(c)
+	}
+
+	cmd.SuggestionsMinimumDistance = suggestionDistance
+	if !cmd.HasParent() || !cmd.HasSubCommands() || cmd.Runnable() {
+		return
+	}
+
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return nil
+		}
+		return NewValidationError(errors.New(unknownCommandMessage(cmd, args[0])), nil)
+	}
+	cmd.RunE = func(cmd *cobra.Command, _ []string) error {
+		return cmd.Help()
+	}
+}
+
+// unknownCommandMessage mimics the error cobra returns for unknown sub commands of the root command.
+func unknownCommandMessage(cmd *cobra.Command, name string) string {
+	msg := fmt.Sprintf("unknown command %q for %q", name, cmd.CommandPath())
+	if suggestions := cmd.SuggestionsFor(name); len(suggestions) > 0 {
+		msg += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t") + "\n"
+	}
+	return msg
+}
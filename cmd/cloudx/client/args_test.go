@@ -0,0 +1,124 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs(t *testing.T) {
+	run := func(t *testing.T, validate cobra.PositionalArgs, args ...string) error {
+		cmd := &cobra.Command{
+			Use:     "project <id>",
+			Args:    validate,
+			Example: "$ ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89\n\nID\tecaaa3cb-0730-4ee8-a6df-9553cdfeef89",
+			RunE:    func(*cobra.Command, []string) error { return nil },
+		}
+		cmd.SetArgs(args)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		return cmd.Execute()
+	}
+
+	for _, tc := range []struct {
+		name     string
+		validate cobra.PositionalArgs
+		args     []string
+		expected string
+	}{
+		{name: "no args", validate: NoArgs, args: []string{"foo"}, expected: `"project" does not accept arguments, but got ["foo"]`},
+		{name: "too few", validate: ExactArgs(1), expected: `"project" requires exactly 1 argument, but got 0`},
+		{name: "too many", validate: MaximumNArgs(1), args: []string{"a", "b"}, expected: `"project" accepts at most 1 argument, but got 2`},
+		{name: "range", validate: RangeArgs(1, 2), expected: `"project" accepts between 1 and 2 arguments, but got 0`},
+		{name: "minimum", validate: MinimumNArgs(2), args: []string{"a"}, expected: `"project" requires at least 2 arguments, but got 1`},
+		{name: "file path instead of project", validate: ExactArgs(1, ProjectIDOrSlug), args: []string{"./project.json"}, expected: `invalid argument "./project.json": expected a project ID (UUID) or slug, e.g. ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 or good-wright-t7kzy3vugf, not a file path`},
+		{name: "invalid UUID", validate: MinimumNArgs(1, UUID("an identity ID")), args: []string{"9f425a8d-7efc-4768-8f23-7647a74fdf13", "jane@example.com"}, expected: `invalid argument "jane@example.com": expected an identity ID (UUID), e.g. 9f425a8d-7efc-4768-8f23-7647a74fdf13`},
+		{name: "one of", validate: ExactArgs(1, OneOf("login", "registration")), args: []string{"signin"}, expected: `invalid argument "signin": expected one of login, registration`},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			err := run(t, tc.validate, tc.args...)
+			require.Error(t, err)
+			assert.Equal(t, tc.expected+"\n\nExample:\n  ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89", err.Error())
+			assert.Equal(t, ExitValidation, ExitCode(err))
+		})
+	}
+
+	for _, tc := range []struct {
+		validate cobra.PositionalArgs
+		args     []string
+	}{
+		{validate: NoArgs},
+		{validate: ExactArgs(1, ProjectIDOrSlug), args: []string{"ecaaa3cb-0730-4ee8-a6df-9553cdfeef89"}},
+		{validate: ExactArgs(1, ProjectIDOrSlug), args: []string{"good-wright-t7kzy3vugf"}},
+		{validate: MaximumNArgs(1, ProjectIDOrSlug)},
+		{validate: MinimumNArgs(1, UUID("an identity ID")), args: []string{"9f425a8d-7efc-4768-8f23-7647a74fdf13"}},
+	} {
+		assert.NoError(t, run(t, tc.validate, tc.args...), "%v", tc.args)
+	}
+}
+
+func TestExampleInvocation(t *testing.T) {
+	for _, tc := range []struct {
+		example, expected string
+	}{
+		{example: "", expected: "ory patch"},
+		{example: "ory tunnel http://localhost:3000 --dev\nory tunnel https://app.example.com", expected: "ory tunnel http://localhost:3000 --dev"},
+		{example: "$ ory patch identity-config ecaaa3cb \\\n\t--add '/a=1' \\\n\t--format json\n\n{}", expected: "ory patch identity-config ecaaa3cb --add '/a=1' --format json"},
+	} {
+		parent := &cobra.Command{Use: "ory"}
+		cmd := &cobra.Command{Use: "patch", Example: tc.example}
+		parent.AddCommand(cmd)
+		assert.Equal(t, tc.expected, ExampleInvocation(cmd))
+	}
+}
+
+func TestEnableSuggestions(t *testing.T) {
+	newRoot := func() *cobra.Command {
+		noop := func(*cobra.Command, []string) error { return nil }
+		root := &cobra.Command{Use: "ory"}
+		get := &cobra.Command{Use: "get", Aliases: []string{"describe"}}
+		get.AddCommand(
+			&cobra.Command{Use: "identity", RunE: noop},
+			&cobra.Command{Use: "project", RunE: noop},
+			&cobra.Command{Use: "courier-message", RunE: noop},
+		)
+		root.AddCommand(get, &cobra.Command{Use: "list", Aliases: []string{"ls"}, RunE: noop})
+		EnableSuggestions(root)
+		return root
+	}
+	run := func(args ...string) (string, error) {
+		root := newRoot()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(new(bytes.Buffer))
+		root.SetArgs(args)
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"get", "identiy"}, expected: "unknown command \"identiy\" for \"ory get\"\n\nDid you mean this?\n\tidentity\n"},
+		{args: []string{"get", "porject"}, expected: "unknown command \"porject\" for \"ory get\"\n\nDid you mean this?\n\tproject\n"},
+		{args: []string{"describe", "courier"}, expected: "unknown command \"courier\" for \"ory get\"\n\nDid you mean this?\n\tcourier-message\n"},
+		{args: []string{"get", "sessions"}, expected: "unknown command \"sessions\" for \"ory get\""},
+		{args: []string{"lsit"}, expected: "unknown command \"lsit\" for \"ory\"\n\nDid you mean this?\n\tlist\n"},
+	} {
+		t.Run("args="+tc.args[len(tc.args)-1], func(t *testing.T) {
+			_, err := run(tc.args...)
+			require.Error(t, err)
+			assert.Equal(t, tc.expected, err.Error())
+		})
+	}
+
+	t.Run("case=shows the help without a sub command", func(t *testing.T) {
+		out, err := run("get")
+		require.NoError(t, err)
+		assert.Contains(t, out, "Available Commands:")
+	})
+}
@@ -0,0 +1,297 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/cli/cmd/cloudx/client/pool"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	ItemRetriesFlag = "retries"
+	FailedFileFlag  = "failed-file"
+	StrictFlag      = "strict"
+
+	// bulkThrottledRetries is how often the deletion of an item is retried after the API rate limited it. These
+	// retries do not count against --retries.
+	bulkThrottledRetries = 10
+)
+
+// RegisterBulkDeleteFlags registers the flags of bulk deletions, including --workers. failedFile is the default of
+// the --failed-file flag.
+func RegisterBulkDeleteFlags(f *pflag.FlagSet, failedFile string) {
+	f.Int(ItemRetriesFlag, 3, "How often the deletion of an item is retried after a transient error.")
+	f.String(FailedFileFlag, failedFile, "The file to which the items that could not be deleted are written, so that they can be deleted again.")
+	f.Bool(StrictFlag, false, "Exit with a non-zero code if any item could not be deleted or was not found.")
+	RegisterWorkersFlag(f)
+	RegisterMaxFailuresFlag(f)
+}
+
+// DeleteFunc deletes the item with the given key. It returns the response of the API so that a BulkDeletion can adapt
+// to its rate limit.
+type DeleteFunc func(ctx context.Context, key string) (*http.Response, error)
+
+// BulkDeletion deletes many items, e.g. identities, concurrently on the worker pool. Server and network errors are
+// retried with an exponential backoff, rate limited requests pause all workers. Items the API does not find count as
+// not found rather than failed.
+//
+// An interrupt stops starting new items, but the requests which were already sent finish. The same happens once
+// --max-failures requests failed in a row. The items which were not deleted are reported as failed, so that they can
+// be written to the --failed-file and deleted again.
+type BulkDeletion struct {
+	keys        []string
+	delete      DeleteFunc
+	workers     int
+	retries     int
+	maxFailures int
+	backoff     time.Duration
+	throttle    *Throttle
+	progress    *Progress
+	breaker     *pool.Breaker
+}
+
+// BulkDeleteSummary is the result of a BulkDeletion.
+type BulkDeleteSummary struct {
+	Total       int    `json:"total"`
+	Deleted     int    `json:"deleted"`
+	NotFound    int    `json:"not_found"`
+	Failed      int    `json:"failed"`
+	Throttled   int    `json:"throttled"`
+	FailedFile  string `json:"failed_file,omitempty"`
+	Interrupted bool   `json:"interrupted"`
+	// Stopped is set if the deletion stopped because too many requests failed in a row.
+	Stopped error `json:"-"`
+
+	// DeletedKeys and FailedKeys are the keys of the deleted and failed items in the order they were passed in.
+	DeletedKeys []string `json:"-"`
+	FailedKeys  []string `json:"-"`
+}
+
+func (*BulkDeleteSummary) Header() []string {
+	return []string{"TOTAL", "DELETED", "NOT FOUND", "FAILED", "THROTTLED", "FAILED FILE"}
+}
+
+func (s *BulkDeleteSummary) Columns() []string {
+	return []string{
+		fmt.Sprintf("%d", s.Total),
+		fmt.Sprintf("%d", s.Deleted),
+		fmt.Sprintf("%d", s.NotFound),
+		fmt.Sprintf("%d", s.Failed),
+		fmt.Sprintf("%d", s.Throttled),
+		s.FailedFile,
+	}
+}
+
+func (s *BulkDeleteSummary) Interface() interface{} {
+	return s
+}
+
+// NewBulkDeletion returns a BulkDeletion of the items with the given keys, which reads --workers, --retries, and
+// --max-failures from cmd. The label is shown in the progress, e.g. "Deleting identities".
+func (h *CommandHelper) NewBulkDeletion(cmd *cobra.Command, label string, keys []string, del DeleteFunc) (*BulkDeletion, error) {
+	workers, err := Workers(cmd)
+	if err != nil {
+		return nil, err
+	}
+	retries := flagx.MustGetInt(cmd, ItemRetriesFlag)
+	if retries < 0 {
+		return nil, NewValidationError(errors.Errorf("--%s must not be negative", ItemRetriesFlag), nil)
+	}
+	maxFailures, err := MaxFailures(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &BulkDeletion{
+		keys:        keys,
+		delete:      del,
+		workers:     workers,
+		retries:     retries,
+		maxFailures: maxFailures,
+		backoff:     500 * time.Millisecond,
+		throttle:    h.NewThrottle(workers),
+		progress:    h.NewProgress(label, len(keys)),
+	}, nil
+}
+
+// Run deletes the items until all are processed, or until the user interrupts the deletion or ctx is canceled.
+func (d *BulkDeletion) Run(ctx context.Context) *BulkDeleteSummary {
+	stop, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+	return d.run(WithoutCancel(ctx), stop)
+}
+
+// run deletes the items. Once interrupt is done or the breaker opens, no further items are started and no failed
+// requests are retried, but the requests in flight finish because they are sent with ctx.
+func (d *BulkDeletion) run(ctx, interrupt context.Context) *BulkDeleteSummary {
+	stop, breaker := NewBreaker(interrupt, d.maxFailures)
+	d.breaker = breaker
+	summary := &BulkDeleteSummary{Total: len(d.keys)}
+	notFound := make([]bool, len(d.keys))
+
+	errs := pool.Stream(stop, d.workers, len(d.keys), func(_ context.Context, i int) (err error) {
+		notFound[i], err = d.deleteItem(ctx, stop, d.keys[i])
+		return err
+	}, func(i int, err error) {
+		if err != nil {
+			if stop.Err() == nil {
+				d.progress.Logf("Unable to delete %s: %s", d.keys[i], err)
+			}
+			d.progress.Add(1, 1)
+			return
+		}
+		d.progress.Add(1, 0)
+	})
+
+	for i, err := range errs {
+		switch {
+		case err != nil:
+			summary.Failed++
+			summary.FailedKeys = append(summary.FailedKeys, d.keys[i])
+		case notFound[i]:
+			summary.NotFound++
+		default:
+			summary.Deleted++
+			summary.DeletedKeys = append(summary.DeletedKeys, d.keys[i])
+		}
+	}
+	summary.Interrupted = interrupt.Err() != nil
+	summary.Stopped = breaker.Err()
+	summary.Throttled = d.throttle.Events()
+	if summary.Stopped != nil && !summary.Interrupted {
+		d.progress.Halt()
+	} else {
+		d.progress.Finish(summary.Interrupted)
+	}
+	return summary
+}
+
+// deleteItem deletes one item and retries transient errors until stop is done.
+func (d *BulkDeletion) deleteItem(ctx, stop context.Context, key string) (notFound bool, err error) {
+	backoff := d.backoff
+	for attempt, throttled := 0, 0; ; {
+		if err = d.send(ctx, stop, key); err == nil {
+			return false, nil
+		}
+
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			return true, nil
+		case stop.Err() != nil:
+			return false, err
+		case IsThrottled(err):
+			// The throttle already pauses the next request for as long as the API asked for.
+			if throttled >= bulkThrottledRetries {
+				return false, err
+			}
+			throttled++
+			continue
+		case !isTransientError(err) || attempt >= d.retries:
+			return false, err
+		}
+		attempt++
+
+		select {
+		case <-stop.Done():
+			return false, err
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+// send waits for the throttle and sends the request. Waiting is aborted once stop is done, the request itself is not.
+func (d *BulkDeletion) send(ctx, stop context.Context, key string) error {
+	if err := d.throttle.Acquire(stop); err != nil {
+		return errors.WithStack(err)
+	}
+	res, err := d.delete(ctx, key)
+	d.throttle.Release(res)
+	d.breaker.Record(err)
+	return err
+}
+
+// isTransientError returns true for server and network errors, which might not occur when the request is retried.
+func isTransientError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// FinishBulkDeletion writes the keys of the failed items to the --failed-file and logs the summary. It returns an
+// error if the deletion was interrupted, or if --strict is set and any item could not be deleted or was not found.
+func (h *CommandHelper) FinishBulkDeletion(cmd *cobra.Command, summary *BulkDeleteSummary) error {
+	if len(summary.FailedKeys) > 0 {
+		summary.FailedFile = flagx.MustGetString(cmd, FailedFileFlag)
+		if err := writeLines(summary.FailedFile, summary.FailedKeys); err != nil {
+			return err
+		}
+	}
+
+	h.Log.Infof("Deleted: %d, not found: %d, failed: %d", summary.Deleted, summary.NotFound, summary.Failed)
+	if summary.FailedFile != "" {
+		h.Log.Warnf("The %d items which were not deleted were written to %s, pass the file to delete them again.", summary.Failed, summary.FailedFile)
+	}
+
+	if summary.Interrupted {
+		return NewInterruptedError("the deletion was interrupted, the summary contains the partial results")
+	} else if summary.Stopped != nil {
+		return errors.Wrap(summary.Stopped, "the API seems to be unavailable, try again later")
+	}
+	if flagx.MustGetBool(cmd, StrictFlag) && (summary.Failed > 0 || summary.NotFound > 0) {
+		return FailSilently(cmd, ExitFailure)
+	}
+	return nil
+}
+
+// ReadLines reads the non-empty lines of a file, e.g. a file of IDs. Use - to read from stdin.
+func ReadLines(cmd *cobra.Command, file string) ([]string, error) {
+	f, err := OpenInputFile(cmd, file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrapf(err, "unable to read file: %s", displayFileName(InputFileName(file)))
+	}
+	return lines, nil
+}
+
+func writeLines(file string, lines []string) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open file for writing: %s", file)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, l := range lines {
+		_, _ = w.WriteString(l + "\n")
+	}
+	if err := w.Flush(); err != nil {
+		return errors.Wrapf(err, "unable to write file: %s", file)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBulkDeletion(keys []string, workers int, del DeleteFunc) (*BulkDeletion, *bytes.Buffer) {
+	var log bytes.Buffer
+	l := &Logger{out: &log, level: LogLevelInfo}
+	return &BulkDeletion{
+		keys:     keys,
+		delete:   del,
+		workers:  workers,
+		retries:  2,
+		backoff:  time.Millisecond,
+		throttle: newThrottle(l, workers, time.Now),
+		progress: newProgress(&log, l, false, "Deleting identities", len(keys), time.Now),
+	}, &log
+}
+
+func apiResponse(method string, status int) (*http.Response, error) {
+	req, _ := http.NewRequest(method, "https://project.projects.oryapis.com/admin/identities", nil)
+	res := &http.Response{StatusCode: status, Header: http.Header{}, Request: req}
+	if status == http.StatusTooManyRequests {
+		res.Header.Set("Retry-After", "0")
+	}
+	if status >= 300 {
+		return res, &APIError{Method: method, URL: req.URL.String(), StatusCode: status}
+	}
+	return res, nil
+}
+
+func TestBulkDeletion(t *testing.T) {
+	t.Run("case=collects the results", func(t *testing.T) {
+		var mu sync.Mutex
+		attempts := map[string]int{}
+		keys := []string{"deleted-1", "missing", "flaky", "forbidden", "throttled", "deleted-2", "broken"}
+		d, log := newTestBulkDeletion(keys, 4, func(_ context.Context, key string) (*http.Response, error) {
+			mu.Lock()
+			attempts[key]++
+			attempt := attempts[key]
+			mu.Unlock()
+
+			switch {
+			case key == "missing":
+				return apiResponse(http.MethodDelete, http.StatusNotFound)
+			case key == "forbidden":
+				return apiResponse(http.MethodDelete, http.StatusForbidden)
+			case key == "broken":
+				return apiResponse(http.MethodDelete, http.StatusInternalServerError)
+			case key == "flaky" && attempt < 3:
+				return apiResponse(http.MethodDelete, http.StatusBadGateway)
+			case key == "throttled" && attempt < 5:
+				return apiResponse(http.MethodDelete, http.StatusTooManyRequests)
+			}
+			return apiResponse(http.MethodDelete, http.StatusNoContent)
+		})
+
+		s := d.run(context.Background(), context.Background())
+		assert.Equal(t, 7, s.Total)
+		assert.Equal(t, []string{"deleted-1", "flaky", "throttled", "deleted-2"}, s.DeletedKeys)
+		assert.Equal(t, 4, s.Deleted)
+		assert.Equal(t, 1, s.NotFound)
+		assert.Equal(t, []string{"forbidden", "broken"}, s.FailedKeys)
+		assert.Equal(t, 2, s.Failed)
+		assert.Equal(t, 4, s.Throttled)
+		assert.False(t, s.Interrupted)
+
+		assert.Equal(t, 1, attempts["forbidden"], "client errors are not retried")
+		assert.Equal(t, 3, attempts["broken"], "server errors are retried --retries times")
+		assert.Equal(t, 5, attempts["throttled"], "rate limited requests do not count against --retries")
+		assert.Contains(t, log.String(), "Unable to delete forbidden: DELETE")
+		assert.Contains(t, log.String(), "Deleting identities: done, 7 processed (2 failed)")
+	})
+
+	t.Run("case=an interrupt lets the requests in flight finish", func(t *testing.T) {
+		keys := make([]string, 100)
+		for k := range keys {
+			keys[k] = fmt.Sprintf("identity-%d", k)
+		}
+
+		stop, interrupt := context.WithCancel(context.Background())
+		started := make(chan struct{}, len(keys))
+		release := make(chan struct{})
+		d, _ := newTestBulkDeletion(keys, 4, func(ctx context.Context, key string) (*http.Response, error) {
+			started <- struct{}{}
+			<-release
+			// The request must not be canceled by the interrupt.
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return apiResponse(http.MethodDelete, http.StatusNoContent)
+		})
+
+		done := make(chan *BulkDeleteSummary)
+		go func() { done <- d.run(context.Background(), stop) }()
+		for i := 0; i < 4; i++ {
+			<-started
+		}
+		interrupt()
+		close(release)
+		s := <-done
+
+		assert.True(t, s.Interrupted)
+		assert.Equal(t, 4, s.Deleted, "the requests in flight finished")
+		assert.Equal(t, len(keys)-4, s.Failed, "no further items were started")
+		assert.Len(t, s.FailedKeys, len(keys)-4)
+	})
+
+	t.Run("case=stops once the API is consistently failing", func(t *testing.T) {
+		keys := make([]string, 1000)
+		for k := range keys {
+			keys[k] = fmt.Sprintf("identity-%d", k)
+		}
+
+		var requests int
+		d, log := newTestBulkDeletion(keys, 1, func(context.Context, string) (*http.Response, error) {
+			// Every other request is rate limited, which does not count toward the failures.
+			if requests++; requests%2 == 1 {
+				return apiResponse(http.MethodDelete, http.StatusTooManyRequests)
+			}
+			return apiResponse(http.MethodDelete, http.StatusInternalServerError)
+		})
+		d.retries, d.maxFailures = 0, 5
+
+		s := d.run(context.Background(), context.Background())
+		assert.Equal(t, 10, requests, "no request is sent after the fifth failure")
+		assert.False(t, s.Interrupted)
+		assert.ErrorContains(t, s.Stopped, "stopped after 5 consecutive failures")
+		assert.Equal(t, len(keys), s.Failed)
+		assert.Len(t, s.FailedKeys, len(keys), "all items can be deleted again")
+		assert.Contains(t, log.String(), "Deleting identities: stopped because the API is failing, 5 of 1000 processed (5 failed)")
+	})
+}
+
+func TestFinishBulkDeletion(t *testing.T) {
+	newCmd := func(t *testing.T, args ...string) (*cobra.Command, *CommandHelper, *bytes.Buffer) {
+		cmd := &cobra.Command{}
+		RegisterBulkDeleteFlags(cmd.Flags(), filepath.Join(t.TempDir(), "failed.txt"))
+		require.NoError(t, cmd.Flags().Parse(args))
+		var log bytes.Buffer
+		return cmd, &CommandHelper{Log: &Logger{out: &log, level: LogLevelInfo}}, &log
+	}
+
+	t.Run("case=writes the failed items", func(t *testing.T) {
+		cmd, h, log := newCmd(t)
+		s := &BulkDeleteSummary{Total: 3, Deleted: 1, Failed: 2, FailedKeys: []string{"a", "b"}}
+		require.NoError(t, h.FinishBulkDeletion(cmd, s))
+
+		content, err := os.ReadFile(s.FailedFile)
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", string(content))
+		assert.Contains(t, log.String(), "Deleted: 1, not found: 0, failed: 2")
+	})
+
+	t.Run("case=--strict fails on any failed or missing item", func(t *testing.T) {
+		cmd, h, _ := newCmd(t, "--strict")
+		assert.Equal(t, ExitFailure, ExitCode(h.FinishBulkDeletion(cmd, &BulkDeleteSummary{Total: 1, NotFound: 1})))
+		assert.NoError(t, h.FinishBulkDeletion(cmd, &BulkDeleteSummary{Total: 1, Deleted: 1}))
+	})
+
+	t.Run("case=interrupted deletions fail", func(t *testing.T) {
+		cmd, h, _ := newCmd(t)
+		assert.Error(t, h.FinishBulkDeletion(cmd, &BulkDeleteSummary{Total: 2, Deleted: 1, Interrupted: true}))
+	})
+
+	t.Run("case=stopped deletions fail", func(t *testing.T) {
+		cmd, h, _ := newCmd(t)
+		err := h.FinishBulkDeletion(cmd, &BulkDeleteSummary{Total: 2, Failed: 2, FailedKeys: []string{"a", "b"}, Stopped: errors.New("stopped after 25 consecutive failures")})
+		assert.ErrorContains(t, err, "the API seems to be unavailable, try again later")
+	})
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	NoCacheFlag = "no-cache"
+
+	// cacheDirName is the directory of the response cache, next to the configuration file.
+	cacheDirName = ".ory-cache"
+	// projectsCacheTTL is how long the project list is cached, e.g. for resolving project slugs.
+	projectsCacheTTL = 30 * time.Second
+)
+
+// secretKeys are parts of JSON keys whose values are secrets. Responses containing them are never cached.
+var secretKeys = []string{"secret", "password", "token", "private", "api_key", "credential"}
+
+// RegisterNoCacheFlag registers the flag which bypasses the response cache.
+func RegisterNoCacheFlag(f *pflag.FlagSet) {
+	f.Bool(NoCacheFlag, false, "Do not use cached API responses, e.g. of the project list, and do not cache any.")
+}
+
+// responseCache caches the responses of read-only requests which are explicitly safe to cache, e.g. the project
+// list, on disk for a short time. Entries are keyed by the endpoint and a fingerprint of the session token, so that
+// they are never shared between accounts.
+type responseCache struct {
+	dir string
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	ExpiresAt time.Time       `json:"expires_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// newResponseCache returns the cache in the directory of the configuration file, or nil if --no-cache is set.
+func newResponseCache(cmd *cobra.Command, configLocation string) *responseCache {
+	if f := cmd.Flags().Lookup(NoCacheFlag); f != nil && f.Value.String() == "true" {
+		return nil
+	}
+	return &responseCache{dir: filepath.Join(filepath.Dir(configLocation), cacheDirName), now: time.Now}
+}
+
+// cacheKey returns the file name of the entry of endpoint, which is requested with the session token.
+func cacheKey(endpoint, token string) string {
+	fingerprint := sha256.Sum256([]byte(token))
+	key := sha256.Sum256(append([]byte(endpoint+"\x00"), fingerprint[:]...))
+	return hex.EncodeToString(key[:]) + ".json"
+}
+
+// get decodes the entry of endpoint into v and returns true if it exists and did not expire.
+func (c *responseCache) get(endpoint, token string, v interface{}) bool {
+	if c == nil {
+		return false
+	}
+	content, err := os.ReadFile(filepath.Join(c.dir, cacheKey(endpoint, token)))
+	if err != nil {
+		return false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(content, &e); err != nil || !c.now().Before(e.ExpiresAt) {
+		return false
+	}
+	return json.Unmarshal(e.Body, v) == nil
+}
+
+// put caches v as the response of endpoint for ttl. Responses containing secrets are not cached.
+func (c *responseCache) put(endpoint, token string, ttl time.Duration, v interface{}) error {
+	if c == nil {
+		return nil
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if containsSecrets(body) {
+		return errors.Errorf("refusing to cache the response of %s because it contains secrets", endpoint)
+	}
+	content, err := json.Marshal(&cacheEntry{ExpiresAt: c.now().Add(ttl), Body: body})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return errors.Wrapf(err, "unable to create the cache directory %s", c.dir)
+	}
+	// Write to a temporary file first, so that concurrent commands never read a partial entry.
+	tmp, err := os.CreateTemp(c.dir, "entry-*")
+	if err != nil {
+		return errors.Wrapf(err, "unable to write to the cache directory %s", c.dir)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return errors.Wrapf(err, "unable to write to the cache directory %s", c.dir)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "unable to write to the cache directory %s", c.dir)
+	}
+	return errors.WithStack(os.Rename(tmp.Name(), filepath.Join(c.dir, cacheKey(endpoint, token))))
+}
+
+// clear removes all entries and returns how many there were.
+func (c *responseCache) clear() (int, error) {
+	if c == nil {
+		return 0, nil
+	}
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, errors.Wrapf(err, "unable to read the cache directory %s", c.dir)
+	}
+	var n int
+	for _, e := range entries {
+		if err := os.Remove(filepath.Join(c.dir, e.Name())); err != nil {
+			return n, errors.Wrapf(err, "unable to clear the cache directory %s", c.dir)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// containsSecrets returns true if any key of the JSON document looks like it holds a secret.
+func containsSecrets(body []byte) bool {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return true
+	}
+	var walk func(v interface{}) bool
+	walk = func(v interface{}) bool {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				for _, secret := range secretKeys {
+					if strings.Contains(strings.ToLower(k), secret) {
+						return true
+					}
+				}
+				if walk(child) {
+					return true
+				}
+			}
+		case []interface{}:
+			for _, child := range v {
+				if walk(child) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return walk(v)
+}
+
+// ClearCache removes all cached API responses and returns how many there were. It clears the cache even if --no-cache
+// is set.
+func (h *CommandHelper) ClearCache() (int, error) {
+	c := h.cache
+	if c == nil {
+		c = &responseCache{dir: filepath.Join(filepath.Dir(h.ConfigLocation), cacheDirName), now: time.Now}
+	}
+	return c.clear()
+}
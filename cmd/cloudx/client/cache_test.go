@@ -0,0 +1,91 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	const endpoint = "https://api.console.ory.sh/projects"
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := &responseCache{dir: filepath.Join(t.TempDir(), cacheDirName), now: func() time.Time { return now }}
+
+	type project struct {
+		ID   string `json:"id"`
+		Slug string `json:"slug"`
+	}
+	projects := []project{{ID: "ecaaa3cb-0730-4ee8-a6df-9553cdfeef89", Slug: "good-wright-t7kzy3vugf"}}
+
+	var cached []project
+	assert.False(t, c.get(endpoint, "token-a", &cached), "nothing is cached yet")
+
+	require.NoError(t, c.put(endpoint, "token-a", time.Minute, projects))
+	require.True(t, c.get(endpoint, "token-a", &cached))
+	assert.Equal(t, projects, cached)
+
+	t.Run("case=entries are bound to the session token", func(t *testing.T) {
+		assert.False(t, c.get(endpoint, "token-b", &cached))
+	})
+
+	t.Run("case=the session token is not stored", func(t *testing.T) {
+		entries, err := os.ReadDir(c.dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		content, err := os.ReadFile(filepath.Join(c.dir, entries[0].Name()))
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "token-a")
+		assert.NotContains(t, entries[0].Name(), "token-a")
+
+		info, err := entries[0].Info()
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("case=entries expire", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		assert.False(t, c.get(endpoint, "token-a", &cached))
+	})
+
+	t.Run("case=secrets are never cached", func(t *testing.T) {
+		for _, body := range []interface{}{
+			map[string]interface{}{"client_secret": "s3cr3t"},
+			[]interface{}{map[string]interface{}{"id": "a", "credentials": map[string]interface{}{"password": "x"}}},
+			map[string]interface{}{"SessionToken": "x"},
+		} {
+			assert.ErrorContains(t, c.put("https://api.console.ory.sh/secrets", "token-a", time.Minute, body), "contains secrets")
+			assert.False(t, c.get("https://api.console.ory.sh/secrets", "token-a", new(interface{})))
+		}
+	})
+
+	t.Run("case=clear removes all entries", func(t *testing.T) {
+		require.NoError(t, c.put(endpoint, "token-a", time.Minute, projects))
+		require.NoError(t, c.put(endpoint, "token-b", time.Minute, projects))
+		n, err := c.clear()
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.False(t, c.get(endpoint, "token-a", &cached))
+
+		n, err = (&responseCache{dir: filepath.Join(t.TempDir(), "missing")}).clear()
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("case=--no-cache disables the cache", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		RegisterNoCacheFlag(cmd.Flags())
+		config := filepath.Join(t.TempDir(), fileName)
+		assert.Equal(t, filepath.Join(filepath.Dir(config), cacheDirName), newResponseCache(cmd, config).dir)
+
+		require.NoError(t, cmd.Flags().Parse([]string{"--no-cache"}))
+		var disabled *responseCache = newResponseCache(cmd, config)
+		assert.Nil(t, disabled)
+		assert.False(t, disabled.get(endpoint, "token-a", &cached))
+		assert.NoError(t, disabled.put(endpoint, "token-a", time.Minute, projects))
+	})
+}
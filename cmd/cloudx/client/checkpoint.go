@@ -0,0 +1,172 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	checkpointVersion = 1
+	// checkpointInterval is how often a Checkpoint is saved while records are processed.
+	checkpointInterval = 2 * time.Second
+)
+
+type recordState uint8
+
+const (
+	recordPending recordState = iota
+	recordSucceeded
+	recordFailed
+)
+
+// Checkpoint records which records of a bulk operation, e.g. the identities of an import, were processed, so that
+// an aborted operation can be resumed. It is saved to a state file periodically and when the operation finished.
+//
+// Records are identified by their position in the input, starting at 1, which is the line number of newline
+// delimited JSON. The state file is bound to the hash of the input and cannot be used with a modified input.
+type Checkpoint struct {
+	file   string
+	hash   string
+	states []recordState
+	// processed is the number of leading records which were all processed.
+	processed int
+	now       func() time.Time
+	saved     time.Time
+}
+
+// checkpointFile is the content of the state file.
+type checkpointFile struct {
+	Version   int    `json:"version"`
+	InputHash string `json:"input_hash"`
+	Total     int    `json:"total"`
+	// Processed is the highest position up to which all records were processed.
+	Processed int `json:"processed"`
+	// Failed are the positions of the records which failed.
+	Failed []int `json:"failed,omitempty"`
+	// Succeeded are the positions above Processed of the records which succeeded.
+	Succeeded []int `json:"succeeded,omitempty"`
+}
+
+// LoadCheckpoint loads the checkpoint of the input with the given hash and number of records from the state file.
+// If the state file does not exist, no record was processed yet. The file is not written before the first Record.
+func LoadCheckpoint(file, inputHash string, total int) (*Checkpoint, error) {
+	c := &Checkpoint{file: file, hash: inputHash, states: make([]recordState, total), now: time.Now}
+
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "unable to read state file: %s", file)
+	}
+
+	var f checkpointFile
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse state file: %s", file)
+	}
+	if f.Version != checkpointVersion {
+		return nil, NewValidationError(errors.Errorf("the state file %s was written by an incompatible version of the CLI, remove it to start over", file), nil)
+	}
+	if f.InputHash != inputHash || f.Total != total || f.Processed > total {
+		return nil, NewValidationError(errors.Errorf("the state file %s belongs to a different input, the input was modified since the state was saved; remove the state file to start over", file), nil)
+	}
+
+	for i := 0; i < f.Processed; i++ {
+		c.states[i] = recordSucceeded
+	}
+	for _, pos := range f.Succeeded {
+		if pos < 1 || pos > total {
+			return nil, errors.Errorf("unable to parse state file %s: position %d is out of range", file, pos)
+		}
+		c.states[pos-1] = recordSucceeded
+	}
+	// Failed records are retried, so they are pending again.
+	for _, pos := range f.Failed {
+		if pos < 1 || pos > total {
+			return nil, errors.Errorf("unable to parse state file %s: position %d is out of range", file, pos)
+		}
+		c.states[pos-1] = recordPending
+	}
+	c.advance()
+	return c, nil
+}
+
+// Succeeded returns true if the record at index i, starting at 0, succeeded in a previous run and must be skipped.
+func (c *Checkpoint) Succeeded(i int) bool {
+	return c.states[i] == recordSucceeded
+}
+
+// Record records the outcome of the record at index i, starting at 0, and saves the checkpoint if it was not saved
+// within the last checkpointInterval. Records which were not processed, e.g. because the operation was interrupted,
+// must not be recorded, so that they are processed when the operation is resumed.
+func (c *Checkpoint) Record(i int, err error) error {
+	c.states[i] = recordSucceeded
+	if err != nil {
+		c.states[i] = recordFailed
+	}
+	c.advance()
+
+	if now := c.now(); now.Sub(c.saved) >= checkpointInterval {
+		c.saved = now
+		return c.save()
+	}
+	return nil
+}
+
+// Finish removes the state file if all records succeeded, and saves the checkpoint otherwise. It returns true if the
+// state file was kept.
+func (c *Checkpoint) Finish() (bool, error) {
+	for _, s := range c.states {
+		if s != recordSucceeded {
+			return true, c.save()
+		}
+	}
+	if err := os.Remove(c.file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, errors.Wrapf(err, "unable to remove state file: %s", c.file)
+	}
+	return false, nil
+}
+
+// advance moves processed past the records which were processed, including failed ones.
+func (c *Checkpoint) advance() {
+	for c.processed < len(c.states) && c.states[c.processed] != recordPending {
+		c.processed++
+	}
+}
+
+// save writes the state file atomically, so that a crash while saving does not lose the previous checkpoint.
+func (c *Checkpoint) save() error {
+	f := checkpointFile{Version: checkpointVersion, InputHash: c.hash, Total: len(c.states), Processed: c.processed}
+	for i, s := range c.states {
+		switch {
+		case s == recordFailed:
+			f.Failed = append(f.Failed, i+1)
+		case s == recordSucceeded && i >= c.processed:
+			f.Succeeded = append(f.Succeeded, i+1)
+		}
+	}
+
+	content, err := json.Marshal(&f)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.file), filepath.Base(c.file)+".*.tmp")
+	if err != nil {
+		return errors.Wrapf(err, "unable to write state file: %s", c.file)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return errors.Wrapf(err, "unable to write state file: %s", c.file)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "unable to write state file: %s", c.file)
+	}
+	if err := os.Rename(tmp.Name(), c.file); err != nil {
+		return errors.Wrapf(err, "unable to write state file: %s", c.file)
+	}
+	return nil
+}
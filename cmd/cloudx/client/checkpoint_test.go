@@ -0,0 +1,94 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	const hash = "sha256:0a1b"
+	file := filepath.Join(t.TempDir(), "import.checkpoint")
+	failed := errors.New("failed")
+
+	c, err := LoadCheckpoint(file, hash, 6)
+	require.NoError(t, err)
+	for i := 0; i < 6; i++ {
+		assert.False(t, c.Succeeded(i))
+	}
+
+	// Records finish out of order, record 3 is still pending and record 4 was not started.
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+	require.NoError(t, c.Record(0, nil))
+	require.NoError(t, c.Record(1, failed))
+	require.NoError(t, c.Record(5, nil))
+	require.NoError(t, c.Record(2, nil))
+	kept, err := c.Finish()
+	require.NoError(t, err)
+	assert.True(t, kept)
+
+	var saved checkpointFile
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &saved))
+	assert.Equal(t, checkpointFile{Version: checkpointVersion, InputHash: hash, Total: 6, Processed: 3, Failed: []int{2}, Succeeded: []int{6}}, saved)
+
+	t.Run("case=resumes the failed and unprocessed records", func(t *testing.T) {
+		c, err := LoadCheckpoint(file, hash, 6)
+		require.NoError(t, err)
+		var succeeded []bool
+		for i := 0; i < 6; i++ {
+			succeeded = append(succeeded, c.Succeeded(i))
+		}
+		assert.Equal(t, []bool{true, false, true, false, false, true}, succeeded)
+	})
+
+	t.Run("case=refuses a modified input", func(t *testing.T) {
+		_, err := LoadCheckpoint(file, "sha256:ffff", 6)
+		assert.Equal(t, ExitValidation, ExitCode(err))
+		assert.Contains(t, err.Error(), "belongs to a different input")
+
+		_, err = LoadCheckpoint(file, hash, 7)
+		assert.Equal(t, ExitValidation, ExitCode(err))
+	})
+
+	t.Run("case=saves periodically", func(t *testing.T) {
+		c, err := LoadCheckpoint(file, hash, 6)
+		require.NoError(t, err)
+		c.now = func() time.Time { return now }
+		c.saved = now
+
+		require.NoError(t, c.Record(1, nil))
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"failed":[2]`, "the checkpoint is not saved before the interval passed")
+
+		now = now.Add(checkpointInterval)
+		require.NoError(t, c.Record(3, nil))
+		content, err = os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"processed":4`)
+		assert.NotContains(t, string(content), `"failed"`)
+	})
+
+	t.Run("case=removes the state file once all records succeeded", func(t *testing.T) {
+		c, err := LoadCheckpoint(file, hash, 6)
+		require.NoError(t, err)
+		for i := 0; i < 6; i++ {
+			if !c.Succeeded(i) {
+				require.NoError(t, c.Record(i, nil))
+			}
+		}
+		kept, err := c.Finish()
+		require.NoError(t, err)
+		assert.False(t, kept)
+		assert.NoFileExists(t, file)
+	})
+}
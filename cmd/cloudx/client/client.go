@@ -3,18 +3,13 @@ package client
 import (
 	"context"
 	"fmt"
-	"net/http"
-	"os"
-	"time"
 
 	"github.com/gofrs/uuid/v3"
-	"github.com/hashicorp/go-retryablehttp"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 
 	kratos "github.com/ory/kratos-client-go"
 	"github.com/ory/kratos/cmd/cliclient"
-	"github.com/ory/x/cmdx"
 	"github.com/ory/x/flagx"
 )
 
@@ -24,12 +19,21 @@ func RegisterProjectFlag(f *flag.FlagSet) {
 	f.String(projectFlag, "", "The project to use")
 }
 
+// ContextWithClient configures the Ory Cloud API clients of all commands executed with the returned context.
 func ContextWithClient(ctx context.Context) context.Context {
+	ctx = contextWithConnectivity(contextWithTimingLog(contextWithRequestIDLog(ctx)))
 	return context.WithValue(ctx, cliclient.ClientContextKey, func(cmd *cobra.Command) (*kratos.APIClient, error) {
 		sc, err := NewCommandHelper(cmd)
 		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Failed to initialize HTTP Client: %s\n", err)
-			return nil, cmdx.FailSilently(cmd)
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Failed to initialize HTTP Client: %s\n", err)
+			return nil, FailSilently(cmd, ExitFailure)
+		}
+
+		endpoints, err := sc.Endpoints()
+		if err != nil {
+			return nil, err
+		} else if endpoint := endpoints[ServiceIdentity]; endpoint != "" {
+			return sc.client.identityAPI("", endpoint), nil
 		}
 
 		ac, err := sc.EnsureContext()
@@ -39,8 +43,8 @@ func ContextWithClient(ctx context.Context) context.Context {
 
 		project := uuid.FromStringOrNil(flagx.MustGetString(cmd, projectFlag))
 		if project == uuid.Nil {
-			_, _ = fmt.Fprintf(os.Stderr, "No project selected! Please use the flag --%s to specify one.\n", projectFlag)
-			return nil, cmdx.FailSilently(cmd)
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "No project selected! Please use the flag --%s to specify one.\n", projectFlag)
+			return nil, FailSilently(cmd, ExitValidation)
 		}
 
 		p, err := sc.GetProject(project.String())
@@ -48,15 +52,6 @@ func ContextWithClient(ctx context.Context) context.Context {
 			return nil, err
 		}
 
-		c := retryablehttp.NewClient()
-		c.Logger = nil
-
-		conf := kratos.NewConfiguration()
-		conf.HTTPClient = &http.Client{
-			Transport: &bearerTokenTransporter{RoundTripper: c.StandardClient().Transport, bearerToken: ac.SessionToken},
-			Timeout:   time.Second * 10}
-
-		conf.Servers = kratos.ServerConfigurations{{URL: makeCloudConsoleURL(p.Slug + ".projects")}}
-		return kratos.NewAPIClient(conf), nil
+		return sc.client.identityAPI(ac.SessionToken, ProjectAPIURL(p.Slug)), nil
 	})
 }
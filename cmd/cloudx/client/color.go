@@ -0,0 +1,81 @@
+package client
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+const NoColorFlag = "no-color"
+
+// RegisterNoColorFlag registers the flag which disables colored output.
+func RegisterNoColorFlag(f *pflag.FlagSet) {
+	f.Bool(NoColorFlag, false, "Disable colored output. Colors are also disabled if the NO_COLOR environment variable is set or the output is not a terminal.")
+}
+
+// Colors colorizes human readable output. The zero value does not colorize anything.
+type Colors struct {
+	Enabled bool
+}
+
+// NewColors returns the Colors for output written to w by cmd.
+func NewColors(cmd *cobra.Command, w io.Writer) Colors {
+	var noColor bool
+	if f := cmd.Flags().Lookup(NoColorFlag); f != nil {
+		noColor = f.Value.String() == "true"
+	}
+	return Colors{Enabled: !noColor && os.Getenv("NO_COLOR") == "" && isTerminalWriter(w)}
+}
+
+func isTerminalWriter(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func (c Colors) paint(code, s string) string {
+	if !c.Enabled || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func (c Colors) Success(s string) string {
+	return c.paint("32", s)
+}
+
+func (c Colors) Warn(s string) string {
+	return c.paint("33", s)
+}
+
+func (c Colors) Error(s string) string {
+	return c.paint("31", s)
+}
+
+// Dim is used for secondary information.
+func (c Colors) Dim(s string) string {
+	return c.paint("2", s)
+}
+
+// Diff colorizes the lines of a unified diff.
+func (c Colors) Diff(diff string) string {
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		content := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			content = c.paint("1", content)
+		case strings.HasPrefix(line, "@@"):
+			content = c.paint("36", content)
+		case strings.HasPrefix(line, "+"):
+			content = c.Success(content)
+		case strings.HasPrefix(line, "-"):
+			content = c.Error(content)
+		}
+		lines[i] = content + line[len(strings.TrimSuffix(line, "\n")):]
+	}
+	return strings.Join(lines, "")
+}
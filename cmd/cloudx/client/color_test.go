@@ -0,0 +1,49 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColors(t *testing.T) {
+	diff := `--- deployed
++++ namespaces.ts
+@@ -1 +1,2 @@
+ class User implements Namespace {}
+-class Group {}
++class Group implements Namespace {}
+`
+
+	t.Run("case=plain", func(t *testing.T) {
+		var c Colors
+		assert.Equal(t, diff, c.Diff(diff))
+		assert.Equal(t, "done", c.Success("done"))
+		assert.Equal(t, "careful", c.Warn("careful"))
+		assert.Equal(t, "failed", c.Error("failed"))
+		assert.Equal(t, "secondary", c.Dim("secondary"))
+	})
+
+	t.Run("case=colored", func(t *testing.T) {
+		c := Colors{Enabled: true}
+		assert.Equal(t, "\x1b[1m--- deployed\x1b[0m\n"+
+			"\x1b[1m+++ namespaces.ts\x1b[0m\n"+
+			"\x1b[36m@@ -1 +1,2 @@\x1b[0m\n"+
+			" class User implements Namespace {}\n"+
+			"\x1b[31m-class Group {}\x1b[0m\n"+
+			"\x1b[32m+class Group implements Namespace {}\x1b[0m\n", c.Diff(diff))
+		assert.Equal(t, "\x1b[32mdone\x1b[0m", c.Success("done"))
+		assert.Equal(t, "\x1b[33mcareful\x1b[0m", c.Warn("careful"))
+		assert.Equal(t, "\x1b[31mfailed\x1b[0m", c.Error("failed"))
+		assert.Equal(t, "\x1b[2msecondary\x1b[0m", c.Dim("secondary"))
+		assert.Equal(t, "", c.Warn(""))
+	})
+
+	t.Run("case=disabled if the output is not a terminal", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		RegisterNoColorFlag(cmd.Flags())
+		assert.False(t, NewColors(cmd, new(bytes.Buffer)).Enabled)
+	})
+}
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	ColumnsFlag    = "columns"
+	NoTruncateFlag = "no-truncate"
+
+	// ColumnsHelp is the value of the --columns flag which prints the selectable columns instead of the table.
+	ColumnsHelp = "help"
+
+	// maxCellWidth is the number of characters after which cells of custom columns are truncated.
+	maxCellWidth = 48
+)
+
+// RegisterColumnsFlags registers the --columns and --no-truncate flags, which are applied to the table output of
+// commands listing resources.
+func RegisterColumnsFlags(f *pflag.FlagSet) {
+	f.StringSlice(ColumnsFlag, nil, "Comma separated list of the columns to print in table output. Columns are table column names or gjson paths into the JSON output. Use \"help\" to list the available columns.")
+	f.Bool(NoTruncateFlag, false, "Do not truncate long cells of custom columns. Cells are never truncated with --format table-wide.")
+}
+
+// Columns returns the value of the --columns flag or nil if the command does not have the flag.
+func Columns(cmd *cobra.Command) []string {
+	columns, err := cmd.Flags().GetStringSlice(ColumnsFlag)
+	if err != nil {
+		return nil
+	}
+	return columns
+}
+
+// columnTables holds the tables printed by PrintTable while EnableColumnsFlag captures the output of a command, so
+// that columns can be selected by their table column name.
+var columnTables = struct {
+	sync.Mutex
+	m map[*cobra.Command]cmdx.Table
+}{m: map[*cobra.Command]cmdx.Table{}}
+
+func recordColumnTable(cmd *cobra.Command, table cmdx.Table) {
+	columnTables.Lock()
+	defer columnTables.Unlock()
+	if _, ok := columnTables.m[cmd]; ok {
+		columnTables.m[cmd] = table
+	}
+}
+
+// EnableColumnsFlag applies the --columns flag to the output of cmd and all its sub commands. The command is run
+// with JSON output, and the selected columns are rendered from the tables passed to PrintTable, or only from the JSON
+// output for commands which print their tables otherwise.
+func EnableColumnsFlag(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableColumnsFlag(c)
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		columns := Columns(cmd)
+		if len(columns) == 0 {
+			return run(cmd, args)
+		}
+		format := OutputFormat(cmd)
+		switch {
+		case format != FormatDefault && format != FormatTable && format != FormatTableWide && format != FormatGitHub:
+			return NewValidationError(errors.Errorf("the --%s flag can only be used with table output", ColumnsFlag), nil)
+		case Query(cmd) != "":
+			return NewValidationError(errors.Errorf("the --%s and --%s flags can not be used together", ColumnsFlag, QueryFlag), nil)
+		}
+		if f := cmd.Flags().Lookup(cmdx.FlagFormat); f != nil {
+			if err := f.Value.Set(FormatJSON); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		columnTables.Lock()
+		columnTables.m[cmd] = nil
+		columnTables.Unlock()
+		defer func() {
+			columnTables.Lock()
+			delete(columnTables.m, cmd)
+			columnTables.Unlock()
+		}()
+
+		out := cmd.OutOrStdout()
+		var captured bytes.Buffer
+		cmd.SetOut(&captured)
+		err := run(cmd, args)
+		cmd.SetOut(out)
+		if err != nil {
+			return err
+		}
+
+		columnTables.Lock()
+		table := columnTables.m[cmd]
+		columnTables.Unlock()
+
+		noTruncate, _ := cmd.Flags().GetBool(NoTruncateFlag)
+		return printColumns(out, captured.Bytes(), table, columns, !noTruncate && format != FormatTableWide)
+	}
+}
+
+// columnSource resolves column names against the table columns and the JSON items of a collection.
+type columnSource struct {
+	header []string
+	rows   [][]string
+	items  []gjson.Result
+}
+
+func newColumnSource(output []byte, table cmdx.Table) (*columnSource, error) {
+	if !gjson.ValidBytes(output) {
+		return nil, errors.New("unable to select columns because the output is not JSON")
+	}
+
+	s := new(columnSource)
+	if parsed := gjson.ParseBytes(output); parsed.IsArray() {
+		s.items = parsed.Array()
+	} else {
+		s.items = []gjson.Result{parsed}
+	}
+	// Table columns can only be used if the rows belong to the JSON items.
+	if table != nil && len(table.Table()) == len(s.items) {
+		s.header, s.rows = table.Header(), table.Table()
+	}
+	return s, nil
+}
+
+// normalizeColumnName makes table column names such as "CLIENT ID" selectable as "client_id".
+func normalizeColumnName(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// available returns the table column names followed by the paths of all other values of the JSON items.
+func (s *columnSource) available() []string {
+	var columns []string
+	seen := map[string]bool{}
+	for _, h := range s.header {
+		columns = append(columns, normalizeColumnName(h))
+		seen[normalizeColumnName(h)] = true
+	}
+
+	var paths []string
+	var walk func(prefix string, v gjson.Result)
+	walk = func(prefix string, v gjson.Result) {
+		if !v.IsObject() {
+			if prefix != "" && !seen[prefix] {
+				seen[prefix] = true
+				paths = append(paths, prefix)
+			}
+			return
+		}
+		v.ForEach(func(key, value gjson.Result) bool {
+			path := key.String()
+			if prefix != "" {
+				path = prefix + "." + path
+			}
+			walk(path, value)
+			return true
+		})
+	}
+	for _, item := range s.items {
+		walk("", item)
+	}
+	sort.Strings(paths)
+	return append(columns, paths...)
+}
+
+// cells returns the column values of all items, or false if the column neither is a table column nor exists in any
+// of the items.
+func (s *columnSource) cells(column string) ([]string, bool) {
+	cells := make([]string, len(s.items))
+	for i, h := range s.header {
+		if normalizeColumnName(h) != normalizeColumnName(column) {
+			continue
+		}
+		for k, row := range s.rows {
+			if i < len(row) {
+				cells[k] = row[i]
+			}
+		}
+		return cells, true
+	}
+
+	var exists bool
+	for k, item := range s.items {
+		v := item.Get(column)
+		if !v.Exists() {
+			continue
+		}
+		exists = true
+		cells[k] = formatQueryResult(v)
+	}
+	return cells, exists
+}
+
+func printColumns(out io.Writer, output []byte, table cmdx.Table, columns []string, truncate bool) error {
+	s, err := newColumnSource(output, table)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 1 && columns[0] == ColumnsHelp {
+		for _, c := range s.available() {
+			_, _ = fmt.Fprintln(out, c)
+		}
+		return nil
+	}
+
+	values := make([][]string, len(columns))
+	for k, c := range columns {
+		cells, ok := s.cells(c)
+		if !ok && len(s.items) > 0 {
+			return NewValidationError(errors.Errorf("unknown column %q, available columns are: %s", c, strings.Join(s.available(), ", ")), nil)
+		}
+		values[k] = cells
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 1, '\t', 0)
+	header := make([]string, len(columns))
+	for k, c := range columns {
+		header[k] = strings.ToUpper(c)
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+	for row := range s.items {
+		line := make([]string, len(columns))
+		for k := range columns {
+			line[k] = values[k][row]
+			if truncate {
+				line[k] = truncateCell(line[k])
+			}
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(line, "\t"))
+	}
+	return errors.WithStack(w.Flush())
+}
+
+// truncateCell shortens cells longer than maxCellWidth characters and ends them with an ellipsis.
+func truncateCell(cell string) string {
+	cell = strings.Join(strings.Fields(cell), " ")
+	if utf8.RuneCountInString(cell) <= maxCellWidth {
+		return cell
+	}
+	return string([]rune(cell)[:maxCellWidth-1]) + "…"
+}
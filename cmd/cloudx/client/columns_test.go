@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testIdentities []map[string]interface{}
+
+func (t testIdentities) Header() []string {
+	return []string{"ID", "VERIFIED ADDRESS"}
+}
+
+func (t testIdentities) Table() [][]string {
+	rows := make([][]string, len(t))
+	for k, i := range t {
+		rows[k] = []string{i["id"].(string), i["traits"].(map[string]interface{})["email"].(string)}
+	}
+	return rows
+}
+
+func (t testIdentities) Interface() interface{} {
+	return []map[string]interface{}(t)
+}
+
+func (t testIdentities) Len() int {
+	return len(t)
+}
+
+func TestEnableColumnsFlag(t *testing.T) {
+	identities := testIdentities{
+		{"id": "a", "traits": map[string]interface{}{"email": "a@example.com"}, "created_at": "2022-01-01"},
+		{"id": "b", "traits": map[string]interface{}{"email": "b@example.com", "bio": strings.Repeat("x", 60)}},
+	}
+	run := func(t *testing.T, args ...string) (string, error) {
+		cmd := &cobra.Command{Use: "identities", RunE: func(cmd *cobra.Command, _ []string) error {
+			PrintTable(cmd, identities)
+			return nil
+		}}
+		RegisterFormatFlag(cmd.Flags())
+		RegisterColumnsFlags(cmd.Flags())
+		EnableColumnsFlag(cmd)
+
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	t.Run("case=selects table columns and paths in order", func(t *testing.T) {
+		out, err := run(t, "--columns", "traits.email,id,verified_address,created_at")
+		require.NoError(t, err)
+		assert.Equal(t, "TRAITS.EMAIL\tID\tVERIFIED_ADDRESS\tCREATED_AT\n"+
+			"a@example.com\ta\ta@example.com\t\t2022-01-01\n"+
+			"b@example.com\tb\tb@example.com\t\t\n", out)
+	})
+
+	t.Run("case=truncates long cells", func(t *testing.T) {
+		out, err := run(t, "--columns", "traits.bio")
+		require.NoError(t, err)
+		assert.Contains(t, out, strings.Repeat("x", maxCellWidth-1)+"…\n")
+
+		out, err = run(t, "--columns", "traits.bio", "--no-truncate")
+		require.NoError(t, err)
+		assert.Contains(t, out, strings.Repeat("x", 60)+"\n")
+	})
+
+	t.Run("case=lists available columns", func(t *testing.T) {
+		out, err := run(t, "--columns", "help")
+		require.NoError(t, err)
+		assert.Equal(t, "id\nverified_address\ncreated_at\ntraits.bio\ntraits.email\n", out)
+	})
+
+	t.Run("case=fails on unknown columns", func(t *testing.T) {
+		_, err := run(t, "--columns", "id,nope")
+		assert.ErrorContains(t, err, `unknown column "nope", available columns are: id, verified_address, created_at`)
+	})
+
+	t.Run("case=only applies to table output", func(t *testing.T) {
+		_, err := run(t, "--columns", "id", "--format", "json")
+		assert.ErrorContains(t, err, "can only be used with table output")
+	})
+
+	t.Run("case=default output is unchanged", func(t *testing.T) {
+		out, err := run(t)
+		require.NoError(t, err)
+		assert.Contains(t, out, "VERIFIED ADDRESS")
+	})
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	NoCompressionFlag = "no-compression"
+
+	// gzipMinSize is the size from which request bodies are compressed. Smaller bodies do not get much smaller.
+	gzipMinSize = 8 << 10
+)
+
+// gzipEndpoints are the endpoints which are known to accept gzip compressed request bodies, by method and path
+// prefix. They receive the large request bodies of bulk operations and project updates.
+var gzipEndpoints = []struct{ method, path string }{
+	{method: http.MethodPatch, path: "/admin/relation-tuples"},
+	{method: http.MethodPut, path: "/projects/"},
+}
+
+// RegisterCompressionFlag registers the flag which disables the compression of request bodies.
+func RegisterCompressionFlag(f *pflag.FlagSet) {
+	f.Bool(NoCompressionFlag, false, "Do not compress large request bodies with gzip, e.g. if a proxy does not support it.")
+}
+
+// IsCompressionEnabled returns false if the --no-compression flag of cmd is set.
+func IsCompressionEnabled(cmd *cobra.Command) bool {
+	disabled, _ := cmd.Flags().GetBool(NoCompressionFlag)
+	return !disabled
+}
+
+// gzipTransport requests gzip compressed responses and decompresses them. If compress is set, it also compresses
+// request bodies of at least gzipMinSize bytes which are sent to gzipEndpoints. The sizes before and after the
+// compression are logged at the debug level.
+//
+// http.Transport transparently decompresses responses as well, but does not reveal their compressed size.
+type gzipTransport struct {
+	http.RoundTripper
+	compress bool
+}
+
+func (t *gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := loggerFromContext(req.Context())
+	if t.compress && acceptsGzip(req) {
+		compressed, err := gzipRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		if compressed != req {
+			l.Debugf("Compressed the request body of %s %s from %d to %d bytes", req.Method, req.URL.Path, req.ContentLength, compressed.ContentLength)
+			req = compressed
+		}
+	}
+
+	if req.Header.Get("Accept-Encoding") != "" {
+		return t.RoundTripper.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	res, err := t.RoundTripper.RoundTrip(req)
+	if err != nil || !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") ||
+		req.Method == http.MethodHead || res.StatusCode == http.StatusNoContent || res.StatusCode == http.StatusNotModified {
+		return res, err
+	}
+
+	raw := &countingReader{r: res.Body}
+	zr, err := gzip.NewReader(raw)
+	if err != nil {
+		_ = res.Body.Close()
+		return nil, errors.Wrapf(err, "unable to decompress the response of %s %s", req.Method, req.URL.Path)
+	}
+	res.Body = &gzipBody{zr: zr, body: res.Body, raw: raw, log: func(compressed, decompressed int64) {
+		l.Debugf("Received %d bytes (%d bytes compressed) from %s %s", decompressed, compressed, req.Method, req.URL.Path)
+	}}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+	return res, nil
+}
+
+func acceptsGzip(req *http.Request) bool {
+	if req.Body == nil || req.GetBody == nil || req.ContentLength < gzipMinSize || req.Header.Get("Content-Encoding") != "" {
+		return false
+	}
+	for _, e := range gzipEndpoints {
+		if req.Method == e.method && strings.HasPrefix(req.URL.Path, e.path) {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipRequest returns a copy of req with a gzip compressed body. It returns req itself if compressing does not make the
+// body smaller.
+func gzipRequest(req *http.Request) (*http.Request, error) {
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer body.Close()
+
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	if _, err := io.Copy(zw, body); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if int64(b.Len()) >= req.ContentLength {
+		return req, nil
+	}
+
+	compressed := b.Bytes()
+	c := req.Clone(req.Context())
+	c.Header.Set("Content-Encoding", "gzip")
+	c.ContentLength = int64(len(compressed))
+	c.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	c.Body, _ = c.GetBody()
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	return c, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// gzipBody decompresses a response body and logs the sizes once it is closed.
+type gzipBody struct {
+	zr     *gzip.Reader
+	body   io.Closer
+	raw    *countingReader
+	n      int64
+	log    func(compressed, decompressed int64)
+	logged bool
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	n, err := b.zr.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *gzipBody) Close() error {
+	if !b.logged {
+		b.logged = true
+		b.log(b.raw.n, b.n)
+	}
+	return b.body.Close()
+}
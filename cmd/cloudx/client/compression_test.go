@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGzipTransport(t *testing.T) {
+	payload := `[` + strings.Repeat(`{"namespace":"documents","object":"doc-1","relation":"viewer","subject_id":"user:alice"},`, 200) + `{}]`
+
+	type received struct {
+		acceptEncoding, contentEncoding, body string
+	}
+	var last received
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = received{acceptEncoding: r.Header.Get("Accept-Encoding"), contentEncoding: r.Header.Get("Content-Encoding")}
+		body := io.Reader(r.Body)
+		if last.contentEncoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = zr
+		}
+		b, err := io.ReadAll(body)
+		require.NoError(t, err)
+		last.body = string(b)
+
+		if r.URL.Query().Get("compress") == "false" || !strings.Contains(last.acceptEncoding, "gzip") {
+			_, _ = w.Write([]byte(payload))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(payload))
+		_ = zw.Close()
+	}))
+	t.Cleanup(ts.Close)
+
+	send := func(t *testing.T, compress bool, method, path, body string) (string, string) {
+		var log bytes.Buffer
+		ctx := contextWithLogger(context.Background(), &Logger{out: &log, level: LogLevelDebug})
+		req, err := http.NewRequestWithContext(ctx, method, ts.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+
+		c := newClient(http.DefaultTransport, clientConfig{compress: compress}).httpClient("")
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		b, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		// The sizes are logged once the body is closed.
+		require.NoError(t, res.Body.Close())
+		return string(b), log.String()
+	}
+
+	t.Run("case=decompresses responses", func(t *testing.T) {
+		body, log := send(t, true, http.MethodGet, "/admin/identities", "")
+		assert.Equal(t, payload, body)
+		assert.Equal(t, "gzip", last.acceptEncoding)
+		assert.Regexp(t, fmt.Sprintf(`Received %d bytes \(\d+ bytes compressed\) from GET /admin/identities`, len(payload)), log)
+	})
+
+	t.Run("case=accepts uncompressed responses", func(t *testing.T) {
+		body, log := send(t, true, http.MethodGet, "/admin/identities?compress=false", "")
+		assert.Equal(t, payload, body)
+		assert.NotContains(t, log, "Received")
+	})
+
+	t.Run("case=compresses large request bodies of known endpoints", func(t *testing.T) {
+		_, log := send(t, true, http.MethodPatch, "/admin/relation-tuples", payload)
+		assert.Equal(t, "gzip", last.contentEncoding)
+		assert.Equal(t, payload, last.body)
+		assert.Regexp(t, fmt.Sprintf(`Compressed the request body of PATCH /admin/relation-tuples from %d to \d+ bytes`, len(payload)), log)
+
+		send(t, true, http.MethodPut, "/projects/ecaaa3cb-0730-4ee8-a6df-9553cdfeef89", payload)
+		assert.Equal(t, "gzip", last.contentEncoding)
+		assert.Equal(t, payload, last.body)
+	})
+
+	for name, tc := range map[string]struct {
+		compress           bool
+		method, path, body string
+	}{
+		"small bodies":      {compress: true, method: http.MethodPatch, path: "/admin/relation-tuples", body: `[{}]`},
+		"unknown endpoints": {compress: true, method: http.MethodPost, path: "/admin/identities", body: payload},
+		"--no-compression":  {compress: false, method: http.MethodPatch, path: "/admin/relation-tuples", body: payload},
+	} {
+		t.Run("case=does not compress "+name, func(t *testing.T) {
+			_, log := send(t, tc.compress, tc.method, tc.path, tc.body)
+			assert.Empty(t, last.contentEncoding)
+			assert.Equal(t, tc.body, last.body)
+			assert.NotContains(t, log, "Compressed")
+		})
+	}
+}
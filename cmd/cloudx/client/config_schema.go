@@ -0,0 +1,180 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/jsonschema/v3"
+)
+
+const (
+	// ConfigSchemaID is the ID of the JSON Schema of the configuration file. The schema is committed to the
+	// repository, so that editors can validate and complete configuration files.
+	ConfigSchemaID = "https://github.com/ory/cli/spec/config.schema.json"
+
+	// StrictConfigFlag validates the configuration file against its JSON Schema before reading it.
+	StrictConfigFlag = "strict-config"
+)
+
+// uuidPattern matches the UUIDs the configuration file contains. Draft 7 has no uuid format, so that the format is
+// only an annotation for editors.
+const uuidPattern = "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$"
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// jsonSchemaDescriber is implemented by the types of the configuration file whose JSON Schema can not be derived from
+// their fields.
+type jsonSchemaDescriber interface {
+	JSONSchema() map[string]interface{}
+}
+
+var jsonSchemaDescriberType = reflect.TypeOf((*jsonSchemaDescriber)(nil)).Elem()
+
+// RegisterStrictConfigFlag registers the flag which validates the configuration file against its JSON Schema.
+func RegisterStrictConfigFlag(f *pflag.FlagSet) {
+	f.Bool(StrictConfigFlag, false, "Validate the configuration file against its JSON Schema and fail if it is invalid.")
+}
+
+// ConfigSchema returns the JSON Schema of the configuration file. It is derived from the fields and struct tags of
+// AuthContext: the json tag names the properties, fields without omitempty are required, and the description tag
+// documents them.
+func ConfigSchema() map[string]interface{} {
+	schema := typeSchema(reflect.TypeOf(AuthContext{}))
+	schema["$id"] = ConfigSchemaID
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "The configuration file of the Ory CLI, " + fileName
+	if version, ok := schema["properties"].(map[string]interface{})["version"].(map[string]interface{}); ok {
+		version["enum"] = []string{Version}
+	}
+	return schema
+}
+
+// ConfigSchemaJSON returns the JSON Schema of the configuration file as indented JSON with sorted keys.
+func ConfigSchemaJSON() ([]byte, error) {
+	out, err := json.MarshalIndent(ConfigSchema(), "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return append(out, '\n'), nil
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t.Implements(jsonSchemaDescriberType) {
+		return reflect.Zero(t).Interface().(jsonSchemaDescriber).JSONSchema()
+	}
+	if t == uuidType {
+		return map[string]interface{}{"type": "string", "format": "uuid", "pattern": uuidPattern}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			} else if name == "" {
+				name = f.Name
+			}
+
+			property := typeSchema(f.Type)
+			if description := f.Tag.Get("description"); description != "" {
+				property["description"] = description
+			}
+			properties[name] = property
+			if !strings.Contains(","+options+",", ",omitempty,") {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	return map[string]interface{}{}
+}
+
+var compiledConfigSchema struct {
+	once   sync.Once
+	schema *jsonschema.Schema
+	err    error
+}
+
+// validateConfig validates the contents of the configuration file against ConfigSchema. The error names the JSON
+// pointer of the first invalid value.
+func validateConfig(location string, contents []byte) error {
+	compiledConfigSchema.once.Do(func() {
+		raw, err := ConfigSchemaJSON()
+		if err != nil {
+			compiledConfigSchema.err = err
+			return
+		}
+		compiler := jsonschema.NewCompiler()
+		if err := compiler.AddResource(ConfigSchemaID, bytes.NewReader(raw)); err != nil {
+			compiledConfigSchema.err = errors.WithStack(err)
+			return
+		}
+		compiledConfigSchema.schema, compiledConfigSchema.err = compiler.Compile(context.Background(), ConfigSchemaID)
+	})
+	if compiledConfigSchema.err != nil {
+		return errors.Wrap(compiledConfigSchema.err, "unable to compile the JSON Schema of the configuration file")
+	}
+
+	err := compiledConfigSchema.schema.Validate(bytes.NewReader(contents))
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		if err != nil {
+			return NewValidationError(errors.Wrapf(err, "unable to validate the configuration file %s", location), nil)
+		}
+		return nil
+	}
+	// The leaf of the error explains what is wrong, but e.g. the errors of property names point to the name itself, so
+	// that the most specific pointer on the way is reported.
+	pointer := strings.TrimPrefix(validationErr.InstancePtr, "#")
+	for len(validationErr.Causes) > 0 {
+		validationErr = validationErr.Causes[0]
+		if p := strings.TrimPrefix(validationErr.InstancePtr, "#"); len(p) > len(pointer) {
+			pointer = p
+		}
+	}
+	if pointer == "" {
+		pointer = "/"
+	}
+	return NewValidationError(errors.Errorf("the configuration file %s is invalid at %s: %s", location, pointer, validationErr.Message), map[string]interface{}{
+		"file":    location,
+		"pointer": pointer,
+		"schema":  ConfigSchemaID,
+	})
+}
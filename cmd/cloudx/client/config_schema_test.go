@@ -0,0 +1,47 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSchemaIsUpToDate(t *testing.T) {
+	expected, err := ConfigSchemaJSON()
+	require.NoError(t, err)
+	actual, err := os.ReadFile("../../../spec/config.schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual), "the JSON Schema of the configuration file is outdated, run `go run . gen config-schema > spec/config.schema.json`")
+}
+
+func TestValidateConfig(t *testing.T) {
+	valid, err := json.Marshal(&AuthContext{
+		Version:         Version,
+		SessionToken:    "token",
+		SelectedProject: uuid.Must(uuid.NewV4()),
+		IdentityTraits:  AuthIdentity{ID: uuid.Must(uuid.NewV4()), Email: "dev@ory.sh"},
+		Endpoints:       Endpoints{ServicePermission: "http://127.0.0.1:4466"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, validateConfig("config.json", valid))
+
+	for _, tc := range []struct {
+		name, config, pointer string
+	}{
+		{name: "unknown version", config: `{"version":"v1","session_token":"","selected_project":"00000000-0000-0000-0000-000000000000","session_identity_traits":{"ID":"00000000-0000-0000-0000-000000000000","email":""}}`, pointer: "/version"},
+		{name: "invalid project", config: `{"version":"v0alpha0","session_token":"","selected_project":"my-project","session_identity_traits":{"ID":"00000000-0000-0000-0000-000000000000","email":""}}`, pointer: "/selected_project"},
+		{name: "unknown service", config: `{"version":"v0alpha0","session_token":"","selected_project":"00000000-0000-0000-0000-000000000000","session_identity_traits":{"ID":"00000000-0000-0000-0000-000000000000","email":""},"endpoints":{"kratos":"http://127.0.0.1:4433"}}`, pointer: "/endpoints"},
+		{name: "wrong type", config: `{"version":"v0alpha0","session_token":1,"selected_project":"00000000-0000-0000-0000-000000000000","session_identity_traits":{"ID":"00000000-0000-0000-0000-000000000000","email":""}}`, pointer: "/session_token"},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			err := validateConfig("config.json", []byte(tc.config))
+			require.Error(t, err)
+			assert.Equal(t, ExitValidation, ExitCode(err))
+			assert.Contains(t, err.Error(), "the configuration file config.json is invalid at "+tc.pointer+":")
+		})
+	}
+}
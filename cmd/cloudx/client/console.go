@@ -0,0 +1,46 @@
+package client
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ConsoleProjectURL returns the URL of the project with the given slug in the Ory Console.
+func ConsoleProjectURL(slug string) string {
+	return consolePageURL("projects", slug)
+}
+
+// ConsoleIdentityURL returns the URL of an identity of the project with the given slug in the Ory Console.
+func ConsoleIdentityURL(slug, id string) string {
+	return consolePageURL("projects", slug, "identities", id)
+}
+
+// ConsoleOAuth2ClientURL returns the URL of an OAuth2 client of the project with the given slug in the Ory Console.
+func ConsoleOAuth2ClientURL(slug, id string) string {
+	return consolePageURL("projects", slug, "oauth2", "clients", id)
+}
+
+func consolePageURL(segments ...string) string {
+	u := consoleURL()
+	u.RawQuery, u.Fragment = "", ""
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.TrimRight(u.String(), "/") + "/" + strings.Join(segments, "/")
+}
+
+// hyperlink renders link as a clickable OSC 8 hyperlink if w is a terminal which supports it, and as is otherwise.
+func hyperlink(w io.Writer, link string) string {
+	if !isTerminalWriter(w) || os.Getenv("TERM") == "dumb" {
+		return link
+	}
+	return "\x1b]8;;" + link + "\x1b\\" + link + "\x1b]8;;\x1b\\"
+}
+
+// PrintConsoleLink tells the user where to find a created resource in the Ory Console. The link is printed to stderr,
+// so that it never ends up in the output of the command, and not at all if --quiet is set.
+func (h *CommandHelper) PrintConsoleLink(link string) {
+	h.Log.Infof("View it in the Ory Console: %s", hyperlink(h.VerboseErrWriter, link))
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleURLs(t *testing.T) {
+	for _, tc := range []struct {
+		console  string
+		resource string
+		actual   func() string
+		expected string
+	}{
+		{resource: "project", actual: func() string { return ConsoleProjectURL("good-wright-t7kzy3vugf") }, expected: "https://console.ory.sh/projects/good-wright-t7kzy3vugf"},
+		{resource: "identity", actual: func() string {
+			return ConsoleIdentityURL("good-wright-t7kzy3vugf", "e9fd5ba2-7e5b-4f1c-b4c5-4d4ba3bbcb8e")
+		}, expected: "https://console.ory.sh/projects/good-wright-t7kzy3vugf/identities/e9fd5ba2-7e5b-4f1c-b4c5-4d4ba3bbcb8e"},
+		{resource: "oauth2 client", actual: func() string { return ConsoleOAuth2ClientURL("good-wright-t7kzy3vugf", "my client") }, expected: "https://console.ory.sh/projects/good-wright-t7kzy3vugf/oauth2/clients/my%20client"},
+		{console: "https://console.staging.ory.dev/", resource: "project", actual: func() string { return ConsoleProjectURL("good-wright-t7kzy3vugf") }, expected: "https://console.staging.ory.dev/projects/good-wright-t7kzy3vugf"},
+		{console: "http://localhost:3000/console?foo=bar", resource: "identity", actual: func() string { return ConsoleIdentityURL("slug", "id") }, expected: "http://localhost:3000/console/projects/slug/identities/id"},
+	} {
+		t.Run("resource="+tc.resource+"/console="+tc.console, func(t *testing.T) {
+			t.Setenv("ORY_CLOUD_CONSOLE_URL", tc.console)
+			assert.Equal(t, tc.expected, tc.actual())
+		})
+	}
+}
+
+func TestPrintConsoleLink(t *testing.T) {
+	var stderr bytes.Buffer
+	h := &CommandHelper{VerboseErrWriter: &stderr, Log: &Logger{out: &stderr, level: LogLevelInfo}}
+	h.PrintConsoleLink("https://console.ory.sh/projects/slug")
+	assert.Equal(t, "View it in the Ory Console: https://console.ory.sh/projects/slug\n", stderr.String(), "plain URL if stderr is not a terminal")
+}
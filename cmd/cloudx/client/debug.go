@@ -0,0 +1,267 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	DebugFlag = "debug"
+
+	// DebugBody is the value of the --debug flag which additionally logs request and response bodies.
+	DebugBody = "body"
+
+	redacted = "[REDACTED]"
+)
+
+type debugValue string
+
+func (d *debugValue) String() string {
+	return string(*d)
+}
+
+func (d *debugValue) Set(v string) error {
+	switch v {
+	case "true", "false", DebugBody:
+		*d = debugValue(v)
+		return nil
+	}
+	return fmt.Errorf("unknown debug level %q, expected true, false, or %s", v, DebugBody)
+}
+
+func (*debugValue) Type() string {
+	return "string"
+}
+
+// RegisterDebugFlag registers the flag which logs all HTTP requests and responses to stderr.
+func RegisterDebugFlag(f *pflag.FlagSet) {
+	v := debugValue("false")
+	f.Var(&v, DebugFlag, "Log HTTP requests and responses to stderr with credentials redacted. Use --debug=body to include the bodies.")
+	f.Lookup(DebugFlag).NoOptDefVal = "true"
+}
+
+// newDebugLog returns the debug log configured by the --debug flag or nil if the flag is not set. The log is written
+// to stderr even if --quiet is set.
+func newDebugLog(cmd *cobra.Command) *debugLog {
+	f := cmd.Flags().Lookup(DebugFlag)
+	if f == nil {
+		return nil
+	}
+	switch f.Value.String() {
+	case "true":
+		return &debugLog{out: cmd.ErrOrStderr()}
+	case DebugBody:
+		return &debugLog{out: cmd.ErrOrStderr(), bodies: true}
+	}
+	return nil
+}
+
+type debugLogKey struct{}
+
+// contextWithDebugLog makes all Ory Cloud API clients log their requests and responses to l.
+func contextWithDebugLog(ctx context.Context, l *debugLog) context.Context {
+	if l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, debugLogKey{}, l)
+}
+
+type debugLog struct {
+	sync.Mutex
+	out    io.Writer
+	bodies bool
+}
+
+// debugTransport logs requests and responses to the debug log of the request context, or to log if it is set. It
+// must be the innermost transport to see the headers set by the other transports. Only the timingTransport, which
+// measures the requests as they are sent, the gzipTransport, so that the logged bodies are not compressed, and the
+// networkTransport, which classifies connection errors, are below it.
+type debugTransport struct {
+	http.RoundTripper
+	log *debugLog
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := t.log
+	if cl, ok := req.Context().Value(debugLogKey{}).(*debugLog); ok {
+		l = cl
+	}
+	if l == nil {
+		return t.RoundTripper.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if l.bodies && req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	start := time.Now()
+	res, err := t.RoundTripper.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "--> %s %s\n", req.Method, redactURL(req.URL))
+	writeDebugHeaders(&b, req.Header)
+	if l.bodies {
+		writeDebugBody(&b, req.Header.Get("Content-Type"), reqBody)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(&b, "<-- %s %s failed after %s: %s\n", req.Method, redactURL(req.URL), duration, err)
+	} else {
+		_, _ = fmt.Fprintf(&b, "<-- %s %s %s (%s)\n", res.Status, req.Method, redactURL(req.URL), duration)
+		writeDebugHeaders(&b, res.Header)
+		if l.bodies && res.Body != nil {
+			resBody, readErr := io.ReadAll(res.Body)
+			_ = res.Body.Close()
+			res.Body = io.NopCloser(bytes.NewReader(resBody))
+			if readErr != nil {
+				err = errors.WithStack(readErr)
+			}
+			writeDebugBody(&b, res.Header.Get("Content-Type"), resBody)
+		}
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	_, _ = io.WriteString(l.out, b.String())
+	return res, err
+}
+
+// IsSensitiveKey returns true for header names, query parameters, and JSON keys which carry credentials.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"authorization", "cookie", "password", "secret", "token", "api-key", "api_key", "apikey", "private_key"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactURL(u *url.URL) string {
+	c := *u
+	if c.User != nil {
+		c.User = url.User(redacted)
+	}
+	if c.RawQuery != "" {
+		c.RawQuery = redactValues(c.Query()).Encode()
+	}
+	return c.String()
+}
+
+func redactValues(values url.Values) url.Values {
+	r := make(url.Values, len(values))
+	for k, v := range values {
+		if IsSensitiveKey(k) {
+			v = []string{redacted}
+		}
+		r[k] = v
+	}
+	return r
+}
+
+// redactHeader keeps the scheme of authorization headers, e.g. "Bearer [REDACTED]".
+func redactHeader(name, value string) string {
+	if !IsSensitiveKey(name) {
+		return value
+	}
+	if scheme, _, ok := strings.Cut(value, " "); ok && strings.HasSuffix(strings.ToLower(name), "authorization") {
+		return scheme + " " + redacted
+	}
+	return redacted
+}
+
+func writeDebugHeaders(w io.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range header[name] {
+			_, _ = fmt.Fprintf(w, "    %s: %s\n", name, redactHeader(name, v))
+		}
+	}
+}
+
+// writeDebugBody writes JSON and form bodies with all sensitive values redacted. Other bodies are omitted because
+// they can not be redacted reliably.
+func writeDebugBody(w io.Writer, contentType string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	var redactedBody string
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			redactedBody = fmt.Sprintf("[form body of %d bytes omitted because it could not be parsed]", len(body))
+			break
+		}
+		redactedBody = redactValues(values).Encode()
+	case json.Valid(body):
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			redactedBody = fmt.Sprintf("[JSON body of %d bytes omitted because it could not be parsed]", len(body))
+			break
+		}
+		out, err := json.MarshalIndent(redactJSON(v), "", "  ")
+		if err != nil {
+			redactedBody = fmt.Sprintf("[JSON body of %d bytes omitted because it could not be encoded]", len(body))
+			break
+		}
+		redactedBody = string(out)
+	default:
+		redactedBody = fmt.Sprintf("[non-JSON body of %d bytes omitted]", len(body))
+	}
+
+	for _, line := range strings.Split(redactedBody, "\n") {
+		_, _ = fmt.Fprintf(w, "    | %s\n", line)
+	}
+}
+
+// redactJSON replaces the values of all sensitive keys, including nested objects and arrays.
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			if IsSensitiveKey(k) {
+				r[k] = redacted
+				continue
+			}
+			r[k] = redactJSON(value)
+		}
+		return r
+	case []interface{}:
+		r := make([]interface{}, len(v))
+		for k, value := range v {
+			r[k] = redactJSON(value)
+		}
+		return r
+	}
+	return v
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugTransport(t *testing.T) {
+	const secret = "ory_st_very-secret-value"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(body), secret, "the request body must be sent unchanged")
+
+		http.SetCookie(w, &http.Cookie{Name: "ory_session", Value: secret})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"session_token":"` + secret + `","session":{"identity":{"traits":{"email":"jane@example.com"}}},"items":[{"client_secret":"` + secret + `"}]}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	send := func(t *testing.T, l *debugLog) string {
+		c := &http.Client{Transport: &bearerTokenTransporter{
+			RoundTripper: &debugTransport{RoundTripper: http.DefaultTransport},
+			bearerToken:  secret,
+		}}
+		req, err := http.NewRequestWithContext(contextWithDebugLog(context.Background(), l), http.MethodPost,
+			ts.URL+"/self-service/login?flow=abc&token="+secret, strings.NewReader(`{"identifier":"jane@example.com","password":"`+secret+`","traits":{"api_key":"`+secret+`"}}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Session-Token", secret)
+		req.Header.Set("Cookie", "ory_session="+secret)
+
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), secret, "the response body must be returned unchanged")
+
+		return l.out.(*bytes.Buffer).String()
+	}
+
+	t.Run("case=logs requests without bodies", func(t *testing.T) {
+		log := send(t, &debugLog{out: new(bytes.Buffer)})
+		assert.NotContains(t, log, secret)
+		assert.Contains(t, log, "--> POST "+ts.URL+"/self-service/login?flow=abc&token=%5BREDACTED%5D\n")
+		assert.Contains(t, log, "    Authorization: Bearer [REDACTED]\n")
+		assert.Contains(t, log, "    X-Session-Token: [REDACTED]\n")
+		assert.Contains(t, log, "    Cookie: [REDACTED]\n")
+		assert.Contains(t, log, "    Set-Cookie: [REDACTED]\n")
+		assert.Contains(t, log, "<-- 200 OK POST ")
+		assert.NotContains(t, log, "jane@example.com")
+	})
+
+	t.Run("case=logs redacted bodies", func(t *testing.T) {
+		log := send(t, &debugLog{out: new(bytes.Buffer), bodies: true})
+		assert.NotContains(t, log, secret)
+		assert.Contains(t, log, `    |   "identifier": "jane@example.com",`)
+		assert.Contains(t, log, `    |   "password": "[REDACTED]",`)
+		assert.Contains(t, log, `    |     "api_key": "[REDACTED]"`)
+		assert.Contains(t, log, `    |   "session_token": "[REDACTED]"`)
+		assert.Contains(t, log, `    |       "client_secret": "[REDACTED]"`)
+	})
+
+	t.Run("case=does not log without a debug log", func(t *testing.T) {
+		c := &http.Client{Transport: &debugTransport{RoundTripper: http.DefaultTransport}}
+		res, err := c.Post(ts.URL, "application/json", strings.NewReader(secret))
+		require.NoError(t, err)
+		_ = res.Body.Close()
+	})
+}
+
+func TestWriteDebugBody(t *testing.T) {
+	for _, tc := range []struct {
+		name, contentType, body, expected string
+	}{
+		{name: "form", contentType: "application/x-www-form-urlencoded", body: "password=secret&method=password", expected: "    | method=password&password=%5BREDACTED%5D\n"},
+		{name: "other", contentType: "text/plain", body: "secret", expected: "    | [non-JSON body of 6 bytes omitted]\n"},
+		{name: "empty", contentType: "application/json", body: "", expected: ""},
+		{name: "array", contentType: "application/json", body: `[{"token":"secret"}]`, expected: "    | [\n    |   {\n    |     \"token\": \"[REDACTED]\"\n    |   }\n    | ]\n"},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			writeDebugBody(&out, tc.contentType, []byte(tc.body))
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestRegisterDebugFlag(t *testing.T) {
+	newCmd := func(t *testing.T, args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		RegisterDebugFlag(cmd.Flags())
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	assert.Nil(t, newDebugLog(newCmd(t)))
+	assert.Nil(t, newDebugLog(&cobra.Command{}))
+	assert.False(t, newDebugLog(newCmd(t, "--debug")).bodies)
+	assert.True(t, newDebugLog(newCmd(t, "--debug=body")).bodies)
+	assert.Error(t, newCmd(t).ParseFlags([]string{"--debug=headers"}))
+}
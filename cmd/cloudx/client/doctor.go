@@ -0,0 +1,324 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
+)
+
+// DoctorWarned is the state of findings which do not fail Doctor, but may explain unexpected behavior.
+const DoctorWarned = "warn"
+
+// The clock skew up to which Doctor warns, and from which it fails. Sessions and tokens are validated against the
+// clocks of the servers, so that larger skews break signing in.
+const (
+	clockSkewWarning = 10 * time.Second
+	clockSkewFailure = time.Minute
+)
+
+// DoctorFinding is the result of one check of Doctor.
+type DoctorFinding struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Details string `json:"details"`
+	// Hint explains how to fix findings which did not pass.
+	Hint string `json:"hint,omitempty"`
+	// Err is the error of failed checks.
+	Err error `json:"-"`
+}
+
+// doctorEnvVars are the environment variables which change the behavior of the CLI.
+var doctorEnvVars = []string{
+	osEnvVar,
+	SessionTokenEnv,
+	consoleURLEnv,
+	EndpointsEnv,
+	ProxyEnv,
+	"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy",
+	LogLevelEnv,
+	NoPluginsEnv,
+	ForceInteractiveEnv,
+	"NO_COLOR",
+	"PAGER",
+}
+
+// doctorOverrides are environment variables which are also set by flags, and which of them wins.
+var doctorOverrides = []struct {
+	env, flag string
+	envWins   bool
+}{
+	{env: osEnvVar, flag: ConfigFlag, envWins: true},
+	{env: SessionTokenEnv, flag: SessionTokenFlag},
+	{env: EndpointsEnv, flag: EndpointsFlag},
+	{env: ProxyEnv, flag: ProxyFlag},
+	{env: LogLevelEnv, flag: LogLevelFlag},
+}
+
+// Doctor diagnoses the environment of the CLI: the configuration file, the environment variables in effect, the
+// terminal, the clock, and the connectivity to Ory Cloud and the self-hosted endpoints. It only reads, never prompts,
+// and works without a session, but skips the checks which need one then.
+func (h *CommandHelper) Doctor(cmd *cobra.Command) []*DoctorFinding {
+	findings := []*DoctorFinding{
+		h.doctorConfig(),
+		h.doctorCredentials(),
+		doctorEnvironment(cmd),
+		h.doctorTerminal(cmd),
+	}
+
+	ctx := contextWithMaxRetries(h.Ctx, 0)
+	if !h.timeoutSet {
+		ctx = contextWithTimeout(ctx, pingTimeout)
+	}
+	var clock, endpoints *DoctorFinding
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		clock = h.doctorClock(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		endpoints = h.doctorEndpoints(ctx)
+	}()
+	checks := h.Ping(cmd)
+	wg.Wait()
+
+	findings = append(findings, clock)
+	for _, c := range checks {
+		f := &DoctorFinding{Name: c.Name, Status: c.Status, Details: c.Details, Err: c.Err}
+		if c.Status == PingFailed {
+			f.Hint = pingHints[c.Name]
+		}
+		findings = append(findings, f)
+	}
+	return append(findings, endpoints)
+}
+
+// pingHints are the remediation hints of failed Ping checks.
+var pingHints = map[string]string{
+	"console":        "check the network connection and firewall, and set --" + ProxyFlag + " if a proxy is required",
+	"authentication": "run `ory auth` to sign in again",
+	"project API":    "check that --" + projectFlag + " or the selected project exists, see `ory list projects`",
+	"proxy":          "fix the URL of --" + ProxyFlag + " or the " + ProxyEnv + " environment variable",
+}
+
+// doctorConfig checks the configuration file: that it can be read and decoded, its version, and its schema.
+func (h *CommandHelper) doctorConfig() *DoctorFinding {
+	f := &DoctorFinding{Name: "configuration", Status: PingPassed}
+	contents, err := os.ReadFile(h.ConfigLocation)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		f.Status, f.Details = DoctorWarned, fmt.Sprintf("%s does not exist", h.ConfigLocation)
+		f.Hint = "run `ory auth` to sign in, which creates it"
+		return f
+	case err != nil:
+		f.Status, f.Err, f.Details = PingFailed, errors.WithStack(err), fmt.Sprintf("unable to read %s: %s", h.ConfigLocation, err)
+		f.Hint = "check the permissions of the file, or set --" + ConfigFlag + " to another location"
+		return f
+	case !json.Valid(contents):
+		f.Status, f.Details = PingFailed, fmt.Sprintf("%s is not valid JSON", h.ConfigLocation)
+		f.Hint = "delete the file and run `ory auth` to sign in again"
+		return f
+	}
+
+	if version := gjson.GetBytes(contents, "version").String(); version != Version {
+		f.Status, f.Details = DoctorWarned, fmt.Sprintf("%s has version %q, this version of the CLI writes %q", h.ConfigLocation, version, Version)
+		f.Hint = "run `ory auth` to sign in again, which rewrites the file"
+		return f
+	}
+	if err := validateConfig(h.ConfigLocation, contents); err != nil {
+		f.Status, f.Details = DoctorWarned, err.Error()
+		f.Hint = "fix the file or run `ory auth` to sign in again, commands run with --" + StrictConfigFlag + " fail otherwise"
+		return f
+	}
+	f.Details = fmt.Sprintf("%s, version %s", h.ConfigLocation, Version)
+	return f
+}
+
+// doctorCredentials checks how the session token is stored. The CLI does not use the keyring of the operating system,
+// the token is stored in the configuration file, which only the user may read.
+func (h *CommandHelper) doctorCredentials() *DoctorFinding {
+	f := &DoctorFinding{Name: "credentials", Status: PingPassed}
+	contents, err := os.ReadFile(h.ConfigLocation)
+	if err != nil || gjson.GetBytes(contents, "session_token").String() == "" {
+		f.Status, f.Details = PingSkipped, "not signed in, no session token is stored"
+		return f
+	}
+	info, err := os.Stat(h.ConfigLocation)
+	if err != nil {
+		f.Status, f.Err, f.Details = PingFailed, errors.WithStack(err), err.Error()
+		return f
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		f.Status, f.Details = DoctorWarned, fmt.Sprintf("the session token is stored in %s, which other users may read (mode %s)", h.ConfigLocation, info.Mode().Perm())
+		f.Hint = "run `chmod 600 " + h.ConfigLocation + "`"
+		return f
+	}
+	f.Details = fmt.Sprintf("the session token is stored in %s, no keyring is used", h.ConfigLocation)
+	return f
+}
+
+// doctorEnvironment lists the environment variables in effect, and warns about those which conflict with flags.
+func doctorEnvironment(cmd *cobra.Command) *DoctorFinding {
+	f := &DoctorFinding{Name: "environment", Status: PingPassed, Details: "no environment variables of the CLI are set"}
+	var set []string
+	for _, name := range doctorEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			if name == SessionTokenEnv {
+				value = "<redacted>"
+			} else if u, err := url.Parse(value); err == nil && u.User != nil {
+				value = u.Redacted()
+			}
+			set = append(set, name+"="+value)
+		}
+	}
+	if len(set) > 0 {
+		f.Details = strings.Join(set, ", ")
+	}
+
+	var conflicts []string
+	for _, o := range doctorOverrides {
+		flag := cmd.Flags().Lookup(o.flag)
+		if _, ok := os.LookupEnv(o.env); !ok || flag == nil || !flag.Changed {
+			continue
+		}
+		if o.envWins {
+			conflicts = append(conflicts, fmt.Sprintf("%s overrides --%s", o.env, o.flag))
+		} else {
+			conflicts = append(conflicts, fmt.Sprintf("--%s overrides %s", o.flag, o.env))
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		f.Status = DoctorWarned
+		f.Details += "; " + strings.Join(conflicts, ", ")
+		f.Hint = "unset the environment variables or the flags which should not apply"
+	}
+	return f
+}
+
+// doctorTerminal reports which standard streams are terminals, and why prompts are disabled, if they are.
+func (h *CommandHelper) doctorTerminal(cmd *cobra.Command) *DoctorFinding {
+	stream := func(name string, v interface{}) string {
+		w, ok := v.(io.Writer)
+		if ok && isTerminalWriter(w) {
+			return name + " is a terminal"
+		}
+		return name + " is not a terminal"
+	}
+	term := os.Getenv("TERM")
+	if term == "" {
+		term = "unset"
+	}
+
+	f := &DoctorFinding{Name: "terminal", Status: PingPassed}
+	f.Details = strings.Join([]string{
+		stream("stdin", cmd.InOrStdin()),
+		stream("stdout", cmd.OutOrStdout()),
+		stream("stderr", cmd.ErrOrStderr()),
+		"TERM " + term,
+	}, ", ")
+	if h.NonInteractive {
+		f.Status = DoctorWarned
+		f.Details += "; prompts are disabled because " + h.promptsDisabled
+		f.Hint = fmt.Sprintf("pass all inputs as flags, e.g. --%s and --%s, or set %s=true", projectFlag, yesFlag, ForceInteractiveEnv)
+	}
+	return f
+}
+
+// doctorClock compares the system time with the Date header of the Ory Console. The request is assumed to take as
+// long in both directions.
+func (h *CommandHelper) doctorClock(ctx context.Context) *DoctorFinding {
+	f := &DoctorFinding{Name: "clock"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, makeCloudConsoleURL("api")+"/health/alive", nil)
+	if err != nil {
+		f.Status, f.Err, f.Details = PingFailed, errors.WithStack(err), err.Error()
+		return f
+	}
+	start := time.Now()
+	res, err := h.client.httpClient("").Do(req)
+	if err != nil {
+		f.Status, f.Details = PingSkipped, "unable to reach the Ory Console to compare the time, see the console check"
+		return f
+	}
+	_ = res.Body.Close()
+	local := start.Add(time.Since(start) / 2)
+
+	server, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		f.Status, f.Details = PingSkipped, fmt.Sprintf("%s did not respond with a valid Date header", req.URL.Host)
+		return f
+	}
+	// The Date header has a resolution of one second.
+	skew := local.Sub(server).Round(time.Second)
+	if skew < 0 && skew > -time.Second {
+		skew = 0
+	}
+	abs := skew
+	if abs < 0 {
+		abs = -abs
+	}
+
+	f.Status, f.Details = PingPassed, fmt.Sprintf("the system time differs by %s from %s", skew, req.URL.Host)
+	switch {
+	case abs >= clockSkewFailure:
+		f.Status, f.Err = PingFailed, errors.Errorf("the system clock is off by %s", skew)
+	case abs >= clockSkewWarning:
+		f.Status = DoctorWarned
+	}
+	if f.Status != PingPassed {
+		f.Hint = "synchronize the system clock, e.g. by enabling NTP, sessions may be rejected otherwise"
+	}
+	return f
+}
+
+// doctorEndpoints checks that the self-hosted endpoints are reachable.
+func (h *CommandHelper) doctorEndpoints(ctx context.Context) *DoctorFinding {
+	f := &DoctorFinding{Name: "endpoints"}
+	e, err := h.Endpoints()
+	if err != nil {
+		f.Status, f.Err, f.Details = PingFailed, err, err.Error()
+		f.Hint = "fix --" + EndpointsFlag + ", the " + EndpointsEnv + " environment variable, or the endpoints in the configuration file"
+		return f
+	} else if len(e) == 0 {
+		f.Status, f.Details = PingSkipped, "no self-hosted endpoints are set"
+		return f
+	}
+
+	services := make([]string, 0, len(e))
+	for s := range e {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+
+	var details []string
+	for _, s := range services {
+		c := &PingCheck{}
+		if err := h.probeURL(ctx, c, strings.TrimSuffix(e[s], "/")+"/health/alive"); err != nil {
+			f.Status, f.Err = PingFailed, withNetworkError(err)
+			details = append(details, fmt.Sprintf("%s: %s", s, f.Err))
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s: %s", s, c.Details))
+	}
+	f.Details = strings.Join(details, ", ")
+	if f.Status == PingFailed {
+		f.Hint = "check that the self-hosted services are running and reachable from this machine"
+	} else {
+		f.Status = PingPassed
+	}
+	return f
+}
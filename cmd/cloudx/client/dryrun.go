@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	DryRunFlag = "dry-run"
+
+	// DryRunAnnotation marks commands which support the --dry-run flag, see MarkDryRunCapable.
+	DryRunAnnotation = "dry-run"
+)
+
+// ErrDryRun is matched by all DryRunErrors.
+var ErrDryRun = errors.New("the request was not sent because of the --dry-run flag")
+
+// DryRunError is returned by the Ory Cloud API clients instead of sending a request which changes resources while the
+// --dry-run flag is set. Commands which support dry runs report it as what they would have done.
+type DryRunError struct {
+	Method      string
+	URL         string
+	ContentType string
+	Body        []byte
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("%s %s was not sent because of the --%s flag", e.Method, e.URL, DryRunFlag)
+}
+
+func (e *DryRunError) Is(target error) bool {
+	return target == ErrDryRun
+}
+
+// Report describes the request which would have been sent. Credentials in the URL and body are redacted.
+func (e *DryRunError) Report(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Dry run: would have sent %s %s\n", e.Method, e.URL)
+	writeDebugBody(w, e.ContentType, e.Body)
+}
+
+// RegisterDryRunFlag registers the flag which previews commands without changing any resources.
+func RegisterDryRunFlag(f *pflag.FlagSet) {
+	f.Bool(DryRunFlag, false, "Show what the command would change without changing anything. Only supported by commands which change resources.")
+}
+
+// IsDryRun returns true if the --dry-run flag is set.
+func IsDryRun(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup(DryRunFlag)
+	return f != nil && f.Value.String() == "true"
+}
+
+// MarkDryRunCapable declares that the command supports the --dry-run flag. Commands whose API supports dry runs route
+// to it when CommandHelper.DryRun is set. All other commands rely on the API clients, which refuse to send requests
+// changing resources in dry runs and return a DryRunError instead.
+func MarkDryRunCapable(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[DryRunAnnotation] = "true"
+	return cmd
+}
+
+// EnableDryRunFlag applies the --dry-run flag to cmd and all its sub commands. Commands marked with MarkDryRunCapable
+// report DryRunErrors as what they would have done, while all other commands reject the flag instead of ignoring it.
+func EnableDryRunFlag(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableDryRunFlag(c)
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+	run := cmd.RunE
+	if cmd.Annotations[DryRunAnnotation] == "" {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			if IsDryRun(cmd) {
+				return NewValidationError(errors.Errorf("%q does not support the --%s flag because it does not change resources or can not preview its changes", cmd.CommandPath(), DryRunFlag), nil)
+			}
+			return run(cmd, args)
+		}
+		return
+	}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := run(cmd, args)
+		var dryRun *DryRunError
+		if !IsDryRun(cmd) || !errors.As(err, &dryRun) {
+			return err
+		}
+		dryRun.Report(cmd.ErrOrStderr())
+		return nil
+	}
+}
+
+// dryRunTransport refuses to send requests with unsafe methods, i.e. requests which change resources.
+type dryRunTransport struct {
+	http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.RoundTripper.RoundTrip(req)
+	}
+
+	e := &DryRunError{Method: req.Method, URL: redactURL(req.URL), ContentType: req.Header.Get("Content-Type")}
+	if req.Body != nil && req.Body != http.NoBody {
+		var b bytes.Buffer
+		if _, err := io.Copy(&b, req.Body); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		_ = req.Body.Close()
+		e.Body = b.Bytes()
+	}
+	return nil, e
+}
+
+// withDryRun wraps the transport with a dryRunTransport if dryRun is true.
+func withDryRun(rt http.RoundTripper, dryRun bool) http.RoundTripper {
+	if !dryRun {
+		return rt
+	}
+	return &dryRunTransport{RoundTripper: rt}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunTransport(t *testing.T) {
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(ts.Close)
+	c := &http.Client{Transport: withDryRun(http.DefaultTransport, true)}
+
+	res, err := c.Get(ts.URL)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+
+	_, err = c.Post(ts.URL+"/admin/identities?token=secret", "application/json", strings.NewReader(`{"traits":{"email":"jane@example.com"},"password":"secret"}`))
+	require.ErrorIs(t, err, ErrDryRun)
+	var dryRun *DryRunError
+	require.ErrorAs(t, err, &dryRun)
+	assert.Equal(t, []string{http.MethodGet}, received)
+
+	var report bytes.Buffer
+	dryRun.Report(&report)
+	assert.Equal(t, `Dry run: would have sent POST `+ts.URL+`/admin/identities?token=%5BREDACTED%5D
+    | {
+    |   "password": "[REDACTED]",
+    |   "traits": {
+    |     "email": "jane@example.com"
+    |   }
+    | }
+`, report.String())
+}
+
+func TestEnableDryRunFlag(t *testing.T) {
+	newRoot := func() *cobra.Command {
+		root := &cobra.Command{Use: "ory"}
+		RegisterDryRunFlag(root.PersistentFlags())
+		root.AddCommand(&cobra.Command{
+			Use:  "get",
+			RunE: func(*cobra.Command, []string) error { return nil },
+		})
+		root.AddCommand(MarkDryRunCapable(&cobra.Command{
+			Use: "delete",
+			RunE: func(*cobra.Command, []string) error {
+				return &DryRunError{Method: http.MethodDelete, URL: "https://example.projects.oryapis.com/admin/identities/1"}
+			},
+		}))
+		EnableDryRunFlag(root)
+		return root
+	}
+	exec := func(args ...string) (string, error) {
+		root := newRoot()
+		var stderr bytes.Buffer
+		root.SetErr(&stderr)
+		root.SetOut(new(bytes.Buffer))
+		root.SetArgs(args)
+		err := root.Execute()
+		return stderr.String(), err
+	}
+
+	t.Run("case=rejects the flag if the command does not support it", func(t *testing.T) {
+		_, err := exec("get", "--dry-run")
+		require.Error(t, err)
+		assert.Equal(t, ExitValidation, ExitCode(err))
+		assert.Contains(t, err.Error(), `"ory get" does not support the --dry-run flag`)
+
+		_, err = exec("get")
+		assert.NoError(t, err)
+	})
+
+	t.Run("case=reports what the command would have done", func(t *testing.T) {
+		stderr, err := exec("delete", "--dry-run")
+		require.NoError(t, err)
+		assert.Equal(t, "Dry run: would have sent DELETE https://example.projects.oryapis.com/admin/identities/1\n", stderr)
+	})
+
+	t.Run("case=does not hide the error without the flag", func(t *testing.T) {
+		_, err := exec("delete")
+		assert.ErrorIs(t, err, ErrDryRun)
+	})
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/x/flagx"
+)
+
+const (
+	endUserTokenStdinFlag = "token-stdin"
+	endUserCookieFlag     = "cookie"
+)
+
+// RegisterEndUserSessionFlags registers the flags used to pass the session of an end user of a project. This session
+// is unrelated to the Ory Console session stored in the CLI configuration.
+func RegisterEndUserSessionFlags(flags *pflag.FlagSet) {
+	flags.Bool(endUserTokenStdinFlag, false, "Read the end-user session token from stdin.")
+	flags.String(endUserCookieFlag, "", "The end-user session cookie header, e.g. ory_session_...=...")
+}
+
+// EndUserSessionHeader returns the headers to authenticate with the end-user session passed using the flags registered
+// by RegisterEndUserSessionFlags. It returns false if no session was passed.
+func EndUserSessionHeader(cmd *cobra.Command) (http.Header, bool, error) {
+	fromStdin, cookie := flagx.MustGetBool(cmd, endUserTokenStdinFlag), flagx.MustGetString(cmd, endUserCookieFlag)
+	switch {
+	case fromStdin && cookie != "":
+		return nil, false, errors.Errorf("only one of --%s and --%s can be used", endUserTokenStdinFlag, endUserCookieFlag)
+	case cookie != "":
+		return http.Header{"Cookie": {strings.TrimPrefix(cookie, "Cookie: ")}}, true, nil
+	case fromStdin:
+		token, err := ReadInputFile(cmd, StdinFile)
+		if err != nil {
+			return nil, false, err
+		}
+		if t := strings.TrimSpace(string(token)); t != "" {
+			return http.Header{"X-Session-Token": {t}}, true, nil
+		}
+		return nil, false, errors.New("the session token read from stdin is empty")
+	}
+	return http.Header{}, false, nil
+}
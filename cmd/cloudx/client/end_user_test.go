@@ -0,0 +1,41 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndUserSessionHeader(t *testing.T) {
+	newCmd := func(stdin string, args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		RegisterEndUserSessionFlags(cmd.Flags())
+		cmd.SetIn(strings.NewReader(stdin))
+		require.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	header, ok, err := EndUserSessionHeader(newCmd("  my-token\n", "--"+endUserTokenStdinFlag))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, http.Header{"X-Session-Token": {"my-token"}}, header)
+
+	header, ok, err = EndUserSessionHeader(newCmd("", "--"+endUserCookieFlag, "Cookie: ory_session_x=abc"))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, http.Header{"Cookie": {"ory_session_x=abc"}}, header)
+
+	_, _, err = EndUserSessionHeader(newCmd("\n", "--"+endUserTokenStdinFlag))
+	assert.ErrorContains(t, err, "empty")
+
+	_, _, err = EndUserSessionHeader(newCmd("my-token", "--"+endUserTokenStdinFlag, "--"+endUserCookieFlag, "a=b"))
+	assert.ErrorContains(t, err, "only one of")
+
+	_, ok, err = EndUserSessionHeader(newCmd(""))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"encoding/json"
+	stderrs "errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The services of an Ory project. Each of them can be self-hosted, see RegisterEndpointsFlag.
+const (
+	ServiceIdentity   = "identity"
+	ServicePermission = "permission"
+	ServiceOAuth2     = "oauth2"
+)
+
+const (
+	EndpointsFlag = "endpoints"
+	// EndpointsEnv sets the endpoints file if the --endpoints flag is not set.
+	EndpointsEnv = "ORY_ENDPOINTS"
+)
+
+// Services lists the services which can be self-hosted.
+var Services = []string{ServiceIdentity, ServicePermission, ServiceOAuth2}
+
+// ErrSelfHosted is returned by the commands which manage Ory Cloud projects if all services are self-hosted.
+var ErrSelfHosted = stderrs.New("projects only exist in Ory Cloud, but all services are self-hosted (see --" + EndpointsFlag + ")")
+
+// Endpoints maps services to the base URLs of their self-hosted deployments. Services without an endpoint are used
+// through the Ory Cloud project, so that e.g. the identity service of a project can be used together with a
+// self-hosted permission service.
+type Endpoints map[string]string
+
+// JSONSchema describes the endpoints in the JSON Schema of the configuration file.
+func (Endpoints) JSONSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":                 "object",
+		"propertyNames":        map[string]interface{}{"enum": Services},
+		"additionalProperties": map[string]interface{}{"type": "string", "format": "uri"},
+	}
+}
+
+// RegisterEndpointsFlag registers the flag which sets the endpoints of self-hosted services.
+func RegisterEndpointsFlag(f *pflag.FlagSet) {
+	f.String(EndpointsFlag, "", fmt.Sprintf("A YAML or JSON file mapping services (%s) to the URLs of self-hosted deployments, e.g. 'permission: http://127.0.0.1:4466'. Defaults to the %s environment variable, or the endpoints stored in the configuration file.", strings.Join(Services, ", "), EndpointsEnv))
+}
+
+// ReadEndpoints reads and validates the endpoints file at source.
+func ReadEndpoints(cmd *cobra.Command, source string) (Endpoints, error) {
+	raw, err := readConfigFile(cmd, source)
+	if err != nil {
+		return nil, err
+	}
+	var e Endpoints
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, NewValidationError(errors.Errorf("the endpoints file %s must map services to URLs: %s", displayFileName(source), err), nil)
+	}
+	return e, e.validate(displayFileName(source))
+}
+
+// readEndpointsFlag reads the endpoints file set by the --endpoints flag or the ORY_ENDPOINTS environment variable.
+// It returns nil if neither is set.
+func readEndpointsFlag(cmd *cobra.Command) (Endpoints, error) {
+	var source string
+	if f := cmd.Flags().Lookup(EndpointsFlag); f != nil {
+		source = f.Value.String()
+	}
+	if source == "" {
+		source = os.Getenv(EndpointsEnv)
+	}
+	if source == "" {
+		return nil, nil
+	}
+	return ReadEndpoints(cmd, source)
+}
+
+func (e Endpoints) validate(source string) error {
+	for service, endpoint := range e {
+		if !isService(service) {
+			return NewValidationError(errors.Errorf("unknown service %q in %s, expected one of: %s", service, source, strings.Join(Services, ", ")), nil)
+		}
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return NewValidationError(errors.Errorf("the endpoint of the %s service in %s must be an HTTP(S) URL but got: %s", service, source, endpoint), nil)
+		}
+	}
+	return nil
+}
+
+func isService(s string) bool {
+	for _, service := range Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// SelfHosted returns true if all services are self-hosted, i.e. no Ory Cloud project is used.
+func (e Endpoints) SelfHosted() bool {
+	for _, s := range Services {
+		if e[s] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// String lists the endpoints in the format "service=URL" sorted by service.
+func (e Endpoints) String() string {
+	var pairs []string
+	for service, endpoint := range e {
+		pairs = append(pairs, service+"="+endpoint)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// Endpoints returns the endpoints of the self-hosted services set by the --endpoints flag or the ORY_ENDPOINTS
+// environment variable, or stored in the configuration file.
+func (h *CommandHelper) Endpoints() (Endpoints, error) {
+	if h.endpoints != nil {
+		return h.endpoints, nil
+	}
+
+	c, err := h.readConfig()
+	if errors.Is(err, ErrNoConfig) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := c.Endpoints.validate(h.ConfigLocation); err != nil {
+		return nil, err
+	}
+	h.endpoints = c.Endpoints
+	return c.Endpoints, nil
+}
+
+// requireCloud returns ErrSelfHosted if all services are self-hosted.
+func (h *CommandHelper) requireCloud() error {
+	e, err := h.Endpoints()
+	if err != nil {
+		return err
+	} else if e.SelfHosted() {
+		return errors.WithStack(ErrSelfHosted)
+	}
+	return nil
+}
+
+// NewServiceAPI returns a ProjectAPI for the service. If the service is self-hosted, it talks to the self-hosted
+// deployment without credentials. Otherwise, it is the API of the project, see NewProjectAPI.
+func (h *CommandHelper) NewServiceAPI(service string) (*ProjectAPI, error) {
+	e, err := h.Endpoints()
+	if err != nil {
+		return nil, err
+	}
+	if endpoint := e[service]; endpoint != "" {
+		h.Log.Debugf("Using the self-hosted %s service at %s", service, endpoint)
+		return &ProjectAPI{
+			URL:      endpoint,
+			Client:   h.client.httpClient(""),
+			Log:      h.Log,
+			debugLog: h.debugLog,
+			client:   h.client,
+		}, nil
+	}
+	return h.NewProjectAPI()
+}
+
+// SetEndpoints stores the endpoints in the configuration file. Empty endpoints remove them.
+func (h *CommandHelper) SetEndpoints(e Endpoints) error {
+	c, err := h.readConfig()
+	if err != nil && !errors.Is(err, ErrNoConfig) {
+		return err
+	}
+	c.Endpoints = e
+	h.endpoints = e
+	return h.WriteConfig(c)
+}
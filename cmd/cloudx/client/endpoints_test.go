@@ -0,0 +1,51 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEndpoints(t *testing.T) {
+	write := func(t *testing.T, contents string) string {
+		file := filepath.Join(t.TempDir(), "ory-endpoints.yaml")
+		require.NoError(t, os.WriteFile(file, []byte(contents), 0600))
+		return file
+	}
+
+	t.Run("case=reads the services", func(t *testing.T) {
+		e, err := ReadEndpoints(&cobra.Command{}, write(t, "permission: http://127.0.0.1:4466\nidentity: https://kratos.example.com/admin\n"))
+		require.NoError(t, err)
+		assert.Equal(t, Endpoints{ServicePermission: "http://127.0.0.1:4466", ServiceIdentity: "https://kratos.example.com/admin"}, e)
+		assert.Equal(t, "identity=https://kratos.example.com/admin, permission=http://127.0.0.1:4466", e.String())
+		assert.False(t, e.SelfHosted(), "the oauth2 service is used through Ory Cloud")
+
+		e[ServiceOAuth2] = "http://127.0.0.1:4445"
+		assert.True(t, e.SelfHosted())
+	})
+
+	t.Run("case=rejects unknown services", func(t *testing.T) {
+		_, err := ReadEndpoints(&cobra.Command{}, write(t, "kratos: http://127.0.0.1:4434\n"))
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.ErrorContains(t, err, `unknown service "kratos"`)
+	})
+
+	t.Run("case=rejects invalid URLs", func(t *testing.T) {
+		for _, endpoint := range []string{"127.0.0.1:4466", "ftp://127.0.0.1", "http://"} {
+			_, err := ReadEndpoints(&cobra.Command{}, write(t, "permission: "+endpoint+"\n"))
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr, endpoint)
+			assert.ErrorContains(t, err, "must be an HTTP(S) URL", endpoint)
+		}
+	})
+
+	t.Run("case=rejects other documents", func(t *testing.T) {
+		_, err := ReadEndpoints(&cobra.Command{}, write(t, "- http://127.0.0.1:4466\n"))
+		assert.ErrorContains(t, err, "must map services to URLs")
+	})
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"github.com/gofrs/uuid/v3"
+
+	cloud "github.com/ory/client-go"
+)
+
+// SessionProject returns the configuration of the signed in user and the project set by --project, or the selected
+// project. The project is nil if none is selected. Like RequireSession, it never prompts the user to sign in.
+func (h *CommandHelper) SessionProject() (*AuthContext, *cloud.Project, error) {
+	ac, err := h.RequireSession()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id := ac.SelectedProject.String()
+	if h.Project != "" {
+		if id, err = h.ResolveProjectID(h.Project); err != nil {
+			return nil, nil, err
+		}
+	}
+	if id == uuid.Nil.String() {
+		return ac, nil, nil
+	}
+
+	p, err := h.GetProject(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ac, p, nil
+}
+
+// ProjectAPIURL returns the URL of the API of the project with the given slug.
+func ProjectAPIURL(slug string) string {
+	return makeCloudConsoleURL(slug + ".projects")
+}
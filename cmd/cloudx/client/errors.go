@@ -0,0 +1,261 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrs "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/x/cmdx"
+)
+
+// The error codes printed in machine readable formats. Scripts depend on them, so never change existing codes.
+const (
+	ErrorCodeUnknown             = "unknown"
+	ErrorCodeNotAuthenticated    = "not_authenticated"
+	ErrorCodeSessionExpired      = "session_expired"
+	ErrorCodeInteractionRequired = "interaction_required"
+	ErrorCodeAborted             = "aborted"
+	ErrorCodeProjectNotSelected  = "project_not_selected"
+	ErrorCodeProjectNotFound     = "project_not_found"
+	ErrorCodeNotFound            = "not_found"
+	ErrorCodeValidationFailed    = "validation_failed"
+	ErrorCodePermissionDenied    = "permission_denied"
+	ErrorCodeConflict            = "conflict"
+	ErrorCodeRateLimited         = "rate_limited"
+	ErrorCodeServerError         = "server_error"
+	ErrorCodeNetworkError        = "network_error"
+	ErrorCodeInterrupted         = "interrupted"
+	ErrorCodeSelfHosted          = "self_hosted"
+)
+
+var (
+	ErrNoProjectSelected = stderrs.New("no project selected! Please use the flag --" + projectFlag + " to specify one")
+	ErrProjectNotFound   = stderrs.New("project not found")
+	// ErrNotFound marks errors of resources other than projects which do not exist.
+	ErrNotFound = stderrs.New("not found")
+)
+
+// ValidationError is returned if the input of a command, e.g. a flag or a file, is invalid.
+type ValidationError struct {
+	err error
+	// Details, if set, describes the validation failures in a machine readable way.
+	Details interface{}
+}
+
+// NewValidationError marks err as a validation error.
+func NewValidationError(err error, details interface{}) error {
+	return errors.WithStack(&ValidationError{err: err, Details: details})
+}
+
+func (e *ValidationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+type (
+	// ErrorEnvelope is printed to stderr if a command fails and a machine readable format is used.
+	ErrorEnvelope struct {
+		Error ErrorDetails `json:"error"`
+	}
+	ErrorDetails struct {
+		Code      string      `json:"code"`
+		Message   string      `json:"message"`
+		Details   interface{} `json:"details,omitempty"`
+		RequestID string      `json:"request_id,omitempty"`
+		TraceID   string      `json:"trace_id,omitempty"`
+	}
+
+	// openAPIError is implemented by the errors of all generated Ory SDKs.
+	openAPIError interface {
+		error
+		Body() []byte
+	}
+)
+
+// NewErrorEnvelope maps err onto one of the stable error codes.
+func NewErrorEnvelope(err error) *ErrorEnvelope {
+	e := ErrorDetails{Code: ErrorCodeUnknown, Message: err.Error()}
+
+	var (
+		validationErr *ValidationError
+		promptErr     *PromptDisabledError
+		apiErr        *APIError
+		sdkErr        openAPIError
+		netErr        net.Error
+		idErr         *RequestIDError
+	)
+	switch {
+	case errors.Is(err, ErrNoConfig), errors.Is(err, ErrNoConfigQuiet), errors.Is(err, ErrNotSignedIn):
+		e.Code = ErrorCodeNotAuthenticated
+	case errors.Is(err, ErrSessionExpired):
+		e.Code = ErrorCodeSessionExpired
+	case errors.As(err, &promptErr):
+		e.Code = ErrorCodeInteractionRequired
+	case errors.Is(err, ErrAborted):
+		e.Code = ErrorCodeAborted
+	case errors.Is(err, ErrNoProjectSelected):
+		e.Code = ErrorCodeProjectNotSelected
+	case errors.Is(err, ErrSelfHosted):
+		e.Code = ErrorCodeSelfHosted
+	case errors.Is(err, ErrProjectNotFound):
+		e.Code = ErrorCodeProjectNotFound
+	case errors.Is(err, ErrNotFound):
+		e.Code = ErrorCodeNotFound
+	case errors.As(err, &validationErr):
+		e.Code = ErrorCodeValidationFailed
+		e.Details = validationErr.Details
+	case errors.As(err, &apiErr):
+		e.Code = errorCodeFromResponse(apiErr.StatusCode, apiErr.Body)
+		e.Details, e.RequestID = errorDetailsFromBody(apiErr.Body)
+	case errors.As(err, &sdkErr):
+		e.Code = errorCodeFromResponse(statusCodeFromSDKError(sdkErr), sdkErr.Body())
+		e.Details, e.RequestID = errorDetailsFromBody(sdkErr.Body())
+	case errors.Is(err, context.Canceled):
+		// Canceled requests are net.Errors as well.
+		e.Code = ErrorCodeInterrupted
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		e.Code = ErrorCodeNetworkError
+	}
+
+	if errors.As(err, &idErr) {
+		if e.RequestID == "" {
+			e.RequestID = idErr.ID
+		}
+		e.TraceID = idErr.TraceID
+	}
+
+	return &ErrorEnvelope{Error: e}
+}
+
+// statusCodeFromSDKError returns the HTTP status code of an error returned by a generated Ory SDK. These errors do not
+// expose the status code, but their message starts with the status, e.g. "404 Not Found".
+func statusCodeFromSDKError(err openAPIError) int {
+	if code := gjson.GetBytes(err.Body(), "error.code").Int(); code > 0 {
+		return int(code)
+	}
+	code, _ := strconv.Atoi(strings.SplitN(err.Error(), " ", 2)[0])
+	return code
+}
+
+func errorCodeFromResponse(status int, body []byte) string {
+	switch gjson.GetBytes(body, "error.id").String() {
+	case "session_inactive":
+		return ErrorCodeSessionExpired
+	}
+
+	switch {
+	case status == http.StatusUnauthorized:
+		return ErrorCodeNotAuthenticated
+	case status == http.StatusForbidden:
+		return ErrorCodePermissionDenied
+	case status == http.StatusNotFound, status == http.StatusGone:
+		return ErrorCodeNotFound
+	case status == http.StatusConflict:
+		return ErrorCodeConflict
+	case status == http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return ErrorCodeValidationFailed
+	case status >= 500:
+		return ErrorCodeServerError
+	}
+	return ErrorCodeUnknown
+}
+
+// errorDetailsFromBody returns the error of an Ory API response, if the body contains one, and the ID of the request.
+func errorDetailsFromBody(body []byte) (interface{}, string) {
+	details := gjson.GetBytes(body, "error")
+	if !details.IsObject() {
+		if !gjson.ValidBytes(body) || len(body) == 0 {
+			return nil, ""
+		}
+		return json.RawMessage(body), ""
+	}
+	return json.RawMessage(details.Raw), details.Get("request").String()
+}
+
+// printedError is returned by commands which printed their error already. It unwraps to the original error.
+type printedError struct {
+	err error
+}
+
+func (e *printedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *printedError) Unwrap() error {
+	return e.err
+}
+
+func (e *printedError) Is(target error) bool {
+	return target == cmdx.ErrNoPrintButFail
+}
+
+// EnableStructuredErrors prints the errors of cmd and all its sub commands as ErrorEnvelope to stderr if a machine
+// readable format is used, or as workflow commands if GitHubAnnotations is enabled. Human readable errors are left to the caller of the command as before. Errors caused by a
+// failed Ory Cloud API request carry the ID of that request in both cases.
+func EnableStructuredErrors(cmd *cobra.Command) {
+	if !cmd.HasParent() {
+		cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+			return NewValidationError(err, nil)
+		})
+	}
+	for _, c := range cmd.Commands() {
+		EnableStructuredErrors(c)
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := withRequestID(cmd.Context(), withTimeoutError(withNetworkError(run(cmd, args))))
+		if err == nil || errors.Is(err, cmdx.ErrNoPrintButFail) {
+			return err
+		}
+		if GitHubAnnotations(cmd) {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			PrintAnnotations(cmd, ErrorAnnotations(cmd, err))
+			return &printedError{err: err}
+		}
+		if !IsMachineReadableFormat(cmd) {
+			return err
+		}
+
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		out, encErr := json.Marshal(NewErrorEnvelope(err))
+		if encErr != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), string(out))
+		return &printedError{err: err}
+	}
+}
+
+// PrintOpenAPIError is like cmdx.PrintOpenAPIError but returns the error as is in machine readable formats so that it
+// is printed as ErrorEnvelope.
+func PrintOpenAPIError(cmd *cobra.Command, err error) error {
+	if IsMachineReadableFormat(cmd) || errors.Is(err, ErrDryRun) {
+		return err
+	}
+	printed := cmdx.PrintOpenAPIError(cmd, err)
+	var idErr *RequestIDError
+	if errors.As(withRequestID(cmd.Context(), err), &idErr) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "(%s)\n", idErr.RequestID)
+	}
+	return printed
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+)
+
+type fakeSDKError struct {
+	status string
+	body   []byte
+}
+
+func (e *fakeSDKError) Error() string { return e.status }
+func (e *fakeSDKError) Body() []byte  { return e.body }
+
+func TestNewErrorEnvelope(t *testing.T) {
+	apiErr := func(status int, body string) error {
+		return errors.WithStack(&APIError{Method: "GET", URL: "https://example.com", StatusCode: status, Body: []byte(body)})
+	}
+
+	// These codes are part of the CLI's interface, never change an existing expectation.
+	for _, tc := range []struct {
+		err  error
+		code string
+	}{
+		{err: errors.New("something went wrong"), code: "unknown"},
+		{err: errors.WithStack(ErrNoConfig), code: "not_authenticated"},
+		{err: ErrNoConfigQuiet, code: "not_authenticated"},
+		{err: errors.WithStack(ErrNotSignedIn), code: "not_authenticated"},
+		{err: errors.WithStack(&NotAuthenticatedError{Reason: "stdin is not a terminal", err: ErrNotSignedIn}), code: "not_authenticated"},
+		{err: errors.WithStack(fmt.Errorf("%w and you cannot reauthenticate", ErrSessionExpired)), code: "session_expired"},
+		{err: errors.WithStack(&PromptDisabledError{Prompt: "Continue?", Alternative: "set the --yes flag"}), code: "interaction_required"},
+		{err: errors.WithStack(ErrAborted), code: "aborted"},
+		{err: errors.WithStack(ErrNoProjectSelected), code: "project_not_selected"},
+		{err: errors.WithStack(ErrSelfHosted), code: "self_hosted"},
+		{err: fmt.Errorf("%w: no project has the ID or slug %q", ErrProjectNotFound, "foo"), code: "project_not_found"},
+		{err: fmt.Errorf("error expired or %w", ErrNotFound), code: "not_found"},
+		{err: NewValidationError(errors.New("invalid"), nil), code: "validation_failed"},
+		{err: apiErr(400, `{"error":{"code":400}}`), code: "validation_failed"},
+		{err: apiErr(401, `{"error":{"code":401}}`), code: "not_authenticated"},
+		{err: apiErr(401, `{"error":{"id":"session_inactive","code":401}}`), code: "session_expired"},
+		{err: apiErr(403, ``), code: "permission_denied"},
+		{err: apiErr(404, ``), code: "not_found"},
+		{err: apiErr(409, ``), code: "conflict"},
+		{err: apiErr(429, ``), code: "rate_limited"},
+		{err: apiErr(502, `bad gateway`), code: "server_error"},
+		{err: errors.WithStack(&fakeSDKError{status: "404 Not Found"}), code: "not_found"},
+		{err: errors.WithStack(&fakeSDKError{status: "500 Internal Server Error", body: []byte(`{"error":{"code":403}}`)}), code: "permission_denied"},
+		{err: errors.WithStack(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}), code: "network_error"},
+	} {
+		t.Run("code="+tc.code+"/err="+tc.err.Error(), func(t *testing.T) {
+			e := NewErrorEnvelope(tc.err)
+			assert.Equal(t, tc.code, e.Error.Code)
+			assert.Equal(t, tc.err.Error(), e.Error.Message)
+		})
+	}
+
+	t.Run("case=includes the details and request ID of API errors", func(t *testing.T) {
+		e := NewErrorEnvelope(apiErr(404, `{"error":{"code":404,"message":"not found","request":"7b8b2c1e"}}`))
+		assert.Equal(t, "7b8b2c1e", e.Error.RequestID)
+		assert.JSONEq(t, `{"code":404,"message":"not found","request":"7b8b2c1e"}`, string(e.Error.Details.(json.RawMessage)))
+	})
+
+	t.Run("case=includes the details of validation errors", func(t *testing.T) {
+		e := NewErrorEnvelope(NewValidationError(errors.New("invalid"), []string{"line 1"}))
+		assert.Equal(t, []string{"line 1"}, e.Error.Details)
+	})
+}
+
+func TestEnableStructuredErrors(t *testing.T) {
+	t.Setenv(githubActionsEnv, "")
+	exec := func(args ...string) (string, error) {
+		cmd := &cobra.Command{
+			Use: "fail",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return errors.WithStack(ErrAborted)
+			},
+		}
+		RegisterFormatFlag(cmd.Flags())
+		EnableStructuredErrors(cmd)
+
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stderr.String(), err
+	}
+
+	stderr, err := exec("--format", "json")
+	require.ErrorIs(t, err, cmdx.ErrNoPrintButFail)
+	assert.ErrorIs(t, err, ErrAborted)
+	assert.JSONEq(t, `{"error":{"code":"aborted","message":"aborted by user"}}`, stderr)
+
+	stderr, err = exec()
+	require.ErrorIs(t, err, ErrAborted)
+	assert.False(t, errors.Is(err, cmdx.ErrNoPrintButFail))
+	assert.Contains(t, stderr, "aborted by user")
+}
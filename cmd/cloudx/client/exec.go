@@ -0,0 +1,72 @@
+package client
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// RunChild runs argv with the stdio of cmd and env added to the environment of the CLI. It returns an ExitError with
+// the exit code of the child process if it fails, or 128 plus the signal number if a signal killed it.
+//
+// SIGTERM and SIGINT are forwarded to the child process. If stdin is a terminal, Ctrl-C is sent to the whole process
+// group by the terminal already, so SIGINT is not forwarded again, because tools like terraform exit immediately on
+// a second interrupt.
+func RunChild(cmd *cobra.Command, argv []string, env []EnvVar) error {
+	child := exec.Command(argv[0], argv[1:]...)
+	child.Stdin, child.Stdout, child.Stderr = cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()
+	child.Env = os.Environ()
+	for _, v := range env {
+		child.Env = append(child.Env, v.Name+"="+v.Value)
+	}
+
+	forward := []os.Signal{syscall.SIGTERM}
+	if !isTerminal(cmd.InOrStdin()) {
+		forward = append(forward, os.Interrupt)
+	}
+	signals := make(chan os.Signal, 1)
+	// Ctrl-C must not exit the CLI while the child process still runs.
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	atomic.AddInt32(&childProcesses, 1)
+	defer atomic.AddInt32(&childProcesses, -1)
+
+	if err := child.Start(); err != nil {
+		return errors.Wrapf(err, "unable to run %s", argv[0])
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				for _, f := range forward {
+					if sig == f {
+						_ = child.Process.Signal(sig)
+					}
+				}
+			}
+		}
+	}()
+
+	err := child.Wait()
+	var exitErr *exec.ExitError
+	if err == nil {
+		return nil
+	} else if !errors.As(err, &exitErr) {
+		return errors.WithStack(err)
+	}
+	code := exitErr.ExitCode()
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		code = 128 + int(status.Signal())
+	}
+	return FailSilently(cmd, code)
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunChild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test uses sh")
+	}
+
+	run := func(script string, stdout *syncBuffer) error {
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader(""))
+		cmd.SetOut(stdout)
+		cmd.SetErr(stdout)
+		return RunChild(cmd, []string{"sh", "-c", script}, []EnvVar{{Name: "ORY_PROJECT_SLUG", Value: "good-wright-t7kzy3vugf"}})
+	}
+
+	t.Run("case=passes the environment and output", func(t *testing.T) {
+		var out syncBuffer
+		require.NoError(t, run(`printf %s "$ORY_PROJECT_SLUG"`, &out))
+		assert.Equal(t, "good-wright-t7kzy3vugf", out.String())
+	})
+
+	t.Run("case=propagates the exit code", func(t *testing.T) {
+		err := run(`exit 3`, new(syncBuffer))
+		assert.Equal(t, 3, ExitCode(err))
+
+		err = run(`kill -KILL $$`, new(syncBuffer))
+		assert.Equal(t, 128+int(syscall.SIGKILL), ExitCode(err))
+
+		assert.ErrorContains(t, RunChild(&cobra.Command{}, []string{"ory-does-not-exist"}, nil), "unable to run ory-does-not-exist")
+	})
+
+	t.Run("case=forwards SIGTERM", func(t *testing.T) {
+		var out syncBuffer
+		done := make(chan error, 1)
+		go func() {
+			done <- run(`trap 'exit 7' TERM; echo ready; while :; do sleep 0.05; done`, &out)
+		}()
+		require.Eventually(t, func() bool { return out.String() == "ready\n" }, 5*time.Second, 10*time.Millisecond)
+
+		p, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, p.Signal(syscall.SIGTERM))
+		select {
+		case err := <-done:
+			assert.Equal(t, 7, ExitCode(err))
+		case <-time.After(5 * time.Second):
+			t.Fatal("the child process did not receive SIGTERM")
+		}
+	})
+
+	t.Run("case=forwards SIGINT if stdin is not a terminal", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close(); _ = w.Close() })
+
+		var out syncBuffer
+		cmd := &cobra.Command{}
+		cmd.SetIn(r)
+		cmd.SetOut(&out)
+		done := make(chan error, 1)
+		go func() {
+			done <- RunChild(cmd, []string{"sh", "-c", `trap 'exit 9' INT; echo ready; while :; do sleep 0.05; done`}, nil)
+		}()
+		require.Eventually(t, func() bool { return out.String() == "ready\n" }, 5*time.Second, 10*time.Millisecond)
+
+		p, err := os.FindProcess(os.Getpid())
+		require.NoError(t, err)
+		require.NoError(t, p.Signal(os.Interrupt))
+		select {
+		case err := <-done:
+			assert.Equal(t, 9, ExitCode(err))
+		case <-time.After(5 * time.Second):
+			t.Fatal("the child process did not receive SIGINT")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/x/cmdx"
+)
+
+// The exit codes of the CLI. Scripts depend on them, so never change existing codes.
+const (
+	ExitSuccess          = 0
+	ExitFailure          = 1
+	ExitNotAuthenticated = 2
+	ExitValidation       = 3
+	ExitNotFound         = 4
+	ExitPermissionDenied = 5
+	ExitAborted          = 6
+	ExitNetwork          = 7
+	// ExitOperational is the code of generic failures of commands run with --exit-code, which reserve ExitFailure
+	// for differences and findings.
+	ExitOperational = 8
+	// ExitInterrupted follows the convention of shells for processes stopped by Ctrl-C.
+	ExitInterrupted = 130
+)
+
+// ExitCodeHelp documents the exit codes in the help of the root command.
+const ExitCodeHelp = `Exit codes:
+  0    Success
+  1    Generic failure
+  2    Not authenticated or the session expired
+  3    Invalid input, e.g. a missing or malformed flag
+  4    Resource not found
+  5    Permission denied
+  6    Aborted by the user at a prompt
+  7    Network error or timeout
+  8    Generic failure of a command run with --exit-code, which exits with 1 on differences or findings instead
+  130  Interrupted with Ctrl-C
+
+"ory is allowed" exits with 1 if the subject is denied, like test(1). Code 5 means that the CLI itself is not allowed
+to call the API.`
+
+var exitCodes = map[string]int{
+	ErrorCodeNotAuthenticated:    ExitNotAuthenticated,
+	ErrorCodeSessionExpired:      ExitNotAuthenticated,
+	ErrorCodeInteractionRequired: ExitValidation,
+	ErrorCodeAborted:             ExitAborted,
+	ErrorCodeProjectNotSelected:  ExitValidation,
+	ErrorCodeProjectNotFound:     ExitNotFound,
+	ErrorCodeNotFound:            ExitNotFound,
+	ErrorCodeValidationFailed:    ExitValidation,
+	ErrorCodePermissionDenied:    ExitPermissionDenied,
+	ErrorCodeNetworkError:        ExitNetwork,
+	ErrorCodeInterrupted:         ExitInterrupted,
+	ErrorCodeSelfHosted:          ExitValidation,
+}
+
+// ExitError sets the exit code of a failed command explicitly.
+type ExitError struct {
+	Code int
+	err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.err
+}
+
+// FailSilently is like cmdx.FailSilently but exits with the given code.
+func FailSilently(cmd *cobra.Command, code int) error {
+	return &ExitError{Code: code, err: cmdx.FailSilently(cmd)}
+}
+
+// ExitCode returns the exit code of the CLI for the error returned by a command.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	if code, ok := exitCodes[NewErrorEnvelope(err).Error.Code]; ok {
+		return code
+	}
+	return ExitFailure
+}
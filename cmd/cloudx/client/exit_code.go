@@ -0,0 +1,78 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/x/cmdx"
+)
+
+// ExitCodeFlag makes diff-style commands, e.g. validators, exit with ExitFailure on differences or findings and with a
+// code of at least 2 on all other errors, so that CI pipelines can tell them apart without parsing the output.
+const ExitCodeFlag = "exit-code"
+
+// FindingsError is returned by commands supporting the --exit-code flag if they found differences or problems. The
+// findings were printed already.
+type FindingsError struct {
+	// Code is the exit code without the --exit-code flag. ExitSuccess means that the findings are no failure then.
+	Code int
+	err  error
+}
+
+func (e *FindingsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *FindingsError) Unwrap() error {
+	return e.err
+}
+
+// NewFindingsError reports printed findings. Without the --exit-code flag, the command exits with code.
+func NewFindingsError(cmd *cobra.Command, code int) error {
+	return &FindingsError{Code: code, err: cmdx.FailSilently(cmd)}
+}
+
+// RegisterExitCodeFlag registers the --exit-code flag. Commands registering it return a FindingsError on differences
+// or findings.
+func RegisterExitCodeFlag(f *pflag.FlagSet) {
+	f.Bool(ExitCodeFlag, false, "Exit with 1 on differences or findings, 0 without, and at least 2 on all other errors.")
+}
+
+func isExitCodeSet(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup(ExitCodeFlag)
+	return f != nil && f.Value.String() == "true"
+}
+
+// EnableExitCodeFlag maps the errors of cmd and all its sub commands which register the --exit-code flag onto the
+// exit codes it promises. It must be applied after EnableStructuredErrors, so that network errors are classified.
+func EnableExitCodeFlag(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableExitCodeFlag(c)
+	}
+
+	if cmd.RunE == nil || cmd.LocalFlags().Lookup(ExitCodeFlag) == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := run(cmd, args)
+		if err == nil {
+			return nil
+		}
+
+		var findings *FindingsError
+		isFindings := errors.As(err, &findings)
+		switch {
+		case isExitCodeSet(cmd) && isFindings:
+			return &ExitError{Code: ExitFailure, err: err}
+		case isExitCodeSet(cmd) && ExitCode(err) < 2:
+			return &ExitError{Code: ExitOperational, err: err}
+		case isFindings && findings.Code == ExitSuccess:
+			return nil
+		case isFindings:
+			return &ExitError{Code: findings.Code, err: err}
+		}
+		return err
+	}
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ory/x/cmdx"
+)
+
+func TestEnableExitCodeFlag(t *testing.T) {
+	run := func(t *testing.T, result func(cmd *cobra.Command) error, args ...string) int {
+		root := &cobra.Command{Use: "ory", SilenceErrors: true, SilenceUsage: true}
+		validate := &cobra.Command{Use: "validate", RunE: func(cmd *cobra.Command, _ []string) error { return result(cmd) }}
+		RegisterExitCodeFlag(validate.Flags())
+		root.AddCommand(validate)
+		EnableExitCodeFlag(root)
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(append([]string{"validate"}, args...))
+		return ExitCode(root.Execute())
+	}
+
+	for _, tc := range []struct {
+		name          string
+		result        func(t *testing.T, cmd *cobra.Command) error
+		without, with int
+	}{
+		{name: "no findings", result: func(*testing.T, *cobra.Command) error { return nil }, without: ExitSuccess, with: ExitSuccess},
+		{name: "failing findings", result: func(_ *testing.T, cmd *cobra.Command) error { return NewFindingsError(cmd, ExitValidation) }, without: ExitValidation, with: ExitFailure},
+		{name: "differences", result: func(_ *testing.T, cmd *cobra.Command) error { return NewFindingsError(cmd, ExitSuccess) }, without: ExitSuccess, with: ExitFailure},
+		{name: "generic failure", result: func(t *testing.T, _ *cobra.Command) error { return exitCodeCases["generic failure"](t) }, without: ExitFailure, with: ExitOperational},
+		{name: "failed silently", result: func(_ *testing.T, cmd *cobra.Command) error { return cmdx.FailSilently(cmd) }, without: ExitFailure, with: ExitOperational},
+		{name: "session expired", result: func(t *testing.T, _ *cobra.Command) error { return exitCodeCases["session expired"](t) }, without: ExitNotAuthenticated, with: ExitNotAuthenticated},
+		{name: "invalid input", result: func(t *testing.T, _ *cobra.Command) error { return exitCodeCases["validation error"](t) }, without: ExitValidation, with: ExitValidation},
+		{name: "timeout", result: func(t *testing.T, _ *cobra.Command) error { return exitCodeCases["timeout"](t) }, without: ExitNetwork, with: ExitNetwork},
+		{name: "aborted", result: func(t *testing.T, _ *cobra.Command) error { return exitCodeCases["aborted"](t) }, without: ExitAborted, with: ExitAborted},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			result := func(cmd *cobra.Command) error { return tc.result(t, cmd) }
+			assert.Equal(t, tc.without, run(t, result), "without --exit-code")
+			assert.Equal(t, tc.with, run(t, result, "--"+ExitCodeFlag), "with --exit-code")
+		})
+	}
+
+	t.Run("case=commands without the flag are unchanged", func(t *testing.T) {
+		root := &cobra.Command{Use: "ory", SilenceErrors: true, SilenceUsage: true}
+		root.AddCommand(&cobra.Command{Use: "get", RunE: func(cmd *cobra.Command, _ []string) error { return NewFindingsError(cmd, ExitNotFound) }})
+		EnableExitCodeFlag(root)
+		root.SetArgs([]string{"get"})
+		assert.Equal(t, ExitFailure, ExitCode(root.Execute()), "the findings are not mapped")
+	})
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exitCodeCaseEnv = "TEST_EXIT_CODE_CASE"
+
+// exitCodeCases return the error of a command which failed for the given reason.
+var exitCodeCases = map[string]func(t *testing.T) error{
+	"success": func(t *testing.T) error {
+		return nil
+	},
+	"generic failure": func(t *testing.T) error {
+		return errors.New("something went wrong")
+	},
+	"session expired": func(t *testing.T) error {
+		return callMockedAPI(t, http.StatusUnauthorized, `{"error":{"id":"session_inactive","code":401}}`)
+	},
+	"validation error": func(t *testing.T) error {
+		return callMockedAPI(t, http.StatusBadRequest, `{"error":{"code":400,"message":"invalid"}}`)
+	},
+	"not found": func(t *testing.T) error {
+		return callMockedAPI(t, http.StatusNotFound, `{"error":{"code":404}}`)
+	},
+	"permission denied": func(t *testing.T) error {
+		return callMockedAPI(t, http.StatusForbidden, `{"error":{"code":403}}`)
+	},
+	"aborted": func(t *testing.T) error {
+		return errors.WithStack(ErrAborted)
+	},
+	"timeout": func(t *testing.T) error {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer ts.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		api := &ProjectAPI{URL: ts.URL, Client: ts.Client()}
+		_, err := api.Do(ctx, http.MethodGet, "/", nil, nil, nil)
+		return err
+	},
+	"failed silently": func(t *testing.T) error {
+		return FailSilently(&cobra.Command{}, ExitNotFound)
+	},
+}
+
+func callMockedAPI(t *testing.T, status int, body string) error {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, body)
+	}))
+	defer ts.Close()
+
+	api := &ProjectAPI{URL: ts.URL, Client: ts.Client()}
+	_, err := api.Do(context.Background(), http.MethodGet, "/", nil, nil, nil)
+	require.Error(t, err)
+	return err
+}
+
+func TestExitCode(t *testing.T) {
+	if name := os.Getenv(exitCodeCaseEnv); name != "" {
+		os.Exit(ExitCode(exitCodeCases[name](t)))
+	}
+
+	for name, expected := range map[string]int{
+		"success":           0,
+		"generic failure":   1,
+		"session expired":   2,
+		"validation error":  3,
+		"not found":         4,
+		"permission denied": 5,
+		"aborted":           6,
+		"timeout":           7,
+		"failed silently":   4,
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestExitCode$")
+			cmd.Env = append(os.Environ(), exitCodeCaseEnv+"="+name)
+			err := cmd.Run()
+
+			var exitErr *exec.ExitError
+			if expected == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorAs(t, err, &exitErr)
+			assert.Equal(t, expected, exitErr.ExitCode())
+		})
+	}
+}
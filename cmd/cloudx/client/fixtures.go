@@ -0,0 +1,291 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrs "errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/pkg/errors"
+)
+
+// ErrFixtureNotFound is returned instead of sending a request when replaying fixtures and none matches the request.
+var ErrFixtureNotFound = stderrs.New("no fixture matches the request")
+
+// fixtureHeaders are the response headers which are recorded. All others, e.g. cookies, are dropped.
+var fixtureHeaders = []string{"Content-Type", "Link", "Location", "Retry-After"}
+
+var (
+	uuidPattern    = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	idTemplate     = regexp.MustCompile(`\{\{id-([0-9a-f]{12})}}`)
+	fixtureFile    = regexp.MustCompile(`^[0-9a-f]{16}-[0-9]{3}\.json$`)
+	fixturesMu     sync.Mutex
+	activeFixtures *fixtureStore
+)
+
+type fixture struct {
+	Request  fixtureRequest  `json:"request"`
+	Response fixtureResponse `json:"response"`
+}
+
+type fixtureRequest struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	BodyHash string `json:"body_hash"`
+	Body     string `json:"body,omitempty"`
+}
+
+type fixtureResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// fixtureStore records requests to, or replays responses from, a directory. The store set by UseFixtures is shared by
+// all commands run by the process, so that tests running several commands replay the responses in the recorded order.
+//
+// Credentials are scrubbed like in the debug log, and request headers are not recorded at all. IDs are replaced by
+// templates derived from their hash, e.g. {{id-3f2a9c0d1e4b}}, so that requests match regardless of the order in
+// which concurrent requests saw the IDs. When replaying, the templates are replaced by the IDs seen in the requests or,
+// for IDs first seen in responses, by stable IDs such as 00000000-0000-4000-8000-3f2a9c0d1e4b.
+type fixtureStore struct {
+	sync.Mutex
+	dir       string
+	record    bool
+	templates map[string]string
+	ids       map[string]string
+	// sequences counts the requests per key, so that repeated requests get the responses in the recorded order.
+	sequences map[string]int
+}
+
+// UseFixtures makes all commands run by the process record their requests and responses to dir, or replay the
+// responses from it without sending any requests, see fixtureTransport. Recording removes the fixtures of
+// previous recordings from dir, but no other files. Call stop to send requests again.
+//
+// Only tests use fixtures, see cloudxtest.RecordReplay. The CLI itself has no way to enable them.
+func UseFixtures(dir string, record bool) (stop func(), err error) {
+	if record {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() && fixtureFile.MatchString(e.Name()) {
+				if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+					return nil, errors.WithStack(err)
+				}
+			}
+		}
+	} else if _, err := os.Stat(dir); err != nil {
+		return nil, errors.Wrapf(err, "unable to replay fixtures from %s", dir)
+	}
+
+	s := &fixtureStore{dir: dir, record: record, templates: map[string]string{}, ids: map[string]string{}, sequences: map[string]int{}}
+	fixturesMu.Lock()
+	activeFixtures = s
+	fixturesMu.Unlock()
+	return func() {
+		fixturesMu.Lock()
+		defer fixturesMu.Unlock()
+		if activeFixtures == s {
+			activeFixtures = nil
+		}
+	}, nil
+}
+
+// currentFixtures returns the fixtures set by UseFixtures, or nil if requests are sent.
+func currentFixtures() *fixtureStore {
+	fixturesMu.Lock()
+	defer fixturesMu.Unlock()
+	return activeFixtures
+}
+
+// template replaces all IDs in v by their templates.
+func (s *fixtureStore) template(v string) string {
+	return uuidPattern.ReplaceAllStringFunc(v, func(id string) string {
+		if id == uuid.Nil.String() {
+			return id
+		}
+		if t, ok := s.templates[id]; ok {
+			return t
+		}
+		sum := sha256.Sum256([]byte(strings.ToLower(id)))
+		t := "{{id-" + hex.EncodeToString(sum[:6]) + "}}"
+		s.templates[id], s.ids[t] = t, id
+		return t
+	})
+}
+
+// instantiate replaces all templates in v by their IDs.
+func (s *fixtureStore) instantiate(v string) string {
+	return idTemplate.ReplaceAllStringFunc(v, func(t string) string {
+		if id, ok := s.ids[t]; ok {
+			return id
+		}
+		id := "00000000-0000-4000-8000-" + idTemplate.FindStringSubmatch(t)[1]
+		s.templates[id], s.ids[t] = t, id
+		return id
+	})
+}
+
+// sanitize scrubs credentials from JSON and form bodies and replaces IDs by templates. JSON is re-encoded with sorted
+// keys, so that equal bodies are equal after sanitizing.
+func (s *fixtureStore) sanitize(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return s.template(redactValues(values).Encode())
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err == nil {
+		if out, err := json.Marshal(redactJSON(v)); err == nil {
+			return s.template(string(out))
+		}
+	}
+	return s.template(string(body))
+}
+
+func (s *fixtureStore) file(key string, n int) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, fmt.Sprintf("%s-%03d.json", hex.EncodeToString(sum[:8]), n))
+}
+
+// fixtureTransport records all requests and responses to a fixtureStore, or replays the responses from it without
+// sending any requests. Requests match fixtures by method, path, and the hash of the sanitized body.
+type fixtureTransport struct {
+	http.RoundTripper
+	store *fixtureStore
+}
+
+// withFixtures wraps the transport with a fixtureTransport if the store is set.
+func withFixtures(rt http.RoundTripper, s *fixtureStore) http.RoundTripper {
+	if s == nil {
+		return rt
+	}
+	return &fixtureTransport{RoundTripper: rt, store: s}
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	s := t.store
+	s.Lock()
+	sanitized := s.sanitize(req.Header.Get("Content-Type"), body)
+	sum := sha256.Sum256([]byte(sanitized))
+	f := fixture{Request: fixtureRequest{Method: req.Method, Path: s.template(req.URL.Path), BodyHash: hex.EncodeToString(sum[:]), Body: sanitized}}
+	key := f.Request.Method + " " + f.Request.Path + " " + f.Request.BodyHash
+	n := s.sequences[key]
+	s.sequences[key]++
+	s.Unlock()
+
+	if s.record {
+		return t.record(req, &f, s.file(key, n))
+	}
+	return t.replay(req, &f, key, n)
+}
+
+func (t *fixtureTransport) record(req *http.Request, f *fixture, file string) (*http.Response, error) {
+	res, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	s := t.store
+	s.Lock()
+	f.Response = fixtureResponse{Status: res.StatusCode, Body: s.sanitize(res.Header.Get("Content-Type"), body)}
+	for _, name := range fixtureHeaders {
+		if v := res.Header.Get(name); v != "" {
+			if f.Response.Header == nil {
+				f.Response.Header = http.Header{}
+			}
+			f.Response.Header.Set(name, s.template(v))
+		}
+	}
+	s.Unlock()
+
+	out, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := os.WriteFile(file, append(out, '\n'), 0600); err != nil {
+		return nil, errors.Wrapf(err, "unable to record the fixture of %s %s", req.Method, redactURL(req.URL))
+	}
+	return res, nil
+}
+
+// replay serves the n-th response recorded for the key. Requests repeated more often than recorded, e.g. retries, get
+// the last recorded response.
+func (t *fixtureTransport) replay(req *http.Request, f *fixture, key string, n int) (*http.Response, error) {
+	var raw []byte
+	var err error
+	for ; n >= 0; n-- {
+		if raw, err = os.ReadFile(t.store.file(key, n)); !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+	}
+	if n < 0 {
+		return nil, errors.WithStack(fmt.Errorf("%w in %s: %s %s with the body %q, record it again", ErrFixtureNotFound, t.store.dir, f.Request.Method, f.Request.Path, f.Request.Body))
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var recorded fixture
+	if err := json.Unmarshal(raw, &recorded); err != nil {
+		return nil, errors.Wrapf(err, "unable to decode the fixture of %s %s", f.Request.Method, f.Request.Path)
+	}
+
+	s := t.store
+	s.Lock()
+	defer s.Unlock()
+	res := &http.Response{
+		Status:     fmt.Sprintf("%d %s", recorded.Response.Status, http.StatusText(recorded.Response.Status)),
+		StatusCode: recorded.Response.Status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    req,
+	}
+	for name, values := range recorded.Response.Header {
+		for _, v := range values {
+			res.Header.Add(name, s.instantiate(v))
+		}
+	}
+	body := s.instantiate(recorded.Response.Body)
+	res.Body, res.ContentLength = io.NopCloser(bytes.NewReader([]byte(body))), int64(len(body))
+	return res, nil
+}
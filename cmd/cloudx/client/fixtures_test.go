@@ -0,0 +1,127 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestFixtures(t *testing.T) {
+	const identityID = "4cd7b0a2-4d0a-4c3b-9e34-6c8f1e5a7b21"
+	var gets int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=s3cr3t-cookie")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/self-service/login":
+			_, _ = fmt.Fprintf(w, `{"session_token": "s3cr3t-session", "session": {"identity": {"id": %q}}}`, identityID)
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/identities/"+identityID:
+			gets++
+			_, _ = fmt.Fprintf(w, `{"id": %q, "state": "active", "version": %d}`, identityID, gets)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	dir := filepath.Join(t.TempDir(), "fixtures")
+
+	type exchange struct {
+		status int
+		body   string
+	}
+	send := func(t *testing.T, method, path, body string) (exchange, error) {
+		c := newClient(http.DefaultTransport, clientConfig{fixtures: currentFixtures()}).httpClient("s3cr3t-bearer")
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := c.Do(req)
+		if err != nil {
+			return exchange{}, err
+		}
+		defer res.Body.Close()
+		out, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return exchange{status: res.StatusCode, body: string(out)}, nil
+	}
+	// session signs in and reads the identity of the session three times.
+	session := func(t *testing.T) []exchange {
+		login, err := send(t, http.MethodPost, "/self-service/login", `{"method": "password", "identifier": "dev@ory.sh", "password": "s3cr3t-password"}`)
+		require.NoError(t, err)
+		exchanges := []exchange{login}
+		id := gjson.Get(login.body, "session.identity.id").String()
+		for i := 0; i < 3; i++ {
+			e, err := send(t, http.MethodGet, "/admin/identities/"+id, "")
+			require.NoError(t, err)
+			exchanges = append(exchanges, e)
+		}
+		return exchanges
+	}
+
+	useFixtures := func(t *testing.T, record bool) {
+		stop, err := UseFixtures(dir, record)
+		require.NoError(t, err)
+		t.Cleanup(stop)
+	}
+
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	unrelated := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(unrelated, []byte(`{"keep": true}`), 0600))
+	stale := filepath.Join(dir, "0123456789abcdef-000.json")
+	require.NoError(t, os.WriteFile(stale, []byte(`{}`), 0600))
+
+	useFixtures(t, true)
+	recorded := session(t)
+	assert.EqualValues(t, 3, gjson.Get(recorded[3].body, "version").Int())
+
+	t.Run("case=recording only removes previous fixtures", func(t *testing.T) {
+		assert.NoFileExists(t, stale)
+		assert.FileExists(t, unrelated)
+	})
+
+	t.Run("case=credentials and IDs are scrubbed", func(t *testing.T) {
+		files, err := filepath.Glob(filepath.Join(dir, "*-*.json"))
+		require.NoError(t, err)
+		require.Len(t, files, 4)
+		for _, f := range files {
+			content, err := os.ReadFile(f)
+			require.NoError(t, err)
+			for _, secret := range []string{"s3cr3t-password", "s3cr3t-session", "s3cr3t-bearer", "s3cr3t-cookie", identityID} {
+				assert.NotContains(t, string(content), secret, f)
+			}
+		}
+	})
+
+	useFixtures(t, false)
+	srv.Close()
+
+	t.Run("case=replays the responses in order", func(t *testing.T) {
+		replayed := session(t)
+		require.Len(t, replayed, 4)
+		assert.Equal(t, redacted, gjson.Get(replayed[0].body, "session_token").String())
+		id := gjson.Get(replayed[0].body, "session.identity.id").String()
+		assert.Regexp(t, "^00000000-0000-4000-8000-[0-9a-f]{12}$", id)
+		for k, e := range replayed[1:] {
+			assert.Equal(t, http.StatusOK, e.status)
+			assert.Equal(t, id, gjson.Get(e.body, "id").String())
+			assert.EqualValues(t, k+1, gjson.Get(e.body, "version").Int())
+		}
+
+		again, err := send(t, http.MethodGet, "/admin/identities/"+id, "")
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, gjson.Get(again.body, "version").Int(), "the last response is repeated")
+	})
+
+	t.Run("case=fails on unmatched requests", func(t *testing.T) {
+		_, err := send(t, http.MethodPost, "/self-service/login", `{"method": "password", "identifier": "other@ory.sh"}`)
+		assert.ErrorIs(t, err, ErrFixtureNotFound)
+	})
+}
@@ -6,13 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
 	cloud "github.com/ory/client-go"
 
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
+	"golang.org/x/term"
 
 	"github.com/ory/x/cmdx"
 )
@@ -30,98 +33,128 @@ func getLabel(attrs *cloud.UiNodeInputAttributes, node *cloud.UiNode) string {
 
 type passwordReader = func() ([]byte, error)
 
-func renderForm(stdin *bufio.Reader, pwReader passwordReader, stderr io.Writer, ui cloud.UiContainer, method string, out interface{}) (err error) {
-	for _, message := range ui.Messages {
-		_, _ = fmt.Fprintf(stderr, "%s\n", message.Text)
+// newPasswordReader returns the passwordReader of stdin. If stdin is a terminal, the password is read without echoing
+// it. Otherwise, e.g. if the input is scripted, it is the next line of stdin.
+func newPasswordReader(stdin *bufio.Reader, terminal *os.File) passwordReader {
+	return func() ([]byte, error) {
+		if terminal != nil && term.IsTerminal(int(terminal.Fd())) {
+			return term.ReadPassword(int(terminal.Fd()))
+		}
+		line, err := stdin.ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+			return nil, errors.WithStack(err)
+		}
+		return []byte(strings.TrimRight(line, "\r\n")), nil
 	}
+}
+
+func isRequired(attrs *cloud.UiNodeInputAttributes) bool {
+	return attrs.Required != nil && *attrs.Required
+}
 
+func hasErrorMessages(messages []cloud.UiText) bool {
+	for _, m := range messages {
+		if m.Type == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// renderForm asks for the values of the form. If the server rejected some fields of a previous submission, only
+// those fields and fields without a value, such as passwords, are asked for again. Previously entered values are
+// shown as defaults in brackets. The messages of a field, e.g. why it was rejected, are printed right above its
+// prompt. The messages of the form itself are printed by the caller, see flowMessages.
+//
+// If confirm is true, the values of non-secret fields are echoed before the form is submitted, and the user can
+// re-enter any of them.
+func renderForm(stdin *bufio.Reader, pwReader passwordReader, stderr io.Writer, ui cloud.UiContainer, method string, confirm bool, out interface{}) (err error) {
+	var retry bool
 	for _, node := range ui.Nodes {
-		for _, message := range node.Messages {
-			_, _ = fmt.Fprintf(stderr, "%s\n", message.Text)
+		retry = retry || hasErrorMessages(node.Messages)
+	}
+
+	ask := func(node cloud.UiNode, previous string) (value interface{}, err error) {
+		attrs := node.Attributes.UiNodeInputAttributes
+		switch attrs.Type {
+		case "checkbox":
+			return cmdx.AskScannerForConfirmation(getLabel(attrs, &node), stdin, stderr)
+		case "password":
+			return askValue(stderr, getLabel(attrs, &node), "", isRequired(attrs), func() (string, error) {
+				v, err := pwReader()
+				// The password is not echoed, so the line break has to be printed.
+				_, _ = fmt.Fprintln(stderr)
+				return string(v), err
+			})
 		}
+
+		label := getLabel(attrs, &node)
+		if previous != "" {
+			label = fmt.Sprintf("%s [%s]: ", strings.TrimSuffix(label, ": "), previous)
+		}
+		return askValue(stderr, label, previous, isRequired(attrs), func() (string, error) {
+			v, err := stdin.ReadString('\n')
+			return v, errors.Wrap(err, "failed to read from stdin")
+		})
 	}
 
 	values := json.RawMessage(`{}`)
-	for k := range ui.Nodes {
-		node := ui.Nodes[k]
-		if node.Group != method && node.Group != "default" {
-			continue
+	var echoed []cloud.UiNode
+	if err := walkForm(ui, method, func(node cloud.UiNode) (err error) {
+		attrs := node.Attributes.UiNodeInputAttributes
+		if node.Type != "input" || attrs == nil {
+			return nil
 		}
 
-		switch node.Type {
-		case "input":
-			attrs := node.Attributes.UiNodeInputAttributes
-			switch attrs.Type {
-			case "button":
-				continue
-			case "submit":
-				continue
-			}
+		switch attrs.Type {
+		case "button", "submit", "hidden":
+			return nil
+		}
 
-			if attrs.Name == "traits.consent.tos" {
-				for {
-					ok, err := cmdx.AskScannerForConfirmation(getLabel(attrs, &node), stdin, stderr)
-					if err != nil {
-						return err
-					}
-					if ok {
-						break
-					}
-				}
-				values, err = sjson.SetBytes(values, attrs.Name, time.Now().UTC().Format(time.RFC3339))
-				if err != nil {
-					return err
-				}
-				continue
-			}
+		if !isSecret(node) && attrs.Type != "checkbox" && attrs.Name != "traits.consent.tos" {
+			echoed = append(echoed, node)
+		}
 
-			switch attrs.Type {
-			case "hidden":
-				continue
-			case "checkbox":
-				result, err := cmdx.AskScannerForConfirmation(getLabel(attrs, &node), stdin, stderr)
-				if err != nil {
-					return err
-				}
+		if retry && !hasErrorMessages(node.Messages) && attrs.Value != nil {
+			values, err = sjson.SetBytes(values, attrs.Name, attrs.Value)
+			return err
+		}
 
-				values, err = sjson.SetBytes(values, attrs.Name, result)
-				if err != nil {
-					return err
-				}
-			case "password":
-				var password string
-				for password == "" {
-					_, _ = fmt.Fprint(stderr, getLabel(attrs, &node))
-					v, err := pwReader()
-					if err != nil {
-						return err
-					}
-					password = strings.ReplaceAll(string(v), "\n", "")
-					fmt.Println("")
-				}
+		for _, m := range node.Messages {
+			_, _ = fmt.Fprintln(stderr, m.Text)
+		}
 
-				values, err = sjson.SetBytes(values, attrs.Name, password)
+		if attrs.Name == "traits.consent.tos" {
+			for {
+				ok, err := cmdx.AskScannerForConfirmation(getLabel(attrs, &node), stdin, stderr)
 				if err != nil {
 					return err
 				}
-			default:
-				var value string
-				for value == "" {
-					_, _ = fmt.Fprint(stderr, getLabel(attrs, &node))
-					v, err := stdin.ReadString('\n')
-					if err != nil {
-						return errors.Wrap(err, "failed to read from stdin")
-					}
-					value = strings.ReplaceAll(v, "\n", "")
-				}
-
-				values, err = sjson.SetBytes(values, attrs.Name, value)
-				if err != nil {
-					return err
+				if ok {
+					break
 				}
 			}
-		default:
-			// Do nothing
+			values, err = sjson.SetBytes(values, attrs.Name, time.Now().UTC().Format(time.RFC3339))
+			return err
+		}
+
+		previous, _ := attrs.Value.(string)
+		value, err := ask(node, previous)
+		if err != nil {
+			return err
+		} else if value == "" {
+			return nil
+		}
+
+		values, err = sjson.SetBytes(values, attrs.Name, value)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if confirm {
+		if values, err = confirmValues(stdin, stderr, echoed, values, ask); err != nil {
+			return err
 		}
 	}
 
@@ -132,3 +165,213 @@ func renderForm(stdin *bufio.Reader, pwReader passwordReader, stderr io.Writer,
 
 	return errors.WithStack(json.NewDecoder(bytes.NewBuffer(values)).Decode(out))
 }
+
+// maxFormAttempts is how often the sign up and sign in forms are submitted before giving up.
+const maxFormAttempts = 3
+
+// flowMessages returns the messages of a form which the server rejected, e.g. "The provided credentials are invalid",
+// without the messages of its fields, which renderForm prints above their prompts.
+func flowMessages(ui cloud.UiContainer) []string {
+	messages := make([]string, 0, len(ui.Messages))
+	for _, m := range ui.Messages {
+		messages = append(messages, m.Text)
+	}
+	return messages
+}
+
+// formMessages returns all messages of a form which the server rejected for the error after the last attempt: the
+// messages of the form first, see flowMessages, then the messages of its fields prefixed with the label of the field,
+// e.g. "Password: The password is too weak."
+func formMessages(ui cloud.UiContainer) []string {
+	messages := flowMessages(ui)
+	for k := range ui.Nodes {
+		node := &ui.Nodes[k]
+		label := node.Group
+		if attrs := node.Attributes.UiNodeInputAttributes; attrs != nil {
+			label = strings.TrimSuffix(getLabel(attrs, node), ": ")
+		}
+		for _, m := range node.Messages {
+			messages = append(messages, label+": "+m.Text)
+		}
+	}
+	return messages
+}
+
+// formAttemptsError is returned if the form was rejected maxFormAttempts times. It includes the messages of the last
+// rejection.
+func formAttemptsError(action string, messages []string) error {
+	if len(messages) == 0 {
+		return errors.Errorf("unable to %s after %d attempts", action, maxFormAttempts)
+	}
+	return errors.Errorf("unable to %s after %d attempts: %s", action, maxFormAttempts, strings.Join(messages, " "))
+}
+
+// isSecret returns true for fields whose values are never echoed, such as passwords and one-time codes.
+func isSecret(node cloud.UiNode) bool {
+	return node.Attributes.UiNodeInputAttributes.Type == "password" || node.Group == "totp" || node.Group == "lookup_secret"
+}
+
+// confirmValues echoes the values of the fields, e.g. "Email: jane@example.com — correct? [Y/n]", and re-asks for a
+// field until the user confirms the values.
+func confirmValues(stdin *bufio.Reader, stderr io.Writer, fields []cloud.UiNode, values json.RawMessage, ask func(node cloud.UiNode, previous string) (interface{}, error)) (json.RawMessage, error) {
+	for {
+		var summary []string
+		for k := range fields {
+			attrs := fields[k].Attributes.UiNodeInputAttributes
+			if v := gjson.GetBytes(values, attrs.Name).String(); v != "" {
+				summary = append(summary, getLabel(attrs, &fields[k])+v)
+			}
+		}
+		if len(summary) == 0 {
+			return values, nil
+		}
+
+		ok, err := askYesByDefault(stdin, stderr, strings.Join(summary, ", ")+" — correct?")
+		if err != nil || ok {
+			return values, err
+		}
+
+		node, err := askField(stdin, stderr, fields)
+		if err != nil {
+			return nil, err
+		}
+		attrs := node.Attributes.UiNodeInputAttributes
+		value, err := ask(node, gjson.GetBytes(values, attrs.Name).String())
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			values, err = sjson.DeleteBytes(values, attrs.Name)
+		} else {
+			values, err = sjson.SetBytes(values, attrs.Name, value)
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+}
+
+// askYesByDefault asks a yes/no question which is answered with yes if the user just presses enter.
+func askYesByDefault(stdin *bufio.Reader, stderr io.Writer, question string) (bool, error) {
+	for {
+		_, _ = fmt.Fprintf(stderr, "%s [Y/n]: ", question)
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return false, errors.Wrap(err, "failed to read from stdin")
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "", "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// askField asks which of the fields the user wants to change. The field can be given by its label or name.
+func askField(stdin *bufio.Reader, stderr io.Writer, fields []cloud.UiNode) (cloud.UiNode, error) {
+	if len(fields) == 1 {
+		return fields[0], nil
+	}
+
+	labels := make([]string, len(fields))
+	for k := range fields {
+		labels[k] = strings.TrimSuffix(getLabel(fields[k].Attributes.UiNodeInputAttributes, &fields[k]), ": ")
+	}
+	for {
+		_, _ = fmt.Fprintf(stderr, "Which field do you want to change (%s)? ", strings.Join(labels, ", "))
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return cloud.UiNode{}, errors.Wrap(err, "failed to read from stdin")
+		}
+
+		answer := strings.TrimSpace(line)
+		for k := range fields {
+			if strings.EqualFold(answer, labels[k]) || answer == fields[k].Attributes.UiNodeInputAttributes.Name {
+				return fields[k], nil
+			}
+		}
+	}
+}
+
+// askValue prompts until the value is not empty if it is required. Empty input selects the default.
+func askValue(stderr io.Writer, label, defaultValue string, required bool, read func() (string, error)) (string, error) {
+	for {
+		_, _ = fmt.Fprint(stderr, label)
+		v, err := read()
+		if err != nil {
+			return "", err
+		}
+
+		value := strings.TrimRight(v, "\r\n")
+		if value == "" {
+			value = defaultValue
+		}
+		if value != "" || !required {
+			return value, nil
+		}
+		_, _ = fmt.Fprintln(stderr, "This field is required, please enter a value.")
+	}
+}
+
+// walkForm calls visit for every node of the given group and the default group. If group is empty, all nodes are
+// visited.
+func walkForm(ui cloud.UiContainer, group string, visit func(node cloud.UiNode) error) error {
+	for k := range ui.Nodes {
+		node := ui.Nodes[k]
+		if group != "" && node.Group != group && node.Group != "default" {
+			continue
+		}
+		if err := visit(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintFormOutline writes the messages and nodes of the form grouped by method to w without asking for any input.
+func PrintFormOutline(w io.Writer, ui cloud.UiContainer) error {
+	printMessages := func(indent string, messages []cloud.UiText) {
+		for _, m := range messages {
+			_, _ = fmt.Fprintf(w, "%s[%s %d] %s\n", indent, m.Type, m.Id, m.Text)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "%s %s\n", ui.Method, ui.Action)
+	printMessages("  ", ui.Messages)
+
+	var group string
+	return walkForm(ui, "", func(node cloud.UiNode) error {
+		if node.Group != group {
+			group = node.Group
+			_, _ = fmt.Fprintf(w, "\n%s:\n", group)
+		}
+
+		if node.Type != "input" || node.Attributes.UiNodeInputAttributes == nil {
+			_, _ = fmt.Fprintf(w, "  <%s>\n", node.Type)
+			printMessages("    ", node.Messages)
+			return nil
+		}
+
+		attrs := node.Attributes.UiNodeInputAttributes
+		details := []string{attrs.Type}
+		if attrs.Required != nil && *attrs.Required {
+			details = append(details, "required")
+		}
+		if attrs.Disabled {
+			details = append(details, "disabled")
+		}
+
+		line := fmt.Sprintf("  %s (%s)", attrs.Name, strings.Join(details, ", "))
+		if label := strings.TrimSuffix(getLabel(attrs, &node), ": "); label != attrs.Name {
+			line += fmt.Sprintf(" %q", label)
+		}
+		if attrs.Value != nil && attrs.Type != "password" && attrs.Type != "hidden" {
+			line += fmt.Sprintf(" = %v", attrs.Value)
+		}
+		_, _ = fmt.Fprintln(w, line)
+		printMessages("    ", node.Messages)
+		return nil
+	})
+}
@@ -0,0 +1,219 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cloud "github.com/ory/client-go"
+)
+
+func TestRenderForm(t *testing.T) {
+	required := true
+	input := func(name, typ string, value interface{}, messages ...cloud.UiText) cloud.UiNode {
+		return cloud.UiNode{
+			Type:     "input",
+			Group:    "password",
+			Messages: messages,
+			Attributes: cloud.UiNodeAttributes{UiNodeInputAttributes: &cloud.UiNodeInputAttributes{
+				Name:     name,
+				Type:     typ,
+				Value:    value,
+				Required: &required,
+			}},
+		}
+	}
+	ui := func(nodes ...cloud.UiNode) cloud.UiContainer {
+		return cloud.UiContainer{Nodes: append([]cloud.UiNode{
+			input("csrf_token", "hidden", "csrf"),
+			input("method", "submit", "password"),
+		}, nodes...)}
+	}
+	passwords := func(t *testing.T, passwords ...string) passwordReader {
+		return func() ([]byte, error) {
+			require.NotEmpty(t, passwords, "unexpected password prompt")
+			p := passwords[0]
+			passwords = passwords[1:]
+			return []byte(p), nil
+		}
+	}
+	renderConfirmed := func(t *testing.T, stdin string, pwReader passwordReader, ui cloud.UiContainer) (map[string]interface{}, string) {
+		var stderr bytes.Buffer
+		var out map[string]interface{}
+		require.NoError(t, renderForm(bufio.NewReader(strings.NewReader(stdin)), pwReader, &stderr, ui, "password", true, &out))
+		return out, stderr.String()
+	}
+	render := func(t *testing.T, stdin string, pwReader passwordReader, ui cloud.UiContainer) (map[string]interface{}, string) {
+		var stderr bytes.Buffer
+		var out map[string]interface{}
+		require.NoError(t, renderForm(bufio.NewReader(strings.NewReader(stdin)), pwReader, &stderr, ui, "password", false, &out))
+		return out, stderr.String()
+	}
+
+	t.Run("case=asks for all fields", func(t *testing.T) {
+		out, stderr := render(t, "jane@example.com\n", passwords(t, "secret"), ui(
+			input("traits.email", "email", nil),
+			input("password", "password", nil),
+		))
+		assert.Equal(t, map[string]interface{}{
+			"method":   "password",
+			"traits":   map[string]interface{}{"email": "jane@example.com"},
+			"password": "secret",
+		}, out)
+		assert.Equal(t, "traits.email: password: \n", stderr)
+	})
+
+	t.Run("case=re-prompts only rejected fields", func(t *testing.T) {
+		out, stderr := render(t, "", passwords(t, "a much stronger secret"), ui(
+			input("traits.email", "email", "jane@example.com"),
+			input("password", "password", nil, cloud.UiText{Id: 4000005, Type: "error", Text: "The password is too weak."}),
+		))
+		assert.Equal(t, map[string]interface{}{
+			"method":   "password",
+			"traits":   map[string]interface{}{"email": "jane@example.com"},
+			"password": "a much stronger secret",
+		}, out)
+		assert.Equal(t, "The password is too weak.\npassword: \n", stderr, "the message is printed above the prompt")
+	})
+
+	t.Run("case=re-prompts fields without a value and shows previous values", func(t *testing.T) {
+		out, stderr := render(t, "\n", passwords(t, "secret"), ui(
+			input("traits.email", "email", "jane@example", cloud.UiText{Id: 4000001, Type: "error", Text: "\"jane@example\" is not valid \"email\""}),
+			input("password", "password", nil),
+		))
+		assert.Equal(t, map[string]interface{}{
+			"method":   "password",
+			"traits":   map[string]interface{}{"email": "jane@example"},
+			"password": "secret",
+		}, out)
+		assert.Equal(t, "\"jane@example\" is not valid \"email\"\ntraits.email [jane@example]: password: \n", stderr)
+	})
+
+	t.Run("case=enforces required fields", func(t *testing.T) {
+		optional := input("traits.name", "text", nil)
+		optional.Attributes.UiNodeInputAttributes.Required = nil
+
+		out, stderr := render(t, "\njane@example.com\n\n", passwords(t, "", "secret"), ui(
+			input("traits.email", "email", nil),
+			optional,
+			input("password", "password", nil),
+		))
+		assert.Equal(t, map[string]interface{}{
+			"method":   "password",
+			"traits":   map[string]interface{}{"email": "jane@example.com"},
+			"password": "secret",
+		}, out)
+		assert.Equal(t, "traits.email: This field is required, please enter a value.\n"+
+			"traits.email: traits.name: password: \n"+
+			"This field is required, please enter a value.\n"+
+			"password: \n", stderr)
+	})
+
+	t.Run("case=echoes non-secret values for confirmation", func(t *testing.T) {
+		out, stderr := renderConfirmed(t, "jane@example.com\n\n", passwords(t, "secret"), ui(
+			input("traits.email", "email", nil),
+			input("password", "password", nil),
+		))
+		assert.Equal(t, map[string]interface{}{
+			"method":   "password",
+			"traits":   map[string]interface{}{"email": "jane@example.com"},
+			"password": "secret",
+		}, out)
+		assert.Equal(t, "traits.email: password: \ntraits.email: jane@example.com — correct? [Y/n]: ", stderr)
+	})
+
+	t.Run("case=re-enters a single field", func(t *testing.T) {
+		out, stderr := renderConfirmed(t, "jane@examplecom\nJane\nn\ntraits.email\njane@example.com\ny\n", passwords(t, "secret"), ui(
+			input("traits.email", "email", nil),
+			input("traits.name", "text", nil),
+			input("password", "password", nil),
+		))
+		assert.Equal(t, map[string]interface{}{
+			"method":   "password",
+			"traits":   map[string]interface{}{"email": "jane@example.com", "name": "Jane"},
+			"password": "secret",
+		}, out)
+		assert.Equal(t, "traits.email: traits.name: password: \n"+
+			"traits.email: jane@examplecom, traits.name: Jane — correct? [Y/n]: "+
+			"Which field do you want to change (traits.email, traits.name)? "+
+			"traits.email [jane@examplecom]: "+
+			"traits.email: jane@example.com, traits.name: Jane — correct? [Y/n]: ", stderr)
+	})
+
+	t.Run("case=does not confirm secrets only", func(t *testing.T) {
+		totp := input("totp_code", "text", nil)
+		totp.Group = "totp"
+		var stderr bytes.Buffer
+		var out map[string]interface{}
+		require.NoError(t, renderForm(bufio.NewReader(strings.NewReader("123456\n")), passwords(t), &stderr, cloud.UiContainer{Nodes: []cloud.UiNode{totp}}, "totp", true, &out))
+		assert.Equal(t, map[string]interface{}{"method": "totp", "totp_code": "123456"}, out)
+		assert.Equal(t, "totp_code: ", stderr.String())
+	})
+}
+
+func TestFormMessages(t *testing.T) {
+	load := func(t *testing.T, name string) cloud.UiContainer {
+		raw, err := os.ReadFile(filepath.Join("fixtures", "flows", name))
+		require.NoError(t, err)
+		var flow struct {
+			UI cloud.UiContainer `json:"ui"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &flow))
+		return flow.UI
+	}
+
+	t.Run("case=login with invalid credentials", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"The provided credentials are invalid, check for spelling mistakes in your password or username, email address, or phone number.",
+		}, formMessages(load(t, "login_invalid_credentials.json")))
+	})
+
+	t.Run("case=registration with messages of the flow and a field", func(t *testing.T) {
+		ui := load(t, "registration_rejected.json")
+		assert.Equal(t, []string{
+			"An account with the same identifier (email, phone, username, ...) exists already.",
+		}, flowMessages(ui), "the messages of the fields are printed above their prompts")
+
+		messages := formMessages(ui)
+		assert.Equal(t, []string{
+			"An account with the same identifier (email, phone, username, ...) exists already.",
+			"Password: The password can not be used because the password has been found in data breaches and must no longer be used.",
+		}, messages)
+
+		assert.EqualError(t, formAttemptsError("create the account", messages), "unable to create the account after 3 attempts: "+strings.Join(messages, " "))
+	})
+
+	t.Run("case=no messages", func(t *testing.T) {
+		assert.Empty(t, formMessages(cloud.UiContainer{}))
+		assert.EqualError(t, formAttemptsError("sign in", nil), "unable to sign in after 3 attempts")
+	})
+}
+
+func TestNewPasswordReader(t *testing.T) {
+	t.Run("case=reads the lines of scripted input", func(t *testing.T) {
+		read := newPasswordReader(bufio.NewReader(strings.NewReader("s3cr3t-password\r\nsecond password\nlast")), nil)
+		for _, expected := range []string{"s3cr3t-password", "second password", "last"} {
+			pw, err := read()
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(pw))
+		}
+		_, err := read()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("case=files which are no terminal are read line by line", func(t *testing.T) {
+		f, err := os.Open(os.DevNull)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+		_, err = newPasswordReader(bufio.NewReader(f), f)()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
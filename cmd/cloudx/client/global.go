@@ -0,0 +1,42 @@
+package client
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// EnableGlobalFlags registers the flags which all commands share on the root command and applies them to all its sub
+// commands. It wraps the commands, so it must be called after all sub commands were added.
+//
+// Both the ory command and the cloud command of the other CLIs use it, so that tests run the same command tree as
+// the released binary.
+func EnableGlobalFlags(root *cobra.Command) {
+	f := root.PersistentFlags()
+	RegisterVerboseFlag(f)
+	RegisterDebugFlag(f)
+	RegisterLogFlags(f)
+	RegisterNoColorFlag(f)
+	RegisterNoPagerFlag(f)
+	RegisterDryRunFlag(f)
+	RegisterUTCFlag(f)
+	RegisterRetryFlags(f)
+	RegisterTimeoutFlag(f)
+	RegisterProxyFlag(f)
+	RegisterEndpointsFlag(f)
+	RegisterTLSFlags(f)
+	RegisterCompressionFlag(f)
+	RegisterTimingsFlag(f)
+	RegisterNoCacheFlag(f)
+
+	EnableSortFlag(root)
+	EnableColumnsFlag(root)
+	EnableIDOnlyFlag(root)
+	EnableTemplateFormat(root)
+	EnablePager(root)
+	EnableQueryFlag(root)
+	EnableDryRunFlag(root)
+	EnableTimings(root)
+	EnableStructuredErrors(root)
+	EnableExitCodeFlag(root)
+	EnableSuggestions(root)
+	EnableGroupedHelp(root)
+}
@@ -15,7 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"syscall"
+	"time"
 
 	"github.com/gofrs/uuid/v3"
 	"github.com/imdario/mergo"
@@ -23,12 +23,12 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/tidwall/gjson"
-	"golang.org/x/term"
 
 	cloud "github.com/ory/client-go"
 	"github.com/ory/x/cmdx"
 	"github.com/ory/x/flagx"
 	"github.com/ory/x/jsonx"
+	"github.com/ory/x/stringslice"
 	"github.com/ory/x/stringsx"
 )
 
@@ -38,21 +38,33 @@ const (
 	osEnvVar   = "ORY_CLOUD_CONFIG_PATH"
 	Version    = "v0alpha0"
 	yesFlag    = "yes"
+
+	VerboseFlag = "verbose"
 )
 
 func RegisterConfigFlag(f *pflag.FlagSet) {
 	f.StringP(ConfigFlag, ConfigFlag[:1], "", "Path to the Ory Cloud configuration file.")
+	RegisterStrictConfigFlag(f)
+	RegisterSessionTokenFlag(f)
 }
 
 func RegisterYesFlag(f *pflag.FlagSet) {
 	f.BoolP(yesFlag, yesFlag[:1], false, "Confirm all dialogs with yes.")
 }
 
+// RegisterVerboseFlag registers the flag which enables verbose output, e.g. the rate limits of API responses.
+func RegisterVerboseFlag(f *pflag.FlagSet) {
+	f.Bool(VerboseFlag, false, "Print additional information such as rate limits of API responses. Same as --log-level debug.")
+}
+
+// AuthContext is the configuration file. Its JSON Schema is derived from the fields, see ConfigSchema.
 type AuthContext struct {
-	Version         string       `json:"version"`
-	SessionToken    string       `json:"session_token"`
-	SelectedProject uuid.UUID    `json:"selected_project"`
-	IdentityTraits  AuthIdentity `json:"session_identity_traits"`
+	Version         string       `json:"version" description:"The version of the format of the configuration file."`
+	SessionToken    string       `json:"session_token" description:"The token of the Ory Cloud session, set by signing in."`
+	SelectedProject uuid.UUID    `json:"selected_project" description:"The ID of the project which commands use unless --project is set."`
+	IdentityTraits  AuthIdentity `json:"session_identity_traits" description:"The Ory Cloud account which is signed in."`
+	// Endpoints are the URLs of self-hosted services, see RegisterEndpointsFlag.
+	Endpoints Endpoints `json:"endpoints,omitempty" description:"The URLs of self-hosted services, see --endpoints."`
 }
 
 func (i *AuthContext) ID() string {
@@ -76,8 +88,8 @@ func (i *AuthContext) Interface() interface{} {
 }
 
 type AuthIdentity struct {
-	ID    uuid.UUID
-	Email string `json:"email"`
+	ID    uuid.UUID `description:"The ID of the account."`
+	Email string    `json:"email" description:"The email address of the account."`
 }
 
 type AuthProject struct {
@@ -87,6 +99,40 @@ type AuthProject struct {
 
 var ErrNoConfig = stderrs.New("no ory configuration file present")
 var ErrNoConfigQuiet = stderrs.New("please run `ory auth` to initialize your configuration or remove the `--quiet` flag")
+var ErrSessionExpired = stderrs.New("Your session has expired")
+var ErrNotSignedIn = stderrs.New("you are not signed in, run `ory auth` to sign in")
+
+// NotAuthenticatedError is returned if a command requires a session, but the user is not signed in and unable to sign
+// in because prompts are disabled, e.g. by --quiet or because stdin is not a terminal. It unwraps to ErrNotSignedIn,
+// or ErrNoConfigQuiet if there is no configuration file and --quiet is set, so that it exits with ExitNotAuthenticated.
+type NotAuthenticatedError struct {
+	// Reason explains why the user is unable to sign in.
+	Reason string
+
+	err error
+}
+
+func (e *NotAuthenticatedError) Error() string {
+	return fmt.Sprintf("you are not signed in and unable to sign in because %s, please %s", e.Reason, signInAlternatives())
+}
+
+func (e *NotAuthenticatedError) Unwrap() error {
+	return e.err
+}
+
+// credentialSources are the ways to provide a session without answering the prompts of signing in. They are listed
+// whenever signing in would require input which can not be given.
+func credentialSources() []string {
+	return []string{
+		"run `ory auth` in a terminal to sign in",
+		fmt.Sprintf("set %s or --%s to a session token", SessionTokenEnv, SessionTokenFlag),
+		fmt.Sprintf("set %s or --%s to the configuration file of a signed in CLI", osEnvVar, ConfigFlag),
+	}
+}
+
+func signInAlternatives() string {
+	return strings.Join(credentialSources(), ", or ")
+}
 
 func getConfigPath(cmd *cobra.Command) (string, error) {
 	path, err := os.UserHomeDir()
@@ -102,15 +148,62 @@ func getConfigPath(cmd *cobra.Command) (string, error) {
 }
 
 type CommandHelper struct {
-	Ctx              context.Context
-	VerboseWriter    io.Writer
+	Ctx context.Context
+	// VerboseWriter is the same as VerboseErrWriter. Stdout is reserved for the data printed by commands, so that it
+	// can always be piped into other programs.
+	VerboseWriter io.Writer
+	// VerboseErrWriter receives prompts, warnings, and other informational messages. It is stderr, or discards
+	// everything if the --quiet flag is set.
 	VerboseErrWriter io.Writer
-	ConfigLocation   string
-	NoConfirm        bool
-	IsQuiet          bool
-	APIDomain        *url.URL
-	Stdin            *bufio.Reader
-	PwReader         passwordReader
+	// Log receives log messages, see RegisterLogFlags. Prompts are written to VerboseErrWriter instead.
+	Log            *Logger
+	ConfigLocation string
+	// NoConfirm is true if the --yes flag is set. It answers confirmations with yes, see Confirm, but never any other
+	// prompt, so that signing in still requires a terminal or one of the credentialSources.
+	NoConfirm bool
+	IsQuiet   bool
+	// NonInteractive is true if the user is unable to answer prompts, see promptsDisabledReason.
+	NonInteractive bool
+	APIDomain      *url.URL
+	Stdin          *bufio.Reader
+	PwReader       passwordReader
+	Project        string
+	Verbose        bool
+	Colors         Colors
+	// DryRun is true if the --dry-run flag is set. The API clients refuse to send requests changing resources then.
+	DryRun bool
+
+	// terminal is stdin if it is a file. It is used to render the interactive selector.
+	terminal *os.File
+	// debugLog, if set, receives all HTTP requests and responses, see RegisterDebugFlag.
+	debugLog *debugLog
+	// timeoutSet is true if the timeout of API requests was set explicitly, see RegisterTimeoutFlag.
+	timeoutSet bool
+	// client creates the API clients of the command.
+	client *Client
+	// promptsDisabled explains why NonInteractive is set.
+	promptsDisabled string
+	// spinner is true if WithSpinner may render a spinner, spinning is set while it does.
+	spinner  bool
+	spinning int32
+	// prefetchProjects is true if the command lists the projects, see MarkProjectsNeeded. projects lists them while
+	// the session is checked.
+	prefetchProjects bool
+	projects         *projectsFetch
+	// cache caches the responses of read-only requests, it is nil if --no-cache is set.
+	cache *responseCache
+	// endpoints are the URLs of self-hosted services, see Endpoints.
+	endpoints Endpoints
+	// strictConfig is true if the configuration file is validated against its JSON Schema, see StrictConfigFlag.
+	strictConfig bool
+	// sessionToken is set by SessionTokenFlag or SessionTokenEnv, which sessionTokenSource names. It takes precedence
+	// over the session of the configuration file, see tokenContext. tokenAuth is its configuration once checked.
+	sessionToken       string
+	sessionTokenSource string
+	tokenAuth          *AuthContext
+	// checkedSessionToken is the session token of the configuration file once it was checked, so that a command
+	// which calls EnsureContext several times, e.g. through NewProjectAPI, checks the session only once.
+	checkedSessionToken string
 }
 
 type PasswordReader struct{}
@@ -122,42 +215,116 @@ func NewCommandHelper(cmd *cobra.Command) (*CommandHelper, error) {
 		return nil, err
 	}
 
-	var out = cmd.OutOrStdout()
-	if flagx.MustGetBool(cmd, cmdx.FlagQuiet) {
-		out = io.Discard
-	}
-
 	var outErr = cmd.ErrOrStderr()
 	if flagx.MustGetBool(cmd, cmdx.FlagQuiet) {
 		outErr = io.Discard
 	}
 
-	pwReader := func() ([]byte, error) {
-		return term.ReadPassword(int(syscall.Stdin))
-	}
+	stdin := bufio.NewReader(cmd.InOrStdin())
+	terminal, _ := cmd.InOrStdin().(*os.File)
+	pwReader := newPasswordReader(stdin, terminal)
 	if p, ok := cmd.Context().Value(PasswordReader{}).(passwordReader); ok {
 		pwReader = p
 	}
 
+	var project string
+	if f := cmd.Flags().Lookup(projectFlag); f != nil {
+		project = f.Value.String()
+	}
+
+	var verbose bool
+	if f := cmd.Flags().Lookup(VerboseFlag); f != nil {
+		verbose = f.Value.String() == "true"
+	}
+
+	sessionToken, sessionTokenSource := sessionTokenFromCmd(cmd)
+
+	var strictConfig bool
+	if f := cmd.Flags().Lookup(StrictConfigFlag); f != nil {
+		strictConfig = f.Value.String() == "true"
+	}
+
+	promptsDisabled := promptsDisabledReason(cmd.InOrStdin(), os.Getenv)
+
+	log, err := NewLogger(cmd)
+	if err != nil {
+		return nil, err
+	}
+	maxRetries, err := MaxRetries(cmd)
+	if err != nil {
+		return nil, err
+	}
+	timeout, timeoutSet, err := RequestTimeout(cmd)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := RequestRate(cmd)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := NewTransport(cmd, consoleURL())
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := readEndpointsFlag(cmd)
+	if err != nil {
+		return nil, err
+	}
+	debug := newDebugLog(cmd)
+	apiClient := newClient(transport, clientConfig{
+		debug:       debug,
+		maxRetries:  maxRetries,
+		timeout:     timeout,
+		limiter:     newLimiter(rate, time.Now),
+		commandPath: cmd.CommandPath(),
+		compress:    IsCompressionEnabled(cmd),
+		dryRun:      IsDryRun(cmd),
+		fixtures:    currentFixtures(),
+	})
+	ctx := contextWithDebugLog(contextWithLogger(contextWithTimeout(contextWithMaxRetries(cmd.Context(), maxRetries), timeout), log), debug)
+
 	return &CommandHelper{
-		ConfigLocation:   location,
-		NoConfirm:        flagx.MustGetBool(cmd, yesFlag),
-		IsQuiet:          flagx.MustGetBool(cmd, cmdx.FlagQuiet),
-		VerboseWriter:    out,
-		VerboseErrWriter: outErr,
-		Stdin:            bufio.NewReader(cmd.InOrStdin()),
-		Ctx:              cmd.Context(),
-		PwReader:         pwReader,
+		ConfigLocation:     location,
+		NoConfirm:          flagx.MustGetBool(cmd, yesFlag),
+		IsQuiet:            flagx.MustGetBool(cmd, cmdx.FlagQuiet),
+		NonInteractive:     promptsDisabled != "",
+		VerboseWriter:      outErr,
+		VerboseErrWriter:   outErr,
+		Log:                log,
+		Stdin:              stdin,
+		Ctx:                ctx,
+		PwReader:           pwReader,
+		Project:            project,
+		Verbose:            verbose,
+		Colors:             NewColors(cmd, cmd.ErrOrStderr()),
+		DryRun:             IsDryRun(cmd),
+		terminal:           terminal,
+		debugLog:           debug,
+		timeoutSet:         timeoutSet,
+		client:             apiClient,
+		promptsDisabled:    promptsDisabled,
+		spinner:            isTerminalWriter(outErr) && !IsMachineReadableFormat(cmd),
+		prefetchProjects:   needsProjects(cmd, project),
+		cache:              newResponseCache(cmd, location),
+		endpoints:          endpoints,
+		strictConfig:       strictConfig,
+		sessionToken:       sessionToken,
+		sessionTokenSource: sessionTokenSource,
 	}, nil
 }
 
+// SetDefaultProject selects the project which commands use unless --project is set. It returns ErrSessionNotStored if
+// the session is set by a session token, because the configuration file is not changed then.
 func (h *CommandHelper) SetDefaultProject(id string) error {
+	if h.sessionToken != "" {
+		return h.errSessionNotStored()
+	}
 	conf, err := h.readConfig()
 	if err != nil {
 		return err
 	}
 
-	uid, err := uuid.FromString(id)
+	uid, err := parseAPIUUID("project ID", id)
 	if err != nil {
 		return err
 	}
@@ -168,6 +335,7 @@ func (h *CommandHelper) SetDefaultProject(id string) error {
 
 func (h *CommandHelper) WriteConfig(c *AuthContext) error {
 	c.Version = Version
+	h.Log.Debugf("Writing the configuration to %s", h.ConfigLocation)
 	file, err := os.OpenFile(h.ConfigLocation, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return errors.Wrapf(err, "unable to open file for writing at location: %s", file.Name())
@@ -182,6 +350,7 @@ func (h *CommandHelper) WriteConfig(c *AuthContext) error {
 }
 
 func (h *CommandHelper) readConfig() (*AuthContext, error) {
+	h.Log.Debugf("Reading the configuration from %s", h.ConfigLocation)
 	contents, err := os.ReadFile(h.ConfigLocation)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
@@ -189,6 +358,11 @@ func (h *CommandHelper) readConfig() (*AuthContext, error) {
 		}
 		return nil, errors.Wrapf(err, "unable to open ory config file location: %s", h.ConfigLocation)
 	}
+	if h.strictConfig {
+		if err := validateConfig(h.ConfigLocation, contents); err != nil {
+			return nil, err
+		}
+	}
 
 	var c AuthContext
 	if err := json.Unmarshal(contents, &c); err != nil {
@@ -198,47 +372,53 @@ func (h *CommandHelper) readConfig() (*AuthContext, error) {
 	return &c, nil
 }
 
+// EnsureContext returns the configuration of the signed in user. A valid session is used without prompting. If the
+// session expired or the user is not signed in, the user is asked to sign in, unless prompts are disabled: then a
+// NotAuthenticatedError, or ErrSessionExpired, is returned instead of waiting for input which never arrives. It never
+// returns a nil configuration without an error, so that callers can use the configuration once err is nil. The
+// session set by --session-token or ORY_SESSION_TOKEN takes precedence over the configuration file, see tokenContext.
 func (h *CommandHelper) EnsureContext() (*AuthContext, error) {
+	if h.sessionToken != "" {
+		return h.tokenContext()
+	}
+
 	c, err := h.readConfig()
-	if err != nil {
-		if errors.Is(err, ErrNoConfig) {
-			if h.IsQuiet {
-				return nil, ErrNoConfigQuiet
-			}
-			// Continue to sign in
-		} else {
-			return nil, err
-		}
+	if err != nil && !errors.Is(err, ErrNoConfig) {
+		return nil, err
 	}
 
 	if len(c.SessionToken) > 0 {
-		client, err := NewKratosClient()
+		if c.SessionToken == h.checkedSessionToken {
+			return c, nil
+		}
+		if h.prefetchProjects && h.projects == nil {
+			h.projects = h.fetchProjects(c.SessionToken)
+		}
+		client := h.client.authAPI()
+		sess, _, err := client.V0alpha2Api.ToSession(h.Ctx).XSessionToken(c.SessionToken).Execute()
+		if sess != nil && err == nil {
+			h.Log.Infof("You are authenticated as: %s", c.IdentityTraits.Email)
+			h.checkedSessionToken = c.SessionToken
+			return c, nil
+		}
+
+		h.projects = nil
+		if h.IsQuiet {
+			return nil, errors.WithStack(fmt.Errorf("%w and you cannot reauthenticate when the --quiet flag is set", ErrSessionExpired))
+		} else if !h.isInteractive() {
+			return nil, errors.WithStack(fmt.Errorf("%w and you cannot reauthenticate because %s, please %s", ErrSessionExpired, h.promptsDisabled, signInAlternatives()))
+		}
+		ok, err := h.Confirm(fmt.Sprintf("Your CLI session has expired. Do you wish to log in again as \"%s\"?", c.IdentityTraits.Email))
 		if err != nil {
 			return nil, err
+		} else if !ok {
+			return nil, errors.WithStack(ErrSessionExpired)
 		}
-		sess, _, err := client.V0alpha2Api.ToSession(h.Ctx).XSessionToken(c.SessionToken).Execute()
-		if sess == nil || err != nil {
-			if h.IsQuiet {
-				return nil, errors.New("Your session has expired and you cannot reauthenticate when the --quiet flag is set")
-			}
-			ok, err := cmdx.AskScannerForConfirmation(fmt.Sprintf("Your CLI session has expired. Do you wish to log in again as \"%s\"?", c.IdentityTraits.Email), h.Stdin, h.VerboseErrWriter)
-			if err != nil {
-				return nil, err
-			}
-			if ok {
-				if err := h.SignOut(); err != nil {
-					return nil, err
-				}
-				c, err := h.Authenticate()
-				if err != nil {
-					return nil, err
-				}
-				return c, nil
-			}
-			return nil, errors.New("Your session has expired")
+		if err := h.SignOut(); err != nil {
+			return nil, err
 		}
-		_, _ = fmt.Fprintf(h.VerboseErrWriter, "You are authenticated as: %s\n", c.IdentityTraits.Email)
-		return c, nil
+	} else if !h.isInteractive() {
+		return nil, h.notAuthenticated(errors.Is(err, ErrNoConfig))
 	}
 
 	c, err = h.Authenticate()
@@ -246,20 +426,60 @@ func (h *CommandHelper) EnsureContext() (*AuthContext, error) {
 		return nil, err
 	}
 
-	if len(c.SessionToken) == 0 {
-		return nil, errors.Errorf("unable to authenticate")
+	if c == nil || len(c.SessionToken) == 0 {
+		return nil, errors.WithStack(ErrNotSignedIn)
 	}
 
+	h.checkedSessionToken = c.SessionToken
 	return c, nil
 }
 
-func (h *CommandHelper) getField(i interface{}, path string) (*gjson.Result, error) {
-	var b bytes.Buffer
-	if err := json.NewEncoder(&b).Encode(i); err != nil {
+// notAuthenticated returns the NotAuthenticatedError of users who are not signed in and unable to answer the prompts
+// of signing in.
+func (h *CommandHelper) notAuthenticated(noConfig bool) error {
+	e := &NotAuthenticatedError{Reason: h.promptsDisabled, err: ErrNotSignedIn}
+	if h.IsQuiet {
+		e.Reason = "the --quiet flag is set"
+		if noConfig {
+			e.err = ErrNoConfigQuiet
+		}
+	}
+	return errors.WithStack(e)
+}
+
+// RequireSession returns the configuration if the user is signed in and the session is valid. Unlike EnsureContext,
+// it never prompts the user to sign in.
+func (h *CommandHelper) RequireSession() (*AuthContext, error) {
+	if h.sessionToken != "" {
+		return h.tokenContext()
+	}
+	c, err := h.readConfig()
+	if errors.Is(err, ErrNoConfig) || err == nil && len(c.SessionToken) == 0 {
+		return nil, errors.WithStack(ErrNotSignedIn)
+	} else if err != nil {
+		return nil, err
+	}
+	if c.SessionToken == h.checkedSessionToken {
+		return c, nil
+	}
+	if _, err := h.checkSession(h.Ctx, c.SessionToken); err != nil {
 		return nil, err
 	}
-	result := gjson.GetBytes(b.Bytes(), path)
-	return &result, nil
+	h.checkedSessionToken = c.SessionToken
+	return c, nil
+}
+
+// checkSession returns the session of the token. Expired sessions yield ErrSessionExpired.
+func (h *CommandHelper) checkSession(ctx context.Context, token string) (*cloud.Session, error) {
+	sess, res, err := h.client.authAPI().V0alpha2Api.ToSession(ctx).XSessionToken(token).Execute()
+	if err != nil {
+		var sdkErr openAPIError
+		if errors.As(err, &sdkErr) && statusCodeFromSDKError(sdkErr) == http.StatusUnauthorized {
+			return nil, errors.WithStack(fmt.Errorf("%w, run `ory auth` to sign in again", ErrSessionExpired))
+		}
+		return nil, handleError("unable to check the session", res, err)
+	}
+	return sess, nil
 }
 
 func (h *CommandHelper) signup(c *cloud.APIClient) (*AuthContext, error) {
@@ -268,15 +488,18 @@ func (h *CommandHelper) signup(c *cloud.APIClient) (*AuthContext, error) {
 		return nil, err
 	}
 
-	var isRetry bool
+	var attempts int
 retryRegistration:
-	if isRetry {
-		_, _ = fmt.Fprintf(h.VerboseErrWriter, "\nYour account creation attempt failed. Please try again!\n\n")
+	if attempts++; attempts > 1 {
+		if attempts > maxFormAttempts {
+			return nil, formAttemptsError("create the account", formMessages(flow.Ui))
+		}
+		h.Log.Warnf("Your account creation attempt failed. Please try again!")
+		h.printFormMessages(flowMessages(flow.Ui))
 	}
-	isRetry = true
 
 	var form cloud.SubmitSelfServiceRegistrationFlowWithPasswordMethodBody
-	if err := renderForm(h.Stdin, h.PwReader, h.VerboseErrWriter, flow.Ui, "password", &form); err != nil {
+	if err := renderForm(h.Stdin, h.PwReader, h.VerboseErrWriter, flow.Ui, "password", h.confirmForms(), &form); err != nil {
 		return nil, err
 	}
 
@@ -308,7 +531,45 @@ retryRegistration:
 	return h.sessionToContext(sess, sessionToken)
 }
 
-func (h *CommandHelper) signin(c *cloud.APIClient, sessionToken string) (*AuthContext, error) {
+// maxAAL2Attempts bounds how often the second factor is asked for while the session still requires it, e.g. because
+// the clock of the device generating TOTP codes is skewed.
+const maxAAL2Attempts = 3
+
+// aal2Methods are the second factors which the CLI supports.
+var aal2Methods = []string{"totp", "lookup_secret"}
+
+// signin signs the user in with a password and, if the account requires it, a second factor. The session token of
+// the first factor is kept while the second factor is asked for.
+func (h *CommandHelper) signin(c *cloud.APIClient) (*AuthContext, error) {
+	sessionToken, err := h.submitLogin(c, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		sess, _, err := c.V0alpha2Api.ToSession(h.Ctx).XSessionToken(sessionToken).Execute()
+		if err == nil {
+			return h.sessionToContext(sess, sessionToken)
+		}
+		e, ok := err.(*cloud.GenericOpenAPIError)
+		if !ok || gjson.GetBytes(e.Body(), "error.id").String() != "session_aal2_required" {
+			return nil, err
+		} else if attempt > maxAAL2Attempts {
+			return nil, errors.Errorf("the session still requires a second factor after %d attempts, check that the clock of the device generating your codes is correct", maxAAL2Attempts)
+		}
+
+		token, err := h.submitLogin(c, sessionToken)
+		if err != nil {
+			return nil, err
+		}
+		sessionToken = stringsx.Coalesce(token, sessionToken)
+	}
+}
+
+// submitLogin asks for the credentials and submits them until they are accepted. Without a session token, it asks for
+// the password, and with one for the second factor. It returns the session token of the response, which is empty if
+// the session token was upgraded in place.
+func (h *CommandHelper) submitLogin(c *cloud.APIClient, sessionToken string) (string, error) {
 	req := c.V0alpha2Api.InitializeSelfServiceLoginFlowWithoutBrowser(h.Ctx)
 	if len(sessionToken) > 0 {
 		req = req.XSessionToken(sessionToken).Aal("aal2")
@@ -316,47 +577,47 @@ func (h *CommandHelper) signin(c *cloud.APIClient, sessionToken string) (*AuthCo
 
 	flow, _, err := req.Execute()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	var isRetry bool
+	var attempts int
 retryLogin:
-	if isRetry {
-		_, _ = fmt.Fprintf(h.VerboseErrWriter, "\nYour sign in attempt failed. Please try again!\n\n")
+	if attempts++; attempts > 1 {
+		if attempts > maxFormAttempts {
+			return "", formAttemptsError("sign in", formMessages(flow.Ui))
+		}
+		h.Log.Warnf("Your sign in attempt failed. Please try again!")
+		h.printFormMessages(flowMessages(flow.Ui))
 	}
-	isRetry = true
 
 	var form interface{} = &cloud.SubmitSelfServiceLoginFlowWithPasswordMethodBody{}
 	method := "password"
 	if len(sessionToken) > 0 {
-		var foundTOTP bool
-		var foundLookup bool
-		for _, n := range flow.Ui.Nodes {
-			if n.Group == "totp" {
-				foundTOTP = true
-			} else if n.Group == "lookup_secret" {
-				foundLookup = true
-			}
-		}
-		if !foundLookup && !foundTOTP {
-			return nil, errors.New("only TOTP and lookup secrets are supported for two-step verification in the CLI")
-		}
-
-		method = "lookup_secret"
-		if foundTOTP {
+		methods := flowMethods(flow.Ui)
+		switch {
+		case stringslice.Has(methods, "totp"):
 			form = &cloud.SubmitSelfServiceLoginFlowWithTotpMethodBody{}
 			method = "totp"
+		case stringslice.Has(methods, "lookup_secret"):
+			form = &cloud.SubmitSelfServiceLoginFlowWithLookupSecretMethodBody{}
+			method = "lookup_secret"
+		case len(methods) == 0:
+			return "", errors.Errorf("the account requires a second factor, but offers none, only %s are supported in the CLI", strings.Join(aal2Methods, " and "))
+		default:
+			return "", errors.Errorf("the account requires a second factor, but only offers %s, only %s are supported in the CLI", strings.Join(methods, ", "), strings.Join(aal2Methods, " and "))
 		}
 	}
 
-	if err := renderForm(h.Stdin, h.PwReader, h.VerboseErrWriter, flow.Ui, method, form); err != nil {
-		return nil, err
+	if err := renderForm(h.Stdin, h.PwReader, h.VerboseErrWriter, flow.Ui, method, h.confirmForms(), form); err != nil {
+		return "", err
 	}
 
 	var body cloud.SubmitSelfServiceLoginFlowBody
 	switch e := form.(type) {
 	case *cloud.SubmitSelfServiceLoginFlowWithTotpMethodBody:
 		body.SubmitSelfServiceLoginFlowWithTotpMethodBody = e
+	case *cloud.SubmitSelfServiceLoginFlowWithLookupSecretMethodBody:
+		body.SubmitSelfServiceLoginFlowWithLookupSecretMethodBody = e
 	case *cloud.SubmitSelfServiceLoginFlowWithPasswordMethodBody:
 		body.SubmitSelfServiceLoginFlowWithPasswordMethodBody = e
 	default:
@@ -377,26 +638,48 @@ retryLogin:
 			}
 		}
 
-		return nil, errors.WithStack(err)
+		return "", errors.WithStack(err)
 	}
 
-	sessionToken = stringsx.Coalesce(*login.SessionToken, sessionToken)
-	sess, _, err := c.V0alpha2Api.ToSession(h.Ctx).XSessionToken(sessionToken).Execute()
-	if err == nil {
-		return h.sessionToContext(sess, sessionToken)
+	return login.GetSessionToken(), nil
+}
+
+// printFormMessages prints why the server rejected a form before it is asked for again. The messages of the fields are
+// printed above their prompts, see renderForm.
+func (h *CommandHelper) printFormMessages(messages []string) {
+	for _, m := range messages {
+		_, _ = fmt.Fprintln(h.VerboseErrWriter, m)
 	}
+}
 
-	if e, ok := err.(*cloud.GenericOpenAPIError); ok {
-		switch gjson.GetBytes(e.Body(), "error.id").String() {
-		case "session_aal2_required":
-			return h.signin(c, sessionToken)
+// flowMethods returns the methods which the flow offers, i.e. the groups of its nodes except the default group, in the
+// order of the nodes.
+func flowMethods(ui cloud.UiContainer) []string {
+	var methods []string
+	for _, n := range ui.Nodes {
+		if n.Group != "default" && !stringslice.Has(methods, n.Group) {
+			methods = append(methods, n.Group)
 		}
 	}
-	return nil, err
+	return methods
+}
+
+// parseAPIUUID parses a UUID returned by the API. Unlike uuid.FromStringOrNil, it fails if the value is not a UUID,
+// so that a malformed response is never stored as the zero UUID. What describes the value in the error.
+func parseAPIUUID(what, value string) (uuid.UUID, error) {
+	id, err := uuid.FromString(value)
+	if err != nil {
+		return uuid.Nil, errors.Errorf("the API returned the %s %q, which is not a valid UUID", what, value)
+	}
+	return id, nil
 }
 
 func (h *CommandHelper) sessionToContext(session *cloud.Session, token string) (*AuthContext, error) {
-	email, err := h.getField(session.Identity.Traits, "email")
+	email, found := identityEmail(session.Identity)
+	if !found {
+		h.Log.Debugf("Unable to find the email address of identity %s in the traits at %s or in its verifiable addresses, showing its traits instead", session.Identity.Id, strings.Join(emailTraitPaths, ", "))
+	}
+	id, err := parseAPIUUID("identity ID of the session", session.Identity.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -405,113 +688,157 @@ func (h *CommandHelper) sessionToContext(session *cloud.Session, token string) (
 		Version:      Version,
 		SessionToken: token,
 		IdentityTraits: AuthIdentity{
-			Email: email.String(),
-			ID:    uuid.FromStringOrNil(session.Identity.Id),
+			Email: email,
+			ID:    id,
 		},
 	}, nil
 }
 
+// Authenticate signs the user in or up and writes the session to the configuration file. If the session is set by
+// --session-token or ORY_SESSION_TOKEN, it is checked and returned instead, without prompting or writing anything.
 func (h *CommandHelper) Authenticate() (*AuthContext, error) {
-	if h.IsQuiet {
-		return nil, errors.New("can not sign in or sign up when flag --quiet is set")
+	ac, _, err := h.authenticate()
+	return ac, err
+}
+
+// SignIn is like Authenticate but also selects the project which commands use by default once the user signed in or
+// up. It is used by `ory auth`; commands which sign in again because the session expired do not ask for a project.
+func (h *CommandHelper) SignIn() (*AuthContext, error) {
+	ac, signedIn, err := h.authenticate()
+	if err != nil {
+		return nil, err
+	}
+	if signedIn {
+		h.selectProjectAfterSignIn(ac)
+	}
+	return ac, nil
+}
+
+// authenticate implements Authenticate and reports whether the user signed in or up, as opposed to keeping the
+// existing session.
+func (h *CommandHelper) authenticate() (_ *AuthContext, signedIn bool, _ error) {
+	if h.sessionToken != "" {
+		ac, err := h.tokenContext()
+		return ac, false, err
+	}
+
+	if err := h.RequireInteractive("Do you already have an Ory Console account you wish to use?", signInAlternatives()); err != nil {
+		return nil, false, err
 	}
 
 	ac, err := h.readConfig()
 	if err != nil {
 		if !errors.Is(err, ErrNoConfig) {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
+	// The endpoints of self-hosted services are kept when signing in with another account.
+	endpoints := ac.Endpoints
+
 	if len(ac.SessionToken) > 0 {
-		if !h.NoConfirm {
-			ok, err := cmdx.AskScannerForConfirmation(fmt.Sprintf("You are signed in as \"%s\" already. Do you wish to authenticate with another account?", ac.IdentityTraits.Email), h.Stdin, h.VerboseErrWriter)
-			if err != nil {
-				return nil, err
-			} else if !ok {
-				return ac, nil
-			}
-			_, _ = fmt.Fprintf(h.VerboseErrWriter, "Ok, signing you out!\n")
+		ok, err := h.Confirm(fmt.Sprintf("You are signed in as \"%s\" already. Do you wish to authenticate with another account?", ac.IdentityTraits.Email))
+		if err != nil {
+			return nil, false, err
+		} else if !ok {
+			return ac, false, nil
 		}
+		h.Log.Infof("Ok, signing you out!")
 
 		if err := h.SignOut(); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
-	c, err := NewKratosClient()
-	if err != nil {
-		return nil, err
-	}
+	c := h.client.authAPI()
 
 	signIn, err := cmdx.AskScannerForConfirmation("Do you already have an Ory Console account you wish to use?", h.Stdin, h.VerboseErrWriter)
 	if err != nil {
-		return nil, err
-	}
-
-	var retry bool
-	if retry {
-		_, _ = fmt.Fprintln(h.VerboseErrWriter, "Unable to Authenticate you, please try again.")
+		return nil, false, err
 	}
 
 	if signIn {
-		ac, err = h.signin(c, "")
+		ac, err = h.signin(c)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	} else {
-		_, _ = fmt.Fprintln(h.VerboseErrWriter, "Great to have you here, creating an Ory Cloud account is absolutely free and only requires to answer four easy questions.")
+		h.Log.Infof("Great to have you here, creating an Ory Cloud account is absolutely free and only requires to answer four easy questions.")
 
 		ac, err = h.signup(c)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 	}
 
+	ac.Endpoints = endpoints
 	if err := h.WriteConfig(ac); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	_, _ = fmt.Fprintf(h.VerboseErrWriter, "You are now signed in as: %s\n", ac.IdentityTraits.Email)
-
-	return ac, nil
+	h.Log.Infof("You are now signed in as: %s", ac.IdentityTraits.Email)
+	return ac, true, nil
 }
 
+// SignOut removes the session from the configuration. The endpoints of self-hosted services are kept. It returns
+// ErrSessionNotStored if the session is set by a session token, which is never stored.
 func (h *CommandHelper) SignOut() error {
-	return h.WriteConfig(new(AuthContext))
+	if h.sessionToken != "" {
+		return h.errSessionNotStored()
+	}
+	h.forgetProjects()
+	c, err := h.readConfig()
+	if err != nil && !errors.Is(err, ErrNoConfig) {
+		return err
+	}
+	return h.WriteConfig(&AuthContext{Endpoints: c.Endpoints})
 }
 
 func (h *CommandHelper) ListProjects() ([]cloud.ProjectMetadata, error) {
+	if err := h.requireCloud(); err != nil {
+		return nil, err
+	}
 	ac, err := h.EnsureContext()
 	if err != nil {
 		return nil, err
 	}
 
-	c, err := newCloudClient(ac.SessionToken)
-	if err != nil {
-		return nil, err
+	// Use the projects fetched while the session was checked, but only once.
+	f := h.projects
+	h.projects, h.prefetchProjects = nil, false
+	if f == nil || f.token != ac.SessionToken {
+		f = h.fetchProjects(ac.SessionToken)
 	}
 
-	projects, res, err := c.V0alpha2Api.ListProjects(h.Ctx).Execute()
+	var projects []cloud.ProjectMetadata
+	err = h.WithSpinner("Listing projects", func(ctx context.Context) (err error) {
+		projects, err = f.wait(ctx)
+		return err
+	})
 	if err != nil {
-		return nil, handleError("unable to list projects", res, err)
+		return nil, err
 	}
 
 	return projects, nil
 }
 
 func (h *CommandHelper) GetProject(id string) (*cloud.Project, error) {
-	ac, err := h.EnsureContext()
-	if err != nil {
+	if err := h.requireCloud(); err != nil {
 		return nil, err
 	}
-
-	c, err := newCloudClient(ac.SessionToken)
+	ac, err := h.EnsureContext()
 	if err != nil {
 		return nil, err
 	}
 
-	project, res, err := c.V0alpha2Api.GetProject(h.Ctx, id).Execute()
+	c := h.client.consoleAPI(ac.SessionToken)
+
+	var project *cloud.Project
+	var res *http.Response
+	err = h.WithSpinner("Fetching project", func(ctx context.Context) (err error) {
+		project, res, err = c.V0alpha2Api.GetProject(ctx, id).Execute()
+		return err
+	})
 	if err != nil {
 		return nil, handleError("unable to get project", res, err)
 	}
@@ -520,22 +847,30 @@ func (h *CommandHelper) GetProject(id string) (*cloud.Project, error) {
 }
 
 func (h *CommandHelper) CreateProject(name string) (*cloud.Project, error) {
-	ac, err := h.EnsureContext()
-	if err != nil {
+	if err := h.requireCloud(); err != nil {
 		return nil, err
 	}
-
-	c, err := newCloudClient(ac.SessionToken)
+	ac, err := h.EnsureContext()
 	if err != nil {
 		return nil, err
 	}
 
-	project, res, err := c.V0alpha2Api.CreateProject(h.Ctx).CreateProjectBody(*cloud.NewCreateProjectBody(strings.TrimSpace(name))).Execute()
+	c := h.client.consoleAPI(ac.SessionToken)
+
+	var project *cloud.Project
+	var res *http.Response
+	err = h.WithSpinner("Creating project", func(ctx context.Context) (err error) {
+		project, res, err = c.V0alpha2Api.CreateProject(h.WithSlowRequestTimeout(ctx)).CreateProjectBody(*cloud.NewCreateProjectBody(strings.TrimSpace(name))).Execute()
+		return err
+	})
 	if err != nil {
 		return nil, handleError("unable to list projects", res, err)
 	}
+	h.forgetProjects()
 
-	if err := h.SetDefaultProject(project.Id); err != nil {
+	if err := h.SetDefaultProject(project.Id); errors.Is(err, ErrSessionNotStored) {
+		h.Log.Debugf("Not selecting the new project: %s", err)
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -559,9 +894,9 @@ func toPatch(op string, values []string) (patches []cloud.JsonPatch, err error)
 	for _, v := range values {
 		parts := strings.SplitN(v, "=", 2)
 		if len(parts) != 2 {
-			return nil, errors.Errorf("patches must be in format of `/some/config/key=some-value` but got: %s", v)
+			return nil, NewValidationError(errors.Errorf("patches must be in format of `/some/config/key=some-value` but got: %s", v), nil)
 		} else if !gjson.Valid(parts[1]) {
-			return nil, errors.Errorf("value for %s must be valid JSON but got: %s", parts[0], parts[1])
+			return nil, NewValidationError(errors.Errorf("value for %s must be valid JSON but got: %s", parts[0], parts[1]), nil)
 		}
 
 		config, err := jsonx.EmbedSources(json.RawMessage(parts[1]), jsonx.WithIgnoreKeys("$id", "$schema"), jsonx.WithOnlySchemes("file"))
@@ -580,16 +915,16 @@ func toPatch(op string, values []string) (patches []cloud.JsonPatch, err error)
 }
 
 func (h *CommandHelper) PatchProject(id string, raw []json.RawMessage, add, replace, del []string) (*cloud.SuccessfulProjectUpdate, error) {
-	ac, err := h.EnsureContext()
-	if err != nil {
+	if err := h.requireCloud(); err != nil {
 		return nil, err
 	}
-
-	c, err := newCloudClient(ac.SessionToken)
+	ac, err := h.EnsureContext()
 	if err != nil {
 		return nil, err
 	}
 
+	c := h.client.consoleAPI(ac.SessionToken)
+
 	var patches []cloud.JsonPatch
 	for _, r := range raw {
 		config, err := jsonx.EmbedSources(r, jsonx.WithIgnoreKeys("$id", "$schema"), jsonx.WithOnlySchemes("file"))
@@ -622,24 +957,30 @@ func (h *CommandHelper) PatchProject(id string, raw []json.RawMessage, add, repl
 		patches = append(patches, cloud.JsonPatch{Op: "remove", Path: del})
 	}
 
-	res, _, err := c.V0alpha2Api.PatchProject(h.Ctx, id).JsonPatch(patches).Execute()
+	var res *cloud.SuccessfulProjectUpdate
+	err = h.WithSpinner("Updating project", func(ctx context.Context) (err error) {
+		res, _, err = c.V0alpha2Api.PatchProject(ctx, id).JsonPatch(patches).Execute()
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	// The name of the project might have changed.
+	h.forgetProjects()
 	return res, nil
 }
 
 func (h *CommandHelper) UpdateProject(id string, name string, configs []json.RawMessage) (*cloud.SuccessfulProjectUpdate, error) {
-	ac, err := h.EnsureContext()
-	if err != nil {
+	if err := h.requireCloud(); err != nil {
 		return nil, err
 	}
-
-	c, err := newCloudClient(ac.SessionToken)
+	ac, err := h.EnsureContext()
 	if err != nil {
 		return nil, err
 	}
 
+	c := h.client.consoleAPI(ac.SessionToken)
+
 	for k := range configs {
 		config, err := jsonx.EmbedSources(
 			configs[k],
@@ -692,9 +1033,15 @@ func (h *CommandHelper) UpdateProject(id string, name string, configs []json.Raw
 		payload.Name = res.Name
 	}
 
-	res, _, err := c.V0alpha2Api.UpdateProject(h.Ctx, id).UpdateProject(payload).Execute()
+	var res *cloud.SuccessfulProjectUpdate
+	err = h.WithSpinner("Updating project", func(ctx context.Context) (err error) {
+		res, _, err = c.V0alpha2Api.UpdateProject(ctx, id).UpdateProject(payload).Execute()
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	// The name of the project might have changed.
+	h.forgetProjects()
 	return res, nil
 }
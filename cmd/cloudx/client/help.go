@@ -0,0 +1,115 @@
+package client
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// The resource areas by which sub commands are grouped in the help output. Cobra v1.4 does not support command groups,
+// so the area of a command is stored in the GroupAnnotation and rendered by the usage template of EnableGroupedHelp.
+const (
+	GroupAnnotation = "group"
+
+	GroupResources   = "resources"
+	GroupAuth        = "auth"
+	GroupProject     = "project"
+	GroupIdentity    = "identity"
+	GroupOAuth2      = "oauth2"
+	GroupPermissions = "permissions"
+)
+
+// commandGroups are the titles of the groups in the order they appear in the help output.
+var commandGroups = []struct{ ID, Title string }{
+	{GroupResources, "Resource Commands:"},
+	{GroupAuth, "Authentication Commands:"},
+	{GroupProject, "Project Commands:"},
+	{GroupIdentity, "Identity Commands:"},
+	{GroupOAuth2, "OAuth2 Commands:"},
+	{GroupPermissions, "Permission Commands:"},
+}
+
+// GettingStartedHelp documents the first steps in the help of the root command.
+const GettingStartedHelp = `Getting started:
+  1. Sign in or create an account:  ory auth
+  2. Select the default project:    ory use project
+  3. Show the project:              ory get project <project-id-or-slug> --format yaml`
+
+// AddGroupedCommands adds the sub commands to cmd and shows them in the given group of its help output.
+func AddGroupedCommands(cmd *cobra.Command, group string, cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[GroupAnnotation] = group
+	}
+	cmd.AddCommand(cmds...)
+}
+
+// helpSection is a group of sub commands in the help output.
+type helpSection struct {
+	Title    string
+	Commands []*cobra.Command
+}
+
+// helpSections groups the available sub commands of cmd. Commands without a group are listed last, as "Additional
+// Commands" if there are any groups, and as "Available Commands" otherwise.
+func helpSections(cmd *cobra.Command) []helpSection {
+	grouped := map[string][]*cobra.Command{}
+	var other []*cobra.Command
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() && c.Name() != "help" {
+			continue
+		}
+		if group := c.Annotations[GroupAnnotation]; group != "" {
+			grouped[group] = append(grouped[group], c)
+		} else {
+			other = append(other, c)
+		}
+	}
+
+	var sections []helpSection
+	for _, g := range commandGroups {
+		if len(grouped[g.ID]) > 0 {
+			sections = append(sections, helpSection{Title: g.Title, Commands: grouped[g.ID]})
+		}
+	}
+	if len(other) > 0 {
+		title := "Available Commands:"
+		if len(sections) > 0 {
+			title = "Additional Commands:"
+		}
+		sections = append(sections, helpSection{Title: title, Commands: other})
+	}
+	return sections
+}
+
+// groupedUsageTemplate is the default usage template of cobra, except that sub commands are listed by group.
+const groupedUsageTemplate = `Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+Aliases:
+  {{.NameAndAliases}}{{end}}{{if .HasExample}}
+
+Examples:
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{range helpSections .}}
+
+{{.Title}}{{range .Commands}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
+`
+
+// EnableGroupedHelp lists the sub commands of cmd and all its sub commands by the groups set with AddGroupedCommands.
+func EnableGroupedHelp(cmd *cobra.Command) {
+	cobra.AddTemplateFunc("helpSections", helpSections)
+	cmd.SetUsageTemplate(groupedUsageTemplate)
+}
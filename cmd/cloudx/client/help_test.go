@@ -0,0 +1,65 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupedHelp(t *testing.T) {
+	newCmd := func(use string) *cobra.Command {
+		return &cobra.Command{Use: use, Short: "Short " + use, Run: func(*cobra.Command, []string) {}}
+	}
+
+	t.Run("case=lists commands by group", func(t *testing.T) {
+		root := &cobra.Command{Use: "ory"}
+		AddGroupedCommands(root, GroupPermissions, newCmd("expand"))
+		AddGroupedCommands(root, GroupAuth, newCmd("auth"))
+		root.AddCommand(newCmd("version"))
+		EnableGroupedHelp(root)
+
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs([]string{"--help"})
+		require.NoError(t, root.Execute())
+
+		assert.Equal(t, `Usage:
+  ory [command]
+
+Authentication Commands:
+  auth        Short auth
+
+Permission Commands:
+  expand      Short expand
+
+Additional Commands:
+  completion  Generate the autocompletion script for the specified shell
+  help        Help about any command
+  version     Short version
+
+Flags:
+  -h, --help   help for ory
+
+Use "ory [command] --help" for more information about a command.
+`, out.String())
+	})
+
+	t.Run("case=lists commands without groups as available commands", func(t *testing.T) {
+		root := &cobra.Command{Use: "ory"}
+		get := &cobra.Command{Use: "get"}
+		get.AddCommand(newCmd("project"))
+		root.AddCommand(get)
+		EnableGroupedHelp(root)
+
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs([]string{"get", "--help"})
+		require.NoError(t, root.Execute())
+
+		assert.Contains(t, out.String(), "Available Commands:\n  project     Short project\n")
+		assert.NotContains(t, out.String(), "Additional Commands:")
+	})
+}
@@ -3,6 +3,9 @@ package client
 import (
 	"net/http"
 	"time"
+
+	cloud "github.com/ory/client-go"
+	kratos "github.com/ory/kratos-client-go"
 )
 
 type bearerTokenTransporter struct {
@@ -17,12 +20,83 @@ func (t *bearerTokenTransporter) RoundTrip(req *http.Request) (*http.Response, e
 	return t.RoundTripper.RoundTrip(req)
 }
 
-func newBearerTokenClient(token string) *http.Client {
-	return &http.Client{
-		Transport: &bearerTokenTransporter{
-			RoundTripper: http.DefaultTransport,
-			bearerToken:  token,
+// Client creates the API clients of a command. All of them share one connection pool and the middlewares which
+// retry, time out, and log requests, so that a command opens as few connections as possible.
+type Client struct {
+	// transport applies the middlewares shared by all API clients to the requests.
+	transport http.RoundTripper
+	// limiter caps the rate of all requests including retries, see RegisterRateFlag. It is nil if the rate is not
+	// limited.
+	limiter *Limiter
+	dryRun  bool
+}
+
+// clientConfig configures the middlewares of a Client.
+type clientConfig struct {
+	debug      *debugLog
+	maxRetries int
+	timeout    time.Duration
+	// limiter caps the rate of requests, it may be nil.
+	limiter *Limiter
+	// commandPath identifies the command sending the requests, see userAgent.
+	commandPath string
+	// compress enables the compression of large request bodies, see gzipTransport.
+	compress bool
+	dryRun   bool
+	// fixtures records or replays all requests, see fixtureTransport. It is nil unless fixtures are enabled.
+	fixtures *fixtureStore
+}
+
+func newClient(base http.RoundTripper, conf clientConfig) *Client {
+	sender := &timingTransport{RoundTripper: &gzipTransport{RoundTripper: &networkTransport{RoundTripper: base}, compress: conf.compress}}
+	transport := newRetryTransport(&rateTransport{
+		RoundTripper: &timeoutTransport{
+			RoundTripper: &logTransport{RoundTripper: &debugTransport{RoundTripper: withFixtures(sender, conf.fixtures), log: conf.debug}},
+			timeout:      conf.timeout,
 		},
-		Timeout: time.Second * 30,
+		limiter: conf.limiter,
+	}, conf.maxRetries)
+	return &Client{
+		transport: &userAgentTransport{RoundTripper: transport, userAgent: userAgent(conf.commandPath)},
+		limiter:   conf.limiter,
+		dryRun:    conf.dryRun,
+	}
+}
+
+// rate returns the rate to which the requests are limited, or the zero Rate if they are not limited.
+func (c *Client) rate() Rate {
+	if c == nil {
+		return Rate{}
 	}
+	return c.limiter.Rate()
+}
+
+// httpClient returns an HTTP client which authenticates using the token, unless it is empty. It refuses to send
+// requests changing resources if the --dry-run flag is set.
+func (c *Client) httpClient(token string) *http.Client {
+	return &http.Client{Transport: withDryRun(&bearerTokenTransporter{RoundTripper: c.transport, bearerToken: token}, c.dryRun)}
+}
+
+// consoleAPI returns a client of the Ory Console API.
+func (c *Client) consoleAPI(token string) *cloud.APIClient {
+	conf := cloud.NewConfiguration()
+	conf.Servers = cloud.ServerConfigurations{{URL: makeCloudConsoleURL("api")}}
+	conf.HTTPClient = c.httpClient(token)
+	return cloud.NewAPIClient(conf)
+}
+
+// authAPI returns a client of the API which signs users in to the Ory Console. Signing in is possible in dry runs.
+func (c *Client) authAPI() *cloud.APIClient {
+	conf := cloud.NewConfiguration()
+	conf.Servers = cloud.ServerConfigurations{{URL: makeCloudConsoleURL("project")}}
+	conf.HTTPClient = &http.Client{Transport: c.transport}
+	return cloud.NewAPIClient(conf)
+}
+
+// identityAPI returns a client of the identity API at the URL, see ProjectAPIURL.
+func (c *Client) identityAPI(token, url string) *kratos.APIClient {
+	conf := kratos.NewConfiguration()
+	conf.Servers = kratos.ServerConfigurations{{URL: url}}
+	conf.HTTPClient = c.httpClient(token)
+	return kratos.NewAPIClient(conf)
 }
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientSharesConnections(t *testing.T) {
+	var handshakes int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&handshakes, 1)
+		}
+	}
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	newTestClient := func() *Client {
+		return newClient(newBaseTransport(nil, &tls.Config{RootCAs: roots}, nil), clientConfig{})
+	}
+
+	get := func(t *testing.T, c *http.Client) string {
+		res, err := c.Get(ts.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("case=API clients of a command share connections", func(t *testing.T) {
+		atomic.StoreInt32(&handshakes, 0)
+		c := newTestClient()
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, "Bearer console-token", get(t, c.httpClient("console-token")))
+			assert.Equal(t, "", get(t, c.httpClient("")))
+		}
+		assert.EqualValues(t, 1, atomic.LoadInt32(&handshakes))
+	})
+
+	t.Run("case=separate clients open separate connections", func(t *testing.T) {
+		atomic.StoreInt32(&handshakes, 0)
+		for i := 0; i < 3; i++ {
+			get(t, newTestClient().httpClient(""))
+		}
+		assert.EqualValues(t, 3, atomic.LoadInt32(&handshakes))
+	})
+
+	t.Run("case=concurrent workers reuse their connections", func(t *testing.T) {
+		atomic.StoreInt32(&handshakes, 0)
+		c := newTestClient().httpClient("token")
+
+		const workers = 8
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 25; i++ {
+					get(t, c)
+				}
+			}()
+		}
+		wg.Wait()
+		// Dials racing for the first connection may open a few more connections than workers.
+		assert.LessOrEqual(t, atomic.LoadInt32(&handshakes), int32(2*workers), "200 requests reuse a handful of connections")
+	})
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/x/cmdx"
+)
+
+// IDOnlyFlag prints only the identifiers of the created resources to stdout, so that scripts do not need to parse
+// JSON to use them.
+const IDOnlyFlag = "id-only"
+
+// idOnlyValue is the value of the --id-only flag. While the command runs, out is the actual stdout, and the stdout of
+// the command is redirected to stderr, see EnableIDOnlyFlag.
+type idOnlyValue struct {
+	set bool
+	out io.Writer
+}
+
+func (v *idOnlyValue) String() string {
+	return strconv.FormatBool(v.set)
+}
+
+func (v *idOnlyValue) Set(s string) error {
+	set, err := strconv.ParseBool(s)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	v.set = set
+	return nil
+}
+
+func (*idOnlyValue) Type() string {
+	return "bool"
+}
+
+// RegisterIDOnlyFlag registers the --id-only flag of commands creating resources. The commands print the identifiers
+// of the created resources using PrintIDs.
+func RegisterIDOnlyFlag(f *pflag.FlagSet) {
+	f.Var(&idOnlyValue{}, IDOnlyFlag, "Print only the IDs of the created resources to stdout, one per line. The human readable output is printed to stderr instead.")
+	f.Lookup(IDOnlyFlag).NoOptDefVal = "true"
+}
+
+func idOnly(cmd *cobra.Command) *idOnlyValue {
+	f := cmd.Flags().Lookup(IDOnlyFlag)
+	if f == nil {
+		return nil
+	}
+	v, _ := f.Value.(*idOnlyValue)
+	return v
+}
+
+// PrintIDs prints the IDs of the created resources to stdout if the --id-only flag is set, one per line and without
+// any decoration. It does nothing otherwise, so that commands call it in addition to printing the resources.
+func PrintIDs(cmd *cobra.Command, ids ...string) {
+	v := idOnly(cmd)
+	if v == nil || !v.set || v.out == nil {
+		return
+	}
+	for _, id := range ids {
+		_, _ = fmt.Fprintln(v.out, id)
+	}
+}
+
+// EnableIDOnlyFlag applies the --id-only flag to cmd and all its sub commands which registered it. The stdout of the
+// command is redirected to stderr while it runs, or discarded if --quiet is set, so that the IDs printed by PrintIDs
+// are the only output on stdout. The flag can not be combined with --format.
+func EnableIDOnlyFlag(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableIDOnlyFlag(c)
+	}
+
+	if cmd.RunE == nil || cmd.LocalFlags().Lookup(IDOnlyFlag) == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		v := idOnly(cmd)
+		if v == nil || !v.set {
+			return run(cmd, args)
+		}
+		if OutputFormat(cmd) != FormatDefault {
+			return NewValidationError(errors.Errorf("the --%s flag can not be used with --%s or --%s", IDOnlyFlag, cmdx.FlagFormat, QueryFlag), nil)
+		}
+
+		out := cmd.OutOrStdout()
+		v.out = out
+		if quiet, _ := cmd.Flags().GetBool(cmdx.FlagQuiet); quiet {
+			cmd.SetOut(io.Discard)
+		} else {
+			cmd.SetOut(cmd.ErrOrStderr())
+		}
+		err := run(cmd, args)
+		cmd.SetOut(out)
+		v.out = nil
+		return err
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+)
+
+func TestEnableIDOnlyFlag(t *testing.T) {
+	run := func(t *testing.T, args ...string) (string, string, error) {
+		root := &cobra.Command{Use: "ory", SilenceErrors: true, SilenceUsage: true}
+		create := &cobra.Command{Use: "create", RunE: func(cmd *cobra.Command, _ []string) error {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "ID\tNAME\nfirst\tExample")
+			PrintIDs(cmd, "first", "second")
+			return nil
+		}}
+		RegisterIDOnlyFlag(create.Flags())
+		RegisterFormatFlag(create.Flags())
+		cmdx.RegisterNoiseFlags(create.Flags())
+		root.AddCommand(create)
+		EnableIDOnlyFlag(root)
+
+		var stdout, stderr bytes.Buffer
+		root.SetOut(&stdout)
+		root.SetErr(&stderr)
+		root.SetArgs(append([]string{"create"}, args...))
+		err := root.Execute()
+		return stdout.String(), stderr.String(), err
+	}
+
+	t.Run("case=without the flag", func(t *testing.T) {
+		stdout, stderr, err := run(t)
+		require.NoError(t, err)
+		assert.Equal(t, "ID\tNAME\nfirst\tExample\n", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("case=prints only the IDs to stdout", func(t *testing.T) {
+		stdout, stderr, err := run(t, "--"+IDOnlyFlag)
+		require.NoError(t, err)
+		assert.Equal(t, "first\nsecond\n", stdout)
+		assert.Equal(t, "ID\tNAME\nfirst\tExample\n", stderr)
+	})
+
+	t.Run("case=quiet discards the human readable output", func(t *testing.T) {
+		stdout, stderr, err := run(t, "--"+IDOnlyFlag, "--"+cmdx.FlagQuiet)
+		require.NoError(t, err)
+		assert.Equal(t, "first\nsecond\n", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("case=can not be combined with --format", func(t *testing.T) {
+		stdout, _, err := run(t, "--"+IDOnlyFlag, "--"+cmdx.FlagFormat, FormatJSON)
+		require.Error(t, err)
+		assert.Equal(t, ExitValidation, ExitCode(err))
+		assert.Empty(t, stdout)
+	})
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	cloud "github.com/ory/client-go"
+)
+
+// ResolveIdentityID returns the ID of the identity referenced by an ID or an email address. Email addresses
+// are looked up using the credentials identifier of the identity.
+func (a *ProjectAPI) ResolveIdentityID(ctx context.Context, idOrEmail string) (string, error) {
+	if id, err := uuid.FromString(idOrEmail); err == nil {
+		return id.String(), nil
+	}
+	if !strings.Contains(idOrEmail, "@") {
+		return "", errors.Errorf("%q is neither an identity ID nor an email address", idOrEmail)
+	}
+
+	var identities []struct {
+		ID     string          `json:"id"`
+		Traits json.RawMessage `json:"traits"`
+	}
+	query := url.Values{"credentials_identifier": {idOrEmail}}
+	if _, err := a.Do(ctx, http.MethodGet, "/admin/identities", query, nil, &identities); err != nil {
+		return "", err
+	}
+
+	// Older APIs ignore the credentials identifier filter, so make sure that the email is part of the traits.
+	var ids []string
+	for _, i := range identities {
+		if traitsContain(i.Traits, idOrEmail) {
+			ids = append(ids, i.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", errors.Errorf("unable to find an identity with the email address %q", idOrEmail)
+	case 1:
+		return ids[0], nil
+	}
+	return "", errors.Errorf("found %d identities with the email address %q, please use the identity ID instead", len(ids), idOrEmail)
+}
+
+func traitsContain(traits json.RawMessage, value string) bool {
+	var decoded interface{}
+	if err := json.Unmarshal(traits, &decoded); err != nil {
+		return false
+	}
+
+	var contains func(v interface{}) bool
+	contains = func(v interface{}) bool {
+		switch v := v.(type) {
+		case string:
+			return strings.EqualFold(v, value)
+		case []interface{}:
+			for _, e := range v {
+				if contains(e) {
+					return true
+				}
+			}
+		case map[string]interface{}:
+			for _, e := range v {
+				if contains(e) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return contains(decoded)
+}
+
+// emailTraitPaths are the paths of the email address in the traits of an identity, in the order they are tried. Besides
+// the flat schema of the Ory Console, they cover the common schemas which nest the email address or keep a list of
+// addresses.
+var emailTraitPaths = []string{
+	"email",
+	"emails.0",
+	"email.value",
+	"email.address",
+	"emails.0.value",
+	"emails.0.address",
+	"contact.email",
+	"contact.emails.0",
+	"personal.email",
+	"profile.email",
+}
+
+// maxTraitsRender is the maximum length of the traits stored instead of the email address, see identityEmail.
+const maxTraitsRender = 64
+
+// identityEmail returns the email address of the identity. It tries the emailTraitPaths of the traits first, then the
+// verifiable email addresses of the identity. If neither contains an email address, found is false and the traits
+// are returned instead, rendered as JSON and truncated to maxTraitsRender characters, so that the user still sees
+// who is signed in.
+func identityEmail(identity cloud.Identity) (email string, found bool) {
+	traits, _ := json.Marshal(identity.Traits)
+	for _, path := range emailTraitPaths {
+		if v := gjson.GetBytes(traits, path); v.Type == gjson.String && strings.Contains(v.Str, "@") {
+			return v.Str, true
+		}
+	}
+
+	for _, a := range identity.VerifiableAddresses {
+		if a.Via == "email" && a.Value != "" {
+			return a.Value, true
+		}
+	}
+
+	rendered := string(traits)
+	if rendered == "null" {
+		rendered = "{}"
+	}
+	if r := []rune(rendered); len(r) > maxTraitsRender {
+		rendered = string(r[:maxTraitsRender-1]) + "…"
+	}
+	return rendered, false
+}
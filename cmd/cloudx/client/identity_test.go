@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cloud "github.com/ory/client-go"
+)
+
+func TestResolveIdentityID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/admin/identities", r.URL.Path)
+		// Mimics an API which ignores the credentials identifier filter.
+		_, _ = w.Write([]byte(`[
+  {"id": "2f1c5a4e-3b5d-4d3f-8f3e-6a7c2b1d9e01", "traits": {"email": "Jane@example.com"}},
+  {"id": "7b2d9c1f-5e4a-4c8b-9d6e-1f2a3b4c5d6e", "traits": {"emails": ["john@example.com", "j@example.com"]}},
+  {"id": "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d", "traits": {"emails": ["j@example.com"]}}
+]`))
+	}))
+	t.Cleanup(ts.Close)
+
+	api := &ProjectAPI{URL: ts.URL, Client: ts.Client()}
+	ctx := context.Background()
+
+	id, err := api.ResolveIdentityID(ctx, "9F425A8D-7EFC-4768-8F23-7647A74FDF13")
+	require.NoError(t, err)
+	assert.Equal(t, "9f425a8d-7efc-4768-8f23-7647a74fdf13", id)
+
+	id, err = api.ResolveIdentityID(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "2f1c5a4e-3b5d-4d3f-8f3e-6a7c2b1d9e01", id)
+
+	id, err = api.ResolveIdentityID(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "7b2d9c1f-5e4a-4c8b-9d6e-1f2a3b4c5d6e", id)
+
+	_, err = api.ResolveIdentityID(ctx, "j@example.com")
+	assert.ErrorContains(t, err, "found 2 identities")
+
+	_, err = api.ResolveIdentityID(ctx, "unknown@example.com")
+	assert.ErrorContains(t, err, "unable to find an identity")
+
+	_, err = api.ResolveIdentityID(ctx, "jane")
+	assert.ErrorContains(t, err, "neither an identity ID nor an email address")
+}
+
+func TestIdentityEmail(t *testing.T) {
+	for _, tc := range []struct {
+		fixture string
+		email   string
+		found   bool
+	}{
+		{fixture: "flat", email: "dev@ory.sh", found: true},
+		{fixture: "nested", email: "dev@ory.sh", found: true},
+		{fixture: "emails", email: "dev@ory.sh", found: true},
+		{fixture: "addresses", email: "dev@ory.sh", found: true},
+		{fixture: "unknown", email: `{"handles":{"primary":"dev-at-ory","secondary":"dev-at-example-…`, found: false},
+	} {
+		t.Run("fixture="+tc.fixture, func(t *testing.T) {
+			raw, err := os.ReadFile("fixtures/sessions/" + tc.fixture + ".json")
+			require.NoError(t, err)
+			var session cloud.Session
+			require.NoError(t, json.Unmarshal(raw, &session))
+
+			email, found := identityEmail(session.Identity)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.email, email)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	stderrs "errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+
+	"github.com/ory/x/cmdx"
+)
+
+var ErrAborted = stderrs.New("aborted by user")
+
+// ForceInteractiveEnv enables prompts in CI services, e.g. on self-hosted runners with a terminal attached.
+const ForceInteractiveEnv = "ORY_FORCE_INTERACTIVE"
+
+// ciEnvVars are set by CI services. CI is set by most of them, the others cover the services which do not set it.
+var ciEnvVars = []string{
+	"CI",
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"CIRCLECI",
+	"TRAVIS",
+	"BUILDKITE",
+	"DRONE",
+	"JENKINS_URL",
+	"TEAMCITY_VERSION",
+	"TF_BUILD",
+	"BITBUCKET_BUILD_NUMBER",
+	"CODEBUILD_BUILD_ID",
+}
+
+// PromptDisabledError is returned instead of prompting the user if the --quiet flag is set, stdin is not a terminal,
+// or the CLI runs in a CI service.
+type PromptDisabledError struct {
+	// Prompt is the question which could not be asked.
+	Prompt string
+	// Alternative explains how to answer the prompt without interaction, e.g. by setting a flag.
+	Alternative string
+
+	reason string
+}
+
+func (e *PromptDisabledError) Error() string {
+	reason := e.reason
+	if reason == "" {
+		reason = "stdin is not a terminal"
+	}
+	return fmt.Sprintf("unable to ask %q because %s, please %s", e.Prompt, reason, e.Alternative)
+}
+
+// isTerminal returns false if stdin is a file, pipe, or closed. Readers which are not files, e.g. the buffers used
+// in tests, are treated as terminals.
+func isTerminal(stdin io.Reader) bool {
+	f, ok := stdin.(*os.File)
+	return !ok || term.IsTerminal(int(f.Fd()))
+}
+
+// detectCI returns the environment variable which shows that the CLI runs in a CI service, or an empty string.
+func detectCI(getenv func(string) string) string {
+	for _, name := range ciEnvVars {
+		switch strings.ToLower(getenv(name)) {
+		case "", "0", "false":
+		default:
+			return name
+		}
+	}
+	return ""
+}
+
+// promptsDisabledReason returns why the user is unable to answer prompts on stdin, or an empty string if they are
+// able to. Stdin has to be a terminal, and in CI services prompts are disabled even if it is, see ciPromptsDisabled.
+// Readers which are not files are not affected, see isTerminal.
+func promptsDisabledReason(stdin io.Reader, getenv func(string) string) string {
+	if _, ok := stdin.(*os.File); !ok {
+		return ""
+	} else if !isTerminal(stdin) {
+		return "stdin is not a terminal"
+	}
+	return ciPromptsDisabled(getenv)
+}
+
+// ciPromptsDisabled returns why prompts are disabled if the CLI runs in a CI service, where a build would hang on
+// them, or an empty string. ORY_FORCE_INTERACTIVE enables them.
+func ciPromptsDisabled(getenv func(string) string) string {
+	switch strings.ToLower(getenv(ForceInteractiveEnv)) {
+	case "1", "true":
+		return ""
+	}
+	if ci := detectCI(getenv); ci != "" {
+		return fmt.Sprintf("the CLI runs in CI (%s is set, use %s=1 to allow prompts)", ci, ForceInteractiveEnv)
+	}
+	return ""
+}
+
+// isInteractive returns true if the user is able to answer prompts.
+func (h *CommandHelper) isInteractive() bool {
+	return !h.IsQuiet && !h.NonInteractive
+}
+
+// RequireInteractive returns a PromptDisabledError if the user is unable to answer the given prompt. The alternative
+// names the flag or command which answers the prompt without interaction.
+func (h *CommandHelper) RequireInteractive(prompt, alternative string) error {
+	if h.isInteractive() {
+		return nil
+	}
+	reason := h.promptsDisabled
+	if h.IsQuiet {
+		reason = "the --quiet flag is set"
+	}
+	return errors.WithStack(&PromptDisabledError{Prompt: prompt, Alternative: alternative, reason: reason})
+}
+
+// Confirm asks the user to confirm the given question. It returns true without asking if the --yes flag is set and
+// fails instead of waiting for input if the user is unable to answer.
+func (h *CommandHelper) Confirm(question string) (bool, error) {
+	if h.NoConfirm {
+		return true, nil
+	}
+	if err := h.RequireInteractive(question, "set the --yes flag to confirm"); err != nil {
+		return false, err
+	}
+	return cmdx.AskScannerForConfirmation(h.Colors.Warn(question), h.Stdin, h.VerboseErrWriter)
+}
+
+// confirmForms returns true if the values entered into sign up and sign in forms are echoed for confirmation before
+// they are submitted. The --yes flag skips the confirmation.
+func (h *CommandHelper) confirmForms() bool {
+	return !h.NoConfirm && h.isInteractive()
+}
+
+// ConfirmOrAbort is like Confirm but returns ErrAborted if the user declines.
+func (h *CommandHelper) ConfirmOrAbort(question string) error {
+	ok, err := h.Confirm(question)
+	if err != nil {
+		return err
+	} else if !ok {
+		return ErrAborted
+	}
+	return nil
+}
+
+// ConfirmationLevel is how explicitly a destructive operation has to be confirmed.
+type ConfirmationLevel int
+
+const (
+	// ConfirmWithYes requires answering the confirmation with yes.
+	ConfirmWithYes ConfirmationLevel = iota
+	// ConfirmWithName requires typing the name of the resource. It is used for operations with a high blast radius,
+	// e.g. deleting a project or all relation tuples of a namespace.
+	ConfirmWithName
+)
+
+// SummaryLine is a line of the summary shown before a destructive operation.
+type SummaryLine struct {
+	Key, Value string
+}
+
+// Destruction describes a destructive operation.
+type Destruction struct {
+	// Action describes the operation, e.g. "delete the identity".
+	Action string
+	// Summary describes what will be destroyed, e.g. the ID and email address of an identity.
+	Summary []SummaryLine
+	// FetchSummary, if set, is called to complete the summary. It is only called if the user is asked, so that
+	// nothing is fetched if the --yes flag is set or the user is unable to answer.
+	FetchSummary func() ([]SummaryLine, error)
+	Level        ConfirmationLevel
+	// Name has to be typed to confirm operations with ConfirmWithName.
+	Name string
+}
+
+// ConfirmDestruction shows the summary of the destructive operation and asks the user to confirm it. It returns
+// ErrAborted if the user declines. The --yes flag confirms the operation without showing the summary, while the
+// --quiet flag without --yes fails instead of asking. In dry runs, the summary is shown without asking.
+func (h *CommandHelper) ConfirmDestruction(d *Destruction) error {
+	if h.NoConfirm && !h.DryRun {
+		return nil
+	}
+
+	question := fmt.Sprintf("Do you want to %s?", d.Action)
+	if d.Level == ConfirmWithName {
+		question = fmt.Sprintf("Type %q to %s:", d.Name, d.Action)
+	}
+	if !h.DryRun {
+		if err := h.RequireInteractive(question, "set the --yes flag to confirm"); err != nil {
+			return err
+		}
+	}
+
+	summary := d.Summary
+	if d.FetchSummary != nil {
+		fetched, err := d.FetchSummary()
+		if err != nil {
+			return err
+		}
+		summary = append(summary[:len(summary):len(summary)], fetched...)
+	}
+
+	if h.DryRun {
+		_, _ = fmt.Fprintf(h.VerboseErrWriter, "Dry run: would %s:\n", d.Action)
+	} else {
+		_, _ = fmt.Fprintf(h.VerboseErrWriter, "You are about to %s:\n", d.Action)
+	}
+	var width int
+	for _, l := range summary {
+		if len(l.Key) > width {
+			width = len(l.Key)
+		}
+	}
+	for _, l := range summary {
+		_, _ = fmt.Fprintf(h.VerboseErrWriter, "  %-*s  %s\n", width+1, l.Key+":", l.Value)
+	}
+	if h.DryRun {
+		return nil
+	}
+
+	if d.Level != ConfirmWithName {
+		return h.ConfirmOrAbort(question)
+	}
+
+	_, _ = fmt.Fprint(h.VerboseErrWriter, h.Colors.Warn(question)+" ")
+	answer, err := h.Stdin.ReadString('\n')
+	if err != nil && answer == "" {
+		return errors.Wrap(err, "failed to read from stdin")
+	}
+	if answer = strings.TrimSpace(answer); answer != d.Name {
+		return fmt.Errorf("%w because %q does not match %q", ErrAborted, answer, d.Name)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+)
+
+func newClosedStdinHelper(t *testing.T, args ...string) *CommandHelper {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, r.Close())
+	return newHelper(t, r, args...)
+}
+
+func newHelper(t *testing.T, stdin io.Reader, args ...string) *CommandHelper {
+	cmd := &cobra.Command{}
+	RegisterConfigFlag(cmd.Flags())
+	RegisterYesFlag(cmd.Flags())
+	cmdx.RegisterNoiseFlags(cmd.Flags())
+	require.NoError(t, cmd.ParseFlags(append(args, "--"+ConfigFlag, filepath.Join(t.TempDir(), "config.json"))))
+	cmd.SetIn(stdin)
+	cmd.SetErr(io.Discard)
+
+	h, err := NewCommandHelper(cmd)
+	require.NoError(t, err)
+	return h
+}
+
+func TestNonInteractive(t *testing.T) {
+	t.Run("case=confirmation fails instead of waiting for input", func(t *testing.T) {
+		h := newClosedStdinHelper(t)
+		assert.True(t, h.NonInteractive)
+
+		_, err := h.Confirm("Do you want to continue?")
+		var promptErr *PromptDisabledError
+		require.ErrorAs(t, err, &promptErr)
+		assert.Equal(t, `unable to ask "Do you want to continue?" because stdin is not a terminal, please set the --yes flag to confirm`, err.Error())
+	})
+
+	t.Run("case=yes flag answers the confirmation", func(t *testing.T) {
+		h := newClosedStdinHelper(t, "--yes")
+		require.NoError(t, h.ConfirmOrAbort("Do you want to continue?"))
+	})
+
+	t.Run("case=quiet flag is named in the error", func(t *testing.T) {
+		h := newClosedStdinHelper(t, "--quiet")
+		_, err := h.Confirm("Do you want to continue?")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "because the --quiet flag is set, please set the --yes flag")
+	})
+
+	t.Run("case=project selection after signing in is skipped", func(t *testing.T) {
+		h := newClosedStdinHelper(t)
+		ac := &AuthContext{SessionToken: "s3cr3t-session"}
+		h.selectProjectAfterSignIn(ac)
+		assert.Equal(t, uuid.Nil, ac.SelectedProject)
+	})
+
+	t.Run("case=sign in fails instead of waiting for input", func(t *testing.T) {
+		h := newClosedStdinHelper(t, "--yes")
+		_, err := h.Authenticate()
+		var promptErr *PromptDisabledError
+		require.ErrorAs(t, err, &promptErr)
+		assert.Contains(t, err.Error(), "run `ory auth` in a terminal")
+	})
+}
+
+func TestCIDetection(t *testing.T) {
+	for _, tc := range []struct {
+		env    map[string]string
+		reason string
+	}{
+		{env: map[string]string{}},
+		{env: map[string]string{"CI": "true"}, reason: "the CLI runs in CI (CI is set, use ORY_FORCE_INTERACTIVE=1 to allow prompts)"},
+		{env: map[string]string{"CI": "1"}, reason: "the CLI runs in CI (CI is set, use ORY_FORCE_INTERACTIVE=1 to allow prompts)"},
+		{env: map[string]string{"CI": "false"}},
+		{env: map[string]string{"CI": "0"}},
+		{env: map[string]string{"GITHUB_ACTIONS": "true"}, reason: "the CLI runs in CI (GITHUB_ACTIONS is set, use ORY_FORCE_INTERACTIVE=1 to allow prompts)"},
+		{env: map[string]string{"GITLAB_CI": "true"}, reason: "the CLI runs in CI (GITLAB_CI is set, use ORY_FORCE_INTERACTIVE=1 to allow prompts)"},
+		{env: map[string]string{"JENKINS_URL": "https://jenkins.example.com"}, reason: "the CLI runs in CI (JENKINS_URL is set, use ORY_FORCE_INTERACTIVE=1 to allow prompts)"},
+		{env: map[string]string{"CI": "false", "TF_BUILD": "True"}, reason: "the CLI runs in CI (TF_BUILD is set, use ORY_FORCE_INTERACTIVE=1 to allow prompts)"},
+		{env: map[string]string{"CI": "true", "ORY_FORCE_INTERACTIVE": "1"}},
+		{env: map[string]string{"GITHUB_ACTIONS": "true", "ORY_FORCE_INTERACTIVE": "true"}},
+		{env: map[string]string{"CI": "true", "ORY_FORCE_INTERACTIVE": "0"}, reason: "the CLI runs in CI (CI is set, use ORY_FORCE_INTERACTIVE=1 to allow prompts)"},
+		{env: map[string]string{"ORY_FORCE_INTERACTIVE": "1"}},
+	} {
+		t.Run(fmt.Sprintf("env=%v", tc.env), func(t *testing.T) {
+			assert.Equal(t, tc.reason, ciPromptsDisabled(func(name string) string { return tc.env[name] }))
+		})
+	}
+
+	ci := func(name string) string { return map[string]string{"CI": "true"}[name] }
+
+	t.Run("case=stdin which is not a terminal is never interactive", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close(); _ = w.Close() })
+		assert.Equal(t, "stdin is not a terminal", promptsDisabledReason(r, ci))
+		assert.Equal(t, "stdin is not a terminal", promptsDisabledReason(r, func(string) string { return "1" }))
+	})
+
+	t.Run("case=readers which are not files are not affected", func(t *testing.T) {
+		assert.Empty(t, promptsDisabledReason(strings.NewReader(""), ci))
+	})
+
+	t.Run("case=the reason is named in the error", func(t *testing.T) {
+		h := newHelper(t, strings.NewReader(""))
+		h.NonInteractive, h.promptsDisabled = true, ciPromptsDisabled(ci)
+		_, err := h.Confirm("Do you want to continue?")
+		assert.EqualError(t, err, `unable to ask "Do you want to continue?" because the CLI runs in CI (CI is set, use ORY_FORCE_INTERACTIVE=1 to allow prompts), please set the --yes flag to confirm`)
+	})
+}
+
+func TestConfirmDestruction(t *testing.T) {
+	identity := &Destruction{
+		Action: "delete the identity",
+		Summary: []SummaryLine{
+			{Key: "ID", Value: "9f425a8d-7efc-4768-8f23-7647a74fdf13"},
+			{Key: "Email", Value: "jane@example.com"},
+		},
+	}
+	project := &Destruction{
+		Action:  "delete the project",
+		Summary: []SummaryLine{{Key: "Slug", Value: "good-wright-t7kzy3vugf"}, {Key: "Identities", Value: "1204"}},
+		Level:   ConfirmWithName,
+		Name:    "good-wright-t7kzy3vugf",
+	}
+	confirm := func(t *testing.T, d *Destruction, stdin string, args ...string) (string, error) {
+		h := newHelper(t, strings.NewReader(stdin), args...)
+		var out bytes.Buffer
+		h.VerboseErrWriter = &out
+		err := h.ConfirmDestruction(d)
+		return out.String(), err
+	}
+
+	t.Run("level=yes", func(t *testing.T) {
+		h := newHelper(t, strings.NewReader("y\n"))
+		var out bytes.Buffer
+		h.VerboseErrWriter = &out
+		require.NoError(t, h.ConfirmDestruction(identity))
+		assert.Equal(t, `You are about to delete the identity:
+  ID:     9f425a8d-7efc-4768-8f23-7647a74fdf13
+  Email:  jane@example.com
+Do you want to delete the identity? [y/n]: `, out.String())
+
+		_, err := confirm(t, identity, "n\n")
+		assert.ErrorIs(t, err, ErrAborted)
+	})
+
+	t.Run("level=name", func(t *testing.T) {
+		h := newHelper(t, strings.NewReader("good-wright-t7kzy3vugf\n"))
+		var out bytes.Buffer
+		h.VerboseErrWriter = &out
+		require.NoError(t, h.ConfirmDestruction(project))
+		assert.Equal(t, `You are about to delete the project:
+  Slug:        good-wright-t7kzy3vugf
+  Identities:  1204
+Type "good-wright-t7kzy3vugf" to delete the project: `, out.String())
+
+		_, err := confirm(t, project, "y\n")
+		assert.ErrorIs(t, err, ErrAborted)
+		assert.EqualError(t, err, `aborted by user because "y" does not match "good-wright-t7kzy3vugf"`)
+	})
+
+	t.Run("case=yes flag skips the confirmation", func(t *testing.T) {
+		for _, d := range []*Destruction{identity, project} {
+			out, err := confirm(t, d, "", "--yes")
+			require.NoError(t, err)
+			assert.Empty(t, out)
+		}
+	})
+
+	t.Run("case=dry run shows the summary without asking", func(t *testing.T) {
+		h := newClosedStdinHelper(t, "--yes")
+		h.DryRun = true
+		var out bytes.Buffer
+		h.VerboseErrWriter = &out
+		require.NoError(t, h.ConfirmDestruction(project))
+		assert.Equal(t, `Dry run: would delete the project:
+  Slug:        good-wright-t7kzy3vugf
+  Identities:  1204
+`, out.String())
+	})
+
+	t.Run("case=quiet flag fails fast", func(t *testing.T) {
+		for _, d := range []*Destruction{identity, project} {
+			_, err := confirm(t, d, "y\n", "--quiet")
+			var promptErr *PromptDisabledError
+			require.ErrorAs(t, err, &promptErr)
+		}
+	})
+}
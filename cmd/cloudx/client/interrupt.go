@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// childProcesses is the number of child processes, e.g. the pager, which are attached to the terminal. While it is
+// not zero, a second Ctrl-C does not exit the CLI, because the child process handles it.
+var childProcesses int32
+
+// ContextWithInterrupt returns a context which is canceled when the user presses Ctrl-C. Commands then stop sending
+// requests, clear their progress output, and report partial results. Pressing Ctrl-C a second time exits immediately
+// with ExitInterrupted, in case a command does not react to the cancellation, e.g. while it waits for input.
+//
+// Call stop once the command returned.
+func ContextWithInterrupt(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+			return
+		case <-signals:
+			cancel()
+		}
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				if atomic.LoadInt32(&childProcesses) == 0 {
+					os.Exit(ExitInterrupted)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			cancel()
+		})
+	}
+}
+
+// NewInterruptedError returns an error with the given message which reports that the user interrupted the command.
+// The CLI exits with ExitInterrupted.
+func NewInterruptedError(message string) error {
+	return &interruptedError{message: message}
+}
+
+type interruptedError struct {
+	message string
+}
+
+func (e *interruptedError) Error() string {
+	return e.message
+}
+
+func (e *interruptedError) Is(target error) bool {
+	return target == context.Canceled
+}
+
+// WithoutCancel returns a context with the values of parent which is never canceled, so that requests which were
+// already sent finish after an interrupt. The requests are still bounded by the --timeout.
+func WithoutCancel(parent context.Context) context.Context {
+	return detachedContext{parent}
+}
+
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
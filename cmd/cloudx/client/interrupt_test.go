@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextWithInterrupt(t *testing.T) {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Skipf("unable to find the test process: %s", err)
+	}
+
+	ctx, stop := ContextWithInterrupt(context.Background())
+	defer stop()
+	// Keep a second interrupt from exiting the test.
+	childProcesses++
+	defer func() { childProcesses-- }()
+
+	if err := p.Signal(os.Interrupt); err != nil {
+		t.Skipf("unable to send an interrupt: %s", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the context was not canceled by the interrupt")
+	}
+}
+
+func TestNewInterruptedError(t *testing.T) {
+	err := errors.WithStack(NewInterruptedError("the import was interrupted"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, ExitInterrupted, ExitCode(err))
+	assert.Equal(t, ErrorCodeInterrupted, NewErrorEnvelope(err).Error.Code)
+}
+
+func TestWithoutCancel(t *testing.T) {
+	type key struct{}
+	parent, cancel := context.WithTimeout(context.WithValue(context.Background(), key{}, "value"), time.Millisecond)
+	cancel()
+
+	ctx := WithoutCancel(parent)
+	assert.NoError(t, ctx.Err())
+	assert.Nil(t, ctx.Done())
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	assert.Equal(t, "value", ctx.Value(key{}))
+}
@@ -1,21 +1,110 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
 
 	"github.com/ory/x/osx"
 	"github.com/ory/x/stringsx"
 )
 
-func ReadConfigFiles(files []string) ([]json.RawMessage, error) {
+const (
+	// StdinFile is the file name which makes file flags read from stdin.
+	StdinFile = "-"
+
+	// MaxInputFileSize is the maximum size of files read by ReadInputFile.
+	MaxInputFileSize = 32 << 20
+
+	// binarySniffLength is the number of bytes checked for NUL bytes to detect binary files.
+	binarySniffLength = 8000
+)
+
+// consumedStdin replaces stdin of a command once it was read by OpenInputFile.
+type consumedStdin struct{}
+
+func (consumedStdin) Read([]byte) (int, error) {
+	return 0, errors.New("stdin was already read")
+}
+
+// InputFileName returns the file name passed to a file flag without the optional @ prefix, which is supported for
+// parity with curl.
+func InputFileName(file string) string {
+	return strings.TrimPrefix(file, "@")
+}
+
+// OpenInputFile opens the file passed to a file flag. The file "-" is stdin, which can only be read once per
+// invocation, and "@path" is the same as "path". Binary files are rejected.
+func OpenInputFile(cmd *cobra.Command, file string) (io.ReadCloser, error) {
+	file = InputFileName(file)
+
+	var f io.ReadCloser
+	if file == StdinFile {
+		if _, ok := cmd.InOrStdin().(consumedStdin); ok {
+			return nil, errors.New("stdin can only be read once, please pass - to only one of the flags and use files for the others")
+		}
+		f = io.NopCloser(cmd.InOrStdin())
+		cmd.SetIn(consumedStdin{})
+	} else {
+		var err error
+		if f, err = os.Open(file); err != nil {
+			return nil, errors.Wrapf(err, "unable to open file: %s", file)
+		}
+	}
+
+	r := bufio.NewReaderSize(f, binarySniffLength)
+	if head, err := r.Peek(binarySniffLength); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		_ = f.Close()
+		return nil, errors.Wrapf(err, "unable to read file: %s", displayFileName(file))
+	} else if bytes.IndexByte(head, 0) >= 0 {
+		_ = f.Close()
+		return nil, errors.Errorf("unable to read file %s because it looks like a binary file", displayFileName(file))
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, f}, nil
+}
+
+// ReadInputFile is like OpenInputFile but reads the whole file, which must not be larger than MaxInputFileSize.
+func ReadInputFile(cmd *cobra.Command, file string) ([]byte, error) {
+	f, err := OpenInputFile(cmd, file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	contents, err := io.ReadAll(io.LimitReader(f, MaxInputFileSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file: %s", displayFileName(InputFileName(file)))
+	} else if len(contents) > MaxInputFileSize {
+		return nil, errors.Errorf("unable to read file %s because it is larger than %d MiB", displayFileName(InputFileName(file)), MaxInputFileSize>>20)
+	}
+	return contents, nil
+}
+
+func displayFileName(file string) string {
+	if file == StdinFile {
+		return "stdin"
+	}
+	return file
+}
+
+// ReadConfigFiles reads and parses the configuration files passed to a file flag. Besides the sources supported by
+// ReadInputFile, configurations can be loaded from HTTP(S) URLs and base64 encoded strings.
+func ReadConfigFiles(cmd *cobra.Command, files []string) ([]json.RawMessage, error) {
 	var configs []json.RawMessage
 	for _, source := range files {
-		config, err := readConfigFile(source)
+		config, err := readConfigFile(cmd, source)
 		if err != nil {
 			return nil, err
 		}
@@ -24,17 +113,26 @@ func ReadConfigFiles(files []string) ([]json.RawMessage, error) {
 	return configs, nil
 }
 
-func readConfigFile(source string) (json.RawMessage, error) {
-	contents, err := osx.ReadFileFromAllSources(source, osx.WithEnabledBase64Loader(), osx.WithEnabledHTTPLoader(), osx.WithEnabledFileLoader())
+func readConfigFile(cmd *cobra.Command, source string) (json.RawMessage, error) {
+	var contents []byte
+	var err error
+	if strings.HasPrefix(source, "@") || source == StdinFile {
+		source = InputFileName(source)
+		contents, err = ReadInputFile(cmd, source)
+	} else {
+		contents, err = osx.ReadFileFromAllSources(source, osx.WithEnabledBase64Loader(), osx.WithEnabledHTTPLoader(), osx.WithEnabledFileLoader())
+		err = errors.Wrapf(err, "failed to read file: %s", source)
+	}
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read file: %s", source)
+		return nil, err
 	}
 
 	switch f := stringsx.SwitchExact(filepath.Ext(source)); {
-	case f.AddCase(".yaml"), f.AddCase(".yml"):
+	case f.AddCase(".yaml"), f.AddCase(".yml"), source == StdinFile:
+		// JSON is valid YAML, so stdin can contain either.
 		var config json.RawMessage
 		if err := yaml.Unmarshal(contents, &config); err != nil {
-			return nil, errors.Wrapf(err, "failed to parse YAML file: %s", source)
+			return nil, errors.Wrapf(err, "failed to parse YAML file: %s", displayFileName(source))
 		}
 		return config, nil
 	case f.AddCase(".json"):
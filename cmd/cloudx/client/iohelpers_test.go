@@ -1,20 +1,73 @@
 package client
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ory/x/assertx"
 )
 
 func TestReadConfigFiles(t *testing.T) {
-	configs, err := ReadConfigFiles([]string{
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader(`{"d": true}`))
+	configs, err := ReadConfigFiles(cmd, []string{
 		"fixtures/iohelpers/a.yaml",
-		"fixtures/iohelpers/b.yml",
+		"@fixtures/iohelpers/b.yml",
 		"fixtures/iohelpers/c.json",
+		"-",
 	})
 	require.NoError(t, err)
-	assertx.EqualAsJSON(t, json.RawMessage(`[{"a":true},{"b":true},{"c":true}]`), configs)
+	assertx.EqualAsJSON(t, json.RawMessage(`[{"a":true},{"b":true},{"c":true},{"d":true}]`), configs)
+}
+
+func TestReadInputFile(t *testing.T) {
+	stdin := func(r io.Reader) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.SetIn(r)
+		return cmd
+	}
+
+	t.Run("case=reads files", func(t *testing.T) {
+		for _, file := range []string{"fixtures/iohelpers/c.json", "@fixtures/iohelpers/c.json"} {
+			contents, err := ReadInputFile(stdin(nil), file)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"c": true}`, string(contents))
+		}
+	})
+
+	t.Run("case=reads stdin only once", func(t *testing.T) {
+		cmd := stdin(strings.NewReader("from stdin"))
+		contents, err := ReadInputFile(cmd, "-")
+		require.NoError(t, err)
+		assert.Equal(t, "from stdin", string(contents))
+
+		_, err = ReadInputFile(cmd, "@-")
+		assert.ErrorContains(t, err, "stdin can only be read once")
+	})
+
+	t.Run("case=includes the path in errors", func(t *testing.T) {
+		_, err := ReadInputFile(stdin(nil), "fixtures/iohelpers/does-not-exist.json")
+		assert.ErrorContains(t, err, "unable to open file: fixtures/iohelpers/does-not-exist.json")
+	})
+
+	t.Run("case=rejects binary files", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "binary")
+		require.NoError(t, os.WriteFile(file, []byte{0x7f, 'E', 'L', 'F', 0, 1}, 0600))
+		_, err := ReadInputFile(stdin(nil), file)
+		assert.ErrorContains(t, err, "unable to read file "+file+" because it looks like a binary file")
+	})
+
+	t.Run("case=rejects large files", func(t *testing.T) {
+		_, err := ReadInputFile(stdin(bytes.NewReader(bytes.Repeat([]byte("a"), MaxInputFileSize+1))), "-")
+		assert.ErrorContains(t, err, "unable to read file stdin because it is larger than 32 MiB")
+	})
 }
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	LogLevelFlag  = "log-level"
+	LogFormatFlag = "log-format"
+
+	// LogLevelEnv sets the log level if the --log-level flag is not set.
+	LogLevelEnv = "ORY_LOG_LEVEL"
+
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// LogLevel is the severity of a log message. Higher levels are more verbose.
+type LogLevel int
+
+const (
+	LogLevelError LogLevel = iota
+	LogLevelWarn
+	LogLevelInfo
+	LogLevelDebug
+	LogLevelTrace
+)
+
+var logLevels = []string{"error", "warn", "info", "debug", "trace"}
+
+func (l LogLevel) String() string {
+	if l < 0 || int(l) >= len(logLevels) {
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+	return logLevels[l]
+}
+
+// ParseLogLevel parses the name of a log level, e.g. "warn".
+func ParseLogLevel(s string) (LogLevel, error) {
+	for i, name := range logLevels {
+		if strings.EqualFold(s, name) {
+			return LogLevel(i), nil
+		}
+	}
+	return 0, errors.Errorf("unknown log level %q, expected one of %s", s, strings.Join(logLevels, ", "))
+}
+
+type logLevelValue string
+
+func (v *logLevelValue) String() string {
+	return string(*v)
+}
+
+func (v *logLevelValue) Set(s string) error {
+	if _, err := ParseLogLevel(s); err != nil {
+		return err
+	}
+	*v = logLevelValue(s)
+	return nil
+}
+
+func (*logLevelValue) Type() string {
+	return "string"
+}
+
+type logFormatValue string
+
+func (v *logFormatValue) String() string {
+	return string(*v)
+}
+
+func (v *logFormatValue) Set(s string) error {
+	switch s {
+	case LogFormatText, LogFormatJSON:
+		*v = logFormatValue(s)
+		return nil
+	}
+	return fmt.Errorf("unknown log format %q, expected %s or %s", s, LogFormatText, LogFormatJSON)
+}
+
+func (*logFormatValue) Type() string {
+	return "string"
+}
+
+// RegisterLogFlags registers the flags which configure the log messages printed to stderr.
+func RegisterLogFlags(f *pflag.FlagSet) {
+	var level logLevelValue
+	f.Var(&level, LogLevelFlag, fmt.Sprintf("Set the log level, one of %s. Defaults to the %s environment variable, or error if --quiet is set, debug if --verbose is set, and info otherwise.", strings.Join(logLevels, ", "), LogLevelEnv))
+	format := logFormatValue(LogFormatText)
+	f.Var(&format, LogFormatFlag, fmt.Sprintf("Set the log format, %s or %s.", LogFormatText, LogFormatJSON))
+}
+
+// Logger prints leveled log messages to stderr. The zero value and nil discard all messages. Requests and responses
+// are logged with the --debug flag instead, see RegisterDebugFlag.
+type Logger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	level  LogLevel
+	json   bool
+	colors Colors
+	now    func() time.Time
+}
+
+// NewLogger returns the Logger configured by the flags of cmd. The --quiet and --verbose flags are shortcuts for the
+// error and debug log levels.
+func NewLogger(cmd *cobra.Command) (*Logger, error) {
+	l := &Logger{out: cmd.ErrOrStderr(), level: LogLevelInfo, colors: NewColors(cmd, cmd.ErrOrStderr()), now: time.Now}
+
+	lookup := func(name string) string {
+		if f := cmd.Flags().Lookup(name); f != nil {
+			return f.Value.String()
+		}
+		return ""
+	}
+	switch {
+	case lookup(cmdx.FlagQuiet) == "true":
+		l.level = LogLevelError
+	case lookup(VerboseFlag) == "true":
+		l.level = LogLevelDebug
+	}
+
+	if level := lookup(LogLevelFlag); level != "" {
+		var err error
+		if l.level, err = ParseLogLevel(level); err != nil {
+			return nil, NewValidationError(err, nil)
+		}
+	} else if level := os.Getenv(LogLevelEnv); level != "" {
+		var err error
+		if l.level, err = ParseLogLevel(level); err != nil {
+			return nil, NewValidationError(errors.Wrapf(err, "invalid %s environment variable", LogLevelEnv), nil)
+		}
+	}
+
+	l.json = lookup(LogFormatFlag) == LogFormatJSON
+	return l, nil
+}
+
+// Enabled returns true if messages of the given level are printed.
+func (l *Logger) Enabled(level LogLevel) bool {
+	return l != nil && l.out != nil && level <= l.level
+}
+
+func (l *Logger) logf(level LogLevel, format string, args ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	var line string
+	if l.json {
+		out, err := json.Marshal(&struct {
+			Time    string `json:"time"`
+			Level   string `json:"level"`
+			Message string `json:"msg"`
+		}{Time: l.now().UTC().Format(time.RFC3339Nano), Level: level.String(), Message: strings.TrimSpace(msg)})
+		if err != nil {
+			return
+		}
+		line = string(out)
+	} else {
+		msg = strings.TrimRight(msg, "\n")
+		switch level {
+		case LogLevelError:
+			line = l.colors.Error(msg)
+		case LogLevelWarn:
+			line = l.colors.Warn(msg)
+		case LogLevelInfo:
+			line = msg
+		default:
+			line = l.colors.Dim(msg)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = fmt.Fprintln(l.out, line)
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(LogLevelError, format, args...)
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(LogLevelWarn, format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(LogLevelInfo, format, args...)
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(LogLevelDebug, format, args...)
+}
+
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	l.logf(LogLevelTrace, format, args...)
+}
+
+type loggerKey struct{}
+
+// contextWithLogger makes all Ory Cloud API clients log to l.
+func contextWithLogger(ctx context.Context, l *Logger) context.Context {
+	if l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+func loggerFromContext(ctx context.Context) *Logger {
+	l, _ := ctx.Value(loggerKey{}).(*Logger)
+	return l
+}
+
+// logTransport logs the responses to the logger of the request context and records the IDs of failed requests, see
+// withRequestID.
+type logTransport struct {
+	http.RoundTripper
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	recordRequestID(req.Context(), res)
+	if l := loggerFromContext(req.Context()); l.Enabled(LogLevelDebug) {
+		l.Tracef("%s %s: %s", req.Method, redactURL(req.URL), res.Status)
+		logRateLimit(l, res)
+		logRequestID(l, res)
+	}
+	return res, err
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+)
+
+func newLoggerCmd(t *testing.T, args ...string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	RegisterLogFlags(cmd.Flags())
+	RegisterVerboseFlag(cmd.Flags())
+	cmdx.RegisterNoiseFlags(cmd.Flags())
+	require.NoError(t, cmd.ParseFlags(args))
+
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	return cmd, &stderr
+}
+
+func TestNewLogger(t *testing.T) {
+	for _, tc := range []struct {
+		args     []string
+		env      string
+		expected LogLevel
+	}{
+		{expected: LogLevelInfo},
+		{args: []string{"--quiet"}, expected: LogLevelError},
+		{args: []string{"--verbose"}, expected: LogLevelDebug},
+		{args: []string{"--log-level", "trace"}, expected: LogLevelTrace},
+		{args: []string{"--log-level", "WARN", "--quiet"}, expected: LogLevelWarn},
+		{env: "debug", expected: LogLevelDebug},
+		{args: []string{"--log-level", "error"}, env: "debug", expected: LogLevelError},
+		{args: []string{"--quiet"}, env: "info", expected: LogLevelInfo},
+	} {
+		t.Run("case="+tc.expected.String(), func(t *testing.T) {
+			t.Setenv(LogLevelEnv, tc.env)
+			cmd, _ := newLoggerCmd(t, tc.args...)
+			l, err := NewLogger(cmd)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, l.level, "%v", tc.args)
+		})
+	}
+
+	t.Run("case=rejects unknown levels", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		RegisterLogFlags(cmd.Flags())
+		assert.ErrorContains(t, cmd.ParseFlags([]string{"--log-level", "verbose"}), `unknown log level "verbose", expected one of error, warn, info, debug, trace`)
+		assert.ErrorContains(t, cmd.ParseFlags([]string{"--log-format", "yaml"}), `unknown log format "yaml"`)
+
+		t.Setenv(LogLevelEnv, "loud")
+		_, err := NewLogger(cmd)
+		require.Error(t, err)
+		assert.Equal(t, ExitValidation, ExitCode(err))
+	})
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("format=text", func(t *testing.T) {
+		t.Setenv(LogLevelEnv, "")
+		cmd, stderr := newLoggerCmd(t, "--log-level", "warn")
+		l, err := NewLogger(cmd)
+		require.NoError(t, err)
+
+		l.Errorf("Unable to import %d relation tuples", 2)
+		l.Warnf("Warnings were found.\n")
+		l.Infof("Project created successfully!")
+		l.Debugf("Rate limit of GET /projects: 599/600")
+		assert.Equal(t, "Unable to import 2 relation tuples\nWarnings were found.\n", stderr.String())
+	})
+
+	t.Run("format=json", func(t *testing.T) {
+		t.Setenv(LogLevelEnv, "")
+		cmd, stderr := newLoggerCmd(t, "--log-format", "json", "--verbose")
+		l, err := NewLogger(cmd)
+		require.NoError(t, err)
+		l.now = func() time.Time { return time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+		l.Infof("\nMore messages are available.")
+		l.Debugf("Rate limit of GET /projects: 599/600")
+		l.Tracef("GET https://api.console.ory.sh/projects: 200 OK")
+		assert.Equal(t, `{"time":"2022-06-01T12:00:00Z","level":"info","msg":"More messages are available."}
+{"time":"2022-06-01T12:00:00Z","level":"debug","msg":"Rate limit of GET /projects: 599/600"}
+`, stderr.String())
+	})
+
+	t.Run("case=nil discards everything", func(t *testing.T) {
+		var l *Logger
+		assert.False(t, l.Enabled(LogLevelError))
+		l.Errorf("not printed")
+	})
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+type networkErrorKind int
+
+const (
+	networkErrorOther networkErrorKind = iota
+	networkErrorDNS
+	networkErrorRefused
+	networkErrorTLS
+	networkErrorOffline
+)
+
+// NetworkError is returned if a request failed before the API responded, e.g. because the host could not be resolved
+// or its TLS certificate is not trusted. Its message explains what to check. It is a net.Error so that the CLI exits
+// with ExitNetwork.
+type NetworkError struct {
+	// Endpoint is the URL of the endpoint without the path, e.g. https://project.projects.oryapis.com.
+	Endpoint string
+	kind     networkErrorKind
+	custom   bool
+	err      error
+}
+
+func (e *NetworkError) Error() string {
+	switch e.kind {
+	case networkErrorOffline:
+		return fmt.Sprintf("unable to connect to %s, you seem to be offline: check your network connection and try again (%s)", e.Endpoint, e.err)
+	case networkErrorDNS:
+		msg := fmt.Sprintf("unable to resolve the host of %s (%s): check that the endpoint is correct and that you are connected to the network", e.Endpoint, e.err)
+		if env, ok := os.LookupEnv(consoleURLEnv); ok {
+			msg += fmt.Sprintf("; the endpoint is derived from %s=%s", consoleURLEnv, env)
+		}
+		return msg
+	case networkErrorRefused:
+		if e.custom {
+			return fmt.Sprintf("the connection to %s was refused: is the self-hosted Ory service running and listening on this address?", e.Endpoint)
+		}
+		return fmt.Sprintf("the connection to %s was refused: check your network, proxy, and firewall settings", e.Endpoint)
+	case networkErrorTLS:
+		return fmt.Sprintf("the TLS certificate of %s is not trusted (%s): if the endpoint uses a private certificate authority, pass its certificate with --%s", e.Endpoint, e.err, CAFileFlag)
+	}
+	return e.err.Error()
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.err
+}
+
+func (e *NetworkError) Timeout() bool {
+	return false
+}
+
+func (e *NetworkError) Temporary() bool {
+	return e.kind == networkErrorOffline
+}
+
+// withNetworkError replaces errors caused by a failed connection with the NetworkError, whose message explains what to
+// check. Other errors are returned as is.
+func withNetworkError(err error) error {
+	var netErr *NetworkError
+	if errors.As(err, &netErr) && netErr.kind != networkErrorOther {
+		return errors.WithStack(netErr)
+	}
+	return err
+}
+
+type connectivityKey struct{}
+
+// connectivity tracks whether any request of a command reached a server, to tell being offline apart from a single
+// unreachable endpoint.
+type connectivity struct {
+	mu        sync.Mutex
+	connected bool
+}
+
+// contextWithConnectivity makes all Ory Cloud API clients track whether they reached a server.
+func contextWithConnectivity(ctx context.Context) context.Context {
+	return context.WithValue(ctx, connectivityKey{}, new(connectivity))
+}
+
+// networkTransport classifies the errors of requests which failed before the server responded as NetworkError. If no
+// request of the command reached a server and the network seems to be unavailable, the error says so explicitly.
+type networkTransport struct {
+	http.RoundTripper
+}
+
+func (t *networkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c, _ := req.Context().Value(connectivityKey{}).(*connectivity)
+	res, err := t.RoundTripper.RoundTrip(req)
+	if err == nil {
+		if c != nil {
+			c.mu.Lock()
+			c.connected = true
+			c.mu.Unlock()
+		}
+		return res, nil
+	}
+
+	kind, offline := classifyNetworkError(err)
+	if kind == networkErrorOther && !offline {
+		return nil, err
+	}
+	if offline && c != nil {
+		c.mu.Lock()
+		if !c.connected {
+			kind = networkErrorOffline
+		}
+		c.mu.Unlock()
+	}
+	endpoint := &url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host}
+	return nil, &NetworkError{Endpoint: endpoint.String(), kind: kind, custom: !isPublicOryEndpoint(req.URL), err: err}
+}
+
+// classifyNetworkError returns the kind of err, and whether err indicates that the network is unavailable.
+func classifyNetworkError(err error) (kind networkErrorKind, offline bool) {
+	var (
+		dnsErr      *net.DNSError
+		opErr       *net.OpError
+		authority   x509.UnknownAuthorityError
+		invalid     x509.CertificateInvalidError
+		hostname    x509.HostnameError
+		systemRoots x509.SystemRootsError
+	)
+	switch {
+	case errors.As(err, &dnsErr):
+		// A host which does not exist is most likely a typo, other DNS failures mean that no DNS server is reachable.
+		return networkErrorDNS, !dnsErr.IsNotFound
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return networkErrorRefused, false
+	case errors.Is(err, syscall.ENETUNREACH), errors.Is(err, syscall.EHOSTUNREACH):
+		return networkErrorOther, true
+	case errors.As(err, &authority), errors.As(err, &invalid), errors.As(err, &hostname), errors.As(err, &systemRoots):
+		return networkErrorTLS, false
+	case errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout():
+		return networkErrorOther, true
+	}
+	return networkErrorOther, false
+}
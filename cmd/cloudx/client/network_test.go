@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingTransport struct {
+	err error
+}
+
+func (t *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+func TestNetworkErrors(t *testing.T) {
+	get := func(t *testing.T, ctx context.Context, base http.RoundTripper, u string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		require.NoError(t, err)
+		res, err := (&http.Client{Transport: &networkTransport{RoundTripper: base}}).Do(req)
+		if err == nil {
+			_ = res.Body.Close()
+		}
+		return withNetworkError(err)
+	}
+
+	t.Run("case=host not found", func(t *testing.T) {
+		t.Setenv(consoleURLEnv, "https://console.ory.typo")
+		err := get(t, context.Background(), &failingTransport{err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "api.console.ory.typo", IsNotFound: true}}}, "https://api.console.ory.typo/projects")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to resolve the host of https://api.console.ory.typo")
+		assert.Contains(t, err.Error(), "the endpoint is derived from ORY_CLOUD_CONSOLE_URL=https://console.ory.typo")
+		assert.Equal(t, ExitNetwork, ExitCode(err))
+	})
+
+	t.Run("case=connection refused", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := l.Addr().String()
+		require.NoError(t, l.Close())
+
+		err = get(t, context.Background(), http.DefaultTransport, "http://"+addr+"/admin/identities")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the connection to http://"+addr+" was refused: is the self-hosted Ory service running")
+		assert.Equal(t, ExitNetwork, ExitCode(err))
+
+		err = get(t, context.Background(), &failingTransport{err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, "https://project.projects.oryapis.com/sessions")
+		assert.Contains(t, err.Error(), "check your network, proxy, and firewall settings")
+	})
+
+	t.Run("case=untrusted certificate", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.NotFoundHandler())
+		t.Cleanup(ts.Close)
+
+		err := get(t, context.Background(), http.DefaultTransport, ts.URL)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the TLS certificate of "+ts.URL+" is not trusted")
+		assert.Contains(t, err.Error(), "--ca-file")
+		assert.Equal(t, ExitNetwork, ExitCode(err))
+	})
+
+	t.Run("case=offline", func(t *testing.T) {
+		unreachable := &failingTransport{err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ENETUNREACH)}}
+		ctx := contextWithConnectivity(context.Background())
+		err := get(t, ctx, unreachable, "https://api.console.ory.sh/projects")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to connect to https://api.console.ory.sh, you seem to be offline")
+		assert.Equal(t, ExitNetwork, ExitCode(err))
+
+		ts := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(ts.Close)
+		require.NoError(t, get(t, ctx, http.DefaultTransport, ts.URL))
+		err = get(t, ctx, unreachable, "https://api.console.ory.sh/projects")
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "offline", "a server was reached before")
+	})
+}
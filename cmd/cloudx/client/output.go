@@ -0,0 +1,220 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	FormatDefault    = ""
+	FormatJSON       = "json"
+	FormatJSONPretty = "json-pretty"
+	// FormatNDJSON prints lists as newline delimited JSON, one item per line.
+	FormatNDJSON    = "ndjson"
+	FormatYAML      = "yaml"
+	FormatTable     = "table"
+	FormatTableWide = "table-wide"
+	FormatNone      = "none"
+	// FormatTemplate is the format of --format template=TEMPLATE and --format template-file=FILE, see
+	// EnableTemplateFormat.
+	FormatTemplate = "template"
+)
+
+var formats = []string{FormatJSON, FormatJSONPretty, FormatNDJSON, FormatYAML, FormatTable, FormatTableWide, FormatNone, FormatGitHub}
+
+type formatValue struct {
+	raw      string
+	format   string
+	template *template.Template
+}
+
+func (f *formatValue) String() string {
+	return f.raw
+}
+
+func (f *formatValue) Set(v string) error {
+	if strings.HasPrefix(v, templatePrefix) || strings.HasPrefix(v, templateFilePrefix) {
+		t, err := parseOutputTemplate(v)
+		if err != nil {
+			return err
+		}
+		*f = formatValue{raw: v, format: FormatTemplate, template: t}
+		return nil
+	}
+	for _, format := range formats {
+		if v == format {
+			*f = formatValue{raw: v, format: v}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q, expected one of: %s, %sTEMPLATE, %sFILE", v, strings.Join(formats, ", "), templatePrefix, templateFilePrefix)
+}
+
+func (*formatValue) Type() string {
+	return "string"
+}
+
+// RegisterFormatFlag registers the --format and --query flags. If the format is not set, each command prints its
+// default human readable output.
+func RegisterFormatFlag(f *pflag.FlagSet) {
+	var v formatValue
+	f.Var(&v, cmdx.FlagFormat, fmt.Sprintf("Set the output format. One of %s, %sTEMPLATE, or %sFILE. Templates use Go template syntax and are executed per item of lists. Defaults to human readable output.", strings.Join(formats, ", "), templatePrefix, templateFilePrefix))
+	registerQueryFlag(f)
+}
+
+// OutputFormat returns the value of the --format flag or FormatDefault if the command does not have the flag. All
+// templates are returned as FormatTemplate.
+func OutputFormat(cmd *cobra.Command) string {
+	f := cmd.Flags().Lookup(cmdx.FlagFormat)
+	if f == nil {
+		return FormatDefault
+	}
+	if v, ok := f.Value.(*formatValue); ok {
+		return v.format
+	}
+	return f.Value.String()
+}
+
+// IsMachineReadableFormat returns true if the --format flag is set to a machine readable format such as JSON.
+func IsMachineReadableFormat(cmd *cobra.Command) bool {
+	switch OutputFormat(cmd) {
+	case FormatJSON, FormatJSONPretty, FormatNDJSON, FormatYAML:
+		return true
+	}
+	return false
+}
+
+// PrintRow prints a single resource. Resources which do not implement cmdx.TableRow are printed as JSON instead of a
+// table. Timestamps in human readable output are rendered in the local timezone, see RegisterUTCFlag.
+func PrintRow(cmd *cobra.Command, v interface{}) {
+	row, ok := v.(cmdx.TableRow)
+	switch format := OutputFormat(cmd); {
+	case format == FormatNone:
+	case IsMachineReadableFormat(cmd):
+		if ok {
+			v = row.Interface()
+		}
+		printStructured(cmd, format, v)
+	case !ok:
+		printStructured(cmd, FormatJSONPretty, v)
+	default:
+		cmdx.PrintRow(cmd, &timeRow{row: row, f: newTimeFormatter(cmd, time.Now())})
+	}
+}
+
+// PrintTable prints a collection of resources sorted by the --sort flag, if set. Empty collections are printed as [] in
+// machine readable formats. Tables implementing WideTable show all their columns in the table-wide format. See
+// EnableColumnsFlag for selecting the columns of the table. Timestamps in human readable output are rendered in the local
+// timezone, and relative to now if they are recent, except in the table-wide format.
+func PrintTable(cmd *cobra.Command, table cmdx.Table) {
+	if w, ok := table.(WideTable); ok && (OutputFormat(cmd) == FormatTableWide || IsMachineReadableFormat(cmd)) {
+		// The wide columns can also be selected using --sort and --columns.
+		table = &wideTable{w}
+	}
+	table, err := sortTable(cmd, table)
+	if err != nil {
+		if !recordSortError(cmd, err) {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to sort the output: %s\n", err)
+		}
+		return
+	}
+
+	switch format := OutputFormat(cmd); {
+	case format == FormatNone:
+	case IsMachineReadableFormat(cmd):
+		recordColumnTable(cmd, table)
+		v := table.Interface()
+		if table.Len() == 0 {
+			v = []interface{}{}
+		}
+		printStructured(cmd, format, v)
+	default:
+		cmdx.PrintTable(cmd, &timeTable{table: table, f: newTimeFormatter(cmd, time.Now())})
+	}
+}
+
+// WideTable is implemented by tables which have more columns than fit a terminal. The wide columns are printed with
+// --format table-wide and should include all columns of the default table.
+type WideTable interface {
+	cmdx.Table
+	WideHeader() []string
+	WideTable() [][]string
+}
+
+// wideTable prints the wide columns of a WideTable. Long lines are not truncated but wrapped by the terminal.
+type wideTable struct {
+	WideTable
+}
+
+func (t *wideTable) Header() []string {
+	return t.WideHeader()
+}
+
+func (t *wideTable) Table() [][]string {
+	return t.WideTable.WideTable()
+}
+
+// PrintJSONAble prints resources which have no table representation, e.g. configurations. The human readable output
+// uses the String method if implemented and pretty JSON otherwise.
+func PrintJSONAble(cmd *cobra.Command, v interface{}) {
+	switch format := OutputFormat(cmd); {
+	case format == FormatNone:
+	case IsMachineReadableFormat(cmd):
+		printStructured(cmd, format, v)
+	default:
+		if s, ok := v.(fmt.Stringer); ok {
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), s.String())
+			return
+		}
+		printStructured(cmd, FormatJSONPretty, v)
+	}
+}
+
+func printStructured(cmd *cobra.Command, format string, v interface{}) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.IsNil() {
+		v = []interface{}{}
+	}
+
+	if format == FormatNDJSON {
+		printNDJSON(cmd, v)
+		return
+	}
+
+	var out []byte
+	var err error
+	switch format {
+	case FormatJSONPretty:
+		out, err = json.MarshalIndent(v, "", "  ")
+	case FormatYAML:
+		out, err = yaml.Marshal(v)
+	default:
+		out, err = json.Marshal(v)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to encode the output: %s\n", err)
+		return
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSuffix(string(out), "\n"))
+}
+
+// printNDJSON prints every item of lists on its own line. Everything else is printed as a single line.
+func printNDJSON(cmd *cobra.Command, v interface{}) {
+	for _, item := range sliceItems(v) {
+		out, err := json.Marshal(item)
+		if err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Unable to encode the output: %s\n", err)
+			return
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(out))
+	}
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOutputCmd(t *testing.T, format string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	RegisterFormatFlag(cmd.Flags())
+	if format != "" {
+		require.NoError(t, cmd.Flags().Set("format", format))
+	}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func TestRegisterFormatFlag(t *testing.T) {
+	cmd, _ := newOutputCmd(t, "")
+	assert.Equal(t, FormatDefault, OutputFormat(cmd))
+	assert.False(t, IsMachineReadableFormat(cmd))
+
+	err := cmd.Flags().Set("format", "xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "json, json-pretty, ndjson, yaml, table, table-wide, none")
+
+	assert.Equal(t, FormatDefault, OutputFormat(&cobra.Command{}))
+}
+
+func TestPrintJSONAble(t *testing.T) {
+	for format, expected := range map[string]string{
+		FormatJSON:       `{"a":1}` + "\n",
+		FormatJSONPretty: "{\n  \"a\": 1\n}\n",
+		FormatNone:       "",
+		FormatDefault:    "{\n  \"a\": 1\n}\n",
+	} {
+		t.Run("format="+format, func(t *testing.T) {
+			cmd, out := newOutputCmd(t, format)
+			PrintJSONAble(cmd, map[string]int{"a": 1})
+			assert.Equal(t, expected, out.String())
+		})
+	}
+
+	t.Run("case=nil slices are printed as empty arrays", func(t *testing.T) {
+		cmd, out := newOutputCmd(t, FormatJSON)
+		var v []string
+		PrintJSONAble(cmd, v)
+		assert.Equal(t, "[]\n", out.String())
+	})
+}
+
+type testWideTable struct {
+	testIdentities
+}
+
+func (testWideTable) WideHeader() []string {
+	return []string{"ID", "VERIFIED ADDRESS", "STATE"}
+}
+
+func (t testWideTable) WideTable() [][]string {
+	rows := t.Table()
+	for k := range rows {
+		rows[k] = append(rows[k], t.testIdentities[k]["state"].(string))
+	}
+	return rows
+}
+
+func TestPrintTableWide(t *testing.T) {
+	table := testWideTable{testIdentities{
+		{"id": "a", "traits": map[string]interface{}{"email": "a@example.com"}, "state": "active"},
+	}}
+
+	cmd, out := newOutputCmd(t, FormatTableWide)
+	PrintTable(cmd, table)
+	assert.Contains(t, out.String(), "STATE")
+	assert.Contains(t, out.String(), "active")
+
+	cmd, out = newOutputCmd(t, FormatTable)
+	PrintTable(cmd, table)
+	assert.NotContains(t, out.String(), "STATE")
+}
+
+func TestPrintTableNDJSON(t *testing.T) {
+	cmd, out := newOutputCmd(t, FormatNDJSON)
+	PrintTable(cmd, testIdentities{
+		{"id": "a", "traits": map[string]interface{}{"email": "a@example.com"}},
+		{"id": "b", "traits": map[string]interface{}{"email": "b@example.com"}},
+	})
+	assert.Equal(t, `{"id":"a","traits":{"email":"a@example.com"}}`+"\n"+`{"id":"b","traits":{"email":"b@example.com"}}`+"\n", out.String())
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+const NoPagerFlag = "no-pager"
+
+// RegisterNoPagerFlag registers the flag which disables the pager.
+func RegisterNoPagerFlag(f *pflag.FlagSet) {
+	f.Bool(NoPagerFlag, false, "Do not pipe human readable output which is longer than the terminal through $PAGER.")
+}
+
+// EnablePager pipes the human readable output of cmd and all its sub commands through $PAGER (defaulting to
+// `less -R`) if stdout is a terminal and the output does not fit on the screen. The pager is never used for
+// machine readable formats or if the --no-pager flag is set.
+func EnablePager(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnablePager(c)
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		height, ok := pagerHeight(cmd, out)
+		if !ok {
+			return run(cmd, args)
+		}
+
+		p := newPager(out, cmd.ErrOrStderr(), pagerCommand(), height)
+		cmd.SetOut(p)
+		err := run(cmd, args)
+		cmd.SetOut(out)
+		p.Close()
+		return err
+	}
+}
+
+// pagerHeight returns the height of the terminal and whether the output of cmd should be paged at all.
+func pagerHeight(cmd *cobra.Command, out io.Writer) (int, bool) {
+	if f := cmd.Flags().Lookup(NoPagerFlag); f != nil && f.Value.String() == "true" {
+		return 0, false
+	}
+	if IsMachineReadableFormat(cmd) || OutputFormat(cmd) == FormatNone {
+		return 0, false
+	}
+
+	f, ok := out.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return 0, false
+	}
+	_, height, err := term.GetSize(int(f.Fd()))
+	if err != nil || height <= 0 {
+		return 0, false
+	}
+	return height, true
+}
+
+func pagerCommand() []string {
+	if command := strings.Fields(os.Getenv("PAGER")); len(command) > 0 {
+		return command
+	}
+	return []string{"less", "-R"}
+}
+
+// pager buffers the output until it exceeds the given number of lines and only then starts the pager. Shorter output
+// is printed directly when the pager is closed.
+type pager struct {
+	out, errOut io.Writer
+	command     []string
+	height      int
+
+	buf   bytes.Buffer
+	lines int
+
+	// dst is nil while buffering. Afterwards, it is either the stdin of the pager, the output if the pager could not
+	// be started, or io.Discard if the user quit the pager.
+	dst     io.Writer
+	proc    *exec.Cmd
+	stdin   io.WriteCloser
+	signals chan os.Signal
+}
+
+func newPager(out, errOut io.Writer, command []string, height int) *pager {
+	return &pager{out: out, errOut: errOut, command: command, height: height}
+}
+
+func (p *pager) Write(b []byte) (int, error) {
+	if p.dst != nil {
+		if _, err := p.dst.Write(b); err != nil {
+			// The user quit the pager before reading all the output.
+			p.dst = io.Discard
+		}
+		return len(b), nil
+	}
+
+	p.buf.Write(b)
+	p.lines += bytes.Count(b, []byte("\n"))
+	if p.lines >= p.height {
+		p.start()
+	}
+	return len(b), nil
+}
+
+func (p *pager) start() {
+	proc := exec.Command(p.command[0], p.command[1:]...)
+	proc.Stdout = p.out
+	proc.Stderr = p.errOut
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		// Make less quit on Ctrl-C like other pagers do, unless the user configured it otherwise.
+		proc.Env = append(os.Environ(), "LESS=K")
+	}
+
+	stdin, err := proc.StdinPipe()
+	if err == nil {
+		err = proc.Start()
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(p.errOut, "Unable to start the pager %q, printing the output directly: %s\n", strings.Join(p.command, " "), err)
+		p.dst = p.out
+		_, _ = p.out.Write(p.buf.Bytes())
+		p.buf.Reset()
+		return
+	}
+
+	// Ctrl-C is sent to the whole process group. The pager handles it, while the CLI ignores it until the pager
+	// exits so that it is not killed with the pager still attached to the terminal.
+	p.signals = make(chan os.Signal, 1)
+	signal.Notify(p.signals, os.Interrupt)
+	atomic.AddInt32(&childProcesses, 1)
+
+	p.proc, p.stdin, p.dst = proc, stdin, stdin
+	_, _ = p.Write(p.buf.Bytes())
+	p.buf.Reset()
+}
+
+// Close prints buffered output and waits for the user to quit the pager.
+func (p *pager) Close() {
+	switch {
+	case p.proc != nil:
+		_ = p.stdin.Close()
+		_ = p.proc.Wait()
+		signal.Stop(p.signals)
+		atomic.AddInt32(&childProcesses, -1)
+	case p.dst == nil:
+		_, _ = p.out.Write(p.buf.Bytes())
+	}
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPager(t *testing.T) {
+	long := strings.Repeat("line\n", 10)
+
+	t.Run("case=prints short output directly", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		p := newPager(&out, &errOut, []string{"does-not-exist"}, 20)
+		_, _ = p.Write([]byte(long))
+		assert.Empty(t, out.String())
+		p.Close()
+		assert.Equal(t, long, out.String())
+		assert.Empty(t, errOut.String())
+	})
+
+	t.Run("case=pipes long output through the pager", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		p := newPager(&out, &errOut, []string{"cat"}, 5)
+		_, _ = p.Write([]byte(long))
+		_, _ = p.Write([]byte("more\n"))
+		p.Close()
+		assert.Equal(t, long+"more\n", out.String())
+		assert.Empty(t, errOut.String())
+	})
+
+	t.Run("case=falls back to printing directly if the pager does not exist", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		p := newPager(&out, &errOut, []string{"does-not-exist", "-R"}, 5)
+		_, _ = p.Write([]byte(long))
+		_, _ = p.Write([]byte("more\n"))
+		p.Close()
+		assert.Equal(t, long+"more\n", out.String())
+		assert.Contains(t, errOut.String(), `Unable to start the pager "does-not-exist -R"`)
+	})
+
+	t.Run("case=defaults to less", func(t *testing.T) {
+		t.Setenv("PAGER", "")
+		assert.Equal(t, []string{"less", "-R"}, pagerCommand())
+		t.Setenv("PAGER", "more -s")
+		assert.Equal(t, []string{"more", "-s"}, pagerCommand())
+	})
+
+	t.Run("case=never pages if stdout is not a terminal", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		RegisterNoPagerFlag(cmd.Flags())
+		_, ok := pagerHeight(cmd, new(bytes.Buffer))
+		assert.False(t, ok)
+	})
+}
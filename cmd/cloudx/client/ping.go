@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// pingTimeout is the timeout of every probe of Ping, unless the --timeout flag is set explicitly.
+const pingTimeout = 5 * time.Second
+
+// The states of a PingCheck.
+const (
+	PingPassed  = "pass"
+	PingFailed  = "fail"
+	PingSkipped = "skip"
+)
+
+// PingCheck is the result of one probe of Ping.
+type PingCheck struct {
+	Name          string        `json:"name"`
+	Status        string        `json:"status"`
+	Latency       time.Duration `json:"-"`
+	LatencyMillis int64         `json:"latency_ms"`
+	Details       string        `json:"details"`
+	// Err is the error of failed checks.
+	Err error `json:"-"`
+}
+
+// Ping checks the connectivity to Ory Cloud: whether the Ory Console and the project API are reachable, whether the
+// session is valid, and which proxy is used. The probes run concurrently and only send read-only requests. Checks
+// which need a session are skipped if the user is not signed in, and the user is never prompted.
+func (h *CommandHelper) Ping(cmd *cobra.Command) []*PingCheck {
+	ctx := contextWithMaxRetries(h.Ctx, 0)
+	if !h.timeoutSet {
+		ctx = contextWithTimeout(ctx, pingTimeout)
+	}
+
+	var token string
+	var selected uuid.UUID
+	if c, err := h.readConfig(); err == nil {
+		token, selected = c.SessionToken, c.SelectedProject
+	}
+	if h.sessionToken != "" {
+		token = h.sessionToken
+	}
+
+	checks := []*PingCheck{
+		{Name: "console"},
+		{Name: "authentication"},
+		{Name: "project API"},
+		{Name: "proxy"},
+	}
+	probes := []func(*PingCheck) error{
+		func(c *PingCheck) error {
+			return h.probeURL(ctx, c, makeCloudConsoleURL("api")+"/health/alive")
+		},
+		func(c *PingCheck) error {
+			return h.probeSession(ctx, c, token)
+		},
+		func(c *PingCheck) error {
+			return h.probeProject(ctx, c, token, selected)
+		},
+		func(c *PingCheck) error {
+			return probeProxy(cmd, c)
+		},
+	}
+
+	var wg sync.WaitGroup
+	for k := range checks {
+		wg.Add(1)
+		go func(c *PingCheck, probe func(*PingCheck) error) {
+			defer wg.Done()
+			start := time.Now()
+			err := probe(c)
+			c.Latency = time.Since(start)
+			c.LatencyMillis = c.Latency.Milliseconds()
+			switch {
+			case err != nil:
+				c.Status, c.Err, c.Details = PingFailed, withNetworkError(err), withNetworkError(err).Error()
+			case c.Status == "":
+				c.Status = PingPassed
+			}
+		}(checks[k], probes[k])
+	}
+	wg.Wait()
+	return checks
+}
+
+// probeURL checks that u is reachable. Every HTTP response counts, because only the network path is checked.
+func (h *CommandHelper) probeURL(ctx context.Context, c *PingCheck, u string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	res, err := h.client.httpClient("").Do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	c.Details = fmt.Sprintf("%s responded with %s", req.URL.Host, res.Status)
+	return nil
+}
+
+// probeSession checks that the session token is valid.
+func (h *CommandHelper) probeSession(ctx context.Context, c *PingCheck, token string) error {
+	if token == "" {
+		c.Status, c.Details = PingSkipped, "not signed in, run `ory auth` to sign in"
+		return nil
+	}
+	sess, err := h.checkSession(ctx, token)
+	if err != nil {
+		return err
+	}
+	c.Details = "signed in"
+	if email, found := identityEmail(sess.Identity); found {
+		c.Details = "signed in as " + email
+	}
+	return nil
+}
+
+// probeProject checks that the API of the project set by --project, or of the selected project, is reachable.
+// Resolving the slug of the project requires a session, unless --project is set to the slug.
+func (h *CommandHelper) probeProject(ctx context.Context, c *PingCheck, token string, selected uuid.UUID) error {
+	slug := h.Project
+	if id, err := uuid.FromString(h.Project); err == nil {
+		slug, selected = "", id
+	}
+	if slug == "" {
+		switch {
+		case selected == uuid.Nil:
+			c.Status, c.Details = PingSkipped, fmt.Sprintf("no project selected, use --%s or `ory use project`", projectFlag)
+			return nil
+		case token == "":
+			c.Status, c.Details = PingSkipped, "sign in to look up the project, or set --"+projectFlag+" to its slug"
+			return nil
+		}
+		p, _, err := h.client.consoleAPI(token).V0alpha2Api.GetProject(ctx, selected.String()).Execute()
+		if err != nil {
+			return errors.Wrapf(err, "unable to look up project %s", selected)
+		}
+		slug = p.Slug
+	}
+	return h.probeURL(ctx, c, ProjectAPIURL(slug)+"/health/alive")
+}
+
+// probeProxy reports the proxy which API requests go through. It only fails if the proxy is misconfigured.
+func probeProxy(cmd *cobra.Command, c *PingCheck) error {
+	c.Details = "none, requests are sent directly"
+	if proxy, err := Proxy(cmd); err != nil {
+		return err
+	} else if proxy != nil {
+		source := ProxyEnv
+		if f := cmd.Flags().Lookup(ProxyFlag); f != nil && f.Changed {
+			source = "--" + ProxyFlag
+		}
+		c.Details = fmt.Sprintf("%s (from %s)", proxy.Redacted(), source)
+		return nil
+	}
+
+	u := consoleURL()
+	u.Host = "api." + u.Host
+	if proxy, err := httpproxy.FromEnvironment().ProxyFunc()(u); err != nil {
+		return errors.WithStack(err)
+	} else if proxy != nil {
+		c.Details = fmt.Sprintf("%s (from the HTTPS_PROXY or HTTP_PROXY environment variables)", proxy.Redacted())
+	}
+	return nil
+}
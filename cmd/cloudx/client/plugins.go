@@ -0,0 +1,171 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	// PluginPrefix is the prefix of the executables which extend the CLI, e.g. ory-foo is run by `ory foo`.
+	PluginPrefix = "ory-"
+	// NoPluginsEnv disables plugins if set.
+	NoPluginsEnv = "ORY_NO_PLUGINS"
+)
+
+// Plugin is an executable on PATH which is run for an unknown sub command of the CLI.
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// PluginsDisabled returns true if the ORY_NO_PLUGINS environment variable is set.
+func PluginsDisabled() bool {
+	_, ok := os.LookupEnv(NoPluginsEnv)
+	return ok
+}
+
+// pluginDirs returns the directories of PATH in which plugins are looked up. Relative directories, including the
+// current directory, are skipped, so that running the CLI in an untrusted directory never runs its executables.
+func pluginDirs() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// pluginName returns the name of the plugin if file is a plugin executable.
+func pluginName(file os.DirEntry) (string, bool) {
+	name := file.Name()
+	if !strings.HasPrefix(name, PluginPrefix) || file.IsDir() {
+		return "", false
+	}
+	info, err := file.Info()
+	if err != nil {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	} else if info.Mode().Perm()&0111 == 0 {
+		return "", false
+	}
+	name = strings.TrimPrefix(name, PluginPrefix)
+	return name, name != ""
+}
+
+// FindPlugins lists the plugins on PATH sorted by name. If several directories contain a plugin of the same name, the
+// first one wins, like in the shell.
+func FindPlugins() []Plugin {
+	seen := map[string]bool{}
+	var plugins []Plugin
+	for _, dir := range pluginDirs() {
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name, ok := pluginName(f)
+			if !ok || seen[name] {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: filepath.Join(dir, f.Name())})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// LookupPlugin returns the plugin with the name, or false if there is none.
+func LookupPlugin(name string) (*Plugin, bool) {
+	for _, p := range FindPlugins() {
+		if p.Name == name {
+			return &p, true
+		}
+	}
+	return nil, false
+}
+
+// AddPluginCommand adds a command running the plugin to root if args invoke an unknown sub command of root and a
+// plugin of that name exists. Flags of the CLI must not precede the plugin's name, all arguments after it are passed
+// to the plugin.
+func AddPluginCommand(root *cobra.Command, args []string) {
+	if PluginsDisabled() || len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return
+	}
+	if c, _, err := root.Find(args); err == nil && c != root {
+		return
+	}
+	p, ok := LookupPlugin(args[0])
+	if !ok {
+		return
+	}
+	root.AddCommand(newPluginCmd(p))
+}
+
+func newPluginCmd(p *Plugin) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                p.Name,
+		Short:              "Run the plugin " + p.Path,
+		Hidden:             true,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+			env, err := h.pluginEnv()
+			if err != nil {
+				return err
+			}
+			h.Log.Debugf("Running the plugin %s", p.Path)
+			return RunChild(cmd, append([]string{p.Path}, args...), env)
+		},
+	}
+	RegisterConfigFlag(cmd.PersistentFlags())
+	RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	return cmd
+}
+
+// pluginEnv returns the environment of plugins, so that they do not need to implement signing in:
+//
+//   - ORY_CLOUD_CONFIG_PATH: the configuration file, so that plugins running the CLI use the same session.
+//   - ORY_SESSION_TOKEN: the session token, if signed in or set by --session-token.
+//   - ORY_PROJECT: the ID of the selected project, if any.
+//
+// The session is not checked, plugins get the same errors as the CLI if it expired.
+func (h *CommandHelper) pluginEnv() ([]EnvVar, error) {
+	env := []EnvVar{{Name: osEnvVar, Value: h.ConfigLocation}}
+	if h.sessionToken != "" {
+		env = append(env, EnvVar{Name: SessionTokenEnv, Value: h.sessionToken})
+	}
+	c, err := h.readConfig()
+	if errors.Is(err, ErrNoConfig) {
+		return env, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if c.SessionToken != "" && h.sessionToken == "" {
+		env = append(env, EnvVar{Name: SessionTokenEnv, Value: c.SessionToken})
+	}
+	if c.SelectedProject != uuid.Nil {
+		env = append(env, EnvVar{Name: "ORY_PROJECT", Value: c.SelectedProject.String()})
+	}
+	return env, nil
+}
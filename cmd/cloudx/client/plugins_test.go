@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePlugin(t *testing.T, dir, name, script string, mode os.FileMode) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), mode))
+	return path
+}
+
+func TestFindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	deploy := writePlugin(t, first, "ory-deploy", "", 0755)
+	writePlugin(t, first, "ory-notes", "", 0644)
+	writePlugin(t, first, "kubectl-deploy", "", 0755)
+	writePlugin(t, second, "ory-deploy", "", 0755)
+	audit := writePlugin(t, second, "ory-audit", "", 0755)
+	require.NoError(t, os.Mkdir(filepath.Join(second, "ory-dir"), 0755))
+
+	relative := t.TempDir()
+	writePlugin(t, relative, "ory-evil", "", 0755)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(relative))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	t.Setenv("PATH", first+string(filepath.ListSeparator)+"."+string(filepath.ListSeparator)+string(filepath.ListSeparator)+second)
+	assert.Equal(t, []Plugin{{Name: "audit", Path: audit}, {Name: "deploy", Path: deploy}}, FindPlugins(),
+		"plugins are executables, the first one on PATH wins, and relative directories are skipped")
+
+	_, ok := LookupPlugin("evil")
+	assert.False(t, ok)
+	p, ok := LookupPlugin("deploy")
+	require.True(t, ok)
+	assert.Equal(t, deploy, p.Path)
+}
+
+func TestAddPluginCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "ory-hello", `echo "args: $*"; echo "config: $ORY_CLOUD_CONFIG_PATH"`, 0755)
+	writePlugin(t, dir, "ory-list", "echo shadowed", 0755)
+	t.Setenv("PATH", dir)
+
+	newRoot := func() *cobra.Command {
+		root := &cobra.Command{Use: "ory"}
+		root.AddCommand(&cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}})
+		return root
+	}
+	exec := func(args ...string) (*cobra.Command, string, error) {
+		root := newRoot()
+		AddPluginCommand(root, args)
+		var stdout bytes.Buffer
+		root.SetOut(&stdout)
+		root.SetErr(new(bytes.Buffer))
+		root.SetArgs(args)
+		err := root.ExecuteContext(context.Background())
+		return root, stdout.String(), err
+	}
+
+	t.Run("case=runs the plugin", func(t *testing.T) {
+		config := filepath.Join(t.TempDir(), "config.json")
+		t.Setenv(osEnvVar, config)
+		_, stdout, err := exec("hello", "world", "--flag")
+		require.NoError(t, err)
+		assert.Equal(t, "args: world --flag\nconfig: "+config+"\n", stdout)
+	})
+
+	t.Run("case=commands of the CLI win", func(t *testing.T) {
+		_, stdout, err := exec("list")
+		require.NoError(t, err)
+		assert.Empty(t, stdout, "the plugin ory-list is not run")
+	})
+
+	t.Run("case=flags must not precede the plugin", func(t *testing.T) {
+		root := newRoot()
+		AddPluginCommand(root, []string{"--verbose", "hello"})
+		assert.Len(t, root.Commands(), 1)
+	})
+
+	t.Run("case=plugins can be disabled", func(t *testing.T) {
+		t.Setenv(NoPluginsEnv, "1")
+		root := newRoot()
+		AddPluginCommand(root, []string{"hello"})
+		assert.Len(t, root.Commands(), 1)
+	})
+}
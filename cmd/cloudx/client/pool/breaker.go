@@ -0,0 +1,96 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxFailures is the number of consecutive failures after which a Breaker opens by default.
+const DefaultMaxFailures = 25
+
+// Outcome classifies the result of a request for a Breaker.
+type Outcome int
+
+const (
+	// Success means the API works, e.g. the request succeeded or the input was rejected. It ends a failure streak.
+	Success Outcome = iota
+	// Failure means the API is failing, e.g. it returned a server error or was unreachable.
+	Failure
+	// Ignored neither counts toward nor ends a failure streak, e.g. throttled requests.
+	Ignored
+)
+
+// BreakerError is the error of a Breaker which opened.
+type BreakerError struct {
+	// Failures is the number of consecutive failures which opened the breaker.
+	Failures int
+	// Last is the error of the last failure.
+	Last error
+}
+
+func (e *BreakerError) Error() string {
+	return fmt.Sprintf("stopped after %d consecutive failures, the last one was: %s", e.Failures, e.Last)
+}
+
+func (e *BreakerError) Unwrap() error {
+	return e.Last
+}
+
+// Breaker halts bulk operations once the API is consistently failing, so that they do not collect one error for
+// every remaining item. It opens after a streak of consecutive failures and cancels its context, which stops Run and
+// Stream from starting further items.
+type Breaker struct {
+	max      int
+	classify func(err error) Outcome
+	cancel   context.CancelFunc
+
+	mu     sync.Mutex
+	streak int
+	err    *BreakerError
+}
+
+// NewBreaker returns a breaker which opens after max consecutive failures, and a context derived from ctx which is
+// canceled once it opens. classify decides whether the error of a request is a failure. If max is less than 1, the
+// breaker never opens.
+func NewBreaker(ctx context.Context, max int, classify func(err error) Outcome) (context.Context, *Breaker) {
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, &Breaker{max: max, classify: classify, cancel: cancel}
+}
+
+// Record counts the outcome of a request. It is safe to call from multiple workers.
+func (b *Breaker) Record(err error) {
+	if b == nil {
+		return
+	}
+	outcome := Success
+	if err != nil {
+		outcome = b.classify(err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.err != nil:
+	case outcome == Success:
+		b.streak = 0
+	case outcome == Failure:
+		if b.streak++; b.max > 0 && b.streak >= b.max {
+			b.err = &BreakerError{Failures: b.streak, Last: err}
+			b.cancel()
+		}
+	}
+}
+
+// Err returns a BreakerError once the breaker opened, and nil before.
+func (b *Breaker) Err() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == nil {
+		return nil
+	}
+	return b.err
+}
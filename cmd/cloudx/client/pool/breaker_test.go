@@ -0,0 +1,82 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errServer    = errors.New("500 Internal Server Error")
+	errThrottled = errors.New("429 Too Many Requests")
+	errInvalid   = errors.New("400 Bad Request")
+)
+
+func classify(err error) Outcome {
+	switch {
+	case errors.Is(err, errServer):
+		return Failure
+	case errors.Is(err, errThrottled):
+		return Ignored
+	}
+	return Success
+}
+
+func TestBreaker(t *testing.T) {
+	t.Run("case=opens after consecutive failures and stops the pipeline", func(t *testing.T) {
+		for _, workers := range []int{1, 8} {
+			ctx, b := NewBreaker(context.Background(), DefaultMaxFailures, classify)
+			var requests int32
+			errs := Run(ctx, workers, 200000, func(context.Context, int) error {
+				atomic.AddInt32(&requests, 1)
+				b.Record(errServer)
+				return errServer
+			})
+
+			var bErr *BreakerError
+			require.ErrorAs(t, b.Err(), &bErr)
+			assert.Equal(t, DefaultMaxFailures, bErr.Failures)
+			assert.ErrorIs(t, b.Err(), errServer)
+
+			// Only the requests which were in flight when the breaker opened finish.
+			assert.GreaterOrEqual(t, int(requests), DefaultMaxFailures)
+			assert.Less(t, int(requests), DefaultMaxFailures+workers)
+			if workers == 1 {
+				assert.EqualValues(t, DefaultMaxFailures, requests, "no request is attempted after the breaker opened")
+			}
+			assert.ErrorIs(t, errs[len(errs)-1], context.Canceled)
+		}
+	})
+
+	t.Run("case=throttled requests do not count", func(t *testing.T) {
+		_, b := NewBreaker(context.Background(), 3, classify)
+		for _, err := range []error{errServer, errThrottled, errServer, errThrottled, errThrottled} {
+			b.Record(err)
+		}
+		assert.NoError(t, b.Err())
+		b.Record(errServer)
+		assert.Error(t, b.Err())
+	})
+
+	t.Run("case=successes end the streak", func(t *testing.T) {
+		ctx, b := NewBreaker(context.Background(), 3, classify)
+		for _, err := range []error{errServer, errServer, nil, errServer, errServer, errInvalid, errServer, errServer} {
+			b.Record(err)
+		}
+		assert.NoError(t, b.Err())
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("case=never opens without a maximum", func(t *testing.T) {
+		ctx, b := NewBreaker(context.Background(), 0, classify)
+		for k := 0; k < 1000; k++ {
+			b.Record(errServer)
+		}
+		assert.NoError(t, b.Err())
+		assert.NoError(t, ctx.Err())
+	})
+}
@@ -0,0 +1,102 @@
+// Package pool runs the items of bulk operations, e.g. the batches of an import, on a fixed number of concurrent
+// workers. The error of every item is captured individually, so that one failed item does not abort the others.
+package pool
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultWorkers is the number of workers of bulk operations if the --workers flag is not set. It is conservative
+	// so that bulk operations do not overload projects by default.
+	DefaultWorkers = 4
+	// MaxWorkers is the maximum number of workers. Higher values only lead to the API rate limiting the requests.
+	MaxWorkers = 32
+)
+
+// Run calls work for each of the n items using up to workers goroutines and returns the error of every item in the
+// order of the items. To collect ordered results, work stores the result of item i at index i of a slice.
+//
+// Once ctx is done, no further items are started and the items which were not started fail with the error of ctx.
+func Run(ctx context.Context, workers, n int, work func(ctx context.Context, i int) error) []error {
+	return Stream(ctx, workers, n, work, nil)
+}
+
+// Stream is like Run, but additionally calls done for every item as soon as it finished, in the order of completion.
+// done is called from the goroutine which called Stream, so it may update state like the progress without locking.
+// Items which were not started are not passed to done.
+func Stream(ctx context.Context, workers, n int, work func(ctx context.Context, i int) error, done func(i int, err error)) []error {
+	errs := make([]error, n)
+	if n == 0 {
+		return errs
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	type result struct {
+		i   int
+		err error
+	}
+	items := make(chan int)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				// The dispatcher might hand out an item at the same time as ctx is done.
+				if err := ctx.Err(); err != nil {
+					errs[i] = errors.WithStack(err)
+					continue
+				}
+				results <- result{i: i, err: call(ctx, i, work)}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(items)
+		for i := 0; i < n; i++ {
+			if ctx.Err() == nil {
+				select {
+				case items <- i:
+					continue
+				case <-ctx.Done():
+				}
+			}
+			errs[i] = errors.WithStack(ctx.Err())
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		errs[r.i] = r.err
+		if done != nil {
+			done(r.i, r.err)
+		}
+	}
+	return errs
+}
+
+// call runs work for item i and turns a panic into the error of the item so that the other workers can finish.
+func call(ctx context.Context, i int, work func(ctx context.Context, i int) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("worker panicked while processing item %d: %v\n%s", i, r, debug.Stack())
+		}
+	}()
+	return work(ctx, i)
+}
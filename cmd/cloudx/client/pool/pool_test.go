@@ -0,0 +1,148 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("case=returns ordered results with many workers", func(t *testing.T) {
+		for _, workers := range []int{0, 1, DefaultWorkers, MaxWorkers, 256} {
+			t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+				const n = 1000
+				var running, maxRunning int32
+				results := make([]int, n)
+				errs := Run(context.Background(), workers, n, func(_ context.Context, i int) error {
+					r := atomic.AddInt32(&running, 1)
+					defer atomic.AddInt32(&running, -1)
+					for {
+						m := atomic.LoadInt32(&maxRunning)
+						if r <= m || atomic.CompareAndSwapInt32(&maxRunning, m, r) {
+							break
+						}
+					}
+					results[i] = i * i
+					return nil
+				})
+
+				require.Len(t, errs, n)
+				for i := range results {
+					require.NoError(t, errs[i])
+					require.Equal(t, i*i, results[i])
+				}
+				expected := workers
+				if expected < 1 {
+					expected = 1
+				}
+				assert.LessOrEqual(t, int(atomic.LoadInt32(&maxRunning)), expected)
+			})
+		}
+	})
+
+	t.Run("case=captures the error of every item", func(t *testing.T) {
+		errs := Run(context.Background(), 64, 500, func(_ context.Context, i int) error {
+			switch i % 3 {
+			case 1:
+				return fmt.Errorf("item %d failed", i)
+			case 2:
+				panic("boom")
+			}
+			return nil
+		})
+
+		for i, err := range errs {
+			switch i % 3 {
+			case 0:
+				assert.NoError(t, err)
+			case 1:
+				assert.EqualError(t, err, fmt.Sprintf("item %d failed", i))
+			case 2:
+				assert.ErrorContains(t, err, fmt.Sprintf("worker panicked while processing item %d: boom", i))
+			}
+		}
+	})
+
+	t.Run("case=does not start items after the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var started int32
+		errs := Run(ctx, 8, 10000, func(ctx context.Context, i int) error {
+			if atomic.AddInt32(&started, 1) == 100 {
+				cancel()
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Millisecond):
+				return nil
+			}
+		})
+
+		var canceled int
+		for _, err := range errs {
+			if err != nil {
+				assert.ErrorIs(t, err, context.Canceled)
+				canceled++
+			}
+		}
+		assert.Less(t, int(atomic.LoadInt32(&started)), 200)
+		assert.Greater(t, canceled, 10000-200)
+	})
+
+	t.Run("case=handles no items", func(t *testing.T) {
+		assert.Empty(t, Run(context.Background(), 4, 0, func(context.Context, int) error {
+			panic("not called")
+		}))
+	})
+}
+
+func TestStream(t *testing.T) {
+	t.Run("case=reports every item in the calling goroutine", func(t *testing.T) {
+		const n = 2000
+		// The counters are not synchronized, the race detector fails the test if done is called concurrently.
+		var succeeded, failed int
+		seen := make([]bool, n)
+		errs := Stream(context.Background(), 128, n, func(_ context.Context, i int) error {
+			if i%10 == 0 {
+				return fmt.Errorf("item %d failed", i)
+			}
+			return nil
+		}, func(i int, err error) {
+			require.False(t, seen[i], "item %d reported twice", i)
+			seen[i] = true
+			if err != nil {
+				failed++
+			} else {
+				succeeded++
+			}
+		})
+
+		assert.Equal(t, n/10, failed)
+		assert.Equal(t, n-n/10, succeeded)
+		for i, err := range errs {
+			assert.Equal(t, i%10 == 0, err != nil, i)
+		}
+	})
+
+	t.Run("case=does not report items which were not started", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var reported int
+		errs := Stream(ctx, 4, 100, func(context.Context, int) error {
+			return nil
+		}, func(int, error) { reported++ })
+
+		assert.Zero(t, reported)
+		for _, err := range errs {
+			assert.ErrorIs(t, err, context.Canceled)
+		}
+	})
+}
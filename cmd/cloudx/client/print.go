@@ -1,21 +1,18 @@
 package client
 
 import (
-	"fmt"
-
 	"github.com/ory/client-go"
 )
 
 func (h *CommandHelper) PrintUpdateProjectWarnings(p *client.SuccessfulProjectUpdate) error {
 	if len(p.Warnings) > 0 {
-		_, _ = fmt.Fprintln(h.VerboseErrWriter)
-		_, _ = fmt.Fprintln(h.VerboseErrWriter, "Warnings were found.")
+		h.Log.Warnf("\nWarnings were found.")
 		for _, warning := range p.Warnings {
-			_, _ = fmt.Fprintf(h.VerboseErrWriter, "- %s\n", *warning.Message)
+			h.Log.Warnf("- %s", *warning.Message)
 		}
-		_, _ = fmt.Fprintln(h.VerboseErrWriter, "It is save to ignore these warnings unless your intention was to set these keys.")
+		h.Log.Infof("It is save to ignore these warnings unless your intention was to set these keys.")
 	}
 
-	_, _ = fmt.Fprintf(h.VerboseErrWriter, "\nProject updated successfully!\n")
+	h.Log.Infof("\n%s", h.Colors.Success("Project updated successfully!"))
 	return nil
 }
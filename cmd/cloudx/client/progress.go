@@ -0,0 +1,121 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// progressLogInterval is the number of records after which the progress is logged if stderr is not a terminal.
+const progressLogInterval = 1000
+
+// Progress reports the progress of bulk operations. If stderr is a terminal, it renders a single updating line.
+// Otherwise, it logs a line every progressLogInterval records at the info level. Progress never writes to stdout,
+// which carries the data, and writes nothing if the --quiet flag is set.
+type Progress struct {
+	w     io.Writer
+	log   *Logger
+	label string
+	total int
+	tty   bool
+	every int
+	now   func() time.Time
+	// limit is the rate to which the API requests are capped, see RegisterRateFlag.
+	limit Rate
+
+	start             time.Time
+	processed, failed int
+	logged            int
+}
+
+// NewProgress returns a Progress for the given number of records. Use 0 if the total is not known. If the --rate flag
+// caps the API requests, it logs the rate.
+func (h *CommandHelper) NewProgress(label string, total int) *Progress {
+	p := newProgress(h.VerboseErrWriter, h.Log, isTerminalWriter(h.VerboseErrWriter), label, total, time.Now)
+	p.limitTo(h.client.rate())
+	return p
+}
+
+func newProgress(w io.Writer, log *Logger, tty bool, label string, total int, now func() time.Time) *Progress {
+	return &Progress{w: w, log: log, tty: tty, label: label, total: total, every: progressLogInterval, now: now, start: now()}
+}
+
+// limitTo shows the rate to which the API requests are capped in the progress line.
+func (p *Progress) limitTo(r Rate) {
+	if r.IsZero() {
+		return
+	}
+	p.limit = r
+	p.log.Infof("%s: sending at most %s API requests", p.label, r)
+}
+
+// Add records processed and failed records. Failed records count as processed.
+func (p *Progress) Add(processed, failed int) {
+	p.processed += processed
+	p.failed += failed
+
+	if p.tty {
+		_, _ = fmt.Fprint(p.w, "\r\x1b[K"+p.status())
+	} else if p.processed-p.logged >= p.every || (p.total > 0 && p.processed == p.total && p.logged != p.processed) {
+		p.logged = p.processed
+		p.log.Infof("%s", p.status())
+	}
+}
+
+// Logf logs a warning on its own line without garbling the progress line.
+func (p *Progress) Logf(format string, args ...interface{}) {
+	if p.tty {
+		_, _ = fmt.Fprint(p.w, "\r\x1b[K")
+	}
+	p.log.Warnf(format, args...)
+	if p.tty {
+		_, _ = fmt.Fprint(p.w, p.status())
+	}
+}
+
+// Finish prints the summary line.
+func (p *Progress) Finish(interrupted bool) {
+	if p.tty {
+		_, _ = fmt.Fprint(p.w, "\r\x1b[K")
+	}
+
+	state := "done"
+	if interrupted {
+		state = "interrupted"
+	}
+	p.log.Infof("%s: %s, %d processed (%d failed) in %s", p.label, state, p.processed, p.failed, p.now().Sub(p.start).Round(time.Second))
+}
+
+// Halt prints the summary line of an operation which stopped early because the API is failing, including where it
+// stopped.
+func (p *Progress) Halt() {
+	if p.tty {
+		_, _ = fmt.Fprint(p.w, "\r\x1b[K")
+	}
+	p.log.Warnf("%s: stopped because the API is failing, %d of %d processed (%d failed) in %s", p.label, p.processed, p.total, p.failed, p.now().Sub(p.start).Round(time.Second))
+}
+
+func (p *Progress) status() string {
+	parts := []string{fmt.Sprintf("%d", p.processed)}
+	if p.total > 0 {
+		parts[0] = fmt.Sprintf("%d/%d (%d%%)", p.processed, p.total, p.processed*100/p.total)
+	}
+	if p.failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", p.failed))
+	}
+
+	elapsed := p.now().Sub(p.start)
+	if elapsed > 0 && p.processed > 0 {
+		rate := float64(p.processed) / elapsed.Seconds()
+		parts = append(parts, fmt.Sprintf("%.0f/s", rate))
+		if p.total > p.processed {
+			eta := time.Duration(float64(p.total-p.processed) / rate * float64(time.Second))
+			parts = append(parts, "ETA "+eta.Round(time.Second).String())
+		}
+	}
+	if !p.limit.IsZero() {
+		parts = append(parts, "requests capped at "+p.limit.String())
+	}
+	return p.label + ": " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	clock := func() (func() time.Time, func(time.Duration)) {
+		now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+		return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+	}
+
+	t.Run("case=logs every interval if not a terminal", func(t *testing.T) {
+		now, advance := clock()
+		var out bytes.Buffer
+		p := newProgress(&out, &Logger{out: &out, level: LogLevelInfo}, false, "Importing", 2500, now)
+
+		advance(time.Second)
+		p.Add(500, 0)
+		assert.Empty(t, out.String())
+
+		advance(time.Second)
+		p.Add(500, 10)
+		p.Logf("Unable to import batch")
+		advance(3 * time.Second)
+		p.Add(1500, 0)
+		p.Finish(false)
+
+		assert.Equal(t, `Importing: 1000/2500 (40%), 10 failed, 500/s, ETA 3s
+Unable to import batch
+Importing: 2500/2500 (100%), 10 failed, 500/s
+Importing: done, 2500 processed (10 failed) in 5s
+`, out.String())
+	})
+
+	t.Run("case=renders a single line on a terminal", func(t *testing.T) {
+		now, advance := clock()
+		var out bytes.Buffer
+		p := newProgress(&out, &Logger{out: &out, level: LogLevelInfo}, true, "Deleting", 0, now)
+
+		advance(2 * time.Second)
+		p.Add(10, 0)
+		p.Logf("oops")
+		p.Finish(true)
+
+		assert.Equal(t, "\r\x1b[KDeleting: 10, 5/s"+
+			"\r\x1b[Koops\nDeleting: 10, 5/s"+
+			"\r\x1b[KDeleting: interrupted, 10 processed (0 failed) in 2s\n", out.String())
+	})
+
+	t.Run("case=shows the capped request rate", func(t *testing.T) {
+		now, advance := clock()
+		var out bytes.Buffer
+		p := newProgress(&out, &Logger{out: &out, level: LogLevelInfo}, true, "Deleting", 0, now)
+		p.limitTo(Rate{Requests: 600, Per: time.Minute})
+
+		advance(2 * time.Second)
+		p.Add(10, 0)
+		assert.Equal(t, "Deleting: sending at most 600/min API requests\n"+
+			"\r\x1b[KDeleting: 10, 5/s, requests capped at 600/min", out.String())
+	})
+
+	t.Run("case=logs nothing below the info level", func(t *testing.T) {
+		now, _ := clock()
+		var out bytes.Buffer
+		p := newProgress(&out, &Logger{out: &out, level: LogLevelError}, false, "Importing", 1, now)
+
+		p.Add(1, 0)
+		p.Finish(false)
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,280 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/pkg/errors"
+
+	cloud "github.com/ory/client-go"
+)
+
+// APIError is returned by ProjectAPI when the server responds with a non-2xx status code.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       []byte
+	// RequestID identifies the failed request in the logs of the Ory Cloud APIs.
+	RequestID RequestID
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s failed with status code %d: %s", e.Method, e.URL, e.StatusCode, bytes.TrimSpace(e.Body))
+}
+
+// ProjectAPI talks to the APIs (identity, permission, ...) of a single Ory Cloud project, or to a self-hosted service,
+// see NewServiceAPI. Project is nil for self-hosted services.
+type ProjectAPI struct {
+	URL     string
+	Client  *http.Client
+	Project *cloud.Project
+	// Header is added to every request.
+	Header http.Header
+	// Log, if set, receives the rate limit and request ID of every response.
+	Log *Logger
+
+	debugLog *debugLog
+	// client creates the HTTP clients of derived ProjectAPIs, see WithoutCredentials.
+	client *Client
+}
+
+// Do sends a request to the project's API. The body, if not nil, is encoded as JSON and the
+// response, if out is not nil, is decoded into out.
+func (a *ProjectAPI) Do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (*http.Response, error) {
+	if body == nil {
+		return a.Send(ctx, method, path, query, "", nil, out)
+	}
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(body); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return a.Send(ctx, method, path, query, "application/json", &b, out)
+}
+
+// Send is like Do but sends the body as is using the given content type.
+func (a *ProjectAPI) Send(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out interface{}) (*http.Response, error) {
+	u := strings.TrimRight(a.URL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(contextWithLogger(contextWithDebugLog(ctx, a.debugLog), a.Log), method, u, body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for k, v := range a.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := a.Client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		b, _ := io.ReadAll(res.Body)
+		return res, errors.WithStack(&APIError{Method: method, URL: u, StatusCode: res.StatusCode, Body: b, RequestID: requestIDFromHeader(res.Header)})
+	}
+
+	if out != nil && res.StatusCode != http.StatusNoContent {
+		var err error
+		if items, ok := out.(Items); ok {
+			err = decodeItems(res.Body, items)
+		} else {
+			err = json.NewDecoder(res.Body).Decode(out)
+		}
+		if err != nil {
+			return res, errors.Wrapf(err, "unable to decode response of %s %s", method, u)
+		}
+	}
+
+	return res, nil
+}
+
+// SendRaw sends a request to the API and returns the response regardless of its status code. The target is the path
+// and query of the request. The caller closes the response body.
+func (a *ProjectAPI) SendRaw(ctx context.Context, method, target string, header http.Header, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(contextWithLogger(contextWithDebugLog(ctx, a.debugLog), a.Log), method, strings.TrimRight(a.URL, "/")+target, body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for k, v := range a.Header {
+		req.Header[k] = v
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	res, err := a.Client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// Items is called for every item of a response which is a JSON array. Pass it as out to Do to decode large responses,
+// e.g. long pages, incrementally: only the current item is held in memory, not the whole response. Returning an
+// error stops decoding.
+type Items func(item json.RawMessage) error
+
+// decodeItems calls items for every item of the JSON array read from r. A null response has no items.
+func decodeItems(r io.Reader, items Items) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return errors.Errorf("expected a JSON array but got %v", tok)
+	}
+
+	for dec.More() {
+		var item json.RawMessage
+		if err := dec.Decode(&item); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := items(item); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// NextPageQuery returns the query of the next page from the response's Link header, or nil if this is the last page.
+func NextPageQuery(res *http.Response) url.Values {
+	if res == nil {
+		return nil
+	}
+	for _, link := range res.Header.Values("Link") {
+		for _, part := range strings.Split(link, ",") {
+			if !strings.Contains(part, `rel="next"`) {
+				continue
+			}
+			start, end := strings.Index(part, "<"), strings.Index(part, ">")
+			if start < 0 || end < start {
+				continue
+			}
+			u, err := url.Parse(part[start+1 : end])
+			if err != nil {
+				continue
+			}
+			return u.Query()
+		}
+	}
+	return nil
+}
+
+// ProjectID returns the ID of the project set using the --project flag or, if the flag is
+// not set, the project selected in the Ory Cloud configuration. Project slugs are resolved
+// to their ID.
+func (h *CommandHelper) ProjectID() (string, error) {
+	if h.Project == "" {
+		ac, err := h.EnsureContext()
+		if err != nil {
+			return "", err
+		}
+		if ac.SelectedProject == uuid.Nil {
+			return "", errors.WithStack(ErrNoProjectSelected)
+		}
+		return ac.SelectedProject.String(), nil
+	}
+
+	return h.ResolveProjectID(h.Project)
+}
+
+// ResolveProjectID returns the ID of the project with the given ID or slug.
+func (h *CommandHelper) ResolveProjectID(idOrSlug string) (string, error) {
+	if id, err := uuid.FromString(idOrSlug); err == nil {
+		return id.String(), nil
+	}
+
+	projects, err := h.ListProjects()
+	if err != nil {
+		return "", err
+	}
+	for _, p := range projects {
+		if p.Slug != nil && *p.Slug == idOrSlug {
+			return p.Id, nil
+		}
+	}
+	return "", errors.WithStack(fmt.Errorf("%w: no project has the ID or slug %q", ErrProjectNotFound, idOrSlug))
+}
+
+// NewProjectAPI returns a ProjectAPI for the selected project which authenticates using the
+// Ory Cloud session token.
+func (h *CommandHelper) NewProjectAPI() (*ProjectAPI, error) {
+	ac, err := h.EnsureContext()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := h.ProjectID()
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := h.GetProject(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectAPI{
+		URL:      ProjectAPIURL(p.Slug),
+		Client:   h.client.httpClient(ac.SessionToken),
+		Project:  p,
+		Log:      h.Log,
+		debugLog: h.debugLog,
+		client:   h.client,
+	}, nil
+}
+
+// NewConsoleAPI returns a ProjectAPI which talks to the Ory Console API instead of a project's API. It authenticates
+// using the Ory Cloud session token.
+func (h *CommandHelper) NewConsoleAPI() (*ProjectAPI, error) {
+	ac, err := h.EnsureContext()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectAPI{
+		URL:      makeCloudConsoleURL("api"),
+		Client:   h.client.httpClient(ac.SessionToken),
+		Log:      h.Log,
+		debugLog: h.debugLog,
+		client:   h.client,
+	}, nil
+}
+
+// WithoutCredentials returns a copy of the ProjectAPI which does not send the Ory Cloud session token. Use it to call
+// the project's public APIs on behalf of end users.
+func (a *ProjectAPI) WithoutCredentials() *ProjectAPI {
+	return &ProjectAPI{
+		URL:      a.URL,
+		Client:   a.client.httpClient(""),
+		Project:  a.Project,
+		Header:   http.Header{},
+		Log:      a.Log,
+		debugLog: a.debugLog,
+		client:   a.client,
+	}
+}
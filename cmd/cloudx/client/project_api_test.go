@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/cloudxtest/streamtest"
+)
+
+func TestDecodeItems(t *testing.T) {
+	collect := func(body string) ([]string, error) {
+		var items []string
+		err := decodeItems(strings.NewReader(body), func(item json.RawMessage) error {
+			items = append(items, string(item))
+			return nil
+		})
+		return items, err
+	}
+
+	items, err := collect(`[{"id":1}, {"id":2},3]`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`{"id":1}`, `{"id":2}`, "3"}, items)
+
+	items, err = collect("null")
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	_, err = collect(`{"id":1}`)
+	assert.ErrorContains(t, err, "expected a JSON array")
+
+	_, err = collect(`[{"id":1}, {"id":`)
+	assert.Error(t, err)
+
+	stop := errors.New("stop")
+	var n int
+	err = decodeItems(strings.NewReader(`[1,2,3]`), func(json.RawMessage) error {
+		n++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, n, "decoding stops at the first error")
+}
+
+const (
+	// syntheticItems and syntheticPadding make the synthetic response about 100MB large.
+	syntheticItems   = 100_000
+	syntheticPadding = 950
+)
+
+// newSyntheticServer serves a JSON array of syntheticItems identities. The response is generated while it is sent,
+// so that the server does not hold it in memory.
+func newSyntheticServer(t testing.TB) *httptest.Server {
+	padding := strings.Repeat("x", syntheticPadding)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "[")
+		for i := 0; i < syntheticItems; i++ {
+			if i > 0 {
+				_, _ = io.WriteString(w, ",")
+			}
+			_, _ = fmt.Fprintf(w, `{"id":"%08d","traits":{"email":"user-%d@example.com"},"metadata_admin":%q}`, i, i, padding)
+		}
+		_, _ = io.WriteString(w, "]")
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+type syntheticIdentity struct {
+	ID     string `json:"id"`
+	Traits struct {
+		Email string `json:"email"`
+	} `json:"traits"`
+}
+
+// decodeSynthetic decodes the synthetic response either buffered, into a slice of all identities, or streamed with
+// Items, and returns the number of identities and the growth of the heap while decoding.
+func decodeSynthetic(t testing.TB, api *ProjectAPI, streamed bool) (int, uint64) {
+	peak := streamtest.NewHeapPeak()
+	var n int
+	if streamed {
+		_, err := api.Do(context.Background(), http.MethodGet, "/admin/identities", nil, nil, Items(func(item json.RawMessage) error {
+			var identity syntheticIdentity
+			if err := json.Unmarshal(item, &identity); err != nil {
+				return err
+			}
+			if n++; n%(syntheticItems/10) == 0 {
+				peak.Sample()
+			}
+			return nil
+		}))
+		require.NoError(t, err)
+		return n, peak.Growth()
+	}
+
+	var all []json.RawMessage
+	_, err := api.Do(context.Background(), http.MethodGet, "/admin/identities", nil, nil, &all)
+	require.NoError(t, err)
+	peak.Sample()
+	runtime.KeepAlive(all)
+	return len(all), peak.Growth()
+}
+
+func TestItemsStreams(t *testing.T) {
+	const items = 1000
+
+	first := streamtest.NewFirstOutput()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "[")
+		for i := 0; i < items; i++ {
+			if i == items/2 {
+				// Buffering the response would decode nothing before the end of the response was sent.
+				w.(http.Flusher).Flush()
+				assert.True(t, first.Wait(streamtest.Timeout), "the first item was not decoded before the end of the response was sent")
+			}
+			if i > 0 {
+				_, _ = io.WriteString(w, ",")
+			}
+			_, _ = fmt.Fprintf(w, `{"id":"%08d","traits":{"email":"user-%d@example.com"}}`, i, i)
+		}
+		_, _ = io.WriteString(w, "]")
+	}))
+	t.Cleanup(ts.Close)
+	api := &ProjectAPI{URL: ts.URL, Client: ts.Client()}
+
+	var ids []string
+	_, err := api.Do(context.Background(), http.MethodGet, "/admin/identities", nil, nil, Items(func(item json.RawMessage) error {
+		var identity syntheticIdentity
+		if err := json.Unmarshal(item, &identity); err != nil {
+			return err
+		}
+		ids = append(ids, identity.ID)
+		first.Done()
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Len(t, ids, items)
+	assert.Equal(t, "00000000", ids[0])
+	assert.Equal(t, fmt.Sprintf("%08d", items-1), ids[items-1])
+}
+
+func BenchmarkDecodeLargeResponse(b *testing.B) {
+	ts := newSyntheticServer(b)
+	api := &ProjectAPI{URL: ts.URL, Client: ts.Client()}
+
+	for _, streamed := range []bool{false, true} {
+		name := "buffered"
+		if streamed {
+			name = "streamed"
+		}
+		b.Run(name, func(b *testing.B) {
+			var peak uint64
+			for i := 0; i < b.N; i++ {
+				if _, growth := decodeSynthetic(b, api, streamed); growth > peak {
+					peak = growth
+				}
+			}
+			b.ReportMetric(float64(peak)/(1<<20), "peak-heap-MB")
+		})
+	}
+}
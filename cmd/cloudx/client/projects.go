@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/spf13/cobra"
+
+	cloud "github.com/ory/client-go"
+)
+
+// ProjectsAnnotation marks commands which list the projects of the user, see MarkProjectsNeeded.
+const ProjectsAnnotation = "ory.sh/projects"
+
+// MarkProjectsNeeded declares that the command lists the projects of the user, e.g. to resolve the project slug given
+// as its first argument or to let the user select a project. The projects are then fetched while the session is
+// checked instead of afterwards, unless the first argument is a project ID.
+func MarkProjectsNeeded(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[ProjectsAnnotation] = "true"
+	return cmd
+}
+
+// needsProjects returns true if the command will list the projects: it is marked with MarkProjectsNeeded and its first
+// argument is not a project ID, or the --project flag is set to a slug.
+func needsProjects(cmd *cobra.Command, project string) bool {
+	if project != "" && uuid.FromStringOrNil(project) == uuid.Nil {
+		return true
+	}
+	if cmd.Annotations[ProjectsAnnotation] == "" {
+		return false
+	}
+	args := cmd.Flags().Args()
+	return len(args) == 0 || uuid.FromStringOrNil(args[0]) == uuid.Nil
+}
+
+// projectsFetch lists the projects in the background. done is closed once projects and err are set.
+type projectsFetch struct {
+	token    string
+	done     chan struct{}
+	projects []cloud.ProjectMetadata
+	err      error
+}
+
+// fetchProjects starts listing the projects which the session token has access to. The list is cached for a short
+// time, so that e.g. resolving project slugs in a row does not list the projects every time.
+func (h *CommandHelper) fetchProjects(token string) *projectsFetch {
+	f := &projectsFetch{token: token, done: make(chan struct{})}
+	endpoint := makeCloudConsoleURL("api") + "/projects"
+	if h.cache.get(endpoint, token, &f.projects) {
+		h.Log.Debugf("Using the cached response of GET %s", endpoint)
+		close(f.done)
+		return f
+	}
+
+	go func() {
+		defer close(f.done)
+		var res *http.Response
+		f.projects, res, f.err = h.client.consoleAPI(token).V0alpha2Api.ListProjects(h.Ctx).Execute()
+		if f.err != nil {
+			f.err = handleError("unable to list projects", res, f.err)
+			return
+		}
+		if err := h.cache.put(endpoint, token, projectsCacheTTL, f.projects); err != nil {
+			h.Log.Debugf("Unable to cache the project list: %s", err)
+		}
+	}()
+	return f
+}
+
+// forgetProjects removes the cached project list after projects were changed.
+func (h *CommandHelper) forgetProjects() {
+	if _, err := h.cache.clear(); err != nil {
+		h.Log.Debugf("Unable to clear the cache: %s", err)
+	}
+}
+
+// wait returns the projects once they are listed.
+func (f *projectsFetch) wait(ctx context.Context) ([]cloud.ProjectMetadata, error) {
+	select {
+	case <-f.done:
+		return f.projects, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
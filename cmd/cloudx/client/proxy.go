@@ -0,0 +1,129 @@
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	ProxyFlag = "proxy"
+	// ProxyEnv sets the proxy if the --proxy flag is not set. It takes precedence over HTTP_PROXY and HTTPS_PROXY.
+	ProxyEnv = "ORY_HTTP_PROXY"
+)
+
+// RegisterProxyFlag registers the flag which sends all API requests through a proxy.
+func RegisterProxyFlag(f *pflag.FlagSet) {
+	f.String(ProxyFlag, "", fmt.Sprintf("Send API requests through this proxy, e.g. http://proxy.corp:3128. Defaults to the %s environment variable, or HTTPS_PROXY and HTTP_PROXY. NO_PROXY is respected.", ProxyEnv))
+}
+
+// Proxy returns the proxy configured by the --proxy flag or the ORY_HTTP_PROXY environment variable. It returns nil if
+// neither is set, in which case the proxy environment variables apply.
+func Proxy(cmd *cobra.Command) (*url.URL, error) {
+	proxy, _ := cmd.Flags().GetString(ProxyFlag)
+	source := "--" + ProxyFlag
+	if proxy == "" {
+		proxy, source = os.Getenv(ProxyEnv), ProxyEnv
+	}
+	if proxy == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return nil, NewValidationError(errors.Errorf("%s must be a URL like http://proxy.corp:3128, got %q", source, proxy), nil)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, NewValidationError(errors.Errorf("%s must use the http, https, or socks5 scheme, got %q", source, proxy), nil)
+	}
+	return u, nil
+}
+
+// newProxyFunc returns the proxy function of the transport. If proxy is nil, the proxy environment variables apply.
+// Otherwise, all requests except the ones to hosts listed in NO_PROXY go through proxy.
+func newProxyFunc(proxy *url.URL) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return http.ProxyFromEnvironment
+	}
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	proxyURL := (&httpproxy.Config{HTTPProxy: proxy.String(), HTTPSProxy: proxy.String(), NoProxy: noProxy}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyURL(req.URL)
+	}
+}
+
+// maxIdleConnsPerHost is the number of connections per host which are kept open for reuse.
+const maxIdleConnsPerHost = 32
+
+// newBaseTransport returns the transport which sends the API requests of a command through the given proxy using the
+// given TLS configuration and transport settings, which may be nil. It is based on http.DefaultTransport so that all
+// clients share its settings.
+func newBaseTransport(proxy *url.URL, tlsConf *tls.Config, settings *transportSettings) http.RoundTripper {
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return http.DefaultTransport
+	}
+	t = t.Clone()
+	t.Proxy = newProxyFunc(proxy)
+	// Bulk operations send many concurrent requests to the same host, which should reuse their connections.
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	if tlsConf != nil {
+		t.TLSClientConfig = tlsConf
+	}
+	if settings != nil {
+		settings.apply(t)
+	}
+	return &proxyTransport{Transport: t}
+}
+
+// proxyTransport names the proxy in the errors of requests which were sent through it, because a misconfigured proxy
+// is the most likely cause of failed requests then.
+type proxyTransport struct {
+	*http.Transport
+}
+
+func (t *proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		if proxy, _ := t.Proxy(req); proxy != nil {
+			return nil, &ProxyError{Host: req.URL.Host, Proxy: proxy.Redacted(), err: err}
+		}
+	}
+	return res, err
+}
+
+// ProxyError is returned if a request sent through a proxy failed without a response. It is a net.Error so that the
+// CLI exits with ExitNetwork.
+type ProxyError struct {
+	Host, Proxy string
+	err         error
+}
+
+func (e *ProxyError) Error() string {
+	return fmt.Sprintf("unable to reach %s through the proxy %s: %s", e.Host, e.Proxy, e.err)
+}
+
+func (e *ProxyError) Unwrap() error {
+	return e.err
+}
+
+func (e *ProxyError) Timeout() bool {
+	var netErr interface{ Timeout() bool }
+	return errors.As(e.err, &netErr) && netErr.Timeout()
+}
+
+func (e *ProxyError) Temporary() bool {
+	return true
+}
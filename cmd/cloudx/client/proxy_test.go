@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Host)
+		_, _ = w.Write([]byte("proxied"))
+	}))
+	t.Cleanup(proxy.Close)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("direct"))
+	}))
+	t.Cleanup(api.Close)
+
+	// The proxy functions never use proxies for localhost, so requests go to a fake host which resolves to the API.
+	dialer := &net.Dialer{}
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = &http.Transport{DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == "api.ory.test:80" {
+			addr = api.Listener.Addr().String()
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}}
+	t.Cleanup(func() { http.DefaultTransport = defaultTransport })
+
+	get := func(t *testing.T, proxyURL string) (string, error) {
+		u, err := url.Parse(proxyURL)
+		require.NoError(t, err)
+		res, err := (&http.Client{Transport: newBaseTransport(u, nil, nil)}).Get("http://api.ory.test/health/alive")
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		return string(body), err
+	}
+
+	t.Run("case=sends requests through the proxy", func(t *testing.T) {
+		proxied = nil
+		body, err := get(t, proxy.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "proxied", body)
+		assert.Equal(t, []string{"api.ory.test"}, proxied)
+	})
+
+	t.Run("case=respects NO_PROXY", func(t *testing.T) {
+		t.Setenv("NO_PROXY", "example.com,.ory.test")
+		proxied = nil
+		body, err := get(t, proxy.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "direct", body)
+		assert.Empty(t, proxied)
+	})
+
+	t.Run("case=names the proxy in errors", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		unreachable := "http://user:secret@" + l.Addr().String()
+		require.NoError(t, l.Close())
+
+		_, err = get(t, unreachable)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to reach api.ory.test through the proxy http://user:xxxxx@"+l.Addr().String())
+		assert.Equal(t, ExitNetwork, ExitCode(err))
+	})
+}
+
+func TestProxyFlag(t *testing.T) {
+	newCmd := func(t *testing.T, args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		RegisterProxyFlag(cmd.Flags())
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	t.Setenv(ProxyEnv, "")
+	p, err := Proxy(newCmd(t))
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	t.Setenv(ProxyEnv, "http://env.corp:3128")
+	p, err = Proxy(newCmd(t))
+	require.NoError(t, err)
+	assert.Equal(t, "http://env.corp:3128", p.String())
+
+	p, err = Proxy(newCmd(t, "--proxy", "socks5://flag.corp:1080"))
+	require.NoError(t, err)
+	assert.Equal(t, "socks5://flag.corp:1080", p.String(), "the flag overrides the environment")
+
+	for _, invalid := range []string{"proxy.corp:3128", "ftp://proxy.corp", "://"} {
+		_, err = Proxy(newCmd(t, "--proxy", invalid))
+		require.Error(t, err, invalid)
+		assert.Equal(t, ExitValidation, ExitCode(err), invalid)
+	}
+}
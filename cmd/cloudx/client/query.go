@@ -0,0 +1,156 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	QueryFlag = "query"
+
+	// QueryExampleAnnotation holds newline separated gjson paths which are documented as examples of the --query
+	// flag in the help of the annotated command.
+	QueryExampleAnnotation = "query-example"
+)
+
+func registerQueryFlag(f *pflag.FlagSet) {
+	f.String(QueryFlag, "", "Print only the result of the gjson path (see https://github.com/tidwall/gjson/blob/master/SYNTAX.md) applied to the JSON output. Lists are queried per item.")
+}
+
+// Query returns the value of the --query flag or an empty string if the command does not have the flag.
+func Query(cmd *cobra.Command) string {
+	f := cmd.Flags().Lookup(QueryFlag)
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+// EnableQueryFlag applies the --query flag to the output of cmd and all its sub commands and documents the paths
+// given in the QueryExampleAnnotation.
+func EnableQueryFlag(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableQueryFlag(c)
+	}
+
+	if paths := cmd.Annotations[QueryExampleAnnotation]; paths != "" {
+		examples := []string{strings.TrimSpace(cmd.Example)}
+		for _, path := range strings.Split(paths, "\n") {
+			examples = append(examples, fmt.Sprintf("$ %s --query '%s'", exampleUseLine(cmd), path))
+		}
+		cmd.Example = strings.TrimSpace(strings.Join(examples, "\n\n"))
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		query := Query(cmd)
+		if query == "" {
+			return run(cmd, args)
+		}
+		if OutputFormat(cmd) == FormatTemplate {
+			return NewValidationError(errors.Errorf("the --%s flag can not be used with template output", QueryFlag), nil)
+		}
+		if f := cmd.Flags().Lookup(cmdx.FlagFormat); f != nil {
+			if err := f.Value.Set(FormatJSON); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		r, w := io.Pipe()
+		done := make(chan error, 1)
+		var matched int
+		go func() {
+			var err error
+			matched, err = printQueryResults(out, r, query)
+			done <- err
+		}()
+
+		cmd.SetOut(w)
+		err := run(cmd, args)
+		_ = w.Close()
+		cmd.SetOut(out)
+
+		if queryErr := <-done; err != nil {
+			return err
+		} else if queryErr != nil {
+			return queryErr
+		} else if matched == 0 {
+			return errors.Errorf("the query %q did not match anything", query)
+		}
+		return nil
+	}
+}
+
+// exampleUseLine returns the use line of the command as invoked through the ory binary, independent of the root
+// command the command tree is mounted on.
+func exampleUseLine(cmd *cobra.Command) string {
+	parts := strings.Fields(cmd.Use)
+	for c := cmd.Parent(); c != nil && c.HasParent(); c = c.Parent() {
+		parts = append([]string{c.Name()}, parts...)
+	}
+	return strings.Join(append([]string{"ory"}, parts...), " ")
+}
+
+// printQueryResults applies the query to every JSON document read from r. Arrays are queried per item. Strings are
+// printed raw, all other results as compact JSON, one result per line.
+func printQueryResults(out io.Writer, r io.Reader, query string) (matched int, err error) {
+	defer func() {
+		// Drain the reader so that the command does not block on writing its output.
+		_, _ = io.Copy(io.Discard, r)
+	}()
+
+	dec := json.NewDecoder(r)
+	for {
+		var doc json.RawMessage
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			return matched, nil
+		} else if err != nil {
+			return matched, errors.Wrap(err, "unable to apply the query because the output is not JSON")
+		}
+
+		var results []gjson.Result
+		if parsed := gjson.ParseBytes(doc); parsed.IsArray() {
+			parsed.ForEach(func(_, item gjson.Result) bool {
+				results = append(results, item.Get(query))
+				return true
+			})
+		} else {
+			results = append(results, parsed.Get(query))
+		}
+
+		for _, result := range results {
+			if !result.Exists() {
+				continue
+			}
+			matched++
+			_, _ = fmt.Fprintln(out, formatQueryResult(result))
+		}
+	}
+}
+
+func formatQueryResult(result gjson.Result) string {
+	switch result.Type {
+	case gjson.String:
+		return result.Str
+	case gjson.JSON:
+		var b bytes.Buffer
+		if err := json.Compact(&b, []byte(result.Raw)); err == nil {
+			return b.String()
+		}
+	}
+	return result.Raw
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintQueryResults(t *testing.T) {
+	for _, tc := range []struct {
+		name, query, input, expected string
+		matched                      int
+	}{
+		{name: "strings are printed raw", query: "slug", input: `{"slug":"good-wright"}`, expected: "good-wright\n", matched: 1},
+		{name: "objects are printed as compact JSON", query: "a", input: "{\n  \"a\": {\n    \"b\": 1\n  }\n}", expected: `{"b":1}` + "\n", matched: 1},
+		{name: "lists are queried per item", query: "id", input: `[{"id":"a"},{"name":"b"},{"id":"c"}]`, expected: "a\nc\n", matched: 2},
+		{name: "every document of a stream is queried", query: "id", input: "{\"id\":1}\n{\"id\":2}\n", expected: "1\n2\n", matched: 2},
+		{name: "no match", query: "missing", input: `{"id":1}`, expected: "", matched: 0},
+		{name: "empty list", query: "id", input: `[]`, expected: "", matched: 0},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			matched, err := printQueryResults(&out, strings.NewReader(tc.input), tc.query)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matched, matched)
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+
+	_, err := printQueryResults(new(bytes.Buffer), strings.NewReader("Allowed\n"), "id")
+	assert.ErrorContains(t, err, "output is not JSON")
+}
+
+func TestEnableQueryFlag(t *testing.T) {
+	newRoot := func() *cobra.Command {
+		root := &cobra.Command{Use: "ory"}
+		verb := &cobra.Command{Use: "get"}
+		RegisterFormatFlag(verb.PersistentFlags())
+		leaf := &cobra.Command{
+			Use:         "thing <id>",
+			Annotations: map[string]string{QueryExampleAnnotation: "name"},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if OutputFormat(cmd) != FormatJSON {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "NAME\tfoo")
+					return nil
+				}
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), `{"name":"foo","labels":["a","b"]}`)
+				return nil
+			},
+		}
+		verb.AddCommand(leaf)
+		root.AddCommand(verb)
+		EnableQueryFlag(root)
+		return root
+	}
+
+	exec := func(args ...string) (string, error) {
+		root := newRoot()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(new(bytes.Buffer))
+		root.SetArgs(args)
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := exec("get", "thing", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "NAME\tfoo\n", out)
+
+	out, err = exec("get", "thing", "1", "--query", "name")
+	require.NoError(t, err)
+	assert.Equal(t, "foo\n", out)
+
+	out, err = exec("get", "thing", "1", "--query", "labels")
+	require.NoError(t, err)
+	assert.Equal(t, `["a","b"]`+"\n", out)
+
+	_, err = exec("get", "thing", "1", "--query", "missing")
+	assert.EqualError(t, err, `the query "missing" did not match anything`)
+
+	leaf, _, err := newRoot().Find([]string{"get", "thing"})
+	require.NoError(t, err)
+	assert.Equal(t, "$ ory get thing <id> --query 'name'", leaf.Example)
+}
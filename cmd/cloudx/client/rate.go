@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const RateFlag = "rate"
+
+// RegisterRateFlag registers the flag which caps the number of API requests per second of bulk operations.
+func RegisterRateFlag(f *pflag.FlagSet) {
+	f.String(RateFlag, "", "Send at most this many API requests, e.g. 50/s or 600/min. Retried requests count as well. Defaults to no limit.")
+}
+
+// Rate is a number of requests per time unit. The zero value means no limit.
+type Rate struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseRate parses rate expressions like 50/s, 50/sec, 600/min, or 3000/minute. The time unit defaults to seconds.
+// An empty expression means no limit.
+func ParseRate(expr string) (Rate, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	if expr == "" {
+		return Rate{}, nil
+	}
+
+	n, unit := expr, "s"
+	if i := strings.Index(expr, "/"); i >= 0 {
+		n, unit = strings.TrimSpace(expr[:i]), strings.TrimSpace(expr[i+1:])
+	}
+	requests, err := strconv.Atoi(n)
+	if err != nil || requests < 1 {
+		return Rate{}, errors.Errorf("the rate must be a positive number of requests like 50/s or 600/min, got %q", expr)
+	}
+
+	switch unit {
+	case "s", "sec", "second":
+		return Rate{Requests: requests, Per: time.Second}, nil
+	case "m", "min", "minute":
+		return Rate{Requests: requests, Per: time.Minute}, nil
+	case "h", "hour":
+		return Rate{Requests: requests, Per: time.Hour}, nil
+	}
+	return Rate{}, errors.Errorf("the rate must be given per s, min, or h like 50/s or 600/min, got %q", expr)
+}
+
+// RequestRate returns the rate configured by the --rate flag of cmd. It returns the zero Rate if the flag is not set
+// or not registered.
+func RequestRate(cmd *cobra.Command) (Rate, error) {
+	expr, err := cmd.Flags().GetString(RateFlag)
+	if err != nil {
+		return Rate{}, nil
+	}
+	r, err := ParseRate(expr)
+	if err != nil {
+		return Rate{}, NewValidationError(errors.Wrapf(err, "invalid --%s", RateFlag), nil)
+	}
+	return r, nil
+}
+
+// IsZero returns true if the rate does not limit requests.
+func (r Rate) IsZero() bool {
+	return r.Requests == 0
+}
+
+func (r Rate) String() string {
+	switch r.Per {
+	case time.Second:
+		return fmt.Sprintf("%d/s", r.Requests)
+	case time.Minute:
+		return fmt.Sprintf("%d/min", r.Requests)
+	case time.Hour:
+		return fmt.Sprintf("%d/h", r.Requests)
+	}
+	return fmt.Sprintf("%d/%s", r.Requests, r.Per)
+}
+
+// interval returns the time between two requests if they are spread evenly.
+func (r Rate) interval() time.Duration {
+	return r.Per / time.Duration(r.Requests)
+}
+
+// Limiter is a token bucket which caps the rate of requests. It is shared by all workers of a command. The bucket
+// holds a single token, so requests are spread evenly instead of being sent in bursts after idle periods, e.g. while
+// a Throttle pauses the pipeline. A nil Limiter does not limit requests.
+type Limiter struct {
+	rate Rate
+	now  func() time.Time
+
+	mu sync.Mutex
+	// next is the time at which the next token is available.
+	next time.Time
+}
+
+func newLimiter(rate Rate, now func() time.Time) *Limiter {
+	if rate.IsZero() {
+		return nil
+	}
+	return &Limiter{rate: rate, now: now}
+}
+
+// Rate returns the rate of the limiter, or the zero Rate if l is nil.
+func (l *Limiter) Rate() Rate {
+	if l == nil {
+		return Rate{}
+	}
+	return l.rate
+}
+
+// Wait blocks until a token is available and takes it, or the context is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.rate.interval())
+	l.mu.Unlock()
+
+	wait := at.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel(at)
+		return errors.WithStack(ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancel returns the token reserved for the given time if no later token was reserved since.
+func (l *Limiter) cancel(at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.next.Equal(at.Add(l.rate.interval())) {
+		l.next = at
+	}
+}
+
+// rateTransport takes a token of the limiter for every request it sends. It sits below the retryTransport so that
+// retried requests consume tokens, too.
+type rateTransport struct {
+	http.RoundTripper
+	limiter *Limiter
+}
+
+func (t *rateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
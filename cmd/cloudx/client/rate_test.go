@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	for expr, expected := range map[string]Rate{
+		"":             {},
+		"50/s":         {Requests: 50, Per: time.Second},
+		"50":           {Requests: 50, Per: time.Second},
+		"50/sec":       {Requests: 50, Per: time.Second},
+		" 50 / second": {Requests: 50, Per: time.Second},
+		"600/min":      {Requests: 600, Per: time.Minute},
+		"600/m":        {Requests: 600, Per: time.Minute},
+		"600/Minute":   {Requests: 600, Per: time.Minute},
+		"1000/h":       {Requests: 1000, Per: time.Hour},
+	} {
+		r, err := ParseRate(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, expected, r, expr)
+	}
+
+	for _, expr := range []string{"0/s", "-5/s", "fast", "50/day", "50/", "/s", "1.5/s", "50 per second"} {
+		_, err := ParseRate(expr)
+		assert.Error(t, err, expr)
+	}
+
+	assert.Equal(t, "50/s", Rate{Requests: 50, Per: time.Second}.String())
+	assert.Equal(t, "600/min", Rate{Requests: 600, Per: time.Minute}.String())
+	assert.Equal(t, 100*time.Millisecond, Rate{Requests: 600, Per: time.Minute}.interval())
+}
+
+func TestRequestRate(t *testing.T) {
+	cmd := &cobra.Command{}
+	r, err := RequestRate(cmd)
+	require.NoError(t, err)
+	assert.True(t, r.IsZero(), "commands without the flag are not limited")
+
+	RegisterRateFlag(cmd.Flags())
+	require.NoError(t, cmd.ParseFlags([]string{"--rate", "fast"}))
+	_, err = RequestRate(cmd)
+	require.Error(t, err)
+	assert.Equal(t, ExitValidation, ExitCode(err))
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run("case=nil does not limit", func(t *testing.T) {
+		l := newLimiter(Rate{}, time.Now)
+		assert.Nil(t, l)
+		assert.NoError(t, l.Wait(context.Background()))
+		assert.True(t, l.Rate().IsZero())
+	})
+
+	t.Run("case=spreads the requests of all workers evenly", func(t *testing.T) {
+		l := newLimiter(Rate{Requests: 50, Per: time.Second}, time.Now)
+		start := time.Now()
+		var wg sync.WaitGroup
+		for w := 0; w < 5; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 2; i++ {
+					assert.NoError(t, l.Wait(context.Background()))
+				}
+			}()
+		}
+		wg.Wait()
+		// The first token is available immediately, the other nine follow every 20ms.
+		assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+	})
+
+	t.Run("case=returns the token if the context is done", func(t *testing.T) {
+		now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+		l := newLimiter(Rate{Requests: 1, Per: time.Hour}, func() time.Time { return now })
+		require.NoError(t, l.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+		assert.Equal(t, now.Add(time.Hour), l.next)
+	})
+}
+
+func TestRateTransport(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(ts.Close)
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	l := newLimiter(Rate{Requests: 1000, Per: time.Second}, func() time.Time { return now })
+	rt := newRetryTransport(&rateTransport{RoundTripper: http.DefaultTransport, limiter: l}, 3)
+	rt.minWait, rt.maxWait = time.Millisecond, time.Millisecond
+
+	res, err := (&http.Client{Transport: rt}).Get(ts.URL)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+	assert.Equal(t, now.Add(3*time.Millisecond), l.next, "every retry takes a token")
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit contains the rate limit information returned in the response headers of the API.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+func (r *RateLimit) String() string {
+	return fmt.Sprintf("%d of %d requests remaining, resets at %s", r.Remaining, r.Limit, r.Reset.Format(time.RFC3339))
+}
+
+// Delay returns how long to wait before sending the next request without exceeding the rate limit.
+func (r *RateLimit) Delay(now time.Time) time.Duration {
+	if r == nil || r.Remaining > 0 || !r.Reset.After(now) {
+		return 0
+	}
+	return r.Reset.Sub(now)
+}
+
+// Wait blocks until the next request can be sent without exceeding the rate limit or the context is done.
+func (r *RateLimit) Wait(ctx context.Context) error {
+	d := r.Delay(time.Now())
+	if d == 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// ParseRateLimit extracts the rate limit from the X-RateLimit-* or RateLimit-* response headers. The reset header is
+// either a Unix timestamp or the number of seconds until the limit resets. It returns false if the headers are not set.
+func ParseRateLimit(header http.Header, now time.Time) (*RateLimit, bool) {
+	get := func(name string) (int64, bool) {
+		for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+			if v := header.Get(prefix + name); v != "" {
+				// The IETF draft allows a list of quota policies after the value, e.g. "100, 100;w=60".
+				v = strings.TrimSpace(strings.SplitN(v, ",", 2)[0])
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+					return n, true
+				}
+			}
+		}
+		return 0, false
+	}
+
+	limit, hasLimit := get("Limit")
+	remaining, hasRemaining := get("Remaining")
+	if !hasLimit && !hasRemaining {
+		return nil, false
+	}
+
+	r := &RateLimit{Limit: int(limit), Remaining: int(remaining)}
+	if reset, ok := get("Reset"); ok {
+		// Values larger than a year are Unix timestamps, everything else is a delta in seconds.
+		if reset > 365*24*60*60 {
+			r.Reset = time.Unix(reset, 0).UTC()
+		} else {
+			r.Reset = now.Add(time.Duration(reset) * time.Second).UTC()
+		}
+	}
+	return r, true
+}
+
+func logRateLimit(l *Logger, res *http.Response) {
+	if r, ok := ParseRateLimit(res.Header, time.Now()); ok {
+		l.Debugf("Rate limit of %s %s: %s", res.Request.Method, res.Request.URL.Path, r)
+	}
+}
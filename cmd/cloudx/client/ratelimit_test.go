@@ -0,0 +1,38 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	_, ok := ParseRateLimit(http.Header{}, now)
+	assert.False(t, ok)
+
+	r, ok := ParseRateLimit(http.Header{
+		"X-Ratelimit-Limit":     {"100"},
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {"30"},
+	}, now)
+	require.True(t, ok)
+	assert.Equal(t, &RateLimit{Limit: 100, Remaining: 0, Reset: now.Add(30 * time.Second)}, r)
+	assert.Equal(t, 30*time.Second, r.Delay(now))
+
+	r, ok = ParseRateLimit(http.Header{
+		"Ratelimit-Limit":     {"100, 100;w=60"},
+		"Ratelimit-Remaining": {"42"},
+		"Ratelimit-Reset":     {"1654084860"},
+	}, now)
+	require.True(t, ok)
+	assert.Equal(t, &RateLimit{Limit: 100, Remaining: 42, Reset: time.Unix(1654084860, 0).UTC()}, r)
+	assert.Zero(t, r.Delay(now))
+
+	var none *RateLimit
+	assert.Zero(t, none.Delay(now))
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// RequestID identifies a request in the logs of the Ory Cloud APIs. Include it when reporting issues.
+type RequestID struct {
+	ID      string
+	TraceID string
+}
+
+// requestIDFromHeader returns the IDs of the request which produced the response with the header h.
+func requestIDFromHeader(h http.Header) RequestID {
+	id := RequestID{ID: h.Get("X-Request-Id"), TraceID: h.Get("X-Trace-Id")}
+	if id.TraceID == "" {
+		// https://www.w3.org/TR/trace-context/#traceparent-header-field-values
+		if parts := strings.Split(h.Get("Traceparent"), "-"); len(parts) == 4 {
+			id.TraceID = parts[1]
+		}
+	}
+	return id
+}
+
+func (id RequestID) IsZero() bool {
+	return id.ID == "" && id.TraceID == ""
+}
+
+func (id RequestID) String() string {
+	var parts []string
+	if id.ID != "" {
+		parts = append(parts, "request ID: "+id.ID)
+	}
+	if id.TraceID != "" {
+		parts = append(parts, "trace ID: "+id.TraceID)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RequestIDError adds the IDs of the failed request to the message of err.
+type RequestIDError struct {
+	err error
+	RequestID
+}
+
+func (e *RequestIDError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.err, e.RequestID)
+}
+
+func (e *RequestIDError) Unwrap() error {
+	return e.err
+}
+
+// withRequestID attaches the IDs of the failed request which caused err, if any, to err.
+func withRequestID(ctx context.Context, err error) error {
+	var idErr *RequestIDError
+	if err == nil || errors.As(err, &idErr) {
+		return err
+	}
+
+	var id RequestID
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		id = apiErr.RequestID
+	} else if l, ok := ctx.Value(requestIDLogKey{}).(*requestIDLog); ok {
+		id = l.lastFailed()
+	}
+	if id.IsZero() {
+		return err
+	}
+	return &RequestIDError{err: err, RequestID: id}
+}
+
+type requestIDLogKey struct{}
+
+// requestIDLog remembers the IDs of the last failed request, because the errors of the generated Ory SDKs do not
+// expose the response headers.
+type requestIDLog struct {
+	sync.Mutex
+	failed RequestID
+}
+
+func (l *requestIDLog) lastFailed() RequestID {
+	l.Lock()
+	defer l.Unlock()
+	return l.failed
+}
+
+// contextWithRequestIDLog makes all Ory Cloud API clients remember the IDs of failed requests, so that commands can
+// attach them to their errors.
+func contextWithRequestIDLog(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestIDLogKey{}, new(requestIDLog))
+}
+
+// recordRequestID records the IDs of the request, if it failed, in the request ID log of ctx.
+func recordRequestID(ctx context.Context, res *http.Response) {
+	if res.StatusCode < 400 {
+		return
+	}
+	if l, ok := ctx.Value(requestIDLogKey{}).(*requestIDLog); ok {
+		if id := requestIDFromHeader(res.Header); !id.IsZero() {
+			l.Lock()
+			l.failed = id
+			l.Unlock()
+		}
+	}
+}
+
+func logRequestID(l *Logger, res *http.Response) {
+	if id := requestIDFromHeader(res.Header); !id.IsZero() {
+		l.Debugf("Response of %s %s: %d (%s)", res.Request.Method, res.Request.URL.Path, res.StatusCode, id)
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+)
+
+func TestRequestIDFromHeader(t *testing.T) {
+	for _, tc := range []struct {
+		header http.Header
+		id     RequestID
+		str    string
+	}{
+		{header: http.Header{}},
+		{header: http.Header{"X-Request-Id": {"abc-123"}}, id: RequestID{ID: "abc-123"}, str: "request ID: abc-123"},
+		{
+			header: http.Header{"X-Request-Id": {"abc-123"}, "Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+			id:     RequestID{ID: "abc-123", TraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+			str:    "request ID: abc-123, trace ID: 4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{header: http.Header{"X-Trace-Id": {"def"}, "Traceparent": {"invalid"}}, id: RequestID{TraceID: "def"}, str: "trace ID: def"},
+	} {
+		t.Run("case="+tc.str, func(t *testing.T) {
+			id := requestIDFromHeader(tc.header)
+			assert.Equal(t, tc.id, id)
+			assert.Equal(t, tc.str, id.String())
+		})
+	}
+}
+
+func TestRequestIDs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "id-of-"+r.URL.Path[1:])
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(ts.Close)
+
+	exec := func(path string, args ...string) (string, error) {
+		cmd := &cobra.Command{
+			Use: "get",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				h, err := NewCommandHelper(cmd)
+				if err != nil {
+					return err
+				}
+				c := newClient(http.DefaultTransport, clientConfig{}).httpClient("")
+				req, err := http.NewRequestWithContext(h.Ctx, http.MethodGet, ts.URL+path, nil)
+				require.NoError(t, err)
+				res, err := c.Do(req)
+				require.NoError(t, err)
+				_ = res.Body.Close()
+				if res.StatusCode != http.StatusNoContent {
+					return errors.Errorf("getting %s failed", path)
+				}
+				return nil
+			},
+		}
+		RegisterConfigFlag(cmd.Flags())
+		RegisterYesFlag(cmd.Flags())
+		RegisterFormatFlag(cmd.Flags())
+		RegisterVerboseFlag(cmd.Flags())
+		cmdx.RegisterNoiseFlags(cmd.Flags())
+		EnableStructuredErrors(cmd)
+
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetArgs(append(args, "--"+ConfigFlag, filepath.Join(t.TempDir(), "config.json")))
+		err := cmd.ExecuteContext(contextWithRequestIDLog(context.Background()))
+		return stderr.String(), err
+	}
+
+	t.Run("case=adds the request ID to human readable errors", func(t *testing.T) {
+		_, err := exec("/fail")
+		require.Error(t, err)
+		assert.Equal(t, "getting /fail failed (request ID: id-of-fail)", err.Error())
+	})
+
+	t.Run("case=adds the request ID to the error envelope", func(t *testing.T) {
+		stderr, err := exec("/fail", "--format", "json")
+		require.ErrorIs(t, err, cmdx.ErrNoPrintButFail)
+		assert.JSONEq(t, `{"error":{"code":"unknown","message":"getting /fail failed (request ID: id-of-fail)","request_id":"id-of-fail"}}`, stderr)
+	})
+
+	t.Run("case=logs the request ID of successful requests in verbose mode", func(t *testing.T) {
+		stderr, err := exec("/ok", "--verbose")
+		require.NoError(t, err)
+		// The timings of the request follow once its body was closed.
+		assert.Regexp(t, `^Response of GET /ok: 204 \(request ID: id-of-ok\)\nGET /ok: 204 No Content \(.+\)\n$`, stderr)
+
+		stderr, err = exec("/ok")
+		require.NoError(t, err)
+		assert.Empty(t, stderr)
+	})
+}
+
+func TestWithRequestID(t *testing.T) {
+	err := withRequestID(context.Background(), errors.WithStack(&APIError{Method: "GET", URL: "https://example.com", StatusCode: 404, RequestID: RequestID{ID: "abc-123"}}))
+	assert.Equal(t, "GET https://example.com failed with status code 404:  (request ID: abc-123)", err.Error())
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Same(t, err, withRequestID(context.Background(), err), "does not add the request ID twice")
+
+	plain := errors.New("something went wrong")
+	assert.Same(t, plain, withRequestID(context.Background(), plain))
+	assert.NoError(t, withRequestID(context.Background(), nil))
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	MaxRetriesFlag = "max-retries"
+	NoRetryFlag    = "no-retry"
+
+	// IdempotencyKeyHeader marks requests which are safe to retry although their method is not idempotent. Commands
+	// opt in to retrying POST and PATCH requests by setting it.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	defaultMaxRetries = 3
+)
+
+// RegisterRetryFlags registers the flags which control retrying failed API requests.
+func RegisterRetryFlags(f *pflag.FlagSet) {
+	f.Int(MaxRetriesFlag, defaultMaxRetries, "Retry idempotent API requests which failed with a 502, 503, or 504 status code or a connection reset up to this many times.")
+	f.Bool(NoRetryFlag, false, "Never retry failed API requests. Same as --max-retries 0.")
+}
+
+// MaxRetries returns the number of times failed API requests are retried as configured by the flags of cmd.
+func MaxRetries(cmd *cobra.Command) (int, error) {
+	if noRetry, _ := cmd.Flags().GetBool(NoRetryFlag); noRetry {
+		return 0, nil
+	}
+	n, err := cmd.Flags().GetInt(MaxRetriesFlag)
+	if err != nil {
+		return defaultMaxRetries, nil
+	}
+	if n < 0 {
+		return 0, NewValidationError(errors.Errorf("--%s must not be negative", MaxRetriesFlag), nil)
+	}
+	return n, nil
+}
+
+type maxRetriesKey struct{}
+
+// contextWithMaxRetries makes all Ory Cloud API clients retry failed requests up to n times.
+func contextWithMaxRetries(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, maxRetriesKey{}, n)
+}
+
+// retryTransport retries idempotent requests which failed because of transient errors with exponential backoff and
+// jitter. The number of retries is taken from the request context, or from maxRetries if the context does not set it.
+type retryTransport struct {
+	http.RoundTripper
+	maxRetries int
+	// minWait is the wait before the first retry, it doubles for every further retry up to maxWait.
+	minWait, maxWait time.Duration
+}
+
+func newRetryTransport(rt http.RoundTripper, maxRetries int) *retryTransport {
+	return &retryTransport{RoundTripper: rt, maxRetries: maxRetries, minWait: 500 * time.Millisecond, maxWait: 10 * time.Second}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	maxRetries, ok := req.Context().Value(maxRetriesKey{}).(int)
+	if !ok {
+		maxRetries = t.maxRetries
+	}
+	if !isRetryable(req) {
+		maxRetries = 0
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := t.RoundTripper.RoundTrip(req)
+		if attempt > maxRetries || !isTransientFailure(res, err) {
+			return res, err
+		}
+
+		reason := "connection reset"
+		if err == nil {
+			reason = res.Status
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+		wait := t.backoff(attempt)
+		loggerFromContext(req.Context()).Debugf("Retrying %s %s in %s (retry %d of %d) because of %s", req.Method, redactURL(req.URL), wait, attempt, maxRetries, reason)
+
+		select {
+		case <-req.Context().Done():
+			return nil, errors.WithStack(req.Context().Err())
+		case <-time.After(wait):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// backoff returns the wait before the given retry. The jitter spreads the retries of concurrent requests.
+func (t *retryTransport) backoff(retry int) time.Duration {
+	wait := t.minWait << (retry - 1)
+	if wait > t.maxWait || wait <= 0 {
+		wait = t.maxWait
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// isRetryable returns true for requests which can be sent again without changing resources twice.
+func isRetryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+func isTransientFailure(res *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
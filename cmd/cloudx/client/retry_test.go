@@ -0,0 +1,168 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer returns a server which fails the first failures requests with a 503 and records the request bodies.
+func newFlakyServer(t *testing.T, failures int32) (*httptest.Server, *int32, *[]string) {
+	var requests int32
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &requests, &bodies
+}
+
+func newTestRetryClient(maxRetries int) *http.Client {
+	return &http.Client{Transport: &retryTransport{RoundTripper: http.DefaultTransport, maxRetries: maxRetries, minWait: time.Millisecond, maxWait: 5 * time.Millisecond}}
+}
+
+func TestRetryTransport(t *testing.T) {
+	t.Run("case=retries until the request succeeds", func(t *testing.T) {
+		ts, requests, _ := newFlakyServer(t, 2)
+		var log bytes.Buffer
+		ctx := contextWithLogger(context.Background(), &Logger{out: &log, level: LogLevelDebug})
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/projects", nil)
+		require.NoError(t, err)
+		res, err := newTestRetryClient(3).Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 3, atomic.LoadInt32(requests))
+		assert.Contains(t, log.String(), "(retry 1 of 3) because of 503 Service Unavailable")
+		assert.Contains(t, log.String(), "(retry 2 of 3) because of 503 Service Unavailable")
+	})
+
+	t.Run("case=gives up after the maximum retries", func(t *testing.T) {
+		ts, requests, _ := newFlakyServer(t, 10)
+
+		res, err := newTestRetryClient(2).Get(ts.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.EqualValues(t, 3, atomic.LoadInt32(requests))
+	})
+
+	t.Run("case=the context overrides the maximum retries", func(t *testing.T) {
+		ts, requests, _ := newFlakyServer(t, 10)
+
+		req, err := http.NewRequestWithContext(contextWithMaxRetries(context.Background(), 0), http.MethodGet, ts.URL, nil)
+		require.NoError(t, err)
+		res, err := newTestRetryClient(3).Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.EqualValues(t, 1, atomic.LoadInt32(requests))
+	})
+
+	t.Run("case=does not retry POST requests", func(t *testing.T) {
+		ts, requests, _ := newFlakyServer(t, 1)
+
+		res, err := newTestRetryClient(3).Post(ts.URL, "application/json", strings.NewReader(`{"name":"foo"}`))
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.EqualValues(t, 1, atomic.LoadInt32(requests))
+	})
+
+	t.Run("case=retries POST requests with an idempotency key", func(t *testing.T) {
+		ts, requests, bodies := newFlakyServer(t, 1)
+
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"name":"foo"}`))
+		require.NoError(t, err)
+		req.Header.Set(IdempotencyKeyHeader, "create-project-foo")
+		res, err := newTestRetryClient(3).Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 2, atomic.LoadInt32(requests))
+		assert.Equal(t, []string{`{"name":"foo"}`, `{"name":"foo"}`}, *bodies, "the body is sent again")
+	})
+
+	t.Run("case=does not retry other errors", func(t *testing.T) {
+		var requests int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(ts.Close)
+
+		res, err := newTestRetryClient(3).Get(ts.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+	})
+
+	t.Run("case=stops waiting when the context is canceled", func(t *testing.T) {
+		ts, _, _ := newFlakyServer(t, 10)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+		require.NoError(t, err)
+		_, err = (&retryTransport{RoundTripper: http.DefaultTransport, maxRetries: 3, minWait: time.Hour, maxWait: time.Hour}).RoundTrip(req)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	rt := newRetryTransport(http.DefaultTransport, 3)
+	for retry, upper := range map[int]time.Duration{1: 500 * time.Millisecond, 2: time.Second, 3: 2 * time.Second, 10: 10 * time.Second, 100: 10 * time.Second} {
+		wait := rt.backoff(retry)
+		assert.GreaterOrEqual(t, wait, upper/2, "retry %d", retry)
+		assert.LessOrEqual(t, wait, upper, "retry %d", retry)
+	}
+}
+
+func TestMaxRetries(t *testing.T) {
+	for _, tc := range []struct {
+		args     []string
+		expected int
+	}{
+		{expected: defaultMaxRetries},
+		{args: []string{"--max-retries", "5"}, expected: 5},
+		{args: []string{"--max-retries", "5", "--no-retry"}, expected: 0},
+	} {
+		cmd := &cobra.Command{}
+		RegisterRetryFlags(cmd.Flags())
+		require.NoError(t, cmd.ParseFlags(tc.args))
+		n, err := MaxRetries(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, n, "%v", tc.args)
+	}
+
+	cmd := &cobra.Command{}
+	RegisterRetryFlags(cmd.Flags())
+	require.NoError(t, cmd.ParseFlags([]string{"--max-retries", "-1"}))
+	_, err := MaxRetries(cmd)
+	require.Error(t, err)
+	assert.Equal(t, ExitValidation, ExitCode(err))
+}
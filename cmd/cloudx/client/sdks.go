@@ -1,38 +1,31 @@
 package client
 
 import (
-	"net/http"
 	"net/url"
 	"os"
-	"time"
 
 	cloud "github.com/ory/client-go"
 	"github.com/ory/x/stringsx"
 )
 
-func makeCloudConsoleURL(prefix string) string {
-	u, err := url.ParseRequestURI(stringsx.Coalesce(os.Getenv("ORY_CLOUD_CONSOLE_URL"), "https://console.ory.sh"))
+const consoleURLEnv = "ORY_CLOUD_CONSOLE_URL"
+
+// consoleURL returns the URL of the Ory Console, which can be changed using the ORY_CLOUD_CONSOLE_URL environment
+// variable.
+func consoleURL() *url.URL {
+	u, err := url.ParseRequestURI(stringsx.Coalesce(os.Getenv(consoleURLEnv), "https://console.ory.sh"))
 	if err != nil {
 		u = &url.URL{Scheme: "https", Host: "console.ory.sh"}
 	}
+	return u
+}
+
+func makeCloudConsoleURL(prefix string) string {
+	u := consoleURL()
 	u.Host = prefix + "." + u.Host
 	return u.Scheme + "://" + u.Host
 }
 
 func NewKratosClient() (*cloud.APIClient, error) {
-	conf := cloud.NewConfiguration()
-	conf.Servers = cloud.ServerConfigurations{{URL: makeCloudConsoleURL("project")}}
-	conf.HTTPClient = &http.Client{Timeout: time.Second * 10}
-
-	return cloud.NewAPIClient(conf), nil
-}
-
-func newCloudClient(token string) (*cloud.APIClient, error) {
-	u := makeCloudConsoleURL("api")
-
-	conf := cloud.NewConfiguration()
-	conf.Servers = cloud.ServerConfigurations{{URL: u}}
-	conf.HTTPClient = newBearerTokenClient(token)
-
-	return cloud.NewAPIClient(conf), nil
+	return newClient(newBaseTransport(nil, nil, nil), clientConfig{maxRetries: defaultMaxRetries, timeout: defaultRequestTimeout, compress: true}).authAPI(), nil
 }
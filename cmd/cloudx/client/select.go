@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/cli/cmd/cloudx/client/selector"
+	cloud "github.com/ory/client-go"
+)
+
+// Select asks the user to pick one of the items and returns the ID of the selected item. It fails instead of
+// prompting if the user is unable to answer, see RequireInteractive.
+func (h *CommandHelper) Select(prompt, alternative string, items []selector.Item) (string, error) {
+	if err := h.RequireInteractive(prompt, alternative); err != nil {
+		return "", err
+	}
+
+	s := &selector.Selector{In: h.Stdin, Out: h.VerboseErrWriter, Terminal: h.terminal}
+	item, err := s.Select(prompt, items)
+	if errors.Is(err, selector.ErrInterrupted) {
+		return "", errors.WithStack(ErrAborted)
+	} else if err != nil {
+		return "", err
+	}
+	return item.ID, nil
+}
+
+// SelectProject asks the user to pick one of their projects and returns its ID.
+func (h *CommandHelper) SelectProject(alternative string) (string, error) {
+	const prompt = "Select a project:"
+	if err := h.RequireInteractive(prompt, alternative); err != nil {
+		return "", err
+	}
+
+	projects, err := h.ListProjects()
+	if err != nil {
+		return "", err
+	}
+	if len(projects) == 0 {
+		return "", errors.New("you do not have any projects yet, please create one using `ory create project`")
+	}
+
+	items := make([]selector.Item, len(projects))
+	for k, p := range projects {
+		items[k] = selector.Item{ID: p.Id, Label: p.Name}
+	}
+	return h.Select(prompt, alternative, items)
+}
+
+// selectProjectAfterSignIn selects the project which commands use after signing in: the only project of the account,
+// or the one the user picks if there are several. The selection is skipped if the user is unable to answer prompts,
+// so that SelectedProject stays unset. The user is already signed in, so failing to select a project only warns.
+func (h *CommandHelper) selectProjectAfterSignIn(ac *AuthContext) {
+	if !h.isInteractive() {
+		return
+	}
+
+	var projects []cloud.ProjectMetadata
+	err := h.WithSpinner("Listing projects", func(ctx context.Context) (err error) {
+		projects, err = h.fetchProjects(ac.SessionToken).wait(ctx)
+		return err
+	})
+	if err != nil {
+		h.Log.Warnf("Unable to list your projects, select one using `ory use project`: %s", err)
+		return
+	}
+
+	var id string
+	switch len(projects) {
+	case 0:
+		h.Log.Infof("You do not have any projects yet, create one using `ory create project`.")
+		return
+	case 1:
+		id = projects[0].Id
+		h.Log.Infof("Selected your project %q, commands use it unless --%s is set.", projects[0].Name, projectFlag)
+	default:
+		items := make([]selector.Item, len(projects))
+		for k, p := range projects {
+			items[k] = selector.Item{ID: p.Id, Label: p.Name}
+		}
+		if id, err = h.Select("Select the project which commands use by default:", "select one later using `ory use project`", items); err != nil {
+			h.Log.Warnf("No project was selected, select one using `ory use project`: %s", err)
+			return
+		}
+	}
+
+	uid, err := parseAPIUUID("project ID", id)
+	if err != nil {
+		h.Log.Warnf("Unable to select the project, select one using `ory use project`: %s", err)
+		return
+	}
+	ac.SelectedProject = uid
+	if err := h.WriteConfig(ac); err != nil {
+		h.Log.Warnf("Unable to save the selected project, select one using `ory use project`: %s", err)
+	}
+}
+
+// MultiSelect asks the user to pick any number of the items and returns the IDs of the selected items. If more is
+// not nil, the user can load more items. It fails instead of prompting if the user is unable to answer, see
+// RequireInteractive.
+func (h *CommandHelper) MultiSelect(prompt, alternative string, items []selector.Item, more selector.LoadMore) ([]string, error) {
+	if err := h.RequireInteractive(prompt, alternative); err != nil {
+		return nil, err
+	}
+
+	s := &selector.Selector{In: h.Stdin, Out: h.VerboseErrWriter, Terminal: h.terminal}
+	selected, err := s.MultiSelect(prompt, items, more)
+	if errors.Is(err, selector.ErrInterrupted) {
+		return nil, errors.WithStack(ErrAborted)
+	} else if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(selected))
+	for k, item := range selected {
+		ids[k] = item.ID
+	}
+	return ids, nil
+}
+
+// identityPageSize is the number of identities loaded at once by SelectIdentities.
+const identityPageSize = 100
+
+// SelectIdentities asks the user to pick any number of the identities of the project and returns their IDs. The
+// identities are loaded page by page. Identities are labeled with their email address or username trait if they have
+// one.
+func (h *CommandHelper) SelectIdentities(ctx context.Context, api *ProjectAPI, alternative string) ([]string, error) {
+	const prompt = "Select identities:"
+	if err := h.RequireInteractive(prompt, alternative); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"per_page": {strconv.Itoa(identityPageSize)}}
+	more := func() ([]selector.Item, error) {
+		if query == nil {
+			return nil, nil
+		}
+
+		var identities []struct {
+			ID     string          `json:"id"`
+			Traits json.RawMessage `json:"traits"`
+		}
+		res, err := api.Do(ctx, http.MethodGet, "/admin/identities", query, nil, &identities)
+		if err != nil {
+			return nil, err
+		}
+		query = NextPageQuery(res)
+
+		items := make([]selector.Item, len(identities))
+		for k, i := range identities {
+			items[k] = selector.Item{ID: i.ID, Label: gjson.GetBytes(i.Traits, "email").String()}
+			if items[k].Label == "" {
+				items[k].Label = gjson.GetBytes(i.Traits, "username").String()
+			}
+		}
+		return items, nil
+	}
+
+	items, err := more()
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("the project does not have any identities")
+	}
+	if query == nil {
+		return h.MultiSelect(prompt, alternative, items, nil)
+	}
+	return h.MultiSelect(prompt, alternative, items, more)
+}
@@ -0,0 +1,219 @@
+package selector
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// LoadMore returns the next items, e.g. the next page of a paginated API. It returns no items once all items were
+// loaded.
+type LoadMore func() ([]Item, error)
+
+// MultiSelect asks the user to pick any number of the items and prints the IDs of the selected items. If more is not
+// nil, the user can load more items. The selected items are returned in the order they were loaded.
+//
+// The fuzzy selector toggles the current item with space and all matching items with Ctrl-A. Pressing enter without
+// having selected any item selects the current one.
+func (s *Selector) MultiSelect(prompt string, items []Item, more LoadMore) ([]Item, error) {
+	if len(items) == 0 {
+		return nil, errors.New("there is nothing to select")
+	}
+
+	var selected []Item
+	var err error
+	if s.isCapableTerminal() {
+		selected, err = s.fuzzyMulti(prompt, items, more)
+	} else {
+		selected, err = s.numberedMulti(prompt, items, more)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(selected))
+	for k, item := range selected {
+		ids[k] = item.ID
+	}
+	_, _ = fmt.Fprintf(s.Out, "%s %s\n", prompt, strings.Join(ids, ", "))
+	return selected, nil
+}
+
+// loadMore appends the next items and returns false if there are no more items.
+func loadMore(items []Item, more LoadMore) ([]Item, bool, error) {
+	if more == nil {
+		return items, false, nil
+	}
+	next, err := more()
+	if err != nil {
+		return nil, false, err
+	}
+	return append(items, next...), len(next) > 0, nil
+}
+
+func selectedItems(items []Item, selected map[string]bool) []Item {
+	var result []Item
+	for _, item := range items {
+		if selected[item.ID] {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (s *Selector) numberedMulti(prompt string, items []Item, more LoadMore) ([]Item, error) {
+	in := s.reader()
+	_, _ = fmt.Fprintln(s.Out, prompt)
+	printed := 0
+	hasMore := more != nil
+
+	for {
+		for ; printed < len(items); printed++ {
+			_, _ = fmt.Fprintf(s.Out, "%3d) %s\n", printed+1, items[printed])
+		}
+
+		hint := `Enter numbers or IDs separated by spaces, or "all"`
+		if hasMore {
+			hint += `, or "more" to load more items`
+		}
+		_, _ = fmt.Fprint(s.Out, hint+": ")
+
+		line, err := in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if err != nil && answer == "" {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.WithStack(ErrInterrupted)
+			}
+			return nil, errors.WithStack(err)
+		}
+
+		switch answer {
+		case "":
+			_, _ = fmt.Fprintln(s.Out, "Please select at least one item.")
+			continue
+		case "all":
+			return items, nil
+		case "more":
+			if !hasMore {
+				_, _ = fmt.Fprintln(s.Out, "There are no more items.")
+				continue
+			}
+			var err error
+			if items, hasMore, err = loadMore(items, more); err != nil {
+				return nil, err
+			}
+			if !hasMore {
+				_, _ = fmt.Fprintln(s.Out, "There are no more items.")
+			}
+			continue
+		}
+
+		selected, invalid := parseChoices(answer, items)
+		if invalid != "" {
+			_, _ = fmt.Fprintf(s.Out, "%q is not a valid choice.\n", invalid)
+			continue
+		}
+		return selectedItems(items, selected), nil
+	}
+}
+
+// parseChoices resolves the numbers and IDs of the answer. It returns the first invalid choice, if any.
+func parseChoices(answer string, items []Item) (map[string]bool, string) {
+	selected := map[string]bool{}
+	for _, choice := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(items) {
+			selected[items[n-1].ID] = true
+			continue
+		}
+
+		var found bool
+		for _, item := range items {
+			if item.ID == choice {
+				selected[item.ID], found = true, true
+				break
+			}
+		}
+		if !found {
+			return nil, choice
+		}
+	}
+	return selected, ""
+}
+
+func (s *Selector) fuzzyMulti(prompt string, items []Item, more LoadMore) ([]Item, error) {
+	state, err := term.MakeRaw(int(s.Terminal.Fd()))
+	if err != nil {
+		return s.numberedMulti(prompt, items, more)
+	}
+	defer func() {
+		_ = term.Restore(int(s.Terminal.Fd()), state)
+	}()
+
+	l := &list{out: s.Out, prompt: prompt, items: items, matches: items, selected: map[string]bool{}, more: more != nil}
+	defer l.clear()
+
+	in := s.reader()
+	for {
+		l.render()
+
+		k, r, err := readKey(in)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case k == keyInterrupt:
+			return nil, errors.WithStack(ErrInterrupted)
+		case k == keyEnter && l.onLoadMore():
+			cursor := l.cursor
+			if l.items, l.more, err = loadMore(l.items, more); err != nil {
+				return nil, err
+			}
+			l.filter(l.query)
+			l.cursor = cursor
+			if l.cursor >= l.entries() {
+				l.cursor = l.entries() - 1
+			}
+			l.move(0)
+		case k == keyEnter && len(l.selected) > 0:
+			return selectedItems(l.items, l.selected), nil
+		case k == keyEnter && len(l.matches) > 0:
+			return []Item{l.matches[l.cursor]}, nil
+		case k == keyRune && r == ' ':
+			if !l.onLoadMore() && len(l.matches) > 0 {
+				l.toggle(l.matches[l.cursor])
+			}
+		case k == keyToggleAll:
+			l.toggleAll()
+		default:
+			l.handle(k, r)
+		}
+	}
+}
+
+func (l *list) toggle(item Item) {
+	if l.selected[item.ID] {
+		delete(l.selected, item.ID)
+	} else {
+		l.selected[item.ID] = true
+	}
+}
+
+// toggleAll selects all matching items, or deselects them if all of them are selected already.
+func (l *list) toggleAll() {
+	all := true
+	for _, item := range l.matches {
+		all = all && l.selected[item.ID]
+	}
+	for _, item := range l.matches {
+		if all {
+			delete(l.selected, item.ID)
+		} else {
+			l.selected[item.ID] = true
+		}
+	}
+}
@@ -0,0 +1,363 @@
+// Package selector lets the user pick one of several items, e.g. a project or an identity. On capable terminals, it
+// renders a list which can be navigated with the arrow keys and filtered by typing. Otherwise, it falls back to a
+// numbered list.
+package selector
+
+import (
+	"bufio"
+	stderrs "errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// maxVisible is the number of items shown at once by the fuzzy selector.
+const maxVisible = 10
+
+var ErrInterrupted = stderrs.New("selection interrupted")
+
+// Item is a selectable item.
+type Item struct {
+	// ID is the canonical identifier of the item. It is printed after the item was selected.
+	ID string
+	// Label describes the item, e.g. the name of a project. It is optional.
+	Label string
+}
+
+func (i Item) String() string {
+	if i.Label == "" {
+		return i.ID
+	}
+	return fmt.Sprintf("%s (%s)", i.Label, i.ID)
+}
+
+// Selector asks the user to pick an item.
+type Selector struct {
+	// In is the input the selection is read from.
+	In io.Reader
+	// Out is where the selector is rendered, usually stderr.
+	Out io.Writer
+	// Terminal is the file In reads from, if any. The fuzzy selector is only used if Terminal and Out are terminals
+	// and the terminal is not dumb.
+	Terminal *os.File
+}
+
+// Select asks the user to pick one of the items and prints the ID of the selected item.
+func (s *Selector) Select(prompt string, items []Item) (Item, error) {
+	if len(items) == 0 {
+		return Item{}, errors.New("there is nothing to select")
+	}
+
+	var selected Item
+	var err error
+	if s.isCapableTerminal() {
+		selected, err = s.fuzzy(prompt, items)
+	} else {
+		selected, err = s.numbered(prompt, items)
+	}
+	if err != nil {
+		return Item{}, err
+	}
+
+	_, _ = fmt.Fprintf(s.Out, "%s %s\n", prompt, selected.ID)
+	return selected, nil
+}
+
+func (s *Selector) isCapableTerminal() bool {
+	if s.Terminal == nil || !term.IsTerminal(int(s.Terminal.Fd())) {
+		return false
+	}
+	if t := os.Getenv("TERM"); t == "" || t == "dumb" {
+		return false
+	}
+	out, ok := s.Out.(*os.File)
+	return ok && term.IsTerminal(int(out.Fd()))
+}
+
+// reader returns In as a bufio.Reader, reusing it if possible so that no buffered input is lost.
+func (s *Selector) reader() *bufio.Reader {
+	if in, ok := s.In.(*bufio.Reader); ok {
+		return in
+	}
+	return bufio.NewReader(s.In)
+}
+
+func (s *Selector) numbered(prompt string, items []Item) (Item, error) {
+	in := s.reader()
+	_, _ = fmt.Fprintln(s.Out, prompt)
+	for k, item := range items {
+		_, _ = fmt.Fprintf(s.Out, "%3d) %s\n", k+1, item)
+	}
+
+	for {
+		_, _ = fmt.Fprintf(s.Out, "Enter a number between 1 and %d or an ID: ", len(items))
+		line, err := in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if err != nil && answer == "" {
+			if errors.Is(err, io.EOF) {
+				return Item{}, errors.WithStack(ErrInterrupted)
+			}
+			return Item{}, errors.WithStack(err)
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(items) {
+			return items[n-1], nil
+		}
+		for _, item := range items {
+			if item.ID == answer {
+				return item, nil
+			}
+		}
+		_, _ = fmt.Fprintf(s.Out, "%q is not a valid choice.\n", answer)
+	}
+}
+
+func (s *Selector) fuzzy(prompt string, items []Item) (Item, error) {
+	state, err := term.MakeRaw(int(s.Terminal.Fd()))
+	if err != nil {
+		return s.numbered(prompt, items)
+	}
+	defer func() {
+		_ = term.Restore(int(s.Terminal.Fd()), state)
+	}()
+
+	l := &list{out: s.Out, prompt: prompt, items: items, matches: items}
+	defer l.clear()
+
+	in := s.reader()
+	for {
+		l.render()
+
+		k, r, err := readKey(in)
+		if err != nil {
+			return Item{}, err
+		}
+
+		switch k {
+		case keyEnter:
+			if len(l.matches) > 0 {
+				return l.matches[l.cursor], nil
+			}
+		case keyInterrupt:
+			return Item{}, errors.WithStack(ErrInterrupted)
+		default:
+			l.handle(k, r)
+		}
+	}
+}
+
+type key int
+
+const (
+	keyIgnored key = iota
+	keyRune
+	keyEnter
+	keyInterrupt
+	keyUp
+	keyDown
+	keyBackspace
+	keyToggleAll
+)
+
+// readKey reads a key press from a terminal in raw mode.
+func readKey(in *bufio.Reader) (key, rune, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return keyIgnored, 0, errors.WithStack(err)
+	}
+
+	switch r {
+	case '\r', '\n':
+		return keyEnter, r, nil
+	case 0x03, 0x04: // Ctrl-C, Ctrl-D
+		return keyInterrupt, r, nil
+	case 0x10: // Ctrl-P
+		return keyUp, r, nil
+	case 0x0e: // Ctrl-N
+		return keyDown, r, nil
+	case 0x01: // Ctrl-A
+		return keyToggleAll, r, nil
+	case 0x7f, 0x08: // Backspace
+		return keyBackspace, r, nil
+	case 0x1b: // Escape sequences, e.g. the arrow keys
+		if next, _, _ := in.ReadRune(); next != '[' && next != 'O' {
+			return keyIgnored, r, nil
+		}
+		switch k, _, _ := in.ReadRune(); k {
+		case 'A':
+			return keyUp, r, nil
+		case 'B':
+			return keyDown, r, nil
+		}
+		return keyIgnored, r, nil
+	}
+	if r < ' ' {
+		return keyIgnored, r, nil
+	}
+	return keyRune, r, nil
+}
+
+// list is the state of the fuzzy selector.
+type list struct {
+	out     io.Writer
+	prompt  string
+	items   []Item
+	query   string
+	matches []Item
+	cursor  int
+	offset  int
+	lines   int
+
+	// selected holds the IDs of the selected items if multiple items can be selected.
+	selected map[string]bool
+	// more is true if more items can be loaded. The last entry of the list loads them.
+	more bool
+}
+
+// entries returns the number of entries in the list, including the entry loading more items.
+func (l *list) entries() int {
+	if l.more {
+		return len(l.matches) + 1
+	}
+	return len(l.matches)
+}
+
+// onLoadMore returns true if the cursor is on the entry loading more items.
+func (l *list) onLoadMore() bool {
+	return l.more && l.cursor == len(l.matches)
+}
+
+// handle applies the keys to navigate and filter the list.
+func (l *list) handle(k key, r rune) {
+	switch k {
+	case keyUp:
+		l.move(-1)
+	case keyDown:
+		l.move(1)
+	case keyBackspace:
+		if len(l.query) > 0 {
+			_, size := utf8.DecodeLastRuneInString(l.query)
+			l.filter(l.query[:len(l.query)-size])
+		}
+	case keyRune:
+		l.filter(l.query + string(r))
+	}
+}
+
+func (l *list) move(delta int) {
+	n := l.entries()
+	if n == 0 {
+		return
+	}
+	l.cursor = (l.cursor + delta + n) % n
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	} else if l.cursor >= l.offset+maxVisible {
+		l.offset = l.cursor - maxVisible + 1
+	}
+}
+
+func (l *list) filter(query string) {
+	l.query, l.matches, l.cursor, l.offset = query, Filter(l.items, query), 0, 0
+}
+
+// clear removes the rendered list from the terminal.
+func (l *list) clear() {
+	if l.lines > 0 {
+		_, _ = fmt.Fprintf(l.out, "\x1b[%dA", l.lines)
+	}
+	_, _ = fmt.Fprint(l.out, "\r\x1b[J")
+	l.lines = 0
+}
+
+func (l *list) render() {
+	l.clear()
+
+	// The terminal is in raw mode, so lines must end with \r\n.
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "%s %s", l.prompt, l.query)
+	if len(l.matches) == 0 {
+		b.WriteString("\r\n  no matches")
+		l.lines++
+	}
+	for k := l.offset; k < l.entries() && k < l.offset+maxVisible; k++ {
+		marker := "  "
+		if k == l.cursor {
+			marker = "> "
+		}
+		switch {
+		case k == len(l.matches):
+			b.WriteString("\r\n" + marker + "Load more…")
+		case l.selected == nil:
+			b.WriteString("\r\n" + marker + l.matches[k].String())
+		case l.selected[l.matches[k].ID]:
+			b.WriteString("\r\n" + marker + "[x] " + l.matches[k].String())
+		default:
+			b.WriteString("\r\n" + marker + "[ ] " + l.matches[k].String())
+		}
+		l.lines++
+	}
+	if hidden := l.entries() - maxVisible; hidden > 0 {
+		_, _ = fmt.Fprintf(&b, "\r\n  (%d more, type to filter)", hidden)
+		l.lines++
+	}
+	if l.selected != nil {
+		_, _ = fmt.Fprintf(&b, "\r\n  %d selected. Space: toggle, Ctrl-A: toggle all, Enter: confirm", len(l.selected))
+		l.lines++
+	}
+	_, _ = fmt.Fprint(l.out, b.String())
+}
+
+// Filter returns the items which contain the characters of the query in order, ignoring case. Items containing the
+// query as a substring are ranked first, followed by items where the characters are closest together.
+func Filter(items []Item, query string) []Item {
+	query = strings.ToLower(query)
+	type match struct {
+		item  Item
+		score int
+	}
+
+	var matches []match
+	for _, item := range items {
+		if score, ok := matchScore(strings.ToLower(item.String()), query); ok {
+			matches = append(matches, match{item: item, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+
+	filtered := make([]Item, len(matches))
+	for k, m := range matches {
+		filtered[k] = m.item
+	}
+	return filtered
+}
+
+// matchScore returns how far apart the characters of query are in s. Substring matches score 0 and always rank
+// first.
+func matchScore(s, query string) (int, bool) {
+	if strings.Contains(s, query) {
+		return 0, true
+	}
+
+	start, pos := -1, 0
+	for _, r := range query {
+		i := strings.IndexRune(s[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		if start < 0 {
+			start = pos + i
+		}
+		pos += i + utf8.RuneLen(r)
+	}
+	return pos - start, true
+}
@@ -0,0 +1,263 @@
+package selector
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var items = []Item{
+	{ID: "ecaaa3cb-0730-4ee8-a6df-9553cdfeef89", Label: "Production"},
+	{ID: "2d1e5f3a-8b7c-4d6e-9f0a-1b2c3d4e5f6a", Label: "Staging"},
+	{ID: "9f425a8d-7efc-4768-8f23-7647a74fdf13", Label: "Playground"},
+}
+
+// syncBuffer collects the output of the pseudo-terminal.
+type syncBuffer struct {
+	sync.Mutex
+	b bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.b.String()
+}
+
+// inPTY runs the selector on a pseudo-terminal and types the keys once the selector is rendered.
+func inPTY(t *testing.T, keys string, run func(s *Selector) error) (string, error) {
+	ptmx, tty, err := pty.Open()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = ptmx.Close()
+		_ = tty.Close()
+	})
+
+	var out syncBuffer
+	go func() {
+		_, _ = io.Copy(&out, ptmx)
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- run(&Selector{In: tty, Out: tty, Terminal: tty})
+	}()
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "> ")
+	}, 5*time.Second, 10*time.Millisecond, "%q", out.String())
+	_, err = ptmx.Write([]byte(keys))
+	require.NoError(t, err)
+
+	select {
+	case err := <-done:
+		// Wait for the output to be copied.
+		time.Sleep(50 * time.Millisecond)
+		return out.String(), err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("selector did not return, output: %q", out.String())
+		return "", nil
+	}
+}
+
+func selectInPTY(t *testing.T, keys string) (item Item, out string, err error) {
+	out, err = inPTY(t, keys, func(s *Selector) (err error) {
+		item, err = s.Select("Select a project:", items)
+		return err
+	})
+	return item, out, err
+}
+
+func multiSelectInPTY(t *testing.T, keys string, more LoadMore) (selected []Item, out string, err error) {
+	out, err = inPTY(t, keys, func(s *Selector) (err error) {
+		selected, err = s.MultiSelect("Select projects:", items[:2], more)
+		return err
+	})
+	return selected, out, err
+}
+
+// loadOnce loads the remaining items once.
+func loadOnce() LoadMore {
+	var loaded bool
+	return func() ([]Item, error) {
+		if loaded {
+			return nil, nil
+		}
+		loaded = true
+		return items[2:], nil
+	}
+}
+
+func TestSelector(t *testing.T) {
+	t.Run("case=fuzzy", func(t *testing.T) {
+		t.Setenv("TERM", "xterm")
+
+		t.Run("case=type to filter", func(t *testing.T) {
+			item, out, err := selectInPTY(t, "stg\r")
+			require.NoError(t, err)
+			assert.Equal(t, items[1], item)
+			assert.Contains(t, out, "Select a project: "+items[1].ID)
+		})
+
+		t.Run("case=arrow keys", func(t *testing.T) {
+			item, _, err := selectInPTY(t, "\x1b[B\x1b[B\x1b[A\r")
+			require.NoError(t, err)
+			assert.Equal(t, items[1], item)
+		})
+
+		t.Run("case=wraps around", func(t *testing.T) {
+			item, _, err := selectInPTY(t, "\x1b[A\r")
+			require.NoError(t, err)
+			assert.Equal(t, items[2], item)
+		})
+
+		t.Run("case=backspace", func(t *testing.T) {
+			item, _, err := selectInPTY(t, "stx\x7f\x7f\x7fpla\r")
+			require.NoError(t, err)
+			assert.Equal(t, items[2], item)
+		})
+
+		t.Run("case=interrupted", func(t *testing.T) {
+			_, _, err := selectInPTY(t, "\x03")
+			assert.ErrorIs(t, err, ErrInterrupted)
+		})
+	})
+
+	t.Run("case=falls back to a numbered list on dumb terminals", func(t *testing.T) {
+		t.Setenv("TERM", "dumb")
+		ptmx, tty, err := pty.Open()
+		require.NoError(t, err)
+		defer ptmx.Close()
+		defer tty.Close()
+
+		var out bytes.Buffer
+		s := &Selector{In: strings.NewReader("2\n"), Out: &out, Terminal: tty}
+		item, err := s.Select("Select a project:", items)
+		require.NoError(t, err)
+		assert.Equal(t, items[1], item)
+	})
+
+	t.Run("case=numbered", func(t *testing.T) {
+		var out bytes.Buffer
+		s := &Selector{In: strings.NewReader("4\nfoo\n" + items[2].ID + "\n"), Out: &out}
+		item, err := s.Select("Select a project:", items)
+		require.NoError(t, err)
+		assert.Equal(t, items[2], item)
+		assert.Equal(t, `Select a project:
+  1) Production (ecaaa3cb-0730-4ee8-a6df-9553cdfeef89)
+  2) Staging (2d1e5f3a-8b7c-4d6e-9f0a-1b2c3d4e5f6a)
+  3) Playground (9f425a8d-7efc-4768-8f23-7647a74fdf13)
+Enter a number between 1 and 3 or an ID: "4" is not a valid choice.
+Enter a number between 1 and 3 or an ID: "foo" is not a valid choice.
+Enter a number between 1 and 3 or an ID: Select a project: 9f425a8d-7efc-4768-8f23-7647a74fdf13
+`, out.String())
+	})
+
+	t.Run("case=numbered input ends", func(t *testing.T) {
+		s := &Selector{In: strings.NewReader(""), Out: io.Discard}
+		_, err := s.Select("Select a project:", items)
+		assert.ErrorIs(t, err, ErrInterrupted)
+	})
+}
+
+func TestFilter(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		expected []Item
+	}{
+		{query: "", expected: items},
+		{query: "PLAY", expected: items[2:]},
+		{query: "ag", expected: []Item{items[1], items[2]}},
+		{query: "xyz", expected: []Item{}},
+		{query: "2d1e", expected: items[1:2]},
+	} {
+		t.Run("query="+tc.query, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Filter(items, tc.query))
+		})
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	t.Run("case=fuzzy", func(t *testing.T) {
+		t.Setenv("TERM", "xterm")
+
+		t.Run("case=space toggles", func(t *testing.T) {
+			selected, out, err := multiSelectInPTY(t, " \x1b[B \x1b[A \r", nil)
+			require.NoError(t, err)
+			assert.Equal(t, items[1:2], selected)
+			assert.Contains(t, out, "Select projects: "+items[1].ID)
+		})
+
+		t.Run("case=selects the current item if none is selected", func(t *testing.T) {
+			selected, _, err := multiSelectInPTY(t, "stag\r", nil)
+			require.NoError(t, err)
+			assert.Equal(t, items[1:2], selected)
+		})
+
+		t.Run("case=toggles all matches", func(t *testing.T) {
+			selected, out, err := multiSelectInPTY(t, "\x01\r", nil)
+			require.NoError(t, err)
+			assert.Equal(t, items[:2], selected)
+			assert.Contains(t, out, "Select projects: "+items[0].ID+", "+items[1].ID)
+		})
+
+		t.Run("case=loads more", func(t *testing.T) {
+			selected, out, err := multiSelectInPTY(t, "\x1b[A\r\x01\r", loadOnce())
+			require.NoError(t, err)
+			assert.Equal(t, items, selected)
+			assert.Contains(t, out, "Load more…")
+		})
+
+		t.Run("case=interrupted", func(t *testing.T) {
+			_, _, err := multiSelectInPTY(t, " \x03", nil)
+			assert.ErrorIs(t, err, ErrInterrupted)
+		})
+	})
+
+	t.Run("case=numbered", func(t *testing.T) {
+		var out bytes.Buffer
+		s := &Selector{In: strings.NewReader("\n3\nmore\nmore\n3 " + items[0].ID + "\n"), Out: &out}
+		selected, err := s.MultiSelect("Select projects:", items[:2], loadOnce())
+		require.NoError(t, err)
+		assert.Equal(t, []Item{items[0], items[2]}, selected)
+		assert.Equal(t, `Select projects:
+  1) Production (ecaaa3cb-0730-4ee8-a6df-9553cdfeef89)
+  2) Staging (2d1e5f3a-8b7c-4d6e-9f0a-1b2c3d4e5f6a)
+Enter numbers or IDs separated by spaces, or "all", or "more" to load more items: Please select at least one item.
+Enter numbers or IDs separated by spaces, or "all", or "more" to load more items: "3" is not a valid choice.
+Enter numbers or IDs separated by spaces, or "all", or "more" to load more items:   3) Playground (9f425a8d-7efc-4768-8f23-7647a74fdf13)
+Enter numbers or IDs separated by spaces, or "all", or "more" to load more items: There are no more items.
+Enter numbers or IDs separated by spaces, or "all": Select projects: ecaaa3cb-0730-4ee8-a6df-9553cdfeef89, 9f425a8d-7efc-4768-8f23-7647a74fdf13
+`, out.String())
+	})
+
+	t.Run("case=numbered all", func(t *testing.T) {
+		s := &Selector{In: strings.NewReader("all\n"), Out: io.Discard}
+		selected, err := s.MultiSelect("Select projects:", items, nil)
+		require.NoError(t, err)
+		assert.Equal(t, items, selected)
+	})
+
+	t.Run("case=numbered input ends", func(t *testing.T) {
+		s := &Selector{In: strings.NewReader("1"), Out: io.Discard}
+		_, err := s.MultiSelect("Select projects:", items, nil)
+		require.NoError(t, err)
+
+		s = &Selector{In: strings.NewReader(""), Out: io.Discard}
+		_, err = s.MultiSelect("Select projects:", items, nil)
+		assert.ErrorIs(t, err, ErrInterrupted)
+	})
+}
@@ -0,0 +1,294 @@
+package client
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrs "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+)
+
+const (
+	// checksumsAsset is the name of the release asset listing the SHA-256 checksums of all other assets.
+	checksumsAsset = "checksums.txt"
+	// maxChecksumsSize limits the size of the checksums file.
+	maxChecksumsSize = 1 << 20
+)
+
+// ErrBinaryNotWritable is returned by PlanSelfUpdate if the running binary can not be replaced, e.g. because it was
+// installed by a package manager into a directory owned by root.
+var ErrBinaryNotWritable = stderrs.New("the binary can not be replaced")
+
+// packageManagerHint suggests how to update installations which can not update themselves.
+const packageManagerHint = "If you installed the CLI with a package manager, update it there instead, e.g. with `brew upgrade ory/tap/cli`, `scoop update ory`, or `npm install -g @ory/cli`."
+
+// SelfUpdate describes how the running binary is replaced by a release, see PlanSelfUpdate.
+type SelfUpdate struct {
+	// Version is the version of the release.
+	Version string `json:"version"`
+	// Binary is the path of the binary which is replaced.
+	Binary string `json:"binary"`
+	// Asset is the name of the archive containing the binary for this platform.
+	Asset        string `json:"asset"`
+	URL          string `json:"url"`
+	ChecksumsURL string `json:"checksums_url"`
+}
+
+// PlanSelfUpdate looks up the archive of the release for this platform. If version is empty, the latest release is
+// used. It fails with ErrBinaryNotWritable if the running binary can not be replaced.
+func (h *CommandHelper) PlanSelfUpdate(version string) (*SelfUpdate, error) {
+	binary, err := os.Executable()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if binary, err = filepath.EvalSymlinks(binary); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := checkWritable(binary); err != nil {
+		return nil, err
+	}
+
+	endpoint := releasesURL + "/latest"
+	if version != "" {
+		if _, err := semver.NewVersion(strings.TrimPrefix(version, "v")); err != nil {
+			return nil, NewValidationError(errors.Errorf("%q is not a version, expected e.g. v0.2.3", version), nil)
+		}
+		endpoint = releasesURL + "/tags/v" + strings.TrimPrefix(version, "v")
+	}
+	var r release
+	if err := h.fetchRelease(h.Ctx, endpoint, &r); err != nil {
+		return nil, errors.WithMessagef(err, "unable to look up the release")
+	}
+	return planSelfUpdate(&r, binary, runtime.GOOS, runtime.GOARCH)
+}
+
+func planSelfUpdate(r *release, binary, goos, goarch string) (*SelfUpdate, error) {
+	name, err := archiveName(r.Tag, goos, goarch)
+	if err != nil {
+		return nil, err
+	}
+	u := &SelfUpdate{Version: r.Tag, Binary: binary, Asset: name}
+	for _, a := range r.Assets {
+		switch a.Name {
+		case name:
+			u.URL = a.URL
+		case checksumsAsset:
+			u.ChecksumsURL = a.URL
+		}
+	}
+	if u.URL == "" {
+		return nil, errors.Errorf("release %s has no archive %s for %s/%s", r.Tag, name, goos, goarch)
+	} else if u.ChecksumsURL == "" {
+		return nil, errors.Errorf("release %s has no %s to verify the download with", r.Tag, checksumsAsset)
+	}
+	return u, nil
+}
+
+// archiveName returns the name of the release archive for the platform, following the naming of the release builds.
+func archiveName(version, goos, goarch string) (string, error) {
+	system := map[string]string{"darwin": "macOS", "linux": "linux", "windows": "windows"}[goos]
+	arch := map[string]string{"amd64": "64bit", "386": "32bit", "arm64": "arm64"}[goarch]
+	if system == "" || arch == "" {
+		return "", errors.Errorf("there are no release builds for %s/%s", goos, goarch)
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("ory_%s-%s_%s%s", strings.TrimPrefix(version, "v"), system, arch, ext), nil
+}
+
+// checkWritable checks that a file can be created next to the binary, which is needed to replace it atomically.
+func checkWritable(binary string) error {
+	f, err := os.CreateTemp(filepath.Dir(binary), ".ory-update-*")
+	if err != nil {
+		return errors.WithStack(fmt.Errorf("%w: %s is not writable. %s", ErrBinaryNotWritable, filepath.Dir(binary), packageManagerHint))
+	}
+	_ = f.Close()
+	return errors.WithStack(os.Remove(f.Name()))
+}
+
+// ApplySelfUpdate downloads the archive, verifies its checksum, and replaces the binary. The download is written to a
+// temporary file next to the binary, which is only moved into place once it is complete and verified.
+func (h *CommandHelper) ApplySelfUpdate(u *SelfUpdate) error {
+	removeStaleBinary(u.Binary)
+
+	checksums, err := h.download(u.ChecksumsURL, maxChecksumsSize)
+	if err != nil {
+		return err
+	}
+	want, err := findChecksum(checksums, u.Asset)
+	if err != nil {
+		return err
+	}
+
+	archive, err := os.CreateTemp(filepath.Dir(u.Binary), ".ory-download-*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	res, err := h.get(u.URL)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(archive, hash), res.Body)
+	if err != nil {
+		return errors.Wrapf(err, "downloading %s failed", u.Asset)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return errors.Errorf("the checksum of %s is %s but %s expected %s, the download is discarded", u.Asset, got, checksumsAsset, want)
+	}
+	h.Log.Debugf("Downloaded %s (%d bytes), the checksum matches", u.Asset, size)
+
+	return replaceBinary(archive, size, u.Asset, u.Binary)
+}
+
+// get sends a GET request for a release asset. The caller must close the body of the response.
+func (h *CommandHelper) get(url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(h.Ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	res, err := h.client.httpClient("").Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, errors.Errorf("downloading %s failed: GitHub responded with %s", url, res.Status)
+	}
+	return res, nil
+}
+
+func (h *CommandHelper) download(url string, limit int64) ([]byte, error) {
+	res, err := h.get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, limit))
+	return body, errors.Wrapf(err, "downloading %s failed", url)
+}
+
+// findChecksum returns the checksum of the asset from a checksums file in the format of sha256sum.
+func findChecksum(checksums []byte, asset string) (string, error) {
+	s := bufio.NewScanner(strings.NewReader(string(checksums)))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", errors.Errorf("%s does not list the checksum of %s", checksumsAsset, asset)
+}
+
+// replaceBinary extracts the binary from the archive and moves it into place. The running binary can not be
+// overwritten on Windows, but it can be renamed, so it is moved aside first and removed by the next update.
+func replaceBinary(archive io.ReaderAt, size int64, name, binary string) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(binary), ".ory-binary-*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = f.Close()
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if err := extractBinary(archive, size, name, f); err != nil {
+		return err
+	}
+	if err := f.Chmod(0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if runtime.GOOS == "windows" {
+		if err := os.Rename(binary, staleBinary(binary)); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := os.Rename(f.Name(), binary); err != nil {
+			// Restore the previous binary, so that the CLI keeps working.
+			_ = os.Rename(staleBinary(binary), binary)
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	return errors.WithStack(os.Rename(f.Name(), binary))
+}
+
+func staleBinary(binary string) string {
+	return binary + ".old"
+}
+
+// removeStaleBinary removes the binary which a previous update on Windows moved aside.
+func removeStaleBinary(binary string) {
+	_ = os.Remove(staleBinary(binary))
+}
+
+// extractBinary copies the ory binary from the tar.gz or zip archive to w.
+func extractBinary(archive io.ReaderAt, size int64, name string, w io.Writer) error {
+	if strings.HasSuffix(name, ".zip") {
+		zr, err := zip.NewReader(archive, size)
+		if err != nil {
+			return errors.Wrapf(err, "unable to read %s", name)
+		}
+		for _, f := range zr.File {
+			if !isBinary(f.Name) {
+				continue
+			}
+			r, err := f.Open()
+			if err != nil {
+				return errors.Wrapf(err, "unable to read %s", name)
+			}
+			defer r.Close()
+			_, err = io.Copy(w, r)
+			return errors.Wrapf(err, "unable to extract the binary from %s", name)
+		}
+		return errors.Errorf("%s does not contain the ory binary", name)
+	}
+
+	gz, err := gzip.NewReader(io.NewSectionReader(archive, 0, size))
+	if err != nil {
+		return errors.Wrapf(err, "unable to read %s", name)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return errors.Errorf("%s does not contain the ory binary", name)
+		} else if err != nil {
+			return errors.Wrapf(err, "unable to read %s", name)
+		}
+		if hdr.Typeflag == tar.TypeReg && isBinary(hdr.Name) {
+			_, err = io.Copy(w, tr)
+			return errors.Wrapf(err, "unable to extract the binary from %s", name)
+		}
+	}
+}
+
+func isBinary(path string) bool {
+	base := filepath.Base(path)
+	return base == "ory" || base == "ory.exe"
+}
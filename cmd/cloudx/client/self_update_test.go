@@ -0,0 +1,231 @@
+package client
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+)
+
+func newTarGz(t *testing.T, files map[string]string) []byte {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return b.Bytes()
+}
+
+func newZip(t *testing.T, files map[string]string) []byte {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return b.Bytes()
+}
+
+func TestArchiveName(t *testing.T) {
+	for _, tc := range []struct{ goos, goarch, name string }{
+		{"linux", "amd64", "ory_0.2.3-linux_64bit.tar.gz"},
+		{"darwin", "arm64", "ory_0.2.3-macOS_arm64.tar.gz"},
+		{"windows", "386", "ory_0.2.3-windows_32bit.zip"},
+	} {
+		t.Run("platform="+tc.goos+"/"+tc.goarch, func(t *testing.T) {
+			name, err := archiveName("v0.2.3", tc.goos, tc.goarch)
+			require.NoError(t, err)
+			assert.Equal(t, tc.name, name)
+		})
+	}
+
+	_, err := archiveName("v0.2.3", "plan9", "amd64")
+	assert.ErrorContains(t, err, "no release builds for plan9/amd64")
+}
+
+func TestPlanSelfUpdate(t *testing.T) {
+	r := &release{Tag: "v0.2.3", Assets: []releaseAsset{
+		{Name: "ory_0.2.3-linux_64bit.tar.gz", URL: "https://example.com/ory_0.2.3-linux_64bit.tar.gz"},
+		{Name: "checksums.txt", URL: "https://example.com/checksums.txt"},
+	}}
+
+	u, err := planSelfUpdate(r, "/usr/local/bin/ory", "linux", "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, &SelfUpdate{
+		Version:      "v0.2.3",
+		Binary:       "/usr/local/bin/ory",
+		Asset:        "ory_0.2.3-linux_64bit.tar.gz",
+		URL:          "https://example.com/ory_0.2.3-linux_64bit.tar.gz",
+		ChecksumsURL: "https://example.com/checksums.txt",
+	}, u)
+
+	_, err = planSelfUpdate(r, "/usr/local/bin/ory", "darwin", "amd64")
+	assert.ErrorContains(t, err, "has no archive ory_0.2.3-macOS_64bit.tar.gz")
+
+	r.Assets = r.Assets[:1]
+	_, err = planSelfUpdate(r, "/usr/local/bin/ory", "linux", "amd64")
+	assert.ErrorContains(t, err, "has no checksums.txt")
+}
+
+func TestFindChecksum(t *testing.T) {
+	checksums := []byte("ABC123  ory_0.2.3-linux_64bit.tar.gz\ndef456 *ory_0.2.3-windows_64bit.zip\n")
+
+	sum, err := findChecksum(checksums, "ory_0.2.3-linux_64bit.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", sum)
+
+	sum, err = findChecksum(checksums, "ory_0.2.3-windows_64bit.zip")
+	require.NoError(t, err)
+	assert.Equal(t, "def456", sum)
+
+	_, err = findChecksum(checksums, "ory_0.2.3-macOS_arm64.tar.gz")
+	assert.ErrorContains(t, err, "does not list the checksum")
+}
+
+func TestReplaceBinary(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		archive []byte
+	}{
+		{"ory.tar.gz", newTarGz(t, map[string]string{"LICENSE": "license", "ory": "new"})},
+		{"ory.zip", newZip(t, map[string]string{"README.md": "readme", "ory.exe": "new"})},
+	} {
+		t.Run("archive="+tc.name, func(t *testing.T) {
+			binary := filepath.Join(t.TempDir(), "ory")
+			require.NoError(t, os.WriteFile(binary, []byte("old"), 0755))
+
+			require.NoError(t, replaceBinary(bytes.NewReader(tc.archive), int64(len(tc.archive)), tc.name, binary))
+			content, err := os.ReadFile(binary)
+			require.NoError(t, err)
+			assert.Equal(t, "new", string(content))
+		})
+	}
+
+	t.Run("case=keeps the binary if the archive is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		binary := filepath.Join(dir, "ory")
+		require.NoError(t, os.WriteFile(binary, []byte("old"), 0755))
+
+		archive := newTarGz(t, map[string]string{"LICENSE": "license"})
+		assert.ErrorContains(t, replaceBinary(bytes.NewReader(archive), int64(len(archive)), "ory.tar.gz", binary), "does not contain the ory binary")
+		content, err := os.ReadFile(binary)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "temporary files are removed")
+	})
+}
+
+func TestApplySelfUpdate(t *testing.T) {
+	name, err := archiveName("v0.2.3", runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		t.Skip(err)
+	}
+	archive := newTarGz(t, map[string]string{"ory": "new"})
+	if runtime.GOOS == "windows" {
+		archive = newZip(t, map[string]string{"ory.exe": "new"})
+	}
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checksums.txt":
+			_, _ = fmt.Fprintf(w, "%s  %s\n", checksum, name)
+		case "/" + name:
+			_, _ = w.Write(archive)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv(ProxyEnv, "")
+
+	apply := func(t *testing.T, binary string) error {
+		u := &SelfUpdate{Version: "v0.2.3", Binary: binary, Asset: name, URL: srv.URL + "/" + name, ChecksumsURL: srv.URL + "/checksums.txt"}
+		cmd := &cobra.Command{
+			Use: "self-update",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				h, err := NewCommandHelper(cmd)
+				if err != nil {
+					return err
+				}
+				return h.ApplySelfUpdate(u)
+			},
+		}
+		RegisterConfigFlag(cmd.Flags())
+		RegisterYesFlag(cmd.Flags())
+		cmdx.RegisterNoiseFlags(cmd.Flags())
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetArgs([]string{"--" + ConfigFlag, filepath.Join(t.TempDir(), "config.json")})
+		return cmd.ExecuteContext(context.Background())
+	}
+
+	t.Run("case=replaces the binary", func(t *testing.T) {
+		binary := filepath.Join(t.TempDir(), "ory")
+		require.NoError(t, os.WriteFile(binary, []byte("old"), 0755))
+		require.NoError(t, apply(t, binary))
+		content, err := os.ReadFile(binary)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content))
+	})
+
+	t.Run("case=discards downloads with the wrong checksum", func(t *testing.T) {
+		original := checksum
+		checksum = hex.EncodeToString(make([]byte, sha256.Size))
+		t.Cleanup(func() { checksum = original })
+
+		dir := t.TempDir()
+		binary := filepath.Join(dir, "ory")
+		require.NoError(t, os.WriteFile(binary, []byte("old"), 0755))
+		assert.ErrorContains(t, apply(t, binary), "the download is discarded")
+
+		content, err := os.ReadFile(binary)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(content))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "the partial download is removed")
+	})
+}
+
+func TestCheckWritable(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getuid() == 0 {
+		t.Skip("directory permissions are not enforced")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.Chmod(dir, 0555))
+	t.Cleanup(func() { _ = os.Chmod(dir, 0755) })
+
+	err := checkWritable(filepath.Join(dir, "ory"))
+	assert.ErrorIs(t, err, ErrBinaryNotWritable)
+	assert.ErrorContains(t, err, "package manager")
+	assert.NoError(t, checkWritable(filepath.Join(t.TempDir(), "ory")))
+}
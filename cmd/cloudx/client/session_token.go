@@ -0,0 +1,97 @@
+package client
+
+import (
+	stderrs "errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// SessionTokenFlag sets the session token which commands use instead of the session of the configuration file,
+	// e.g. in CI where nobody is able to sign in.
+	SessionTokenFlag = "session-token"
+	// SessionTokenEnv is like SessionTokenFlag. The flag takes precedence.
+	SessionTokenEnv = "ORY_SESSION_TOKEN"
+)
+
+// ErrSessionNotStored is returned when changing the session or the selected project of the configuration file while
+// the session is set by SessionTokenFlag or SessionTokenEnv. Such sessions are never written to the configuration file.
+var ErrSessionNotStored = stderrs.New("the session is set by a session token and not stored in the configuration file")
+
+// RegisterSessionTokenFlag registers the flag which sets the session token, see SessionTokenFlag.
+func RegisterSessionTokenFlag(f *pflag.FlagSet) {
+	f.String(SessionTokenFlag, "", fmt.Sprintf("The session token to use instead of signing in, e.g. in CI. Defaults to the %s environment variable. The session is never written to the configuration file.", SessionTokenEnv))
+}
+
+// sessionTokenFromCmd returns the session token set by the --session-token flag or, if the flag is not set, the
+// ORY_SESSION_TOKEN environment variable, and the name of the one which set it.
+func sessionTokenFromCmd(cmd *cobra.Command) (token, source string) {
+	if f := cmd.Flags().Lookup(SessionTokenFlag); f != nil && f.Value.String() != "" {
+		return f.Value.String(), "--" + SessionTokenFlag
+	}
+	if token := os.Getenv(SessionTokenEnv); token != "" {
+		return token, SessionTokenEnv
+	}
+	return "", ""
+}
+
+// errSessionNotStored returns ErrSessionNotStored naming the flag or environment variable which set the session.
+func (h *CommandHelper) errSessionNotStored() error {
+	return errors.WithStack(fmt.Errorf("%w: you are signed in using %s", ErrSessionNotStored, h.sessionTokenSource))
+}
+
+// tokenContext returns the configuration of the session set by --session-token or ORY_SESSION_TOKEN. The session is
+// checked once per command, without reading the configuration file or prompting. An invalid or expired token fails
+// instead of falling back to signing in.
+func (h *CommandHelper) tokenContext() (*AuthContext, error) {
+	if h.tokenAuth != nil {
+		return h.tokenAuth, nil
+	}
+	if h.prefetchProjects && h.projects == nil {
+		h.projects = h.fetchProjects(h.sessionToken)
+	}
+
+	sess, res, err := h.client.authAPI().V0alpha2Api.ToSession(h.Ctx).XSessionToken(h.sessionToken).Execute()
+	if err != nil {
+		h.projects = nil
+		if res != nil && (res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden) {
+			return nil, errors.WithStack(fmt.Errorf("%w: the session token set by %s was rejected with %s", ErrSessionExpired, h.sessionTokenSource, res.Status))
+		}
+		return nil, handleError("unable to check the session token set by "+h.sessionTokenSource, res, err)
+	}
+
+	ac, err := h.sessionToContext(sess, h.sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	h.warnOnConfigIdentity(ac)
+	h.Log.Infof("You are authenticated as: %s", ac.IdentityTraits.Email)
+	h.tokenAuth = ac
+	return ac, nil
+}
+
+// warnOnConfigIdentity warns in verbose mode if the configuration file is signed in as another identity than the
+// session token, which takes precedence.
+func (h *CommandHelper) warnOnConfigIdentity(ac *AuthContext) {
+	if !h.Verbose {
+		return
+	}
+	c, err := h.readConfig()
+	if err != nil || c.SessionToken == "" {
+		return
+	}
+
+	differs := c.IdentityTraits.ID != ac.IdentityTraits.ID
+	if c.IdentityTraits.ID == uuid.Nil {
+		differs = c.IdentityTraits.Email != ac.IdentityTraits.Email
+	}
+	if differs {
+		h.Log.Warnf("The session token set by %s belongs to %s, but the configuration file %s is signed in as %s. The session token is used.", h.sessionTokenSource, ac.IdentityTraits.Email, h.ConfigLocation, c.IdentityTraits.Email)
+	}
+}
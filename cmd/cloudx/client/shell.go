@@ -0,0 +1,87 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const ShellFlag = "shell"
+
+// The shells which ShellExports supports. ShellPOSIX covers sh, bash, and zsh.
+const (
+	ShellPOSIX      = "posix"
+	ShellFish       = "fish"
+	ShellPowerShell = "powershell"
+)
+
+// shellAliases maps the accepted values of the --shell flag to the supported shells.
+var shellAliases = map[string]string{
+	ShellPOSIX:      ShellPOSIX,
+	"sh":            ShellPOSIX,
+	"bash":          ShellPOSIX,
+	"zsh":           ShellPOSIX,
+	ShellFish:       ShellFish,
+	ShellPowerShell: ShellPowerShell,
+	"pwsh":          ShellPowerShell,
+}
+
+// dotenvQuotes escapes double quoted dotenv values. Dollar signs are escaped because some dotenv loaders expand
+// variables in double quoted values.
+var dotenvQuotes = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`)
+
+// fishQuotes escapes single quotes and backslashes in fish strings.
+var fishQuotes = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// powerShellQuotes escapes single quotes in PowerShell strings. PowerShell treats typographic single quotes as quotes
+// as well.
+var powerShellQuotes = strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019", "\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b")
+
+// EnvVar is an environment variable exported by ShellExports.
+type EnvVar struct {
+	Name, Value string
+}
+
+// RegisterShellFlag registers the flag which selects the syntax of ShellExports.
+func RegisterShellFlag(f *pflag.FlagSet) {
+	f.String(ShellFlag, ShellPOSIX, "The shell to print the commands for. One of posix (sh, bash, zsh), fish, or powershell.")
+}
+
+// Shell returns the shell selected by the --shell flag.
+func Shell(cmd *cobra.Command) (string, error) {
+	v, _ := cmd.Flags().GetString(ShellFlag)
+	shell, ok := shellAliases[strings.ToLower(v)]
+	if !ok {
+		return "", NewValidationError(errors.Errorf("unknown shell %q, use one of posix, sh, bash, zsh, fish, or powershell", v), nil)
+	}
+	return shell, nil
+}
+
+// ShellExports returns the commands which export vars in the given shell, one per line. The values are quoted so
+// that evaluating the output never runs anything else.
+func ShellExports(shell string, vars []EnvVar) string {
+	var b strings.Builder
+	for _, v := range vars {
+		switch shell {
+		case ShellFish:
+			_, _ = fmt.Fprintf(&b, "set -gx %s '%s';\n", v.Name, fishQuotes.Replace(v.Value))
+		case ShellPowerShell:
+			_, _ = fmt.Fprintf(&b, "$Env:%s = '%s'\n", v.Name, powerShellQuotes.Replace(v.Value))
+		default:
+			_, _ = fmt.Fprintf(&b, "export %s='%s'\n", v.Name, strings.ReplaceAll(v.Value, `'`, `'\''`))
+		}
+	}
+	return b.String()
+}
+
+// Dotenv returns vars in the dotenv format, one KEY="value" assignment per line.
+func Dotenv(vars []EnvVar) string {
+	var b strings.Builder
+	for _, v := range vars {
+		_, _ = fmt.Fprintf(&b, "%s=\"%s\"\n", v.Name, dotenvQuotes.Replace(v.Value))
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellExports(t *testing.T) {
+	vars := []EnvVar{{Name: "ORY_PROJECT_SLUG", Value: "good-wright-t7kzy3vugf"}, {Name: "ORY_SESSION_TOKEN", Value: `it's $(rm -rf) \n`}}
+
+	assert.Equal(t, `export ORY_PROJECT_SLUG='good-wright-t7kzy3vugf'
+export ORY_SESSION_TOKEN='it'\''s $(rm -rf) \n'
+`, ShellExports(ShellPOSIX, vars))
+	assert.Equal(t, `set -gx ORY_PROJECT_SLUG 'good-wright-t7kzy3vugf';
+set -gx ORY_SESSION_TOKEN 'it\'s $(rm -rf) \\n';
+`, ShellExports(ShellFish, vars))
+	assert.Equal(t, `$Env:ORY_PROJECT_SLUG = 'good-wright-t7kzy3vugf'
+$Env:ORY_SESSION_TOKEN = 'it''s $(rm -rf) \n'
+`, ShellExports(ShellPowerShell, vars))
+
+	t.Run("case=sh evaluates the exports", func(t *testing.T) {
+		sh, err := exec.LookPath("sh")
+		if err != nil {
+			t.Skip("sh is not installed")
+		}
+		out, err := exec.Command(sh, "-c", ShellExports(ShellPOSIX, vars)+`printf %s "$ORY_SESSION_TOKEN"`).Output()
+		require.NoError(t, err)
+		assert.Equal(t, vars[1].Value, string(out))
+	})
+
+	t.Run("case=--shell", func(t *testing.T) {
+		for value, shell := range map[string]string{"": ShellPOSIX, "zsh": ShellPOSIX, "fish": ShellFish, "PowerShell": ShellPowerShell, "pwsh": ShellPowerShell} {
+			cmd := &cobra.Command{}
+			RegisterShellFlag(cmd.Flags())
+			if value != "" {
+				require.NoError(t, cmd.Flags().Set(ShellFlag, value))
+			}
+			actual, err := Shell(cmd)
+			require.NoError(t, err)
+			assert.Equal(t, shell, actual, value)
+		}
+
+		cmd := &cobra.Command{}
+		RegisterShellFlag(cmd.Flags())
+		require.NoError(t, cmd.Flags().Set(ShellFlag, "cmd.exe"))
+		_, err := Shell(cmd)
+		assert.ErrorContains(t, err, `unknown shell "cmd.exe"`)
+	})
+}
+
+func TestDotenv(t *testing.T) {
+	assert.Equal(t, `ORY_SDK_URL="https://good-wright-t7kzy3vugf.projects.oryapis.com"
+ORY_CLIENT_SECRET="it's \"\${x}\" \\n\n"
+`, Dotenv([]EnvVar{
+		{Name: "ORY_SDK_URL", Value: "https://good-wright-t7kzy3vugf.projects.oryapis.com"},
+		{Name: "ORY_CLIENT_SECRET", Value: "it's \"${x}\" \\n\n"},
+	}))
+	assert.Empty(t, Dotenv(nil))
+}
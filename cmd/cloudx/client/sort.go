@@ -0,0 +1,177 @@
+package client
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	SortFlag = "sort"
+	DescFlag = "desc"
+)
+
+// RegisterSortFlags registers the --sort and --desc flags of commands listing resources. None of the list APIs
+// support ordering, so lists are sorted by the CLI. Paginated lists are sorted per page.
+func RegisterSortFlags(f *pflag.FlagSet) {
+	f.String(SortFlag, "", "Sort the list by a table column name or a gjson path into the JSON output. Numbers are sorted numerically, everything else alphabetically. Ties are sorted by ID.")
+	f.Bool(DescFlag, false, "Sort in descending order.")
+}
+
+// SortKey returns the value of the --sort flag or an empty string if the command does not have the flag.
+func SortKey(cmd *cobra.Command) string {
+	f := cmd.Flags().Lookup(SortFlag)
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+// sortErrors holds the errors of sorting the tables passed to PrintTable, which EnableSortFlag returns.
+var sortErrors = struct {
+	sync.Mutex
+	m map[*cobra.Command]error
+}{m: map[*cobra.Command]error{}}
+
+func recordSortError(cmd *cobra.Command, err error) bool {
+	sortErrors.Lock()
+	defer sortErrors.Unlock()
+	if _, ok := sortErrors.m[cmd]; !ok {
+		return false
+	}
+	sortErrors.m[cmd] = err
+	return true
+}
+
+// EnableSortFlag returns the error of sorting the output of cmd or any of its sub commands by an unknown column.
+func EnableSortFlag(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableSortFlag(c)
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if SortKey(cmd) == "" {
+			return run(cmd, args)
+		}
+
+		sortErrors.Lock()
+		sortErrors.m[cmd] = nil
+		sortErrors.Unlock()
+		defer func() {
+			sortErrors.Lock()
+			delete(sortErrors.m, cmd)
+			sortErrors.Unlock()
+		}()
+
+		if err := run(cmd, args); err != nil {
+			return err
+		}
+
+		sortErrors.Lock()
+		defer sortErrors.Unlock()
+		return sortErrors.m[cmd]
+	}
+}
+
+// sortedTable is a table in the order given by the --sort and --desc flags.
+type sortedTable struct {
+	table cmdx.Table
+	order []int
+	items []json.RawMessage
+}
+
+func (t *sortedTable) Header() []string {
+	return t.table.Header()
+}
+
+func (t *sortedTable) Len() int {
+	return len(t.order)
+}
+
+func (t *sortedTable) Table() [][]string {
+	rows := t.table.Table()
+	sorted := make([][]string, len(t.order))
+	for k, i := range t.order {
+		sorted[k] = rows[i]
+	}
+	return sorted
+}
+
+func (t *sortedTable) Interface() interface{} {
+	sorted := make([]json.RawMessage, len(t.order))
+	for k, i := range t.order {
+		sorted[k] = t.items[i]
+	}
+	return sorted
+}
+
+// sortTable sorts the table by the --sort flag, which is resolved like the --columns flag. The ID column breaks ties
+// so that the order is stable across runs.
+func sortTable(cmd *cobra.Command, table cmdx.Table) (cmdx.Table, error) {
+	key := SortKey(cmd)
+	if key == "" || table.Len() == 0 {
+		return table, nil
+	}
+
+	raw, err := json.Marshal(table.Interface())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s, err := newColumnSource(raw, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.items) != table.Len() {
+		return nil, errors.New("unable to sort the list because the output is not a list")
+	}
+
+	keys, ok := s.cells(key)
+	if !ok {
+		return nil, NewValidationError(errors.Errorf("unable to sort by unknown column %q, available columns are: %s", key, strings.Join(s.available(), ", ")), nil)
+	}
+	ids, _ := s.cells("id")
+	desc, _ := cmd.Flags().GetBool(DescFlag)
+
+	order := make([]int, len(s.items))
+	items := make([]json.RawMessage, len(s.items))
+	for k, item := range s.items {
+		order[k] = k
+		items[k] = json.RawMessage(item.Raw)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if c := compareSortKeys(keys[i], keys[j]); c != 0 {
+			return (c < 0) != desc
+		}
+		return compareSortKeys(ids[i], ids[j]) < 0
+	})
+
+	return &sortedTable{table: table, order: order, items: items}, nil
+}
+
+// compareSortKeys compares numbers numerically and everything else alphabetically.
+func compareSortKeys(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	switch {
+	case errA == nil && errB == nil && x < y:
+		return -1
+	case errA == nil && errB == nil && x > y:
+		return 1
+	case errA == nil && errB == nil:
+		return 0
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableSortFlag(t *testing.T) {
+	identities := testIdentities{
+		{"id": "c", "traits": map[string]interface{}{"email": "b@example.com"}, "logins": 10},
+		{"id": "a", "traits": map[string]interface{}{"email": "b@example.com"}, "logins": 9},
+		{"id": "b", "traits": map[string]interface{}{"email": "a@example.com"}, "logins": 100},
+	}
+	run := func(t *testing.T, args ...string) (string, error) {
+		cmd := &cobra.Command{Use: "identities", SilenceUsage: true, RunE: func(cmd *cobra.Command, _ []string) error {
+			PrintTable(cmd, identities)
+			return nil
+		}}
+		RegisterFormatFlag(cmd.Flags())
+		RegisterSortFlags(cmd.Flags())
+		EnableSortFlag(cmd)
+
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(append(args, "--format", "json"))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "unsorted", expected: `["c","a","b"]`},
+		{name: "table column with ties sorted by ID", args: []string{"--sort", "verified_address"}, expected: `["b","a","c"]`},
+		{name: "descending", args: []string{"--sort", "traits.email", "--desc"}, expected: `["a","c","b"]`},
+		{name: "numbers", args: []string{"--sort", "logins"}, expected: `["a","c","b"]`},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			out, err := run(t, tc.args...)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, gjsonIDs(t, out))
+		})
+	}
+
+	t.Run("case=fails on unknown columns", func(t *testing.T) {
+		out, err := run(t, "--sort", "nope")
+		assert.ErrorContains(t, err, `unable to sort by unknown column "nope", available columns are: id, verified_address`)
+		assert.Empty(t, out)
+	})
+}
+
+func gjsonIDs(t *testing.T, out string) string {
+	var ids bytes.Buffer
+	_, err := printQueryResults(&ids, bytes.NewBufferString(out), "id")
+	require.NoError(t, err)
+	return `["` + string(bytes.Join(bytes.Fields(ids.Bytes()), []byte(`","`))) + `"]`
+}
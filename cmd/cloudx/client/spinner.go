@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// spinnerDelay is how long an operation may take before the spinner is shown.
+	spinnerDelay    = 300 * time.Millisecond
+	spinnerInterval = 100 * time.Millisecond
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// WithSpinner runs fn and shows a spinner with the label, e.g. "Creating project", on stderr if fn takes longer than
+// spinnerDelay. The spinner is only shown if stderr is a terminal and neither --quiet nor a machine readable format is
+// set. It is cleared before WithSpinner returns, so that the output of the command is never garbled. Nested calls run
+// fn without a spinner of their own.
+//
+// The context passed to fn is canceled if the user presses Ctrl-C, which stops the spinner and leaves a clean line.
+func (h *CommandHelper) WithSpinner(label string, fn func(ctx context.Context) error) error {
+	if !h.spinner || !atomic.CompareAndSwapInt32(&h.spinning, 0, 1) {
+		return fn(h.Ctx)
+	}
+	defer atomic.StoreInt32(&h.spinning, 0)
+
+	ctx, cancel := signal.NotifyContext(h.Ctx, os.Interrupt)
+	defer cancel()
+
+	s := startSpinner(ctx, h.VerboseErrWriter, label, spinnerDelay)
+	defer s.Stop()
+	return fn(ctx)
+}
+
+type spinner struct {
+	w        io.Writer
+	label    string
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func startSpinner(ctx context.Context, w io.Writer, label string, delay time.Duration) *spinner {
+	s := &spinner{w: w, label: label, stop: make(chan struct{}), done: make(chan struct{})}
+	go s.run(ctx, delay)
+	return s
+}
+
+func (s *spinner) run(ctx context.Context, delay time.Duration) {
+	defer close(s.done)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-s.stop:
+		return
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		_, _ = fmt.Fprintf(s.w, "\r\x1b[K%s %s…", spinnerFrames[i%len(spinnerFrames)], s.label)
+		select {
+		case <-s.stop:
+		case <-ctx.Done():
+		case <-ticker.C:
+			continue
+		}
+		_, _ = fmt.Fprint(s.w, "\r\x1b[K")
+		return
+	}
+}
+
+// Stop clears the spinner and waits until it is cleared.
+func (s *spinner) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer collects the output of the spinner goroutine.
+type syncBuffer struct {
+	sync.Mutex
+	b bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.b.String()
+}
+
+func TestSpinner(t *testing.T) {
+	t.Run("case=is not shown for fast operations", func(t *testing.T) {
+		var out syncBuffer
+		s := startSpinner(context.Background(), &out, "Creating project", time.Hour)
+		s.Stop()
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("case=is cleared when stopped", func(t *testing.T) {
+		var out syncBuffer
+		s := startSpinner(context.Background(), &out, "Creating project", 0)
+		require.Eventually(t, func() bool {
+			return strings.Contains(out.String(), "Creating project…")
+		}, time.Second, 10*time.Millisecond)
+		s.Stop()
+		s.Stop()
+		assert.True(t, strings.HasSuffix(out.String(), "\r\x1b[K"), "%q", out.String())
+	})
+
+	t.Run("case=is cleared when the context is canceled", func(t *testing.T) {
+		var out syncBuffer
+		ctx, cancel := context.WithCancel(context.Background())
+		s := startSpinner(ctx, &out, "Creating project", 0)
+		require.Eventually(t, func() bool {
+			return strings.Contains(out.String(), "Creating project…")
+		}, time.Second, 10*time.Millisecond)
+		cancel()
+		<-s.done
+		assert.True(t, strings.HasSuffix(out.String(), "\r\x1b[K"), "%q", out.String())
+		s.Stop()
+	})
+
+	t.Run("case=only the outermost spinner is shown", func(t *testing.T) {
+		var out syncBuffer
+		h := &CommandHelper{Ctx: context.Background(), VerboseErrWriter: &out, spinner: true}
+		require.NoError(t, h.WithSpinner("Updating project", func(context.Context) error {
+			return h.WithSpinner("Fetching project", func(context.Context) error {
+				time.Sleep(spinnerDelay + 2*spinnerInterval)
+				return nil
+			})
+		}))
+		assert.Contains(t, out.String(), "Updating project…")
+		assert.NotContains(t, out.String(), "Fetching project")
+	})
+
+	t.Run("case=is disabled", func(t *testing.T) {
+		var out syncBuffer
+		h := &CommandHelper{Ctx: context.Background(), VerboseErrWriter: &out}
+		require.NoError(t, h.WithSpinner("Updating project", func(context.Context) error {
+			time.Sleep(spinnerDelay + spinnerInterval)
+			return nil
+		}))
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/x/cmdx"
+)
+
+// CanStreamOutput returns false if the output of cmd can only be printed once the whole collection was fetched,
+// because --sort or --columns are set. Commands then collect all pages and call PrintTable.
+func CanStreamOutput(cmd *cobra.Command) bool {
+	return SortKey(cmd) == "" && len(Columns(cmd)) == 0
+}
+
+// TableStream prints the pages of a collection as soon as they are fetched, so that the memory usage does not grow
+// with the size of the collection and pipes can process the output right away. JSON and YAML output is a single list
+// of all items, NDJSON output a line per item, and table output one table whose columns are aligned per page.
+//
+// Create a TableStream only if CanStreamOutput returns true.
+type TableStream struct {
+	cmd    *cobra.Command
+	out    io.Writer
+	format string
+	f      *timeFormatter
+	// header is the header of the last page, it is printed by Close if the collection is empty.
+	header []string
+	// printed is the number of items printed so far.
+	printed int
+}
+
+// NewTableStream returns a TableStream which prints to the output of cmd. Call Close after the last page.
+func NewTableStream(cmd *cobra.Command) *TableStream {
+	return &TableStream{cmd: cmd, out: cmd.OutOrStdout(), format: OutputFormat(cmd), f: newTimeFormatter(cmd, time.Now())}
+}
+
+// Print prints a page of the collection.
+func (s *TableStream) Print(page cmdx.Table) {
+	if w, ok := page.(WideTable); ok && (s.format == FormatTableWide || IsMachineReadableFormat(s.cmd)) {
+		page = &wideTable{w}
+	}
+	s.header = page.Header()
+	if page.Len() == 0 {
+		return
+	}
+
+	switch s.format {
+	case FormatNone:
+	case FormatJSON, FormatJSONPretty, FormatNDJSON:
+		for _, item := range sliceItems(page.Interface()) {
+			s.printJSON(item)
+		}
+	case FormatYAML:
+		// The YAML lists of the pages concatenate to one list.
+		s.printYAML(page.Interface())
+		s.printed += page.Len()
+	default:
+		w := tabwriter.NewWriter(s.out, 0, 8, 1, '\t', 0)
+		if s.printed == 0 {
+			_, _ = fmt.Fprintln(w, strings.Join(s.header, "\t")+"\t")
+		}
+		for _, row := range (&timeTable{table: page, f: s.f}).Table() {
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t")+"\t")
+		}
+		_ = w.Flush()
+		s.printed += page.Len()
+	}
+}
+
+// Close terminates the output. Empty collections are printed as an empty list or a table without rows.
+func (s *TableStream) Close() {
+	switch s.format {
+	case FormatNone, FormatNDJSON:
+	case FormatJSON, FormatJSONPretty:
+		switch {
+		case s.printed == 0:
+			_, _ = fmt.Fprintln(s.out, "[]")
+		case s.format == FormatJSONPretty:
+			_, _ = fmt.Fprintln(s.out, "\n]")
+		default:
+			_, _ = fmt.Fprintln(s.out, "]")
+		}
+	case FormatYAML:
+		if s.printed == 0 {
+			_, _ = fmt.Fprintln(s.out, "[]")
+		}
+	default:
+		if s.printed == 0 {
+			w := tabwriter.NewWriter(s.out, 0, 8, 1, '\t', 0)
+			_, _ = fmt.Fprintln(w, strings.Join(s.header, "\t")+"\t")
+			_ = w.Flush()
+		}
+	}
+}
+
+func (s *TableStream) printJSON(item interface{}) {
+	var out []byte
+	var err error
+	if s.format == FormatJSONPretty {
+		out, err = json.MarshalIndent(item, "  ", "  ")
+	} else {
+		out, err = json.Marshal(item)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(s.cmd.ErrOrStderr(), "Unable to encode the output: %s\n", err)
+		return
+	}
+
+	var prefix, suffix string
+	switch {
+	case s.format == FormatNDJSON:
+		suffix = "\n"
+	case s.format == FormatJSONPretty && s.printed == 0:
+		prefix = "[\n  "
+	case s.format == FormatJSONPretty:
+		prefix = ",\n  "
+	case s.printed == 0:
+		prefix = "["
+	default:
+		prefix = ","
+	}
+	_, _ = fmt.Fprint(s.out, prefix+string(out)+suffix)
+	s.printed++
+}
+
+func (s *TableStream) printYAML(v interface{}) {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		_, _ = fmt.Fprintf(s.cmd.ErrOrStderr(), "Unable to encode the output: %s\n", err)
+		return
+	}
+	_, _ = s.out.Write(out)
+}
+
+// sliceItems returns the items of v if it is a slice, or v itself otherwise.
+func sliceItems(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []interface{}{v}
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
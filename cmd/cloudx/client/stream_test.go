@@ -0,0 +1,75 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableStream(t *testing.T) {
+	pages := []testIdentities{
+		{{"id": "a", "traits": map[string]interface{}{"email": "a@example.com"}}},
+		{},
+		{
+			{"id": "b", "traits": map[string]interface{}{"email": "b@example.com"}},
+			{"id": "c", "traits": map[string]interface{}{"email": "c@example.com"}},
+		},
+	}
+
+	for format, expected := range map[string]string{
+		FormatJSON:       `[{"id":"a","traits":{"email":"a@example.com"}},{"id":"b","traits":{"email":"b@example.com"}},{"id":"c","traits":{"email":"c@example.com"}}]` + "\n",
+		FormatJSONPretty: "[\n  {\n    \"id\": \"a\",\n    \"traits\": {\n      \"email\": \"a@example.com\"\n    }\n  },\n  {\n    \"id\": \"b\",\n    \"traits\": {\n      \"email\": \"b@example.com\"\n    }\n  },\n  {\n    \"id\": \"c\",\n    \"traits\": {\n      \"email\": \"c@example.com\"\n    }\n  }\n]\n",
+		FormatNDJSON:     `{"id":"a","traits":{"email":"a@example.com"}}` + "\n" + `{"id":"b","traits":{"email":"b@example.com"}}` + "\n" + `{"id":"c","traits":{"email":"c@example.com"}}` + "\n",
+		FormatYAML:       "- id: a\n  traits:\n    email: a@example.com\n- id: b\n  traits:\n    email: b@example.com\n- id: c\n  traits:\n    email: c@example.com\n",
+		FormatNone:       "",
+	} {
+		t.Run("format="+format, func(t *testing.T) {
+			cmd, out := newOutputCmd(t, format)
+			s := NewTableStream(cmd)
+			for _, page := range pages {
+				s.Print(page)
+			}
+			s.Close()
+			assert.Equal(t, expected, out.String())
+		})
+	}
+
+	t.Run("format=table", func(t *testing.T) {
+		cmd, out := newOutputCmd(t, FormatTable)
+		s := NewTableStream(cmd)
+		s.Print(pages[0])
+		// The first page is printed before the next one arrives.
+		assert.Equal(t, "ID\tVERIFIED ADDRESS\t\na\ta@example.com\t\t\n", out.String())
+		s.Print(pages[1])
+		s.Print(pages[2])
+		s.Close()
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Len(t, lines, 4, "the header is printed once")
+		assert.Equal(t, "c\tc@example.com", strings.TrimSpace(lines[3]))
+	})
+
+	for format, expected := range map[string]string{
+		FormatJSON:   "[]\n",
+		FormatNDJSON: "",
+		FormatYAML:   "[]\n",
+		FormatTable:  "ID\tVERIFIED ADDRESS\t\n",
+	} {
+		t.Run("case=empty collection/format="+format, func(t *testing.T) {
+			cmd, out := newOutputCmd(t, format)
+			s := NewTableStream(cmd)
+			s.Print(testIdentities{})
+			s.Close()
+			assert.Equal(t, expected, out.String())
+		})
+	}
+}
+
+func TestCanStreamOutput(t *testing.T) {
+	cmd, _ := newOutputCmd(t, FormatJSON)
+	RegisterSortFlags(cmd.Flags())
+	assert.True(t, CanStreamOutput(cmd))
+	assert.NoError(t, cmd.Flags().Set(SortFlag, "id"))
+	assert.False(t, CanStreamOutput(cmd))
+}
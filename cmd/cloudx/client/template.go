@@ -0,0 +1,153 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	templatePrefix     = "template="
+	templateFilePrefix = "template-file="
+)
+
+// parseOutputTemplate parses the template of --format template=TEMPLATE or reads and parses the file of
+// --format template-file=FILE. It is called while parsing the flags, so that invalid templates are reported before any
+// API call happens.
+func parseOutputTemplate(format string) (*template.Template, error) {
+	text := strings.TrimPrefix(format, templatePrefix)
+	if file := strings.TrimPrefix(format, templateFilePrefix); file != format {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read the output template file %s", file)
+		}
+		text = strings.TrimSuffix(string(contents), "\n")
+	}
+
+	t, err := template.New("output").Funcs(sprig.TxtFuncMap()).Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse the output template")
+	}
+	return t, nil
+}
+
+// templateFuncs complement the sprig functions with helpers for aligning columns and formatting the timestamps of
+// API responses.
+var templateFuncs = template.FuncMap{
+	"padRight": func(width int, v interface{}) string {
+		s := fmt.Sprint(v)
+		return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+	},
+	"padLeft": func(width int, v interface{}) string {
+		s := fmt.Sprint(v)
+		return strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s))) + s
+	},
+	// formatTime formats RFC 3339 timestamps such as "created_at" using a Go time layout.
+	"formatTime": func(layout string, v interface{}) (string, error) {
+		t, err := time.Parse(time.RFC3339Nano, fmt.Sprint(v))
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		return t.Format(layout), nil
+	},
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// outputTemplate returns the template of the --format flag or nil if the format is not a template.
+func outputTemplate(cmd *cobra.Command) *template.Template {
+	f := cmd.Flags().Lookup(cmdx.FlagFormat)
+	if f == nil {
+		return nil
+	}
+	if v, ok := f.Value.(*formatValue); ok {
+		return v.template
+	}
+	return nil
+}
+
+// EnableTemplateFormat applies --format template=TEMPLATE to the output of cmd and all its sub commands. The command
+// is run with JSON output, and the template is executed once per item of lists and once for everything else.
+func EnableTemplateFormat(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableTemplateFormat(c)
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		t := outputTemplate(cmd)
+		if t == nil {
+			return run(cmd, args)
+		}
+		if err := cmd.Flags().Lookup(cmdx.FlagFormat).Value.Set(FormatJSON); err != nil {
+			return errors.WithStack(err)
+		}
+
+		out := cmd.OutOrStdout()
+		var captured bytes.Buffer
+		cmd.SetOut(&captured)
+		err := run(cmd, args)
+		cmd.SetOut(out)
+		if err != nil {
+			return err
+		}
+
+		return printTemplate(out, cmd.ErrOrStderr(), &captured, t)
+	}
+}
+
+// printTemplate executes the template for every JSON document read from r. Arrays are executed per item. If the
+// template fails for an item, the error is printed and the remaining items are printed nonetheless.
+func printTemplate(out, stderr io.Writer, r io.Reader, t *template.Template) error {
+	var items []interface{}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	for {
+		var doc interface{}
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "unable to execute the output template because the output is not JSON")
+		}
+		if list, ok := doc.([]interface{}); ok {
+			items = append(items, list...)
+		} else {
+			items = append(items, doc)
+		}
+	}
+
+	var failed int
+	for k, item := range items {
+		var b bytes.Buffer
+		if err := t.Execute(&b, item); err != nil {
+			failed++
+			_, _ = fmt.Fprintf(stderr, "Unable to execute the output template for item %d: %s\n", k+1, err)
+			continue
+		}
+		_, _ = fmt.Fprintln(out, b.String())
+	}
+	if failed > 0 {
+		return errors.Errorf("the output template failed for %d of %d items", failed, len(items))
+	}
+	return nil
+}
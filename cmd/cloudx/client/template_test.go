@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableTemplateFormat(t *testing.T) {
+	var called bool
+	run := func(t *testing.T, output string, args ...string) (string, string, error) {
+		called = false
+		cmd := &cobra.Command{Use: "identities", SilenceUsage: true, RunE: func(cmd *cobra.Command, _ []string) error {
+			called = true
+			assert.Equal(t, FormatJSON, OutputFormat(cmd))
+			_, _ = cmd.OutOrStdout().Write([]byte(output))
+			return nil
+		}}
+		RegisterFormatFlag(cmd.Flags())
+		EnableTemplateFormat(cmd)
+
+		var out, stderr bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), stderr.String(), err
+	}
+	const identities = `[{"id":"a","traits":{"email":"a@example.com"},"created_at":"2022-07-01T10:12:13.123Z"},{"id":"b","traits":{}}]`
+
+	t.Run("case=executes the template per item of lists", func(t *testing.T) {
+		out, _, err := run(t, identities, "--format", "template={{.id}} {{.traits.email}}")
+		require.NoError(t, err)
+		assert.Equal(t, "a a@example.com\nb <no value>\n", out)
+	})
+
+	t.Run("case=executes the template once for objects", func(t *testing.T) {
+		out, _, err := run(t, `{"id":"a","name":"Production"}`, "--format", `template={{.name | upper}} ({{.id}})`)
+		require.NoError(t, err)
+		assert.Equal(t, "PRODUCTION (a)\n", out)
+	})
+
+	t.Run("case=supports helper functions", func(t *testing.T) {
+		out, _, err := run(t, identities[:len(identities)-len(`,{"id":"b","traits":{}}]`)]+"]",
+			"--format", `template={{padRight 4 .id}}|{{padLeft 4 .id}}|{{formatTime "2006-01-02" .created_at}}`)
+		require.NoError(t, err)
+		assert.Equal(t, "a   |   a|2022-07-01\n", out)
+	})
+
+	t.Run("case=continues after failing items", func(t *testing.T) {
+		out, stderr, err := run(t, identities, "--format", `template={{.id}} {{formatTime "2006" .created_at}}`)
+		assert.ErrorContains(t, err, "the output template failed for 1 of 2 items")
+		assert.Equal(t, "a 2022\n", out)
+		assert.Contains(t, stderr, "Unable to execute the output template for item 2:")
+	})
+
+	t.Run("case=reads templates from files", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "report.tmpl")
+		require.NoError(t, os.WriteFile(file, []byte("{{- .id -}}\n"), 0600))
+		out, _, err := run(t, identities, "--format", "template-file="+file)
+		require.NoError(t, err)
+		assert.Equal(t, "a\nb\n", out)
+	})
+
+	t.Run("case=reports parse errors before running the command", func(t *testing.T) {
+		_, _, err := run(t, identities, "--format", "template={{.id")
+		assert.ErrorContains(t, err, "unable to parse the output template")
+		assert.False(t, called)
+
+		_, _, err = run(t, identities, "--format", "template-file=does-not-exist.tmpl")
+		assert.ErrorContains(t, err, "unable to read the output template file does-not-exist.tmpl")
+		assert.False(t, called)
+	})
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// throttleRampUpAfter is the number of successful requests after which a throttled pipeline runs one more request
+// concurrently again.
+const throttleRampUpAfter = 20
+
+// Throttle adapts bulk operations to the rate limit of the API. Workers call Acquire before and Release after every
+// request. If the API answers with 429 Too Many Requests, all workers pause for the duration of the Retry-After
+// header. Repeated 429 responses halve the number of concurrent requests, which slowly ramps up again while requests
+// succeed.
+type Throttle struct {
+	log *Logger
+	now func() time.Time
+
+	// defaultPause is the pause after the first 429 response without a Retry-After header. It doubles for every
+	// following 429 response up to maxPause.
+	defaultPause, maxPause time.Duration
+
+	mu sync.Mutex
+	// changed is closed and replaced whenever a slot frees up or the limit changes.
+	changed                 chan struct{}
+	limit, maxLimit, active int
+	pausedUntil             time.Time
+	consecutive, successes  int
+	events                  int
+}
+
+// NewThrottle returns a Throttle for a pipeline which runs up to concurrency requests at the same time.
+func (h *CommandHelper) NewThrottle(concurrency int) *Throttle {
+	return newThrottle(h.Log, concurrency, time.Now)
+}
+
+func newThrottle(log *Logger, concurrency int, now func() time.Time) *Throttle {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Throttle{
+		log:          log,
+		now:          now,
+		defaultPause: time.Second,
+		maxPause:     30 * time.Second,
+		changed:      make(chan struct{}),
+		limit:        concurrency,
+		maxLimit:     concurrency,
+	}
+}
+
+// Acquire blocks until the pipeline is not paused and fewer than the current limit of requests are running, or the
+// context is done.
+func (t *Throttle) Acquire(ctx context.Context) error {
+	for {
+		t.mu.Lock()
+		wait := t.pausedUntil.Sub(t.now())
+		if wait <= 0 && t.active < t.limit {
+			t.active++
+			t.mu.Unlock()
+			return nil
+		}
+		changed := t.changed
+		t.mu.Unlock()
+
+		var timer <-chan time.Time
+		if wait > 0 {
+			timer = time.After(wait)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-changed:
+		case <-timer:
+		}
+	}
+}
+
+// Release records the response of a request started after Acquire. It returns true if the API throttled the request,
+// which should then be retried without counting against the retries for failed requests. The response may be nil if
+// the request failed without a response.
+func (t *Throttle) Release(res *http.Response) (throttled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.notify()
+
+	t.active--
+	if res == nil || res.StatusCode != http.StatusTooManyRequests {
+		t.consecutive = 0
+		if t.successes++; t.limit < t.maxLimit && t.successes >= throttleRampUpAfter {
+			t.limit++
+			t.successes = 0
+			t.log.Debugf("Increasing the concurrency to %d requests", t.limit)
+		}
+		return false
+	}
+
+	t.events++
+	t.consecutive++
+	t.successes = 0
+
+	now := t.now()
+	pause, ok := retryAfter(res.Header, now)
+	if !ok {
+		pause = t.defaultPause << (t.consecutive - 1)
+		if pause > t.maxPause || pause <= 0 {
+			pause = t.maxPause
+		}
+	}
+	if until := now.Add(pause); until.After(t.pausedUntil) {
+		t.pausedUntil = until
+		t.log.Debugf("The API rate limited %s %s, pausing all requests for %s", res.Request.Method, res.Request.URL.Path, pause)
+	}
+	if t.consecutive > 1 && t.limit > 1 {
+		t.limit /= 2
+		t.log.Debugf("Reducing the concurrency to %d requests", t.limit)
+	}
+	return true
+}
+
+// Events returns the number of 429 responses the pipeline received.
+func (t *Throttle) Events() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.events
+}
+
+func (t *Throttle) notify() {
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// retryAfter parses the Retry-After header which contains either the number of seconds to wait or an HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(v); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// IsThrottled returns true if err is the error of a request the API rejected with 429 Too Many Requests.
+func IsThrottled(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func throttledResponse(retryAfter string) *http.Response {
+	res := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Request: httptest.NewRequest(http.MethodPost, "/admin/identities", nil)}
+	if retryAfter != "" {
+		res.Header.Set("Retry-After", retryAfter)
+	}
+	return res
+}
+
+func TestThrottle(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	ok := &http.Response{StatusCode: http.StatusOK}
+
+	t.Run("case=pauses for the duration of Retry-After", func(t *testing.T) {
+		th := newThrottle(nil, 4, clock)
+		require.NoError(t, th.Acquire(context.Background()))
+		assert.True(t, th.Release(throttledResponse("5")))
+		assert.Equal(t, now.Add(5*time.Second), th.pausedUntil)
+		assert.Equal(t, 4, th.limit, "a single 429 does not reduce the concurrency")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, th.Acquire(ctx), context.DeadlineExceeded, "workers wait while the throttle is paused")
+	})
+
+	t.Run("case=backs off exponentially without Retry-After", func(t *testing.T) {
+		th := newThrottle(nil, 1, clock)
+		for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+			th.active++
+			th.Release(throttledResponse(""))
+			assert.Equal(t, now.Add(expected), th.pausedUntil)
+		}
+	})
+
+	t.Run("case=reduces the concurrency on repeated 429s and ramps up again", func(t *testing.T) {
+		th := newThrottle(nil, 8, clock)
+		for i := 0; i < 3; i++ {
+			th.active++
+			th.Release(throttledResponse("0"))
+		}
+		assert.Equal(t, 2, th.limit)
+		assert.Equal(t, 3, th.Events())
+
+		for i := 0; i < throttleRampUpAfter; i++ {
+			th.active++
+			assert.False(t, th.Release(ok))
+		}
+		assert.Equal(t, 3, th.limit)
+		for i := 0; i < 10*throttleRampUpAfter; i++ {
+			th.active++
+			th.Release(nil)
+		}
+		assert.Equal(t, 8, th.limit, "the concurrency never exceeds the initial value")
+	})
+
+	t.Run("case=limits the concurrent requests", func(t *testing.T) {
+		th := newThrottle(nil, 3, time.Now)
+		var running, peak int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, th.Acquire(context.Background()))
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				th.Release(ok)
+			}()
+		}
+		wg.Wait()
+		assert.EqualValues(t, 3, peak)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{header: ""},
+		{header: "soon"},
+		{header: "-1"},
+		{header: "0", ok: true},
+		{header: "120", expected: 2 * time.Minute, ok: true},
+		{header: "Wed, 01 Jun 2022 12:00:30 GMT", expected: 30 * time.Second, ok: true},
+		{header: "Wed, 01 Jun 2022 11:00:00 GMT", ok: true},
+	} {
+		d, ok := retryAfter(http.Header{"Retry-After": {tc.header}}, now)
+		assert.Equal(t, tc.ok, ok, tc.header)
+		assert.Equal(t, tc.expected, d, tc.header)
+	}
+}
+
+func TestIsThrottled(t *testing.T) {
+	assert.True(t, IsThrottled(errors.WithStack(&APIError{StatusCode: http.StatusTooManyRequests})))
+	assert.False(t, IsThrottled(errors.WithStack(&APIError{StatusCode: http.StatusServiceUnavailable})))
+	assert.False(t, IsThrottled(errors.New("connection refused")))
+}
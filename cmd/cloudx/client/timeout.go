@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	TimeoutFlag = "timeout"
+
+	defaultRequestTimeout = 30 * time.Second
+	// slowRequestTimeout is the default timeout of requests which are known to take long, e.g. creating a project.
+	slowRequestTimeout = 5 * time.Minute
+)
+
+// RegisterTimeoutFlag registers the flag which limits the duration of every API request.
+func RegisterTimeoutFlag(f *pflag.FlagSet) {
+	f.Duration(TimeoutFlag, defaultRequestTimeout, "Abort API requests which take longer than this, e.g. 10s or 2m. Use 0 to wait indefinitely.")
+}
+
+// RequestTimeout returns the timeout of API requests as configured by the flags of cmd. It also returns whether the
+// timeout was set explicitly, in which case it applies to slow requests as well.
+func RequestTimeout(cmd *cobra.Command) (time.Duration, bool, error) {
+	f := cmd.Flags().Lookup(TimeoutFlag)
+	if f == nil {
+		return defaultRequestTimeout, false, nil
+	}
+	timeout, err := cmd.Flags().GetDuration(TimeoutFlag)
+	if err != nil {
+		return 0, false, errors.WithStack(err)
+	}
+	if timeout < 0 {
+		return 0, false, NewValidationError(errors.Errorf("--%s must not be negative", TimeoutFlag), nil)
+	}
+	return timeout, f.Changed, nil
+}
+
+// WithSlowRequestTimeout returns a context for requests which are known to take long, e.g. creating a project. They
+// time out after a few minutes, unless the --timeout flag is set explicitly.
+func (h *CommandHelper) WithSlowRequestTimeout(ctx context.Context) context.Context {
+	if h.timeoutSet {
+		return ctx
+	}
+	return contextWithTimeout(ctx, slowRequestTimeout)
+}
+
+type timeoutKey struct{}
+
+// contextWithTimeout makes all Ory Cloud API clients abort requests which take longer than timeout.
+func contextWithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+// TimeoutError is returned if an API request timed out. It is a net.Error so that the CLI exits with ExitNetwork.
+type TimeoutError struct {
+	Host string
+	// After is the timeout which was exceeded.
+	After time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("request to %s timed out after %s; pass --%s to increase", e.Host, e.After, TimeoutFlag)
+}
+
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+func (e *TimeoutError) Temporary() bool {
+	return true
+}
+
+// withTimeoutError replaces errors caused by a timed out request with the TimeoutError, whose message explains how to
+// increase the timeout. Other errors are returned as is.
+func withTimeoutError(err error) error {
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return errors.WithStack(timeoutErr)
+	}
+	return err
+}
+
+// timeoutTransport aborts requests which take longer than the timeout from the request context, or timeout if the
+// context does not set it. The timeout covers reading the response body. Canceling the request context, e.g. by
+// pressing Ctrl-C, still aborts the request immediately and is not reported as timeout.
+type timeoutTransport struct {
+	http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout, ok := req.Context().Value(timeoutKey{}).(time.Duration)
+	if !ok {
+		timeout = t.timeout
+	}
+	if timeout <= 0 {
+		return t.RoundTripper.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	timedOut := func(err error) error {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && req.Context().Err() == nil {
+			return &TimeoutError{Host: req.URL.Host, After: timeout}
+		}
+		return err
+	}
+
+	res, err := t.RoundTripper.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, timedOut(err)
+	}
+	res.Body = &timeoutBody{ReadCloser: res.Body, cancel: cancel, timedOut: timedOut}
+	return res, nil
+}
+
+// timeoutBody releases the timer of a request once its body is closed.
+type timeoutBody struct {
+	io.ReadCloser
+	cancel   context.CancelFunc
+	timedOut func(error) error
+}
+
+func (b *timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = b.timedOut(err)
+	}
+	return n, err
+}
+
+func (b *timeoutBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutTransport(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-body" {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { close(release) })
+	host, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	c := &http.Client{Transport: &timeoutTransport{RoundTripper: http.DefaultTransport, timeout: 20 * time.Millisecond}}
+
+	t.Run("case=times out waiting for the response", func(t *testing.T) {
+		_, err := c.Get(ts.URL)
+		require.Error(t, err)
+
+		var timeoutErr *TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, "request to "+host.Host+" timed out after 20ms; pass --timeout to increase", withTimeoutError(err).Error())
+		assert.Equal(t, ExitNetwork, ExitCode(err))
+	})
+
+	t.Run("case=times out reading the body", func(t *testing.T) {
+		res, err := c.Get(ts.URL + "/slow-body")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		_, err = io.ReadAll(res.Body)
+		var timeoutErr *TimeoutError
+		assert.ErrorAs(t, err, &timeoutErr)
+	})
+
+	t.Run("case=the context sets the timeout", func(t *testing.T) {
+		req, err := http.NewRequestWithContext(contextWithTimeout(context.Background(), 5*time.Millisecond), http.MethodGet, ts.URL, nil)
+		require.NoError(t, err)
+		_, err = c.Do(req)
+		var timeoutErr *TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, 5*time.Millisecond, timeoutErr.After)
+	})
+
+	t.Run("case=canceling the context is not a timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(5*time.Millisecond, cancel)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+		require.NoError(t, err)
+
+		_, err = c.Do(req)
+		assert.ErrorIs(t, err, context.Canceled)
+		var timeoutErr *TimeoutError
+		assert.False(t, errors.As(err, &timeoutErr))
+	})
+}
+
+func TestRequestTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		args     []string
+		expected time.Duration
+		set      bool
+	}{
+		{expected: defaultRequestTimeout},
+		{args: []string{"--timeout", "2m"}, expected: 2 * time.Minute, set: true},
+		{args: []string{"--timeout", "0"}, set: true},
+	} {
+		cmd := &cobra.Command{}
+		RegisterTimeoutFlag(cmd.Flags())
+		require.NoError(t, cmd.ParseFlags(tc.args))
+		timeout, set, err := RequestTimeout(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, timeout, "%v", tc.args)
+		assert.Equal(t, tc.set, set, "%v", tc.args)
+
+		ctx := (&CommandHelper{timeoutSet: set}).WithSlowRequestTimeout(context.Background())
+		slow, ok := ctx.Value(timeoutKey{}).(time.Duration)
+		assert.Equal(t, !set, ok && slow == slowRequestTimeout, "slow requests use the explicit timeout")
+	}
+
+	cmd := &cobra.Command{}
+	RegisterTimeoutFlag(cmd.Flags())
+	require.NoError(t, cmd.ParseFlags([]string{"--timeout", "-1s"}))
+	_, _, err := RequestTimeout(cmd)
+	assert.Equal(t, ExitValidation, ExitCode(err))
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/x/cmdx"
+)
+
+const (
+	UTCFlag = "utc"
+
+	// relativeTimeRange is the distance from now up to which timestamps are printed relative to now in tables.
+	relativeTimeRange = 7 * 24 * time.Hour
+)
+
+// RegisterUTCFlag registers the flag which prints timestamps in UTC instead of the local timezone.
+func RegisterUTCFlag(f *pflag.FlagSet) {
+	f.Bool(UTCFlag, false, "Print timestamps in UTC instead of the local timezone in human readable output. Machine readable formats always use UTC.")
+}
+
+// timeFormatter renders the RFC3339 timestamps in the cells of human readable tables. Tables always contain timestamps
+// as RFC3339 in UTC, which are rendered in the local timezone, or relative to now if they are close to now.
+type timeFormatter struct {
+	loc      *time.Location
+	now      time.Time
+	relative bool
+}
+
+// newTimeFormatter returns the timeFormatter for the tables printed by cmd. Recent timestamps are printed relative
+// to now, except in the table-wide format which shows all timestamps as absolute values.
+func newTimeFormatter(cmd *cobra.Command, now time.Time) *timeFormatter {
+	f := &timeFormatter{loc: time.Local, now: now, relative: OutputFormat(cmd) != FormatTableWide}
+	if utc, _ := cmd.Flags().GetBool(UTCFlag); utc {
+		f.loc = time.UTC
+	}
+	return f
+}
+
+func (f *timeFormatter) cell(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	if d := t.Sub(f.now); f.relative && d < relativeTimeRange && d > -relativeTimeRange {
+		return relativeTime(d)
+	}
+	return t.In(f.loc).Format(time.RFC3339)
+}
+
+func (f *timeFormatter) cells(row []string) []string {
+	formatted := make([]string, len(row))
+	for i, s := range row {
+		formatted[i] = f.cell(s)
+	}
+	return formatted
+}
+
+// relativeTime renders the distance d to now in the largest unit, e.g. "3h ago" or "in 2d".
+func relativeTime(d time.Duration) string {
+	future := d > 0
+	if !future {
+		d = -d
+	}
+
+	var s string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		s = fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		s = fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		s = fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	if future {
+		return "in " + s
+	}
+	return s + " ago"
+}
+
+// timeTable renders the timestamps of a table using the timeFormatter.
+type timeTable struct {
+	table cmdx.Table
+	f     *timeFormatter
+}
+
+func (t *timeTable) Header() []string {
+	return t.table.Header()
+}
+
+func (t *timeTable) Interface() interface{} {
+	return t.table.Interface()
+}
+
+func (t *timeTable) Len() int {
+	return t.table.Len()
+}
+
+func (t *timeTable) Table() [][]string {
+	rows := t.table.Table()
+	formatted := make([][]string, len(rows))
+	for i, row := range rows {
+		formatted[i] = t.f.cells(row)
+	}
+	return formatted
+}
+
+// timeRow renders the timestamps of a row using the timeFormatter.
+type timeRow struct {
+	row cmdx.TableRow
+	f   *timeFormatter
+}
+
+func (r *timeRow) Header() []string {
+	return r.row.Header()
+}
+
+func (r *timeRow) Interface() interface{} {
+	return r.row.Interface()
+}
+
+func (r *timeRow) Columns() []string {
+	return r.f.cells(r.row.Columns())
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTimestamps [][]string
+
+func (testTimestamps) Header() []string         { return []string{"ID", "CREATED AT"} }
+func (t testTimestamps) Table() [][]string      { return t }
+func (t testTimestamps) Interface() interface{} { return t }
+func (t testTimestamps) Len() int               { return len(t) }
+
+func TestTimeFormatter(t *testing.T) {
+	now := time.Date(2022, 6, 10, 12, 0, 0, 0, time.UTC)
+	berlin := time.FixedZone("CEST", 2*60*60)
+
+	for _, tc := range []struct {
+		cell, relative, absolute string
+	}{
+		{cell: "2022-06-10T11:59:30Z", relative: "just now", absolute: "2022-06-10T13:59:30+02:00"},
+		{cell: "2022-06-10T11:15:00Z", relative: "45m ago", absolute: "2022-06-10T13:15:00+02:00"},
+		{cell: "2022-06-10T09:00:00Z", relative: "3h ago", absolute: "2022-06-10T11:00:00+02:00"},
+		{cell: "2022-06-12T12:00:00Z", relative: "in 2d", absolute: "2022-06-12T14:00:00+02:00"},
+		{cell: "2022-06-10T12:30:00+02:00", relative: "1h ago", absolute: "2022-06-10T12:30:00+02:00"},
+		{cell: "2022-05-01T12:00:00Z", relative: "2022-05-01T14:00:00+02:00", absolute: "2022-05-01T14:00:00+02:00"},
+		{cell: "2023-06-10T12:00:00Z", relative: "2023-06-10T14:00:00+02:00", absolute: "2023-06-10T14:00:00+02:00"},
+		{cell: "jane@example.com", relative: "jane@example.com", absolute: "jane@example.com"},
+		{cell: "2022-06-10", relative: "2022-06-10", absolute: "2022-06-10"},
+	} {
+		t.Run("cell="+tc.cell, func(t *testing.T) {
+			assert.Equal(t, tc.relative, (&timeFormatter{loc: berlin, now: now, relative: true}).cell(tc.cell))
+			assert.Equal(t, tc.absolute, (&timeFormatter{loc: berlin, now: now}).cell(tc.cell))
+		})
+	}
+}
+
+func TestNewTimeFormatter(t *testing.T) {
+	now := time.Date(2022, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	cmd, _ := newOutputCmd(t, FormatTable)
+	RegisterUTCFlag(cmd.Flags())
+	f := newTimeFormatter(cmd, now)
+	assert.True(t, f.relative)
+	assert.Equal(t, time.Local, f.loc)
+
+	require.NoError(t, cmd.Flags().Set(UTCFlag, "true"))
+	require.NoError(t, cmd.Flags().Set("format", FormatTableWide))
+	f = newTimeFormatter(cmd, now)
+	assert.False(t, f.relative)
+	assert.Equal(t, "2022-05-01T12:00:00Z", f.cell("2022-05-01T12:00:00Z"))
+	assert.Equal(t, "2022-06-10T09:00:00Z", f.cell("2022-06-10T11:00:00+02:00"))
+}
+
+func TestPrintTableTimestamps(t *testing.T) {
+	table := testTimestamps{{"a", "2022-05-01T12:00:00Z"}}
+
+	cmd, out := newOutputCmd(t, FormatJSON)
+	PrintTable(cmd, table)
+	assert.JSONEq(t, `[["a", "2022-05-01T12:00:00Z"]]`, out.String(), "machine readable formats are not changed")
+
+	cmd, out = newOutputCmd(t, FormatTable)
+	RegisterUTCFlag(cmd.Flags())
+	require.NoError(t, cmd.Flags().Set(UTCFlag, "true"))
+	PrintTable(cmd, table)
+	assert.Contains(t, out.String(), "2022-05-01T12:00:00Z")
+}
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const TimingsFlag = "timings"
+
+// RegisterTimingsFlag registers the flag which prints the summary of the request timings.
+func RegisterTimingsFlag(f *pflag.FlagSet) {
+	f.Bool(TimingsFlag, false, "Print how many API requests the command sent and how long they took. The timings of every request are logged at the debug level.")
+}
+
+// EnableTimings logs the summary of the request timings after cmd and all its sub commands ran. The summary is logged
+// at the info level if the --timings flag is set, and at the debug level otherwise.
+func EnableTimings(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		EnableTimings(c)
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if ctx == nil {
+			return run(cmd, args)
+		}
+		timings, ok := ctx.Value(timingLogKey{}).(*timingLog)
+		if !ok {
+			return run(cmd, args)
+		}
+
+		timings.reset(time.Now())
+		err := run(cmd, args)
+
+		l, logErr := NewLogger(cmd)
+		summary, ok := timings.summary(time.Now())
+		if logErr != nil || !ok {
+			return err
+		}
+		if printTimings, _ := cmd.Flags().GetBool(TimingsFlag); printTimings {
+			l.Infof("%s", summary)
+		} else {
+			l.Debugf("%s", summary)
+		}
+		return err
+	}
+}
+
+type timingLogKey struct{}
+
+// contextWithTimingLog makes all Ory Cloud API clients record the timings of their requests for the summary of
+// EnableTimings.
+func contextWithTimingLog(ctx context.Context) context.Context {
+	return context.WithValue(ctx, timingLogKey{}, new(timingLog))
+}
+
+// timingLog collects the timings of the requests of a command.
+type timingLog struct {
+	mu       sync.Mutex
+	start    time.Time
+	requests int
+	total    time.Duration
+	slowest  *requestTiming
+}
+
+func (l *timingLog) reset(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.start, l.requests, l.total, l.slowest = now, 0, 0, nil
+}
+
+func (l *timingLog) record(t *requestTiming) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests++
+	l.total += t.total
+	if l.slowest == nil || t.total > l.slowest.total {
+		l.slowest = t
+	}
+}
+
+// summary describes the requests sent since the last reset, or returns false if there were none. The time spent in
+// requests may exceed the run time of the command if requests were sent concurrently.
+func (l *timingLog) summary(now time.Time) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.requests == 0 {
+		return "", false
+	}
+
+	requests := "1 API request"
+	if l.requests > 1 {
+		requests = fmt.Sprintf("%d API requests", l.requests)
+	}
+	return fmt.Sprintf("Timings: %s took %s, the command ran for %s. The slowest request was %s %s (%s).",
+		requests, roundDuration(l.total), roundDuration(now.Sub(l.start)), l.slowest.method, l.slowest.path, roundDuration(l.slowest.total)), true
+}
+
+// requestTiming is the breakdown of the time a request took. Phases which did not happen, e.g. the DNS lookup and
+// the TLS handshake of a reused connection, are zero.
+type requestTiming struct {
+	method, path string
+	status       string
+
+	mu                      sync.Mutex
+	dns, connect, tls, ttfb time.Duration
+	total                   time.Duration
+	reused                  bool
+	dnsStart, connectStart  time.Time
+	tlsStart                time.Time
+}
+
+func (t *requestTiming) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var phases []string
+	if t.reused {
+		phases = append(phases, "reused connection")
+	}
+	for _, p := range []struct {
+		name string
+		d    time.Duration
+	}{{"DNS", t.dns}, {"connect", t.connect}, {"TLS", t.tls}, {"TTFB", t.ttfb}} {
+		if p.d > 0 {
+			phases = append(phases, fmt.Sprintf("%s %s", p.name, roundDuration(p.d)))
+		}
+	}
+	phases = append(phases, fmt.Sprintf("total %s", roundDuration(t.total)))
+	return fmt.Sprintf("%s %s: %s (%s)", t.method, t.path, t.status, strings.Join(phases, ", "))
+}
+
+// trace returns the hooks which record the phases of the request. The hooks may be called concurrently, e.g. while
+// dialing several addresses.
+func (t *requestTiming) trace(start time.Time, now func() time.Time) *httptrace.ClientTrace {
+	set := func(f func()) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		f()
+	}
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { set(func() { t.dnsStart = now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { set(func() { t.dns = now().Sub(t.dnsStart) }) },
+		ConnectStart: func(string, string) {
+			set(func() {
+				if t.connectStart.IsZero() {
+					t.connectStart = now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			set(func() {
+				if err == nil && t.connect == 0 {
+					t.connect = now().Sub(t.connectStart)
+				}
+			})
+		},
+		TLSHandshakeStart:    func() { set(func() { t.tlsStart = now() }) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(func() { t.tls = now().Sub(t.tlsStart) }) },
+		GotConn:              func(info httptrace.GotConnInfo) { set(func() { t.reused = info.Reused }) },
+		GotFirstResponseByte: func() { set(func() { t.ttfb = now().Sub(start) }) },
+	}
+}
+
+// timingTransport measures how long the phases of every request take, from the DNS lookup to reading the last byte of
+// the response body. The timings are logged at the debug level and recorded for the summary of EnableTimings.
+type timingTransport struct {
+	http.RoundTripper
+	now func() time.Time
+}
+
+func (t *timingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	now := t.now
+	if now == nil {
+		now = time.Now
+	}
+
+	timing := &requestTiming{method: req.Method, path: req.URL.Path}
+	start := now()
+	ctx := req.Context()
+	res, err := t.RoundTripper.RoundTrip(req.WithContext(httptrace.WithClientTrace(ctx, timing.trace(start, now))))
+	finish := func() {
+		timing.mu.Lock()
+		timing.total = now().Sub(start)
+		timing.mu.Unlock()
+
+		loggerFromContext(ctx).Debugf("%s", timing)
+		if l, ok := ctx.Value(timingLogKey{}).(*timingLog); ok {
+			l.record(timing)
+		}
+	}
+	if err != nil {
+		timing.status = "failed"
+		finish()
+		return res, err
+	}
+
+	timing.status = res.Status
+	res.Body = &timedBody{ReadCloser: res.Body, done: finish}
+	return res, nil
+}
+
+// timedBody calls done once the body was read completely or closed.
+type timedBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.done)
+	}
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	b.once.Do(b.done)
+	return b.ReadCloser.Close()
+}
+
+// roundDuration rounds d to a precision which is readable in logs, e.g. 312ms or 2.4s.
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= 10*time.Second:
+		return d.Round(time.Second)
+	case d >= time.Second:
+		return d.Round(100 * time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(time.Microsecond)
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimingTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"id":"ecaaa3cb-0730-4ee8-a6df-9553cdfeef89"}`)
+	}))
+	t.Cleanup(ts.Close)
+
+	var log bytes.Buffer
+	ctx := contextWithTimingLog(contextWithLogger(context.Background(), &Logger{out: &log, level: LogLevelDebug}))
+	timings := ctx.Value(timingLogKey{}).(*timingLog)
+	timings.reset(time.Now())
+
+	c := newClient(http.DefaultTransport, clientConfig{}).httpClient("")
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/projects", nil)
+		require.NoError(t, err)
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		_, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+
+	assert.Regexp(t, `GET /projects: 200 OK \(connect \S+, TTFB \S+, total \S+\)`, log.String(), "the first request opens a connection")
+	assert.Regexp(t, `GET /projects: 200 OK \(reused connection, TTFB \S+, total \S+\)`, log.String(), "the second request reuses it")
+
+	summary, ok := timings.summary(time.Now())
+	require.True(t, ok)
+	assert.Regexp(t, `^Timings: 2 API requests took \S+, the command ran for \S+\. The slowest request was GET /projects \(\S+\)\.$`, summary)
+}
+
+func TestEnableTimings(t *testing.T) {
+	run := func(t *testing.T, args ...string) string {
+		cmd := &cobra.Command{
+			Use: "get",
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				timings := cmd.Context().Value(timingLogKey{}).(*timingLog)
+				timings.record(&requestTiming{method: http.MethodGet, path: "/projects", total: 120 * time.Millisecond})
+				timings.record(&requestTiming{method: http.MethodGet, path: "/admin/identities", total: 812 * time.Millisecond})
+				return nil
+			},
+		}
+		RegisterTimingsFlag(cmd.Flags())
+		RegisterVerboseFlag(cmd.Flags())
+		EnableTimings(cmd)
+
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.ExecuteContext(contextWithTimingLog(context.Background())))
+		return stderr.String()
+	}
+
+	const summary = "Timings: 2 API requests took 932ms, the command ran for "
+	assert.NotContains(t, run(t), "Timings", "the summary is only logged at the debug level")
+	assert.Contains(t, run(t, "--verbose"), summary)
+
+	out := run(t, "--timings")
+	assert.Contains(t, out, summary)
+	assert.Contains(t, out, "The slowest request was GET /admin/identities (812ms).")
+}
+
+func TestRoundDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		1234567 * time.Nanosecond:  "1ms",
+		2345678 * time.Microsecond: "2.3s",
+		12500 * time.Millisecond:   "13s",
+		1500 * time.Nanosecond:     "2µs",
+	} {
+		assert.Equal(t, expected, roundDuration(d).String())
+	}
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	CAFileFlag                = "ca-file"
+	InsecureSkipTLSVerifyFlag = "insecure-skip-tls-verify"
+	// CAFileEnv sets the CA file if the --ca-file flag is not set.
+	CAFileEnv = "ORY_CA_FILE"
+)
+
+// publicOryDomains are the domains of Ory Cloud, whose certificates are always verified.
+var publicOryDomains = []string{"ory.sh", "oryapis.com"}
+
+// RegisterTLSFlags registers the flags which configure the verification of TLS certificates. They are meant for
+// self-hosted Ory endpoints behind an internal certificate authority.
+func RegisterTLSFlags(f *pflag.FlagSet) {
+	f.String(CAFileFlag, "", fmt.Sprintf("Trust the PEM encoded certificates in this file in addition to the system's certificate authorities. Defaults to the %s environment variable.", CAFileEnv))
+	f.Bool(InsecureSkipTLSVerifyFlag, false, "Do not verify the TLS certificates of self-hosted Ory endpoints. This is insecure and refused for Ory Cloud.")
+}
+
+// TLSConfig returns the TLS configuration for requests to endpoint as configured by the flags of cmd. It returns nil
+// if the defaults apply.
+func TLSConfig(cmd *cobra.Command, endpoint *url.URL) (*tls.Config, error) {
+	caFile, _ := cmd.Flags().GetString(CAFileFlag)
+	source := "--" + CAFileFlag
+	if caFile == "" {
+		caFile, source = os.Getenv(CAFileEnv), CAFileEnv
+	}
+	insecure, _ := cmd.Flags().GetBool(InsecureSkipTLSVerifyFlag)
+	if caFile == "" && !insecure {
+		return nil, nil
+	}
+
+	conf := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, NewValidationError(errors.Wrapf(err, "unable to read the certificates of %s", source), nil)
+		}
+		conf.RootCAs, err = x509.SystemCertPool()
+		if err != nil {
+			conf.RootCAs = x509.NewCertPool()
+		}
+		if !conf.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, NewValidationError(errors.Errorf("%s does not contain any PEM encoded certificates: %s", source, caFile), nil)
+		}
+	}
+
+	if insecure {
+		if isPublicOryEndpoint(endpoint) {
+			return nil, NewValidationError(errors.Errorf("--%s is not allowed for %s, it is only meant for self-hosted Ory endpoints", InsecureSkipTLSVerifyFlag, endpoint.Host), nil)
+		}
+		// #nosec G402 - the user explicitly asked for it
+		conf.InsecureSkipVerify = true
+	}
+	return conf, nil
+}
+
+// NewTransport returns the transport for requests to endpoint, which applies the proxy and TLS flags of cmd and the
+// transport settings of the environment. It prints a warning if TLS certificates are not verified, and logs the
+// effective transport settings at the debug level.
+func NewTransport(cmd *cobra.Command, endpoint *url.URL) (http.RoundTripper, error) {
+	proxy, err := Proxy(cmd)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, err := TLSConfig(cmd, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := transportSettingsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConf != nil && tlsConf.InsecureSkipVerify {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: TLS certificates of %s are NOT verified because --%s is set. Anyone on the network path can read and modify the requests, including your credentials.\n", endpoint.Host, InsecureSkipTLSVerifyFlag)
+	}
+
+	transport := newBaseTransport(proxy, tlsConf, settings)
+	if t, ok := transport.(*proxyTransport); ok {
+		if l, err := NewLogger(cmd); err == nil {
+			l.Debugf("%s", settings.describe(t.Transport))
+		}
+	}
+	return transport, nil
+}
+
+func isPublicOryEndpoint(endpoint *url.URL) bool {
+	host := strings.ToLower(endpoint.Hostname())
+	for _, domain := range publicOryDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bytes"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransportTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(ts.Close)
+	endpoint, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600))
+
+	send := func(t *testing.T, args ...string) (string, error) {
+		cmd := &cobra.Command{}
+		RegisterProxyFlag(cmd.Flags())
+		RegisterTLSFlags(cmd.Flags())
+		require.NoError(t, cmd.ParseFlags(args))
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+
+		transport, err := NewTransport(cmd, endpoint)
+		require.NoError(t, err)
+		res, err := (&http.Client{Transport: transport}).Get(ts.URL)
+		if err == nil {
+			_ = res.Body.Close()
+		}
+		return stderr.String(), err
+	}
+
+	t.Setenv(CAFileEnv, "")
+
+	t.Run("case=rejects unknown certificate authorities", func(t *testing.T) {
+		_, err := send(t)
+		assert.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("case=trusts the CA file", func(t *testing.T) {
+		stderr, err := send(t, "--ca-file", caFile)
+		assert.NoError(t, err)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("case=trusts the CA file from the environment", func(t *testing.T) {
+		t.Setenv(CAFileEnv, caFile)
+		_, err := send(t)
+		assert.NoError(t, err)
+	})
+
+	t.Run("case=skips the verification with a warning", func(t *testing.T) {
+		stderr, err := send(t, "--insecure-skip-tls-verify")
+		assert.NoError(t, err)
+		assert.Contains(t, stderr, "WARNING: TLS certificates of "+endpoint.Host+" are NOT verified")
+	})
+}
+
+func TestTLSConfig(t *testing.T) {
+	newCmd := func(t *testing.T, args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		RegisterTLSFlags(cmd.Flags())
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+	t.Setenv(CAFileEnv, "")
+	selfHosted, _ := url.Parse("https://ory.internal.corp")
+
+	conf, err := TLSConfig(newCmd(t), selfHosted)
+	require.NoError(t, err)
+	assert.Nil(t, conf, "the defaults apply without flags")
+
+	for _, endpoint := range []string{"https://console.ory.sh", "https://api.console.ory.sh", "https://my-project.projects.oryapis.com/"} {
+		u, _ := url.Parse(endpoint)
+		_, err := TLSConfig(newCmd(t, "--insecure-skip-tls-verify"), u)
+		require.Error(t, err, endpoint)
+		assert.Equal(t, ExitValidation, ExitCode(err), endpoint)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a certificate"), 0600))
+	for _, file := range []string{invalid, filepath.Join(t.TempDir(), "missing.pem")} {
+		_, err := TLSConfig(newCmd(t, "--ca-file", file), selfHosted)
+		require.Error(t, err, file)
+		assert.Equal(t, ExitValidation, ExitCode(err), file)
+	}
+}
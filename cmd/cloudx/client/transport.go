@@ -0,0 +1,113 @@
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The transport settings are meant for networks whose middleboxes break HTTP/2 or many concurrent connections.
+const (
+	HTTP2Env           = "ORY_HTTP2"
+	MaxIdleConnsEnv    = "ORY_MAX_IDLE_CONNS"
+	MaxConnsPerHostEnv = "ORY_MAX_CONNS_PER_HOST"
+	TLSMinVersionEnv   = "ORY_TLS_MIN_VERSION"
+)
+
+var tlsVersions = map[string]uint16{"1.2": tls.VersionTLS12, "1.3": tls.VersionTLS13}
+
+// transportSettings tune the transport of all API requests. Zero values keep the defaults of http.DefaultTransport.
+type transportSettings struct {
+	disableHTTP2    bool
+	maxIdleConns    int
+	maxConnsPerHost int
+	tlsMinVersion   string
+}
+
+// transportSettingsFromEnv reads the transport settings from the environment variables. Invalid values are validation
+// errors which name the variable.
+func transportSettingsFromEnv() (*transportSettings, error) {
+	s := new(transportSettings)
+	if v := os.Getenv(HTTP2Env); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, NewValidationError(errors.Errorf("%s must be true or false, got %q", HTTP2Env, v), nil)
+		}
+		s.disableHTTP2 = !enabled
+	}
+
+	for _, setting := range []struct {
+		env string
+		v   *int
+	}{{MaxIdleConnsEnv, &s.maxIdleConns}, {MaxConnsPerHostEnv, &s.maxConnsPerHost}} {
+		v := os.Getenv(setting.env)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, NewValidationError(errors.Errorf("%s must be a positive number, got %q", setting.env, v), nil)
+		}
+		*setting.v = n
+	}
+
+	if v := strings.TrimPrefix(strings.ToLower(os.Getenv(TLSMinVersionEnv)), "tls"); v != "" {
+		if _, ok := tlsVersions[v]; !ok {
+			return nil, NewValidationError(errors.Errorf("%s must be 1.2 or 1.3, got %q; TLS 1.0 and 1.1 are insecure and not supported by Ory Cloud", TLSMinVersionEnv, os.Getenv(TLSMinVersionEnv)), nil)
+		}
+		s.tlsMinVersion = v
+	}
+	return s, nil
+}
+
+// apply changes t according to the settings. It must be called after the TLS configuration of t was set.
+func (s *transportSettings) apply(t *http.Transport) {
+	if s.maxIdleConns > 0 {
+		t.MaxIdleConns = s.maxIdleConns
+	}
+	if s.maxConnsPerHost > 0 {
+		t.MaxConnsPerHost = s.maxConnsPerHost
+	}
+	for _, limit := range []int{t.MaxIdleConns, t.MaxConnsPerHost} {
+		if limit > 0 && t.MaxIdleConnsPerHost > limit {
+			t.MaxIdleConnsPerHost = limit
+		}
+	}
+
+	if s.tlsMinVersion != "" {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = new(tls.Config)
+		}
+		t.TLSClientConfig.MinVersion = tlsVersions[s.tlsMinVersion]
+	}
+
+	if s.disableHTTP2 {
+		// A non-nil, empty TLSNextProto disables HTTP/2, and only HTTP/1.1 is offered during the TLS handshake.
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		if t.TLSClientConfig != nil {
+			t.TLSClientConfig.NextProtos = []string{"http/1.1"}
+		}
+	}
+}
+
+// describe returns the effective settings of t for the debug log.
+func (s *transportSettings) describe(t *http.Transport) string {
+	limit := func(n int) string {
+		if n <= 0 {
+			return "unlimited"
+		}
+		return strconv.Itoa(n)
+	}
+	minVersion := "1.2"
+	if s.tlsMinVersion != "" {
+		minVersion = s.tlsMinVersion
+	}
+	return fmt.Sprintf("Transport: HTTP/2 %t, max idle connections %s (%s per host), max connections per host %s, minimum TLS version %s",
+		!s.disableHTTP2, limit(t.MaxIdleConns), limit(t.MaxIdleConnsPerHost), limit(t.MaxConnsPerHost), minVersion)
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportSettingsFromEnv(t *testing.T) {
+	t.Run("case=defaults", func(t *testing.T) {
+		s, err := transportSettingsFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, &transportSettings{}, s)
+	})
+
+	t.Run("case=valid values", func(t *testing.T) {
+		t.Setenv(HTTP2Env, "false")
+		t.Setenv(MaxIdleConnsEnv, "4")
+		t.Setenv(MaxConnsPerHostEnv, "2")
+		t.Setenv(TLSMinVersionEnv, "TLS1.3")
+		s, err := transportSettingsFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, &transportSettings{disableHTTP2: true, maxIdleConns: 4, maxConnsPerHost: 2, tlsMinVersion: "1.3"}, s)
+
+		tr := newBaseTransport(nil, nil, s).(*proxyTransport).Transport
+		assert.Equal(t, 4, tr.MaxIdleConns)
+		assert.Equal(t, 2, tr.MaxConnsPerHost)
+		assert.Equal(t, 2, tr.MaxIdleConnsPerHost, "idle connections per host are capped by the connections per host")
+		assert.EqualValues(t, tls.VersionTLS13, tr.TLSClientConfig.MinVersion)
+		assert.Equal(t, "Transport: HTTP/2 false, max idle connections 4 (2 per host), max connections per host 2, minimum TLS version 1.3", s.describe(tr))
+	})
+
+	for env, value := range map[string]string{
+		HTTP2Env:           "maybe",
+		MaxIdleConnsEnv:    "0",
+		MaxConnsPerHostEnv: "many",
+		TLSMinVersionEnv:   "1.0",
+	} {
+		t.Run("case=invalid "+env, func(t *testing.T) {
+			t.Setenv(env, value)
+			_, err := transportSettingsFromEnv()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), env)
+			assert.Equal(t, ExitValidation, ExitCode(err))
+		})
+	}
+}
+
+func TestDisableHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	proto := func(t *testing.T, s *transportSettings) int {
+		res, err := (&http.Client{Transport: newBaseTransport(nil, &tls.Config{RootCAs: roots}, s)}).Get(ts.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		return res.ProtoMajor
+	}
+
+	assert.Equal(t, 2, proto(t, &transportSettings{}))
+	assert.Equal(t, 1, proto(t, &transportSettings{disableHTTP2: true}))
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	NoCheckFlag = "no-check"
+	// NoUpdateCheckEnv disables the update check if set, e.g. in offline environments.
+	NoUpdateCheckEnv = "ORY_NO_UPDATE_CHECK"
+	// updateCheckTimeout bounds the update check, so that it never slows a command down noticeably.
+	updateCheckTimeout = 3 * time.Second
+	// updateCheckTTL is how long the latest release is cached.
+	updateCheckTTL = 24 * time.Hour
+)
+
+// releasesURL is the GitHub API endpoint of the releases of the CLI, it is a variable for tests.
+var releasesURL = "https://api.github.com/repos/ory/cli/releases"
+
+// The statuses of an UpdateCheck.
+const (
+	UpdateAvailable    = "update_available"
+	UpToDate           = "up_to_date"
+	UpdateCheckSkipped = "skipped"
+)
+
+// UpdateCheck is the result of CheckForUpdate.
+type UpdateCheck struct {
+	Status string `json:"status"`
+	// Latest is the version of the latest release, if it is known.
+	Latest string `json:"latest,omitempty"`
+	// URL is the release page of the latest release.
+	URL string `json:"url,omitempty"`
+	// Reason explains why the check was skipped.
+	Reason string `json:"reason,omitempty"`
+}
+
+func (c *UpdateCheck) String() string {
+	switch c.Status {
+	case UpdateAvailable:
+		return fmt.Sprintf("%s is available, run \"ory self-update\" to install it: %s", c.Latest, c.URL)
+	case UpToDate:
+		return "up to date"
+	}
+	return fmt.Sprintf("update check skipped (%s)", c.Reason)
+}
+
+// release is the part of a GitHub release which the update check and self-update need.
+type release struct {
+	Tag    string         `json:"tag_name"`
+	URL    string         `json:"html_url"`
+	Assets []releaseAsset `json:"assets,omitempty"`
+}
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// RegisterNoCheckFlag registers the flag which skips the update check.
+func RegisterNoCheckFlag(f *pflag.FlagSet) {
+	f.Bool(NoCheckFlag, false, fmt.Sprintf("Do not check whether a newer release exists. Setting %s has the same effect.", NoUpdateCheckEnv))
+}
+
+// UpdateCheckDisabled returns the reason why the update check is disabled, or an empty string if it is enabled.
+func UpdateCheckDisabled(f *pflag.FlagSet) string {
+	if noCheck, _ := f.GetBool(NoCheckFlag); noCheck {
+		return "--" + NoCheckFlag + " is set"
+	} else if _, ok := os.LookupEnv(NoUpdateCheckEnv); ok {
+		return NoUpdateCheckEnv + " is set"
+	}
+	return ""
+}
+
+// CheckForUpdate compares the version with the latest release of the CLI on GitHub. The latest release is cached for a
+// day. The check never fails: errors, e.g. if the network is unavailable, are reported as a skipped check.
+func (h *CommandHelper) CheckForUpdate(version string) *UpdateCheck {
+	current, err := semver.NewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return &UpdateCheck{Status: UpdateCheckSkipped, Reason: fmt.Sprintf("%s is a development build", version)}
+	}
+
+	var latest release
+	endpoint := releasesURL + "/latest"
+	if !h.cache.get(endpoint, "", &latest) {
+		ctx, cancel := context.WithTimeout(h.Ctx, updateCheckTimeout)
+		defer cancel()
+		if err := h.fetchRelease(ctx, endpoint, &latest); err != nil {
+			h.Log.Debugf("Unable to check for updates: %s", err)
+			return &UpdateCheck{Status: UpdateCheckSkipped, Reason: err.Error()}
+		}
+		// Only the version is needed, the assets are looked up again by self-update.
+		latest.Assets = nil
+		if err := h.cache.put(endpoint, "", updateCheckTTL, &latest); err != nil {
+			h.Log.Debugf("Unable to cache the latest release: %s", err)
+		}
+	}
+
+	v, err := semver.NewVersion(strings.TrimPrefix(latest.Tag, "v"))
+	if err != nil {
+		return &UpdateCheck{Status: UpdateCheckSkipped, Reason: fmt.Sprintf("the latest release has the invalid version %q", latest.Tag)}
+	}
+	if v.GreaterThan(current) {
+		return &UpdateCheck{Status: UpdateAvailable, Latest: latest.Tag, URL: latest.URL}
+	}
+	return &UpdateCheck{Status: UpToDate, Latest: latest.Tag, URL: latest.URL}
+}
+
+func (h *CommandHelper) fetchRelease(ctx context.Context, endpoint string, r *release) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	res, err := h.client.httpClient("").Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("GitHub responded with %s", res.Status)
+	}
+	return errors.Wrap(json.NewDecoder(res.Body).Decode(r), "unable to decode the release")
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+)
+
+func TestCheckForUpdate(t *testing.T) {
+	var requests int32
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/repos/ory/cli/releases/latest" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"tag_name": "v0.2.0", "html_url": "https://github.com/ory/cli/releases/tag/v0.2.0"}`)
+	}))
+	t.Cleanup(github.Close)
+
+	check := func(t *testing.T, config, server, version string) *UpdateCheck {
+		original := releasesURL
+		releasesURL = server + "/repos/ory/cli/releases"
+		t.Cleanup(func() { releasesURL = original })
+		t.Setenv(ProxyEnv, "")
+		var result *UpdateCheck
+		cmd := &cobra.Command{
+			Use: "version",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				h, err := NewCommandHelper(cmd)
+				if err != nil {
+					return err
+				}
+				result = h.CheckForUpdate(version)
+				return nil
+			},
+		}
+		RegisterConfigFlag(cmd.Flags())
+		RegisterYesFlag(cmd.Flags())
+		cmdx.RegisterNoiseFlags(cmd.Flags())
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetArgs([]string{"--" + ConfigFlag, config})
+		require.NoError(t, cmd.ExecuteContext(context.Background()))
+		return result
+	}
+
+	t.Run("case=reports newer releases", func(t *testing.T) {
+		config := filepath.Join(t.TempDir(), "config.json")
+		assert.Equal(t, &UpdateCheck{Status: UpdateAvailable, Latest: "v0.2.0", URL: "https://github.com/ory/cli/releases/tag/v0.2.0"}, check(t, config, github.URL, "v0.1.33"))
+
+		c := check(t, config, github.URL, "v0.2.0")
+		assert.Equal(t, UpToDate, c.Status)
+		assert.Equal(t, "up to date", c.String())
+	})
+
+	t.Run("case=caches the latest release", func(t *testing.T) {
+		config := filepath.Join(t.TempDir(), "config.json")
+		before := atomic.LoadInt32(&requests)
+		check(t, config, github.URL, "v0.1.33")
+		check(t, config, github.URL, "v0.1.33")
+		assert.Equal(t, before+1, atomic.LoadInt32(&requests))
+	})
+
+	t.Run("case=skips development builds", func(t *testing.T) {
+		c := check(t, filepath.Join(t.TempDir(), "config.json"), github.URL, "master")
+		assert.Equal(t, UpdateCheckSkipped, c.Status)
+		assert.Equal(t, "update check skipped (master is a development build)", c.String())
+	})
+
+	t.Run("case=network errors skip the check", func(t *testing.T) {
+		unreachable := httptest.NewServer(http.NotFoundHandler())
+		unreachable.Close()
+		c := check(t, filepath.Join(t.TempDir(), "config.json"), unreachable.URL, "v0.1.33")
+		assert.Equal(t, UpdateCheckSkipped, c.Status)
+		assert.NotEmpty(t, c.Reason)
+	})
+}
+
+func TestUpdateCheckDisabled(t *testing.T) {
+	f := new(cobra.Command).Flags()
+	RegisterNoCheckFlag(f)
+	t.Setenv(NoUpdateCheckEnv, "")
+	assert.Equal(t, NoUpdateCheckEnv+" is set", UpdateCheckDisabled(f))
+
+	require.NoError(t, f.Set(NoCheckFlag, "true"))
+	assert.Equal(t, "--no-check is set", UpdateCheckDisabled(f))
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"github.com/ory/cli/buildinfo"
+)
+
+// userAgent returns the User-Agent header of the API requests sent by the command with the given path, e.g.
+// "ory-cli/v0.1.0 (linux/amd64) cmd/ory/get/project". It lets the API tell the traffic of the CLI apart from other
+// clients of the SDKs. The version is set at build time and is "dev" for development builds.
+func userAgent(commandPath string) string {
+	version := buildinfo.Version
+	if version == "" || version == "master" {
+		version = "dev"
+	}
+	ua := fmt.Sprintf("ory-cli/%s (%s/%s)", version, runtime.GOOS, runtime.GOARCH)
+	if path := strings.Fields(commandPath); len(path) > 0 {
+		ua += " cmd/" + strings.Join(path, "/")
+	}
+	return ua
+}
+
+// userAgentTransport sets the User-Agent header of all requests, replacing the one set by the SDKs.
+type userAgentTransport struct {
+	http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.RoundTripper.RoundTrip(req)
+}
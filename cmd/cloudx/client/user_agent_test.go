@@ -0,0 +1,49 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/buildinfo"
+)
+
+func TestUserAgent(t *testing.T) {
+	var received string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(ts.Close)
+
+	root := &cobra.Command{Use: "ory"}
+	get := &cobra.Command{Use: "get"}
+	project := &cobra.Command{Use: "project"}
+	root.AddCommand(get)
+	get.AddCommand(project)
+
+	debug := &debugLog{out: new(bytes.Buffer)}
+	c := newClient(http.DefaultTransport, clientConfig{debug: debug, commandPath: project.CommandPath()})
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "OpenAPI-Generator/1.0.0/go")
+	res, err := c.httpClient("").Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+
+	assert.Regexp(t, regexp.MustCompile(`^ory-cli/\S+ \(\w+/\w+\) cmd/ory/get/project$`), received)
+	assert.Equal(t, "ory-cli/dev ("+runtime.GOOS+"/"+runtime.GOARCH+") cmd/ory/get/project", received, "development builds report the dev version")
+	assert.Contains(t, debug.out.(*bytes.Buffer).String(), "    User-Agent: "+received+"\n", "the debug log shows the header")
+
+	version := buildinfo.Version
+	buildinfo.Version = "v0.1.33"
+	t.Cleanup(func() { buildinfo.Version = version })
+	assert.Equal(t, "ory-cli/v0.1.33 ("+runtime.GOOS+"/"+runtime.GOARCH+")", userAgent(""))
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/cli/cmd/cloudx/client/pool"
+)
+
+const (
+	WorkersFlag     = "workers"
+	MaxFailuresFlag = "max-failures"
+)
+
+// RegisterWorkersFlag registers the flag which sets the number of concurrent requests of bulk operations.
+func RegisterWorkersFlag(f *pflag.FlagSet) {
+	f.Int(WorkersFlag, pool.DefaultWorkers, fmt.Sprintf("The number of requests sent concurrently, at most %d.", pool.MaxWorkers))
+}
+
+// Workers returns the number of workers configured by the --workers flag of cmd. Values above pool.MaxWorkers are
+// capped, values below 1 are rejected. It returns pool.DefaultWorkers if the flag is not registered.
+func Workers(cmd *cobra.Command) (int, error) {
+	n, err := cmd.Flags().GetInt(WorkersFlag)
+	if err != nil {
+		return pool.DefaultWorkers, nil
+	}
+	if n < 1 {
+		return 0, NewValidationError(errors.Errorf("--%s must be at least 1, got %d", WorkersFlag, n), nil)
+	}
+	if n > pool.MaxWorkers {
+		return pool.MaxWorkers, nil
+	}
+	return n, nil
+}
+
+// RegisterMaxFailuresFlag registers the flag which stops bulk operations once the API is consistently failing.
+func RegisterMaxFailuresFlag(f *pflag.FlagSet) {
+	f.Int(MaxFailuresFlag, pool.DefaultMaxFailures, "Stop once this many requests failed in a row, e.g. because the API is unavailable. Rate limited requests do not count. Use 0 to never stop.")
+}
+
+// MaxFailures returns the number of consecutive failures configured by the --max-failures flag of cmd. It returns
+// pool.DefaultMaxFailures if the flag is not registered.
+func MaxFailures(cmd *cobra.Command) (int, error) {
+	n, err := cmd.Flags().GetInt(MaxFailuresFlag)
+	if err != nil {
+		return pool.DefaultMaxFailures, nil
+	}
+	if n < 0 {
+		return 0, NewValidationError(errors.Errorf("--%s must not be negative, got %d", MaxFailuresFlag, n), nil)
+	}
+	return n, nil
+}
+
+// NewBreaker returns a pool.Breaker for the requests of bulk operations, which opens after maxFailures server or
+// network errors in a row, and the context which is canceled once it opens.
+func NewBreaker(ctx context.Context, maxFailures int) (context.Context, *pool.Breaker) {
+	return pool.NewBreaker(ctx, maxFailures, classifyFailure)
+}
+
+// classifyFailure classifies the errors of API requests for a pool.Breaker. Server and network errors are failures,
+// rate limited requests are ignored, and all other errors, e.g. an invalid identity, show that the API works.
+func classifyFailure(err error) pool.Outcome {
+	var (
+		apiErr *APIError
+		netErr net.Error
+	)
+	switch {
+	case IsThrottled(err), errors.Is(err, context.Canceled):
+		return pool.Ignored
+	case errors.As(err, &apiErr):
+		if apiErr.StatusCode >= http.StatusInternalServerError {
+			return pool.Failure
+		}
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		return pool.Failure
+	}
+	return pool.Success
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client/pool"
+)
+
+func TestWorkers(t *testing.T) {
+	newCmd := func(t *testing.T, args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		RegisterWorkersFlag(cmd.Flags())
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	n, err := Workers(&cobra.Command{})
+	require.NoError(t, err)
+	assert.Equal(t, pool.DefaultWorkers, n)
+
+	n, err = Workers(newCmd(t))
+	require.NoError(t, err)
+	assert.Equal(t, pool.DefaultWorkers, n)
+
+	n, err = Workers(newCmd(t, "--workers", "8"))
+	require.NoError(t, err)
+	assert.Equal(t, 8, n)
+
+	n, err = Workers(newCmd(t, "--workers", "1000"))
+	require.NoError(t, err)
+	assert.Equal(t, pool.MaxWorkers, n, "the number of workers is capped")
+
+	for _, invalid := range []string{"0", "-1"} {
+		_, err = Workers(newCmd(t, "--workers", invalid))
+		require.Error(t, err, invalid)
+		assert.Equal(t, ExitValidation, ExitCode(err), invalid)
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected pool.Outcome
+	}{
+		{&APIError{StatusCode: http.StatusInternalServerError}, pool.Failure},
+		{&APIError{StatusCode: http.StatusBadGateway}, pool.Failure},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, pool.Failure},
+		{context.DeadlineExceeded, pool.Failure},
+		{&APIError{StatusCode: http.StatusTooManyRequests}, pool.Ignored},
+		{context.Canceled, pool.Ignored},
+		{&APIError{StatusCode: http.StatusBadRequest}, pool.Success},
+		{&APIError{StatusCode: http.StatusConflict}, pool.Success},
+	} {
+		assert.Equal(t, tc.expected, classifyFailure(errors.WithStack(tc.err)), "%+v", tc.err)
+	}
+}
@@ -0,0 +1,181 @@
+package cloudxtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// serveAuth serves the API which signs users up and in to the Ory Console and checks their sessions.
+func (b *Backend) serveAuth(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/self-service/login/api":
+		writeJSON(w, http.StatusOK, b.newFlow(r, "login", nil))
+	case r.Method == http.MethodPost && r.URL.Path == "/self-service/login":
+		b.submitLogin(w, r)
+	case r.Method == http.MethodGet && r.URL.Path == "/self-service/registration/api":
+		writeJSON(w, http.StatusOK, b.newFlow(r, "registration", nil))
+	case r.Method == http.MethodPost && r.URL.Path == "/self-service/registration":
+		b.submitRegistration(w, r)
+	case r.Method == http.MethodGet && r.URL.Path == "/sessions/whoami":
+		if a, ok := b.authenticate(w, r); ok {
+			writeJSON(w, http.StatusOK, a.session())
+		}
+	default:
+		writeError(w, http.StatusNotFound, "the fake does not implement "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (b *Backend) submitLogin(w http.ResponseWriter, r *http.Request) {
+	if !b.checkFlow(w, r, "login") {
+		return
+	}
+	var body struct {
+		Identifier         string `json:"identifier"`
+		PasswordIdentifier string `json:"password_identifier"`
+		Password           string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.Identifier == "" {
+		body.Identifier = body.PasswordIdentifier
+	}
+
+	b.mu.Lock()
+	a, ok := b.accounts[body.Identifier]
+	if !ok || a.password != body.Password {
+		b.mu.Unlock()
+		flow := b.newFlow(r, "login", map[string]string{"identifier": body.Identifier})
+		flow["ui"].(map[string]interface{})["messages"] = []interface{}{uiText(4000006, "error",
+			"The provided credentials are invalid, check for spelling mistakes in your password or username, email address, or phone number.")}
+		writeJSON(w, http.StatusBadRequest, flow)
+		return
+	}
+	token := b.addSession(a)
+	b.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"session_token": token, "session": a.session()})
+}
+
+func (b *Backend) submitRegistration(w http.ResponseWriter, r *http.Request) {
+	if !b.checkFlow(w, r, "registration") {
+		return
+	}
+	var body struct {
+		Password string `json:"password"`
+		Traits   struct {
+			Email string `json:"email"`
+			Name  string `json:"name"`
+		} `json:"traits"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	b.mu.Lock()
+	if _, ok := b.accounts[body.Traits.Email]; ok || body.Traits.Email == "" || body.Password == "" {
+		b.mu.Unlock()
+		flow := b.newFlow(r, "registration", map[string]string{"traits.email": body.Traits.Email, "traits.name": body.Traits.Name})
+		flow["ui"].(map[string]interface{})["messages"] = []interface{}{uiText(4000007, "error",
+			"An account with the same identifier (email, phone, username, ...) exists already.")}
+		writeJSON(w, http.StatusBadRequest, flow)
+		return
+	}
+	a := b.addAccount(body.Traits.Email, body.Traits.Name, body.Password)
+	token := b.addSession(a)
+	b.mu.Unlock()
+
+	s := a.session()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"session_token": token, "session": s, "identity": s["identity"]})
+}
+
+// checkFlow responds with 410 Gone unless the flow query parameter is a flow of the given type.
+func (b *Backend) checkFlow(w http.ResponseWriter, r *http.Request, typ string) bool {
+	b.mu.Lock()
+	ok := b.flows[r.URL.Query().Get("flow")] == typ
+	b.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusGone, "The self-service flow expired or does not exist.")
+	}
+	return ok
+}
+
+// newFlow starts a login or registration flow. The values prefill the inputs, e.g. after a failed submission.
+func (b *Backend) newFlow(r *http.Request, typ string, values map[string]string) map[string]interface{} {
+	id := newID()
+	b.mu.Lock()
+	b.flows[id] = typ
+	b.mu.Unlock()
+
+	nodes := []interface{}{uiInput("default", "csrf_token", "hidden", "", "")}
+	if typ == "login" {
+		nodes = append(nodes,
+			uiInput("default", "identifier", "text", values["identifier"], "ID"),
+			uiInput("password", "password", "password", "", "Password"),
+		)
+	} else {
+		nodes = append(nodes,
+			uiInput("password", "traits.email", "email", values["traits.email"], "E-Mail"),
+			uiInput("password", "traits.name", "text", values["traits.name"], "Name"),
+			uiInput("password", "traits.consent.newsletter", "checkbox", "", "Subscribe to the newsletter"),
+			uiInput("password", "traits.consent.tos", "text", "", "Accept the Terms of Service"),
+			uiInput("password", "password", "password", "", "Password"),
+		)
+	}
+	nodes = append(nodes, uiInput("password", "method", "submit", "password", "Sign in"))
+
+	now := time.Now().UTC()
+	return map[string]interface{}{
+		"id":          id,
+		"type":        "api",
+		"issued_at":   now.Format(time.RFC3339),
+		"expires_at":  now.Add(time.Hour).Format(time.RFC3339),
+		"request_url": "http://" + r.Host + r.URL.RequestURI(),
+		"ui": map[string]interface{}{
+			"action": "http://" + r.Host + "/self-service/" + typ + "?flow=" + id,
+			"method": "POST",
+			"nodes":  nodes,
+		},
+	}
+}
+
+func uiInput(group, name, typ, value, label string) map[string]interface{} {
+	attrs := map[string]interface{}{"node_type": "input", "name": name, "type": typ, "disabled": false}
+	if value != "" {
+		attrs["value"] = value
+	}
+	if typ != "hidden" && typ != "submit" && typ != "checkbox" {
+		attrs["required"] = true
+	}
+	meta := map[string]interface{}{}
+	if label != "" {
+		meta["label"] = uiText(1070001, "info", label)
+	}
+	return map[string]interface{}{"type": "input", "group": group, "attributes": attrs, "messages": []interface{}{}, "meta": meta}
+}
+
+func uiText(id int, typ, text string) map[string]interface{} {
+	return map[string]interface{}{"id": id, "type": typ, "text": text}
+}
+
+// session returns the session of the account as returned by the API.
+func (a *account) session() map[string]interface{} {
+	traits := map[string]interface{}{"email": a.email}
+	if a.name != "" {
+		traits["name"] = a.name
+	}
+	return map[string]interface{}{
+		"id":     newID(),
+		"active": true,
+		"identity": map[string]interface{}{
+			"id":         a.id,
+			"schema_id":  "default",
+			"schema_url": ConsoleURL + "/schemas/default",
+			"state":      "active",
+			"traits":     traits,
+		},
+	}
+}
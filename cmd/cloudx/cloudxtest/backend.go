@@ -0,0 +1,273 @@
+package cloudxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v3"
+)
+
+// ConsoleURL is the URL of the Ory Console which the CLI is configured with by Env. The CLI derives the URLs of all
+// APIs from it, e.g. the Console API is served at http://api.console.ory.test.
+const ConsoleURL = "http://console.ory.test"
+
+// Backend is an in-memory fake of the Ory Cloud APIs. All accounts have access to all projects. Use NewBackend to
+// create one, it is safe for concurrent use.
+type Backend struct {
+	mu       sync.Mutex
+	accounts map[string]*account
+	sessions map[string]*account
+	flows    map[string]string
+	projects []*project
+
+	// overrides are the handlers registered with Handle, mux serves them.
+	overrides map[string]http.Handler
+	mux       *http.ServeMux
+}
+
+type account struct {
+	id, email, name, password string
+}
+
+type project struct {
+	id, slug string
+	// doc is the project as returned by the API.
+	doc        json.RawMessage
+	identities []json.RawMessage
+}
+
+// Project describes a project added with AddProject.
+type Project struct {
+	ID   string
+	Name string
+	Slug string
+}
+
+// NewBackend returns an empty Backend.
+func NewBackend() *Backend {
+	return &Backend{
+		accounts:  map[string]*account{},
+		sessions:  map[string]*account{},
+		flows:     map[string]string{},
+		overrides: map[string]http.Handler{},
+		mux:       http.NewServeMux(),
+	}
+}
+
+// Handle serves requests matching the pattern, see http.ServeMux, with handler instead of the fake. The pattern
+// applies to all hosts. Registering a pattern again replaces its handler.
+func (b *Backend) Handle(pattern string, handler http.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.overrides[pattern] = handler
+	mux := http.NewServeMux()
+	for p, h := range b.overrides {
+		mux.Handle(p, h)
+	}
+	b.mux = mux
+}
+
+// HandleFunc is like Handle but takes a handler function.
+func (b *Backend) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	b.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// AddAccount adds an Ory Console account with the given email address and password and returns its identity ID.
+// Adding an existing account changes its password.
+func (b *Backend) AddAccount(email, password string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.addAccount(email, "", password).id
+}
+
+func (b *Backend) addAccount(email, name, password string) *account {
+	if a, ok := b.accounts[email]; ok {
+		a.password = password
+		return a
+	}
+	a := &account{id: newID(), email: email, name: name, password: password}
+	b.accounts[email] = a
+	return a
+}
+
+// AddSession signs the account with the given email address in and returns the session token. The account is added
+// if it does not exist.
+func (b *Backend) AddSession(email string) string {
+	token := newToken()
+	b.AddSessionToken(email, token)
+	return token
+}
+
+// AddSessionToken is like AddSession but uses the given session token, e.g. to match a configuration file which was
+// written before.
+func (b *Backend) AddSessionToken(email, token string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	a, ok := b.accounts[email]
+	if !ok {
+		a = b.addAccount(email, "", newID())
+	}
+	b.sessions[token] = a
+}
+
+func (b *Backend) addSession(a *account) string {
+	token := newToken()
+	b.sessions[token] = a
+	return token
+}
+
+// ExpireSession makes the session token invalid.
+func (b *Backend) ExpireSession(token string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sessions, token)
+}
+
+// AddProject adds a project. Empty fields are generated.
+func (b *Backend) AddProject(p Project) Project {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.addProject(p).describe()
+}
+
+func (b *Backend) addProject(p Project) *project {
+	if p.ID == "" {
+		p.ID = newID()
+	}
+	if p.Name == "" {
+		p.Name = "Example Project"
+	}
+	if p.Slug == "" {
+		p.Slug = "project-" + p.ID[:8]
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	doc, _ := json.Marshal(map[string]interface{}{
+		"id":          p.ID,
+		"name":        p.Name,
+		"slug":        p.Slug,
+		"state":       "running",
+		"revision_id": newID(),
+		"services": map[string]interface{}{
+			"identity":   map[string]interface{}{"config": map[string]interface{}{}},
+			"permission": map[string]interface{}{"config": map[string]interface{}{}},
+		},
+		"created_at": now,
+		"updated_at": now,
+	})
+	pr := &project{id: p.ID, slug: p.Slug, doc: doc}
+	b.projects = append(b.projects, pr)
+	return pr
+}
+
+// ProjectConfig returns the project as returned by the API, or nil if it does not exist.
+func (b *Backend) ProjectConfig(id string) json.RawMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p := b.project(id); p != nil {
+		return append(json.RawMessage(nil), p.doc...)
+	}
+	return nil
+}
+
+func (b *Backend) project(id string) *project {
+	for _, p := range b.projects {
+		if p.id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (b *Backend) projectBySlug(slug string) *project {
+	for _, p := range b.projects {
+		if p.slug == slug {
+			return p
+		}
+	}
+	return nil
+}
+
+func (p *project) describe() Project {
+	var d struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(p.doc, &d)
+	return Project{ID: p.id, Name: d.Name, Slug: p.slug}
+}
+
+// ServeHTTP routes the request by its host to the Console API (api.*), the API which signs users in to the Ory Console
+// (project.*), or the API of a project (<slug>.projects.*).
+func (b *Backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	mux := b.mux
+	b.mu.Unlock()
+	if h, pattern := mux.Handler(r); pattern != "" {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Path == "/health/alive" || r.URL.Path == "/health/ready" {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		return
+	}
+
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	labels := strings.SplitN(host, ".", 3)
+	switch {
+	case labels[0] == "api":
+		b.serveConsole(w, r)
+	case labels[0] == "project":
+		b.serveAuth(w, r)
+	case len(labels) > 1 && labels[1] == "projects":
+		b.serveProject(w, r, labels[0])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("the fake does not serve the host %s", r.Host))
+	}
+}
+
+// authenticate returns the account of the session token sent in the Authorization or X-Session-Token header. It
+// responds with 401 Unauthorized if the token is invalid.
+func (b *Backend) authenticate(w http.ResponseWriter, r *http.Request) (*account, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.Header.Get("X-Session-Token")
+	}
+
+	b.mu.Lock()
+	a, ok := b.sessions[token]
+	b.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "No valid session credentials found in the request.")
+	}
+	return a, ok
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]interface{}{"error": map[string]interface{}{
+		"code":    code,
+		"status":  http.StatusText(code),
+		"message": message,
+	}})
+}
+
+func newID() string {
+	return uuid.Must(uuid.NewV4()).String()
+}
+
+func newToken() string {
+	return "ory_st_" + strings.ReplaceAll(newID(), "-", "")
+}
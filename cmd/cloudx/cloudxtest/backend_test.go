@@ -0,0 +1,202 @@
+package cloudxtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+// newTestClient returns a client which sends all requests to the backend, and a function which sends a request and
+// returns the status code and the body of the response.
+func newTestClient(t *testing.T, b *Backend) func(method, target, token, body string) (int, string) {
+	srv := httptest.NewServer(b)
+	t.Cleanup(srv.Close)
+	proxy, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	c := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxy)}}
+
+	return func(method, target, token, body string) (int, string) {
+		req, err := http.NewRequest(method, target, strings.NewReader(body))
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		out, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, string(out)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	b := NewBackend()
+	b.AddAccount("dev@ory.sh", "secret")
+	do := newTestClient(t, b)
+
+	login := func(password string) (int, string) {
+		code, flow := do("GET", "http://project.console.ory.test/self-service/login/api", "", "")
+		require.Equal(t, http.StatusOK, code, flow)
+		return do("POST", gjson.Get(flow, "ui.action").String(), "", `{"method":"password","identifier":"dev@ory.sh","password":"`+password+`"}`)
+	}
+
+	t.Run("case=signs in", func(t *testing.T) {
+		code, body := login("secret")
+		require.Equal(t, http.StatusOK, code, body)
+		token := gjson.Get(body, "session_token").String()
+		assert.NotEmpty(t, token)
+
+		code, body = do("GET", "http://project.console.ory.test/sessions/whoami", token, "")
+		assert.Equal(t, http.StatusOK, code, body)
+		assert.Equal(t, "dev@ory.sh", gjson.Get(body, "identity.traits.email").String())
+	})
+
+	t.Run("case=rejects invalid credentials", func(t *testing.T) {
+		code, body := login("wrong")
+		require.Equal(t, http.StatusBadRequest, code, body)
+		assert.EqualValues(t, 4000006, gjson.Get(body, "ui.messages.0.id").Int())
+		assert.Equal(t, "dev@ory.sh", gjson.Get(body, `ui.nodes.#(attributes.name=="identifier").attributes.value`).String())
+	})
+
+	t.Run("case=signs up", func(t *testing.T) {
+		_, flow := do("GET", "http://project.console.ory.test/self-service/registration/api", "", "")
+		code, body := do("POST", gjson.Get(flow, "ui.action").String(), "", `{"method":"password","password":"secret","traits":{"email":"new@ory.sh","name":"New"}}`)
+		require.Equal(t, http.StatusOK, code, body)
+		assert.Equal(t, "New", gjson.Get(body, "identity.traits.name").String())
+
+		code, _ = do("POST", gjson.Get(flow, "ui.action").String(), "", `{"method":"password","password":"secret","traits":{"email":"new@ory.sh"}}`)
+		assert.Equal(t, http.StatusBadRequest, code, "the account exists already")
+	})
+
+	t.Run("case=expired session", func(t *testing.T) {
+		token := b.AddSession("dev@ory.sh")
+		b.ExpireSession(token)
+		code, _ := do("GET", "http://project.console.ory.test/sessions/whoami", token, "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
+
+func TestProjects(t *testing.T) {
+	b := NewBackend()
+	token := b.AddSession("dev@ory.sh")
+	p := b.AddProject(Project{Name: "First"})
+	do := newTestClient(t, b)
+
+	code, _ := do("GET", "http://api.console.ory.test/projects", "", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, body := do("POST", "http://api.console.ory.test/projects", token, `{"name":"Second"}`)
+	require.Equal(t, http.StatusCreated, code, body)
+	second := gjson.Get(body, "id").String()
+
+	code, body = do("GET", "http://api.console.ory.test/projects", token, "")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, `["First","Second"]`, gjson.Get(body, "#.name").Raw)
+	assert.False(t, gjson.Get(body, "0.services").Exists(), "the list contains metadata only")
+
+	code, body = do("PATCH", "http://api.console.ory.test/projects/"+p.ID, token, `[
+  {"op":"add","path":"/services/identity/config/courier","value":{"smtp":{"from_name":"Ory"}}},
+  {"op":"replace","path":"/name","value":"Renamed"}
+]`)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, "Ory", gjson.Get(body, "project.services.identity.config.courier.smtp.from_name").String())
+	assert.Equal(t, "Renamed", gjson.GetBytes(b.ProjectConfig(p.ID), "name").String())
+
+	code, _ = do("PATCH", "http://api.console.ory.test/projects/"+p.ID, token, `[{"op":"remove","path":"/services/identity/config/missing"}]`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, body = do("PUT", "http://api.console.ory.test/projects/"+second, token, `{"name":"Second","services":{"permission":{"config":{"namespaces":[]}}}}`)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, `{"namespaces":[]}`, gjson.Get(body, "project.services.permission.config").Raw)
+
+	code, _ = do("GET", "http://api.console.ory.test/projects/"+newID(), token, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestIdentities(t *testing.T) {
+	b := NewBackend()
+	token := b.AddSession("dev@ory.sh")
+	p := b.AddProject(Project{Slug: "good-wright-t7kzy3vugf"})
+	do := newTestClient(t, b)
+	base := "http://good-wright-t7kzy3vugf.projects.console.ory.test/admin/identities"
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		code, body := do("POST", base, token, `{"schema_id":"default","traits":{"email":"`+email+`"},"credentials":{"password":{"config":{"password":"secret"}}}}`)
+		require.Equal(t, http.StatusCreated, code, body)
+		assert.NotContains(t, body, "secret", "credentials are never returned")
+	}
+	code, _ := do("POST", base, token, `{"schema_id":"default","traits":{"email":"a@example.com"}}`)
+	assert.Equal(t, http.StatusConflict, code)
+	b.AddIdentity(p.ID, json.RawMessage(`{"email":"d@example.com"}`))
+	require.Len(t, b.Identities(p.ID), 4)
+
+	t.Run("case=paginates", func(t *testing.T) {
+		srv := httptest.NewServer(b)
+		defer srv.Close()
+		proxy, _ := url.Parse(srv.URL)
+		c := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxy)}}
+
+		req, _ := http.NewRequest("GET", base+"?per_page=3", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		res, err := c.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, `</admin/identities?page=1&per_page=3>; rel="next"`, res.Header.Get("Link"))
+
+		code, body := do("GET", base+"?per_page=3&page=1", token, "")
+		require.Equal(t, http.StatusOK, code, body)
+		assert.Equal(t, `["d@example.com"]`, gjson.Get(body, "#.traits.email").Raw)
+	})
+
+	t.Run("case=filters by email", func(t *testing.T) {
+		_, body := do("GET", base+"?credentials_identifier=b@example.com", token, "")
+		assert.Equal(t, `["b@example.com"]`, gjson.Get(body, "#.traits.email").Raw)
+	})
+
+	t.Run("case=updates and deletes", func(t *testing.T) {
+		id := gjson.GetBytes(b.Identities(p.ID)[0], "id").String()
+		code, body := do("PUT", base+"/"+id, token, `{"schema_id":"default","state":"inactive","traits":{"email":"z@example.com"}}`)
+		require.Equal(t, http.StatusOK, code, body)
+		assert.Equal(t, "inactive", gjson.Get(body, "state").String())
+
+		code, _ = do("DELETE", base+"/"+id, token, "")
+		assert.Equal(t, http.StatusNoContent, code)
+		code, _ = do("GET", base+"/"+id, token, "")
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("case=unknown project", func(t *testing.T) {
+		code, _ := do("GET", "http://unknown.projects.console.ory.test/admin/identities", token, "")
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestHandle(t *testing.T) {
+	b := NewBackend()
+	token := b.AddSession("dev@ory.sh")
+	do := newTestClient(t, b)
+
+	b.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	code, _ := do("GET", "http://api.console.ory.test/projects", token, "")
+	assert.Equal(t, http.StatusTeapot, code)
+
+	b.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	code, _ = do("GET", "http://api.console.ory.test/projects", token, "")
+	assert.Equal(t, http.StatusServiceUnavailable, code, "the handler was replaced")
+
+	code, _ = do("GET", "http://api.console.ory.test/health/alive", "", "")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,74 @@
+package cloudxtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/x/cmdx"
+
+	"github.com/ory/cli/cmd/cloudx"
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+// Env returns the environment variables which make the CLI send all API requests to the server at serverURL, which
+// serves a Backend.
+func Env(serverURL string) []string {
+	return []string{
+		"ORY_CLOUD_CONSOLE_URL=" + ConsoleURL,
+		client.ProxyEnv + "=" + serverURL,
+	}
+}
+
+// Serve starts a server for the handler, usually a Backend, and sets the environment returned by Env for the test.
+// The server is closed when the test finishes.
+func Serve(t testing.TB, handler http.Handler) *httptest.Server {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	for _, kv := range Env(srv.URL) {
+		parts := strings.SplitN(kv, "=", 2)
+		t.Setenv(parts[0], parts[1])
+	}
+	return srv
+}
+
+// WriteConfig writes a configuration file which signs the CLI in with the session token and selects the project, if
+// projectID is not empty. It returns the path of the file.
+func WriteConfig(t testing.TB, email, token, projectID string) string {
+	config := filepath.Join(t.TempDir(), "config.json")
+	ac := client.AuthContext{
+		Version:        client.Version,
+		SessionToken:   token,
+		IdentityTraits: client.AuthIdentity{Email: email},
+	}
+	if projectID != "" {
+		ac.SelectedProject = uuid.FromStringOrNil(projectID)
+	}
+	out, err := json.Marshal(&ac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config, out, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+// NewCmd returns an executer which runs the CLI commands in process with the configuration file.
+func NewCmd(config string) *cmdx.CommandExecuter {
+	return &cmdx.CommandExecuter{
+		New: func() *cobra.Command {
+			return cloudx.NewRootCommand(new(cobra.Command), "", "")
+		},
+		Ctx:            client.ContextWithClient(context.Background()),
+		PersistentArgs: []string{"--" + client.ConfigFlag, config},
+	}
+}
@@ -0,0 +1,31 @@
+// Package cloudxtest fakes the Ory Cloud APIs which the CLI uses, so that the CLI and tools which run it can be tested
+// without an Ory Console account.
+//
+// The Backend keeps its state in memory and implements the subset of the APIs the CLI calls: signing up and in,
+// checking sessions, creating, listing, getting, updating, and patching projects, and creating, listing, getting,
+// updating, and deleting identities. Handle overrides single endpoints, e.g. to test error handling.
+//
+// The CLI sends its requests to the fake through a proxy, because the Ory Cloud APIs are served from several hosts.
+// Env returns the environment variables which configure the CLI accordingly. In tests of this repository, Serve sets
+// them for the test and NewCmd runs CLI commands in process:
+//
+//	b := cloudxtest.NewBackend()
+//	p := b.AddProject(cloudxtest.Project{Name: "Example Project"})
+//	cloudxtest.Serve(t, b)
+//
+//	config := cloudxtest.WriteConfig(t, "dev@ory.sh", b.AddSession("dev@ory.sh"), p.ID)
+//	stdout, stderr, err := cloudxtest.NewCmd(config).Exec(nil, "list", "identities")
+//
+// Tools in other repositories which run the ory binary pass the environment to it instead:
+//
+//	b := cloudxtest.NewBackend()
+//	srv := httptest.NewServer(b)
+//	defer srv.Close()
+//
+//	config := cloudxtest.WriteConfig(t, "dev@ory.sh", b.AddSession("dev@ory.sh"), "")
+//	cmd := exec.Command("ory", "list", "projects", "--config", config)
+//	cmd.Env = append(os.Environ(), cloudxtest.Env(srv.URL)...)
+//
+// RecordReplay runs a test against the Backend while recording its requests as fixtures, and then again replaying them,
+// which checks that the recorded fixtures do not leak credentials.
+package cloudxtest
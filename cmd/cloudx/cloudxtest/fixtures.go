@@ -0,0 +1,66 @@
+package cloudxtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+// RecordReplay runs the test twice: first against the backend while recording all requests as fixtures, then
+// replaying the fixtures while the backend is unreachable. Commands fail in the second run if they send a request
+// which was not recorded. The fixtures must not contain any of the secrets, which are read after the first run, e.g.
+// the session token the backend issued.
+//
+// The test must send the same requests in both runs, so it must not use random data except for secrets, which are
+// scrubbed from the fixtures.
+func RecordReplay(t *testing.T, b *Backend, run func(t *testing.T), secrets func() []string) {
+	dir := filepath.Join(t.TempDir(), "fixtures")
+
+	t.Run("mode=record", func(t *testing.T) {
+		Serve(t, b)
+		useFixtures(t, dir, true)
+		run(t)
+	})
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) == 0 {
+		t.Fatalf("no requests were recorded to %s", dir)
+	}
+	for _, f := range files {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range secrets() {
+			if secret != "" && strings.Contains(string(content), secret) {
+				t.Errorf("the fixture %s contains the secret %q:\n%s", f, secret, content)
+			}
+		}
+	}
+
+	t.Run("mode=replay", func(t *testing.T) {
+		unreachable := httptest.NewServer(http.NotFoundHandler())
+		unreachable.Close()
+		for _, kv := range Env(unreachable.URL) {
+			parts := strings.SplitN(kv, "=", 2)
+			t.Setenv(parts[0], parts[1])
+		}
+		useFixtures(t, dir, false)
+		run(t)
+	})
+}
+
+func useFixtures(t *testing.T, dir string, record bool) {
+	stop, err := client.UseFixtures(dir, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(stop)
+}
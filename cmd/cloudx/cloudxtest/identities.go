@@ -0,0 +1,203 @@
+package cloudxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const defaultPerPage = 250
+
+// AddIdentity adds an identity with the given traits to the project and returns its ID. It panics if the project does
+// not exist.
+func (b *Backend) AddIdentity(projectID string, traits json.RawMessage) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.project(projectID)
+	if p == nil {
+		panic(fmt.Sprintf("the project %s does not exist", projectID))
+	}
+	identity := newIdentity(json.RawMessage(`{"schema_id":"default"}`), traits)
+	p.identities = append(p.identities, identity)
+	return gjson.GetBytes(identity, "id").String()
+}
+
+// Identities returns the identities of the project as returned by the API.
+func (b *Backend) Identities(projectID string) []json.RawMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.project(projectID)
+	if p == nil {
+		return nil
+	}
+	return append([]json.RawMessage(nil), p.identities...)
+}
+
+func newIdentity(body, traits json.RawMessage) json.RawMessage {
+	now := time.Now().UTC().Format(time.RFC3339)
+	identity, _ := json.Marshal(map[string]interface{}{
+		"id":                   newID(),
+		"schema_id":            gjson.GetBytes(body, "schema_id").String(),
+		"schema_url":           ConsoleURL + "/schemas/" + gjson.GetBytes(body, "schema_id").String(),
+		"state":                "active",
+		"traits":               traits,
+		"verifiable_addresses": []interface{}{},
+		"recovery_addresses":   []interface{}{},
+		"created_at":           now,
+		"updated_at":           now,
+	})
+	if v := gjson.GetBytes(body, "state"); v.Exists() {
+		identity, _ = sjson.SetBytes(identity, "state", v.String())
+	}
+	for _, key := range []string{"metadata_public", "metadata_admin"} {
+		if v := gjson.GetBytes(body, key); v.Exists() {
+			identity, _ = sjson.SetRawBytes(identity, key, []byte(v.Raw))
+		}
+	}
+	return identity
+}
+
+// serveProject serves the admin API of the project with the given slug.
+func (b *Backend) serveProject(w http.ResponseWriter, r *http.Request, slug string) {
+	if _, ok := b.authenticate(w, r); !ok {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.projectBySlug(slug)
+	if p == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("the project %s does not exist", slug))
+		return
+	}
+
+	if r.URL.Path == "/admin/identities" {
+		switch r.Method {
+		case http.MethodGet:
+			listIdentities(w, r, p)
+		case http.MethodPost:
+			createIdentity(w, r, p)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "the fake does not implement "+r.Method+" "+r.URL.Path)
+		}
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/admin/identities/")
+	if id == r.URL.Path || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "the fake does not implement "+r.Method+" "+r.URL.Path)
+		return
+	}
+	k := -1
+	for i, identity := range p.identities {
+		if gjson.GetBytes(identity, "id").String() == id {
+			k = i
+		}
+	}
+	if k < 0 {
+		writeError(w, http.StatusNotFound, "Unable to locate the resource")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, p.identities[k])
+	case http.MethodPut:
+		body, ok := readIdentity(w, r)
+		if !ok {
+			return
+		}
+		identity := p.identities[k]
+		identity, _ = sjson.SetRawBytes(identity, "traits", []byte(gjson.GetBytes(body, "traits").Raw))
+		for _, key := range []string{"state", "schema_id", "metadata_public", "metadata_admin"} {
+			if v := gjson.GetBytes(body, key); v.Exists() {
+				identity, _ = sjson.SetRawBytes(identity, key, []byte(v.Raw))
+			}
+		}
+		identity, _ = sjson.SetBytes(identity, "updated_at", time.Now().UTC().Format(time.RFC3339))
+		p.identities[k] = identity
+		writeJSON(w, http.StatusOK, identity)
+	case http.MethodDelete:
+		p.identities = append(p.identities[:k], p.identities[k+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "the fake does not implement "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// listIdentities lists the identities using the per_page and page query parameters, and optionally filters them by
+// their email address with the credentials_identifier parameter.
+func listIdentities(w http.ResponseWriter, r *http.Request, p *project) {
+	q := r.URL.Query()
+	perPage, page := defaultPerPage, 0
+	if v := q.Get("per_page"); v != "" {
+		perPage, _ = strconv.Atoi(v)
+	}
+	if v := q.Get("page"); v != "" {
+		page, _ = strconv.Atoi(v)
+	}
+	if perPage < 1 || page < 0 {
+		writeError(w, http.StatusBadRequest, "The request was malformed or contained invalid parameters: per_page and page are invalid")
+		return
+	}
+
+	identities := make([]json.RawMessage, 0, len(p.identities))
+	for _, identity := range p.identities {
+		if email := q.Get("credentials_identifier"); email == "" || gjson.GetBytes(identity, "traits.email").String() == email {
+			identities = append(identities, identity)
+		}
+	}
+
+	start, end := page*perPage, (page+1)*perPage
+	if start > len(identities) {
+		start = len(identities)
+	}
+	if end < len(identities) {
+		next := url.Values{"per_page": {strconv.Itoa(perPage)}, "page": {strconv.Itoa(page + 1)}}
+		w.Header().Set("Link", fmt.Sprintf(`</admin/identities?%s>; rel="next"`, next.Encode()))
+	} else {
+		end = len(identities)
+	}
+	writeJSON(w, http.StatusOK, identities[start:end])
+}
+
+// createIdentity creates the identity. The email trait must be unique.
+func createIdentity(w http.ResponseWriter, r *http.Request, p *project) {
+	body, ok := readIdentity(w, r)
+	if !ok {
+		return
+	}
+	if email := gjson.GetBytes(body, "traits.email").String(); email != "" {
+		for _, identity := range p.identities {
+			if gjson.GetBytes(identity, "traits.email").String() == email {
+				writeError(w, http.StatusConflict, "A resource with that value exists already")
+				return
+			}
+		}
+	}
+
+	identity := newIdentity(body, json.RawMessage(gjson.GetBytes(body, "traits").Raw))
+	p.identities = append(p.identities, identity)
+	writeJSON(w, http.StatusCreated, identity)
+}
+
+// readIdentity reads the identity from the request body. Credentials are accepted but never stored or returned.
+func readIdentity(w http.ResponseWriter, r *http.Request) (json.RawMessage, bool) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if !gjson.GetBytes(body, "schema_id").Exists() || !gjson.GetBytes(body, "traits").IsObject() {
+		writeError(w, http.StatusBadRequest, "The request was malformed or contained invalid parameters: schema_id and traits are required")
+		return nil, false
+	}
+	return body, true
+}
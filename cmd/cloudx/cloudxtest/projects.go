@@ -0,0 +1,181 @@
+package cloudxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// serveConsole serves the Console API which manages projects.
+func (b *Backend) serveConsole(w http.ResponseWriter, r *http.Request) {
+	if _, ok := b.authenticate(w, r); !ok {
+		return
+	}
+
+	if r.URL.Path == "/projects" {
+		switch r.Method {
+		case http.MethodGet:
+			b.listProjects(w)
+		case http.MethodPost:
+			b.createProject(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "the fake does not implement "+r.Method+" "+r.URL.Path)
+		}
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/projects/")
+	if id == r.URL.Path || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "the fake does not implement "+r.Method+" "+r.URL.Path)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.project(id)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "Unable to locate the resource")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, p.doc)
+	case http.MethodPut:
+		b.updateProject(w, r, p)
+	case http.MethodPatch:
+		b.patchProject(w, r, p)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "the fake does not implement "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func (b *Backend) listProjects(w http.ResponseWriter) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	list := make([]map[string]interface{}, 0, len(b.projects))
+	for _, p := range b.projects {
+		m := map[string]interface{}{}
+		for _, key := range []string{"id", "name", "slug", "state", "created_at", "updated_at"} {
+			m[key] = gjson.GetBytes(p.doc, key).Value()
+		}
+		list = append(list, m)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (b *Backend) createProject(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || strings.TrimSpace(body.Name) == "" {
+		writeError(w, http.StatusBadRequest, "The request was malformed or contained invalid parameters: the name is required")
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	writeJSON(w, http.StatusCreated, b.addProject(Project{Name: body.Name}).doc)
+}
+
+// updateProject replaces the name and the configuration of the services. It must be called with b.mu held.
+func (b *Backend) updateProject(w http.ResponseWriter, r *http.Request, p *project) {
+	var body struct {
+		Name     string                     `json:"name"`
+		Services map[string]json.RawMessage `json:"services"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	doc := p.doc
+	var err error
+	if body.Name != "" {
+		doc, err = sjson.SetBytes(doc, "name", body.Name)
+	}
+	for service, config := range body.Services {
+		if err == nil {
+			doc, err = sjson.SetRawBytes(doc, "services."+service, config)
+		}
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	b.saveProject(w, p, doc)
+}
+
+// patchProject applies a JSON patch with the add, replace, and remove operations. It must be called with b.mu held.
+func (b *Backend) patchProject(w http.ResponseWriter, r *http.Request, p *project) {
+	var patches []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&patches); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	doc := p.doc
+	for _, patch := range patches {
+		path, err := pointerToPath(patch.Path)
+		if err == nil {
+			switch patch.Op {
+			case "add":
+				doc, err = sjson.SetRawBytes(doc, path, patch.Value)
+			case "replace":
+				if !gjson.GetBytes(doc, path).Exists() {
+					err = fmt.Errorf("the path %s does not exist", patch.Path)
+				} else {
+					doc, err = sjson.SetRawBytes(doc, path, patch.Value)
+				}
+			case "remove":
+				if !gjson.GetBytes(doc, path).Exists() {
+					err = fmt.Errorf("the path %s does not exist", patch.Path)
+				} else {
+					doc, err = sjson.DeleteBytes(doc, path)
+				}
+			default:
+				err = fmt.Errorf("the fake does not implement the operation %q", patch.Op)
+			}
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Unable to apply the JSON patch: %s", err))
+			return
+		}
+	}
+	b.saveProject(w, p, doc)
+}
+
+// saveProject stores the updated project and responds with it.
+func (b *Backend) saveProject(w http.ResponseWriter, p *project, doc []byte) {
+	doc, _ = sjson.SetBytes(doc, "revision_id", newID())
+	doc, _ = sjson.SetBytes(doc, "updated_at", time.Now().UTC().Format(time.RFC3339))
+	p.doc = doc
+	writeJSON(w, http.StatusOK, map[string]interface{}{"project": json.RawMessage(doc), "warnings": []interface{}{}})
+}
+
+// pointerToPath converts a JSON pointer, e.g. /services/identity/config/courier, to an sjson path.
+func pointerToPath(pointer string) (string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return "", fmt.Errorf("the path %q is not a JSON pointer", pointer)
+	}
+	segments := strings.Split(pointer[1:], "/")
+	for k, s := range segments {
+		s = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+		if s == "-" {
+			s = "-1"
+		} else {
+			s = strings.NewReplacer(".", `\.`, "*", `\*`, "?", `\?`).Replace(s)
+		}
+		segments[k] = s
+	}
+	return strings.Join(segments, "."), nil
+}
@@ -0,0 +1,91 @@
+// Package streamtest checks that the CLI streams large responses instead of buffering them. It does not import the
+// client package, so that the tests of the client package can use it as well as the tests of the commands.
+//
+// A fake API holds back the end of the response until the CLI produced its first output:
+//
+//	out := streamtest.NewFirstOutput()
+//	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		// Serve all but the last page, then wait.
+//		if !out.Wait(streamtest.Timeout) {
+//			t.Error("the first item was not printed before the last page was requested")
+//		}
+//		// Serve the last page.
+//	}))
+//
+// A CLI which buffers the response never writes before it got all of it, so Wait times out.
+package streamtest
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Timeout is how long tests wait for the first output by default.
+const Timeout = 10 * time.Second
+
+// FirstOutput records when the CLI produced its first output. It is an io.Writer, e.g. for the stdout of a command,
+// and Done marks output which is not written, e.g. an item passed to a callback. It is safe for concurrent use.
+type FirstOutput struct {
+	once    sync.Once
+	written chan struct{}
+}
+
+func NewFirstOutput() *FirstOutput {
+	return &FirstOutput{written: make(chan struct{})}
+}
+
+// Done marks that the first output was produced. Calling it again has no effect.
+func (o *FirstOutput) Done() {
+	o.once.Do(func() { close(o.written) })
+}
+
+// Write discards p and marks that the first output was produced.
+func (o *FirstOutput) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		o.Done()
+	}
+	return len(p), nil
+}
+
+// Wait waits until the first output was produced and returns false if that did not happen within timeout.
+func (o *FirstOutput) Wait(timeout time.Duration) bool {
+	select {
+	case <-o.written:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// HeapPeak tracks the peak of the live heap above the heap when it was created. The live heap depends on the garbage
+// collector and the race detector, so HeapPeak is meant for benchmarks, not for assertions.
+type HeapPeak struct {
+	baseline, peak uint64
+}
+
+func NewHeapPeak() *HeapPeak {
+	return &HeapPeak{baseline: liveHeap()}
+}
+
+// Sample collects garbage and records the live heap if it is the largest so far.
+func (p *HeapPeak) Sample() {
+	if heap := liveHeap(); heap > p.peak {
+		p.peak = heap
+	}
+}
+
+// Growth returns by how much the peak exceeded the baseline.
+func (p *HeapPeak) Growth() uint64 {
+	if p.peak < p.baseline {
+		return 0
+	}
+	return p.peak - p.baseline
+}
+
+func liveHeap() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
@@ -0,0 +1,89 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+)
+
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the local state of the Ory CLI",
+	}
+	cmd.AddCommand(NewClearCacheCmd())
+	cmd.AddCommand(NewSetEndpointsCmd())
+
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	return cmd
+}
+
+func NewClearCacheCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear-cache",
+		Args:  client.NoArgs,
+		Short: "Remove all cached API responses",
+		Long: `Remove all cached API responses.
+
+Read-only responses which are requested often, e.g. the project list used to resolve project slugs, are cached next
+to the configuration file for a few seconds. Use --no-cache to bypass the cache for a single command.`,
+		Example: `$ ory config clear-cache
+Removed 2 cached responses.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+			n, err := h.ClearCache()
+			if err != nil {
+				return err
+			}
+			h.Log.Infof("Removed %d cached responses.", n)
+			return nil
+		},
+	}
+}
+
+func NewSetEndpointsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-endpoints [FILE]",
+		Args:  client.MaximumNArgs(1),
+		Short: "Store the URLs of self-hosted services",
+		Long: `Store the URLs of self-hosted services in the configuration file, so that --endpoints does not have to be
+set for every command. Run the command without a file to remove them.
+
+The file maps services to the base URLs of their deployments. Services without an endpoint are used through the Ory
+Cloud project, so that e.g. the identity service of a project works together with a self-hosted permission service.
+If all services are self-hosted, the commands which manage projects are not available.`,
+		Example: `$ cat ory-endpoints.yaml
+identity: http://127.0.0.1:4434
+permission: http://127.0.0.1:4466
+oauth2: http://127.0.0.1:4445
+
+$ ory config set-endpoints ory-endpoints.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+			var endpoints client.Endpoints
+			if len(args) == 1 {
+				if endpoints, err = client.ReadEndpoints(cmd, args[0]); err != nil {
+					return err
+				}
+			}
+			if err := h.SetEndpoints(endpoints); err != nil {
+				return err
+			}
+			if len(endpoints) == 0 {
+				h.Log.Infof("Removed the endpoints of self-hosted services.")
+			} else {
+				h.Log.Infof("Using the self-hosted services: %s", endpoints)
+			}
+			return nil
+		},
+	}
+}
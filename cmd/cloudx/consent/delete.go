@@ -0,0 +1,109 @@
+package consent
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+func NewDeleteConsentSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "consent-sessions",
+		Aliases: []string{"consent-session", "consents"},
+		Args:    client.NoArgs,
+		Short:   "Revoke the OAuth2 consent sessions of an identity",
+		Long: `Revoke the OAuth2 consent sessions of an identity in the selected project, which disconnects the OAuth2 clients
+(apps) from the identity. All access and refresh tokens issued to the clients for the identity are invalidated as well.
+
+Use --client to only revoke the consent of a single client, or --interactive to select the clients. The revocation
+needs to be confirmed or requires --yes.`,
+		Example: `$ ory delete consent-sessions --identity jane@example.com --client 3b2f1a0e-1c2d-4e5f-8a9b-0c1d2e3f4a5b --yes
+
+IDENTITY				CLIENT					REVOKED SESSIONS	TOKENS INVALIDATED
+9f425a8d-7efc-4768-8f23-7647a74fdf13	3b2f1a0e-1c2d-4e5f-8a9b-0c1d2e3f4a5b	1			true
+
+$ ory delete consent-sessions --identity jane@example.com --interactive --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewServiceAPI(client.ServiceOAuth2)
+			if err != nil {
+				return err
+			}
+
+			identity, err := identityFromFlag(cmd, api)
+			if err != nil {
+				return err
+			}
+
+			sessions, _, err := listConsentSessions(cmd.Context(), api, identity)
+			if err != nil {
+				return err
+			}
+
+			// An empty client revokes the consent sessions of all clients.
+			clients := []string{flagx.MustGetString(cmd, clientFlag)}
+			if clients[0] == "" && flagx.MustGetBool(cmd, interactiveFlag) && len(sessions) > 0 {
+				clients, err = h.MultiSelect("Select the clients to disconnect:", "pass --client or omit --interactive to revoke the consent of all clients", clientItems(sessions), nil)
+				if err != nil {
+					return err
+				}
+			}
+
+			result := &revocationResult{Identity: identity, Client: strings.Join(clients, ", ")}
+			for _, c := range clients {
+				result.Revoked += countSessions(sessions, c)
+			}
+			if result.Revoked == 0 {
+				client.PrintRow(cmd, result)
+				return nil
+			}
+
+			summaryClients := result.Client
+			if summaryClients == "" {
+				summaryClients = "all"
+			}
+			if err := h.ConfirmDestruction(&client.Destruction{
+				Action: "revoke the consent sessions",
+				Summary: []client.SummaryLine{
+					{Key: "Identity", Value: identity},
+					{Key: "Clients", Value: summaryClients},
+					{Key: "Sessions", Value: fmt.Sprintf("%d", result.Revoked)},
+				},
+			}); err != nil {
+				return err
+			}
+
+			for _, c := range clients {
+				query := url.Values{"subject": {identity}}
+				if c != "" {
+					query.Set("client", c)
+				} else {
+					query.Set("all", "true")
+				}
+				if _, err := api.Do(cmd.Context(), http.MethodDelete, consentSessionsPath, query, nil, nil); err != nil {
+					return err
+				}
+			}
+
+			result.TokensInvalidated = true
+			client.PrintRow(cmd, result)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(identityFlag, "", "The ID or email address of the identity.")
+	cmd.Flags().String(clientFlag, "", "Only revoke the consent of this OAuth2 client.")
+	cmd.Flags().Bool(interactiveFlag, false, "Select the OAuth2 clients to disconnect interactively if --client is not set.")
+	client.RegisterProjectFlag(cmd.Flags())
+	return client.MarkDryRunCapable(cmd)
+}
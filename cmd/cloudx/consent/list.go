@@ -0,0 +1,85 @@
+package consent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+func NewListConsentSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "consent-sessions",
+		Aliases:     []string{"consent-session", "consents"},
+		Args:        client.NoArgs,
+		Short:       "List the OAuth2 consent sessions of an identity",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "consent_request.client.client_id"},
+		Long: `List the OAuth2 consent sessions of an identity in the selected project, i.e. the OAuth2 clients (apps) the identity
+granted access to. The identity is referenced by its ID or email address.`,
+		Example: `$ ory list consent-sessions --identity jane@example.com
+
+CLIENT ID				CLIENT NAME	GRANTED SCOPE		REMEMBER	EXPIRES
+3b2f1a0e-1c2d-4e5f-8a9b-0c1d2e3f4a5b	My App		openid offline_access	true		never
+
+$ ory list consent-sessions --identity 9f425a8d-7efc-4768-8f23-7647a74fdf13 --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewServiceAPI(client.ServiceOAuth2)
+			if err != nil {
+				return err
+			}
+
+			identity, err := identityFromFlag(cmd, api)
+			if err != nil {
+				return err
+			}
+
+			sessions, raw, err := listConsentSessions(cmd.Context(), api, identity)
+			if err != nil {
+				return err
+			}
+
+			client.PrintTable(cmd, &outputConsentSessions{sessions: sessions, raw: raw})
+			return nil
+		},
+	}
+
+	cmd.Flags().String(identityFlag, "", "The ID or email address of the identity.")
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterSortFlags(cmd.Flags())
+	return cmd
+}
+
+func identityFromFlag(cmd *cobra.Command, api *client.ProjectAPI) (string, error) {
+	identity := flagx.MustGetString(cmd, identityFlag)
+	if identity == "" {
+		return "", errors.Errorf("--%s must be set", identityFlag)
+	}
+	return api.ResolveIdentityID(cmd.Context(), identity)
+}
+
+func listConsentSessions(ctx context.Context, api *client.ProjectAPI, identity string) ([]consentSession, []json.RawMessage, error) {
+	var sessions []consentSession
+	var raw []json.RawMessage
+	if _, err := api.Do(ctx, http.MethodGet, consentSessionsPath, url.Values{"subject": {identity}}, nil, client.Items(func(item json.RawMessage) error {
+		var s consentSession
+		if err := json.Unmarshal(item, &s); err != nil {
+			return errors.Wrap(err, "unable to decode consent session")
+		}
+		sessions, raw = append(sessions, s), append(raw, item)
+		return nil
+	})); err != nil {
+		return nil, nil, err
+	}
+	return sessions, raw, nil
+}
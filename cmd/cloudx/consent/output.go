@@ -0,0 +1,77 @@
+package consent
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type outputConsentSessions struct {
+	sessions []consentSession
+	raw      []json.RawMessage
+}
+
+func (*outputConsentSessions) Header() []string {
+	return []string{"CLIENT ID", "CLIENT NAME", "GRANTED SCOPE", "REMEMBER", "EXPIRES"}
+}
+
+func (o *outputConsentSessions) Table() [][]string {
+	rows := make([][]string, len(o.sessions))
+	for k, s := range o.sessions {
+		rows[k] = []string{
+			s.ConsentRequest.Client.ClientID,
+			s.ConsentRequest.Client.ClientName,
+			s.scope(),
+			fmt.Sprintf("%v", s.Remember),
+			s.expiry(),
+		}
+	}
+	return rows
+}
+
+func (*outputConsentSessions) WideHeader() []string {
+	return []string{"CLIENT ID", "CLIENT NAME", "GRANTED SCOPE", "REMEMBER", "EXPIRES", "GRANTED AT", "REMEMBER FOR"}
+}
+
+func (o *outputConsentSessions) WideTable() [][]string {
+	rows := o.Table()
+	for k, s := range o.sessions {
+		var grantedAt string
+		if s.HandledAt != nil {
+			grantedAt = s.HandledAt.Format(time.RFC3339)
+		}
+		rows[k] = append(rows[k], grantedAt, (time.Duration(s.RememberFor) * time.Second).String())
+	}
+	return rows
+}
+
+func (o *outputConsentSessions) Interface() interface{} {
+	return o.raw
+}
+
+func (o *outputConsentSessions) Len() int {
+	return len(o.sessions)
+}
+
+type revocationResult struct {
+	Identity          string `json:"identity"`
+	Client            string `json:"client,omitempty"`
+	Revoked           int    `json:"revoked_sessions"`
+	TokensInvalidated bool   `json:"tokens_invalidated"`
+}
+
+func (*revocationResult) Header() []string {
+	return []string{"IDENTITY", "CLIENT", "REVOKED SESSIONS", "TOKENS INVALIDATED"}
+}
+
+func (r *revocationResult) Columns() []string {
+	client := r.Client
+	if client == "" {
+		client = "all"
+	}
+	return []string{r.Identity, client, fmt.Sprintf("%d", r.Revoked), fmt.Sprintf("%v", r.TokensInvalidated)}
+}
+
+func (r *revocationResult) Interface() interface{} {
+	return r
+}
@@ -0,0 +1,75 @@
+package consent
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ory/cli/cmd/cloudx/client/selector"
+)
+
+const (
+	identityFlag    = "identity"
+	clientFlag      = "client"
+	interactiveFlag = "interactive"
+
+	consentSessionsPath = "/admin/oauth2/auth/sessions/consent"
+)
+
+type consentSession struct {
+	ConsentRequest struct {
+		Client struct {
+			ClientID   string `json:"client_id"`
+			ClientName string `json:"client_name"`
+		} `json:"client"`
+	} `json:"consent_request"`
+	GrantScope  []string   `json:"grant_scope"`
+	Remember    bool       `json:"remember"`
+	RememberFor int64      `json:"remember_for"`
+	HandledAt   *time.Time `json:"handled_at"`
+}
+
+// expiry describes until when the consent is remembered.
+func (s *consentSession) expiry() string {
+	switch {
+	case !s.Remember:
+		return "not remembered"
+	case s.RememberFor == 0:
+		return "never"
+	case s.HandledAt == nil:
+		return (time.Duration(s.RememberFor) * time.Second).String()
+	}
+	return s.HandledAt.Add(time.Duration(s.RememberFor) * time.Second).Format(time.RFC3339)
+}
+
+func (s *consentSession) scope() string {
+	return strings.Join(s.GrantScope, " ")
+}
+
+// countSessions returns the number of sessions which belong to the client, or all sessions if client is empty.
+func countSessions(sessions []consentSession, client string) int {
+	if client == "" {
+		return len(sessions)
+	}
+	var n int
+	for _, s := range sessions {
+		if s.ConsentRequest.Client.ClientID == client {
+			n++
+		}
+	}
+	return n
+}
+
+// clientItems returns the distinct clients of the sessions in the order they first appear.
+func clientItems(sessions []consentSession) []selector.Item {
+	var items []selector.Item
+	seen := map[string]bool{}
+	for _, s := range sessions {
+		c := s.ConsentRequest.Client
+		if seen[c.ClientID] {
+			continue
+		}
+		seen[c.ClientID] = true
+		items = append(items, selector.Item{ID: c.ClientID, Label: c.ClientName})
+	}
+	return items
+}
@@ -0,0 +1,47 @@
+package consent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ory/cli/cmd/cloudx/client/selector"
+)
+
+func TestConsentSessionExpiry(t *testing.T) {
+	handledAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		session  consentSession
+		expected string
+	}{
+		{session: consentSession{Remember: false, RememberFor: 3600}, expected: "not remembered"},
+		{session: consentSession{Remember: true}, expected: "never"},
+		{session: consentSession{Remember: true, RememberFor: 3600}, expected: "1h0m0s"},
+		{session: consentSession{Remember: true, RememberFor: 3600, HandledAt: &handledAt}, expected: "2022-06-01T13:00:00Z"},
+	} {
+		assert.Equal(t, tc.expected, tc.session.expiry())
+	}
+}
+
+func TestCountSessions(t *testing.T) {
+	sessions := make([]consentSession, 3)
+	sessions[0].ConsentRequest.Client.ClientID = "a"
+	sessions[1].ConsentRequest.Client.ClientID = "b"
+	sessions[2].ConsentRequest.Client.ClientID = "a"
+
+	assert.Equal(t, 3, countSessions(sessions, ""))
+	assert.Equal(t, 2, countSessions(sessions, "a"))
+	assert.Equal(t, 0, countSessions(sessions, "c"))
+}
+
+func TestClientItems(t *testing.T) {
+	sessions := make([]consentSession, 3)
+	sessions[0].ConsentRequest.Client.ClientID = "a"
+	sessions[0].ConsentRequest.Client.ClientName = "App"
+	sessions[1].ConsentRequest.Client.ClientID = "b"
+	sessions[2].ConsentRequest.Client.ClientID = "a"
+
+	assert.Equal(t, []selector.Item{{ID: "a", Label: "App"}, {ID: "b"}}, clientItems(sessions))
+	assert.Empty(t, clientItems(nil))
+}
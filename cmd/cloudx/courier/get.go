@@ -0,0 +1,84 @@
+package courier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const includeBodyFlag = "include-body"
+
+func NewGetCourierMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "courier-message <id>",
+		Args:        client.ExactArgs(1, client.UUID("a courier message ID")),
+		Short:       "Get a message from the courier queue of an Ory Cloud project",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "status"},
+		Long: `Get a message sent or queued by the courier of the selected project, including all attempts to send it
+and the errors (e.g. SMTP errors) of failed attempts.
+
+The rendered subject and body are only shown when --include-body is set. Because they may contain secrets such as
+recovery links or one-time codes, you need to confirm this or use --yes.`,
+		Example: `$ ory get courier-message b6c6f3a1-6a1e-4a7e-8c8c-1b7e4b1c0f3e
+
+ID		b6c6f3a1-6a1e-4a7e-8c8c-1b7e4b1c0f3e
+TYPE		verification_valid
+RECIPIENT	jane@example.com
+STATUS		abandoned
+SEND COUNT	3
+LAST ERROR	dial tcp 10.0.0.1:25: connect: connection refused
+...
+
+$ ory get courier-message b6c6f3a1-6a1e-4a7e-8c8c-1b7e4b1c0f3e --project my-project --include-body --yes --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			includeBody := flagx.MustGetBool(cmd, includeBodyFlag)
+			if includeBody {
+				if err := h.ConfirmOrAbort("The message body may contain secrets such as recovery links or codes. Do you want to show it?"); err != nil {
+					return err
+				}
+			}
+
+			api, err := h.NewServiceAPI(client.ServiceIdentity)
+			if err != nil {
+				return err
+			}
+
+			var raw json.RawMessage
+			if _, err := api.Do(cmd.Context(), http.MethodGet, "/admin/courier/messages/"+url.PathEscape(args[0]), nil, nil, &raw); err != nil {
+				return err
+			}
+
+			m, err := decodeMessage(raw)
+			if err != nil {
+				return err
+			}
+			if !includeBody {
+				if m.raw, err = withoutBody(m.raw); err != nil {
+					return err
+				}
+			}
+
+			client.PrintRow(cmd, &outputMessage{message: m, includeBody: includeBody})
+			if !client.IsMachineReadableFormat(cmd) && len(m.Dispatches) > 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout())
+				client.PrintTable(cmd, &outputDispatchCollection{dispatches: m.Dispatches})
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(includeBodyFlag, false, "Include the rendered subject and body of the message.")
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
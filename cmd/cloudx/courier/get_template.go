@@ -0,0 +1,100 @@
+package courier
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+func NewGetEmailTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "email-template",
+		Aliases: []string{"email-templates"},
+		Args:    client.NoArgs,
+		Short:   "Download a custom email template of an Ory Cloud project",
+		Long: `Download the subject and body of a custom email template of the selected project to files.
+
+Use --subject, --body-html, and --body-text to choose the files. If not set, the files are written to the current
+directory and named after the template type. Existing files are only overwritten after confirmation or when using --yes.`,
+		Example: `$ ory get email-template --type recovery_code.valid
+
+TYPE			PART		FILE
+recovery_code.valid	subject		recovery_code.valid.subject.gotmpl
+recovery_code.valid	html body	recovery_code.valid.body.html.gotmpl
+recovery_code.valid	plaintext body	recovery_code.valid.body.txt.gotmpl
+
+$ ory get email-template --type verification_code.valid --project my-project \
+	--subject ./subject.gotmpl --body-html ./body.html.gotmpl --body-text ./body.txt.gotmpl --yes --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			templateType := flagx.MustGetString(cmd, templateTypeFlag)
+			if _, _, err := templateConfigPath(templateType); err != nil {
+				return err
+			}
+
+			id, err := h.ProjectID()
+			if err != nil {
+				return err
+			}
+
+			p, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			t, ok, err := configuredTemplate(p.Services.Identity.Config, templateType)
+			if err != nil {
+				return err
+			} else if !ok {
+				return errors.Errorf("the project uses the default template for %s", templateType)
+			}
+
+			out := &outputTemplateFiles{Type: templateType}
+			for _, part := range []struct {
+				flag, name, suffix, contents string
+			}{
+				{flag: subjectFileFlag, name: "subject", suffix: "subject.gotmpl", contents: t.Subject},
+				{flag: bodyHTMLFileFlag, name: "html body", suffix: "body.html.gotmpl", contents: t.BodyHTML},
+				{flag: bodyTextFileFlag, name: "plaintext body", suffix: "body.txt.gotmpl", contents: t.BodyPlain},
+			} {
+				if part.contents == "" {
+					continue
+				}
+
+				file := flagx.MustGetString(cmd, part.flag)
+				if file == "" {
+					file = templateType + "." + part.suffix
+				}
+
+				if _, err := os.Stat(file); err == nil {
+					ok, err := h.Confirm(fmt.Sprintf("File %s exists already. Do you want to overwrite it?", file))
+					if err != nil {
+						return err
+					} else if !ok {
+						continue
+					}
+				}
+
+				if err := os.WriteFile(file, []byte(part.contents), 0600); err != nil {
+					return errors.Wrapf(err, "unable to write file: %s", file)
+				}
+				out.Files = append(out.Files, outputTemplateFile{Part: part.name, File: file})
+			}
+
+			client.PrintTable(cmd, out)
+			return nil
+		},
+	}
+
+	registerTemplateFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,169 @@
+package courier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	statusFlag    = "status"
+	recipientFlag = "recipient"
+	sinceFlag     = "since"
+	pageSizeFlag  = "page-size"
+	pageTokenFlag = "page-token"
+	allFlag       = "all"
+)
+
+func NewListCourierMessagesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "courier-messages",
+		Aliases:     []string{"courier-message", "messages"},
+		Args:        client.NoArgs,
+		Short:       "List the messages in the courier queue of an Ory Cloud project",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "recipient"},
+		Long: `List the messages (e.g. verification and recovery emails) sent or queued by the courier of the selected project.
+
+Use --status and --recipient to narrow down the list. If the API does not support filtering by recipient, the
+filter is applied to the fetched page instead and a notice is printed. Use --since to only show messages created
+in the given duration, e.g. --since 1h.
+
+If more messages are available, the token of the next page is printed and can be passed using --page-token. The
+API does not support ordering, so --sort only sorts the fetched page.
+
+Use --all to fetch all pages. The messages of each page are printed as soon as the page arrives, so that the memory
+usage stays flat and pipes can process the output right away. Use --format ndjson to print one message per line.
+Sorting and selecting columns require all messages and print them at the end.`,
+		Example: `$ ory list courier-messages --status queued --recipient jane@example.com
+
+ID					TYPE			RECIPIENT		STATUS	CREATED AT
+b6c6f3a1-6a1e-4a7e-8c8c-1b7e4b1c0f3e	verification_valid	jane@example.com	queued	2022-06-01T12:00:00Z
+
+$ ory list courier-messages --since 1h --project my-project --format json
+
+$ ory list courier-messages --all --status abandoned --format ndjson | jq -r .recipient`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			status := flagx.MustGetString(cmd, statusFlag)
+			if err := validateStatus(status); err != nil {
+				return err
+			}
+
+			var since time.Time
+			if d := flagx.MustGetDuration(cmd, sinceFlag); d > 0 {
+				since = time.Now().Add(-d)
+			} else if d < 0 {
+				return errors.Errorf("--%s must not be negative", sinceFlag)
+			}
+
+			api, err := h.NewServiceAPI(client.ServiceIdentity)
+			if err != nil {
+				return err
+			}
+
+			recipient := flagx.MustGetString(cmd, recipientFlag)
+			query := url.Values{"page_size": {fmt.Sprintf("%d", flagx.MustGetInt(cmd, pageSizeFlag))}}
+			if status != "" {
+				query.Set("status", status)
+			}
+			if recipient != "" {
+				query.Set("recipient", recipient)
+			}
+			if token := flagx.MustGetString(cmd, pageTokenFlag); token != "" {
+				query.Set("page_token", token)
+			}
+
+			all := flagx.MustGetBool(cmd, allFlag)
+			var stream *client.TableStream
+			if all && client.CanStreamOutput(cmd) {
+				stream = client.NewTableStream(cmd)
+			}
+
+			var collected []*message
+			var next string
+			for warned, pages, fetched := false, 0, 0; ; pages++ {
+				// The messages are decoded one by one, and the ones which do not match the filters are dropped right away.
+				var messages []*message
+				var clientSide bool
+				res, err := api.Do(cmd.Context(), http.MethodGet, "/admin/courier/messages", query, nil, client.Items(func(item json.RawMessage) error {
+					m, err := decodeMessage(item)
+					if err != nil {
+						return err
+					}
+					keep, removed := matchMessage(m, recipient, since)
+					clientSide = clientSide || removed
+					if keep {
+						messages = append(messages, m)
+					}
+					return nil
+				}))
+				if err != nil && pages > 0 {
+					// Print what was fetched before the error or the interrupt, and where to continue.
+					if stream != nil {
+						stream.Close()
+					} else {
+						client.PrintTable(cmd, &outputMessageCollection{messages: collected})
+					}
+					h.Log.Warnf("Stopped after %d pages (%d messages), use --%s %s to continue.", pages, fetched, pageTokenFlag, query.Get("page_token"))
+					return err
+				} else if err != nil {
+					return err
+				}
+
+				if clientSide && !warned {
+					h.Log.Warnf("The API does not support filtering by recipient, the filter was applied to the fetched pages only.")
+					warned = true
+				}
+
+				fetched += len(messages)
+				if stream != nil {
+					stream.Print(&outputMessageCollection{messages: messages})
+				} else {
+					collected = append(collected, messages...)
+				}
+
+				next = nextPageToken(res)
+				if !all || next == "" {
+					break
+				}
+				query.Set("page_token", next)
+			}
+
+			if stream != nil {
+				stream.Close()
+				return nil
+			}
+			client.PrintTable(cmd, &outputMessageCollection{messages: collected})
+
+			if next != "" && !all {
+				h.Log.Infof("\nMore messages are available, use --%s %s to fetch the next page.", pageTokenFlag, next)
+				if client.SortKey(cmd) != "" {
+					h.Log.Infof("Only the messages of this page were sorted.")
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(statusFlag, "", "Only list messages with this status (queued, sent, processing, abandoned).")
+	cmd.Flags().String(recipientFlag, "", "Only list messages sent to this recipient.")
+	cmd.Flags().Duration(sinceFlag, 0, "Only list messages created within this duration, e.g. 1h.")
+	cmd.Flags().Int(pageSizeFlag, 100, "The number of messages to fetch.")
+	cmd.Flags().String(pageTokenFlag, "", "The token of the page to fetch.")
+	cmd.Flags().Bool(allFlag, false, "Fetch all pages. Each page is printed as soon as it arrives unless --sort or --columns are set.")
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterSortFlags(cmd.Flags())
+	return cmd
+}
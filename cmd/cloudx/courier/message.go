@@ -0,0 +1,109 @@
+package courier
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+type message struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	TemplateType string     `json:"template_type"`
+	Recipient    string     `json:"recipient"`
+	Status       string     `json:"status"`
+	Subject      string     `json:"subject"`
+	Body         string     `json:"body"`
+	SendCount    int        `json:"send_count"`
+	Dispatches   []dispatch `json:"dispatches"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// raw contains the message as returned by the API so that JSON output includes all fields.
+	raw json.RawMessage
+}
+
+// dispatch is a single attempt of the courier to send a message.
+type dispatch struct {
+	ID        string          `json:"id"`
+	Status    string          `json:"status"`
+	Error     json.RawMessage `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// errorMessage returns a human readable representation of the (e.g. SMTP) error of the attempt.
+func (d *dispatch) errorMessage() string {
+	if len(d.Error) == 0 || string(d.Error) == "null" || string(d.Error) == "{}" {
+		return ""
+	}
+	for _, path := range []string{"message", "reason", "error"} {
+		if m := gjson.GetBytes(d.Error, path); m.Type == gjson.String && m.String() != "" {
+			return m.String()
+		}
+	}
+	return string(d.Error)
+}
+
+// bodyFields are the fields of a message which contain the rendered template. They may contain
+// secrets such as recovery links or one-time codes.
+var bodyFields = []string{"subject", "body"}
+
+// withoutBody removes the rendered subject and body from the raw message.
+func withoutBody(raw json.RawMessage) (json.RawMessage, error) {
+	for _, field := range bodyFields {
+		var err error
+		raw, err = sjson.DeleteBytes(raw, field)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return raw, nil
+}
+
+var validStatuses = []string{"queued", "sent", "processing", "abandoned"}
+
+func validateStatus(status string) error {
+	if status == "" {
+		return nil
+	}
+	for _, s := range validStatuses {
+		if s == status {
+			return nil
+		}
+	}
+	return errors.Errorf("unknown status %q, expected one of: %s", status, strings.Join(validStatuses, ", "))
+}
+
+func decodeMessage(raw json.RawMessage) (*message, error) {
+	var m message
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, errors.Wrap(err, "unable to decode courier message")
+	}
+	m.raw = raw
+	return &m, nil
+}
+
+// matchMessage returns false if m was created before since (if not zero) or was not sent to recipient (if not
+// empty). It also returns true if the recipient filter removed the message, which means that the server did not
+// apply it.
+func matchMessage(m *message, recipient string, since time.Time) (keep, clientSideRecipient bool) {
+	if !since.IsZero() && m.CreatedAt.Before(since) {
+		return false, false
+	}
+	if recipient != "" && !strings.EqualFold(m.Recipient, recipient) {
+		return false, true
+	}
+	return true, false
+}
+
+// nextPageToken extracts the token of the next page from the response's Link header.
+func nextPageToken(res *http.Response) string {
+	return client.NextPageQuery(res).Get("page_token")
+}
@@ -0,0 +1,79 @@
+package courier
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchMessage(t *testing.T) {
+	now := time.Now()
+	messages := []*message{
+		{ID: "1", Recipient: "jane@example.com", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "2", Recipient: "Jane@example.com", CreatedAt: now.Add(-time.Minute)},
+		{ID: "3", Recipient: "john@example.com", CreatedAt: now.Add(-time.Minute)},
+	}
+
+	filterMessages := func(messages []*message, recipient string, since time.Time) (ids []string, clientSide bool) {
+		for _, m := range messages {
+			keep, removed := matchMessage(m, recipient, since)
+			clientSide = clientSide || removed
+			if keep {
+				ids = append(ids, m.ID)
+			}
+		}
+		return
+	}
+
+	filtered, clientSide := filterMessages(messages, "", time.Time{})
+	assert.Equal(t, []string{"1", "2", "3"}, filtered)
+	assert.False(t, clientSide)
+
+	filtered, clientSide = filterMessages(messages, "jane@example.com", time.Time{})
+	assert.Equal(t, []string{"1", "2"}, filtered)
+	assert.True(t, clientSide)
+
+	filtered, _ = filterMessages(messages, "jane@example.com", now.Add(-time.Hour))
+	assert.Equal(t, []string{"2"}, filtered)
+}
+
+func TestNextPageToken(t *testing.T) {
+	res := &http.Response{Header: http.Header{}}
+	assert.Empty(t, nextPageToken(res))
+
+	res.Header.Set("Link", `</admin/courier/messages?page_size=100&page_token=abc>; rel="first",</admin/courier/messages?page_size=100&page_token=def>; rel="next"`)
+	assert.Equal(t, "def", nextPageToken(res))
+}
+
+func TestValidateStatus(t *testing.T) {
+	require.NoError(t, validateStatus(""))
+	require.NoError(t, validateStatus("queued"))
+	require.Error(t, validateStatus("delivered"))
+}
+
+func TestWithoutBody(t *testing.T) {
+	raw, err := withoutBody([]byte(`{"id":"1","subject":"Recover your account","body":"Use code 123456","dispatches":[]}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"1","dispatches":[]}`, string(raw))
+}
+
+func TestDispatchErrorMessage(t *testing.T) {
+	for _, tc := range []struct {
+		raw, expected string
+	}{
+		{raw: ``, expected: ""},
+		{raw: `null`, expected: ""},
+		{raw: `{}`, expected: ""},
+		{raw: `{"message":"dial tcp: connection refused"}`, expected: "dial tcp: connection refused"},
+		{raw: `{"reason":"550 mailbox unavailable"}`, expected: "550 mailbox unavailable"},
+		{raw: `{"code":550}`, expected: `{"code":550}`},
+	} {
+		t.Run("case="+tc.raw, func(t *testing.T) {
+			d := dispatch{Error: []byte(tc.raw)}
+			assert.Equal(t, tc.expected, d.errorMessage())
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package courier
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type outputMessageCollection struct {
+	messages []*message
+}
+
+func (*outputMessageCollection) Header() []string {
+	return []string{"ID", "TYPE", "RECIPIENT", "STATUS", "CREATED AT"}
+}
+
+func (c *outputMessageCollection) Table() [][]string {
+	rows := make([][]string, len(c.messages))
+	for i, m := range c.messages {
+		rows[i] = []string{
+			m.ID,
+			m.TemplateType,
+			m.Recipient,
+			m.Status,
+			m.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return rows
+}
+
+func (*outputMessageCollection) WideHeader() []string {
+	return []string{"ID", "TYPE", "RECIPIENT", "STATUS", "CREATED AT", "UPDATED AT", "CHANNEL", "SEND COUNT", "SUBJECT"}
+}
+
+func (c *outputMessageCollection) WideTable() [][]string {
+	rows := make([][]string, len(c.messages))
+	for i, m := range c.messages {
+		rows[i] = []string{
+			m.ID,
+			m.TemplateType,
+			m.Recipient,
+			m.Status,
+			m.CreatedAt.Format(time.RFC3339),
+			m.UpdatedAt.Format(time.RFC3339),
+			m.Type,
+			fmt.Sprintf("%d", m.SendCount),
+			m.Subject,
+		}
+	}
+	return rows
+}
+
+func (c *outputMessageCollection) Interface() interface{} {
+	raw := make([]json.RawMessage, len(c.messages))
+	for i, m := range c.messages {
+		raw[i] = m.raw
+	}
+	return raw
+}
+
+func (c *outputMessageCollection) Len() int {
+	return len(c.messages)
+}
+
+type outputMessage struct {
+	message     *message
+	includeBody bool
+}
+
+func (o *outputMessage) Header() []string {
+	h := []string{"ID", "TYPE", "RECIPIENT", "STATUS", "SEND COUNT", "LAST ERROR", "CREATED AT", "UPDATED AT"}
+	if o.includeBody {
+		h = append(h, "SUBJECT", "BODY")
+	}
+	return h
+}
+
+func (o *outputMessage) Columns() []string {
+	m := o.message
+	var lastError string
+	for k := len(m.Dispatches) - 1; k >= 0; k-- {
+		if e := m.Dispatches[k].errorMessage(); e != "" {
+			lastError = e
+			break
+		}
+	}
+
+	c := []string{
+		m.ID,
+		m.TemplateType,
+		m.Recipient,
+		m.Status,
+		fmt.Sprintf("%d", m.SendCount),
+		lastError,
+		m.CreatedAt.Format(time.RFC3339),
+		m.UpdatedAt.Format(time.RFC3339),
+	}
+	if o.includeBody {
+		c = append(c, m.Subject, m.Body)
+	}
+	return c
+}
+
+func (o *outputMessage) Interface() interface{} {
+	return o.message.raw
+}
+
+type outputDispatchCollection struct {
+	dispatches []dispatch
+}
+
+func (*outputDispatchCollection) Header() []string {
+	return []string{"ATTEMPT", "STATUS", "ERROR", "CREATED AT"}
+}
+
+func (c *outputDispatchCollection) Table() [][]string {
+	rows := make([][]string, len(c.dispatches))
+	for i, d := range c.dispatches {
+		rows[i] = []string{
+			fmt.Sprintf("%d", i+1),
+			d.Status,
+			d.errorMessage(),
+			d.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return rows
+}
+
+func (c *outputDispatchCollection) Interface() interface{} {
+	return c.dispatches
+}
+
+func (c *outputDispatchCollection) Len() int {
+	return len(c.dispatches)
+}
+
+// outputTemplateFiles lists the files an email template was read from or written to.
+type outputTemplateFiles struct {
+	Type  string               `json:"type"`
+	Files []outputTemplateFile `json:"files"`
+}
+
+type outputTemplateFile struct {
+	Part string `json:"part"`
+	File string `json:"file"`
+}
+
+func (*outputTemplateFiles) Header() []string {
+	return []string{"TYPE", "PART", "FILE"}
+}
+
+func (o *outputTemplateFiles) Table() [][]string {
+	rows := make([][]string, len(o.Files))
+	for i, f := range o.Files {
+		rows[i] = []string{o.Type, f.Part, f.File}
+	}
+	return rows
+}
+
+func (o *outputTemplateFiles) Interface() interface{} {
+	return o
+}
+
+func (o *outputTemplateFiles) Len() int {
+	return len(o.Files)
+}
@@ -0,0 +1,231 @@
+package courier
+
+import (
+	"encoding/base64"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+)
+
+type (
+	sampleIdentity map[string]interface{}
+
+	recoveryValidModel struct {
+		To          string
+		RecoveryURL string
+		Identity    sampleIdentity
+	}
+	recoveryCodeValidModel struct {
+		To           string
+		RecoveryCode string
+		Identity     sampleIdentity
+	}
+	verificationValidModel struct {
+		To              string
+		VerificationURL string
+		Identity        sampleIdentity
+	}
+	verificationCodeValidModel struct {
+		To               string
+		VerificationURL  string
+		VerificationCode string
+		Identity         sampleIdentity
+	}
+	invalidModel struct {
+		To string
+	}
+
+	// emailTemplate contains the (decoded) parts of a courier email template.
+	emailTemplate struct {
+		Subject   string
+		BodyHTML  string
+		BodyPlain string
+	}
+)
+
+func newSampleIdentity() sampleIdentity {
+	return sampleIdentity{
+		"id":        "9f425a8d-7efc-4768-8f23-7647a74fdf13",
+		"schema_id": "default",
+		"state":     "active",
+		"traits": map[string]interface{}{
+			"email": "jane@example.com",
+		},
+	}
+}
+
+// templateModels contains a sample of the data the courier renders each template type with.
+var templateModels = map[string]func() interface{}{
+	"recovery.valid": func() interface{} {
+		return &recoveryValidModel{To: "jane@example.com", RecoveryURL: "https://example.com/recovery?token=sample", Identity: newSampleIdentity()}
+	},
+	"recovery.invalid": func() interface{} { return &invalidModel{To: "jane@example.com"} },
+	"recovery_code.valid": func() interface{} {
+		return &recoveryCodeValidModel{To: "jane@example.com", RecoveryCode: "123456", Identity: newSampleIdentity()}
+	},
+	"recovery_code.invalid": func() interface{} { return &invalidModel{To: "jane@example.com"} },
+	"verification.valid": func() interface{} {
+		return &verificationValidModel{To: "jane@example.com", VerificationURL: "https://example.com/verification?token=sample", Identity: newSampleIdentity()}
+	},
+	"verification.invalid": func() interface{} { return &invalidModel{To: "jane@example.com"} },
+	"verification_code.valid": func() interface{} {
+		return &verificationCodeValidModel{To: "jane@example.com", VerificationURL: "https://example.com/verification?code=123456", VerificationCode: "123456", Identity: newSampleIdentity()}
+	},
+	"verification_code.invalid": func() interface{} { return &invalidModel{To: "jane@example.com"} },
+}
+
+func templateTypes() []string {
+	types := make([]string, 0, len(templateModels))
+	for t := range templateModels {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// templateConfigPath returns the template name and its status (valid or invalid) in the courier config.
+func templateConfigPath(templateType string) (string, string, error) {
+	if _, ok := templateModels[templateType]; !ok {
+		return "", "", errors.Errorf("unknown template type %q, expected one of: %s", templateType, strings.Join(templateTypes(), ", "))
+	}
+	parts := strings.SplitN(templateType, ".", 2)
+	return parts[0], parts[1], nil
+}
+
+// validate executes the parts of the template against sample data of the template type.
+func (t *emailTemplate) validate(templateType string) error {
+	model, ok := templateModels[templateType]
+	if !ok {
+		_, _, err := templateConfigPath(templateType)
+		return err
+	}
+
+	for _, part := range []struct {
+		name, text string
+	}{
+		{name: "subject", text: t.Subject},
+		{name: "plaintext body", text: t.BodyPlain},
+	} {
+		if part.text == "" {
+			continue
+		}
+		tpl, err := template.New(part.name).Funcs(sprig.HermeticTxtFuncMap()).Parse(part.text)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse the %s template", part.name)
+		}
+		if err := tpl.Execute(io.Discard, model()); err != nil {
+			return errors.Wrapf(err, "unable to render the %s template", part.name)
+		}
+	}
+
+	if t.BodyHTML != "" {
+		tpl, err := htmltemplate.New("html body").Funcs(sprig.HermeticHtmlFuncMap()).Parse(t.BodyHTML)
+		if err != nil {
+			return errors.Wrap(err, "unable to parse the html body template")
+		}
+		if err := tpl.Execute(io.Discard, model()); err != nil {
+			return errors.Wrap(err, "unable to render the html body template")
+		}
+	}
+	return nil
+}
+
+func encodeTemplatePart(part string) string {
+	return "base64://" + base64.StdEncoding.EncodeToString([]byte(part))
+}
+
+func decodeTemplatePart(name string, value interface{}) (string, error) {
+	s, _ := value.(string)
+	if s == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(s, "base64://") {
+		return "", errors.Errorf("the %s template is loaded from %q and can not be downloaded", name, s)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, "base64://"))
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to decode the %s template", name)
+	}
+	return string(decoded), nil
+}
+
+func lookup(config map[string]interface{}, path ...string) map[string]interface{} {
+	for _, p := range path {
+		next, ok := config[p].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		config = next
+	}
+	return config
+}
+
+// configuredTemplate returns the template of the given type configured in the identity config. It returns
+// false if the project uses the default template.
+func configuredTemplate(config map[string]interface{}, templateType string) (*emailTemplate, bool, error) {
+	name, status, err := templateConfigPath(templateType)
+	if err != nil {
+		return nil, false, err
+	}
+
+	email := lookup(config, "courier", "templates", name, status, "email")
+	if email == nil {
+		return nil, false, nil
+	}
+
+	var t emailTemplate
+	if t.Subject, err = decodeTemplatePart("subject", email["subject"]); err != nil {
+		return nil, false, err
+	}
+	body := lookup(email, "body")
+	if body != nil {
+		if t.BodyHTML, err = decodeTemplatePart("html body", body["html"]); err != nil {
+			return nil, false, err
+		}
+		if t.BodyPlain, err = decodeTemplatePart("plaintext body", body["plaintext"]); err != nil {
+			return nil, false, err
+		}
+	}
+	return &t, t != emailTemplate{}, nil
+}
+
+// withTemplate returns the courier templates config with the template of the given type replaced. Parts of the
+// template which are empty are kept as configured.
+func withTemplate(config map[string]interface{}, templateType string, t *emailTemplate) (map[string]interface{}, error) {
+	name, status, err := templateConfigPath(templateType)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := lookup(config, "courier", "templates")
+	if templates == nil {
+		templates = map[string]interface{}{}
+	}
+
+	ensure := func(parent map[string]interface{}, key string) map[string]interface{} {
+		next, ok := parent[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			parent[key] = next
+		}
+		return next
+	}
+
+	email := ensure(ensure(ensure(templates, name), status), "email")
+	body := ensure(email, "body")
+	if t.Subject != "" {
+		email["subject"] = encodeTemplatePart(t.Subject)
+	}
+	if t.BodyHTML != "" {
+		body["html"] = encodeTemplatePart(t.BodyHTML)
+	}
+	if t.BodyPlain != "" {
+		body["plaintext"] = encodeTemplatePart(t.BodyPlain)
+	}
+	return templates, nil
+}
@@ -0,0 +1,95 @@
+package courier
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateConfigPath(t *testing.T) {
+	name, status, err := templateConfigPath("recovery_code.valid")
+	require.NoError(t, err)
+	assert.Equal(t, "recovery_code", name)
+	assert.Equal(t, "valid", status)
+
+	_, _, err = templateConfigPath("recovery_code")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "verification_code.invalid")
+}
+
+func TestValidateEmailTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		name, templateType string
+		template           emailTemplate
+		err                string
+	}{
+		{
+			name:         "valid",
+			templateType: "recovery_code.valid",
+			template: emailTemplate{
+				Subject:   "Your recovery code",
+				BodyHTML:  `<p>Hi {{ .Identity.traits.email }}, your code is {{ .RecoveryCode }}</p>`,
+				BodyPlain: `Your code is {{ .RecoveryCode }}`,
+			},
+		},
+		{
+			name:         "syntax error",
+			templateType: "recovery_code.valid",
+			template:     emailTemplate{Subject: "{{ .RecoveryCode"},
+			err:          "unable to parse the subject template",
+		},
+		{
+			name:         "unknown field",
+			templateType: "recovery_code.valid",
+			template:     emailTemplate{BodyPlain: "{{ .RecoveryURL }}"},
+			err:          "unable to render the plaintext body template",
+		},
+		{
+			name:         "field of other template type",
+			templateType: "recovery_code.invalid",
+			template:     emailTemplate{BodyHTML: "{{ .RecoveryCode }}"},
+			err:          "unable to render the html body template",
+		},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			err := tc.template.validate(tc.templateType)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestTemplateConfig(t *testing.T) {
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"courier":{"templates":{"recovery":{"valid":{"email":{"subject":"base64://b2xk"}}}}}}`), &config))
+
+	_, ok, err := configuredTemplate(config, "recovery_code.valid")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	templates, err := withTemplate(config, "recovery_code.valid", &emailTemplate{Subject: "subject", BodyPlain: "body"})
+	require.NoError(t, err)
+	out, err := json.Marshal(templates)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "recovery": {"valid": {"email": {"subject": "base64://b2xk"}}},
+  "recovery_code": {"valid": {"email": {"subject": "base64://c3ViamVjdA==", "body": {"plaintext": "base64://Ym9keQ=="}}}}
+}`, string(out))
+
+	config = map[string]interface{}{"courier": map[string]interface{}{"templates": templates}}
+	actual, ok, err := configuredTemplate(config, "recovery_code.valid")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, &emailTemplate{Subject: "subject", BodyPlain: "body"}, actual)
+
+	actual, ok, err = configuredTemplate(config, "recovery.valid")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "old", actual.Subject)
+}
@@ -0,0 +1,120 @@
+package courier
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	templateTypeFlag   = "type"
+	subjectFileFlag    = "subject"
+	bodyHTMLFileFlag   = "body-html"
+	bodyTextFileFlag   = "body-text"
+	skipValidationFlag = "skip-validation"
+)
+
+func NewUpdateEmailTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "email-template",
+		Aliases: []string{"email-templates"},
+		Args:    client.NoArgs,
+		Short:   "Upload a custom email template to an Ory Cloud project",
+		Long: `Upload the subject and body of a custom email template (e.g. the recovery code email) to the selected project.
+
+The files use the Go template syntax. Before uploading, each file is rendered with sample data of the template type
+(e.g. the recovery code and identity traits) to find syntax errors and unknown fields. Use --skip-validation to upload
+the files anyway. Parts of the template which are not passed are kept as they are.`,
+		Example: `$ ory update email-template --type recovery_code.valid \
+	--subject ./subject.gotmpl \
+	--body-html ./body.html.gotmpl \
+	--body-text ./body.txt.gotmpl
+
+$ ory update email-template --type verification_code.valid --subject ./subject.gotmpl --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			templateType := flagx.MustGetString(cmd, templateTypeFlag)
+			if _, _, err := templateConfigPath(templateType); err != nil {
+				return err
+			}
+
+			var t emailTemplate
+			out := &outputTemplateFiles{Type: templateType}
+			for _, part := range []struct {
+				flag, name string
+				dest       *string
+			}{
+				{flag: subjectFileFlag, name: "subject", dest: &t.Subject},
+				{flag: bodyHTMLFileFlag, name: "html body", dest: &t.BodyHTML},
+				{flag: bodyTextFileFlag, name: "plaintext body", dest: &t.BodyPlain},
+			} {
+				file := flagx.MustGetString(cmd, part.flag)
+				if file == "" {
+					continue
+				}
+				contents, err := client.ReadInputFile(cmd, file)
+				if err != nil {
+					return err
+				}
+				*part.dest = string(contents)
+				out.Files = append(out.Files, outputTemplateFile{Part: part.name, File: file})
+			}
+			if len(out.Files) == 0 {
+				return errors.Errorf("at least one of --%s, --%s, or --%s must be set", subjectFileFlag, bodyHTMLFileFlag, bodyTextFileFlag)
+			}
+
+			if !flagx.MustGetBool(cmd, skipValidationFlag) {
+				if err := t.validate(templateType); err != nil {
+					return client.NewValidationError(errors.Wrapf(err, "the template is invalid, use --%s to upload it anyway", skipValidationFlag), nil)
+				}
+			}
+
+			id, err := h.ProjectID()
+			if err != nil {
+				return err
+			}
+
+			p, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			templates, err := withTemplate(p.Services.Identity.Config, templateType, &t)
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(templates)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			res, err := h.PatchProject(id, nil, []string{"/services/identity/config/courier/templates=" + string(value)}, nil, nil)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			client.PrintTable(cmd, out)
+			return h.PrintUpdateProjectWarnings(res)
+		},
+	}
+
+	registerTemplateFlags(cmd)
+	cmd.Flags().Bool(skipValidationFlag, false, "Upload the template without rendering it with sample data first.")
+	return client.MarkDryRunCapable(cmd)
+}
+
+func registerTemplateFlags(cmd *cobra.Command) {
+	cmd.Flags().String(templateTypeFlag, "", "The type of the template, e.g. recovery_code.valid.")
+	cmd.Flags().String(subjectFileFlag, "", "Path to the subject template. Use - to read from stdin.")
+	cmd.Flags().String(bodyHTMLFileFlag, "", "Path to the HTML body template. Use - to read from stdin.")
+	cmd.Flags().String(bodyTextFileFlag, "", "Path to the plaintext body template. Use - to read from stdin.")
+	client.RegisterProjectFlag(cmd.Flags())
+}
@@ -3,8 +3,11 @@ package cloudx
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ory/cli/cmd/cloudx/action"
 	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/identity"
 	"github.com/ory/cli/cmd/cloudx/project"
+	"github.com/ory/cli/cmd/cloudx/selfservice"
 	"github.com/ory/x/cmdx"
 )
 
@@ -12,11 +15,21 @@ func NewCreateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create Ory Cloud resources",
+		Example: `$ ory create project --name "Example Project"
+
+$ ory create self-service-flow login --project my-project --format json
+
+$ ory create identities --generate 100 --project my-project --allow-production good-wright-t7kzy3vugf`,
 	}
-	cmd.AddCommand(project.NewCreateProjectCmd())
+	client.AddGroupedCommands(cmd, client.GroupProject, project.NewCreateProjectCmd())
+	client.AddGroupedCommands(cmd, client.GroupIdentity,
+		action.NewCreateActionCmd(),
+		identity.NewCreateIdentitiesCmd(),
+		selfservice.NewCreateFlowCmd(),
+	)
 	client.RegisterConfigFlag(cmd.PersistentFlags())
 	client.RegisterYesFlag(cmd.PersistentFlags())
 	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
-	cmdx.RegisterJSONFormatFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
 	return cmd
 }
@@ -0,0 +1,70 @@
+package cloudx_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+func TestCreateIdentities(t *testing.T) {
+	fake := newMockBackend()
+	fake.HandleFunc("/schemas/default", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{
+  "type": "object",
+  "properties": {
+    "traits": {
+      "type": "object",
+      "properties": {
+        "email": {"type": "string", "format": "email", "ory.sh/kratos": {"credentials": {"password": {"identifier": true}}}},
+        "name": {"type": "string"}
+      }
+    }
+  }
+}`)
+	})
+	cloudxtest.Serve(t, fake)
+	cmd := newMockedCmd(t)
+	const slug = "good-wright-t7kzy3vugf"
+
+	t.Run("case=refuses without confirming the project", func(t *testing.T) {
+		_, stderr, err := cmd.Exec(nil, "create", "identities", "--generate", "3", "--project", mockedProjectID, "--allow-production", "other-project")
+		require.Error(t, err)
+		assert.Equal(t, client.ExitValidation, client.ExitCode(err))
+		assert.Contains(t, stderr, "--allow-production "+slug)
+		assert.Empty(t, fake.Identities(mockedProjectID))
+	})
+
+	t.Run("case=imports the generated identities", func(t *testing.T) {
+		credentials := filepath.Join(t.TempDir(), "credentials.csv")
+		stdout, stderr, err := cmd.Exec(nil, "create", "identities", "--generate", "3", "--seed", "42", "--workers", "1", "--credentials-out", credentials,
+			"--project", mockedProjectID, "--allow-production", slug, "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "Imported: 3, failed: 0")
+		assert.Len(t, gjson.Parse(stdout).Array(), 3)
+		assert.NotContains(t, stdout, "password", "the credentials are only written to the file")
+
+		identities := fake.Identities(mockedProjectID)
+		require.Len(t, identities, 3)
+		for _, identity := range identities {
+			assert.True(t, strings.HasSuffix(gjson.GetBytes(identity, "traits.email").String(), "@example.test"), string(identity))
+			assert.NotEmpty(t, gjson.GetBytes(identity, "traits.name").String())
+		}
+
+		content, err := os.ReadFile(credentials)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		require.Len(t, lines, 4)
+		assert.Equal(t, "id,identifier,password", lines[0])
+		assert.Contains(t, lines[1], gjson.GetBytes(identities[0], "id").String()+","+gjson.GetBytes(identities[0], "traits.email").String()+",")
+	})
+}
@@ -3,22 +3,34 @@ package cloudx
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ory/cli/cmd/cloudx/action"
 	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/consent"
 	"github.com/ory/cli/cmd/cloudx/identity"
+	"github.com/ory/cli/cmd/cloudx/relationtuple"
 	"github.com/ory/x/cmdx"
 )
 
 func NewDeleteCmd(parent *cobra.Command) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete",
-		Short: "Delete resources",
+		Use:     "delete",
+		Aliases: verbAliases["delete"],
+		Short:   "Delete resources",
+		Example: `$ ory delete identity 9f425a8d-7efc-4768-8f23-7647a74fdf13 --project my-project --yes
+
+$ ory delete relation-tuples --namespace documents --object doc-1 --project my-project --format json`,
 	}
 
-	cmd.AddCommand(identity.NewDeleteIdentityCmd(parent))
+	client.AddGroupedCommands(cmd, client.GroupIdentity,
+		identity.NewDeleteIdentityCmd(parent),
+		action.NewDeleteActionCmd(),
+	)
+	client.AddGroupedCommands(cmd, client.GroupOAuth2, consent.NewDeleteConsentSessionsCmd())
+	client.AddGroupedCommands(cmd, client.GroupPermissions, relationtuple.NewDeleteRelationTuplesCmd())
 
 	client.RegisterConfigFlag(cmd.PersistentFlags())
 	client.RegisterYesFlag(cmd.PersistentFlags())
 	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
-	cmdx.RegisterJSONFormatFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
 	return cmd
 }
@@ -0,0 +1,99 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+)
+
+type outputDoctorFindings []*client.DoctorFinding
+
+func (outputDoctorFindings) Header() []string {
+	return []string{"CHECK", "STATUS", "DETAILS", "HINT"}
+}
+
+func (f outputDoctorFindings) Table() [][]string {
+	rows := make([][]string, len(f))
+	for k, finding := range f {
+		hint := finding.Hint
+		if hint == "" {
+			hint = "-"
+		}
+		rows[k] = []string{finding.Name, finding.Status, finding.Details, hint}
+	}
+	return rows
+}
+
+func (f outputDoctorFindings) Interface() interface{} {
+	return []*client.DoctorFinding(f)
+}
+
+func (f outputDoctorFindings) Len() int {
+	return len(f)
+}
+
+func NewDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Args:  client.NoArgs,
+		Short: "Diagnose problems with the environment of the CLI",
+		Long: `Diagnose common problems with the environment of the CLI. All checks are read-only:
+
+- configuration: the configuration file in use, whether it can be read, its version, and whether it is valid.
+- credentials: where the session token is stored, and whether other users may read it. No keyring is used.
+- environment: the environment variables of the CLI which are set, and those which conflict with flags.
+- terminal: which of stdin, stdout, and stderr are terminals, and why prompts are disabled, if they are.
+- clock: the difference between the system time and the time of Ory Cloud. Large differences break signing in.
+- console, authentication, project API, proxy: the connectivity to Ory Cloud, see ` + "`ory ping`" + `.
+- endpoints: whether the self-hosted endpoints, see --endpoints, are reachable.
+
+Checks which need a session are skipped if you are not signed in, and the command never prompts. Every finding which
+did not pass comes with a hint on how to fix it. Warnings do not fail the command, failed checks do.
+
+Attach the output of --format json to support requests. It does not contain the session token.`,
+		Example: `$ ory doctor
+CHECK		STATUS	DETAILS						HINT
+configuration	pass	/home/dev/.ory-cloud.json, version v0alpha0	-
+credentials	pass	the session token is stored in ...		-
+clock		fail	the system time differs by 4m2s from ...	synchronize the system clock, ...
+
+$ ory doctor --format json > doctor.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			findings := h.Doctor(cmd)
+			client.PrintTable(cmd, outputDoctorFindings(findings))
+
+			code := client.ExitSuccess
+			for _, f := range findings {
+				if f.Status != client.PingFailed {
+					continue
+				}
+				c := client.ExitFailure
+				if f.Err != nil {
+					c = client.ExitCode(f.Err)
+				}
+				if code == client.ExitSuccess {
+					code = c
+				} else if code != c {
+					code = client.ExitFailure
+				}
+			}
+			if code != client.ExitSuccess {
+				return client.FailSilently(cmd, code)
+			}
+			return nil
+		},
+	}
+
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
+}
@@ -0,0 +1,70 @@
+package cloudx_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+func TestDoctor(t *testing.T) {
+	fake := newMockBackend()
+	cloudxtest.Serve(t, fake)
+
+	findings := func(t *testing.T, stdout string) map[string]map[string]interface{} {
+		var list []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(stdout), &list), stdout)
+		byName := map[string]map[string]interface{}{}
+		for _, f := range list {
+			byName[f["name"].(string)] = f
+		}
+		return byName
+	}
+
+	t.Run("case=signed in", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "doctor", "--project", mockedProjectID, "--format", "json")
+		require.NoError(t, err, stderr)
+
+		f := findings(t, stdout)
+		for _, name := range []string{"configuration", "credentials", "clock", "console", "authentication", "project API"} {
+			assert.Equal(t, client.PingPassed, f[name]["status"], name)
+		}
+		assert.Equal(t, client.PingSkipped, f["endpoints"]["status"])
+		assert.NotContains(t, stdout, `"mocked"`, "the session token must not be printed")
+	})
+
+	t.Run("case=not signed in", func(t *testing.T) {
+		stdout, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(nil, "doctor", "--format", "json")
+		require.NoError(t, err, stderr)
+
+		f := findings(t, stdout)
+		assert.Equal(t, client.DoctorWarned, f["configuration"]["status"])
+		assert.Contains(t, f["configuration"]["hint"], "ory auth")
+		assert.Equal(t, client.PingSkipped, f["credentials"]["status"])
+		assert.Equal(t, client.PingSkipped, f["authentication"]["status"])
+		assert.Equal(t, client.PingPassed, f["console"]["status"])
+	})
+
+	t.Run("case=clock skew", func(t *testing.T) {
+		fake.HandleFunc("/health/alive", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Date", time.Now().Add(-5*time.Minute).UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusOK)
+		})
+
+		stdout, _, err := newMockedCmd(t).Exec(nil, "doctor", "--format", "json")
+		require.Error(t, err)
+		assert.Equal(t, client.ExitFailure, client.ExitCode(err))
+
+		f := findings(t, stdout)
+		assert.Equal(t, client.PingFailed, f["clock"]["status"])
+		assert.Contains(t, f["clock"]["details"], "the system time differs by 5m")
+		assert.Contains(t, f["clock"]["hint"], "synchronize the system clock")
+	})
+}
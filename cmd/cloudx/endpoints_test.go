@@ -0,0 +1,85 @@
+package cloudx_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+func TestEndpoints(t *testing.T) {
+	fake := newMockBackend()
+	fake.HandleFunc("/admin/courier/messages", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id": "b6c6f3a1-6a1e-4a7e-8c8c-000000000001", "type": "email", "template_type": "verification_valid", "recipient": "cloud@example.com", "status": "sent", "created_at": "2022-06-01T12:00:00Z", "updated_at": "2022-06-01T12:00:00Z"}]`)
+	})
+	cloudxtest.Serve(t, fake)
+
+	var authorization string
+	selfHosted := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = fmt.Fprint(w, `[{"id": "b6c6f3a1-6a1e-4a7e-8c8c-000000000002", "type": "email", "template_type": "verification_valid", "recipient": "self-hosted@example.com", "status": "sent", "created_at": "2022-06-01T12:00:00Z", "updated_at": "2022-06-01T12:00:00Z"}]`)
+	}))
+	t.Cleanup(selfHosted.Close)
+
+	endpoints := func(t *testing.T, contents string) string {
+		file := filepath.Join(t.TempDir(), "ory-endpoints.yaml")
+		require.NoError(t, os.WriteFile(file, []byte(contents), 0600))
+		return file
+	}
+
+	t.Run("case=self-hosted services need no session", func(t *testing.T) {
+		file := endpoints(t, "identity: "+selfHosted.URL+"\n")
+		stdout, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(nil, "list", "courier-messages", "--endpoints", file, "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, "self-hosted@example.com")
+		assert.Empty(t, authorization, "the Ory Cloud session is not sent to self-hosted services")
+	})
+
+	t.Run("case=mixed setups use the project for the other services", func(t *testing.T) {
+		file := endpoints(t, "permission: "+selfHosted.URL+"\n")
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "list", "courier-messages", "--project", mockedProjectID, "--endpoints", file, "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, "cloud@example.com")
+
+		stdout, stderr, err = newMockedCmd(t).Exec(nil, "get", "project", mockedProjectID, "--endpoints", file, "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, mockedProjectID)
+	})
+
+	t.Run("case=project commands fail if all services are self-hosted", func(t *testing.T) {
+		file := endpoints(t, "identity: "+selfHosted.URL+"\npermission: "+selfHosted.URL+"\noauth2: "+selfHosted.URL+"\n")
+		_, _, err := newMockedCmd(t).Exec(nil, "list", "projects", "--endpoints", file)
+		require.ErrorIs(t, err, client.ErrSelfHosted)
+		assert.Equal(t, client.ExitValidation, client.ExitCode(err))
+	})
+
+	t.Run("case=endpoints are stored in the configuration", func(t *testing.T) {
+		file := endpoints(t, "identity: "+selfHosted.URL+"\n")
+		cmd := cloudxtest.NewCmd(cloudxtest.WriteConfig(t, "dev@ory.sh", "mocked", ""))
+		_, stderr, err := cmd.Exec(nil, "config", "set-endpoints", file)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "identity="+selfHosted.URL)
+
+		stdout, stderr, err := cmd.Exec(nil, "list", "courier-messages", "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, "self-hosted@example.com")
+
+		stdout, _, err = cmd.Exec(nil, "auth", "env")
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "mocked", "the session is kept")
+
+		_, _, err = cmd.Exec(nil, "config", "set-endpoints")
+		require.NoError(t, err)
+		_, _, err = cmd.Exec(nil, "list", "courier-messages", "--format", "json")
+		assert.ErrorIs(t, err, client.ErrNoProjectSelected, "the identity service is used through Ory Cloud again")
+	})
+}
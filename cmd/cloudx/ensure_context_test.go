@@ -0,0 +1,201 @@
+package cloudx_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+// closedStdin returns a stdin which is not a terminal and never has any input.
+func closedStdin(t *testing.T) *os.File {
+	f, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+// TestEnsureContext covers every branch of signing in before a command which requires a session: a valid, missing,
+// and expired session, each with closed stdin, --quiet, and --yes. None of them may wait for input which never arrives.
+func TestEnsureContext(t *testing.T) {
+	const password = "s3cr3t-password"
+	serve := func(t *testing.T, expired bool) *cloudxtest.Backend {
+		fake := newMockBackend()
+		fake.AddAccount("dev@ory.sh", password)
+		if expired {
+			fake.ExpireSession("mocked")
+		}
+		cloudxtest.Serve(t, fake)
+		return fake
+	}
+	getProject := []string{"get", "project", mockedProjectID, "--format", "json"}
+
+	t.Run("case=valid session", func(t *testing.T) {
+		serve(t, false)
+		for _, flags := range [][]string{nil, {"--quiet"}, {"--yes"}} {
+			stdout, stderr, err := newMockedCmd(t).Exec(closedStdin(t), append(getProject, flags...)...)
+			require.NoError(t, err, stderr)
+			assert.Contains(t, stdout, mockedProjectID)
+			assert.NotContains(t, stderr, "[y/n]", "a valid session must never prompt")
+		}
+	})
+
+	t.Run("case=not signed in", func(t *testing.T) {
+		serve(t, false)
+
+		t.Run("with closed stdin", func(t *testing.T) {
+			for _, flags := range [][]string{nil, {"--yes"}} {
+				_, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(closedStdin(t), append(getProject, flags...)...)
+				require.Error(t, err)
+				var notAuthenticated *client.NotAuthenticatedError
+				require.ErrorAs(t, err, &notAuthenticated)
+				assert.ErrorIs(t, err, client.ErrNotSignedIn)
+				assert.Equal(t, client.ExitNotAuthenticated, client.ExitCode(err))
+				assert.Contains(t, err.Error(), "run `ory auth` in a terminal")
+				assert.NotContains(t, stderr, "[y/n]")
+			}
+		})
+
+		t.Run("with --quiet", func(t *testing.T) {
+			_, _, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(closedStdin(t), append(getProject, "--quiet")...)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, client.ErrNoConfigQuiet)
+			assert.Contains(t, err.Error(), "the --quiet flag is set")
+			assert.Equal(t, client.ExitNotAuthenticated, client.ExitCode(err))
+		})
+
+		t.Run("with a terminal", func(t *testing.T) {
+			var r bytes.Buffer
+			_, _ = r.WriteString("y\n")          // Do you already have an Ory Console account you wish to use? [y/n]: y
+			_, _ = r.WriteString("dev@ory.sh\n") // Email:
+			_, _ = r.WriteString("\n")           // Email: dev@ory.sh — correct? [Y/n]:
+			stdout, stderr, err := testhelpers.ConfigPasswordAwareCmd(testhelpers.NewConfigDir(t), password).Exec(&r, getProject...)
+			require.NoError(t, err, stderr)
+			assert.Contains(t, stdout, mockedProjectID)
+			assert.Contains(t, stderr, "You are now signed in as: dev@ory.sh")
+		})
+	})
+
+	t.Run("case=expired session", func(t *testing.T) {
+		serve(t, true)
+
+		for name, flags := range map[string][]string{"closed stdin": nil, "--yes": {"--yes"}, "--quiet": {"--quiet"}} {
+			t.Run("with "+name, func(t *testing.T) {
+				_, stderr, err := newMockedCmd(t).Exec(closedStdin(t), append(getProject, flags...)...)
+				require.Error(t, err)
+				assert.ErrorIs(t, err, client.ErrSessionExpired)
+				assert.Equal(t, client.ExitNotAuthenticated, client.ExitCode(err))
+				assert.NotContains(t, stderr, "[y/n]")
+			})
+		}
+
+		t.Run("declined at the prompt", func(t *testing.T) {
+			_, stderr, err := newMockedCmd(t).Exec(bytes.NewBufferString("n\n"), getProject...)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, client.ErrSessionExpired)
+			assert.Contains(t, stderr, `Do you wish to log in again as "dev@ory.sh"?`)
+		})
+
+		t.Run("--yes skips the confirmation", func(t *testing.T) {
+			config := cloudxtest.WriteConfig(t, "dev@ory.sh", "mocked", "")
+			var r bytes.Buffer
+			_, _ = r.WriteString("y\n")          // Do you already have an Ory Console account you wish to use? [y/n]: y
+			_, _ = r.WriteString("dev@ory.sh\n") // Email:
+			stdout, stderr, err := testhelpers.ConfigPasswordAwareCmd(config, password).Exec(&r, append(getProject, "--yes")...)
+			require.NoError(t, err, stderr)
+			assert.Contains(t, stdout, mockedProjectID)
+			assert.NotContains(t, stderr, "log in again")
+			assert.NotEqual(t, "mocked", testhelpers.ReadConfig(t, config).SessionToken)
+		})
+	})
+}
+
+// TestYesWithCredentialSources checks that --yes only answers confirmations: commands authenticate with each source
+// of credentials which requires no input, and fail listing these sources if there is none.
+func TestYesWithCredentialSources(t *testing.T) {
+	const password = "s3cr3t-password"
+	fake := newMockBackend()
+	fake.AddAccount("dev@ory.sh", password)
+	cloudxtest.Serve(t, fake)
+	getProject := []string{"get", "project", mockedProjectID, "--format", "json", "--yes"}
+
+	t.Run("source=--config", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(closedStdin(t), getProject...)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, mockedProjectID)
+	})
+
+	t.Run("source=ORY_CLOUD_CONFIG_PATH", func(t *testing.T) {
+		t.Setenv("ORY_CLOUD_CONFIG_PATH", cloudxtest.WriteConfig(t, "dev@ory.sh", "mocked", ""))
+		stdout, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(closedStdin(t), getProject...)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, mockedProjectID)
+	})
+
+	t.Run("source=terminal", func(t *testing.T) {
+		config := cloudxtest.WriteConfig(t, "dev@ory.sh", "mocked", "")
+		var r bytes.Buffer
+		_, _ = r.WriteString("y\n")          // Do you already have an Ory Console account you wish to use? [y/n]: y
+		_, _ = r.WriteString("dev@ory.sh\n") // Email:
+		_, stderr, err := testhelpers.ConfigPasswordAwareCmd(config, password).Exec(&r, "auth", "--yes")
+		require.NoError(t, err, stderr)
+		assert.NotContains(t, stderr, "Do you wish to authenticate with another account?", "--yes answers the confirmation")
+		assert.Contains(t, stderr, "You are now signed in as: dev@ory.sh")
+		assert.NotEqual(t, "mocked", testhelpers.ReadConfig(t, config).SessionToken)
+	})
+
+	t.Run("source=none", func(t *testing.T) {
+		_, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(closedStdin(t), getProject...)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, client.ErrNotSignedIn)
+		assert.Contains(t, err.Error(), "run `ory auth` in a terminal to sign in, or set ORY_SESSION_TOKEN or --session-token to a session token, or set ORY_CLOUD_CONFIG_PATH or --config to the configuration file of a signed in CLI")
+		assert.NotContains(t, stderr, "[y/n]")
+	})
+
+	t.Run("source=none with ory auth", func(t *testing.T) {
+		_, _, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(closedStdin(t), "auth", "--yes")
+		require.Error(t, err)
+		var disabled *client.PromptDisabledError
+		require.ErrorAs(t, err, &disabled)
+		assert.Contains(t, err.Error(), "ORY_CLOUD_CONFIG_PATH")
+	})
+}
+
+// TestNoConfigRequiresSignIn runs commands using each way of requiring a session without a configuration file. They
+// must fail with the exit code of unauthenticated users instead of using a configuration which does not exist.
+func TestNoConfigRequiresSignIn(t *testing.T) {
+	cloudxtest.Serve(t, newMockBackend())
+
+	for _, args := range [][]string{
+		{"list", "projects"},
+		{"get", "project", mockedProjectID},
+		{"use", "project", mockedProjectID},
+		{"create", "project", "--name", "Example Project"},
+		{"update", "project", mockedProjectID, "--name", "Example Project"},
+		{"patch", "project", mockedProjectID, "--replace", `/name="Example Project"`},
+		{"list", "identities", "--project", mockedProjectID},
+		{"get", "project-env", "--project", mockedProjectID},
+	} {
+		t.Run("command="+strings.Join(args, " "), func(t *testing.T) {
+			for _, flags := range [][]string{nil, {"--yes"}, {"--quiet"}} {
+				config := testhelpers.NewConfigDir(t)
+				stdout, _, err := testhelpers.ConfigAwareCmd(config).Exec(closedStdin(t), append(args, flags...)...)
+				require.Error(t, err, "flags: %v", flags)
+				if !errors.Is(err, client.ErrNoConfigQuiet) {
+					assert.ErrorIs(t, err, client.ErrNotSignedIn, "flags: %v", flags)
+				}
+				assert.Equal(t, client.ExitNotAuthenticated, client.ExitCode(err), "flags: %v: %+v", flags, err)
+				assert.Empty(t, stdout)
+				assert.NoFileExists(t, config)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cloudx
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+)
+
+const noTokenFlag = "no-token"
+
+func NewAuthEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Args:  client.NoArgs,
+		Short: "Print shell commands which export the session token and the project",
+		Long: `Print shell commands which export the session token and the project as environment variables, for
+scripts and tools which use the Ory APIs directly:
+
+- ORY_SESSION_TOKEN: the session token of the signed in user.
+- ORY_PROJECT: the ID of the project set by --project, or of the selected project.
+- ORY_PROJECT_SLUG: the slug of that project.
+
+Only the commands are printed to stdout, so that the output can be evaluated by the shell. The command fails if you
+are not signed in or your session expired, it never prompts you to sign in.
+
+Use --no-token to leave out the session token, e.g. to share the output.`,
+		Example: `$ eval "$(ory auth env)"
+
+$ ory auth env --shell fish | source
+
+$ ory auth env --shell powershell | Invoke-Expression
+
+$ ory auth env --project good-wright-t7kzy3vugf --no-token
+export ORY_PROJECT='ecaaa3cb-0730-4ee8-a6df-9553cdfeef89'
+export ORY_PROJECT_SLUG='good-wright-t7kzy3vugf'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell, err := client.Shell(cmd)
+			if err != nil {
+				return err
+			}
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+			ac, p, err := h.SessionProject()
+			if err != nil {
+				return err
+			}
+
+			var vars []client.EnvVar
+			if noToken, _ := cmd.Flags().GetBool(noTokenFlag); !noToken {
+				vars = append(vars, client.EnvVar{Name: client.SessionTokenEnv, Value: ac.SessionToken})
+			}
+			if p == nil {
+				h.Log.Warnf("No project selected, use --project or `ory use project` to export ORY_PROJECT and ORY_PROJECT_SLUG as well.")
+			} else {
+				vars = append(vars, client.EnvVar{Name: "ORY_PROJECT", Value: p.Id}, client.EnvVar{Name: "ORY_PROJECT_SLUG", Value: p.Slug})
+			}
+
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), client.ShellExports(shell, vars))
+			return nil
+		},
+	}
+
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterShellFlag(cmd.Flags())
+	cmd.Flags().Bool(noTokenFlag, false, "Do not print the session token.")
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	return cmd
+}
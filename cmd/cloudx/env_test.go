@@ -0,0 +1,57 @@
+package cloudx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+func TestAuthEnv(t *testing.T) {
+	fake := newMockBackend()
+	cloudxtest.RecordReplay(t, fake, func(t *testing.T) {
+		testAuthEnv(t, fake)
+	}, func() []string { return []string{"mocked"} })
+}
+
+func testAuthEnv(t *testing.T, fake *cloudxtest.Backend) {
+	t.Run("case=exports the session and project", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "auth", "env", "--project", "good-wright-t7kzy3vugf")
+		require.NoError(t, err, stderr)
+		assert.Equal(t, `export ORY_SESSION_TOKEN='mocked'
+export ORY_PROJECT='`+mockedProjectID+`'
+export ORY_PROJECT_SLUG='good-wright-t7kzy3vugf'
+`, stdout)
+	})
+
+	t.Run("case=--no-token and --shell", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "auth", "env", "--project", mockedProjectID, "--no-token", "--shell", "fish")
+		require.NoError(t, err, stderr)
+		assert.Equal(t, `set -gx ORY_PROJECT '`+mockedProjectID+`';
+set -gx ORY_PROJECT_SLUG 'good-wright-t7kzy3vugf';
+`, stdout)
+	})
+
+	t.Run("case=warns if no project is selected", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "auth", "env")
+		require.NoError(t, err, stderr)
+		assert.Equal(t, "export ORY_SESSION_TOKEN='mocked'\n", stdout)
+		assert.Contains(t, stderr, "No project selected")
+	})
+
+	t.Run("case=refuses to run without a session", func(t *testing.T) {
+		stdout, _, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(nil, "auth", "env")
+		require.ErrorIs(t, err, client.ErrNotSignedIn)
+		assert.Empty(t, stdout)
+
+		fake.ExpireSession("mocked")
+		t.Cleanup(func() { fake.AddSessionToken("dev@ory.sh", "mocked") })
+		stdout, _, err = newMockedCmd(t).Exec(nil, "auth", "env")
+		require.ErrorIs(t, err, client.ErrSessionExpired)
+		assert.Empty(t, stdout)
+	})
+}
@@ -0,0 +1,61 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+)
+
+func NewExecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec -- COMMAND [ARGS...]",
+		Args:  client.MinimumNArgs(1),
+		Short: "Run a command with the Ory Cloud session and project in its environment",
+		Long: `Run a command with the session token and the project set by --project, or the selected project, in its
+environment:
+
+- ORY_SESSION_TOKEN: the session token of the signed in user.
+- ORY_PROJECT: the ID of the project.
+- ORY_PROJECT_SLUG: the slug of the project.
+- ORY_SDK_URL: the URL of the project's API.
+
+The command fails if you are not signed in or your session expired, it never prompts you to sign in. It only
+writes to stderr, so the output of the command is untouched. The CLI exits with the exit code of the command and
+forwards SIGINT and SIGTERM to it.
+
+Flags after COMMAND are passed to the command.`,
+		Example: `$ ory exec -- terraform apply
+
+$ ory exec --project good-wright-t7kzy3vugf -- sh -c 'curl -H "Authorization: Bearer $ORY_SESSION_TOKEN" $ORY_SDK_URL/admin/identities'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+			ac, p, err := h.SessionProject()
+			if err != nil {
+				return err
+			}
+
+			vars := []client.EnvVar{{Name: "ORY_SESSION_TOKEN", Value: ac.SessionToken}}
+			if p == nil {
+				h.Log.Warnf("No project selected, use --project or `ory use project` to set ORY_PROJECT, ORY_PROJECT_SLUG, and ORY_SDK_URL as well.")
+			} else {
+				vars = append(vars,
+					client.EnvVar{Name: "ORY_PROJECT", Value: p.Id},
+					client.EnvVar{Name: "ORY_PROJECT_SLUG", Value: p.Slug},
+					client.EnvVar{Name: "ORY_SDK_URL", Value: client.ProjectAPIURL(p.Slug)},
+				)
+			}
+			return client.RunChild(cmd, args, vars)
+		},
+	}
+
+	// Flags after the command belong to the command.
+	cmd.Flags().SetInterspersed(false)
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	return cmd
+}
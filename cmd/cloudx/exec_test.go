@@ -0,0 +1,44 @@
+package cloudx_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+func TestExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test uses sh")
+	}
+	cloudxtest.Serve(t, newMockBackend())
+
+	t.Run("case=injects the session and project", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "exec", "--project", "good-wright-t7kzy3vugf", "--", "sh", "-c", `echo "$ORY_SESSION_TOKEN $ORY_PROJECT $ORY_PROJECT_SLUG $ORY_SDK_URL"`)
+		require.NoError(t, err, stderr)
+		assert.Equal(t, "mocked "+mockedProjectID+" good-wright-t7kzy3vugf http://good-wright-t7kzy3vugf.projects.console.ory.test\n", stdout)
+	})
+
+	t.Run("case=flags after the command are passed to it", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "exec", "--project", mockedProjectID, "sh", "-c", `echo "$0"`, "--project")
+		require.NoError(t, err, stderr)
+		assert.Equal(t, "--project\n", stdout)
+	})
+
+	t.Run("case=propagates the exit code", func(t *testing.T) {
+		stdout, _, err := newMockedCmd(t).Exec(nil, "exec", "--", "sh", "-c", "exit 5")
+		assert.Equal(t, 5, client.ExitCode(err))
+		assert.Empty(t, stdout)
+	})
+
+	t.Run("case=refuses to run without a session", func(t *testing.T) {
+		stdout, _, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(nil, "exec", "--", "sh", "-c", "echo ran")
+		require.ErrorIs(t, err, client.ErrNotSignedIn)
+		assert.Empty(t, stdout)
+	})
+}
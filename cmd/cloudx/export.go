@@ -0,0 +1,24 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/project"
+	"github.com/ory/x/cmdx"
+)
+
+func NewExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export resources, e.g. as Terraform configuration",
+		Example: `$ ory export project --project my-project --format terraform --dir ./terraform`,
+	}
+
+	client.AddGroupedCommands(cmd, client.GroupProject, project.NewExportProjectCmd())
+
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	return cmd
+}
@@ -4,27 +4,43 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/courier"
 	"github.com/ory/cli/cmd/cloudx/identity"
 	"github.com/ory/cli/cmd/cloudx/project"
+	"github.com/ory/cli/cmd/cloudx/selfservice"
 	"github.com/ory/x/cmdx"
 )
 
 func NewGetCmd(parent *cobra.Command) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "get",
-		Short: "Get a resource",
+		Use:     "get",
+		Aliases: verbAliases["get"],
+		Short:   "Get a resource",
+		Example: `$ ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format yaml
+
+$ ory get identity 9f425a8d-7efc-4768-8f23-7647a74fdf13 --project my-project --format json`,
 	}
 
-	cmd.AddCommand(
+	client.AddGroupedCommands(cmd, client.GroupProject,
 		project.NewGetProjectCmd(),
+		project.NewGetRateLimitsCmd(),
+		project.NewGetProjectEnvCmd(),
+	)
+	client.AddGroupedCommands(cmd, client.GroupIdentity,
 		project.NewGetKratosConfigCmd(),
-		project.NewGetKetoConfigCmd(),
 		identity.NewGetIdentityCmd(parent),
+		courier.NewGetCourierMessageCmd(),
+		courier.NewGetEmailTemplateCmd(),
+		selfservice.NewGetSelfServiceErrorCmd(),
+	)
+	client.AddGroupedCommands(cmd, client.GroupPermissions,
+		project.NewGetKetoConfigCmd(),
 	)
 
 	client.RegisterConfigFlag(cmd.PersistentFlags())
 	client.RegisterYesFlag(cmd.PersistentFlags())
 	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
 
 	return cmd
 }
@@ -0,0 +1,32 @@
+package cloudx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+	"github.com/ory/x/snapshotx"
+)
+
+func TestHelp(t *testing.T) {
+	cmd := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t))
+
+	for _, args := range [][]string{
+		{},
+		{"get"},
+		{"list"},
+		{"delete"},
+		{"get", "project"},
+		{"list", "identities"},
+		{"patch", "identity-config"},
+		{"is", "allowed"},
+	} {
+		t.Run("command="+strings.Join(append([]string{"cloud"}, args...), "_"), func(t *testing.T) {
+			stdout, stderr, err := cmd.Exec(nil, append(args, "--help")...)
+			require.NoError(t, err, stderr)
+			snapshotx.SnapshotT(t, stdout)
+		})
+	}
+}
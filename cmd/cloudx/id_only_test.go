@@ -0,0 +1,50 @@
+package cloudx_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+func TestCreateIDOnly(t *testing.T) {
+	cloudxtest.Serve(t, newMockBackend())
+	cmd := newMockedCmd(t)
+
+	t.Run("case=project", func(t *testing.T) {
+		stdout, stderr, err := cmd.Exec(nil, "create", "project", "--name", "Example Project", "--id-only")
+		require.NoError(t, err, stderr)
+		id := uuid.FromStringOrNil(strings.TrimSuffix(stdout, "\n"))
+		require.NotEqual(t, uuid.Nil, id, stdout)
+		assert.Equal(t, id.String()+"\n", stdout)
+		assert.Contains(t, stderr, "Example Project", "the human readable output is printed to stderr")
+
+		stdout, stderr, err = cmd.Exec(nil, "get", "project", id.String(), "--format", "json")
+		require.NoError(t, err, stderr)
+		var p struct {
+			Name string `json:"name"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(stdout), &p))
+		assert.Equal(t, "Example Project", p.Name)
+	})
+
+	t.Run("case=quiet", func(t *testing.T) {
+		stdout, stderr, err := cmd.Exec(nil, "create", "project", "--name", "Example Project", "--id-only", "--quiet")
+		require.NoError(t, err, stderr)
+		assert.Regexp(t, "^[0-9a-f-]{36}\n$", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("case=rejects --format", func(t *testing.T) {
+		stdout, _, err := cmd.Exec(nil, "create", "project", "--name", "Example Project", "--id-only", "--format", "json")
+		require.Error(t, err)
+		assert.Equal(t, client.ExitValidation, client.ExitCode(err))
+		assert.Empty(t, stdout)
+	})
+}
@@ -1,16 +1,165 @@
 package identity
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/client/pool"
 	"github.com/ory/kratos/cmd/identities"
-	"github.com/ory/x/cmdx"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	interactiveFlag = "interactive"
+	idsFileFlag     = "ids-file"
+
+	// maxSummarizedIdentities is the number of identities up to which the confirmation shows the email address of
+	// every identity. Larger deletions only show the number of identities.
+	maxSummarizedIdentities = 20
 )
 
 func NewDeleteIdentityCmd(parent *cobra.Command) *cobra.Command {
 	cmd := identities.NewDeleteIdentityCmd(parent)
 	client.RegisterProjectFlag(cmd.Flags())
-	cmdx.RegisterFormatFlags(cmd.Flags())
-	return cmd
+	cmd.Flags().Bool(interactiveFlag, false, "Select the identities to delete interactively if no identity ID is given.")
+	cmd.Flags().String(idsFileFlag, "", "A file with one identity ID per line to delete. Use - to read from stdin.")
+	client.RegisterBulkDeleteFlags(cmd.Flags(), "failed-identities.txt")
+	cmd.Long += `
+
+The deletion needs to be confirmed or requires --yes. The identities shown in the confirmation are fetched with
+--workers concurrent requests.
+
+The identities are deleted by --workers concurrent requests. Server and network errors are retried, and identities
+which do not exist are counted as not found. Identities which could not be deleted are written to --failed-file,
+which can be passed to --ids-file to try again. Ctrl-C stops the deletion after the requests in flight finished.
+Use --strict to exit with a non-zero code if any identity could not be deleted or was not found.`
+	cmd.Example = `$ ory delete identity 9f425a8d-7efc-4768-8f23-7647a74fdf13 --project my-project --yes
+
+$ ory delete identity --interactive --project my-project --format json
+
+$ ory delete identity --ids-file inactive-identities.txt --workers 16 --strict --project my-project --yes`
+
+	validateArgs := client.MinimumNArgs(1, client.UUID("an identity ID"))
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && (flagx.MustGetBool(cmd, interactiveFlag) || flagx.MustGetString(cmd, idsFileFlag) != "") {
+			return nil
+		}
+		return validateArgs(cmd, args)
+	}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		h, err := client.NewCommandHelper(cmd)
+		if err != nil {
+			return err
+		}
+		workers, err := client.Workers(cmd)
+		if err != nil {
+			return err
+		}
+
+		ids := args
+		if file := flagx.MustGetString(cmd, idsFileFlag); file != "" {
+			lines, err := client.ReadLines(cmd, file)
+			if err != nil {
+				return err
+			}
+			for _, id := range lines {
+				if err := client.UUID("an identity ID")(id); err != nil {
+					return client.NewValidationError(errors.Wrapf(err, "invalid line in --%s", idsFileFlag), nil)
+				}
+			}
+			ids = append(ids, lines...)
+		}
+
+		api, err := h.NewServiceAPI(client.ServiceIdentity)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 && flagx.MustGetBool(cmd, interactiveFlag) {
+			if ids, err = h.SelectIdentities(cmd.Context(), api, "pass the identity IDs as arguments"); err != nil {
+				return err
+			}
+		}
+		if len(ids) == 0 {
+			return client.NewValidationError(errors.New("there are no identities to delete"), nil)
+		}
+
+		deletion, err := h.NewBulkDeletion(cmd, "Deleting identities", ids, func(ctx context.Context, id string) (*http.Response, error) {
+			return api.Do(ctx, http.MethodDelete, "/admin/identities/"+url.PathEscape(id), nil, nil, nil)
+		})
+		if err != nil {
+			return err
+		}
+
+		action := "delete the identity"
+		if len(ids) > 1 {
+			action = fmt.Sprintf("delete %d identities", len(ids))
+		}
+		if err := h.ConfirmDestruction(&client.Destruction{
+			Action: action,
+			FetchSummary: func() ([]client.SummaryLine, error) {
+				if len(ids) > maxSummarizedIdentities {
+					return []client.SummaryLine{{Key: "Identities", Value: fmt.Sprintf("%d", len(ids))}}, nil
+				}
+				return summarizeIdentities(cmd.Context(), api, ids, workers)
+			},
+		}); err != nil {
+			return err
+		}
+		// The summary is the report of dry runs.
+		if h.DryRun {
+			return nil
+		}
+
+		summary := deletion.Run(cmd.Context())
+		switch deleted := summary.DeletedKeys; len(deleted) {
+		case 0:
+		case 1:
+			client.PrintRow(cmd, outputIdentityID(deleted[0]))
+		default:
+			client.PrintTable(cmd, outputIdentityIDs(deleted))
+		}
+		return h.FinishBulkDeletion(cmd, summary)
+	}
+	return client.MarkDryRunCapable(cmd)
+}
+
+// summarizeIdentities returns the ID and email address of each identity. The identities are fetched by workers
+// concurrent requests.
+func summarizeIdentities(ctx context.Context, api *client.ProjectAPI, ids []string, workers int) ([]client.SummaryLine, error) {
+	emails := make([]string, len(ids))
+	errs := pool.Run(ctx, workers, len(ids), func(ctx context.Context, i int) error {
+		var identity struct {
+			Traits map[string]interface{} `json:"traits"`
+		}
+		if _, err := api.Do(ctx, http.MethodGet, "/admin/identities/"+url.PathEscape(ids[i]), nil, nil, &identity); err != nil {
+			return err
+		}
+
+		emails[i] = "<none>"
+		if e, ok := identity.Traits["email"].(string); ok {
+			emails[i] = e
+		}
+		return nil
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ids) == 1 {
+		return []client.SummaryLine{{Key: "ID", Value: ids[0]}, {Key: "Email", Value: emails[0]}}, nil
+	}
+	summary := make([]client.SummaryLine, len(ids))
+	for i, id := range ids {
+		summary[i] = client.SummaryLine{Key: id, Value: emails[i]}
+	}
+	return summary, nil
 }
@@ -1,6 +1,8 @@
 package identity_test
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,7 +24,7 @@ func TestDeleteIdentity(t *testing.T) {
 
 	t.Run("is able to delete identities", func(t *testing.T) {
 		userID := testhelpers.ImportIdentity(t, defaultCmd, defaultProject, nil)
-		stdout, stderr, err := defaultCmd.Exec(nil, "delete", "identity", "--format", "json", "--project", defaultProject, userID)
+		stdout, stderr, err := defaultCmd.Exec(nil, "delete", "identity", "--format", "json", "--yes", "--project", defaultProject, userID)
 		require.NoError(t, err, stderr)
 		out := gjson.Parse(stdout)
 		assert.True(t, gjson.Valid(stdout))
@@ -32,10 +34,56 @@ func TestDeleteIdentity(t *testing.T) {
 	t.Run("is able to delete identities after authenticating", func(t *testing.T) {
 		userID := testhelpers.ImportIdentity(t, defaultCmd, defaultProject, nil)
 		cmd, r := testhelpers.WithReAuth(t, defaultEmail, defaultPassword)
-		stdout, stderr, err := cmd.Exec(r, "delete", "identity", "--format", "json", "--project", defaultProject, userID)
+		stdout, stderr, err := cmd.Exec(r, "delete", "identity", "--format", "json", "--yes", "--project", defaultProject, userID)
 		require.NoError(t, err, stderr)
 		assert.True(t, gjson.Valid(stdout))
 		out := gjson.Parse(stdout)
 		assert.Equal(t, userID, out.String(), stdout)
 	})
+
+	t.Run("is able to delete identities from a file", func(t *testing.T) {
+		ids := []string{
+			testhelpers.ImportIdentity(t, defaultCmd, defaultProject, nil),
+			testhelpers.ImportIdentity(t, defaultCmd, defaultProject, nil),
+		}
+		missing := "9f425a8d-7efc-4768-8f23-7647a74fdf13"
+		stdin := bytes.NewBufferString(strings.Join(append(ids, missing), "\n"))
+
+		stdout, stderr, err := defaultCmd.Exec(stdin, "delete", "identity", "--ids-file", "-", "--format", "json", "--yes", "--project", defaultProject)
+		require.NoError(t, err, stderr)
+		assert.ElementsMatch(t, ids, gjson.Parse(stdout).Value(), stdout)
+		assert.Contains(t, stderr, "Deleted: 2, not found: 1, failed: 0")
+
+		_, stderr, err = defaultCmd.Exec(bytes.NewBufferString(missing), "delete", "identity", "--ids-file", "-", "--strict", "--yes", "--project", defaultProject)
+		require.Error(t, err, stderr)
+		assert.Equal(t, client.ExitFailure, client.ExitCode(err))
+	})
+
+	t.Run("is able to select the identity to delete", func(t *testing.T) {
+		userID := testhelpers.ImportIdentity(t, defaultCmd, defaultProject, nil)
+		stdout, stderr, err := defaultCmd.Exec(bytes.NewBufferString(userID+"\ny\n"), "delete", "identity", "--interactive", "--format", "json", "--project", defaultProject)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "Select identities: "+userID)
+		assert.Contains(t, stderr, "You are about to delete the identity:\n  ID:     "+userID)
+		assert.Equal(t, userID, gjson.Parse(stdout).String(), stdout)
+	})
+
+	t.Run("is not able to select the identity to delete if quiet flag", func(t *testing.T) {
+		_, _, err := defaultCmd.Exec(nil, "delete", "identity", "--interactive", "--quiet", "--project", defaultProject)
+		var promptErr *client.PromptDisabledError
+		require.ErrorAs(t, err, &promptErr)
+	})
+
+	t.Run("is not able to delete identities without confirmation if quiet flag", func(t *testing.T) {
+		userID := testhelpers.ImportIdentity(t, defaultCmd, defaultProject, nil)
+		_, _, err := defaultCmd.Exec(nil, "delete", "identity", "--quiet", "--project", defaultProject, userID)
+		var promptErr *client.PromptDisabledError
+		require.ErrorAs(t, err, &promptErr)
+	})
+
+	t.Run("is not able to delete identities if the confirmation is declined", func(t *testing.T) {
+		userID := testhelpers.ImportIdentity(t, defaultCmd, defaultProject, nil)
+		_, _, err := defaultCmd.Exec(bytes.NewBufferString("n\n"), "delete", "identity", "--project", defaultProject, userID)
+		require.ErrorIs(t, err, client.ErrAborted)
+	})
 }
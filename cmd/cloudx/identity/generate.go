@@ -0,0 +1,497 @@
+package identity
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	generateFlag        = "generate"
+	schemaFlag          = "schema"
+	domainFlag          = "domain"
+	passwordPolicyFlag  = "password-policy"
+	credentialsOutFlag  = "credentials-out"
+	seedFlag            = "seed"
+	allowProductionFlag = "allow-production"
+
+	// fallbackSchemaID is the ID of the schema Ory uses if the project does not set a default schema.
+	fallbackSchemaID = "default"
+)
+
+// The password policies of generated identities.
+const (
+	passwordRandom = "random"
+	passwordShared = "shared"
+	passwordNone   = "none"
+)
+
+var passwordPolicies = []string{passwordRandom, passwordShared, passwordNone}
+
+// testDomains are reserved for testing, so that no email sent to generated identities reaches real people.
+var testDomains = []string{".test", ".example", ".invalid", ".localhost", "example.com", "example.net", "example.org"}
+
+func NewCreateIdentitiesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "identities",
+		Args:  client.NoArgs,
+		Short: "Generate fake identities for load tests and demos",
+		Long: `Generate fake identities which conform to an identity schema of the project, and import them like
+"ory import identities" does.
+
+The traits are synthesized from the schema: email addresses on --domain, which defaults to a domain reserved for
+testing, fictional phone numbers, plausible names, and random values for all other traits. Addresses which are
+verified by the schema are randomly marked as verified or not. With the "random" --password-policy, every identity
+gets its own password, with "shared" all identities get the same password, and with "none" no password is set.
+Use --credentials-out to write the IDs, identifiers, and passwords of the imported identities to a CSV file, so that
+load tests can sign in as them.
+
+The identities only depend on the schema and --seed, so running the command again with the same seed generates the
+same identities. The seed is logged if it is not set.
+
+Ory Cloud does not tell staging and production projects apart, so the command refuses to run unless the slug of the
+project is confirmed with --allow-production.`,
+		Example: `$ ory create identities --generate 5000 --project my-project --allow-production good-wright-t7kzy3vugf
+
+$ ory create identities --generate 100 --schema customer --domain example.test --seed 42 \
+    --credentials-out credentials.csv --project my-project --allow-production good-wright-t7kzy3vugf --format json
+
+$ ory create identities --generate 10 --project my-project --allow-production good-wright-t7kzy3vugf --id-only > ids.txt`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			n := flagx.MustGetInt(cmd, generateFlag)
+			if n < 1 {
+				return client.NewValidationError(errors.Errorf("--%s must be set to the number of identities to create", generateFlag), nil)
+			}
+			policy := flagx.MustGetString(cmd, passwordPolicyFlag)
+			if !containsString(passwordPolicies, policy) {
+				return client.NewValidationError(errors.Errorf("--%s must be one of %s", passwordPolicyFlag, strings.Join(passwordPolicies, ", ")), nil)
+			}
+			domain := strings.ToLower(flagx.MustGetString(cmd, domainFlag))
+			if domain == "" {
+				return client.NewValidationError(errors.Errorf("--%s must be set", domainFlag), nil)
+			}
+			opts, err := readImportOptions(cmd)
+			if err != nil {
+				return err
+			}
+			seed := time.Now().UnixNano()
+			if cmd.Flags().Changed(seedFlag) {
+				seed, _ = cmd.Flags().GetInt64(seedFlag)
+			}
+
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+			api, err := h.NewServiceAPI(client.ServiceIdentity)
+			if err != nil {
+				return err
+			}
+			if api.Project != nil && flagx.MustGetString(cmd, allowProductionFlag) != api.Project.Slug {
+				return client.NewValidationError(errors.Errorf("refusing to create fake identities in project %s, confirm the project with --%s %s", api.Project.Slug, allowProductionFlag, api.Project.Slug), nil)
+			}
+			if !isTestDomain(domain) {
+				h.Log.Warnf("The domain %s is not reserved for testing, emails sent to the generated identities may reach real people.", domain)
+			}
+
+			schemaID := flagx.MustGetString(cmd, schemaFlag)
+			if schemaID == "" {
+				schemaID = projectSchemaID(api)
+			}
+			if schemaID == "" {
+				schemaID = fallbackSchemaID
+			}
+			schema, err := fetchIdentitySchema(cmd.Context(), api, schemaID)
+			if err != nil {
+				return err
+			}
+
+			g, err := newIdentityGenerator(schemaID, schema, domain, policy, seed)
+			if err != nil {
+				return err
+			}
+			records, credentials, err := g.generateAll(n)
+			if err != nil {
+				return err
+			}
+			h.Log.Infof("Generated %d identities with --%s %d.", n, seedFlag, seed)
+
+			summary := newIdentityImporter(h, api, opts, len(records), nil).run(cmd.Context(), records)
+			switch len(summary.imported) {
+			case 0:
+			case 1:
+				client.PrintRow(cmd, summary.imported[0])
+			default:
+				client.PrintTable(cmd, summary.imported)
+			}
+			client.PrintIDs(cmd, summary.imported.ids()...)
+
+			if file := flagx.MustGetString(cmd, credentialsOutFlag); file != "" {
+				if err := writeCredentials(file, summary, credentials); err != nil {
+					return err
+				}
+				h.Log.Infof("Wrote the credentials of %d identities to %s.", len(summary.imported), file)
+			}
+
+			h.Log.Infof("Imported: %d, failed: %d, requests: %d", len(summary.imported), summary.failed, summary.requests)
+			if summary.interrupted {
+				return client.NewInterruptedError("the import was interrupted, the summary contains the partial results")
+			} else if summary.stopped != nil {
+				return errors.Wrap(summary.stopped, "the API seems to be unavailable, try again later")
+			} else if summary.failed > 0 {
+				return client.FailSilently(cmd, client.ExitFailure)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(generateFlag, 0, "The number of identities to generate.")
+	client.RegisterIDOnlyFlag(cmd.Flags())
+	cmd.Flags().String(schemaFlag, "", "The ID of the identity schema of the identities. Defaults to the default schema of the project.")
+	cmd.Flags().String(domainFlag, "example.test", "The domain of the generated email addresses.")
+	cmd.Flags().String(passwordPolicyFlag, passwordRandom, fmt.Sprintf("How passwords are set, one of %s.", strings.Join(passwordPolicies, ", ")))
+	cmd.Flags().String(credentialsOutFlag, "", "Write the credentials of the imported identities to this CSV file. Existing files are overwritten.")
+	cmd.Flags().Int64(seedFlag, 0, "The seed of the generator. The same seed generates the same identities. Defaults to a random seed.")
+	cmd.Flags().String(allowProductionFlag, "", "The slug of the project, to confirm that the identities are created in it.")
+	client.RegisterProjectFlag(cmd.Flags())
+	registerImportFlags(cmd.Flags())
+	return cmd
+}
+
+func isTestDomain(domain string) bool {
+	for _, d := range testDomains {
+		if strings.HasPrefix(d, ".") && strings.HasSuffix(domain, d) || domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCredentials writes the credentials of the imported identities as CSV. Only the owner can read the file.
+func writeCredentials(file string, summary *importSummary, credentials []generatedCredentials) error {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write([]string{"id", "identifier", "password"})
+	for k, identity := range summary.imported {
+		var imported struct {
+			ID string `json:"id"`
+		}
+		_ = json.Unmarshal(identity, &imported)
+		c := credentials[summary.indices[k]]
+		_ = w.Write([]string{imported.ID, c.Identifier, c.Password})
+	}
+	w.Flush()
+	if err := os.WriteFile(file, []byte(b.String()), 0600); err != nil {
+		return errors.Wrapf(err, "unable to write %s", file)
+	}
+	return nil
+}
+
+var (
+	firstNames = []string{
+		"Ada", "Alan", "Amara", "Bea", "Carlos", "Chen", "Dana", "Elif", "Emil", "Fatima", "Grace", "Hannah", "Ines",
+		"Jamal", "Jonas", "Kenji", "Lena", "Liam", "Maya", "Mateo", "Nia", "Noah", "Olga", "Priya", "Ravi", "Sofia",
+		"Tariq", "Uma", "Wei", "Yara", "Zoe",
+	}
+	lastNames = []string{
+		"Andersen", "Baker", "Costa", "Dubois", "Eriksson", "Fischer", "Garcia", "Haddad", "Ito", "Jensen", "Kowalski",
+		"Lopez", "Mbeki", "Nakamura", "Novak", "Okafor", "Patel", "Quinn", "Rossi", "Schmidt", "Silva", "Tanaka",
+		"Umarov", "Varga", "Weber", "Xu", "Yilmaz", "Zhang",
+	}
+	words = []string{"alpha", "bravo", "cedar", "delta", "ember", "falcon", "granite", "harbor", "indigo", "juniper", "kelp", "lumen"}
+
+	// areaCodes are combined with the fictional 555-01XX numbers, which exist in every area code.
+	areaCodes = []string{
+		"201", "202", "203", "205", "206", "207", "208", "209", "210", "212", "213", "214", "215", "216", "217", "218",
+		"219", "224", "225", "228", "229", "231", "234", "239", "240", "248", "251", "252", "253", "254", "256", "260",
+		"262", "267", "269", "270", "276", "281", "301", "302", "303", "304", "305", "307", "308", "309", "310", "312",
+		"313", "314", "315", "316", "317", "318", "319", "320", "321", "323", "325", "330",
+	}
+)
+
+// generatedCredentials are the credentials load tests sign in with.
+type generatedCredentials struct {
+	Identifier string
+	Password   string
+}
+
+// identityGenerator synthesizes identities which conform to an identity schema. All randomness comes from the seeded
+// source, so that the same seed generates the same identities.
+type identityGenerator struct {
+	schemaID string
+	root     map[string]interface{}
+	traits   map[string]interface{}
+	domain   string
+	policy   string
+	rand     *rand.Rand
+	shared   string
+}
+
+// generation is the state of generating one identity.
+type generation struct {
+	first, last, handle, email, phone string
+	addresses                         []map[string]interface{}
+	identifier                        string
+}
+
+func newIdentityGenerator(schemaID string, schema map[string]interface{}, domain, policy string, seed int64) (*identityGenerator, error) {
+	traits, ok := lookupSchema(schema, "properties", "traits")
+	if !ok {
+		return nil, errors.Errorf("the identity schema %q does not define the traits property", schemaID)
+	}
+	g := &identityGenerator{schemaID: schemaID, root: schema, domain: domain, policy: policy, rand: rand.New(rand.NewSource(seed))}
+	g.traits = g.resolve(traits)
+	if policy == passwordShared {
+		g.shared = g.password()
+	}
+	return g, nil
+}
+
+// generateAll generates n identities and their credentials, in the same order.
+func (g *identityGenerator) generateAll(n int) ([]json.RawMessage, []generatedCredentials, error) {
+	records := make([]json.RawMessage, n)
+	credentials := make([]generatedCredentials, n)
+	for i := range records {
+		s := g.person(i)
+		identity := map[string]interface{}{
+			"schema_id": g.schemaID,
+			"state":     "active",
+			"traits":    g.value(g.traits, "traits", s),
+		}
+		if len(s.addresses) > 0 {
+			identity["verifiable_addresses"] = s.addresses
+		}
+
+		c := generatedCredentials{Identifier: s.identifier}
+		switch g.policy {
+		case passwordRandom:
+			c.Password = g.password()
+		case passwordShared:
+			c.Password = g.shared
+		}
+		if c.Password != "" {
+			if s.identifier == "" {
+				return nil, nil, client.NewValidationError(errors.Errorf("the identity schema %q has no password identifier, use --%s %s", g.schemaID, passwordPolicyFlag, passwordNone), nil)
+			}
+			identity["credentials"] = map[string]interface{}{
+				"password": map[string]interface{}{"config": map[string]interface{}{"password": c.Password}},
+			}
+		}
+		if c.Identifier == "" {
+			c.Identifier = s.email
+		}
+
+		out, err := json.Marshal(identity)
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		records[i] = out
+		credentials[i] = c
+	}
+	return records, credentials, nil
+}
+
+// person picks the fake person of the i-th identity. The email address, handle, and phone number contain the index, so
+// that they are unique.
+func (g *identityGenerator) person(i int) *generation {
+	first, last := firstNames[g.rand.Intn(len(firstNames))], lastNames[g.rand.Intn(len(lastNames))]
+	handle := strings.ToLower(first+"."+last) + strconv.Itoa(i+1)
+	return &generation{
+		first:  first,
+		last:   last,
+		handle: handle,
+		email:  handle + "@" + g.domain,
+		phone:  fmt.Sprintf("+1%s55501%02d", areaCodes[(i/100)%len(areaCodes)], i%100),
+	}
+}
+
+// value generates a value of the schema. The name of the property hints at what the value is, e.g. a first name.
+func (g *identityGenerator) value(schema map[string]interface{}, name string, s *generation) interface{} {
+	schema = g.resolve(schema)
+	if v, ok := schema["const"]; ok {
+		return v
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[g.rand.Intn(len(enum))]
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		obj := map[string]interface{}{}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, key := range sortedKeys(properties) {
+			if sub, ok := properties[key].(map[string]interface{}); ok {
+				obj[key] = g.value(sub, key, s)
+			}
+		}
+		return obj
+	case "array":
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return []interface{}{}
+		}
+		n := 1
+		if min, ok := schema["minItems"].(float64); ok && int(min) > n {
+			n = int(min)
+		}
+		list := make([]interface{}, n)
+		for k := range list {
+			list[k] = g.value(items, name, s)
+		}
+		return list
+	case "boolean":
+		return g.rand.Intn(2) == 0
+	case "integer":
+		min, max := g.bounds(schema)
+		return int64(min) + g.rand.Int63n(int64(max-min)+1)
+	case "number":
+		min, max := g.bounds(schema)
+		return min + g.rand.Float64()*(max-min)
+	case "null":
+		return nil
+	}
+	return g.string(schema, name, s)
+}
+
+// string generates a string trait and records its Ory extensions, e.g. that the trait is a verifiable address.
+func (g *identityGenerator) string(schema map[string]interface{}, name string, s *generation) string {
+	ext, _ := schema[kratosExtension].(map[string]interface{})
+	channel := addressChannel(ext)
+	format, _ := schema["format"].(string)
+	name = strings.ToLower(name)
+
+	var v string
+	switch {
+	case channel == "email" || format == "email" || strings.Contains(name, "email"):
+		v = s.email
+	case channel == "sms" || format == "tel" || strings.Contains(name, "phone"):
+		v = s.phone
+	case format == "uri" || format == "url" || format == "uri-reference":
+		v = "https://" + g.domain + "/users/" + s.handle
+	case format == "date":
+		v = time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, g.rand.Intn(55*365)).Format("2006-01-02")
+	case format == "date-time":
+		v = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(g.rand.Int63n(3*365*24)) * time.Hour).Format(time.RFC3339)
+	case strings.Contains(name, "first") || strings.Contains(name, "given"):
+		v = fitLength(schema, s.first)
+	case strings.Contains(name, "last") || strings.Contains(name, "family") || strings.Contains(name, "surname"):
+		v = fitLength(schema, s.last)
+	case strings.Contains(name, "user") || strings.Contains(name, "nick") || strings.Contains(name, "handle"):
+		v = fitLength(schema, s.handle)
+	case strings.Contains(name, "name"):
+		v = fitLength(schema, s.first+" "+s.last)
+	default:
+		v = fitLength(schema, words[g.rand.Intn(len(words))])
+	}
+
+	if channel != "" {
+		verified := g.rand.Intn(2) == 0
+		status := "pending"
+		if verified {
+			status = "completed"
+		}
+		s.addresses = append(s.addresses, map[string]interface{}{"value": v, "via": channel, "verified": verified, "status": status})
+	}
+	if credentials, ok := ext["credentials"].(map[string]interface{}); ok && s.identifier == "" {
+		if password, ok := credentials["password"].(map[string]interface{}); ok && password["identifier"] == true {
+			s.identifier = v
+		}
+	}
+	return v
+}
+
+// password returns a random password which is long enough for the password policy of Ory.
+func (g *identityGenerator) password() string {
+	const alphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	b := make([]byte, 20)
+	for k := range b {
+		b[k] = alphabet[g.rand.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+func (g *identityGenerator) bounds(schema map[string]interface{}) (float64, float64) {
+	min, ok := schema["minimum"].(float64)
+	if !ok {
+		min = 0
+	}
+	max, ok := schema["maximum"].(float64)
+	if !ok || max < min {
+		max = min + 100
+	}
+	return min, max
+}
+
+// resolve follows references within the schema, e.g. to its definitions.
+func (g *identityGenerator) resolve(schema map[string]interface{}) map[string]interface{} {
+	for depth := 0; depth < 10; depth++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return schema
+		}
+		var path []string
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			path = append(path, strings.NewReplacer("~1", "/", "~0", "~").Replace(token))
+		}
+		resolved, ok := lookupSchema(g.root, path...)
+		if !ok {
+			return schema
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+// addressChannel returns the channel through which the trait is verified or recovered, or an empty string.
+func addressChannel(ext map[string]interface{}) string {
+	for _, key := range []string{"verification", "recovery"} {
+		config, _ := ext[key].(map[string]interface{})
+		if via, ok := config["via"].(string); ok {
+			return via
+		}
+	}
+	credentials, _ := ext["credentials"].(map[string]interface{})
+	code, _ := credentials["code"].(map[string]interface{})
+	via, _ := code["via"].(string)
+	return via
+}
+
+// schemaType returns the type of the schema. Of multiple types, the first which is not null is used.
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return "string"
+}
+
+// fitLength pads or truncates the value to the length limits of the schema.
+func fitLength(schema map[string]interface{}, v string) string {
+	if max, ok := schema["maxLength"].(float64); ok && len(v) > int(max) {
+		v = v[:int(max)]
+	}
+	if min, ok := schema["minLength"].(float64); ok && len(v) < int(min) {
+		v += strings.Repeat("x", int(min)-len(v))
+	}
+	return v
+}
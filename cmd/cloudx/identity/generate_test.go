@@ -0,0 +1,127 @@
+package identity
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityGenerator(t *testing.T) {
+	schema, err := decodeSchema([]byte(`{
+  "definitions": {"name": {"type": "object", "properties": {"first": {"type": "string"}, "last": {"type": "string", "maxLength": 4}}}},
+  "type": "object",
+  "properties": {
+    "traits": {
+      "type": "object",
+      "properties": {
+        "email": {
+          "type": "string",
+          "format": "email",
+          "ory.sh/kratos": {"credentials": {"password": {"identifier": true}}, "verification": {"via": "email"}}
+        },
+        "phone": {"type": "string", "format": "tel"},
+        "name": {"$ref": "#/definitions/name"},
+        "plan": {"type": "string", "enum": ["free", "pro"]},
+        "age": {"type": "integer", "minimum": 18, "maximum": 99},
+        "newsletter": {"type": "boolean"}
+      }
+    }
+  }
+}`))
+	require.NoError(t, err)
+
+	generate := func(t *testing.T, seed int64, policy string) ([]json.RawMessage, []generatedCredentials) {
+		g, err := newIdentityGenerator("customer", schema, "example.test", policy, seed)
+		require.NoError(t, err)
+		records, credentials, err := g.generateAll(3)
+		require.NoError(t, err)
+		return records, credentials
+	}
+
+	t.Run("case=conforms to the schema", func(t *testing.T) {
+		records, credentials := generate(t, 42, passwordRandom)
+		require.Len(t, records, 3)
+
+		var identity struct {
+			SchemaID string `json:"schema_id"`
+			Traits   struct {
+				Email string `json:"email"`
+				Phone string `json:"phone"`
+				Name  struct {
+					First string `json:"first"`
+					Last  string `json:"last"`
+				} `json:"name"`
+				Plan string `json:"plan"`
+				Age  int    `json:"age"`
+			} `json:"traits"`
+			VerifiableAddresses []struct {
+				Value string `json:"value"`
+				Via   string `json:"via"`
+			} `json:"verifiable_addresses"`
+			Credentials struct {
+				Password struct {
+					Config struct {
+						Password string `json:"password"`
+					} `json:"config"`
+				} `json:"password"`
+			} `json:"credentials"`
+		}
+		require.NoError(t, json.Unmarshal(records[0], &identity))
+		assert.Equal(t, "customer", identity.SchemaID)
+		assert.True(t, strings.HasSuffix(identity.Traits.Email, "1@example.test"), identity.Traits.Email)
+		assert.True(t, strings.HasPrefix(identity.Traits.Phone, "+1201555010"), identity.Traits.Phone)
+		assert.Contains(t, firstNames, identity.Traits.Name.First)
+		assert.LessOrEqual(t, len(identity.Traits.Name.Last), 4)
+		assert.Contains(t, []string{"free", "pro"}, identity.Traits.Plan)
+		assert.True(t, identity.Traits.Age >= 18 && identity.Traits.Age <= 99, identity.Traits.Age)
+		require.Len(t, identity.VerifiableAddresses, 1)
+		assert.Equal(t, identity.Traits.Email, identity.VerifiableAddresses[0].Value)
+		assert.Equal(t, "email", identity.VerifiableAddresses[0].Via)
+
+		assert.Equal(t, identity.Traits.Email, credentials[0].Identifier)
+		assert.Equal(t, identity.Credentials.Password.Config.Password, credentials[0].Password)
+		assert.NotEqual(t, credentials[0].Password, credentials[1].Password)
+	})
+
+	t.Run("case=the seed makes the identities reproducible", func(t *testing.T) {
+		first, _ := generate(t, 7, passwordRandom)
+		second, _ := generate(t, 7, passwordRandom)
+		other, _ := generate(t, 8, passwordRandom)
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first, other)
+	})
+
+	t.Run("case=password policies", func(t *testing.T) {
+		_, shared := generate(t, 1, passwordShared)
+		assert.NotEmpty(t, shared[0].Password)
+		assert.Equal(t, shared[0].Password, shared[2].Password)
+
+		records, none := generate(t, 1, passwordNone)
+		assert.Empty(t, none[0].Password)
+		assert.NotContains(t, string(records[0]), "credentials")
+	})
+
+	t.Run("case=passwords require an identifier", func(t *testing.T) {
+		schema, err := decodeSchema([]byte(`{"properties": {"traits": {"type": "object", "properties": {"name": {"type": "string"}}}}}`))
+		require.NoError(t, err)
+		g, err := newIdentityGenerator("default", schema, "example.test", passwordRandom, 1)
+		require.NoError(t, err)
+		_, _, err = g.generateAll(1)
+		assert.ErrorContains(t, err, "no password identifier")
+	})
+}
+
+func TestIsTestDomain(t *testing.T) {
+	for domain, expected := range map[string]bool{
+		"example.test":     true,
+		"mail.example.com": true,
+		"example.com":      true,
+		"myexample.com":    false,
+		"ory.sh":           false,
+	} {
+		assert.Equal(t, expected, isTestDomain(domain), domain)
+	}
+}
@@ -5,12 +5,18 @@ import (
 
 	"github.com/ory/cli/cmd/cloudx/client"
 	"github.com/ory/kratos/cmd/identities"
-	"github.com/ory/x/cmdx"
 )
 
 func NewGetIdentityCmd(parent *cobra.Command) *cobra.Command {
 	cmd := identities.NewGetIdentityCmd(parent)
+	cmd.Args = client.MinimumNArgs(1, client.UUID("an identity ID"))
 	client.RegisterProjectFlag(cmd.Flags())
-	cmdx.RegisterFormatFlags(cmd.Flags())
+	cmd.Example = `$ ory get identity 9f425a8d-7efc-4768-8f23-7647a74fdf13 --project my-project
+
+$ ory get identity 9f425a8d-7efc-4768-8f23-7647a74fdf13 5b1e7c2d-3f4a-4b6c-8d9e-0f1a2b3c4d5e --project my-project --format json`
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[client.QueryExampleAnnotation] = "traits.email"
 	return cmd
 }
@@ -1,14 +1,427 @@
 package identity
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/client/pool"
 	"github.com/ory/kratos/cmd/identities"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	stateFileFlag = "state-file"
+	batchSizeFlag = "batch-size"
+
+	// maxBatchSize is the maximum number of identities the API accepts in one batch request.
+	maxBatchSize = 1000
+
+	// throttledRetries is how often the import of an identity is retried after the API rate limited it.
+	throttledRetries = 10
 )
 
 func NewImportIdentityCmd(parent *cobra.Command) *cobra.Command {
 	cmd := identities.NewImportIdentitiesCmd(parent)
 	client.RegisterProjectFlag(cmd.Flags())
+	registerImportFlags(cmd.Flags())
+	cmd.Flags().String(stateFileFlag, "", "Save which identities were imported to this file, so that an aborted import can be resumed by running the command again.")
+	cmd.Long += `
+
+Each file contains one identity, a JSON array of identities, or one identity per line. The identities are imported in
+batches of --batch-size identities by --workers concurrent requests, and identities which could not be imported are
+logged. If the API does not support batch requests, every identity is created with its own request.
+
+Large imports can be resumed with --state-file. The file records which identities were processed, and is saved
+periodically and when the import stops, e.g. because it was interrupted with Ctrl-C or because --max-failures
+requests failed in a row. Running the command again with
+the same state file skips the identities which were already imported and only imports the failed and the remaining
+ones. The state file is bound to the content of the input files, resuming with modified files is refused. Once all
+identities were imported, the state file is removed.`
+	cmd.Example = `$ ory import identities --project my-project identity-1.json identity-2.json
+
+$ cat identities.json | ory import identities --project my-project --format json
+
+$ ory import identities --project my-project --workers 16 --state-file import.checkpoint identities.ndjson`
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		h, err := client.NewCommandHelper(cmd)
+		if err != nil {
+			return err
+		}
+		opts, err := readImportOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			args = []string{client.StdinFile}
+		}
+		records, hash, err := readIdentities(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		var checkpoint *client.Checkpoint
+		if file := flagx.MustGetString(cmd, stateFileFlag); file != "" {
+			if checkpoint, err = client.LoadCheckpoint(file, hash, len(records)); err != nil {
+				return err
+			}
+		}
+
+		api, err := h.NewServiceAPI(client.ServiceIdentity)
+		if err != nil {
+			return err
+		}
+
+		summary := newIdentityImporter(h, api, opts, len(records), checkpoint).run(cmd.Context(), records)
+
+		switch len(summary.imported) {
+		case 0:
+		case 1:
+			client.PrintRow(cmd, summary.imported[0])
+		default:
+			client.PrintTable(cmd, summary.imported)
+		}
+
+		h.Log.Infof("Imported: %d, skipped: %d, failed: %d, requests: %d", len(summary.imported), summary.skipped, summary.failed, summary.requests)
+		if checkpoint != nil {
+			kept, err := checkpoint.Finish()
+			if err != nil {
+				return err
+			}
+			if kept {
+				file := flagx.MustGetString(cmd, stateFileFlag)
+				h.Log.Warnf("The state of the import was saved to %s, run the command again with --%s %s to import the remaining identities.", file, stateFileFlag, file)
+			}
+		}
+
+		if summary.interrupted {
+			return client.NewInterruptedError("the import was interrupted, the summary contains the partial results")
+		} else if summary.stopped != nil {
+			if checkpoint == nil {
+				h.Log.Warnf("Use --%s to resume the import where it stopped.", stateFileFlag)
+			}
+			return errors.Wrap(summary.stopped, "the API seems to be unavailable, try again later")
+		} else if summary.failed > 0 {
+			return client.FailSilently(cmd, client.ExitFailure)
+		}
+		return nil
+	}
 	return cmd
 }
+
+// importOptions tune the import pipeline, which is shared by the commands creating identities in bulk.
+type importOptions struct {
+	workers     int
+	maxFailures int
+	batchSize   int
+}
+
+func registerImportFlags(f *pflag.FlagSet) {
+	client.RegisterWorkersFlag(f)
+	client.RegisterMaxFailuresFlag(f)
+	f.Int(batchSizeFlag, 100, fmt.Sprintf("The number of identities created by one request, at most %d. Use 1 to create every identity with its own request.", maxBatchSize))
+}
+
+func readImportOptions(cmd *cobra.Command) (opts importOptions, err error) {
+	if opts.workers, err = client.Workers(cmd); err != nil {
+		return opts, err
+	}
+	if opts.maxFailures, err = client.MaxFailures(cmd); err != nil {
+		return opts, err
+	}
+	opts.batchSize = flagx.MustGetInt(cmd, batchSizeFlag)
+	if opts.batchSize < 1 || opts.batchSize > maxBatchSize {
+		return opts, client.NewValidationError(errors.Errorf("--%s must be between 1 and %d", batchSizeFlag, maxBatchSize), nil)
+	}
+	return opts, nil
+}
+
+// newIdentityImporter returns an importer of total records. The checkpoint is optional.
+func newIdentityImporter(h *client.CommandHelper, api *client.ProjectAPI, opts importOptions, total int, checkpoint *client.Checkpoint) *identityImporter {
+	return &identityImporter{
+		api:         api,
+		workers:     opts.workers,
+		batchSize:   opts.batchSize,
+		maxFailures: opts.maxFailures,
+		throttle:    h.NewThrottle(opts.workers),
+		progress:    h.NewProgress("Importing identities", total),
+		checkpoint:  checkpoint,
+		log:         h.Log,
+	}
+}
+
+// readIdentities reads the identities from the files and returns them in order, together with the hash of the
+// content of all files. Each file contains JSON values which are either an identity or an array of identities.
+func readIdentities(cmd *cobra.Command, files []string) ([]json.RawMessage, string, error) {
+	hash := sha256.New()
+	var records []json.RawMessage
+	for _, file := range files {
+		f, err := client.OpenInputFile(cmd, file)
+		if err != nil {
+			return nil, "", err
+		}
+		content, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "unable to read file: %s", client.InputFileName(file))
+		}
+		_, _ = hash.Write(content)
+		// Separate the files, so that moving content between files changes the hash.
+		_, _ = hash.Write([]byte{0})
+
+		dec := json.NewDecoder(bytes.NewReader(content))
+		for {
+			var v json.RawMessage
+			if err := dec.Decode(&v); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, "", client.NewValidationError(errors.Wrapf(err, "unable to parse identities in file: %s", client.InputFileName(file)), nil)
+			}
+
+			if v = bytes.TrimSpace(v); len(v) > 0 && v[0] == '[' {
+				var list []json.RawMessage
+				if err := json.Unmarshal(v, &list); err != nil {
+					return nil, "", client.NewValidationError(errors.Wrapf(err, "unable to parse identities in file: %s", client.InputFileName(file)), nil)
+				}
+				records = append(records, list...)
+				continue
+			}
+			records = append(records, v)
+		}
+	}
+	return records, "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+type identityImporter struct {
+	api         *client.ProjectAPI
+	workers     int
+	batchSize   int
+	maxFailures int
+	throttle    *client.Throttle
+	progress    *client.Progress
+	log         *client.Logger
+	checkpoint  *client.Checkpoint
+	// breaker stops the import once maxFailures requests failed in a row.
+	breaker *pool.Breaker
+
+	// noBatches is set once the API rejected the batch route, after which every identity is sent on its own.
+	noBatches   int32
+	unsupported sync.Once
+	requests    int64
+}
+
+type importSummary struct {
+	imported outputIdentities
+	// indices are the indices of the imported records, in the order of imported.
+	indices     []int
+	skipped     int
+	failed      int
+	requests    int
+	interrupted bool
+	// stopped is set if the import stopped because too many requests failed in a row.
+	stopped error
+}
+
+// run imports the records which did not succeed in a previous run. An interrupt or too many failures in a row stop
+// starting new batches, but the requests which were already sent finish, so that their outcome is recorded in the
+// checkpoint.
+func (i *identityImporter) run(ctx context.Context, records []json.RawMessage) *importSummary {
+	interrupt, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	defer cancel()
+	stop, breaker := client.NewBreaker(interrupt, i.maxFailures)
+	i.breaker = breaker
+	ctx = client.WithoutCancel(ctx)
+
+	summary := new(importSummary)
+	var pending []int
+	for k := range records {
+		if i.checkpoint != nil && i.checkpoint.Succeeded(k) {
+			summary.skipped++
+			continue
+		}
+		pending = append(pending, k)
+	}
+	if summary.skipped > 0 {
+		i.log.Infof("Skipping %d identities which were imported by a previous run.", summary.skipped)
+		i.progress.Add(summary.skipped, 0)
+	}
+
+	var batches [][]int
+	for len(pending) > 0 {
+		n := i.batchSize
+		if n > len(pending) {
+			n = len(pending)
+		}
+		batches = append(batches, pending[:n])
+		pending = pending[n:]
+	}
+
+	imported := make([]json.RawMessage, len(records))
+	errs := make([]error, len(records))
+	var checkpointErr error
+	batchErrs := pool.Stream(stop, i.workers, len(batches), func(_ context.Context, b int) error {
+		return i.importBatch(ctx, stop, records, batches[b], imported, errs)
+	}, func(b int, err error) {
+		if err != nil && stop.Err() == nil {
+			i.progress.Logf("Unable to import batch of %d identities: %s", len(batches[b]), err)
+		}
+		for _, k := range batches[b] {
+			if err != nil {
+				errs[k] = err
+			} else if errs[k] != nil && stop.Err() == nil {
+				i.progress.Logf("Unable to import identity %d: %s", k+1, errs[k])
+			}
+			if errs[k] == nil {
+				i.progress.Add(1, 0)
+			} else {
+				i.progress.Add(1, 1)
+			}
+
+			// Identities which were not sent because the import stopped early are imported once it is resumed.
+			if i.checkpoint == nil || checkpointErr != nil || (errs[k] != nil && stop.Err() != nil && errors.Is(errs[k], context.Canceled)) {
+				continue
+			}
+			if checkpointErr = i.checkpoint.Record(k, errs[k]); checkpointErr != nil {
+				i.progress.Logf("Unable to save the state of the import: %s", checkpointErr)
+			}
+		}
+	})
+
+	for b, err := range batchErrs {
+		for _, k := range batches[b] {
+			if err != nil || errs[k] != nil {
+				summary.failed++
+				continue
+			}
+			summary.imported = append(summary.imported, outputIdentity(imported[k]))
+			summary.indices = append(summary.indices, k)
+		}
+	}
+	summary.requests = int(atomic.LoadInt64(&i.requests))
+	summary.interrupted = interrupt.Err() != nil
+	summary.stopped = breaker.Err()
+	if summary.stopped != nil && !summary.interrupted {
+		i.progress.Halt()
+	} else {
+		i.progress.Finish(summary.interrupted)
+	}
+	return summary
+}
+
+// batchImportRequest and batchImportResponse are the body and the response of the batch endpoint. The results are
+// in the order of the identities in the request.
+type (
+	batchImportRequest struct {
+		Identities []batchImportItem `json:"identities"`
+	}
+	batchImportItem struct {
+		Create json.RawMessage `json:"create"`
+	}
+	batchImportResponse struct {
+		Identities []struct {
+			Action   string          `json:"action"`
+			Identity string          `json:"identity"`
+			Error    json.RawMessage `json:"error"`
+		} `json:"identities"`
+	}
+)
+
+// importBatch imports the records with the given indices and stores the created identities in imported and the
+// error of every record in errs. Batches are sent as one request if the API supports it, and record by record
+// otherwise. It returns an error if the batch request failed as a whole.
+func (i *identityImporter) importBatch(ctx, stop context.Context, records []json.RawMessage, batch []int, imported []json.RawMessage, errs []error) error {
+	if len(batch) > 1 && atomic.LoadInt32(&i.noBatches) == 0 {
+		body := batchImportRequest{Identities: make([]batchImportItem, len(batch))}
+		for n, k := range batch {
+			body.Identities[n] = batchImportItem{Create: records[k]}
+		}
+
+		var res batchImportResponse
+		err := i.send(ctx, stop, http.MethodPatch, body, &res)
+		var apiErr *client.APIError
+		switch {
+		case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed):
+			atomic.StoreInt32(&i.noBatches, 1)
+			i.unsupported.Do(func() {
+				i.progress.Logf("The API does not support batch imports, every identity is created with its own request.")
+			})
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest:
+			// The batch was rejected as a whole, sending the identities one by one attributes the error to them.
+		case err != nil:
+			return err
+		case len(res.Identities) != len(batch):
+			return errors.Errorf("the API returned %d results for a batch of %d identities", len(res.Identities), len(batch))
+		default:
+			for n, k := range batch {
+				result := res.Identities[n]
+				if result.Action == "error" || len(result.Error) > 0 && string(result.Error) != "null" {
+					errs[k] = errors.Errorf("the API rejected the identity: %s", bytes.TrimSpace(result.Error))
+					continue
+				}
+				imported[k] = withIdentityID(records[k], result.Identity)
+			}
+			return nil
+		}
+	}
+
+	for _, k := range batch {
+		if err := stop.Err(); err != nil {
+			// The identities which were imported before the interrupt keep their result.
+			errs[k] = errors.WithStack(err)
+			continue
+		}
+		var out json.RawMessage
+		if errs[k] = i.send(ctx, stop, http.MethodPost, records[k], &out); errs[k] == nil {
+			imported[k] = out
+		}
+	}
+	return nil
+}
+
+// send waits for the throttle and sends the request to the identities endpoint. Rate limited requests are retried.
+func (i *identityImporter) send(ctx, stop context.Context, method string, body, out interface{}) error {
+	for throttled := 0; ; throttled++ {
+		if err := i.throttle.Acquire(stop); err != nil {
+			return errors.WithStack(err)
+		}
+		atomic.AddInt64(&i.requests, 1)
+		res, err := i.api.Do(ctx, method, "/admin/identities", nil, body, out)
+		i.throttle.Release(res)
+		i.breaker.Record(err)
+		if err == nil || !client.IsThrottled(err) || throttled >= throttledRetries {
+			return err
+		}
+	}
+}
+
+// withIdentityID returns the imported record with the ID of the created identity, because the batch endpoint only
+// returns the IDs. Like the API, it does not return the credentials.
+func withIdentityID(record json.RawMessage, id string) json.RawMessage {
+	var identity map[string]interface{}
+	if err := json.Unmarshal(record, &identity); err != nil {
+		identity = map[string]interface{}{}
+	}
+	identity["id"] = id
+	delete(identity, "credentials")
+	out, err := json.Marshal(identity)
+	if err != nil {
+		return record
+	}
+	return out
+}
@@ -10,5 +10,12 @@ import (
 func NewListIdentityCmd(parent *cobra.Command) *cobra.Command {
 	cmd := identities.NewListIdentitiesCmd(parent)
 	client.RegisterProjectFlag(cmd.Flags())
+	cmd.Example = `$ ory list identities --project my-project
+
+$ ory list identities --project my-project --format json`
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[client.QueryExampleAnnotation] = "traits.email"
 	return cmd
 }
@@ -0,0 +1,96 @@
+package identity
+
+import "encoding/json"
+
+// outputIdentityID prints the ID of a deleted identity like the Ory Kratos commands do.
+type outputIdentityID string
+
+func (outputIdentityID) Header() []string {
+	return []string{"ID"}
+}
+
+func (i outputIdentityID) Columns() []string {
+	return []string{string(i)}
+}
+
+func (i outputIdentityID) Interface() interface{} {
+	return string(i)
+}
+
+type outputIdentityIDs []string
+
+func (outputIdentityIDs) Header() []string {
+	return []string{"ID"}
+}
+
+func (c outputIdentityIDs) Table() [][]string {
+	rows := make([][]string, len(c))
+	for k, id := range c {
+		rows[k] = []string{id}
+	}
+	return rows
+}
+
+func (c outputIdentityIDs) Interface() interface{} {
+	return []string(c)
+}
+
+func (c outputIdentityIDs) Len() int {
+	return len(c)
+}
+
+// outputIdentity prints an imported identity. The JSON output is the identity as returned by the API.
+type outputIdentity json.RawMessage
+
+func (outputIdentity) Header() []string {
+	return []string{"ID", "SCHEMA ID", "STATE"}
+}
+
+func (i outputIdentity) Columns() []string {
+	var identity struct {
+		ID       string `json:"id"`
+		SchemaID string `json:"schema_id"`
+		State    string `json:"state"`
+	}
+	_ = json.Unmarshal(i, &identity)
+	return []string{identity.ID, identity.SchemaID, identity.State}
+}
+
+func (i outputIdentity) Interface() interface{} {
+	return json.RawMessage(i)
+}
+
+type outputIdentities []outputIdentity
+
+func (outputIdentities) Header() []string {
+	return outputIdentity(nil).Header()
+}
+
+func (c outputIdentities) Table() [][]string {
+	rows := make([][]string, len(c))
+	for k, i := range c {
+		rows[k] = i.Columns()
+	}
+	return rows
+}
+
+func (c outputIdentities) Interface() interface{} {
+	identities := make([]json.RawMessage, len(c))
+	for k, i := range c {
+		identities[k] = json.RawMessage(i)
+	}
+	return identities
+}
+
+func (c outputIdentities) Len() int {
+	return len(c)
+}
+
+// ids returns the IDs of the identities in order.
+func (c outputIdentities) ids() []string {
+	ids := make([]string, len(c))
+	for k, i := range c {
+		ids[k] = i.Columns()[0]
+	}
+	return ids
+}
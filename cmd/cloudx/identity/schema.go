@@ -0,0 +1,362 @@
+package identity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/jsonschema/v3"
+)
+
+// kratosExtension is the keyword of the Ory extensions in identity schemas.
+const kratosExtension = "ory.sh/kratos"
+
+// The levels of a schemaProblem. Only errors fail the validation.
+const (
+	schemaError   = "error"
+	schemaWarning = "warning"
+)
+
+type (
+	// schemaProblem is a problem of an identity schema at a JSON pointer into the schema file.
+	schemaProblem struct {
+		File    string `json:"file"`
+		Pointer string `json:"pointer"`
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+	schemaProblems []schemaProblem
+)
+
+func (p schemaProblems) String() string {
+	var b strings.Builder
+	for _, problem := range p {
+		_, _ = fmt.Fprintf(&b, "%s#%s: %s: %s\n", problem.File, problem.Pointer, problem.Level, problem.Message)
+	}
+	return b.String()
+}
+
+// Annotations reports the problems in GitHub Actions. The pointer is used as title, because the problems have no
+// line numbers.
+func (p schemaProblems) Annotations() []client.Annotation {
+	annotations := make([]client.Annotation, 0, len(p))
+	for _, problem := range p {
+		a := client.Annotation{Level: client.AnnotationError, Title: "#" + problem.Pointer, Message: problem.Message}
+		if problem.Level == schemaWarning {
+			a.Level = client.AnnotationWarning
+		}
+		if problem.File != client.StdinFile {
+			a.File = problem.File
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations
+}
+
+func (p schemaProblems) hasErrors() bool {
+	for _, problem := range p {
+		if problem.Level == schemaError {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaChecker collects the problems of an identity schema file.
+type schemaChecker struct {
+	file     string
+	problems schemaProblems
+}
+
+func (c *schemaChecker) add(level, pointer, format string, args ...interface{}) {
+	c.problems = append(c.problems, schemaProblem{File: c.file, Pointer: pointer, Level: level, Message: fmt.Sprintf(format, args...)})
+}
+
+// checkIdentitySchema checks that the file is a valid JSON Schema and that the Ory extensions are well-formed. If
+// requireIdentifier is set, at least one trait must be an identifier for signing in. The problems are sorted by
+// pointer.
+func checkIdentitySchema(ctx context.Context, file string, contents []byte, requireIdentifier bool) schemaProblems {
+	c := &schemaChecker{file: file}
+
+	schema, err := decodeSchema(contents)
+	if err != nil {
+		c.add(schemaError, "", "the file is not valid JSON: %s", err)
+		return c.problems
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(file, bytes.NewReader(contents)); err != nil {
+		c.add(schemaError, "", "the file is not a valid JSON Schema: %s", err)
+		return c.problems
+	}
+	if _, err := compiler.Compile(ctx, file); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if errors.As(err, &validationErr) {
+			c.addValidationErrors(validationErr)
+		} else {
+			c.add(schemaError, "", "the file is not a valid JSON Schema: %s", err)
+		}
+	}
+
+	_, ok := lookupSchema(schema, "properties", "traits")
+	if !ok {
+		c.add(schemaError, "/properties", `the schema must define the "traits" property`)
+	}
+
+	identifiers := c.checkExtensions(schema, "")
+	if requireIdentifier && ok && identifiers == 0 {
+		c.add(schemaError, "/properties/traits", "no trait is an identifier, set %q to true on e.g. the email trait", kratosExtension+".credentials.password.identifier")
+	}
+
+	sort.SliceStable(c.problems, func(i, j int) bool { return c.problems[i].Pointer < c.problems[j].Pointer })
+	return c.problems
+}
+
+// addValidationErrors adds the leaves of the meta-schema validation error, which point to the invalid keywords.
+func (c *schemaChecker) addValidationErrors(e *jsonschema.ValidationError) {
+	if len(e.Causes) == 0 {
+		c.add(schemaError, strings.TrimPrefix(e.InstancePtr, "#"), "%s", e.Message)
+		return
+	}
+	for _, cause := range e.Causes {
+		c.addValidationErrors(cause)
+	}
+}
+
+// checkExtensions checks the Ory extensions of the schema and its sub schemas. It returns the number of traits which
+// are identifiers.
+func (c *schemaChecker) checkExtensions(schema map[string]interface{}, pointer string) int {
+	identifiers := 0
+	if ext, ok := schema[kratosExtension]; ok {
+		identifiers += c.checkExtension(schema, ext, pointer+"/"+escapePointer(kratosExtension))
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(properties) {
+			if sub, ok := properties[name].(map[string]interface{}); ok {
+				identifiers += c.checkExtensions(sub, pointer+"/properties/"+escapePointer(name))
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		identifiers += c.checkExtensions(items, pointer+"/items")
+	}
+	return identifiers
+}
+
+// checkExtension checks the "ory.sh/kratos" keyword of a trait and returns 1 if the trait is an identifier.
+func (c *schemaChecker) checkExtension(schema map[string]interface{}, ext interface{}, pointer string) int {
+	e, ok := ext.(map[string]interface{})
+	if !ok {
+		c.add(schemaError, pointer, "must be an object")
+		return 0
+	}
+
+	identifier := false
+	for _, key := range sortedKeys(e) {
+		p := pointer + "/" + escapePointer(key)
+		switch key {
+		case "credentials":
+			credentials, ok := e[key].(map[string]interface{})
+			if !ok {
+				c.add(schemaError, p, "must be an object")
+				continue
+			}
+			identifier = c.checkCredentials(credentials, p)
+		case "verification", "recovery":
+			c.checkVia(e[key], p, schema)
+		default:
+			c.add(schemaWarning, p, "unknown key %q is ignored", key)
+		}
+	}
+
+	if !identifier {
+		return 0
+	}
+	if typ, _ := schema["type"].(string); typ != "string" {
+		c.add(schemaError, pointer, `identifiers must be of type "string"`)
+	}
+	return 1
+}
+
+// checkCredentials checks the credentials of a trait and returns true if the trait is an identifier.
+func (c *schemaChecker) checkCredentials(credentials map[string]interface{}, pointer string) bool {
+	identifier := false
+	for _, method := range sortedKeys(credentials) {
+		p := pointer + "/" + escapePointer(method)
+		config, ok := credentials[method].(map[string]interface{})
+		if !ok {
+			c.add(schemaError, p, "must be an object")
+			continue
+		}
+
+		var flags []string
+		switch method {
+		case "password", "webauthn", "passkey", "code":
+			flags = []string{"identifier"}
+		case "totp":
+			flags = []string{"account_name"}
+		default:
+			c.add(schemaWarning, p, "unknown credentials method %q is ignored", method)
+			continue
+		}
+		for _, key := range sortedKeys(config) {
+			switch v := config[key]; {
+			case containsString(flags, key):
+				b, ok := v.(bool)
+				if !ok {
+					c.add(schemaError, p+"/"+escapePointer(key), "must be a boolean")
+				}
+				identifier = identifier || (b && key == "identifier")
+			case method == "code" && key == "via":
+				c.checkViaValue(v, p+"/via")
+			default:
+				c.add(schemaWarning, p+"/"+escapePointer(key), "unknown key %q is ignored", key)
+			}
+		}
+	}
+	return identifier
+}
+
+// checkVia checks the verification and recovery settings of a trait, which must name the channel of the address.
+func (c *schemaChecker) checkVia(v interface{}, pointer string, schema map[string]interface{}) {
+	config, ok := v.(map[string]interface{})
+	if !ok {
+		c.add(schemaError, pointer, "must be an object")
+		return
+	}
+	via, ok := config["via"]
+	if !ok {
+		c.add(schemaError, pointer, `"via" must be set to "email" or "sms"`)
+		return
+	}
+	if c.checkViaValue(via, pointer+"/via") && via == "email" {
+		if format, _ := schema["format"].(string); format != "email" {
+			c.add(schemaWarning, pointer+"/via", `the trait should have "format": "email", so that invalid addresses are rejected`)
+		}
+	}
+}
+
+func (c *schemaChecker) checkViaValue(via interface{}, pointer string) bool {
+	if via != "email" && via != "sms" {
+		c.add(schemaError, pointer, `must be "email" or "sms"`)
+		return false
+	}
+	return true
+}
+
+// compareIdentitySchemas warns about changes from the deployed schema which existing identities may not satisfy:
+// removed and newly required traits, and traits whose type changed.
+func compareIdentitySchemas(file string, deployed, updated map[string]interface{}) schemaProblems {
+	c := &schemaChecker{file: file}
+	c.compare(deployed, updated, "")
+	return c.problems
+}
+
+func (c *schemaChecker) compare(deployed, updated map[string]interface{}, pointer string) {
+	if !reflect.DeepEqual(deployed["type"], updated["type"]) && deployed["type"] != nil {
+		c.add(schemaWarning, pointer+"/type", "the type changed from %s to %s, existing identities may no longer be valid", encode(deployed["type"]), encode(updated["type"]))
+	}
+
+	wasRequired := map[string]bool{}
+	for _, name := range stringList(deployed["required"]) {
+		wasRequired[name] = true
+	}
+	for _, name := range stringList(updated["required"]) {
+		if !wasRequired[name] {
+			c.add(schemaWarning, pointer+"/required", "%q is now required, existing identities without it are no longer valid", name)
+		}
+	}
+
+	before, _ := deployed["properties"].(map[string]interface{})
+	after, _ := updated["properties"].(map[string]interface{})
+	for _, name := range sortedKeys(before) {
+		p := pointer + "/properties/" + escapePointer(name)
+		if _, ok := after[name]; !ok {
+			if wasRequired[name] {
+				c.add(schemaWarning, p, "the required property %q was removed, existing identities may still have it", name)
+			} else {
+				c.add(schemaWarning, p, "the property %q was removed, existing identities may still have it", name)
+			}
+			continue
+		}
+		b, _ := before[name].(map[string]interface{})
+		a, _ := after[name].(map[string]interface{})
+		if b != nil && a != nil {
+			c.compare(b, a, p)
+		}
+	}
+	if b, ok := deployed["items"].(map[string]interface{}); ok {
+		if a, ok := updated["items"].(map[string]interface{}); ok {
+			c.compare(b, a, pointer+"/items")
+		}
+	}
+}
+
+func decodeSchema(contents []byte) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal(contents, &schema); err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, errors.New("the schema must be an object")
+	}
+	return schema, nil
+}
+
+func lookupSchema(schema map[string]interface{}, path ...string) (map[string]interface{}, bool) {
+	for _, key := range path {
+		next, ok := schema[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		schema = next
+	}
+	return schema, true
+}
+
+// escapePointer escapes a reference token of a JSON pointer.
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringList(v interface{}) []string {
+	var list []string
+	values, _ := v.([]interface{})
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func encode(v interface{}) string {
+	out, _ := json.Marshal(v)
+	return string(out)
+}
@@ -0,0 +1,161 @@
+package identity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validIdentitySchema = `{
+  "$id": "https://example.com/customer.schema.json",
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "traits": {
+      "type": "object",
+      "properties": {
+        "email": {
+          "type": "string",
+          "format": "email",
+          "ory.sh/kratos": {
+            "credentials": {"password": {"identifier": true}},
+            "verification": {"via": "email"},
+            "recovery": {"via": "email"}
+          }
+        },
+        "name": {"type": "string"}
+      },
+      "required": ["email"]
+    }
+  }
+}`
+
+func TestCheckIdentitySchema(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("case=valid schema", func(t *testing.T) {
+		assert.Empty(t, checkIdentitySchema(ctx, "customer.schema.json", []byte(validIdentitySchema), true))
+	})
+
+	t.Run("case=invalid JSON", func(t *testing.T) {
+		problems := checkIdentitySchema(ctx, "customer.schema.json", []byte(`{`), false)
+		require.Len(t, problems, 1)
+		assert.True(t, problems.hasErrors())
+		assert.Contains(t, problems[0].Message, "not valid JSON")
+	})
+
+	t.Run("case=invalid meta schema", func(t *testing.T) {
+		problems := checkIdentitySchema(ctx, "customer.schema.json", []byte(`{"type": "object", "properties": {"traits": {"type": 1}}}`), false)
+		require.NotEmpty(t, problems)
+		assert.True(t, problems.hasErrors())
+	})
+
+	t.Run("case=missing traits", func(t *testing.T) {
+		problems := checkIdentitySchema(ctx, "customer.schema.json", []byte(`{"type": "object", "properties": {}}`), true)
+		assert.Equal(t, schemaProblems{{
+			File: "customer.schema.json", Pointer: "/properties", Level: schemaError, Message: `the schema must define the "traits" property`,
+		}}, problems)
+	})
+
+	t.Run("case=malformed extensions", func(t *testing.T) {
+		problems := checkIdentitySchema(ctx, "customer.schema.json", []byte(`{
+  "type": "object",
+  "properties": {
+    "traits": {
+      "type": "object",
+      "properties": {
+        "email": {
+          "type": "string",
+          "ory.sh/kratos": {
+            "credentials": {"password": {"identifier": "yes"}, "totp": {"account_name": true}},
+            "verification": {"via": "pigeon"},
+            "unknown": true
+          }
+        },
+        "phone": {
+          "type": "number",
+          "ory.sh/kratos": {"credentials": {"code": {"identifier": true, "via": "sms"}}, "recovery": {}}
+        }
+      }
+    }
+  }
+}`), false)
+
+		const email, phone = "/properties/traits/properties/email/ory.sh~1kratos", "/properties/traits/properties/phone/ory.sh~1kratos"
+		var actual []string
+		for _, p := range problems {
+			actual = append(actual, p.Level+" "+p.Pointer)
+		}
+		assert.Equal(t, []string{
+			"error " + email + "/credentials/password/identifier",
+			"warning " + email + "/unknown",
+			"error " + email + "/verification/via",
+			"error " + phone,
+			"error " + phone + "/recovery",
+		}, actual)
+		assert.True(t, problems.hasErrors())
+	})
+
+	t.Run("case=email verification without format", func(t *testing.T) {
+		problems := checkIdentitySchema(ctx, "customer.schema.json", []byte(`{
+  "type": "object",
+  "properties": {"traits": {"type": "object", "properties": {"email": {"type": "string", "ory.sh/kratos": {"verification": {"via": "email"}}}}}}
+}`), false)
+		require.Len(t, problems, 1)
+		assert.Equal(t, schemaWarning, problems[0].Level)
+		assert.False(t, problems.hasErrors())
+	})
+
+	t.Run("case=no identifier", func(t *testing.T) {
+		contents := []byte(`{"type": "object", "properties": {"traits": {"type": "object", "properties": {"name": {"type": "string"}}}}}`)
+		assert.Empty(t, checkIdentitySchema(ctx, "customer.schema.json", contents, false))
+
+		problems := checkIdentitySchema(ctx, "customer.schema.json", contents, true)
+		require.Len(t, problems, 1)
+		assert.Equal(t, "/properties/traits", problems[0].Pointer)
+		assert.True(t, problems.hasErrors())
+	})
+}
+
+func TestCompareIdentitySchemas(t *testing.T) {
+	deployed, err := decodeSchema([]byte(validIdentitySchema))
+	require.NoError(t, err)
+
+	t.Run("case=unchanged", func(t *testing.T) {
+		assert.Empty(t, compareIdentitySchemas("customer.schema.json", deployed, deployed))
+	})
+
+	t.Run("case=incompatible changes", func(t *testing.T) {
+		updated, err := decodeSchema([]byte(`{
+  "type": "object",
+  "properties": {
+    "traits": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "object"},
+        "phone": {"type": "string"}
+      },
+      "required": ["phone"]
+    }
+  }
+}`))
+		require.NoError(t, err)
+
+		problems := compareIdentitySchemas("customer.schema.json", deployed, updated)
+		assert.False(t, problems.hasErrors())
+		assert.Equal(t, schemaProblems{
+			{File: "customer.schema.json", Pointer: "/properties/traits/required", Level: schemaWarning, Message: `"phone" is now required, existing identities without it are no longer valid`},
+			{File: "customer.schema.json", Pointer: "/properties/traits/properties/email", Level: schemaWarning, Message: `the required property "email" was removed, existing identities may still have it`},
+			{File: "customer.schema.json", Pointer: "/properties/traits/properties/name/type", Level: schemaWarning, Message: `the type changed from "string" to "object", existing identities may no longer be valid`},
+		}, problems)
+	})
+}
+
+func TestSchemaProblemsString(t *testing.T) {
+	problems := schemaProblems{{File: "customer.schema.json", Pointer: "/properties", Level: schemaError, Message: "oops"}}
+	assert.Equal(t, "customer.schema.json#/properties: error: oops\n", problems.String())
+	require.Len(t, problems.Annotations(), 1)
+	assert.Equal(t, "customer.schema.json", problems.Annotations()[0].File)
+}
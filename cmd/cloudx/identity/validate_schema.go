@@ -0,0 +1,144 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	schemaFileFlag        = "file"
+	againstProjectFlag    = "against-project"
+	schemaIDFlag          = "schema-id"
+	requireIdentifierFlag = "require-identifier"
+)
+
+func NewValidateIdentitySchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "identity-schema",
+		Args:  client.NoArgs,
+		Short: "Validate an identity schema file",
+		Long: `Check an identity schema file locally, e.g. before merging changes to it:
+
+- The file must be a valid JSON Schema (draft 7 unless "$schema" says otherwise) which defines the "traits" property.
+- The Ory extensions ("ory.sh/kratos") must be well-formed, e.g. identifiers must be booleans on string traits, and
+  verification and recovery must be sent via "email" or "sms".
+- With --require-identifier, at least one trait must be an identifier for signing in.
+
+Problems are reported with the JSON pointer into the file. Errors fail the command with exit code 3, warnings do not.
+With --exit-code, the command exits with 1 on errors and warnings, and with at least 2 if the schema could not be
+checked, e.g. because the deployed schema could not be fetched.
+
+With --against-project, the schema deployed to the selected project (the default schema, or the one set by
+--schema-id) is fetched, and changes which existing identities may not satisfy are reported as warnings: removed and
+newly required traits, and traits whose type changed.`,
+		Example: `$ ory validate identity-schema --file customer.schema.json --require-identifier
+customer.schema.json#/properties/traits/properties/email/ory.sh~1kratos/credentials/password/identifier: error: must be a boolean
+
+$ ory validate identity-schema --file customer.schema.json --against-project --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file := flagx.MustGetString(cmd, schemaFileFlag)
+			if file == "" {
+				return client.NewValidationError(errors.Errorf("--%s must be set", schemaFileFlag), nil)
+			}
+			contents, err := client.ReadInputFile(cmd, file)
+			if err != nil {
+				return err
+			}
+			file = client.InputFileName(file)
+
+			problems := checkIdentitySchema(cmd.Context(), file, contents, flagx.MustGetBool(cmd, requireIdentifierFlag))
+			if flagx.MustGetBool(cmd, againstProjectFlag) && !problems.hasErrors() {
+				h, err := client.NewCommandHelper(cmd)
+				if err != nil {
+					return err
+				}
+				deployed, err := deployedIdentitySchema(cmd, h)
+				if err != nil {
+					return err
+				}
+				updated, _ := decodeSchema(contents)
+				problems = append(problems, compareIdentitySchemas(file, deployed, updated)...)
+			}
+
+			printSchemaProblems(cmd, problems)
+			if problems.hasErrors() {
+				return client.NewFindingsError(cmd, client.ExitValidation)
+			} else if len(problems) > 0 {
+				return client.NewFindingsError(cmd, client.ExitSuccess)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(schemaFileFlag, schemaFileFlag[:1], "", "The identity schema file to validate. Use - to read from stdin.")
+	cmd.Flags().Bool(requireIdentifierFlag, false, "Fail if no trait is an identifier, e.g. the email address.")
+	cmd.Flags().Bool(againstProjectFlag, false, "Warn about backward incompatible changes to the schema deployed to the project.")
+	cmd.Flags().String(schemaIDFlag, "", "The ID of the deployed schema to compare with. Defaults to the default schema of the project.")
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterExitCodeFlag(cmd.Flags())
+	return cmd
+}
+
+// deployedIdentitySchema fetches the schema set by --schema-id, or the default schema of the project.
+func deployedIdentitySchema(cmd *cobra.Command, h *client.CommandHelper) (map[string]interface{}, error) {
+	api, err := h.NewServiceAPI(client.ServiceIdentity)
+	if err != nil {
+		return nil, err
+	}
+
+	id := flagx.MustGetString(cmd, schemaIDFlag)
+	if id == "" {
+		id = projectSchemaID(api)
+	}
+	if id == "" {
+		return nil, client.NewValidationError(errors.Errorf("the default schema of the project is unknown, set --%s", schemaIDFlag), nil)
+	}
+	return fetchIdentitySchema(cmd.Context(), api, id)
+}
+
+// projectSchemaID returns the ID of the default identity schema of the project, or an empty string if it is unknown,
+// e.g. for self-hosted deployments.
+func projectSchemaID(api *client.ProjectAPI) string {
+	if api.Project == nil || api.Project.Services.Identity == nil {
+		return ""
+	}
+	identity, _ := api.Project.Services.Identity.Config["identity"].(map[string]interface{})
+	id, _ := identity["default_schema_id"].(string)
+	return id
+}
+
+// fetchIdentitySchema fetches a deployed identity schema. The API also resolves presets and embedded schemas.
+func fetchIdentitySchema(ctx context.Context, api *client.ProjectAPI, id string) (map[string]interface{}, error) {
+	var raw json.RawMessage
+	if _, err := api.Do(ctx, http.MethodGet, "/schemas/"+url.PathEscape(id), nil, nil, &raw); err != nil {
+		return nil, errors.WithMessagef(err, "unable to fetch the deployed identity schema %q", id)
+	}
+	schema, err := decodeSchema(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decode the deployed identity schema %q", id)
+	}
+	return schema, nil
+}
+
+func printSchemaProblems(cmd *cobra.Command, problems schemaProblems) {
+	switch {
+	case client.IsMachineReadableFormat(cmd):
+		if problems == nil {
+			problems = schemaProblems{}
+		}
+		client.PrintJSONAble(cmd, problems)
+	case client.GitHubAnnotations(cmd):
+		client.PrintAnnotations(cmd, problems.Annotations())
+	default:
+		_, _ = fmt.Fprint(cmd.ErrOrStderr(), problems.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ory/cli/cmd/cloudx/client"
 	"github.com/ory/cli/cmd/cloudx/identity"
+	"github.com/ory/cli/cmd/cloudx/relationtuple"
 	"github.com/ory/x/cmdx"
 )
 
@@ -12,13 +13,17 @@ func NewImportCmd(parent *cobra.Command) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import resources",
+		Example: `$ ory import identities --project my-project identities.json
+
+$ ory import relation-tuples --project my-project --file tuples.ndjson --format json`,
 	}
 
-	cmd.AddCommand(identity.NewImportIdentityCmd(parent))
+	client.AddGroupedCommands(cmd, client.GroupIdentity, identity.NewImportIdentityCmd(parent))
+	client.AddGroupedCommands(cmd, client.GroupPermissions, relationtuple.NewImportRelationTuplesCmd())
 
 	client.RegisterConfigFlag(cmd.PersistentFlags())
 	client.RegisterYesFlag(cmd.PersistentFlags())
 	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
-	cmdx.RegisterJSONFormatFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
 	return cmd
 }
@@ -0,0 +1,162 @@
+package cloudx_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+// identityBackend mocks the identities endpoint. Identities with a failing email address are rejected.
+type identityBackend struct {
+	mu      sync.Mutex
+	created []string
+	failing map[string]bool
+}
+
+type importedIdentity struct {
+	Traits struct {
+		Email string `json:"email"`
+	} `json:"traits"`
+}
+
+// create creates the identity or returns the error of the API.
+func (b *identityBackend) create(identity *importedIdentity) (string, error) {
+	if b.failing[identity.Traits.Email] {
+		return "", fmt.Errorf("the database is unavailable")
+	}
+	b.created = append(b.created, identity.Traits.Email)
+	return fmt.Sprintf("b6c6f3a1-6a1e-4a7e-8c8c-%012d", len(b.created)), nil
+}
+
+func newIdentityBackend(t *testing.T, batches bool, failing ...string) *identityBackend {
+	b := &identityBackend{failing: map[string]bool{}}
+	for _, email := range failing {
+		b.failing[email] = true
+	}
+
+	fake := newMockBackend()
+	fake.HandleFunc("/admin/identities", func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if r.Method == http.MethodPatch {
+			if !batches {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var body struct {
+				Identities []struct {
+					Create *importedIdentity `json:"create"`
+				} `json:"identities"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			var results []string
+			for _, i := range body.Identities {
+				if id, err := b.create(i.Create); err != nil {
+					results = append(results, fmt.Sprintf(`{"action":"error","error":{"code":500,"message":%q}}`, err))
+				} else {
+					results = append(results, fmt.Sprintf(`{"action":"create","identity":%q}`, id))
+				}
+			}
+			_, _ = fmt.Fprintf(w, `{"identities":[%s]}`, strings.Join(results, ","))
+			return
+		}
+
+		var identity importedIdentity
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&identity))
+		id, err := b.create(&identity)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, `{"error":{"code":500,"message":%q}}`, err)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"id":%q,"schema_id":"default","state":"active","traits":{"email":%q}}`, id, identity.Traits.Email)
+	})
+	cloudxtest.Serve(t, fake)
+	return b
+}
+
+func writeIdentities(t *testing.T, file string, n int) []string {
+	var lines []string
+	for i := 1; i <= n; i++ {
+		lines = append(lines, fmt.Sprintf(`{"schema_id":"default","traits":{"email":"user-%d@example.com"}}`, i))
+	}
+	require.NoError(t, os.WriteFile(file, []byte(strings.Join(lines, "\n")), 0600))
+	return lines
+}
+
+func TestImportIdentitiesInBatches(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		batches  bool
+		requests string
+	}{
+		{name: "batch endpoint", batches: true, requests: "requests: 3"},
+		{name: "fallback to single requests", batches: false, requests: "requests: 6"},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			backend := newIdentityBackend(t, tc.batches, "user-3@example.com")
+			cmd := newMockedCmd(t)
+			input := filepath.Join(t.TempDir(), "identities.ndjson")
+			writeIdentities(t, input, 5)
+
+			stdout, stderr, err := cmd.Exec(nil, "import", "identities", "--project", mockedProjectID, "--batch-size", "2", "--workers", "1", "--format", "json", input)
+			require.Error(t, err)
+			assert.Equal(t, client.ExitFailure, client.ExitCode(err))
+			assert.Contains(t, stderr, "Imported: 4, skipped: 0, failed: 1, "+tc.requests)
+			assert.Contains(t, stderr, "Unable to import identity 3:", "the error is attributed to the identity")
+			assert.Contains(t, stdout, "user-5@example.com")
+			assert.Len(t, backend.created, 4)
+		})
+	}
+}
+
+func TestImportIdentitiesResumes(t *testing.T) {
+	backend := newIdentityBackend(t, true, "user-3@example.com")
+	cmd := newMockedCmd(t)
+
+	dir := t.TempDir()
+	input, state := filepath.Join(dir, "identities.ndjson"), filepath.Join(dir, "import.checkpoint")
+	lines := writeIdentities(t, input, 5)
+
+	_, stderr, err := cmd.Exec(nil, "import", "identities", "--project", mockedProjectID, "--state-file", state, input)
+	require.Error(t, err)
+	assert.Equal(t, client.ExitFailure, client.ExitCode(err))
+	assert.Contains(t, stderr, "Imported: 4, skipped: 0, failed: 1")
+	assert.Len(t, backend.created, 4)
+	assert.FileExists(t, state)
+
+	t.Run("case=refuses a modified input", func(t *testing.T) {
+		modified := filepath.Join(dir, "modified.ndjson")
+		require.NoError(t, os.WriteFile(modified, []byte(strings.Join(lines[1:], "\n")), 0600))
+		_, _, err := cmd.Exec(nil, "import", "identities", "--project", mockedProjectID, "--state-file", state, modified)
+		require.Error(t, err)
+		assert.Equal(t, client.ExitValidation, client.ExitCode(err))
+		assert.Len(t, backend.created, 4, "nothing was imported")
+	})
+
+	t.Run("case=imports only the failed identities", func(t *testing.T) {
+		backend.mu.Lock()
+		backend.failing = map[string]bool{}
+		backend.mu.Unlock()
+
+		stdout, stderr, err := cmd.Exec(nil, "import", "identities", "--project", mockedProjectID, "--state-file", state, "--format", "json", input)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "Imported: 1, skipped: 4, failed: 0")
+		assert.Equal(t, []string{"user-3@example.com"}, backend.created[4:], "only the failed identity was imported again")
+		assert.Contains(t, stdout, "user-3@example.com")
+		assert.NoFileExists(t, state, "the state file is removed once the import completed")
+	})
+}
@@ -0,0 +1,174 @@
+package cloudx_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+// tupleBackend mocks the relation tuples endpoint. Batches containing a relation tuple of a failing object are
+// rejected, and the first failFirst requests fail regardless of their content.
+type tupleBackend struct {
+	mu        sync.Mutex
+	requests  int
+	failFirst int
+	failing   map[string]bool
+	batches   []int
+	inserted  []string
+	deletions []string
+}
+
+type importedTuple struct {
+	Namespace string `json:"namespace"`
+	Object    string `json:"object"`
+	Relation  string `json:"relation"`
+	SubjectID string `json:"subject_id"`
+}
+
+func newTupleBackend(t *testing.T, failFirst int, failing ...string) *tupleBackend {
+	b := &tupleBackend{failFirst: failFirst, failing: map[string]bool{}}
+	for _, object := range failing {
+		b.failing[object] = true
+	}
+
+	fake := newMockBackend()
+	fake.HandleFunc("/admin/relation-tuples", func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if b.requests++; b.requests <= b.failFirst {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, `{"error":{"code":500,"message":"the database is unavailable"}}`)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			b.deletions = append(b.deletions, r.URL.Query().Get("namespace")+":"+r.URL.Query().Get("object"))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var deltas []struct {
+			Action        string        `json:"action"`
+			RelationTuple importedTuple `json:"relation_tuple"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&deltas))
+		for _, d := range deltas {
+			assert.Equal(t, "insert", d.Action)
+			if b.failing[d.RelationTuple.Object] {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = fmt.Fprint(w, `{"error":{"code":500,"message":"the database is unavailable"}}`)
+				return
+			}
+		}
+		b.batches = append(b.batches, len(deltas))
+		for _, d := range deltas {
+			b.inserted = append(b.inserted, d.RelationTuple.Object+"#"+d.RelationTuple.SubjectID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	cloudxtest.Serve(t, fake)
+	return b
+}
+
+// writeTuples writes n relation tuples to file, spread over the given objects.
+func writeTuples(t *testing.T, file string, n int, objects ...string) []string {
+	var lines []string
+	for i := 0; i < n; i++ {
+		line, err := json.Marshal(&importedTuple{Namespace: "documents", Object: objects[i%len(objects)], Relation: "viewer", SubjectID: fmt.Sprintf("user:%d", i)})
+		require.NoError(t, err)
+		lines = append(lines, string(line))
+	}
+	require.NoError(t, os.WriteFile(file, []byte(strings.Join(lines, "\n")), 0600))
+	return lines
+}
+
+func TestImportRelationTuples(t *testing.T) {
+	importTuples := func(t *testing.T, input string, args ...string) (string, string, error) {
+		return newMockedCmd(t).Exec(nil, append([]string{"import", "relation-tuples", "--project", mockedProjectID, "--file", input, "--workers", "1", "--format", "json"}, args...)...)
+	}
+
+	t.Run("case=inserts the relation tuples in batches", func(t *testing.T) {
+		backend := newTupleBackend(t, 0)
+		input := filepath.Join(t.TempDir(), "tuples.ndjson")
+		writeTuples(t, input, 25, "doc-1", "doc-2")
+
+		stdout, stderr, err := importTuples(t, input, "--batch-size", "10")
+		require.NoError(t, err, stderr)
+		assert.JSONEq(t, `{"total":25,"inserted":25,"failed":0,"throttled":0,"interrupted":false}`, stdout)
+		assert.Equal(t, []int{10, 10, 5}, backend.batches)
+		assert.Len(t, backend.inserted, 25)
+		assert.Empty(t, backend.deletions, "nothing is deleted without --replace")
+	})
+
+	t.Run("case=failed batches are retried with a backoff", func(t *testing.T) {
+		backend := newTupleBackend(t, 2)
+		input := filepath.Join(t.TempDir(), "tuples.ndjson")
+		writeTuples(t, input, 5, "doc-1")
+
+		start := time.Now()
+		stdout, stderr, err := importTuples(t, input, "--retries", "2")
+		require.NoError(t, err, stderr)
+		assert.JSONEq(t, `{"total":5,"inserted":5,"failed":0,"throttled":0,"interrupted":false}`, stdout)
+		assert.Equal(t, 3, backend.requests)
+		assert.GreaterOrEqual(t, time.Since(start), 1500*time.Millisecond, "the backoff doubles from 500ms")
+	})
+
+	t.Run("case=batches fail once the retries are exhausted", func(t *testing.T) {
+		backend := newTupleBackend(t, 2)
+		input := filepath.Join(t.TempDir(), "tuples.ndjson")
+		writeTuples(t, input, 5, "doc-1")
+		failedFile := filepath.Join(t.TempDir(), "failed.ndjson")
+
+		stdout, _, err := importTuples(t, input, "--retries", "1", "--failed-file", failedFile)
+		require.Error(t, err)
+		assert.Equal(t, client.ExitFailure, client.ExitCode(err))
+		assert.Contains(t, stdout, `"failed":5`)
+		assert.Equal(t, 2, backend.requests)
+		assert.Empty(t, backend.inserted)
+	})
+
+	t.Run("case=replace deletes each object once before inserting", func(t *testing.T) {
+		backend := newTupleBackend(t, 0)
+		input := filepath.Join(t.TempDir(), "tuples.ndjson")
+		writeTuples(t, input, 12, "doc-1", "doc-2", "doc-3")
+
+		_, _, err := importTuples(t, input, "--replace")
+		require.Error(t, err, "replacing needs to be confirmed")
+		assert.Empty(t, backend.requests)
+
+		stdout, stderr, err := importTuples(t, input, "--replace", "--yes", "--batch-size", "4")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, `"inserted":12`)
+		assert.ElementsMatch(t, []string{"documents:doc-1", "documents:doc-2", "documents:doc-3"}, backend.deletions)
+	})
+
+	t.Run("case=failed relation tuples are written to the failed file", func(t *testing.T) {
+		backend := newTupleBackend(t, 0, "doc-2")
+		input := filepath.Join(t.TempDir(), "tuples.ndjson")
+		lines := writeTuples(t, input, 6, "doc-1", "doc-2")
+		failedFile := filepath.Join(t.TempDir(), "failed.ndjson")
+
+		stdout, _, err := importTuples(t, input, "--batch-size", "1", "--retries", "0", "--failed-file", failedFile)
+		require.Error(t, err)
+		assert.Equal(t, client.ExitFailure, client.ExitCode(err))
+		assert.JSONEq(t, fmt.Sprintf(`{"total":6,"inserted":3,"failed":3,"throttled":0,"failed_file":%q,"interrupted":false}`, failedFile), stdout)
+		assert.Len(t, backend.inserted, 3)
+
+		failed, err := os.ReadFile(failedFile)
+		require.NoError(t, err)
+		assert.Equal(t, lines[1]+"\n"+lines[3]+"\n"+lines[5]+"\n", string(failed), "the failed file can be imported again")
+	})
+}
@@ -0,0 +1,27 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/session"
+	"github.com/ory/x/cmdx"
+)
+
+func NewIntrospectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "introspect",
+		Short: "Introspect resources",
+		Example: `$ echo "$USER_SESSION_TOKEN" | ory introspect session --project my-project --token-stdin
+
+$ ory introspect session --project my-project --cookie "ory_session_myproject=MTY1..." --format json`,
+	}
+
+	client.AddGroupedCommands(cmd, client.GroupIdentity, session.NewIntrospectSessionCmd())
+
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
+}
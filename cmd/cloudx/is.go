@@ -0,0 +1,27 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/relationtuple"
+	"github.com/ory/x/cmdx"
+)
+
+func NewIsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "is",
+		Short: "Assert the state of Ory Cloud resources",
+		Example: `$ ory is allowed user:alice viewer documents doc-1 --project my-project
+
+$ ory is allowed user:alice viewer documents doc-1 --project my-project --format json`,
+	}
+
+	client.AddGroupedCommands(cmd, client.GroupPermissions, relationtuple.NewIsAllowedCmd())
+
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
+}
@@ -3,7 +3,10 @@ package cloudx
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ory/cli/cmd/cloudx/action"
 	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/consent"
+	"github.com/ory/cli/cmd/cloudx/courier"
 	"github.com/ory/cli/cmd/cloudx/identity"
 	"github.com/ory/cli/cmd/cloudx/project"
 	"github.com/ory/x/cmdx"
@@ -12,16 +15,25 @@ import (
 func NewListCmd(parent *cobra.Command) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "list",
-		Aliases: []string{"ls"},
+		Aliases: verbAliases["list"],
 		Short:   "List resources",
+		Example: `$ ory list projects
+
+$ ory list identities --project my-project --format json`,
 	}
 
-	cmd.AddCommand(project.NewListProjectsCmd())
-	cmd.AddCommand(identity.NewListIdentityCmd(parent))
+	client.AddGroupedCommands(cmd, client.GroupProject, project.NewListProjectsCmd())
+	client.AddGroupedCommands(cmd, client.GroupIdentity,
+		identity.NewListIdentityCmd(parent),
+		courier.NewListCourierMessagesCmd(),
+		action.NewListActionsCmd(),
+	)
+	client.AddGroupedCommands(cmd, client.GroupOAuth2, consent.NewListConsentSessionsCmd())
 
 	client.RegisterConfigFlag(cmd.PersistentFlags())
 	client.RegisterYesFlag(cmd.PersistentFlags())
 	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
-	cmdx.RegisterJSONFormatFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	client.RegisterColumnsFlags(cmd.PersistentFlags())
 	return cmd
 }
@@ -0,0 +1,68 @@
+package cloudx_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+func TestListAllStopsOnCancel(t *testing.T) {
+	const pageSize = 2
+
+	slow := make(chan struct{})
+	fake := newMockBackend()
+	fake.HandleFunc("/admin/courier/messages", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+		if page == 2 {
+			close(slow)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Minute):
+			}
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`</admin/courier/messages?page_size=%d&page_token=%d>; rel="next"`, pageSize, page+1))
+		_, _ = fmt.Fprintf(w, `[
+  {"id": "b6c6f3a1-6a1e-4a7e-8c8c-%012d", "type": "email", "template_type": "verification_valid", "recipient": "jane@example.com", "status": "sent", "created_at": "2022-06-01T12:00:00Z", "updated_at": "2022-06-01T12:00:00Z"},
+  {"id": "b6c6f3a1-6a1e-4a7e-8c8c-%012d", "type": "email", "template_type": "recovery_valid", "recipient": "john@example.com", "status": "sent", "created_at": "2022-06-01T12:00:00Z", "updated_at": "2022-06-01T12:00:00Z"}
+]`, 2*page, 2*page+1)
+	})
+	cloudxtest.Serve(t, fake)
+	cmd := newMockedCmd(t)
+
+	var cancel context.CancelFunc
+	cmd.Ctx, cancel = context.WithCancel(cmd.Ctx)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	done := make(chan error)
+	go func() {
+		done <- cmd.ExecBackground(nil, &stdout, &stderr, "list", "courier-messages", "--project", mockedProjectID,
+			"--all", "--page-size", strconv.Itoa(pageSize), "--format", "ndjson").Wait()
+	}()
+
+	<-slow
+	cancel()
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.Equal(t, client.ExitInterrupted, client.ExitCode(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("the command did not return after it was canceled")
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Len(t, lines, 2*pageSize, "the pages fetched before the cancellation are printed")
+	assert.Contains(t, stderr.String(), "Stopped after 2 pages (4 messages), use --page-token 2 to continue.")
+}
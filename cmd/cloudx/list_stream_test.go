@@ -0,0 +1,58 @@
+package cloudx_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest/streamtest"
+)
+
+// lineCounter counts the lines written to it and reports the first one to first.
+type lineCounter struct {
+	first *streamtest.FirstOutput
+	lines int
+}
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	c.lines += bytes.Count(p, []byte("\n"))
+	return c.first.Write(p)
+}
+
+func TestListAllStreamsOutput(t *testing.T) {
+	const pages, pageSize = 5, 100
+
+	out := &lineCounter{first: streamtest.NewFirstOutput()}
+	fake := newMockBackend()
+	fake.HandleFunc("/admin/courier/messages", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+		if page+1 < pages {
+			w.Header().Set("Link", fmt.Sprintf(`</admin/courier/messages?page_size=%d&page_token=%d>; rel="next"`, pageSize, page+1))
+		} else {
+			// Buffering all messages would print nothing before the last page was served.
+			assert.True(t, out.first.Wait(streamtest.Timeout), "the first message was not printed before the last page was requested")
+		}
+		_, _ = io.WriteString(w, "[")
+		for i := 0; i < pageSize; i++ {
+			if i > 0 {
+				_, _ = io.WriteString(w, ",")
+			}
+			_, _ = fmt.Fprintf(w, `{"id": "b6c6f3a1-6a1e-4a7e-8c8c-%012d", "type": "email", "template_type": "verification_valid", "recipient": "user-%d@example.com", "status": "sent", "created_at": "2022-06-01T12:00:00Z", "updated_at": "2022-06-01T12:00:00Z"}`, page*pageSize+i, page*pageSize+i)
+		}
+		_, _ = io.WriteString(w, "]")
+	})
+	cloudxtest.Serve(t, fake)
+	cmd := newMockedCmd(t)
+
+	err := cmd.ExecBackground(nil, out, io.Discard, "list", "courier-messages", "--project", mockedProjectID,
+		"--all", "--page-size", strconv.Itoa(pageSize), "--format", "ndjson").Wait()
+	require.NoError(t, err)
+	assert.Equal(t, pages*pageSize, out.lines, "every message is printed on its own line")
+}
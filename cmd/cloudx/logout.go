@@ -1,8 +1,6 @@
 package cloudx
 
 import (
-	"fmt"
-
 	"github.com/ory/cli/cmd/cloudx/client"
 
 	"github.com/spf13/cobra"
@@ -11,7 +9,10 @@ import (
 func NewLogoutCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "logout",
+		Args:  client.NoArgs,
 		Short: "Signs you out of your account on this computer.",
+		Example: `$ ory auth logout
+You signed out successfully.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			h, err := client.NewCommandHelper(cmd)
 			if err != nil {
@@ -20,7 +21,7 @@ func NewLogoutCmd() *cobra.Command {
 			if err := h.SignOut(); err != nil {
 				return err
 			}
-			fmt.Println("You signed out successfully.")
+			h.Log.Infof("You signed out successfully.")
 			return nil
 		},
 	}
@@ -1,22 +1,39 @@
 package cloudx_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
 	"github.com/ory/cli/cmd/cloudx/testhelpers"
 )
 
 func TestAuthLogout(t *testing.T) {
-	configDir := testhelpers.NewConfigDir(t)
-	testhelpers.RegisterAccount(t, configDir)
+	const email, password = "dev@ory.sh", "s3cr3t-password"
+	fake := cloudxtest.NewBackend()
+	fake.AddAccount(email, password)
 
-	exec := testhelpers.ConfigAwareCmd(configDir)
-	_, _, err := exec.Exec(nil, "auth", "logout")
-	require.NoError(t, err)
+	var token string
+	cloudxtest.RecordReplay(t, fake, func(t *testing.T) {
+		configDir := testhelpers.NewConfigDir(t)
+		var r bytes.Buffer
+		_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
+		_, _ = r.WriteString(email + "\n") // Email:
+		_, _ = r.WriteString("\n")         // Email: dev@ory.sh — correct? [Y/n]:
+		_, stderr, err := testhelpers.ConfigPasswordAwareCmd(configDir, password).Exec(&r, "auth")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "You are now signed in as: "+email)
+		if token == "" {
+			token = testhelpers.ReadConfig(t, configDir).SessionToken
+		}
 
-	ac := testhelpers.ReadConfig(t, configDir)
-	assert.Empty(t, ac.SessionToken)
+		_, _, err = testhelpers.ConfigAwareCmd(configDir).Exec(nil, "auth", "logout")
+		require.NoError(t, err)
+
+		ac := testhelpers.ReadConfig(t, configDir)
+		assert.Empty(t, ac.SessionToken)
+	}, func() []string { return []string{password, token} })
 }
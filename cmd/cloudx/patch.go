@@ -5,19 +5,25 @@ import (
 
 	"github.com/ory/cli/cmd/cloudx/client"
 	"github.com/ory/cli/cmd/cloudx/project"
+	"github.com/ory/x/cmdx"
 )
 
 func NewPatchCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "patch",
 		Short: "Patch resources",
+		Example: `$ ory patch project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --replace '/name="Example Project"'
+
+$ ory patch identity-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
+	--replace '/selfservice/methods/password/enabled=false' --format yaml`,
 	}
 	client.RegisterConfigFlag(cmd.PersistentFlags())
-	cmd.AddCommand(
-		project.NewProjectsPatchCmd(),
-		project.NewPatchKratosConfigCmd(),
-		project.NewPatchKetoConfigCmd(),
-		project.NewPatchOAuth2ConfigCmd(),
-	)
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	client.AddGroupedCommands(cmd, client.GroupProject, project.NewProjectsPatchCmd())
+	client.AddGroupedCommands(cmd, client.GroupIdentity, project.NewPatchKratosConfigCmd())
+	client.AddGroupedCommands(cmd, client.GroupOAuth2, project.NewPatchOAuth2ConfigCmd())
+	client.AddGroupedCommands(cmd, client.GroupPermissions, project.NewPatchKetoConfigCmd())
 	return cmd
 }
@@ -0,0 +1,101 @@
+package cloudx
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+)
+
+type outputPingChecks []*client.PingCheck
+
+func (outputPingChecks) Header() []string {
+	return []string{"CHECK", "STATUS", "TIME", "DETAILS"}
+}
+
+func (c outputPingChecks) Table() [][]string {
+	rows := make([][]string, len(c))
+	for k, check := range c {
+		latency := "-"
+		if check.Status != client.PingSkipped {
+			latency = check.Latency.Round(time.Millisecond).String()
+		}
+		rows[k] = []string{check.Name, check.Status, latency, check.Details}
+	}
+	return rows
+}
+
+func (c outputPingChecks) Interface() interface{} {
+	return []*client.PingCheck(c)
+}
+
+func (c outputPingChecks) Len() int {
+	return len(c)
+}
+
+func NewPingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ping",
+		Args:  client.NoArgs,
+		Short: "Check the connection to Ory Cloud",
+		Long: `Check the connection to Ory Cloud before running large operations, e.g. a migration.
+
+The checks run concurrently and only send read-only requests:
+
+- console: the Ory Console API is reachable.
+- authentication: your session is valid. Skipped if you are not signed in.
+- project API: the API of the project set by --project, or of the selected project, is reachable. Looking up the
+  project requires a session, unless --project is set to the project's slug.
+- proxy: the proxy that API requests are sent through.
+
+Every check times out after 5 seconds unless --timeout is set. The command fails if any check failed, skipped checks
+do not fail it.`,
+		Example: `$ ory ping
+CHECK		STATUS	TIME	DETAILS
+console		pass	84ms	api.console.ory.sh responded with 200 OK
+authentication	pass	112ms	signed in as dev@ory.sh
+project API	pass	205ms	good-wright-t7kzy3vugf.projects.oryapis.com responded with 200 OK
+proxy		pass	0s	none, requests are sent directly
+
+$ ory ping --project good-wright-t7kzy3vugf --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			checks := h.Ping(cmd)
+			client.PrintTable(cmd, outputPingChecks(checks))
+
+			// All failures of the same kind, e.g. network errors, keep their exit code.
+			code, skipped := client.ExitSuccess, false
+			for _, c := range checks {
+				switch {
+				case c.Status == client.PingSkipped:
+					skipped = true
+				case c.Status != client.PingFailed:
+				case code == client.ExitSuccess:
+					code = client.ExitCode(c.Err)
+				case code != client.ExitCode(c.Err):
+					code = client.ExitFailure
+				}
+			}
+			if skipped {
+				h.Log.Infof("Some checks were skipped, their details explain how to run them.")
+			}
+			if code != client.ExitSuccess {
+				return client.FailSilently(cmd, code)
+			}
+			return nil
+		},
+	}
+
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
+}
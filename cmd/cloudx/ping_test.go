@@ -0,0 +1,80 @@
+package cloudx_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+func TestPing(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	fake := newMockBackend()
+	cloudxtest.Serve(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.Host+r.URL.Path)
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+
+	checks := func(t *testing.T, stdout string) map[string]map[string]interface{} {
+		var list []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(stdout), &list), stdout)
+		byName := map[string]map[string]interface{}{}
+		for _, c := range list {
+			byName[c["name"].(string)] = c
+		}
+		return byName
+	}
+
+	t.Run("case=signed in", func(t *testing.T) {
+		requests = nil
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "ping", "--project", mockedProjectID, "--format", "json")
+		require.NoError(t, err, stderr)
+
+		c := checks(t, stdout)
+		require.Len(t, c, 4)
+		for name, check := range c {
+			assert.Equal(t, client.PingPassed, check["status"], name)
+		}
+		assert.Equal(t, "signed in as dev@ory.sh", c["authentication"]["details"])
+		assert.Contains(t, c["project API"]["details"], "good-wright-t7kzy3vugf.projects.console.ory.test responded with 200 OK")
+		for _, r := range requests {
+			assert.Regexp(t, "^GET ", r, "ping must not change anything")
+		}
+	})
+
+	t.Run("case=not signed in", func(t *testing.T) {
+		stdout, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(nil, "ping", "--format", "json")
+		require.NoError(t, err, stderr)
+
+		c := checks(t, stdout)
+		assert.Equal(t, client.PingPassed, c["console"]["status"])
+		assert.Equal(t, client.PingSkipped, c["authentication"]["status"])
+		assert.Equal(t, client.PingSkipped, c["project API"]["status"])
+		assert.Contains(t, stderr, "Some checks were skipped")
+	})
+
+	t.Run("case=expired session", func(t *testing.T) {
+		fake.ExpireSession("mocked")
+		t.Cleanup(func() { fake.AddSessionToken("dev@ory.sh", "mocked") })
+
+		cmd := newMockedCmd(t)
+		stdout, _, err := cmd.Exec(nil, "ping", "--project", "good-wright-t7kzy3vugf", "--format", "json")
+		require.Error(t, err)
+		assert.Equal(t, client.ExitCode(client.ErrSessionExpired), client.ExitCode(err))
+
+		c := checks(t, stdout)
+		assert.Equal(t, client.PingFailed, c["authentication"]["status"])
+		assert.Contains(t, c["authentication"]["details"], "run `ory auth` to sign in again")
+		assert.Equal(t, client.PingPassed, c["project API"]["status"], "the slug does not need to be looked up")
+	})
+}
@@ -0,0 +1,82 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+)
+
+type outputPlugins []client.Plugin
+
+func (outputPlugins) Header() []string {
+	return []string{"NAME", "PATH"}
+}
+
+func (p outputPlugins) Table() [][]string {
+	rows := make([][]string, len(p))
+	for k, plugin := range p {
+		rows[k] = []string{plugin.Name, plugin.Path}
+	}
+	return rows
+}
+
+func (p outputPlugins) Interface() interface{} {
+	return []client.Plugin(p)
+}
+
+func (p outputPlugins) Len() int {
+	return len(p)
+}
+
+func NewPluginsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugins",
+		Short: "Manage the plugins extending the Ory CLI",
+		Long: `Plugins extend the Ory CLI with your own commands. A plugin is an executable named ory-NAME on your PATH, which
+is run by "ory NAME", with all further arguments passed to it. Only absolute directories of PATH are searched, never
+the current directory. Set ` + client.NoPluginsEnv + ` to disable plugins.
+
+Plugins get the configuration of the CLI in their environment, so that they do not need to implement signing in:
+
+- ORY_CLOUD_CONFIG_PATH: the configuration file of the CLI.
+- ORY_SESSION_TOKEN: the session token, if you are signed in.
+- ORY_PROJECT: the ID of the selected project, if any.`,
+	}
+	cmd.AddCommand(NewListPluginsCmd())
+
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
+}
+
+func NewListPluginsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Args:  client.NoArgs,
+		Short: "List the plugins on your PATH",
+		Example: `$ ory plugins list
+NAME	PATH
+deploy	/usr/local/bin/ory-deploy`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+			if client.PluginsDisabled() {
+				h.Log.Warnf("Plugins are disabled because %s is set.", client.NoPluginsEnv)
+			}
+
+			plugins := client.FindPlugins()
+			for _, p := range plugins {
+				if c, _, err := cmd.Root().Find([]string{p.Name}); err == nil && c != cmd.Root() {
+					h.Log.Warnf("The plugin %s is never run because %q is a command of the Ory CLI.", p.Path, c.CommandPath())
+				}
+			}
+			client.PrintTable(cmd, outputPlugins(plugins))
+			return nil
+		},
+	}
+}
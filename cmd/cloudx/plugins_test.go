@@ -0,0 +1,29 @@
+package cloudx_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+func TestListPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"ory-deploy", "ory-list"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755))
+	}
+	t.Setenv("PATH", dir)
+
+	stdout, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(nil, "plugins", "list", "--format", "json")
+	require.NoError(t, err, stderr)
+	assert.JSONEq(t, `[{"name":"deploy","path":"`+filepath.Join(dir, "ory-deploy")+`"},{"name":"list","path":"`+filepath.Join(dir, "ory-list")+`"}]`, stdout)
+	assert.Contains(t, stderr, "ory-list is never run")
+}
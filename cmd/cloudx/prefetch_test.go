@@ -0,0 +1,103 @@
+package cloudx_test
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+// latencyRecorder delays every request by latency, e.g. to simulate a high-RTT link, and records how many requests
+// were in flight at the same time.
+type latencyRecorder struct {
+	next    http.Handler
+	latency time.Duration
+
+	sync.Mutex
+	inFlight, maxInFlight int
+	paths                 []string
+}
+
+func (l *latencyRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.Lock()
+	l.paths = append(l.paths, r.URL.Path)
+	if l.inFlight++; l.inFlight > l.maxInFlight {
+		l.maxInFlight = l.inFlight
+	}
+	l.Unlock()
+
+	time.Sleep(l.latency)
+
+	l.Lock()
+	l.inFlight--
+	l.Unlock()
+	l.next.ServeHTTP(w, r)
+}
+
+func TestProjectsAreFetchedWhileTheSessionIsChecked(t *testing.T) {
+	const latency = 250 * time.Millisecond
+	rec := &latencyRecorder{next: newMockBackend(), latency: latency}
+	cloudxtest.Serve(t, rec)
+	cmd := newMockedCmd(t)
+
+	reset := func() {
+		rec.Lock()
+		defer rec.Unlock()
+		rec.maxInFlight, rec.paths = 0, nil
+	}
+
+	t.Run("case=slug", func(t *testing.T) {
+		reset()
+		start := time.Now()
+		stdout, stderr, err := cmd.Exec(nil, "get", "project", "good-wright-t7kzy3vugf", "--format", "json")
+		require.NoError(t, err, stderr)
+		took := time.Since(start)
+		assert.Contains(t, stdout, mockedProjectID)
+
+		// Checking the session, listing the projects, checking the session, and fetching the project took four round
+		// trips in a row before.
+		t.Logf("Resolving the slug took %s, sequential requests take at least %s", took, 4*latency)
+		assert.Less(t, took, 4*latency)
+		assert.Equal(t, 2, rec.maxInFlight, "the session check and the project list overlap")
+	})
+
+	t.Run("case=project ID", func(t *testing.T) {
+		reset()
+		_, stderr, err := cmd.Exec(nil, "get", "project", mockedProjectID, "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.NotContains(t, rec.paths, "/projects", "the projects are not needed")
+		assert.Equal(t, 1, rec.maxInFlight)
+	})
+}
+
+func TestSessionIsCheckedOnce(t *testing.T) {
+	rec := &latencyRecorder{next: newMockBackend()}
+	cloudxtest.Serve(t, rec)
+	cmd := newMockedCmd(t)
+
+	for _, args := range [][]string{
+		{"get", "project", "good-wright-t7kzy3vugf", "--format", "json"},
+		{"get", "project-env", "--project", mockedProjectID},
+	} {
+		rec.Lock()
+		rec.paths = nil
+		rec.Unlock()
+
+		_, stderr, err := cmd.Exec(nil, args...)
+		require.NoError(t, err, stderr)
+		var checks int
+		for _, p := range rec.paths {
+			if p == "/sessions/whoami" {
+				checks++
+			}
+		}
+		assert.Equal(t, 1, checks, "%v: %v", args, rec.paths)
+		assert.Equal(t, 1, strings.Count(stderr, "You are authenticated as"), stderr)
+	}
+}
@@ -8,14 +8,24 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
-	"github.com/ory/x/cmdx"
 	"github.com/ory/x/flagx"
 )
 
 func NewCreateProjectCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "project",
+		Args:  client.NoArgs,
 		Short: "Create a new Ory Cloud Project",
+		Example: `$ ory create project --name "Example Project"
+
+ID		ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
+SLUG	good-wright-t7kzy3vugf
+STATE	running
+NAME	Example Project
+
+$ ory create project --name "Example Project" --format json
+
+$ ory use project "$(ory create project --name "Example Project" --id-only)"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			h, err := client.NewCommandHelper(cmd)
 			if err != nil {
@@ -23,8 +33,10 @@ func NewCreateProjectCmd() *cobra.Command {
 			}
 
 			name := flagx.MustGetString(cmd, "name")
-			if len(name) == 0 && flagx.MustGetBool(cmd, cmdx.FlagQuiet) {
-				return errors.New("you must specify the --name flag when using --quiet")
+			if len(name) == 0 {
+				if err := h.RequireInteractive("Enter a name for your project", "set the --name flag"); err != nil {
+					return err
+				}
 			}
 
 			stdin := h.Stdin
@@ -38,16 +50,18 @@ func NewCreateProjectCmd() *cobra.Command {
 
 			p, err := h.CreateProject(name)
 			if err != nil {
-				return cmdx.PrintOpenAPIError(cmd, err)
+				return client.PrintOpenAPIError(cmd, err)
 			}
 
-			_, _ = fmt.Fprintln(h.VerboseErrWriter, "Project created successfully!")
-			cmdx.PrintRow(cmd, (*outputProject)(p))
+			h.Log.Infof("Project created successfully!")
+			h.PrintConsoleLink(client.ConsoleProjectURL(p.Slug))
+			client.PrintRow(cmd, (*outputProject)(p))
+			client.PrintIDs(cmd, p.Id)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringP("name", "n", "", "The name of the project, required when quiet mode is used")
-	cmdx.RegisterFormatFlags(cmd.Flags())
-	return cmd
+	client.RegisterIDOnlyFlag(cmd.Flags())
+	return client.MarkDryRunCapable(cmd)
 }
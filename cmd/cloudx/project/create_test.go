@@ -40,7 +40,7 @@ func TestCreateProject(t *testing.T) {
 		stdin := bytes.NewBufferString(name)
 		_, stderr, err := defaultCmd.Exec(stdin, "create", "project", "--quiet")
 		require.Error(t, err)
-		assert.Contains(t, stderr, "you must specify the --name flag when using --quiet")
+		assert.Contains(t, stderr, "because the --quiet flag is set, please set the --name flag")
 	})
 
 	t.Run("is not able to create a project if not authenticated and quiet flag", func(t *testing.T) {
@@ -65,6 +65,7 @@ func TestCreateProject(t *testing.T) {
 		r.WriteString("n\n")        // Please inform me about platform and security updates? [y/n]: n
 		r.WriteString("n\n")        // I accept the Terms of Service [y/n]: n
 		r.WriteString("y\n")        // I accept the Terms of Service [y/n]: y
+		r.WriteString("\n")         // Email: fakeEmail(), Name: fakeName() — correct? [Y/n]:
 		stdout, stderr, err := cmd.Exec(&r, "create", "project", "--name", name, "--format", "json")
 		require.NoError(t, err)
 		t.Logf("stdout: %s", stdout)
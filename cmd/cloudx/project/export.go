@@ -0,0 +1,132 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	exportDirFlag = "dir"
+	// exportFormatTerraform is the only format of exports for now.
+	exportFormatTerraform = "terraform"
+	oauth2ClientsPageSize = 500
+)
+
+func NewExportProjectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Args:  client.NoArgs,
+		Short: "Export an Ory Cloud project as Terraform configuration",
+		Long: `Export the selected project, its identity configuration, and its OAuth2 clients as Terraform JSON configuration,
+so that existing projects can be adopted by Terraform.
+
+One file per resource type is written to --dir, along with variables.tf.json which declares a sensitive variable
+for every secret found in the configuration. Secrets are never written to the files. Existing files of the export are
+overwritten; the output only depends on the project, so exporting twice yields the same files.
+
+The "terraform import" commands which adopt the existing resources are printed to stdout. Each file notes which fields
+Terraform can not manage.`,
+		Example: `$ ory export project --project my-project --format terraform --dir ./terraform
+terraform import ory_project.my_project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
+terraform import ory_identity_config.my_project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
+terraform import ory_oauth2_client.my_app ecaaa3cb-0730-4ee8-a6df-9553cdfeef89/3b2f1a0e-1c2d-4e5f-8a9b-0c1d2e3f4a5b`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format := flagx.MustGetString(cmd, cmdx.FlagFormat); format != exportFormatTerraform {
+				return client.NewValidationError(errors.Errorf("unknown export format %q, expected %s", format, exportFormatTerraform), nil)
+			}
+			dir := flagx.MustGetString(cmd, exportDirFlag)
+			if dir == "" {
+				return client.NewValidationError(errors.Errorf("--%s must be set", exportDirFlag), nil)
+			}
+
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewProjectAPI()
+			if err != nil {
+				return err
+			}
+
+			clients, err := listOAuth2Clients(cmd.Context(), api)
+			if err != nil {
+				return err
+			}
+
+			export := newTerraformExport(api.Project, clients)
+			files, err := writeExport(dir, export.Files)
+			if err != nil {
+				return err
+			}
+
+			for _, f := range files {
+				_, _ = fmt.Fprintf(h.VerboseErrWriter, "Wrote %s\n", f)
+			}
+			_, _ = fmt.Fprintf(h.VerboseErrWriter, "\n%s\n\n", terraformNote)
+			for _, i := range export.Imports {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), i)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(cmdx.FlagFormat, exportFormatTerraform, "Set the format of the export. Only terraform is supported.")
+	cmd.Flags().String(exportDirFlag, "", "The directory to write the exported files to. It is created if it does not exist.")
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
+
+// listOAuth2Clients returns all OAuth2 clients of the project as returned by the admin API.
+func listOAuth2Clients(ctx context.Context, api *client.ProjectAPI) ([]map[string]interface{}, error) {
+	var clients []map[string]interface{}
+	query := url.Values{"page_size": {fmt.Sprint(oauth2ClientsPageSize)}}
+	for query != nil {
+		var page []map[string]interface{}
+		res, err := api.Do(ctx, http.MethodGet, "/admin/clients", query, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, page...)
+		query = client.NextPageQuery(res)
+	}
+	return clients, nil
+}
+
+// writeExport writes the files as indented JSON with sorted keys and returns their paths in order.
+func writeExport(dir string, files map[string]interface{}) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	paths := make([]string, len(names))
+	for i, name := range names {
+		out, err := json.MarshalIndent(files[name], "", "  ")
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		paths[i] = filepath.Join(dir, name)
+		if err := os.WriteFile(paths[i], append(out, '\n'), 0644); err != nil {
+			return nil, errors.Wrapf(err, "unable to write %s", paths[i])
+		}
+	}
+	return paths, nil
+}
@@ -4,14 +4,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
-	"github.com/ory/x/cmdx"
 )
 
 func NewGetProjectCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "project <id>",
-		Args:  cobra.ExactArgs(1),
-		Short: "Get an Ory Cloud project",
+		Use:         "project <id>",
+		Args:        client.ExactArgs(1, client.ProjectIDOrSlug),
+		Short:       "Get an Ory Cloud project",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "slug"},
 		Example: `$ ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
 
 ID		ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
@@ -42,16 +42,20 @@ $ ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format json
 				return err
 			}
 
-			project, err := h.GetProject(args[0])
+			id, err := h.ResolveProjectID(args[0])
 			if err != nil {
-				return cmdx.PrintOpenAPIError(cmd, err)
+				return err
+			}
+
+			project, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
 			}
 
-			cmdx.PrintRow(cmd, (*outputProject)(project))
+			client.PrintRow(cmd, (*outputProject)(project))
 			return nil
 		},
 	}
 
-	cmdx.RegisterFormatFlags(cmd.Flags())
-	return cmd
+	return client.MarkProjectsNeeded(cmd)
 }
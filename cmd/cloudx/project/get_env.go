@@ -0,0 +1,167 @@
+package project
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	envClientFlag         = "client"
+	envOutFlag            = "out"
+	envPrefixFlag         = "prefix"
+	envIncludeSecretsFlag = "include-secrets"
+)
+
+var envPrefixPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// projectEnv holds the values apps need to integrate with a project.
+type projectEnv struct {
+	ProjectID       string `json:"project_id"`
+	ProjectSlug     string `json:"project_slug"`
+	SDKURL          string `json:"sdk_url"`
+	DefaultSchemaID string `json:"default_schema_id,omitempty"`
+	ClientID        string `json:"client_id,omitempty"`
+	ClientSecret    string `json:"client_secret,omitempty"`
+
+	prefix string
+}
+
+func (e *projectEnv) vars() []client.EnvVar {
+	vars := []client.EnvVar{
+		{Name: e.prefix + "PROJECT_ID", Value: e.ProjectID},
+		{Name: e.prefix + "PROJECT_SLUG", Value: e.ProjectSlug},
+		{Name: e.prefix + "SDK_URL", Value: e.SDKURL},
+	}
+	for _, v := range []client.EnvVar{
+		{Name: e.prefix + "DEFAULT_SCHEMA_ID", Value: e.DefaultSchemaID},
+		{Name: e.prefix + "CLIENT_ID", Value: e.ClientID},
+		{Name: e.prefix + "CLIENT_SECRET", Value: e.ClientSecret},
+	} {
+		if v.Value != "" {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
+
+func (e *projectEnv) String() string {
+	return client.Dotenv(e.vars())
+}
+
+func NewGetProjectEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project-env",
+		Args:  client.NoArgs,
+		Short: "Get the values apps need to integrate with an Ory Cloud project as a dotenv file",
+		Long: `Get the values apps need to integrate with the selected project in the dotenv format:
+
+- PROJECT_ID and PROJECT_SLUG: the ID and slug of the project.
+- SDK_URL: the URL of the project's APIs, which the Ory SDKs use.
+- DEFAULT_SCHEMA_ID: the ID of the default identity schema, if the project sets one.
+- CLIENT_ID: the ID of the OAuth2 client set by --client.
+- CLIENT_SECRET: the secret of that client, only with --include-secrets.
+
+The keys are prefixed with ORY_ unless --prefix is set, e.g. to NEXT_PUBLIC_ORY_ for values used in the browser.
+Use --out to write the values to a file instead of stdout, and --format json for consumers which do not read dotenv
+files. OAuth2 client secrets can only be included if the API returns them, which is usually only the case right after
+creating the client.`,
+		Example: `$ ory get project-env --project my-project
+ORY_PROJECT_ID="ecaaa3cb-0730-4ee8-a6df-9553cdfeef89"
+ORY_PROJECT_SLUG="good-wright-t7kzy3vugf"
+ORY_SDK_URL="https://good-wright-t7kzy3vugf.projects.oryapis.com"
+ORY_DEFAULT_SCHEMA_ID="preset://email"
+
+$ ory get project-env --client 3b2f1a0e-1c2d-4e5f-8a9b-0c1d2e3f4a5b --prefix NEXT_PUBLIC_ORY_ --out .env.ory
+
+$ ory get project-env --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefix := flagx.MustGetString(cmd, envPrefixFlag)
+			if !envPrefixPattern.MatchString(prefix) {
+				return client.NewValidationError(errors.Errorf("--%s must only contain letters, digits, and underscores, and must not start with a digit", envPrefixFlag), nil)
+			}
+			clientID := flagx.MustGetString(cmd, envClientFlag)
+			includeSecrets := flagx.MustGetBool(cmd, envIncludeSecretsFlag)
+			if includeSecrets && clientID == "" {
+				return client.NewValidationError(errors.Errorf("--%s requires --%s, the client secret is the only secret", envIncludeSecretsFlag, envClientFlag), nil)
+			}
+
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewProjectAPI()
+			if err != nil {
+				return err
+			}
+
+			env := &projectEnv{
+				ProjectID:   api.Project.Id,
+				ProjectSlug: api.Project.Slug,
+				SDKURL:      client.ProjectAPIURL(api.Project.Slug),
+				prefix:      prefix,
+			}
+			if api.Project.Services.Identity != nil {
+				env.DefaultSchemaID = defaultSchemaID(api.Project.Services.Identity.Config)
+			}
+
+			if clientID != "" {
+				var c struct {
+					ID     string `json:"client_id"`
+					Secret string `json:"client_secret"`
+				}
+				if _, err := api.Do(cmd.Context(), http.MethodGet, "/admin/clients/"+url.PathEscape(clientID), nil, nil, &c); err != nil {
+					return err
+				}
+				env.ClientID = c.ID
+				switch {
+				case includeSecrets && c.Secret == "":
+					h.Log.Warnf("The API does not return the secret of the OAuth2 client %s, it is only shown when the client is created. The secret is not included.", c.ID)
+				case includeSecrets:
+					env.ClientSecret = c.Secret
+					h.Log.Warnf("The output contains the OAuth2 client secret. Do not commit it to version control or expose it to browsers.")
+				}
+			}
+
+			out := flagx.MustGetString(cmd, envOutFlag)
+			if out == "" {
+				client.PrintJSONAble(cmd, env)
+				return nil
+			}
+
+			var b bytes.Buffer
+			stdout := cmd.OutOrStdout()
+			cmd.SetOut(&b)
+			client.PrintJSONAble(cmd, env)
+			cmd.SetOut(stdout)
+			if err := os.WriteFile(out, b.Bytes(), 0600); err != nil {
+				return errors.Wrapf(err, "unable to write %s", out)
+			}
+			h.Log.Infof("Wrote the environment of project %s to %s.", env.ProjectSlug, out)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(envClientFlag, "", "The ID of an OAuth2 client of the project to include.")
+	cmd.Flags().String(envOutFlag, "", "Write the values to this file instead of stdout. Existing files are overwritten.")
+	cmd.Flags().String(envPrefixFlag, "ORY_", "The prefix of all keys.")
+	cmd.Flags().Bool(envIncludeSecretsFlag, false, "Include the secret of the OAuth2 client set by --client.")
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
+
+// defaultSchemaID returns the ID of the default identity schema from the identity configuration.
+func defaultSchemaID(config map[string]interface{}) string {
+	identity, _ := config["identity"].(map[string]interface{})
+	id, _ := identity["default_schema_id"].(string)
+	return id
+}
@@ -4,16 +4,16 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
-	"github.com/ory/x/cmdx"
 )
 
 func NewGetKratosConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "identity-config <project-id>",
-		Aliases: []string{"ic", "kratos-config"},
-		Args:    cobra.ExactArgs(1),
-		Short:   "Get an Ory Cloud project's identity configuration",
-		Long:    "You can use this command to render Ory Kratos configurations as well.",
+		Use:         "identity-config <project-id>",
+		Aliases:     []string{"ic", "kratos-config"},
+		Args:        client.ExactArgs(1, client.ProjectIDOrSlug),
+		Short:       "Get an Ory Cloud project's identity configuration",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "selfservice.flows.login.ui_url"},
+		Long:        "You can use this command to render Ory Kratos configurations as well.",
 		Example: `$ ory get kratos-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format yaml > kratos-config.yaml
 
 $ ory get kratos-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format json
@@ -32,16 +32,20 @@ $ ory get kratos-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format json
 				return err
 			}
 
-			project, err := h.GetProject(args[0])
+			id, err := h.ResolveProjectID(args[0])
 			if err != nil {
-				return cmdx.PrintOpenAPIError(cmd, err)
+				return err
+			}
+
+			project, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
 			}
 
-			cmdx.PrintJSONAble(cmd, outputConfig(project.Services.Identity.Config))
+			client.PrintJSONAble(cmd, outputConfig(project.Services.Identity.Config))
 			return nil
 		},
 	}
 
-	cmdx.RegisterJSONFormatFlags(cmd.Flags())
-	return cmd
+	return client.MarkProjectsNeeded(cmd)
 }
@@ -4,16 +4,16 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
-	"github.com/ory/x/cmdx"
 )
 
 func NewGetKetoConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "permission-config <project-id>",
-		Aliases: []string{"pc", "keto-config"},
-		Args:    cobra.ExactArgs(1),
-		Short:   "Get an Ory Cloud project's permission configuration",
-		Long:    "You can use this command to render Ory Keto configurations as well.",
+		Use:         "permission-config <project-id>",
+		Aliases:     []string{"pc", "keto-config"},
+		Args:        client.ExactArgs(1, client.ProjectIDOrSlug),
+		Short:       "Get an Ory Cloud project's permission configuration",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "namespaces.#.name"},
+		Long:        "You can use this command to render Ory Keto configurations as well.",
 		Example: `$ ory get keto-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format yaml > keto-config.yaml
 
 $ ory get keto-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format json
@@ -33,16 +33,20 @@ $ ory get keto-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format json
 				return err
 			}
 
-			project, err := h.GetProject(args[0])
+			id, err := h.ResolveProjectID(args[0])
 			if err != nil {
-				return cmdx.PrintOpenAPIError(cmd, err)
+				return err
+			}
+
+			project, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
 			}
 
-			cmdx.PrintJSONAble(cmd, outputConfig(project.Services.Permission.Config))
+			client.PrintJSONAble(cmd, outputConfig(project.Services.Permission.Config))
 			return nil
 		},
 	}
 
-	cmdx.RegisterJSONFormatFlags(cmd.Flags())
-	return cmd
+	return client.MarkProjectsNeeded(cmd)
 }
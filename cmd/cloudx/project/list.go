@@ -4,13 +4,20 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
-	"github.com/ory/x/cmdx"
 )
 
 func NewListProjectsCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "projects",
-		Short: "List your Ory Cloud projects",
+		Use:         "projects",
+		Args:        client.NoArgs,
+		Short:       "List your Ory Cloud projects",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "id"},
+		Example: `$ ory list projects
+
+ID					SLUG			STATE	NAME
+ecaaa3cb-0730-4ee8-a6df-9553cdfeef89	good-wright-t7kzy3vugf	running	Example Project
+
+$ ory list projects --format json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			h, err := client.NewCommandHelper(cmd)
 			if err != nil {
@@ -19,13 +26,14 @@ func NewListProjectsCmd() *cobra.Command {
 
 			projects, err := h.ListProjects()
 			if err != nil {
-				return cmdx.PrintOpenAPIError(cmd, err)
+				return client.PrintOpenAPIError(cmd, err)
 			}
 
-			cmdx.PrintTable(cmd, &outputProjectCollection{projects})
+			client.PrintTable(cmd, &outputProjectCollection{projects})
 			return nil
 		},
 	}
 
-	return cmd
+	client.RegisterSortFlags(cmd.Flags())
+	return client.MarkProjectsNeeded(cmd)
 }
@@ -0,0 +1,142 @@
+package project
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const oplFileFlag = "file"
+
+type (
+	oplPosition struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	}
+	oplSyntaxError struct {
+		File    string      `json:"file"`
+		Message string      `json:"message"`
+		Start   oplPosition `json:"start"`
+		End     oplPosition `json:"end"`
+	}
+	oplSyntaxErrors []oplSyntaxError
+)
+
+func (e oplSyntaxErrors) String() string {
+	var b strings.Builder
+	for _, err := range e {
+		_, _ = fmt.Fprintf(&b, "%s:%d:%d: %s\n", err.File, err.Start.Line, err.Start.Column, err.Message)
+	}
+	return b.String()
+}
+
+// Annotations reports the syntax errors at their locations in GitHub Actions.
+func (e oplSyntaxErrors) Annotations() []client.Annotation {
+	annotations := make([]client.Annotation, 0, len(e))
+	for _, err := range e {
+		a := client.Annotation{
+			Level:   client.AnnotationError,
+			Line:    err.Start.Line,
+			Column:  err.Start.Column,
+			Title:   "Ory Permission Language syntax error",
+			Message: err.Message,
+		}
+		if err.File != client.StdinFile {
+			a.File = err.File
+		}
+		// Columns can only be set for annotations of a single line.
+		if err.End.Line == err.Start.Line {
+			a.EndColumn = err.End.Column
+		} else {
+			a.Column, a.EndLine = 0, err.End.Line
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations
+}
+
+func readOPLFile(cmd *cobra.Command) (string, []byte, error) {
+	file := flagx.MustGetString(cmd, oplFileFlag)
+	if file == "" {
+		return "", nil, errors.Errorf("--%s must be set", oplFileFlag)
+	}
+
+	contents, err := client.ReadInputFile(cmd, file)
+	if err != nil {
+		return "", nil, err
+	}
+	return client.InputFileName(file), contents, nil
+}
+
+// checkOPLSyntax uses the syntax check endpoint of the project's permission API to find errors in
+// the Ory Permission Language file.
+func checkOPLSyntax(ctx context.Context, api *client.ProjectAPI, file string, contents []byte) (oplSyntaxErrors, error) {
+	var res struct {
+		Errors oplSyntaxErrors `json:"errors"`
+	}
+	if _, err := api.Send(ctx, http.MethodPost, "/opl/syntax/check", nil, "text/plain", strings.NewReader(string(contents)), &res); err != nil {
+		return nil, err
+	}
+	errs := oplSyntaxErrors{}
+	for _, e := range res.Errors {
+		e.File = file
+		errs = append(errs, e)
+	}
+	return errs, nil
+}
+
+// deployedOPL returns the Ory Permission Language file currently configured for the project. It returns
+// false if the project does not use an OPL file which was uploaded by the CLI.
+func deployedOPL(config map[string]interface{}) (string, bool) {
+	namespaces, ok := config["namespaces"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	location, ok := namespaces["location"].(string)
+	if !ok || !strings.HasPrefix(location, "base64://") {
+		return "", false
+	}
+
+	encoded := strings.TrimPrefix(location, "base64://")
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if decoded, err := enc.DecodeString(encoded); err == nil {
+			return string(decoded), true
+		}
+	}
+	return "", false
+}
+
+func oplLocation(contents []byte) string {
+	return "base64://" + base64.StdEncoding.EncodeToString(contents)
+}
+
+func diffOPL(deployed, updated, file string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(deployed),
+		B:        difflib.SplitLines(updated),
+		FromFile: "deployed",
+		ToFile:   file,
+		Context:  3,
+	})
+}
+
+func printOPLSyntaxErrors(cmd *cobra.Command, errs oplSyntaxErrors) {
+	if client.IsMachineReadableFormat(cmd) {
+		client.PrintJSONAble(cmd, errs)
+		return
+	}
+	if client.GitHubAnnotations(cmd) {
+		client.PrintAnnotations(cmd, errs.Annotations())
+		return
+	}
+	_, _ = fmt.Fprint(cmd.ErrOrStderr(), errs.String())
+}
@@ -0,0 +1,53 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func TestDeployedOPL(t *testing.T) {
+	opl := "class User implements Namespace {}\n"
+
+	actual, ok := deployedOPL(map[string]interface{}{
+		"namespaces": map[string]interface{}{"location": oplLocation([]byte(opl))},
+	})
+	require.True(t, ok)
+	assert.Equal(t, opl, actual)
+
+	for _, config := range []map[string]interface{}{
+		{},
+		{"namespaces": []interface{}{map[string]interface{}{"name": "files", "id": 1}}},
+		{"namespaces": map[string]interface{}{"location": "file:///etc/keto/namespaces.ts"}},
+	} {
+		_, ok := deployedOPL(config)
+		assert.False(t, ok, "%+v", config)
+	}
+}
+
+func TestDiffOPL(t *testing.T) {
+	diff, err := diffOPL("class User implements Namespace {}\n", "class User implements Namespace {}\nclass Group implements Namespace {}\n", "namespaces.ts")
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- deployed\n+++ namespaces.ts\n")
+	assert.Contains(t, diff, "+class Group implements Namespace {}\n")
+}
+
+func TestOPLSyntaxErrorAnnotations(t *testing.T) {
+	errs := oplSyntaxErrors{
+		{File: "namespaces.ts", Message: `expected "=>", got "="`, Start: oplPosition{Line: 12, Column: 5}, End: oplPosition{Line: 12, Column: 6}},
+		{File: "namespaces.ts", Message: "unterminated block", Start: oplPosition{Line: 3, Column: 1}, End: oplPosition{Line: 5, Column: 2}},
+		{File: client.StdinFile, Message: "unexpected EOF", Start: oplPosition{Line: 1, Column: 1}, End: oplPosition{Line: 1, Column: 1}},
+	}
+	var actual []string
+	for _, a := range errs.Annotations() {
+		actual = append(actual, a.String())
+	}
+	assert.Equal(t, []string{
+		`::error file=namespaces.ts,line=12,col=5,endColumn=6,title=Ory Permission Language syntax error::expected "=>", got "="`,
+		`::error file=namespaces.ts,line=3,endLine=5,title=Ory Permission Language syntax error::unterminated block`,
+		`::error line=1,col=1,endColumn=1,title=Ory Permission Language syntax error::unexpected EOF`,
+	}, actual)
+}
@@ -1,7 +1,12 @@
 package project
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
 
 	cloud "github.com/ory/client-go"
 )
@@ -61,6 +66,24 @@ func (c *outputProjectCollection) Table() [][]string {
 	return rows
 }
 
+func (*outputProjectCollection) WideHeader() []string {
+	return []string{"ID", "SLUG", "STATE", "NAME", "HOSTS", "CREATED AT", "UPDATED AT"}
+}
+
+func (c *outputProjectCollection) WideTable() [][]string {
+	rows := c.Table()
+	for i, p := range c.projects {
+		// Not all fields are available in all API versions, so they are read from the JSON representation.
+		raw, _ := json.Marshal(p)
+		var hosts []string
+		for _, h := range gjson.GetBytes(raw, "hosts").Array() {
+			hosts = append(hosts, h.String())
+		}
+		rows[i] = append(rows[i], strings.Join(hosts, ", "), gjson.GetBytes(raw, "created_at").String(), gjson.GetBytes(raw, "updated_at").String())
+	}
+	return rows
+}
+
 func (c *outputProjectCollection) Interface() interface{} {
 	return c.projects
 }
@@ -68,3 +91,36 @@ func (c *outputProjectCollection) Interface() interface{} {
 func (c *outputProjectCollection) Len() int {
 	return len(c.projects)
 }
+
+type outputRateLimits []rateLimitProbe
+
+func (outputRateLimits) Header() []string {
+	return []string{"CLASS", "ENDPOINT", "LIMIT", "REMAINING", "RESET"}
+}
+
+func (o outputRateLimits) Table() [][]string {
+	rows := make([][]string, len(o))
+	for k, p := range o {
+		switch {
+		case p.Error != "":
+			rows[k] = []string{p.Class, p.Endpoint, "error: " + p.Error, "", ""}
+		case p.RateLimit == nil:
+			rows[k] = []string{p.Class, p.Endpoint, "not limited", "", ""}
+		default:
+			var reset string
+			if !p.RateLimit.Reset.IsZero() {
+				reset = p.RateLimit.Reset.Format(time.RFC3339)
+			}
+			rows[k] = []string{p.Class, p.Endpoint, fmt.Sprintf("%d", p.RateLimit.Limit), fmt.Sprintf("%d", p.RateLimit.Remaining), reset}
+		}
+	}
+	return rows
+}
+
+func (o outputRateLimits) Interface() interface{} {
+	return o
+}
+
+func (o outputRateLimits) Len() int {
+	return len(o)
+}
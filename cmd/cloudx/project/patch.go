@@ -8,24 +8,23 @@ import (
 
 	"github.com/ory/cli/cmd/cloudx/client"
 	cloud "github.com/ory/client-go"
-	"github.com/ory/x/cmdx"
 	"github.com/ory/x/flagx"
 )
 
 func NewProjectsPatchCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "project <id>",
-		Args:  cobra.ExactArgs(1),
+		Args:  client.ExactArgs(1, client.ProjectIDOrSlug),
 		Short: "Patch an Ory Cloud Project",
-		Example: `ory patch project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
+		Example: `$ ory patch project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
 	--replace '/name="My new project name"' \
 	--add '/services/identity/config/courier/smtp={"from_name":"My new email name"}' \
 	--replace '/services/identity/config/selfservice/methods/password/enabled=false' \
 	--delete '/services/identity/config/selfservice/methods/totp/enabled'
 
-ory patch project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
+$ ory patch project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
 	--replace '/name="My new project name"' \
-	--delete '/services/identity/config/selfservice/methods/totp/enabled'
+	--delete '/services/identity/config/selfservice/methods/totp/enabled' \
 	--format kratos-config > my-config.yaml`,
 		Long: `Use this command to patch your current Ory Cloud Project's service configuration. Only values
 specified in the patch will be overwritten. To replace the config use the ` + "`update`" + ` command instead.
@@ -43,13 +42,11 @@ The format of the patch is a JSON-Patch document. For more details please check:
 		),
 	}
 
-	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the project")
+	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the project. Use - to read from stdin.")
 	cmd.Flags().StringArray("replace", nil, "Replace a specific key in the configuration")
 	cmd.Flags().StringArray("add", nil, "Add a specific key to the configuration")
 	cmd.Flags().StringArray("remove", nil, "Remove a specific key from the configuration")
-	client.RegisterYesFlag(cmd.Flags())
-	cmdx.RegisterFormatFlags(cmd.Flags())
-	return cmd
+	return client.MarkProjectsNeeded(client.MarkDryRunCapable(cmd))
 }
 
 func runPatch(patchPrefixer func([]string) []string, filePrefixer func([]json.RawMessage) ([]json.RawMessage, error), outputter func(*cobra.Command, *cloud.SuccessfulProjectUpdate)) func(cmd *cobra.Command, args []string) error {
@@ -68,7 +65,7 @@ func runPatch(patchPrefixer func([]string) []string, filePrefixer func([]json.Ra
 			return errors.New("at least one of --file, --add, --replace, or --remove must be set")
 		}
 
-		configs, err := client.ReadConfigFiles(files)
+		configs, err := client.ReadConfigFiles(cmd, files)
 		if err != nil {
 			return err
 		}
@@ -78,9 +75,14 @@ func runPatch(patchPrefixer func([]string) []string, filePrefixer func([]json.Ra
 			return err
 		}
 
-		p, err := h.PatchProject(args[0], configs, add, replace, remove)
+		id, err := h.ResolveProjectID(args[0])
+		if err != nil {
+			return err
+		}
+
+		p, err := h.PatchProject(id, configs, add, replace, remove)
 		if err != nil {
-			return cmdx.PrintOpenAPIError(cmd, err)
+			return client.PrintOpenAPIError(cmd, err)
 		}
 
 		outputter(cmd, p)
@@ -4,14 +4,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
-	"github.com/ory/x/cmdx"
 )
 
 func NewPatchKratosConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "identity-config <project-id>",
 		Aliases: []string{"ic", "kratos-config"},
-		Args:    cobra.ExactArgs(1),
+		Args:    client.ExactArgs(1, client.ProjectIDOrSlug),
 		Short:   "Patch an Ory Cloud Project's Identity Config",
 		Example: `$ ory patch identity-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
 	--add '/courier/smtp={"from_name":"My new email name"}' \
@@ -26,7 +25,9 @@ func NewPatchKratosConfigCmd() *cobra.Command {
     }
     // ...
   }
-}`,
+}
+
+$ ory patch identity-config my-project --replace '/selfservice/methods/totp/enabled=true' --format yaml`,
 		Long: `Use this command to patch your current Ory Cloud Project's identity service configuration. Only values
 specified in the patch will be overwritten. To replace the config use the ` + "`update`" + ` command instead.
 
@@ -53,11 +54,9 @@ The format of the patch is a JSON-Patch document. For more details please check:
 		),
 	}
 
-	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the project")
+	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the project. Use - to read from stdin.")
 	cmd.Flags().StringArray("replace", nil, "Replace a specific key in the configuration")
 	cmd.Flags().StringArray("add", nil, "Add a specific key to the configuration")
 	cmd.Flags().StringArray("remove", nil, "Remove a specific key from the configuration")
-	client.RegisterYesFlag(cmd.Flags())
-	cmdx.RegisterFormatFlags(cmd.Flags())
-	return cmd
+	return client.MarkDryRunCapable(cmd)
 }
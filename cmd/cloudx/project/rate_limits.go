@@ -0,0 +1,93 @@
+package project
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+// rateLimitProbes are cheap requests used to look up the rate limit of each endpoint class.
+var rateLimitProbes = []struct {
+	class, path string
+	query       url.Values
+	public      bool
+}{
+	{class: "public", path: "/sessions/whoami", public: true},
+	{class: "identity admin", path: "/admin/identities", query: url.Values{"per_page": {"1"}}},
+	{class: "oauth2 admin", path: "/admin/clients", query: url.Values{"page_size": {"1"}}},
+	{class: "permission", path: "/relation-tuples", query: url.Values{"page_size": {"1"}}},
+}
+
+type rateLimitProbe struct {
+	Class     string            `json:"class"`
+	Endpoint  string            `json:"endpoint"`
+	RateLimit *client.RateLimit `json:"rate_limit"`
+	Error     string            `json:"error,omitempty"`
+}
+
+func probeRateLimits(ctx context.Context, api *client.ProjectAPI) []rateLimitProbe {
+	public := api.WithoutCredentials()
+	probes := make([]rateLimitProbe, len(rateLimitProbes))
+	for k, p := range rateLimitProbes {
+		a := api
+		if p.public {
+			a = public
+		}
+
+		probes[k] = rateLimitProbe{Class: p.class, Endpoint: "GET " + p.path}
+		res, err := a.Do(ctx, http.MethodGet, p.path, p.query, nil, nil)
+		if res != nil {
+			// Rate limits are returned for error responses as well, e.g. the whoami endpoint responds with 401.
+			probes[k].RateLimit, _ = client.ParseRateLimit(res.Header, time.Now())
+		}
+		var apiErr *client.APIError
+		if err != nil && !errors.As(err, &apiErr) {
+			probes[k].Error = err.Error()
+		}
+	}
+	return probes
+}
+
+func NewGetRateLimitsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rate-limits",
+		Aliases: []string{"rate-limit"},
+		Args:    client.NoArgs,
+		Short:   "Show the rate limits of an Ory Cloud project",
+		Long: `Show the rate limits of the selected project per endpoint class by sending a cheap request to each class and
+reading the rate limit response headers.
+
+Use --format json to integrate the rate limits into your monitoring. To see the rate limits of every request a command
+sends, use the --verbose flag.`,
+		Example: `$ ory get rate-limits --project my-project
+
+CLASS		ENDPOINT		LIMIT	REMAINING	RESET
+public		GET /sessions/whoami	600	599		2022-06-01T12:01:00Z
+identity admin	GET /admin/identities	100	97		2022-06-01T12:01:00Z
+
+$ ory get rate-limits --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewProjectAPI()
+			if err != nil {
+				return err
+			}
+
+			client.PrintTable(cmd, outputRateLimits(probeRateLimits(cmd.Context(), api)))
+			return nil
+		},
+	}
+
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
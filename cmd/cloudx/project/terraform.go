@@ -0,0 +1,224 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	cloud "github.com/ory/client-go"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+// The resource types of the Ory Terraform provider which projects are exported as.
+const (
+	terraformProject        = "ory_project"
+	terraformIdentityConfig = "ory_identity_config"
+	terraformOAuth2Client   = "ory_oauth2_client"
+)
+
+// The files of a Terraform export, one per resource type.
+const (
+	terraformProjectFile        = "project.tf.json"
+	terraformIdentityConfigFile = "identity_config.tf.json"
+	terraformOAuth2ClientsFile  = "oauth2_clients.tf.json"
+	terraformVariablesFile      = "variables.tf.json"
+)
+
+// terraformNote lists what the export does not round-trip. It is added to every file as a comment and printed after
+// exporting.
+const terraformNote = "Exported by `ory export project`. Terraform can not manage the ID, slug, state, and hosts of the project, " +
+	"which are computed by Ory Cloud. The permission and OAuth2 service configurations are not exported. " +
+	"Secrets are replaced by variables, which must be set before applying, and OAuth2 client secrets can not be read back from the API at all."
+
+// oauth2ClientComputedFields are set by Ory Cloud and can not be managed by Terraform.
+var oauth2ClientComputedFields = map[string]bool{
+	"client_id":                 true,
+	"client_secret_expires_at":  true,
+	"created_at":                true,
+	"registration_access_token": true,
+	"registration_client_uri":   true,
+	"updated_at":                true,
+}
+
+var terraformInvalidName = regexp.MustCompile(`[^a-z0-9_]+`)
+
+type terraformVariable struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Sensitive   bool   `json:"sensitive"`
+}
+
+// terraformExport is the Terraform JSON configuration of a project. The output is deterministic: it only depends on
+// the project and its clients, not on the order in which the API returned them.
+type terraformExport struct {
+	// Files maps the file names onto their contents.
+	Files map[string]interface{}
+	// Imports are the `terraform import` commands which adopt the existing resources.
+	Imports   []string
+	variables map[string]terraformVariable
+	names     map[string]bool
+}
+
+// newTerraformExport converts the project, its identity configuration, and its OAuth2 clients. The clients are the
+// JSON objects returned by the OAuth2 admin API.
+func newTerraformExport(p *cloud.Project, clients []map[string]interface{}) *terraformExport {
+	e := &terraformExport{Files: map[string]interface{}{}, variables: map[string]terraformVariable{}, names: map[string]bool{}}
+
+	project := e.name(terraformProject, p.Slug, "project")
+	e.addFile(terraformProjectFile, terraformProject, map[string]interface{}{
+		project: map[string]interface{}{"name": escapeTerraformTemplate(p.Name)},
+	})
+	e.Imports = append(e.Imports, terraformImport(terraformProject, project, p.Id))
+
+	projectID := fmt.Sprintf("${%s.%s.id}", terraformProject, project)
+	var config interface{} = map[string]interface{}{}
+	if p.Services.Identity != nil {
+		config = e.convert(map[string]interface{}(p.Services.Identity.Config), []string{"identity_config", project}, false)
+	}
+	e.addFile(terraformIdentityConfigFile, terraformIdentityConfig, map[string]interface{}{
+		project: map[string]interface{}{"project_id": projectID, "config": config},
+	})
+	e.Imports = append(e.Imports, terraformImport(terraformIdentityConfig, project, p.Id))
+
+	sorted := append([]map[string]interface{}{}, clients...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return fmt.Sprint(sorted[i]["client_id"]) < fmt.Sprint(sorted[j]["client_id"])
+	})
+	resources := map[string]interface{}{}
+	for _, c := range sorted {
+		id := fmt.Sprint(c["client_id"])
+		label, _ := c["client_name"].(string)
+		if label == "" {
+			label = id
+		}
+		name := e.name(terraformOAuth2Client, label, "client")
+
+		body := map[string]interface{}{}
+		for k, v := range c {
+			if v != nil && !oauth2ClientComputedFields[k] {
+				body[k] = v
+			}
+		}
+		converted := e.convert(body, []string{"oauth2_client", name}, false).(map[string]interface{})
+		converted["project_id"] = projectID
+		resources[name] = converted
+		e.Imports = append(e.Imports, terraformImport(terraformOAuth2Client, name, p.Id+"/"+id))
+	}
+	if len(resources) > 0 {
+		e.addFile(terraformOAuth2ClientsFile, terraformOAuth2Client, resources)
+	}
+
+	e.Files[terraformVariablesFile] = map[string]interface{}{"//": terraformNote, "variable": e.variables}
+	return e
+}
+
+func (e *terraformExport) addFile(file, resourceType string, resources map[string]interface{}) {
+	e.Files[file] = map[string]interface{}{
+		"//":       terraformNote,
+		"resource": map[string]interface{}{resourceType: resources},
+	}
+}
+
+// name returns a unique Terraform name of a resource derived from the label, e.g. the slug of a project.
+func (e *terraformExport) name(resourceType, label, fallback string) string {
+	name := strings.Trim(terraformInvalidName.ReplaceAllString(strings.ToLower(label), "_"), "_")
+	switch {
+	case name == "":
+		name = fallback
+	case name[0] >= '0' && name[0] <= '9':
+		name = fallback + "_" + name
+	}
+	unique := name
+	for i := 2; e.names[resourceType+"."+unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	e.names[resourceType+"."+unique] = true
+	return unique
+}
+
+// convert replaces secrets by references to variables and escapes all other strings, so that Terraform does not
+// interpret them as templates. All values nested in a sensitive key, e.g. secrets.default, are secrets. Objects are
+// kept, so that only their values become variables.
+func (e *terraformExport) convert(v interface{}, path []string, secret bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			r[k] = e.convert(value, subPath(path, k), secret || isTerraformSecret(k))
+		}
+		return r
+	case []interface{}:
+		if secret && !containsObjects(v) {
+			return e.variable(v, path)
+		}
+		r := make([]interface{}, len(v))
+		for i, value := range v {
+			r[i] = e.convert(value, subPath(path, strconv.Itoa(i)), secret)
+		}
+		return r
+	case nil:
+		return nil
+	}
+	if secret {
+		return e.variable(v, path)
+	}
+	if s, ok := v.(string); ok {
+		return escapeTerraformTemplate(s)
+	}
+	return v
+}
+
+// variable declares a sensitive variable for the secret at path and returns the reference to it.
+func (e *terraformExport) variable(secret interface{}, path []string) string {
+	name := terraformInvalidName.ReplaceAllString(strings.ToLower(strings.Join(path, "_")), "_")
+	typ := "any"
+	switch s := secret.(type) {
+	case string:
+		typ = "string"
+	case bool:
+		typ = "bool"
+	case float64, json.Number:
+		typ = "number"
+	case []interface{}:
+		typ = "list(string)"
+		for _, item := range s {
+			if _, ok := item.(string); !ok {
+				typ = "list(any)"
+			}
+		}
+	}
+	e.variables[name] = terraformVariable{Type: typ, Description: "The value of " + strings.Join(path, "."), Sensitive: true}
+	return "${var." + name + "}"
+}
+
+// isTerraformSecret returns true for keys with credentials, including connection URIs which contain passwords.
+func isTerraformSecret(key string) bool {
+	return client.IsSensitiveKey(key) || strings.Contains(strings.ToLower(key), "connection_uri")
+}
+
+// escapeTerraformTemplate escapes the template sequences of Terraform's JSON syntax.
+func escapeTerraformTemplate(s string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(s)
+}
+
+func terraformImport(resourceType, name, id string) string {
+	return fmt.Sprintf("terraform import %s.%s %s", resourceType, name, id)
+}
+
+func subPath(path []string, key string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), key)
+}
+
+func containsObjects(values []interface{}) bool {
+	for _, v := range values {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return true
+		}
+	}
+	return false
+}
@@ -8,14 +8,13 @@ import (
 
 	"github.com/ory/cli/cmd/cloudx/client"
 	cloud "github.com/ory/client-go"
-	"github.com/ory/x/cmdx"
 	"github.com/ory/x/flagx"
 )
 
 func NewProjectsUpdateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "project <id>",
-		Args:  cobra.ExactArgs(1),
+		Args:  client.ExactArgs(1, client.ProjectIDOrSlug),
 		Short: "Update Ory Cloud Project Service Configuration",
 		Example: `$ ory update project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
 	--name \"my updated name\" \
@@ -80,10 +79,8 @@ As an example an input could look like:
 	}
 
 	cmd.Flags().StringP("name", "n", "", "The new name of the project.")
-	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the project")
-	client.RegisterYesFlag(cmd.Flags())
-	cmdx.RegisterFormatFlags(cmd.Flags())
-	return cmd
+	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the project. Use - to read from stdin.")
+	return client.MarkProjectsNeeded(client.MarkDryRunCapable(cmd))
 }
 
 func runUpdate(filePrefixer func([]json.RawMessage) ([]json.RawMessage, error), outputter func(*cobra.Command, *cloud.SuccessfulProjectUpdate)) func(*cobra.Command, []string) error {
@@ -98,7 +95,7 @@ func runUpdate(filePrefixer func([]json.RawMessage) ([]json.RawMessage, error),
 			return errors.New("--file must be set")
 		}
 
-		configs, err := client.ReadConfigFiles(files)
+		configs, err := client.ReadConfigFiles(cmd, files)
 		if err != nil {
 			return err
 		}
@@ -112,9 +109,14 @@ func runUpdate(filePrefixer func([]json.RawMessage) ([]json.RawMessage, error),
 		if n := cmd.Flags().Lookup("name"); n != nil {
 			name = n.Value.String()
 		}
-		p, err := h.UpdateProject(args[0], name, configs)
+		id, err := h.ResolveProjectID(args[0])
 		if err != nil {
-			return cmdx.PrintOpenAPIError(cmd, err)
+			return err
+		}
+
+		p, err := h.UpdateProject(id, name, configs)
+		if err != nil {
+			return client.PrintOpenAPIError(cmd, err)
 		}
 
 		outputter(cmd, p)
@@ -4,7 +4,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
-	"github.com/ory/x/cmdx"
 )
 
 func NewUpdateIdentityConfigCmd() *cobra.Command {
@@ -14,7 +13,7 @@ func NewUpdateIdentityConfigCmd() *cobra.Command {
 			"ic",
 			"kratos-config",
 		},
-		Args:  cobra.ExactArgs(1),
+		Args:  client.ExactArgs(1, client.ProjectIDOrSlug),
 		Short: "Update Ory Cloud Project's Identity Service Configuration",
 		Example: `$ ory update identity-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
 	--file /path/to/config.json \
@@ -64,9 +63,6 @@ This command expects the contents of the ` + "`/services/identity/config`" + ` k
 		RunE: runUpdate(prefixFileIdentityConfig, outputIdentityConfig),
 	}
 
-	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the identity config")
-	client.RegisterYesFlag(cmd.Flags())
-	cmdx.RegisterNoiseFlags(cmd.Flags())
-	cmdx.RegisterJSONFormatFlags(cmd.Flags())
-	return cmd
+	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the identity config. Use - to read from stdin.")
+	return client.MarkDryRunCapable(cmd)
 }
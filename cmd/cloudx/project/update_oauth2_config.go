@@ -4,8 +4,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
-
-	"github.com/ory/x/cmdx"
 )
 
 func NewUpdateOAuth2ConfigCmd() *cobra.Command {
@@ -15,7 +13,7 @@ func NewUpdateOAuth2ConfigCmd() *cobra.Command {
 			"oc",
 			"hydra-config",
 		},
-		Args:  cobra.ExactArgs(1),
+		Args:  client.ExactArgs(1, client.ProjectIDOrSlug),
 		Short: "Update Ory Cloud Project's OAuth2 Service Configuration",
 		Example: `$ ory update oauth2-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
 	--file /path/to/config.json \
@@ -67,9 +65,6 @@ This command expects the contents of the ` + "`/services/oauth2/config`" + ` key
 		RunE: runUpdate(prefixFileOAuth2Config, outputOAuth2Config),
 	}
 
-	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the oAuth2 config")
-	client.RegisterYesFlag(cmd.Flags())
-	cmdx.RegisterNoiseFlags(cmd.Flags())
-	cmdx.RegisterJSONFormatFlags(cmd.Flags())
-	return cmd
+	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the oAuth2 config. Use - to read from stdin.")
+	return client.MarkDryRunCapable(cmd)
 }
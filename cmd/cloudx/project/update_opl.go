@@ -0,0 +1,94 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func NewUpdateOPLCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "opl",
+		Args:  client.NoArgs,
+		Short: "Upload an Ory Permission Language file to an Ory Cloud project",
+		Long: `Upload an Ory Permission Language (OPL) file and use it as the namespace configuration of the selected project.
+
+The file is checked for syntax errors before anything is applied. The difference to the currently deployed
+OPL is shown and must be confirmed, unless the --yes flag is set.`,
+		Example: `$ ory update opl --file namespaces.ts --project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
+--- deployed
++++ namespaces.ts
+@@ -1,3 +1,4 @@
+ class User implements Namespace {}
++class Group implements Namespace {}
+...
+Do you want to apply these changes? [y/n]: y
+
+$ ory update opl --file namespaces.ts --project my-project --yes --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			file, contents, err := readOPLFile(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewProjectAPI()
+			if err != nil {
+				return err
+			}
+
+			errs, err := checkOPLSyntax(cmd.Context(), api, file, contents)
+			if err != nil {
+				return err
+			}
+			if len(errs) > 0 {
+				printOPLSyntaxErrors(cmd, errs)
+				return client.FailSilently(cmd, client.ExitValidation)
+			}
+
+			deployed, _ := deployedOPL(api.Project.Services.GetPermission().Config)
+			if deployed == string(contents) {
+				h.Log.Infof("The Ory Permission Language file is already deployed, nothing to do.")
+				return nil
+			}
+
+			diff, err := diffOPL(deployed, string(contents), file)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			_, _ = fmt.Fprintln(h.VerboseErrWriter, h.Colors.Diff(diff))
+			if client.GitHubAnnotations(cmd) && file != client.StdinFile {
+				client.PrintAnnotations(cmd, client.DiffAnnotations(diff, file, client.AnnotationNotice, "Changes to the deployed Ory Permission Language file"))
+			}
+
+			if err := h.ConfirmOrAbort("Do you want to apply these changes?"); err != nil {
+				return err
+			}
+
+			value, err := json.Marshal(map[string]string{"location": oplLocation(contents)})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			p, err := h.PatchProject(api.Project.Id, nil, []string{"/services/permission/config/namespaces=" + string(value)}, nil, nil)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+
+			outputPermissionConfig(cmd, p)
+			return h.PrintUpdateProjectWarnings(p)
+		},
+	}
+
+	cmd.Flags().StringP(oplFileFlag, oplFileFlag[:1], "", "The Ory Permission Language file to upload. Use - to read from stdin.")
+	client.RegisterProjectFlag(cmd.Flags())
+	return client.MarkDryRunCapable(cmd)
+}
@@ -4,7 +4,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
-	"github.com/ory/x/cmdx"
 )
 
 func NewUpdatePermissionConfigCmd() *cobra.Command {
@@ -14,7 +13,7 @@ func NewUpdatePermissionConfigCmd() *cobra.Command {
 			"pc",
 			"keto-config",
 		},
-		Args:  cobra.ExactArgs(1),
+		Args:  client.ExactArgs(1, client.ProjectIDOrSlug),
 		Short: "Update Ory Cloud Project's Permission Service Configuration",
 		Example: `$ ory update permission-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 \
 	--file /path/to/config.json \
@@ -66,9 +65,6 @@ This command expects the contents of the ` + "`/services/permission/config`" + `
 		RunE: runUpdate(prefixFilePermissionConfig, outputPermissionConfig),
 	}
 
-	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the permission config")
-	client.RegisterYesFlag(cmd.Flags())
-	cmdx.RegisterNoiseFlags(cmd.Flags())
-	cmdx.RegisterJSONFormatFlags(cmd.Flags())
-	return cmd
+	cmd.Flags().StringSliceP("file", "f", nil, "Configuration file(s) (file://config.json, https://example.org/config.yaml, ...) to update the permission config. Use - to read from stdin.")
+	return client.MarkDryRunCapable(cmd)
 }
@@ -0,0 +1,56 @@
+package project
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func NewUseProjectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project [id]",
+		Args:  client.MaximumNArgs(1, client.ProjectIDOrSlug),
+		Short: "Set the default Ory Cloud project",
+		Long: `Set the project which is used by all commands unless the --project flag is set.
+
+If no project ID is given, you can pick one of your projects interactively.`,
+		Example: `$ ory use project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
+
+ID		ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
+SLUG	good-wright-t7kzy3vugf
+STATE	running
+NAME	Example Project
+
+$ ory use project
+Select a project: ecaaa3cb-0730-4ee8-a6df-9553cdfeef89`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			var id string
+			if len(args) == 1 {
+				if id, err = h.ResolveProjectID(args[0]); err != nil {
+					return err
+				}
+			} else if id, err = h.SelectProject("pass the project ID as an argument"); err != nil {
+				return err
+			}
+
+			project, err := h.GetProject(id)
+			if err != nil {
+				return client.PrintOpenAPIError(cmd, err)
+			}
+			if err := h.SetDefaultProject(project.Id); err != nil {
+				return err
+			}
+
+			h.Log.Infof("Project %q is now the default project.", project.Name)
+			client.PrintRow(cmd, (*outputProject)(project))
+			return nil
+		},
+	}
+
+	return client.MarkProjectsNeeded(cmd)
+}
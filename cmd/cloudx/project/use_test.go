@@ -0,0 +1,43 @@
+package project_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+func TestUseProject(t *testing.T) {
+	configDir := testhelpers.NewConfigDir(t)
+	cmd := testhelpers.ConfigAwareCmd(configDir)
+	testhelpers.RegisterAccount(t, configDir)
+	first := testhelpers.CreateProject(t, configDir)
+	second := testhelpers.CreateProject(t, configDir)
+
+	t.Run("is able to use a project by ID", func(t *testing.T) {
+		stdout, stderr, err := cmd.Exec(nil, "use", "project", first, "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Equal(t, first, gjson.Get(stdout, "id").String())
+		assert.Equal(t, first, testhelpers.ReadConfig(t, configDir).SelectedProject.String())
+	})
+
+	t.Run("is able to select a project", func(t *testing.T) {
+		stdout, stderr, err := cmd.Exec(bytes.NewBufferString(second+"\n"), "use", "project", "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "Select a project: "+second)
+		assert.Equal(t, second, gjson.Get(stdout, "id").String())
+		assert.Equal(t, second, testhelpers.ReadConfig(t, configDir).SelectedProject.String())
+	})
+
+	t.Run("is not able to select a project if quiet flag", func(t *testing.T) {
+		_, _, err := cmd.Exec(nil, "use", "project", "--quiet")
+		var promptErr *client.PromptDisabledError
+		require.ErrorAs(t, err, &promptErr)
+		assert.Equal(t, second, testhelpers.ReadConfig(t, configDir).SelectedProject.String())
+	})
+}
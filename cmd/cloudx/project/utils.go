@@ -6,8 +6,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/tidwall/sjson"
 
+	"github.com/ory/cli/cmd/cloudx/client"
 	cloud "github.com/ory/client-go"
-	"github.com/ory/x/cmdx"
 )
 
 func prefixConfig(prefix string, s []string) []string {
@@ -57,17 +57,17 @@ func prefixFileNop(s []json.RawMessage) ([]json.RawMessage, error) {
 }
 
 func outputFullProject(cmd *cobra.Command, p *cloud.SuccessfulProjectUpdate) {
-	cmdx.PrintRow(cmd, (*outputProject)(&p.Project))
+	client.PrintRow(cmd, (*outputProject)(&p.Project))
 }
 
 func outputIdentityConfig(cmd *cobra.Command, p *cloud.SuccessfulProjectUpdate) {
-	cmdx.PrintJSONAble(cmd, outputConfig(p.Project.Services.Identity.Config))
+	client.PrintJSONAble(cmd, outputConfig(p.Project.Services.Identity.Config))
 }
 
 func outputPermissionConfig(cmd *cobra.Command, p *cloud.SuccessfulProjectUpdate) {
-	cmdx.PrintJSONAble(cmd, outputConfig(p.Project.Services.Permission.Config))
+	client.PrintJSONAble(cmd, outputConfig(p.Project.Services.Permission.Config))
 }
 
 func outputOAuth2Config(cmd *cobra.Command, p *cloud.SuccessfulProjectUpdate) {
-	cmdx.PrintJSONAble(cmd, outputConfig(p.Project.Services.Oauth2.Config))
+	client.PrintJSONAble(cmd, outputConfig(p.Project.Services.Oauth2.Config))
 }
@@ -0,0 +1,60 @@
+package project
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func NewValidateOPLCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "opl",
+		Args:  client.NoArgs,
+		Short: "Validate an Ory Permission Language file",
+		Long: `Check an Ory Permission Language (OPL) file for syntax errors using the selected project's permission API.
+
+Errors are reported with their line and column. Use --format json to get the errors in a machine readable
+format, for example for editor integrations. In GitHub Actions, or with --format github, the errors are reported
+as annotations of the file. Nothing is applied to the project.
+
+The command exits with 3 if the file has errors. With --exit-code, it exits with 1 instead, and with at least 2 if
+the file could not be checked, e.g. because the API is unavailable.`,
+		Example: `$ ory validate opl --file namespaces.ts
+namespaces.ts:12:5: expected "=>", got "="
+
+$ ory validate opl --file namespaces.ts --format json
+[{"file":"namespaces.ts","message":"expected \"=>\", got \"=\"","start":{"line":12,"column":5},"end":{"line":12,"column":6}}]`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			file, contents, err := readOPLFile(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewServiceAPI(client.ServicePermission)
+			if err != nil {
+				return err
+			}
+
+			errs, err := checkOPLSyntax(cmd.Context(), api, file, contents)
+			if err != nil {
+				return err
+			}
+
+			printOPLSyntaxErrors(cmd, errs)
+			if len(errs) > 0 {
+				return client.NewFindingsError(cmd, client.ExitValidation)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(oplFileFlag, oplFileFlag[:1], "", "The Ory Permission Language file to validate. Use - to read from stdin.")
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterExitCodeFlag(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,82 @@
+package cloudx_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+func TestGetProjectEnv(t *testing.T) {
+	fake := newMockBackend()
+	fake.HandleFunc("/admin/clients/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/clients/app":
+			_, _ = fmt.Fprint(w, `{"client_id": "app", "client_name": "My App", "client_secret": "s3cr3t"}`)
+		case "/admin/clients/legacy":
+			_, _ = fmt.Fprint(w, `{"client_id": "legacy", "client_name": "Legacy App"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"error": {"code": 404, "message": "Unable to locate the resource"}}`)
+		}
+	})
+	cloudxtest.Serve(t, fake)
+	const sdkURL = "http://good-wright-t7kzy3vugf.projects.console.ory.test"
+
+	t.Run("case=prints the project as dotenv", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "get", "project-env", "--project", "good-wright-t7kzy3vugf", "--client", "app")
+		require.NoError(t, err, stderr)
+		assert.Equal(t, `ORY_PROJECT_ID="`+mockedProjectID+`"
+ORY_PROJECT_SLUG="good-wright-t7kzy3vugf"
+ORY_SDK_URL="`+sdkURL+`"
+ORY_CLIENT_ID="app"
+`, stdout)
+	})
+
+	t.Run("case=prefix and secrets", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "get", "project-env", "--project", mockedProjectID, "--client", "app", "--prefix", "NEXT_PUBLIC_ORY_", "--include-secrets")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, "NEXT_PUBLIC_ORY_PROJECT_SLUG=\"good-wright-t7kzy3vugf\"\n")
+		assert.Contains(t, stdout, "NEXT_PUBLIC_ORY_CLIENT_SECRET=\"s3cr3t\"\n")
+		assert.Contains(t, stderr, "The output contains the OAuth2 client secret")
+
+		stdout, stderr, err = newMockedCmd(t).Exec(nil, "get", "project-env", "--project", mockedProjectID, "--client", "legacy", "--include-secrets")
+		require.NoError(t, err, stderr)
+		assert.NotContains(t, stdout, "CLIENT_SECRET")
+		assert.Contains(t, stderr, "does not return the secret")
+	})
+
+	t.Run("case=json", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "get", "project-env", "--project", mockedProjectID, "--client", "app", "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.JSONEq(t, `{"project_id": "`+mockedProjectID+`", "project_slug": "good-wright-t7kzy3vugf", "sdk_url": "`+sdkURL+`", "client_id": "app"}`, stdout)
+	})
+
+	t.Run("case=writes the file", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), ".env.ory")
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "get", "project-env", "--project", mockedProjectID, "--out", out)
+		require.NoError(t, err, stderr)
+		assert.Empty(t, stdout)
+		content, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `ORY_SDK_URL="`+sdkURL+`"`)
+	})
+
+	t.Run("case=invalid input", func(t *testing.T) {
+		_, _, err := newMockedCmd(t).Exec(nil, "get", "project-env", "--project", mockedProjectID, "--include-secrets")
+		assert.Equal(t, client.ExitValidation, client.ExitCode(err))
+
+		_, _, err = newMockedCmd(t).Exec(nil, "get", "project-env", "--project", mockedProjectID, "--prefix", "1ORY-")
+		assert.Equal(t, client.ExitValidation, client.ExitCode(err))
+
+		_, _, err = newMockedCmd(t).Exec(nil, "get", "project-env", "--project", mockedProjectID, "--client", "unknown")
+		assert.Equal(t, client.ExitNotFound, client.ExitCode(err))
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/ory/cli/cmd/cloudx/client"
 	"github.com/ory/x/corsx"
 	"github.com/ory/x/flagx"
 	"github.com/ory/x/stringsx"
@@ -19,7 +20,7 @@ func NewProxyCommand(self string, version string) *cobra.Command {
 	proxyCmd := &cobra.Command{
 		Use:   "proxy application-url [publish-url]",
 		Short: "Run your app and Ory on the same domain using a reverse proxy",
-		Args:  cobra.RangeArgs(1, 2),
+		Args:  client.RangeArgs(1, 2),
 		Example: fmt.Sprintf(`%[1]s proxy http://localhost:3000 --dev
 %[1]s proxy http://localhost:3000 https://app.example.com \
 	--allowed-cors-origins https://www.example.org \
@@ -188,6 +189,11 @@ An example payload of the JSON Web Token is:
 				return err
 			}
 
+			transport, err := client.NewTransport(cmd, oryURL)
+			if err != nil {
+				return err
+			}
+
 			origins, err := corsx.NormalizeOriginStrings(append(
 				flagx.MustGetStringSlice(cmd, CORSFlag), selfURL.String()),
 			)
@@ -208,6 +214,7 @@ An example payload of the JSON Web Token is:
 				isDev:             flagx.MustGetBool(cmd, DevFlag),
 				isDebug:           flagx.MustGetBool(cmd, DebugFlag),
 				corsOrigins:       origins,
+				transport:         transport,
 			}
 
 			return run(cmd, conf, version, "cloud")
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/ory/cli/cmd/cloudx/client"
 	"github.com/ory/x/corsx"
 	"github.com/ory/x/flagx"
 	"github.com/ory/x/stringsx"
@@ -15,7 +16,7 @@ func NewTunnelCommand(self string, version string) *cobra.Command {
 	proxyCmd := &cobra.Command{
 		Use:   "tunnel application-url [tunnel-url]",
 		Short: "Tunnel Ory on a subdomain of your app or a separate port your app's domain",
-		Args:  cobra.RangeArgs(1, 2),
+		Args:  client.RangeArgs(1, 2),
 		Example: fmt.Sprintf(`%[1]s tunnel http://localhost:3000 --dev
 %[1]s tunnel https://app.example.com \
 	--allowed-cors-origins https://www.example.org \
@@ -120,6 +121,11 @@ TO use a different default redirect URL, use the `+"`"+`--default-redirect-url`+
 				return err
 			}
 
+			transport, err := client.NewTransport(cmd, oryURL)
+			if err != nil {
+				return err
+			}
+
 			appURL, err := url.ParseRequestURI(args[0])
 			if err != nil {
 				return err
@@ -146,6 +152,7 @@ TO use a different default redirect URL, use the `+"`"+`--default-redirect-url`+
 				isDev:             flagx.MustGetBool(cmd, DevFlag),
 				isDebug:           flagx.MustGetBool(cmd, DebugFlag),
 				corsOrigins:       origins,
+				transport:         transport,
 			}
 
 			return run(cmd, conf, version, "cloud")
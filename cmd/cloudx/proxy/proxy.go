@@ -59,6 +59,8 @@ type config struct {
 	isDebug           bool
 	isDev             bool
 	corsOrigins       []string
+	// transport sends the requests to the upstream and Ory, see client.NewTransport.
+	transport http.RoundTripper
 }
 
 func portFromEnv() int {
@@ -131,7 +133,8 @@ func run(cmd *cobra.Command, conf *config, version string, name string) error {
 			}
 
 			return body, nil
-		})))
+		}),
+		proxy.WithTransport(conf.transport)))
 
 	cleanup := func() error {
 		return nil
@@ -232,7 +235,7 @@ func newSigner(l *logrusx.Logger, conf *config) (jose.Signer, *jose.JSONWebKeySe
 }
 
 func checkOry(conf *config, _ *logrusx.Logger, writer herodot.Writer, keys *jose.JSONWebKeySet, sig jose.Signer, endpoint *url.URL) func(http.ResponseWriter, *http.Request, http.HandlerFunc) {
-	hc := httpx.NewResilientClient(httpx.ResilientClientWithMaxRetry(5), httpx.ResilientClientWithMaxRetryWait(time.Millisecond*5), httpx.ResilientClientWithConnectionTimeout(time.Second*2))
+	hc := httpx.NewResilientClient(httpx.ResilientClientWithClient(&http.Client{Transport: conf.transport}), httpx.ResilientClientWithMaxRetry(5), httpx.ResilientClientWithMaxRetryWait(time.Millisecond*5), httpx.ResilientClientWithConnectionTimeout(time.Second*2))
 
 	var publicKeys jose.JSONWebKeySet
 	for _, key := range keys.Keys {
@@ -0,0 +1,185 @@
+package relationtuple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	subjectFlag   = "subject"
+	relationFlag  = "relation"
+	namespaceFlag = "namespace"
+	objectFlag    = "object"
+	maxDepthFlag  = "max-depth"
+	watchFlag     = "watch"
+	intervalFlag  = "interval"
+)
+
+func NewIsAllowedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "allowed [<subject> <relation> <namespace> <object>]",
+		Short: "Check whether a subject has a relation on an object",
+		Long: `Check whether a subject has a relation on an object in the selected Ory Cloud project.
+
+The command prints "Allowed" and exits with code 0 if the check succeeds. Otherwise it prints
+"Denied" and exits with code 1, which makes it easy to use in shell scripts. Machine readable formats print the
+response of the check API as is. In watch mode, each check is printed on its own line together with the time of the
+check, e.g. {"checked_at":"2022-06-01T12:00:00Z","response":{"allowed":true}}.
+
+The subject is either a subject ID (e.g. user:alice) or a subject set in the form of
+namespace:object#relation (e.g. groups:admins#member).`,
+		Example: `$ ory is allowed user:alice viewer documents doc-1 --project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
+Allowed
+
+$ ory is allowed --subject groups:admins#member --relation owner --namespace documents --object doc-1
+Denied
+
+$ ory is allowed user:alice viewer documents doc-1 --watch --interval 5s`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 && len(args) != 4 {
+				return client.NewArgsError(cmd, fmt.Sprintf("expected either no or exactly four arguments <subject> <relation> <namespace> <object> but got %d", len(args)))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			tuple, err := checkTupleFromInput(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewServiceAPI(client.ServicePermission)
+			if err != nil {
+				return err
+			}
+
+			query := tuple.subjectQuery()
+			if depth := flagx.MustGetInt(cmd, maxDepthFlag); depth > 0 {
+				query.Set("max-depth", fmt.Sprintf("%d", depth))
+			}
+
+			if !flagx.MustGetBool(cmd, watchFlag) {
+				result, err := check(cmd.Context(), api, query)
+				if err != nil {
+					return err
+				}
+				if client.IsMachineReadableFormat(cmd) {
+					client.PrintJSONAble(cmd, result.raw)
+				} else {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), result.String())
+				}
+				if !result.Allowed {
+					return client.FailSilently(cmd, client.ExitFailure)
+				}
+				return nil
+			}
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+
+			interval := flagx.MustGetDuration(cmd, intervalFlag)
+			for {
+				result, err := check(ctx, api, query)
+				checkedAt := time.Now()
+				if ctx.Err() != nil {
+					return nil
+				} else if err != nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s Unable to check permission: %s\n", checkedAt.Format(time.RFC3339), err)
+				} else if client.IsMachineReadableFormat(cmd) {
+					// Every line of the stream carries the time of the check, like the human readable output.
+					client.PrintJSONAble(cmd, &watchedCheck{CheckedAt: checkedAt.UTC(), Response: result.raw})
+				} else {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", checkedAt.Format(time.RFC3339), result)
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().String(subjectFlag, "", "The subject ID or subject set (namespace:object#relation) to check.")
+	cmd.Flags().String(relationFlag, "", "The relation to check.")
+	cmd.Flags().String(namespaceFlag, "", "The namespace of the object.")
+	cmd.Flags().String(objectFlag, "", "The object to check.")
+	cmd.Flags().Int(maxDepthFlag, 0, "The maximum depth of the search tree. Uses the server's default if not set.")
+	cmd.Flags().BoolP(watchFlag, watchFlag[:1], false, "Repeat the check until interrupted.")
+	cmd.Flags().Duration(intervalFlag, 2*time.Second, "The interval between checks in watch mode.")
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
+
+func checkTupleFromInput(cmd *cobra.Command, args []string) (*relationTuple, error) {
+	t := &relationTuple{
+		Relation:  flagx.MustGetString(cmd, relationFlag),
+		Namespace: flagx.MustGetString(cmd, namespaceFlag),
+		Object:    flagx.MustGetString(cmd, objectFlag),
+	}
+	subject := flagx.MustGetString(cmd, subjectFlag)
+	if len(args) == 4 {
+		subject, t.Relation, t.Namespace, t.Object = args[0], args[1], args[2], args[3]
+	}
+
+	var err error
+	t.SubjectID, t.SubjectSet, err = parseSubject(subject)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.validate(); err != nil {
+		return nil, client.NewValidationError(errors.Wrapf(err, "either pass <subject> <relation> <namespace> <object> as arguments or use the --%s, --%s, --%s, and --%s flags", subjectFlag, relationFlag, namespaceFlag, objectFlag), nil)
+	}
+	return t, nil
+}
+
+// check checks the permission. The result keeps the raw response so that machine readable formats print the response
+// of the API as is.
+func check(ctx context.Context, api *client.ProjectAPI, query url.Values) (*checkResult, error) {
+	var raw json.RawMessage
+	if _, err := api.Do(ctx, http.MethodGet, "/relation-tuples/check", query, nil, &raw); err != nil {
+		// Older versions of the check API respond with 403 Forbidden and {"allowed":false} if the subject is not
+		// allowed. Other 403 responses mean that the request itself is not allowed.
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+			if result, ok := legacyDenial(apiErr.Body); ok {
+				return result, nil
+			}
+		}
+		return nil, err
+	}
+
+	result := checkResult{raw: outputCheckResponse(raw)}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, errors.Wrap(err, "unable to decode the response of the check API")
+	}
+	return &result, nil
+}
+
+// legacyDenial returns the denied result of a 403 Forbidden response of older versions of the check API.
+func legacyDenial(body []byte) (*checkResult, bool) {
+	var legacy struct {
+		Allowed *bool `json:"allowed"`
+	}
+	if err := json.Unmarshal(body, &legacy); err != nil || legacy.Allowed == nil || *legacy.Allowed {
+		return nil, false
+	}
+	return &checkResult{raw: outputCheckResponse(body)}, true
+}
@@ -0,0 +1,230 @@
+package relationtuple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+func NewDeleteRelationTuplesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "relation-tuples",
+		Aliases: []string{"relation-tuple"},
+		Args:    client.NoArgs,
+		Short:   "Delete relation tuples matching a filter",
+		Long: `Delete all relation tuples of the selected Ory Cloud project which match the filter. The namespace is
+required, all other parts of the filter are optional.
+
+The deletion shows the number of matching relation tuples and needs to be confirmed or requires --yes. Deleting all
+relation tuples of a namespace requires typing the name of the namespace.
+
+Use ` + "`--file`" + ` instead of the filter to delete the relation tuples of a newline delimited JSON file in the format of
+` + "`ory import relation-tuples`" + `. They are deleted by ` + "`--workers`" + ` concurrent requests, and server and network errors are
+retried. Relation tuples which could not be deleted are written to ` + "`--failed-file`" + ` so that they can be deleted again.
+Ctrl-C stops the deletion after the requests in flight finished. Use ` + "`--strict`" + ` to exit with a non-zero code if any
+relation tuple could not be deleted.`,
+		Example: `$ ory delete relation-tuples --namespace documents --object doc-1
+You are about to delete the relation tuples:
+  Namespace:        documents
+  Object:           doc-1
+  Relation tuples:  3
+Do you want to delete the relation tuples? [y/n]: y
+
+NAMESPACE	OBJECT	RELATION	SUBJECT
+documents	doc-1	*		*
+
+$ ory delete relation-tuples --namespace documents --subject user:alice --yes
+
+$ ory delete relation-tuples --file revoked.ndjson --workers 16 --yes
+
+TOTAL	DELETED	NOT FOUND	FAILED	THROTTLED	FAILED FILE
+10000	10000	0		0	0`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			filter := &tupleFilter{
+				Namespace: flagx.MustGetString(cmd, namespaceFlag),
+				Object:    flagx.MustGetString(cmd, objectFlag),
+				Relation:  flagx.MustGetString(cmd, relationFlag),
+				Subject:   flagx.MustGetString(cmd, subjectFlag),
+			}
+			if file := flagx.MustGetString(cmd, fileFlag); file != "" {
+				if *filter != (tupleFilter{}) {
+					return client.NewValidationError(errors.Errorf("--%s cannot be combined with a filter", fileFlag), nil)
+				}
+				return deleteTuplesFromFile(cmd, h, file)
+			}
+
+			query, err := filter.query()
+			if err != nil {
+				return client.NewValidationError(err, nil)
+			}
+
+			api, err := h.NewServiceAPI(client.ServicePermission)
+			if err != nil {
+				return err
+			}
+
+			d := &client.Destruction{
+				Action:  "delete the relation tuples",
+				Summary: filter.summary(),
+				FetchSummary: func() ([]client.SummaryLine, error) {
+					count, err := countTuples(cmd.Context(), api, query)
+					if err != nil {
+						return nil, err
+					}
+					return []client.SummaryLine{{Key: "Relation tuples", Value: fmt.Sprintf("%d", count)}}, nil
+				},
+			}
+			if filter.Object == "" && filter.Relation == "" && filter.Subject == "" {
+				d.Level, d.Name = client.ConfirmWithName, filter.Namespace
+				d.Action = "delete all relation tuples of the namespace"
+			}
+			if err := h.ConfirmDestruction(d); err != nil {
+				return err
+			}
+
+			if _, err := api.Do(cmd.Context(), http.MethodDelete, "/admin/relation-tuples", query, nil, nil); err != nil {
+				return err
+			}
+
+			client.PrintRow(cmd, filter)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(namespaceFlag, "", "The namespace of the relation tuples to delete.")
+	cmd.Flags().String(objectFlag, "", "Only delete relation tuples of this object.")
+	cmd.Flags().String(relationFlag, "", "Only delete relation tuples with this relation.")
+	cmd.Flags().String(subjectFlag, "", "Only delete relation tuples of this subject ID or subject set (namespace:object#relation).")
+	cmd.Flags().StringP(fileFlag, fileFlag[:1], "", "Newline delimited JSON file containing the relation tuples to delete instead of a filter. Use - to read from stdin.")
+	client.RegisterBulkDeleteFlags(cmd.Flags(), "failed-relation-tuples.ndjson")
+	client.RegisterProjectFlag(cmd.Flags())
+	return client.MarkDryRunCapable(cmd)
+}
+
+// deleteTuplesFromFile deletes the relation tuples of a file one by one.
+func deleteTuplesFromFile(cmd *cobra.Command, h *client.CommandHelper, file string) error {
+	tuples, err := readTuplesFromFile(cmd, file)
+	if err != nil {
+		return err
+	}
+
+	// The keys are the JSON encoded tuples, so that the failed file can be passed to --file again.
+	keys := make([]string, len(tuples))
+	byKey := make(map[string]*relationTuple, len(tuples))
+	for k, t := range tuples {
+		encoded, err := json.Marshal(t)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		keys[k] = string(encoded)
+		byKey[keys[k]] = t
+	}
+
+	api, err := h.NewServiceAPI(client.ServicePermission)
+	if err != nil {
+		return err
+	}
+	deletion, err := h.NewBulkDeletion(cmd, "Deleting relation tuples", keys, func(ctx context.Context, key string) (*http.Response, error) {
+		return api.Do(ctx, http.MethodDelete, "/admin/relation-tuples", byKey[key].subjectQuery(), nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := h.ConfirmDestruction(&client.Destruction{
+		Action:  "delete the relation tuples",
+		Summary: []client.SummaryLine{{Key: "Relation tuples", Value: fmt.Sprintf("%d", len(tuples))}},
+	}); err != nil {
+		return err
+	}
+	if h.DryRun {
+		return nil
+	}
+
+	summary := deletion.Run(cmd.Context())
+	client.PrintRow(cmd, summary)
+	return h.FinishBulkDeletion(cmd, summary)
+}
+
+// tupleFilter selects relation tuples. Empty fields match everything.
+type tupleFilter struct {
+	Namespace string `json:"namespace"`
+	Object    string `json:"object,omitempty"`
+	Relation  string `json:"relation,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+}
+
+// query returns the URL query of the filter as expected by the relation tuple APIs.
+func (f *tupleFilter) query() (url.Values, error) {
+	if f.Namespace == "" {
+		return nil, errors.Errorf("--%s must be set", namespaceFlag)
+	}
+
+	q := url.Values{"namespace": {f.Namespace}}
+	if f.Object != "" {
+		q.Set("object", f.Object)
+	}
+	if f.Relation != "" {
+		q.Set("relation", f.Relation)
+	}
+	if f.Subject != "" {
+		id, set, err := parseSubject(f.Subject)
+		if err != nil {
+			return nil, err
+		}
+		t := relationTuple{SubjectID: id, SubjectSet: set}
+		for k, v := range t.subjectQuery() {
+			if k != "namespace" && k != "object" && k != "relation" {
+				q[k] = v
+			}
+		}
+	}
+	return q, nil
+}
+
+func (f *tupleFilter) summary() []client.SummaryLine {
+	summary := []client.SummaryLine{{Key: "Namespace", Value: f.Namespace}}
+	for _, part := range []client.SummaryLine{{Key: "Object", Value: f.Object}, {Key: "Relation", Value: f.Relation}, {Key: "Subject", Value: f.Subject}} {
+		if part.Value != "" {
+			summary = append(summary, part)
+		}
+	}
+	return summary
+}
+
+// countTuples counts the relation tuples matching the query using the read API.
+func countTuples(ctx context.Context, api *client.ProjectAPI, query url.Values) (int, error) {
+	q := url.Values{"page_size": {"500"}}
+	for k, v := range query {
+		q[k] = v
+	}
+
+	var count int
+	for {
+		var page struct {
+			RelationTuples []relationTuple `json:"relation_tuples"`
+			NextPageToken  string          `json:"next_page_token"`
+		}
+		if _, err := api.Do(ctx, http.MethodGet, "/relation-tuples", q, nil, &page); err != nil {
+			return 0, err
+		}
+		count += len(page.RelationTuples)
+		if page.NextPageToken == "" {
+			return count, nil
+		}
+		q.Set("page_token", page.NextPageToken)
+	}
+}
@@ -0,0 +1,156 @@
+package relationtuple
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/flagx"
+)
+
+// expandTree is the subject tree returned by the expand API. Depending on the API version, the
+// subject is either part of the node or wrapped in a relation tuple.
+type expandTree struct {
+	Type       string         `json:"type"`
+	SubjectID  *string        `json:"subject_id,omitempty"`
+	SubjectSet *subjectSet    `json:"subject_set,omitempty"`
+	Tuple      *relationTuple `json:"tuple,omitempty"`
+	Children   []*expandTree  `json:"children,omitempty"`
+}
+
+func (t *expandTree) subject() (*string, *subjectSet) {
+	if t.Tuple != nil {
+		return t.Tuple.SubjectID, t.Tuple.SubjectSet
+	}
+	return t.SubjectID, t.SubjectSet
+}
+
+func (t *expandTree) label() string {
+	id, set := t.subject()
+	switch {
+	case id != nil:
+		return *id
+	case set != nil:
+		return set.String()
+	}
+	return "<unknown subject>"
+}
+
+// renderExpandTree writes the tree as an indented ASCII tree. Subject sets which were seen in a parent
+// node are marked as cycles, and leaf subject sets, which the API returns when the maximum depth is
+// reached, are marked as truncated.
+func renderExpandTree(w io.Writer, tree *expandTree) {
+	var render func(node *expandTree, prefix, connector, childPrefix string, ancestors map[string]bool)
+	render = func(node *expandTree, prefix, connector, childPrefix string, ancestors map[string]bool) {
+		label := node.label()
+		_, set := node.subject()
+
+		var notes []string
+		switch node.Type {
+		case "union", "exclusion", "intersection":
+			label = "[" + node.Type + "] " + label
+		}
+		if set != nil && ancestors[set.String()] {
+			notes = append(notes, "(cycle)")
+		} else if set != nil && node.Type == "leaf" {
+			notes = append(notes, "(not expanded: max depth reached)")
+		}
+		if len(notes) > 0 {
+			label += " " + strings.Join(notes, " ")
+		}
+		_, _ = fmt.Fprintf(w, "%s%s%s\n", prefix, connector, label)
+
+		if set != nil {
+			if ancestors[set.String()] {
+				return
+			}
+			next := make(map[string]bool, len(ancestors)+1)
+			for k := range ancestors {
+				next[k] = true
+			}
+			next[set.String()] = true
+			ancestors = next
+		}
+
+		for k, child := range node.Children {
+			if k == len(node.Children)-1 {
+				render(child, prefix+childPrefix, "└── ", "    ", ancestors)
+			} else {
+				render(child, prefix+childPrefix, "├── ", "│   ", ancestors)
+			}
+		}
+	}
+	render(tree, "", "", "", map[string]bool{})
+}
+
+func NewExpandCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expand <relation> <namespace> <object>",
+		Args:  client.ExactArgs(3),
+		Short: "Expand a relation into the tree of subjects which have it",
+		Long: `Expand a relation of an object into the tree of subjects which have the relation.
+
+Unions, intersections, and exclusions are labeled in the tree. Subject sets which appear in one of
+their own parents are marked as cycles, and subject sets which were not expanded because the maximum
+depth was reached are marked as well. Use --format json to get the raw tree.`,
+		Example: `$ ory expand viewer documents doc-1 --max-depth 4
+
+[union] documents:doc-1#viewer
+├── user:alice
+└── [union] groups:admins#member
+    ├── user:bob
+    └── groups:owners#member (not expanded: max depth reached)
+
+$ ory expand viewer documents doc-1 --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewServiceAPI(client.ServicePermission)
+			if err != nil {
+				return err
+			}
+
+			t := &relationTuple{Relation: args[0], Namespace: args[1], Object: args[2]}
+			query := t.objectQuery()
+			query.Set("relation", t.Relation)
+			if depth := flagx.MustGetInt(cmd, maxDepthFlag); depth > 0 {
+				query.Set("max-depth", fmt.Sprintf("%d", depth))
+			}
+
+			var raw json.RawMessage
+			if _, err := api.Do(cmd.Context(), http.MethodGet, "/relation-tuples/expand", query, nil, &raw); err != nil {
+				return err
+			}
+
+			if client.IsMachineReadableFormat(cmd) {
+				client.PrintJSONAble(cmd, outputExpandTree(raw))
+				return nil
+			}
+
+			var tree expandTree
+			if err := json.Unmarshal(raw, &tree); err != nil {
+				return errors.Wrap(err, "unable to decode the expanded subject tree")
+			}
+			renderExpandTree(cmd.OutOrStdout(), &tree)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(maxDepthFlag, 0, "The maximum depth of the tree. Uses the server's default if not set.")
+	client.RegisterProjectFlag(cmd.Flags())
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
+}
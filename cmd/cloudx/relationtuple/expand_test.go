@@ -0,0 +1,42 @@
+package relationtuple
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderExpandTree(t *testing.T) {
+	var tree expandTree
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "type": "union",
+  "subject_set": {"namespace": "documents", "object": "doc-1", "relation": "viewer"},
+  "children": [
+    {"type": "leaf", "subject_id": "user:alice"},
+    {
+      "type": "intersection",
+      "subject_set": {"namespace": "groups", "object": "admins", "relation": "member"},
+      "children": [
+        {"type": "leaf", "subject_id": "user:bob"},
+        {"type": "leaf", "subject_set": {"namespace": "documents", "object": "doc-1", "relation": "viewer"}},
+        {"type": "leaf", "tuple": {"namespace": "groups", "object": "admins", "relation": "member", "subject_set": {"namespace": "groups", "object": "owners", "relation": "member"}}}
+      ]
+    },
+    {"type": "union", "subject_set": {"namespace": "groups", "object": "guests", "relation": "member"}}
+  ]
+}`), &tree))
+
+	var b bytes.Buffer
+	renderExpandTree(&b, &tree)
+	assert.Equal(t, `[union] documents:doc-1#viewer
+├── user:alice
+├── [intersection] groups:admins#member
+│   ├── user:bob
+│   ├── documents:doc-1#viewer (cycle)
+│   └── groups:owners#member (not expanded: max depth reached)
+└── [union] groups:guests#member
+`, b.String())
+}
@@ -0,0 +1,358 @@
+package relationtuple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/client/pool"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	fileFlag       = "file"
+	batchSizeFlag  = "batch-size"
+	replaceFlag    = "replace"
+	retriesFlag    = "retries"
+	throttledFlag  = "throttled-retries"
+	failedFileFlag = "failed-file"
+)
+
+func NewImportRelationTuplesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "relation-tuples",
+		Aliases: []string{"relation-tuple", "rts"},
+		Args:    client.NoArgs,
+		Short:   "Import relation tuples from a file into an Ory Cloud project",
+		Long: `Import relation tuples from a newline delimited JSON file into an Ory Cloud project.
+
+Each line of the file contains one relation tuple:
+
+	{"namespace":"documents","object":"doc-1","relation":"viewer","subject_id":"user:alice"}
+	{"namespace":"documents","object":"doc-1","relation":"viewer","subject_set":{"namespace":"groups","object":"admins","relation":"member"}}
+
+The relation tuples are inserted in transactional batches, ` + "`--workers`" + ` of which are sent concurrently. Failed
+batches are retried with an exponential backoff. If the API rate limits the import, it pauses for the time requested
+by the API, reduces the number of concurrent batches, and retries the batch without counting against ` + "`--retries`" + `.
+Once ` + "`--max-failures`" + ` requests failed in a row, e.g. because the API is unavailable, the import stops.
+Relation tuples which could not be imported are written to the file specified by ` + "`--failed-file`" + ` so that they
+can be imported again.
+
+Use ` + "`--rate`" + ` to keep the load on the project low, e.g. ` + "`--rate 600/min`" + `. The limit applies to all requests including
+retries. If the API rate limits the import as well, the stricter of both limits applies.
+
+Use ` + "`--replace`" + ` to delete all existing relation tuples of each record's namespace and object before inserting
+the new ones. This makes repeated imports of the same data idempotent. Replacing needs to be confirmed or requires
+--yes.`,
+		Example: `$ ory import relation-tuples --project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --file tuples.ndjson --batch-size 100
+
+TOTAL	INSERTED	FAILED	THROTTLED	FAILED FILE
+1000	1000		0	0
+
+$ cat tuples.ndjson | ory import relation-tuples --file - --replace --yes --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			batchSize := flagx.MustGetInt(cmd, batchSizeFlag)
+			if batchSize < 1 {
+				return errors.Errorf("--%s must be at least 1", batchSizeFlag)
+			}
+
+			workers, err := client.Workers(cmd)
+			if err != nil {
+				return err
+			}
+			maxFailures, err := client.MaxFailures(cmd)
+			if err != nil {
+				return err
+			}
+
+			file := flagx.MustGetString(cmd, fileFlag)
+			if file == "" {
+				return errors.Errorf("--%s must be set", fileFlag)
+			}
+
+			tuples, err := readTuplesFromFile(cmd, file)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewServiceAPI(client.ServicePermission)
+			if err != nil {
+				return err
+			}
+
+			if flagx.MustGetBool(cmd, replaceFlag) {
+				objects := map[string]bool{}
+				for _, t := range tuples {
+					objects[t.Namespace+":"+t.Object] = true
+				}
+				if err := h.ConfirmDestruction(&client.Destruction{
+					Action: "replace the existing relation tuples",
+					Summary: []client.SummaryLine{
+						{Key: "Objects", Value: fmt.Sprintf("%d", len(objects))},
+						{Key: "Relation tuples to insert", Value: fmt.Sprintf("%d", len(tuples))},
+					},
+				}); err != nil {
+					return err
+				}
+			}
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+
+			imp := &importer{
+				api:              api,
+				replace:          flagx.MustGetBool(cmd, replaceFlag),
+				retries:          flagx.MustGetInt(cmd, retriesFlag),
+				workers:          workers,
+				maxFailures:      maxFailures,
+				throttledRetries: flagx.MustGetInt(cmd, throttledFlag),
+				throttle:         h.NewThrottle(workers),
+				progress:         h.NewProgress("Importing relation tuples", len(tuples)),
+				deleted:          map[string]*deletion{},
+			}
+			summary, failed := imp.run(ctx, tuples, batchSize)
+
+			if len(failed) > 0 {
+				summary.FailedFile = flagx.MustGetString(cmd, failedFileFlag)
+				if err := writeTuples(summary.FailedFile, failed); err != nil {
+					return err
+				}
+			}
+
+			client.PrintRow(cmd, summary)
+			if summary.Interrupted {
+				return client.NewInterruptedError("the import was interrupted, the summary contains the partial results")
+			} else if summary.stopped != nil {
+				return errors.Wrap(summary.stopped, "the API seems to be unavailable, try again later")
+			} else if summary.Failed > 0 {
+				return client.FailSilently(cmd, client.ExitFailure)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP(fileFlag, fileFlag[:1], "", "Newline delimited JSON file containing the relation tuples. Use - to read from stdin.")
+	cmd.Flags().Int(batchSizeFlag, 100, "The number of relation tuples inserted in one transaction.")
+	cmd.Flags().Bool(replaceFlag, false, "Delete all relation tuples matching the namespace and object of each record before inserting it.")
+	cmd.Flags().Int(retriesFlag, 3, "How often a failed batch is retried.")
+	cmd.Flags().Int(throttledFlag, 10, "How often a batch is retried after the API rate limited it. These retries do not count against --retries.")
+	cmd.Flags().String(failedFileFlag, "failed-relation-tuples.ndjson", "The file to which relation tuples that could not be imported are written.")
+	client.RegisterWorkersFlag(cmd.Flags())
+	client.RegisterMaxFailuresFlag(cmd.Flags())
+	client.RegisterRateFlag(cmd.Flags())
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
+
+func readTuplesFromFile(cmd *cobra.Command, file string) ([]*relationTuple, error) {
+	f, err := client.OpenInputFile(cmd, file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readTuples(f)
+}
+
+func writeTuples(file string, tuples []*relationTuple) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open file for writing: %s", file)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, t := range tuples {
+		if err := enc.Encode(t); err != nil {
+			return errors.Wrapf(err, "unable to write relation tuple to file: %s", file)
+		}
+	}
+	return nil
+}
+
+type importer struct {
+	api      *client.ProjectAPI
+	replace  bool
+	retries  int
+	workers  int
+	progress *client.Progress
+
+	// breaker stops the import once maxFailures requests failed in a row.
+	maxFailures int
+	breaker     *pool.Breaker
+
+	// throttle pauses the import while the API rate limits it. Rate limited batches are retried up to
+	// throttledRetries times in addition to the retries of failed batches.
+	throttle         *client.Throttle
+	throttledRetries int
+
+	mu sync.Mutex
+	// deleted contains the deletions of the namespace and objects in replace mode. Tuples of the same object in
+	// other batches must not be deleted after they were inserted, so all batches wait for the same deletion.
+	deleted map[string]*deletion
+	// rateLimit is the rate limit of the last response and is used to pace the following requests.
+	rateLimit *client.RateLimit
+}
+
+// deletion deletes the relation tuples of one namespace and object. done is closed once err is set.
+type deletion struct {
+	done chan struct{}
+	err  error
+}
+
+func (i *importer) run(interrupt context.Context, tuples []*relationTuple, batchSize int) (*importSummary, []*relationTuple) {
+	ctx, breaker := client.NewBreaker(interrupt, i.maxFailures)
+	i.breaker = breaker
+	summary := &importSummary{Total: len(tuples)}
+	batches := chunkTuples(tuples, batchSize)
+
+	errs := pool.Stream(ctx, i.workers, len(batches), func(ctx context.Context, k int) error {
+		return i.withRetries(ctx, func() error { return i.importBatch(ctx, batches[k]) })
+	}, func(k int, err error) {
+		if err == nil {
+			i.progress.Add(len(batches[k]), 0)
+			return
+		}
+		if ctx.Err() == nil {
+			i.progress.Logf("Unable to import batch of %d relation tuples: %s", len(batches[k]), err)
+		}
+		i.progress.Add(len(batches[k]), len(batches[k]))
+	})
+
+	// Everything that was not imported, including the batches which were not started after the import stopped, is
+	// reported as failed so that it can be imported again.
+	var failed []*relationTuple
+	for k, err := range errs {
+		if err != nil {
+			failed = append(failed, batches[k]...)
+		}
+	}
+	summary.Failed = len(failed)
+	summary.Inserted = len(tuples) - len(failed)
+	summary.Interrupted = interrupt.Err() != nil
+	summary.stopped = breaker.Err()
+	summary.Throttled = i.throttle.Events()
+	if summary.stopped != nil && !summary.Interrupted {
+		i.progress.Halt()
+	} else {
+		i.progress.Finish(summary.Interrupted)
+	}
+
+	return summary, failed
+}
+
+func (i *importer) importBatch(ctx context.Context, batch []*relationTuple) error {
+	if i.replace {
+		for _, t := range batch {
+			if err := i.deleteObject(ctx, t); err != nil {
+				return err
+			}
+		}
+	}
+
+	deltas := make([]patchDelta, len(batch))
+	for k, t := range batch {
+		deltas[k] = patchDelta{Action: "insert", RelationTuple: t}
+	}
+
+	return i.send(ctx, http.MethodPatch, nil, deltas)
+}
+
+// deleteObject deletes the relation tuples of the namespace and object of t, unless another batch already did. If
+// another batch is deleting them right now, it waits for the deletion to finish. Failed deletions are attempted again
+// by the next batch.
+func (i *importer) deleteObject(ctx context.Context, t *relationTuple) error {
+	key := t.Namespace + ":" + t.Object
+	i.mu.Lock()
+	d, ok := i.deleted[key]
+	if !ok {
+		d = &deletion{done: make(chan struct{})}
+		i.deleted[key] = d
+	}
+	i.mu.Unlock()
+
+	if ok {
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-d.done:
+			return d.err
+		}
+	}
+
+	d.err = i.send(ctx, http.MethodDelete, t.objectQuery(), nil)
+	if d.err != nil {
+		i.mu.Lock()
+		delete(i.deleted, key)
+		i.mu.Unlock()
+	}
+	close(d.done)
+	return d.err
+}
+
+// send waits until the rate limit and the throttle allow another request and sends it.
+func (i *importer) send(ctx context.Context, method string, query url.Values, body interface{}) error {
+	i.mu.Lock()
+	rateLimit := i.rateLimit
+	i.mu.Unlock()
+	if err := rateLimit.Wait(ctx); err != nil {
+		return err
+	}
+	if err := i.throttle.Acquire(ctx); err != nil {
+		return err
+	}
+	res, err := i.api.Do(ctx, method, "/admin/relation-tuples", query, body, nil)
+	i.throttle.Release(res)
+	i.breaker.Record(err)
+	if res != nil {
+		r, _ := client.ParseRateLimit(res.Header, time.Now())
+		i.mu.Lock()
+		i.rateLimit = r
+		i.mu.Unlock()
+	}
+	return err
+}
+
+func (i *importer) withRetries(ctx context.Context, f func() error) (err error) {
+	backoff := 500 * time.Millisecond
+	for attempt, throttled := 0, 0; ; {
+		if err = f(); err == nil {
+			return nil
+		}
+
+		if client.IsThrottled(err) {
+			// The throttle already pauses the next request for as long as the API asked for.
+			if throttled >= i.throttledRetries {
+				return err
+			}
+			throttled++
+			continue
+		}
+
+		if attempt >= i.retries {
+			return err
+		}
+		attempt++
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package relationtuple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func TestImporterThrottling(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	inserted := map[string]bool{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Every other request is rate limited.
+		if requests++; requests%2 == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		var deltas []patchDelta
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&deltas))
+		for _, d := range deltas {
+			inserted[d.RelationTuple.String()] = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(ts.Close)
+
+	tuples := make([]*relationTuple, 25)
+	for k := range tuples {
+		subject := fmt.Sprintf("user:%d", k)
+		tuples[k] = &relationTuple{Namespace: "documents", Object: "doc-1", Relation: "viewer", SubjectID: &subject}
+	}
+
+	h := &client.CommandHelper{VerboseErrWriter: io.Discard}
+	imp := &importer{
+		api:      &client.ProjectAPI{URL: ts.URL, Client: ts.Client(), Header: http.Header{}},
+		progress: h.NewProgress("Importing relation tuples", len(tuples)),
+		// Rate limited batches do not count against the retries of failed batches.
+		retries:          0,
+		throttledRetries: 1,
+		throttle:         h.NewThrottle(1),
+		workers:          1,
+		deleted:          map[string]*deletion{},
+	}
+	summary, failed := imp.run(context.Background(), tuples, 10)
+
+	assert.Empty(t, failed)
+	assert.Equal(t, &importSummary{Total: 25, Inserted: 25, Throttled: 3}, summary)
+	assert.Len(t, inserted, 25, "no relation tuple was lost")
+}
+
+func TestImporterWorkers(t *testing.T) {
+	var mu sync.Mutex
+	inserted := map[string]bool{}
+	deletions := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodDelete {
+			object := r.URL.Query().Get("namespace") + ":" + r.URL.Query().Get("object")
+			for tuple := range inserted {
+				assert.False(t, strings.HasPrefix(tuple, object+"#"), "the relation tuples of %s were deleted after inserting %s", object, tuple)
+			}
+			deletions[object]++
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var deltas []patchDelta
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&deltas))
+		for _, d := range deltas {
+			inserted[d.RelationTuple.String()] = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(ts.Close)
+
+	tuples := make([]*relationTuple, 1000)
+	for k := range tuples {
+		subject := fmt.Sprintf("user:%d", k)
+		tuples[k] = &relationTuple{Namespace: "documents", Object: fmt.Sprintf("doc-%d", k%10), Relation: "viewer", SubjectID: &subject}
+	}
+
+	h := &client.CommandHelper{VerboseErrWriter: io.Discard}
+	imp := &importer{
+		api:              &client.ProjectAPI{URL: ts.URL, Client: ts.Client(), Header: http.Header{}},
+		progress:         h.NewProgress("Importing relation tuples", len(tuples)),
+		replace:          true,
+		workers:          32,
+		throttledRetries: 1,
+		throttle:         h.NewThrottle(32),
+		deleted:          map[string]*deletion{},
+	}
+	summary, failed := imp.run(context.Background(), tuples, 7)
+
+	assert.Empty(t, failed)
+	assert.Equal(t, &importSummary{Total: 1000, Inserted: 1000}, summary)
+	assert.Len(t, inserted, 1000, "no relation tuple was lost")
+	assert.Len(t, deletions, 10)
+	for object, n := range deletions {
+		assert.Equal(t, 1, n, "the relation tuples of %s are deleted once", object)
+	}
+}
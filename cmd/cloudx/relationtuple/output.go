@@ -0,0 +1,89 @@
+package relationtuple
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type importSummary struct {
+	Total       int    `json:"total"`
+	Inserted    int    `json:"inserted"`
+	Failed      int    `json:"failed"`
+	Throttled   int    `json:"throttled"`
+	FailedFile  string `json:"failed_file,omitempty"`
+	Interrupted bool   `json:"interrupted"`
+	// stopped is set if the import stopped because too many requests failed in a row.
+	stopped error
+}
+
+func (*importSummary) Header() []string {
+	return []string{"TOTAL", "INSERTED", "FAILED", "THROTTLED", "FAILED FILE"}
+}
+
+func (s *importSummary) Columns() []string {
+	return []string{
+		fmt.Sprintf("%d", s.Total),
+		fmt.Sprintf("%d", s.Inserted),
+		fmt.Sprintf("%d", s.Failed),
+		fmt.Sprintf("%d", s.Throttled),
+		s.FailedFile,
+	}
+}
+
+func (s *importSummary) Interface() interface{} {
+	return s
+}
+
+type checkResult struct {
+	Allowed bool `json:"allowed"`
+	// raw is the response of the check API, which machine readable formats print as is.
+	raw outputCheckResponse
+}
+
+type outputCheckResponse json.RawMessage
+
+func (r outputCheckResponse) MarshalJSON() ([]byte, error) {
+	return r, nil
+}
+
+// watchedCheck is a line of the output of watched checks in machine readable formats.
+type watchedCheck struct {
+	CheckedAt time.Time           `json:"checked_at"`
+	Response  outputCheckResponse `json:"response"`
+}
+
+func (r *checkResult) String() string {
+	if r.Allowed {
+		return "Allowed"
+	}
+	return "Denied"
+}
+
+type outputExpandTree json.RawMessage
+
+func (t outputExpandTree) String() string {
+	return string(t)
+}
+
+func (t outputExpandTree) MarshalJSON() ([]byte, error) {
+	return t, nil
+}
+
+func (*tupleFilter) Header() []string {
+	return []string{"NAMESPACE", "OBJECT", "RELATION", "SUBJECT"}
+}
+
+func (f *tupleFilter) Columns() []string {
+	columns := []string{f.Namespace, f.Object, f.Relation, f.Subject}
+	for k, c := range columns {
+		if c == "" {
+			columns[k] = "*"
+		}
+	}
+	return columns
+}
+
+func (f *tupleFilter) Interface() interface{} {
+	return f
+}
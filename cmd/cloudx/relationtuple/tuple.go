@@ -0,0 +1,150 @@
+package relationtuple
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+type (
+	subjectSet struct {
+		Namespace string `json:"namespace"`
+		Object    string `json:"object"`
+		Relation  string `json:"relation"`
+	}
+	relationTuple struct {
+		Namespace  string      `json:"namespace"`
+		Object     string      `json:"object"`
+		Relation   string      `json:"relation"`
+		SubjectID  *string     `json:"subject_id,omitempty"`
+		SubjectSet *subjectSet `json:"subject_set,omitempty"`
+	}
+	patchDelta struct {
+		Action        string         `json:"action"`
+		RelationTuple *relationTuple `json:"relation_tuple"`
+	}
+)
+
+func (s *subjectSet) String() string {
+	return fmt.Sprintf("%s:%s#%s", s.Namespace, s.Object, s.Relation)
+}
+
+func (t *relationTuple) String() string {
+	subject := ""
+	if t.SubjectID != nil {
+		subject = *t.SubjectID
+	} else if t.SubjectSet != nil {
+		subject = t.SubjectSet.String()
+	}
+	return fmt.Sprintf("%s:%s#%s@%s", t.Namespace, t.Object, t.Relation, subject)
+}
+
+func (t *relationTuple) validate() error {
+	switch {
+	case t.Namespace == "":
+		return errors.New("namespace must not be empty")
+	case t.Object == "":
+		return errors.New("object must not be empty")
+	case t.Relation == "":
+		return errors.New("relation must not be empty")
+	case t.SubjectID == nil && t.SubjectSet == nil:
+		return errors.New("one of subject_id or subject_set must be set")
+	case t.SubjectID != nil && t.SubjectSet != nil:
+		return errors.New("only one of subject_id or subject_set may be set")
+	}
+	return nil
+}
+
+// objectQuery returns the URL query which selects all relation tuples of the tuple's namespace and object.
+func (t *relationTuple) objectQuery() url.Values {
+	return url.Values{
+		"namespace": {t.Namespace},
+		"object":    {t.Object},
+	}
+}
+
+// readTuples reads newline delimited JSON relation tuples. Empty lines are skipped.
+func readTuples(r io.Reader) ([]*relationTuple, error) {
+	var tuples []*relationTuple
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var line int
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var t relationTuple
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&t); err != nil {
+			return nil, client.NewValidationError(errors.Wrapf(err, "unable to decode relation tuple on line %d", line), nil)
+		}
+		if err := t.validate(); err != nil {
+			return nil, client.NewValidationError(errors.Wrapf(err, "invalid relation tuple on line %d", line), nil)
+		}
+		tuples = append(tuples, &t)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to read relation tuples")
+	}
+	return tuples, nil
+}
+
+func chunkTuples(tuples []*relationTuple, size int) [][]*relationTuple {
+	var chunks [][]*relationTuple
+	for size < len(tuples) {
+		tuples, chunks = tuples[size:], append(chunks, tuples[:size])
+	}
+	if len(tuples) > 0 {
+		chunks = append(chunks, tuples)
+	}
+	return chunks
+}
+
+// parseSubject parses a subject which is either a subject ID (`user:alice`) or a subject set
+// in the form of `namespace:object#relation`.
+func parseSubject(s string) (*string, *subjectSet, error) {
+	hash := strings.LastIndex(s, "#")
+	if hash < 0 {
+		if s == "" {
+			return nil, nil, errors.New("subject must not be empty")
+		}
+		return &s, nil, nil
+	}
+
+	parts := strings.SplitN(s[:hash], ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || hash == len(s)-1 {
+		return nil, nil, errors.Errorf("subject set %q must be in the form of namespace:object#relation", s)
+	}
+	return nil, &subjectSet{Namespace: parts[0], Object: parts[1], Relation: s[hash+1:]}, nil
+}
+
+// subjectQuery returns the URL query of the tuple as expected by the check API.
+func (t *relationTuple) subjectQuery() url.Values {
+	q := url.Values{
+		"namespace": {t.Namespace},
+		"object":    {t.Object},
+		"relation":  {t.Relation},
+	}
+	if t.SubjectID != nil {
+		q.Set("subject_id", *t.SubjectID)
+	} else if t.SubjectSet != nil {
+		q.Set("subject_set.namespace", t.SubjectSet.Namespace)
+		q.Set("subject_set.object", t.SubjectSet.Object)
+		q.Set("subject_set.relation", t.SubjectSet.Relation)
+	}
+	return q
+}
@@ -0,0 +1,93 @@
+package relationtuple
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTuples(t *testing.T) {
+	t.Run("case=reads subject IDs and subject sets", func(t *testing.T) {
+		tuples, err := readTuples(strings.NewReader(`{"namespace":"documents","object":"doc-1","relation":"viewer","subject_id":"user:alice"}
+
+{"namespace":"documents","object":"doc-1","relation":"viewer","subject_set":{"namespace":"groups","object":"admins","relation":"member"}}
+`))
+		require.NoError(t, err)
+		require.Len(t, tuples, 2)
+		assert.Equal(t, "documents:doc-1#viewer@user:alice", tuples[0].String())
+		assert.Equal(t, "documents:doc-1#viewer@groups:admins#member", tuples[1].String())
+	})
+
+	t.Run("case=reports the line of invalid tuples", func(t *testing.T) {
+		_, err := readTuples(strings.NewReader(`{"namespace":"documents","object":"doc-1","relation":"viewer","subject_id":"user:alice"}
+{"namespace":"documents","object":"doc-1","relation":"viewer"}
+`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+
+	t.Run("case=rejects unknown fields", func(t *testing.T) {
+		_, err := readTuples(strings.NewReader(`{"namespace":"documents","object":"doc-1","relation":"viewer","subject":"user:alice"}`))
+		require.Error(t, err)
+	})
+}
+
+func TestChunkTuples(t *testing.T) {
+	tuples := make([]*relationTuple, 5)
+	for k := range tuples {
+		tuples[k] = new(relationTuple)
+	}
+
+	for size, expected := range map[int][]int{
+		1:  {1, 1, 1, 1, 1},
+		2:  {2, 2, 1},
+		5:  {5},
+		10: {5},
+	} {
+		chunks := chunkTuples(tuples, size)
+		lengths := make([]int, len(chunks))
+		for k, c := range chunks {
+			lengths[k] = len(c)
+		}
+		assert.Equal(t, expected, lengths, "size=%d", size)
+	}
+}
+
+func TestParseSubject(t *testing.T) {
+	id, set, err := parseSubject("user:alice")
+	require.NoError(t, err)
+	assert.Nil(t, set)
+	assert.Equal(t, "user:alice", *id)
+
+	id, set, err = parseSubject("groups:admins#member")
+	require.NoError(t, err)
+	assert.Nil(t, id)
+	assert.Equal(t, &subjectSet{Namespace: "groups", Object: "admins", Relation: "member"}, set)
+
+	for _, invalid := range []string{"", "groups#member", "groups:admins#", ":admins#member"} {
+		_, _, err := parseSubject(invalid)
+		assert.Error(t, err, "%q", invalid)
+	}
+}
+
+func TestTupleFilter(t *testing.T) {
+	q, err := (&tupleFilter{Namespace: "documents", Object: "doc-1", Subject: "groups:admins#member"}).query()
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"namespace":             {"documents"},
+		"object":                {"doc-1"},
+		"subject_set.namespace": {"groups"},
+		"subject_set.object":    {"admins"},
+		"subject_set.relation":  {"member"},
+	}, q)
+
+	q, err = (&tupleFilter{Namespace: "documents", Relation: "viewer", Subject: "user:alice"}).query()
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"namespace": {"documents"}, "relation": {"viewer"}, "subject_id": {"user:alice"}}, q)
+
+	_, err = (&tupleFilter{Object: "doc-1"}).query()
+	assert.EqualError(t, err, "--namespace must be set")
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ory/cli/cmd/cloudx/client"
 	"github.com/ory/cli/cmd/cloudx/proxy"
+	"github.com/ory/cli/cmd/cloudx/relationtuple"
 
 	"github.com/spf13/cobra"
 )
@@ -17,16 +19,30 @@ func NewRootCommand(parent *cobra.Command, project string, version string) *cobr
 
 	cmdName := strings.ToLower(project + " cloud")
 
-	cmd.AddCommand(NewAuthCmd())
-	cmd.AddCommand(NewLogoutCmd())
-	cmd.AddCommand(NewCreateCmd())
-	cmd.AddCommand(NewListCmd(parent))
-	cmd.AddCommand(NewDeleteCmd(parent))
-	cmd.AddCommand(NewPatchCmd())
-	cmd.AddCommand(NewUpdateCmd())
-	cmd.AddCommand(NewImportCmd(parent))
-	cmd.AddCommand(NewGetCmd(parent))
+	client.AddGroupedCommands(cmd, client.GroupAuth, NewAuthCmd(), NewLogoutCmd())
+	client.AddGroupedCommands(cmd, client.GroupResources,
+		NewCreateCmd(),
+		NewListCmd(parent),
+		NewDeleteCmd(parent),
+		NewPatchCmd(),
+		NewUpdateCmd(),
+		NewImportCmd(parent),
+		NewExportCmd(),
+		NewGetCmd(parent),
+		NewUseCmd(),
+		NewIsCmd(),
+		NewIntrospectCmd(),
+		NewTestCmd(),
+	)
+	client.AddGroupedCommands(cmd, client.GroupPermissions, relationtuple.NewExpandCmd())
+	cmd.AddCommand(NewConfigCmd())
+	cmd.AddCommand(NewPingCmd())
+	cmd.AddCommand(NewDoctorCmd())
+	cmd.AddCommand(NewExecCmd())
+	cmd.AddCommand(NewAPICmd())
+	cmd.AddCommand(NewPluginsCmd())
 	cmd.AddCommand(proxy.NewProxyCommand(cmdName, version))
 	cmd.AddCommand(proxy.NewTunnelCommand(cmdName, version))
+	client.EnableGlobalFlags(cmd)
 	return cmd
 }
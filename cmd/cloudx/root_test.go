@@ -0,0 +1,50 @@
+package cloudx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+func TestCommandNotFound(t *testing.T) {
+	cmd := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t))
+
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"get", "identiy"}, expected: "unknown command \"identiy\" for \"cloud get\"\n\nDid you mean this?\n\tidentity\n"},
+		{args: []string{"ls", "projcts"}, expected: "unknown command \"projcts\" for \"cloud list\"\n\nDid you mean this?\n\tprojects\n"},
+		{args: []string{"rm", "identiyt"}, expected: "unknown command \"identiyt\" for \"cloud delete\"\n\nDid you mean this?\n\tidentity\n"},
+		{args: []string{"describe", "courier-mesage"}, expected: "unknown command \"courier-mesage\" for \"cloud get\"\n\nDid you mean this?\n\tcourier-message\n"},
+		{args: []string{"lsit", "projects"}, expected: "unknown command \"lsit\" for \"cloud\"\n\nDid you mean this?\n\tlist\n"},
+	} {
+		t.Run("args="+tc.args[1], func(t *testing.T) {
+			_, _, err := cmd.Exec(nil, tc.args...)
+			require.Error(t, err)
+			assert.Equal(t, tc.expected, err.Error())
+		})
+	}
+}
+
+func TestArgumentValidation(t *testing.T) {
+	cmd := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t))
+
+	_, _, err := cmd.Exec(nil, "get", "project", "./project.json")
+	require.Error(t, err)
+	assert.Equal(t, client.ExitValidation, client.ExitCode(err))
+	assert.Equal(t, `invalid argument "./project.json": expected a project ID (UUID) or slug, e.g. ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 or good-wright-t7kzy3vugf, not a file path
+
+Example:
+  ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89`, err.Error())
+
+	_, _, err = cmd.Exec(nil, "get", "identity", "jane@example.com")
+	assert.ErrorContains(t, err, `invalid argument "jane@example.com": expected an identity ID (UUID)`)
+
+	_, _, err = cmd.Exec(nil, "list", "projects", "all")
+	assert.ErrorContains(t, err, "\"cloud list projects\" does not accept arguments, but got [\"all\"]\n\nExample:\n  ory list projects")
+}
@@ -0,0 +1,123 @@
+package selfservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+type selfServiceError struct {
+	ID    string `json:"id"`
+	Error struct {
+		Code    int    `json:"code"`
+		Status  string `json:"status"`
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// raw is the error container as returned by the API.
+	raw json.RawMessage
+}
+
+// payload returns the original error payload in compact form.
+func (e *selfServiceError) payload() string {
+	var container struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(e.raw, &container); err != nil {
+		return ""
+	}
+	var b bytes.Buffer
+	if err := json.Compact(&b, container.Error); err != nil {
+		return string(container.Error)
+	}
+	return b.String()
+}
+
+var errSelfServiceErrorNotFound = fmt.Errorf("error expired or %w", client.ErrNotFound)
+
+func getSelfServiceError(ctx context.Context, api *client.ProjectAPI, id string) (*selfServiceError, error) {
+	var raw json.RawMessage
+	if _, err := api.Do(ctx, http.MethodGet, "/self-service/errors", url.Values{"id": {id}}, nil, &raw); err != nil {
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone) {
+			return nil, errors.WithStack(errSelfServiceErrorNotFound)
+		}
+		return nil, err
+	}
+
+	var e selfServiceError
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, errors.Wrap(err, "unable to decode the self-service error")
+	}
+	e.raw = raw
+	return &e, nil
+}
+
+func NewGetSelfServiceErrorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "self-service-error <id> [<id>...]",
+		Aliases:     []string{"self-service-errors"},
+		Args:        client.MinimumNArgs(1, client.UUID("a self-service error ID")),
+		Short:       "Get self-service errors of an Ory Cloud project",
+		Annotations: map[string]string{client.QueryExampleAnnotation: "error.reason"},
+		Long: `Get the self-service errors with the given IDs from the public API of the selected project. These IDs are part of
+the URL users are redirected to when something goes wrong, e.g. https://example.com/error?id=...
+
+The errors are printed in the order of the IDs. Use --format json to get the raw error containers. Errors which
+expired or do not exist are reported and make the command exit with a non-zero exit code.`,
+		Example: `$ ory get self-service-error 9d1b2c3a-4e5f-4a6b-8c7d-9e0f1a2b3c4d --project my-project
+
+ID					CODE	STATUS			MESSAGE				REASON	CREATED AT		PAYLOAD
+9d1b2c3a-4e5f-4a6b-8c7d-9e0f1a2b3c4d	500	Internal Server Error	An internal server error occurred		2022-06-01T12:00:00Z	{"code":500,...}
+
+$ ory get self-service-errors 9d1b2c3a-4e5f-4a6b-8c7d-9e0f1a2b3c4d 1f2e3d4c-5b6a-4798-8a7b-6c5d4e3f2a1b --project my-project --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			api, err := h.NewServiceAPI(client.ServiceIdentity)
+			if err != nil {
+				return err
+			}
+			public := api.WithoutCredentials()
+
+			var found []*selfServiceError
+			var missing int
+			for _, id := range args {
+				e, err := getSelfServiceError(cmd.Context(), public, id)
+				if errors.Is(err, errSelfServiceErrorNotFound) {
+					h.Log.Errorf("Self-service error %s: %s", id, err)
+					missing++
+					continue
+				} else if err != nil {
+					return err
+				}
+				found = append(found, e)
+			}
+
+			if len(found) > 0 {
+				client.PrintTable(cmd, &outputSelfServiceErrors{errors: found})
+			}
+			if missing > 0 {
+				return client.FailSilently(cmd, client.ExitNotFound)
+			}
+			return nil
+		},
+	}
+
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
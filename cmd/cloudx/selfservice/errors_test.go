@@ -0,0 +1,42 @@
+package selfservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func TestGetSelfServiceError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/self-service/errors", r.URL.Path)
+		switch r.URL.Query().Get("id") {
+		case "found":
+			_, _ = w.Write([]byte(`{"id": "found", "error": {"code": 500, "status": "Internal Server Error", "message": "something went wrong", "debug": "stack"}}`))
+		case "expired":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	api := &client.ProjectAPI{URL: ts.URL, Client: ts.Client()}
+
+	e, err := getSelfServiceError(context.Background(), api, "found")
+	require.NoError(t, err)
+	assert.Equal(t, 500, e.Error.Code)
+	assert.Equal(t, "something went wrong", e.Error.Message)
+	assert.Equal(t, `{"code":500,"status":"Internal Server Error","message":"something went wrong","debug":"stack"}`, e.payload())
+
+	for _, id := range []string{"expired", "unknown"} {
+		_, err = getSelfServiceError(context.Background(), api, id)
+		assert.True(t, errors.Is(err, errSelfServiceErrorNotFound), "%+v", err)
+	}
+}
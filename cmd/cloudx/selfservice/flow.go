@@ -0,0 +1,144 @@
+package selfservice
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	cloud "github.com/ory/client-go"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	refreshFlag = "refresh"
+	aalFlag     = "aal"
+	renderFlag  = "render"
+)
+
+var flowTypes = []string{"login", "registration", "recovery", "verification", "settings"}
+
+type flow struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	UI        cloud.UiContainer `json:"ui"`
+}
+
+// methods returns the methods (node groups) available in the flow.
+func (f *flow) methods() []string {
+	seen := map[string]bool{}
+	for _, n := range f.UI.Nodes {
+		if n.Group != "default" {
+			seen[n.Group] = true
+		}
+	}
+	methods := make([]string, 0, len(seen))
+	for m := range seen {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+func NewCreateFlowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "self-service-flow <" + strings.Join(flowTypes, "|") + ">",
+		Aliases:   []string{"self-service-flows", "flow"},
+		Args:      client.ExactArgs(1, client.OneOf(flowTypes...)),
+		ValidArgs: flowTypes,
+		Short:     "Initialize a self-service flow of an Ory Cloud project for debugging",
+		Long: `Initialize an API self-service flow (as used by native apps) using the public API of the selected project and
+print it. This helps to debug which methods and fields the project offers, e.g. if a form misrenders in your app.
+
+Use --render to print the form fields and messages as a human readable outline. Nothing is submitted.
+
+The settings flow, as well as refreshing a login or upgrading it to --aal aal2, requires an end-user session which is
+passed using --token-stdin or --cookie. This is a session of a user of your project, not your Ory Console session.`,
+		Example: `$ ory create self-service-flow login --project my-project --render
+
+POST https://my-project.projects.oryapis.com/self-service/login?flow=5c1a2b3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d
+
+default:
+  csrf_token (hidden)
+  identifier (text, required) "Email"
+
+password:
+  password (password, required) "Password"
+  method (submit) "Sign in" = password
+
+$ ory create self-service-flow login --refresh --aal aal2 --token-stdin --format json < token.txt
+
+$ ory create self-service-flow registration --project my-project --id-only`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			header, hasSession, err := client.EndUserSessionHeader(cmd)
+			if err != nil {
+				return err
+			} else if args[0] == "settings" && !hasSession {
+				return errors.New("the settings flow requires an end-user session, please pass it using --token-stdin or --cookie")
+			}
+
+			query := url.Values{}
+			if flagx.MustGetBool(cmd, refreshFlag) {
+				query.Set("refresh", "true")
+			}
+			if aal := flagx.MustGetString(cmd, aalFlag); aal != "" {
+				query.Set("aal", aal)
+			}
+			if len(query) > 0 && args[0] != "login" {
+				return errors.Errorf("--%s and --%s can only be used with the login flow", refreshFlag, aalFlag)
+			}
+
+			api, err := h.NewServiceAPI(client.ServiceIdentity)
+			if err != nil {
+				return err
+			}
+			public := api.WithoutCredentials()
+			public.Header = header
+
+			var raw json.RawMessage
+			if _, err := public.Do(cmd.Context(), http.MethodGet, "/self-service/"+args[0]+"/api", query, nil, &raw); err != nil {
+				return err
+			}
+
+			if client.IsMachineReadableFormat(cmd) {
+				client.PrintJSONAble(cmd, outputRaw(raw))
+				return nil
+			}
+
+			var f flow
+			if err := json.Unmarshal(raw, &f); err != nil {
+				return errors.Wrap(err, "unable to decode the self-service flow")
+			}
+
+			if flagx.MustGetBool(cmd, renderFlag) {
+				if err := client.PrintFormOutline(cmd.OutOrStdout(), f.UI); err != nil {
+					return err
+				}
+			} else {
+				client.PrintRow(cmd, &outputFlow{flow: &f, raw: raw})
+			}
+			client.PrintIDs(cmd, f.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(refreshFlag, false, "Refresh the login of the end-user session.")
+	cmd.Flags().String(aalFlag, "", "Request the authenticator assurance level, e.g. aal2.")
+	cmd.Flags().Bool(renderFlag, false, "Print the form fields and messages as a human readable outline.")
+	client.RegisterIDOnlyFlag(cmd.Flags())
+	client.RegisterEndUserSessionFlags(cmd.Flags())
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
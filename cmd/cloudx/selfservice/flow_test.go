@@ -0,0 +1,19 @@
+package selfservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cloud "github.com/ory/client-go"
+)
+
+func TestFlowMethods(t *testing.T) {
+	f := &flow{UI: cloud.UiContainer{Nodes: []cloud.UiNode{
+		{Group: "default"},
+		{Group: "password"},
+		{Group: "oidc"},
+		{Group: "password"},
+	}}}
+	assert.Equal(t, []string{"oidc", "password"}, f.methods())
+}
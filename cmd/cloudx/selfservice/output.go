@@ -0,0 +1,77 @@
+package selfservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type outputRaw json.RawMessage
+
+func (r outputRaw) String() string {
+	return string(r)
+}
+
+func (r outputRaw) MarshalJSON() ([]byte, error) {
+	return r, nil
+}
+
+type outputFlow struct {
+	flow *flow
+	raw  json.RawMessage
+}
+
+func (*outputFlow) Header() []string {
+	return []string{"ID", "TYPE", "EXPIRES AT", "METHODS", "ACTION"}
+}
+
+func (o *outputFlow) Columns() []string {
+	return []string{
+		o.flow.ID,
+		o.flow.Type,
+		o.flow.ExpiresAt.Format(time.RFC3339),
+		strings.Join(o.flow.methods(), ", "),
+		o.flow.UI.Action,
+	}
+}
+
+func (o *outputFlow) Interface() interface{} {
+	return o.raw
+}
+
+type outputSelfServiceErrors struct {
+	errors []*selfServiceError
+}
+
+func (*outputSelfServiceErrors) Header() []string {
+	return []string{"ID", "CODE", "STATUS", "MESSAGE", "REASON", "CREATED AT", "PAYLOAD"}
+}
+
+func (o *outputSelfServiceErrors) Table() [][]string {
+	rows := make([][]string, len(o.errors))
+	for k, e := range o.errors {
+		rows[k] = []string{
+			e.ID,
+			fmt.Sprintf("%d", e.Error.Code),
+			e.Error.Status,
+			e.Error.Message,
+			e.Error.Reason,
+			e.CreatedAt.Format(time.RFC3339),
+			e.payload(),
+		}
+	}
+	return rows
+}
+
+func (o *outputSelfServiceErrors) Interface() interface{} {
+	raw := make([]json.RawMessage, len(o.errors))
+	for k, e := range o.errors {
+		raw[k] = e.raw
+	}
+	return raw
+}
+
+func (o *outputSelfServiceErrors) Len() int {
+	return len(o.errors)
+}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func NewIntrospectSessionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Args:  client.NoArgs,
+		Short: "Check an end-user session of an Ory Cloud project",
+		Long: `Check an end-user session issued by the selected project using the project's whoami endpoint and print the
+identity ID, traits, authenticator assurance level (AAL), and expiry of the session.
+
+This command is a debugging aid for app developers. The session token or cookie you check belongs to a user of your
+project and is passed using --token-stdin or --cookie. It is NOT the Ory Console session token stored in the CLI
+configuration, which is only used to look up the project and is never sent to the project's public API.
+
+The command exits with a non-zero exit code if the session is not active.`,
+		Example: `$ echo "$USER_SESSION_TOKEN" | ory introspect session --project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --token-stdin
+
+SESSION ID	2d1e5f3a-8b7c-4d6e-9f0a-1b2c3d4e5f6a
+ACTIVE		true
+IDENTITY ID	9f425a8d-7efc-4768-8f23-7647a74fdf13
+TRAITS		{"email":"jane@example.com"}
+AAL		aal1
+EXPIRES AT	2022-06-02T12:00:00Z
+
+$ ory introspect session --project my-project --cookie "ory_session_myproject=MTY1..."`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			header, ok, err := client.EndUserSessionHeader(cmd)
+			if err != nil {
+				return err
+			} else if !ok {
+				return errors.New("please pass the end-user session using --token-stdin or --cookie")
+			}
+
+			api, err := h.NewServiceAPI(client.ServiceIdentity)
+			if err != nil {
+				return err
+			}
+			public := api.WithoutCredentials()
+			public.Header = header
+
+			var raw json.RawMessage
+			if _, err := public.Do(cmd.Context(), http.MethodGet, "/sessions/whoami", nil, nil, &raw); err != nil {
+				var apiErr *client.APIError
+				if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+					client.PrintRow(cmd, &outputSession{})
+					return client.FailSilently(cmd, client.ExitNotAuthenticated)
+				}
+				return err
+			}
+
+			var s outputSession
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return errors.Wrap(err, "unable to decode the session")
+			}
+			s.raw = raw
+
+			client.PrintRow(cmd, &s)
+			if !s.Active {
+				return client.FailSilently(cmd, client.ExitNotAuthenticated)
+			}
+			return nil
+		},
+	}
+
+	client.RegisterEndUserSessionFlags(cmd.Flags())
+	client.RegisterProjectFlag(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,39 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type outputSession struct {
+	ID        string     `json:"id"`
+	Active    bool       `json:"active"`
+	AAL       string     `json:"authenticator_assurance_level"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Identity  struct {
+		ID     string          `json:"id"`
+		Traits json.RawMessage `json:"traits"`
+	} `json:"identity"`
+
+	raw json.RawMessage
+}
+
+func (*outputSession) Header() []string {
+	return []string{"SESSION ID", "ACTIVE", "IDENTITY ID", "TRAITS", "AAL", "EXPIRES AT"}
+}
+
+func (s *outputSession) Columns() []string {
+	var expiresAt string
+	if s.ExpiresAt != nil {
+		expiresAt = s.ExpiresAt.Format(time.RFC3339)
+	}
+	return []string{s.ID, fmt.Sprintf("%v", s.Active), s.Identity.ID, string(s.Identity.Traits), s.AAL, expiresAt}
+}
+
+func (s *outputSession) Interface() interface{} {
+	if s.raw == nil {
+		return map[string]bool{"active": false}
+	}
+	return s.raw
+}
@@ -0,0 +1,103 @@
+package cloudx_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+// TestSessionToken signs in using ORY_SESSION_TOKEN and --session-token, as in CI where there is neither a
+// configuration file nor a terminal.
+func TestSessionToken(t *testing.T) {
+	const ciEmail, ciToken = "ci@ory.sh", "s3cr3t-ci-token"
+	fake := newMockBackend()
+	fake.AddSessionToken(ciEmail, ciToken)
+	cloudxtest.Serve(t, fake)
+	getProject := []string{"get", "project", mockedProjectID, "--format", "json"}
+
+	t.Run("case=environment variable", func(t *testing.T) {
+		t.Setenv(client.SessionTokenEnv, ciToken)
+		config := testhelpers.NewConfigDir(t)
+		stdout, stderr, err := testhelpers.ConfigAwareCmd(config).Exec(closedStdin(t), getProject...)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, mockedProjectID)
+		assert.Contains(t, stderr, "You are authenticated as: "+ciEmail)
+		assert.NoFileExists(t, config, "the session is never written to the configuration file")
+	})
+
+	t.Run("case=quiet", func(t *testing.T) {
+		t.Setenv(client.SessionTokenEnv, ciToken)
+		stdout, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(closedStdin(t), append(getProject, "--quiet")...)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, mockedProjectID)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("case=the flag takes precedence over the environment variable", func(t *testing.T) {
+		t.Setenv(client.SessionTokenEnv, "invalid-token")
+		stdout, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(closedStdin(t), append(getProject, "--"+client.SessionTokenFlag, ciToken)...)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, mockedProjectID)
+	})
+
+	t.Run("case=invalid token", func(t *testing.T) {
+		t.Setenv(client.SessionTokenEnv, "invalid-token")
+		// Input which would sign in interactively must not be used.
+		stdin := bytes.NewBufferString("y\n" + ciEmail + "\n\n")
+		_, stderr, err := testhelpers.ConfigAwareCmd(testhelpers.NewConfigDir(t)).Exec(stdin, getProject...)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, client.ErrSessionExpired)
+		assert.Contains(t, err.Error(), "the session token set by ORY_SESSION_TOKEN was rejected with 401 Unauthorized")
+		assert.NotContains(t, stderr, "Do you already have an Ory Console account")
+	})
+
+	t.Run("case=--yes with a token", func(t *testing.T) {
+		t.Setenv(client.SessionTokenEnv, ciToken)
+		config := testhelpers.NewConfigDir(t)
+		stdout, stderr, err := testhelpers.ConfigAwareCmd(config).Exec(closedStdin(t), "auth", "--yes", "--format", "json")
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, ciEmail)
+		assert.NoFileExists(t, config)
+
+		stdout, stderr, err = testhelpers.ConfigAwareCmd(config).Exec(closedStdin(t), append(getProject, "--yes")...)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stdout, mockedProjectID)
+	})
+
+	t.Run("case=the configuration file is never changed", func(t *testing.T) {
+		t.Setenv(client.SessionTokenEnv, ciToken)
+		config := cloudxtest.WriteConfig(t, "dev@ory.sh", "mocked", "")
+		before, err := os.ReadFile(config)
+		require.NoError(t, err)
+
+		_, _, err = cloudxtest.NewCmd(config).Exec(closedStdin(t), "auth", "logout")
+		assert.ErrorIs(t, err, client.ErrSessionNotStored)
+		_, _, err = cloudxtest.NewCmd(config).Exec(closedStdin(t), "use", "project", mockedProjectID)
+		assert.ErrorIs(t, err, client.ErrSessionNotStored)
+
+		after, err := os.ReadFile(config)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+
+	t.Run("case=the configuration file is signed in as another identity", func(t *testing.T) {
+		t.Setenv(client.SessionTokenEnv, ciToken)
+		config := cloudxtest.WriteConfig(t, "dev@ory.sh", "mocked", "")
+
+		_, stderr, err := cloudxtest.NewCmd(config).Exec(closedStdin(t), getProject...)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "You are authenticated as: "+ciEmail, "the session token wins")
+		assert.NotContains(t, stderr, "The session token is used")
+
+		_, stderr, err = cloudxtest.NewCmd(config).Exec(closedStdin(t), append(getProject, "--verbose")...)
+		require.NoError(t, err, stderr)
+		assert.Contains(t, stderr, "belongs to "+ciEmail+", but the configuration file "+config+" is signed in as dev@ory.sh. The session token is used.")
+	})
+}
@@ -0,0 +1,184 @@
+package cloudx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+	"github.com/ory/cli/cmd/cloudx/testhelpers"
+)
+
+// TestSignInSecondFactor signs in to an account whose session requires a second factor. Unless the second factor is
+// accepted, e.g. because the CLI can not satisfy the factor the project requires, signing in must fail cleanly instead
+// of asking forever.
+func TestSignInSecondFactor(t *testing.T) {
+	const email, password = "dev@ory.sh", "s3cr3t-password"
+
+	// serve accepts the second factor if the submitted form sets the field accept to "123456". The session always
+	// requires a second factor if accept is empty.
+	serve := func(t *testing.T, group, accept string) *int32 {
+		fake := cloudxtest.NewBackend()
+		fake.AddAccount(email, password)
+
+		var submissions, accepted int32
+		cloudxtest.Serve(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hasSession := r.Header.Get("X-Session-Token") != ""
+			switch {
+			case r.URL.Path == "/sessions/whoami" && atomic.LoadInt32(&accepted) == 0:
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error": {"id": "session_aal2_required", "code": 403, "status": "Forbidden", "message": "aal2 required"}}`))
+			case r.URL.Path == "/self-service/login/api" && hasSession:
+				assert.Equal(t, "aal2", r.URL.Query().Get("aal"))
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(secondFactorFlow(r, group))
+			case r.URL.Path == "/self-service/login" && r.Method == http.MethodPost && hasSession:
+				atomic.AddInt32(&submissions, 1)
+				var form map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&form))
+				assert.Equal(t, group, form["method"])
+				if accept != "" && form[accept] == "123456" {
+					atomic.StoreInt32(&accepted, 1)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"session": {"id": "aa8d4e2c-9a5b-4f4e-8b1a-1f7a1b0e6c3d", "identity": {"id": "b1f0e3a0-0b8c-4d6e-9f2a-3c4d5e6f7a8b", "schema_id": "default", "schema_url": "", "traits": {}}}}`))
+			default:
+				fake.ServeHTTP(w, r)
+			}
+		}))
+		return &submissions
+	}
+	signIn := func(t *testing.T, codes int) error {
+		var r bytes.Buffer
+		_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
+		_, _ = r.WriteString(email + "\n") // Email:
+		_, _ = r.WriteString(strings.Repeat("123456\n", codes))
+		_, _, err := testhelpers.ConfigPasswordAwareCmd(testhelpers.NewConfigDir(t), password).Exec(&r, "auth", "--yes")
+		return err
+	}
+
+	t.Run("case=the second factor is never accepted", func(t *testing.T) {
+		submissions := serve(t, "totp", "")
+		err := signIn(t, 10)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "the session still requires a second factor after 3 attempts")
+		assert.EqualValues(t, 3, atomic.LoadInt32(submissions))
+	})
+
+	t.Run("case=unsupported second factor", func(t *testing.T) {
+		submissions := serve(t, "webauthn", "")
+		err := signIn(t, 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only offers webauthn, only totp and lookup_secret are supported")
+		assert.Zero(t, atomic.LoadInt32(submissions))
+	})
+
+	t.Run("case=lookup code", func(t *testing.T) {
+		submissions := serve(t, "lookup_secret", "lookup_secret")
+		require.NoError(t, signIn(t, 1))
+		assert.EqualValues(t, 1, atomic.LoadInt32(submissions))
+	})
+}
+
+// secondFactorFlow returns a login flow which asks for the second factor of the group.
+func secondFactorFlow(r *http.Request, group string) map[string]interface{} {
+	input := func(group, name, typ, value string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":       "input",
+			"group":      group,
+			"attributes": map[string]interface{}{"node_type": "input", "name": name, "type": typ, "value": value, "required": typ == "text", "disabled": false},
+			"messages":   []interface{}{},
+			"meta":       map[string]interface{}{},
+		}
+	}
+	// The lookup code is the only field of its group which is not named after the group and "_code".
+	code := group + "_code"
+	if group == "lookup_secret" {
+		code = group
+	}
+	return map[string]interface{}{
+		"id":          "5c1a2b3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+		"type":        "api",
+		"issued_at":   "2022-06-01T12:00:00Z",
+		"expires_at":  "2099-06-01T12:00:00Z",
+		"request_url": "http://" + r.Host + r.URL.RequestURI(),
+		"ui": map[string]interface{}{
+			"action": "http://" + r.Host + "/self-service/login?flow=5c1a2b3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+			"method": "POST",
+			"nodes": []interface{}{
+				input("default", "csrf_token", "hidden", ""),
+				input(group, code, "text", ""),
+				input(group, "method", "submit", group),
+			},
+		},
+	}
+}
+
+func TestSignInMessages(t *testing.T) {
+	const email = "dev@ory.sh"
+	fake := cloudxtest.NewBackend()
+	fake.AddAccount(email, "s3cr3t-password")
+	cloudxtest.Serve(t, fake)
+
+	var r bytes.Buffer
+	_, _ = r.WriteString("y\n") // Do you already have an Ory Console account you wish to use? [y/n]: y
+	_, _ = r.WriteString(strings.Repeat(email+"\n", 3))
+	_, stderr, err := testhelpers.ConfigPasswordAwareCmd(testhelpers.NewConfigDir(t), "wrong-password").Exec(&r, "auth", "--yes")
+	require.Error(t, err)
+
+	const invalid = "The provided credentials are invalid"
+	assert.Contains(t, err.Error(), "unable to sign in after 3 attempts: "+invalid)
+	assert.Equal(t, 2, strings.Count(stderr, "Your sign in attempt failed. Please try again!"), stderr)
+	assert.Equal(t, 2, strings.Count(stderr, invalid), "the message is printed on every retry:\n%s", stderr)
+}
+
+// TestSignInScriptedInput signs in reading every answer, including the password, from the stdin of the command.
+func TestSignInScriptedInput(t *testing.T) {
+	const email, password = "dev@ory.sh", "s3cr3t-password"
+	fake := newMockBackend()
+	fake.AddAccount(email, password)
+	cloudxtest.Serve(t, fake)
+
+	config := testhelpers.NewConfigDir(t)
+	var r bytes.Buffer
+	_, _ = r.WriteString("y\n")           // Do you already have an Ory Console account you wish to use? [y/n]: y
+	_, _ = r.WriteString(email + "\n")    // Email:
+	_, _ = r.WriteString(password + "\n") // Password:
+	_, _ = r.WriteString("\n")            // Email: dev@ory.sh — correct? [Y/n]:
+	stdout, stderr, err := testhelpers.ConfigAwareCmd(config).Exec(&r, "get", "project", mockedProjectID, "--format", "json")
+	require.NoError(t, err, stderr)
+	assert.Contains(t, stdout, mockedProjectID)
+	assert.Contains(t, stderr, "You are now signed in as: "+email)
+	assert.NotContains(t, stderr, password, "the password is never echoed")
+	assert.NotEmpty(t, testhelpers.ReadConfig(t, config).SessionToken)
+}
+
+// TestSignInMalformedIdentityID is a regression test for sessions whose identity ID is not a UUID. They were stored as
+// the zero UUID instead of failing.
+func TestSignInMalformedIdentityID(t *testing.T) {
+	const email, password = "dev@ory.sh", "s3cr3t-password"
+	fake := cloudxtest.NewBackend()
+	fake.AddAccount(email, password)
+	fake.HandleFunc("/sessions/whoami", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "aa8d4e2c-9a5b-4f4e-8b1a-1f7a1b0e6c3d", "identity": {"id": "", "schema_id": "default", "schema_url": "", "traits": {"email": "dev@ory.sh"}}}`))
+	})
+	cloudxtest.Serve(t, fake)
+
+	config := testhelpers.NewConfigDir(t)
+	var r bytes.Buffer
+	_, _ = r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
+	_, _ = r.WriteString(email + "\n") // Email:
+	_, _ = r.WriteString("\n")         // Email: dev@ory.sh — correct? [Y/n]:
+	_, _, err := testhelpers.ConfigPasswordAwareCmd(config, password).Exec(&r, "auth")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `the API returned the identity ID of the session "", which is not a valid UUID`)
+	assert.NoFileExists(t, config, "the session must not be stored")
+}
@@ -0,0 +1,76 @@
+package cloudx_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/x/cmdx"
+
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+const mockedProjectID = "ecaaa3cb-0730-4ee8-a6df-9553cdfeef89"
+
+// newMockBackend returns a fake backend in which dev@ory.sh is signed in with the session token "mocked" and which
+// serves the mocked project.
+func newMockBackend() *cloudxtest.Backend {
+	b := cloudxtest.NewBackend()
+	b.AddSessionToken("dev@ory.sh", "mocked")
+	b.AddProject(cloudxtest.Project{ID: mockedProjectID, Name: "Example Project", Slug: "good-wright-t7kzy3vugf"})
+	return b
+}
+
+// newMockedCmd returns a command executer which is authenticated with the mocked backend.
+func newMockedCmd(t *testing.T) *cmdx.CommandExecuter {
+	return cloudxtest.NewCmd(cloudxtest.WriteConfig(t, "dev@ory.sh", "mocked", ""))
+}
+
+func TestStdoutOnlyContainsData(t *testing.T) {
+	fake := newMockBackend()
+	fake.HandleFunc("/admin/courier/messages", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `</admin/courier/messages?page_size=100&page_token=next>; rel="next"`)
+		_, _ = fmt.Fprint(w, `[
+  {"id": "b6c6f3a1-6a1e-4a7e-8c8c-1b7e4b1c0f3e", "type": "email", "template_type": "verification_valid", "recipient": "jane@example.com", "status": "queued", "created_at": "2022-06-01T12:00:00Z", "updated_at": "2022-06-01T12:00:00Z"},
+  {"id": "0f6a1c5f-3c4d-4b6a-9f0e-2b8c7d6e5f4a", "type": "email", "template_type": "recovery_valid", "recipient": "john@example.com", "status": "sent", "created_at": "2022-06-01T12:00:00Z", "updated_at": "2022-06-01T12:00:00Z"}
+]`)
+	})
+	cloudxtest.Serve(t, fake)
+	cmd := newMockedCmd(t)
+
+	for _, args := range [][]string{
+		{"get", "project", mockedProjectID},
+		{"list", "projects"},
+		{"create", "project", "--name", "Example Project"},
+		{"list", "courier-messages", "--project", mockedProjectID, "--recipient", "jane@example.com", "--verbose"},
+	} {
+		t.Run("command="+strings.Join(args, "_"), func(t *testing.T) {
+			stdout, stderr, err := cmd.Exec(nil, append(args, "--format", "json")...)
+			require.NoError(t, err, stderr)
+			assert.True(t, json.Valid([]byte(stdout)), "stdout is not pure JSON:\n%s", stdout)
+			assert.Contains(t, stderr, "You are authenticated as: dev@ory.sh")
+
+			stdout, stderr, err = cmd.Exec(nil, append(args, "--format", "json", "--quiet")...)
+			require.NoError(t, err, stderr)
+			assert.True(t, json.Valid([]byte(stdout)), "stdout is not pure JSON:\n%s", stdout)
+			assert.Empty(t, stderr)
+		})
+	}
+
+	t.Run("command=auth_logout", func(t *testing.T) {
+		stdout, stderr, err := newMockedCmd(t).Exec(nil, "auth", "logout")
+		require.NoError(t, err, stderr)
+		assert.Empty(t, stdout)
+		assert.Contains(t, stderr, "You signed out successfully.")
+
+		stdout, stderr, err = newMockedCmd(t).Exec(nil, "auth", "logout", "--quiet")
+		require.NoError(t, err, stderr)
+		assert.Empty(t, stdout)
+		assert.Empty(t, stderr)
+	})
+}
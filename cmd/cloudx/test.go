@@ -0,0 +1,27 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/action"
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+)
+
+func NewTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Test resources",
+		Example: `$ ory test action --flow registration --hook-index 0 --project my-project
+
+$ ory test action --flow registration --hook-index 0 --project my-project --format json`,
+	}
+
+	client.AddGroupedCommands(cmd, client.GroupIdentity, action.NewTestActionCmd())
+
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
+}
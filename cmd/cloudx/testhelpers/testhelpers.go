@@ -129,6 +129,7 @@ func RegisterAccount(t require.TestingT, configDir string) (email, password stri
 	_, _ = r.WriteString("n\n")        // Please inform me about platform and security updates? [y/n]: n
 	_, _ = r.WriteString("n\n")        // I accept the Terms of Service [y/n]: n
 	_, _ = r.WriteString("y\n")        // I accept the Terms of Service [y/n]: y
+	_, _ = r.WriteString("\n")         // Email: FakeEmail(), Name: FakeName() — correct? [Y/n]:
 
 	exec := cmdx.CommandExecuter{
 		New: func() *cobra.Command {
@@ -157,6 +158,7 @@ func WithReAuth(t require.TestingT, email, password string) (*cmdx.CommandExecut
 	var r bytes.Buffer
 	r.WriteString("y\n")        // Do you already have an Ory Console account you wish to use? [y/n]: y
 	r.WriteString(email + "\n") // Email FakeEmail()
+	r.WriteString("\n")         // Email: FakeEmail() — correct? [Y/n]:
 	return cmd, &r
 }
 
@@ -33,6 +33,10 @@ func CreateDefaultAssets() (defaultConfig, defaultEmail, defaultPassword, defaul
 
 func RunAgainstStaging(m *testing.M) {
 	UseStaging()
+	// The tests check the human readable errors, which are reported as annotations when running in GitHub Actions.
+	if err := os.Unsetenv("GITHUB_ACTIONS"); err != nil {
+		panic(err)
+	}
 	os.Exit(m.Run())
 }
 
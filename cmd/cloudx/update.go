@@ -2,8 +2,11 @@ package cloudx
 
 import (
 	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/courier"
 	"github.com/ory/cli/cmd/cloudx/project"
 
+	"github.com/ory/x/cmdx"
+
 	"github.com/spf13/cobra"
 )
 
@@ -11,13 +14,23 @@ func NewUpdateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update resources",
+		Example: `$ ory update identity-config ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --file config.yaml --format yaml
+
+$ ory update opl --file namespaces.ts --project my-project`,
 	}
-	cmd.AddCommand(
-		project.NewProjectsUpdateCmd(),
+	client.AddGroupedCommands(cmd, client.GroupProject, project.NewProjectsUpdateCmd())
+	client.AddGroupedCommands(cmd, client.GroupIdentity,
 		project.NewUpdateIdentityConfigCmd(),
-		project.NewUpdateOAuth2ConfigCmd(),
+		courier.NewUpdateEmailTemplateCmd(),
+	)
+	client.AddGroupedCommands(cmd, client.GroupOAuth2, project.NewUpdateOAuth2ConfigCmd())
+	client.AddGroupedCommands(cmd, client.GroupPermissions,
 		project.NewUpdatePermissionConfigCmd(),
+		project.NewUpdateOPLCmd(),
 	)
 	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
 	return cmd
 }
@@ -0,0 +1,36 @@
+package cloudx_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+func TestUpdateOPLWithoutPermissionService(t *testing.T) {
+	fake := newMockBackend()
+	fake.HandleFunc("/opl/syntax/check", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[]}`))
+	})
+	cloudxtest.Serve(t, fake)
+	cmd := newMockedCmd(t)
+
+	_, stderr, err := cmd.Exec(nil, "patch", "project", mockedProjectID, "--remove", "/services/permission")
+	require.NoError(t, err, stderr)
+	require.False(t, gjson.GetBytes(fake.ProjectConfig(mockedProjectID), "services.permission").Exists())
+
+	file := filepath.Join(t.TempDir(), "namespaces.ts")
+	require.NoError(t, os.WriteFile(file, []byte("class User implements Namespace {}\n"), 0600))
+
+	stdout, stderr, err := cmd.Exec(nil, "update", "opl", "--file", file, "--project", mockedProjectID, "--yes", "--format", "json")
+	require.NoError(t, err, stderr)
+	assert.Contains(t, stderr, "+class User implements Namespace {}", "the whole file is shown as added")
+	assert.True(t, gjson.Get(stdout, "namespaces.location").Exists(), stdout)
+	assert.True(t, gjson.GetBytes(fake.ProjectConfig(mockedProjectID), "services.permission.config.namespaces.location").Exists())
+}
@@ -0,0 +1,27 @@
+package cloudx
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/project"
+	"github.com/ory/x/cmdx"
+)
+
+func NewUseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use",
+		Short: "Set the default resource, e.g. the project",
+		Example: `$ ory use project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89
+
+$ ory use project`,
+	}
+
+	client.AddGroupedCommands(cmd, client.GroupProject, project.NewUseProjectCmd())
+
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/identity"
+	"github.com/ory/cli/cmd/cloudx/project"
 	"github.com/ory/kratos/cmd/identities"
 	"github.com/ory/x/cmdx"
 )
@@ -12,13 +14,19 @@ func NewValidateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate resources",
+		Example: `$ ory validate opl --file namespaces.ts --project my-project
+
+$ ory validate opl --file namespaces.ts --project my-project --format json
+
+$ ory validate identity-schema --file customer.schema.json --require-identifier`,
 	}
 
-	cmd.AddCommand(identities.NewValidateIdentityCmd())
+	client.AddGroupedCommands(cmd, client.GroupIdentity, identities.NewValidateIdentityCmd(), identity.NewValidateIdentitySchemaCmd())
+	client.AddGroupedCommands(cmd, client.GroupPermissions, project.NewValidateOPLCmd())
 
 	client.RegisterConfigFlag(cmd.PersistentFlags())
 	client.RegisterYesFlag(cmd.PersistentFlags())
 	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
-	cmdx.RegisterJSONFormatFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
 	return cmd
 }
@@ -0,0 +1,54 @@
+package cloudx_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/cli/cmd/cloudx/cloudxtest"
+)
+
+func TestValidateExitCode(t *testing.T) {
+	fake := newMockBackend()
+	fake.HandleFunc("/schemas/default", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	cloudxtest.Serve(t, fake)
+	cmd := newMockedCmd(t)
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+		return file
+	}
+	valid := write("valid.schema.json", `{"type":"object","properties":{"traits":{"type":"object","properties":{"email":{"type":"string","format":"email","ory.sh/kratos":{"credentials":{"password":{"identifier":true}}}}}}}}`)
+	warnings := write("warnings.schema.json", `{"type":"object","properties":{"traits":{"type":"object","properties":{"email":{"type":"string","ory.sh/kratos":{"verification":{"via":"email"}}}}}}}`)
+	invalid := write("invalid.schema.json", `{"type":"object","properties":{}}`)
+
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		without, with int
+	}{
+		{name: "no problems", args: []string{"--file", valid}, without: client.ExitSuccess, with: client.ExitSuccess},
+		{name: "warnings", args: []string{"--file", warnings}, without: client.ExitSuccess, with: client.ExitFailure},
+		{name: "errors", args: []string{"--file", invalid}, without: client.ExitValidation, with: client.ExitFailure},
+		{name: "missing file flag", args: nil, without: client.ExitValidation, with: client.ExitValidation},
+		{name: "unreadable file", args: []string{"--file", filepath.Join(dir, "missing.json")}, without: client.ExitFailure, with: client.ExitOperational},
+		{name: "deployed schema unavailable", args: []string{"--file", valid, "--against-project", "--project", mockedProjectID, "--" + client.NoRetryFlag}, without: client.ExitFailure, with: client.ExitOperational},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			args := append([]string{"validate", "identity-schema"}, tc.args...)
+			_, stderr, err := cmd.Exec(nil, args...)
+			assert.Equal(t, tc.without, client.ExitCode(err), "without --exit-code: %s", stderr)
+			_, stderr, err = cmd.Exec(nil, append(args, "--"+client.ExitCodeFlag)...)
+			assert.Equal(t, tc.with, client.ExitCode(err), "with --exit-code: %s", stderr)
+		})
+	}
+}
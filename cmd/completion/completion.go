@@ -0,0 +1,197 @@
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	noDescriptionsFlag = "no-descriptions"
+	shellFlag          = "shell"
+	printFlag          = "print"
+	modifyRCFlag       = "modify-rc"
+)
+
+// NewCompletionCmd replaces the completion command cobra adds by default. Besides printing the completion scripts, it
+// can install them.
+func NewCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion",
+		Args:  client.NoArgs,
+		Short: "Generate or install the autocompletion script for the specified shell",
+		Long: `Generate the autocompletion script for bash, zsh, fish, or PowerShell, or install it using ` + "`ory completion install`" + `.
+
+See each sub-command's help for details on how to use the generated script.`,
+		Example: `$ ory completion install
+
+$ ory completion zsh > "${fpath[1]}/_ory"`,
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+
+	for _, s := range shells {
+		cmd.AddCommand(newShellCmd(s))
+	}
+	cmd.AddCommand(NewInstallCmd())
+	return cmd
+}
+
+func newShellCmd(s *shell) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               s.name,
+		Args:              client.NoArgs,
+		Short:             fmt.Sprintf("Generate the autocompletion script for %s", s.name),
+		Long:              fmt.Sprintf("Generate the autocompletion script for %[1]s and print it.\n\nTo install it in the conventional location, run `ory completion install --shell %[1]s`.", s.name),
+		Example:           fmt.Sprintf("$ ory completion %[1]s\n\n$ ory completion %[1]s --no-descriptions", s.name),
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return s.generate(cmd.Root(), cmd.OutOrStdout(), !flagx.MustGetBool(cmd, noDescriptionsFlag))
+		},
+	}
+	cmd.Flags().Bool(noDescriptionsFlag, false, "Disable completion descriptions.")
+	return cmd
+}
+
+func NewInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Args:  client.NoArgs,
+		Short: "Install the autocompletion script for your shell",
+		Long: `Detect your shell and write the autocompletion script to the location your shell loads completions from:
+
+  bash        ~/.local/share/bash-completion/completions/ory (requires the bash-completion package)
+  zsh         ~/.zsh/completions/_ory
+  fish        ~/.config/fish/completions/ory.fish
+  powershell  the Completions directory next to your PowerShell profile
+
+If the shell needs a line in its rc file (e.g. ~/.zshrc) to load the script, the line is printed. It is only added to
+the rc file when using --modify-rc. Running the command again updates the script and never adds the line twice.
+
+Use --shell to override the detected shell and --print to print the script instead of installing it.`,
+		Example: `$ ory completion install
+Installed the zsh completion script to /home/jane/.zsh/completions/_ory.
+To load it, add the following line to /home/jane/.zshrc and start a new shell:
+
+  fpath=(/home/jane/.zsh/completions $fpath); autoload -U compinit; compinit
+
+$ ory completion install --shell zsh --modify-rc
+
+$ ory completion install --shell powershell --print`,
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var s *shell
+			var err error
+			if name := flagx.MustGetString(cmd, shellFlag); name != "" {
+				s, err = findShell(name)
+			} else {
+				s, err = detectShell()
+			}
+			if err != nil {
+				return client.NewValidationError(err, nil)
+			}
+
+			if flagx.MustGetBool(cmd, printFlag) {
+				return s.generate(cmd.Root(), cmd.OutOrStdout(), true)
+			}
+
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return install(cmd, s, home, flagx.MustGetBool(cmd, modifyRCFlag))
+		},
+	}
+	cmd.Flags().String(shellFlag, "", fmt.Sprintf("The shell to install the autocompletion script for (%s). Detected from $SHELL if not set.", strings.Join(shellNames(), ", ")))
+	cmd.Flags().Bool(printFlag, false, "Print the autocompletion script instead of installing it.")
+	cmd.Flags().Bool(modifyRCFlag, false, "Add the line loading the autocompletion script to the rc file of your shell, if needed.")
+	return cmd
+}
+
+// install writes the completion script of the shell and reports what it did on stderr. Both the script and the rc
+// line are only written if they changed.
+func install(cmd *cobra.Command, s *shell, home string, modifyRC bool) error {
+	out := cmd.ErrOrStderr()
+	name := cmd.Root().Name()
+
+	var script bytes.Buffer
+	if err := s.generate(cmd.Root(), &script, true); err != nil {
+		return errors.WithStack(err)
+	}
+
+	path := s.script(home, name)
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, script.Bytes()) {
+		_, _ = fmt.Fprintf(out, "The %s completion script at %s is up to date.\n", s.name, path)
+	} else {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := os.WriteFile(path, script.Bytes(), 0644); err != nil {
+			return errors.WithStack(err)
+		}
+		_, _ = fmt.Fprintf(out, "Installed the %s completion script to %s.\n", s.name, path)
+	}
+
+	if s.rcFile == nil {
+		if s.name == "bash" {
+			_, _ = fmt.Fprintln(out, "The script is loaded by the bash-completion package. If completions do not work, install it using your package manager.")
+		}
+		_, _ = fmt.Fprintln(out, "Start a new shell to use the completions.")
+		return nil
+	}
+
+	rcFile, line := s.rcFile(home), s.rcLine(path)
+	rc, err := os.ReadFile(rcFile)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+
+	switch {
+	case containsLine(string(rc), line):
+		_, _ = fmt.Fprintf(out, "%s already loads the script. Start a new shell to use the completions.\n", rcFile)
+	case modifyRC:
+		if err := appendLine(rcFile, rc, line); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "Added the following line to %s. Start a new shell to use the completions.\n\n  %s\n", rcFile, line)
+	default:
+		_, _ = fmt.Fprintf(out, "To load it, add the following line to %s and start a new shell:\n\n  %s\n\nOr run the command again with --%s to add it for you.\n", rcFile, line, modifyRCFlag)
+	}
+	return nil
+}
+
+func containsLine(contents, line string) bool {
+	for _, l := range strings.Split(contents, "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
+
+func appendLine(rcFile string, rc []byte, line string) error {
+	if err := os.MkdirAll(filepath.Dir(rcFile), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	f, err := os.OpenFile(rcFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	prefix := ""
+	if len(rc) > 0 && !bytes.HasSuffix(rc, []byte("\n")) {
+		prefix = "\n"
+	}
+	if _, err := fmt.Fprintf(f, "%s\n# Ory CLI autocompletion\n%s\n", prefix, line); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(f.Close())
+}
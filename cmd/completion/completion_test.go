@@ -0,0 +1,118 @@
+package completion
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execInstall(t *testing.T, args ...string) (string, string) {
+	root := &cobra.Command{Use: "ory"}
+	root.AddCommand(&cobra.Command{Use: "version", Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(NewCompletionCmd())
+
+	var stdout, stderr bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SetArgs(append([]string{"completion", "install"}, args...))
+	require.NoError(t, root.Execute(), stderr.String())
+	return stdout.String(), stderr.String()
+}
+
+func setHome(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("ZDOTDIR", "")
+	return home
+}
+
+func TestInstall(t *testing.T) {
+	t.Run("case=installs the zsh script and prints the rc line", func(t *testing.T) {
+		home := setHome(t)
+		t.Setenv("SHELL", "/bin/zsh")
+
+		stdout, stderr := execInstall(t)
+		assert.Empty(t, stdout)
+		script := filepath.Join(home, ".zsh", "completions", "_ory")
+		assert.Contains(t, stderr, "Installed the zsh completion script to "+script)
+		assert.Contains(t, stderr, "fpath=("+filepath.Dir(script)+" $fpath); autoload -U compinit; compinit")
+		assert.FileExists(t, script)
+		assert.NoFileExists(t, filepath.Join(home, ".zshrc"))
+	})
+
+	t.Run("case=modifies the rc file only once", func(t *testing.T) {
+		home := setHome(t)
+		rcFile := filepath.Join(home, ".zshrc")
+		require.NoError(t, os.WriteFile(rcFile, []byte("export EDITOR=vim"), 0644))
+
+		_, stderr := execInstall(t, "--shell", "zsh", "--modify-rc")
+		assert.Contains(t, stderr, "Added the following line to "+rcFile)
+
+		_, stderr = execInstall(t, "--shell", "zsh", "--modify-rc")
+		assert.Contains(t, stderr, "is up to date")
+		assert.Contains(t, stderr, rcFile+" already loads the script")
+
+		rc, err := os.ReadFile(rcFile)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(rc), "export EDITOR=vim\n"), "%s", rc)
+		assert.Equal(t, 1, strings.Count(string(rc), "autoload -U compinit"), "%s", rc)
+	})
+
+	t.Run("case=does not need an rc line for fish", func(t *testing.T) {
+		home := setHome(t)
+		t.Setenv("SHELL", "/usr/bin/fish")
+
+		_, stderr := execInstall(t, "--modify-rc")
+		assert.FileExists(t, filepath.Join(home, ".config", "fish", "completions", "ory.fish"))
+		assert.NotContains(t, stderr, "following line")
+	})
+
+	t.Run("case=respects XDG_DATA_HOME for bash", func(t *testing.T) {
+		setHome(t)
+		data := t.TempDir()
+		t.Setenv("XDG_DATA_HOME", data)
+
+		_, stderr := execInstall(t, "--shell", "bash")
+		assert.FileExists(t, filepath.Join(data, "bash-completion", "completions", "ory"))
+		assert.Contains(t, stderr, "bash-completion package")
+	})
+
+	t.Run("case=installs the PowerShell script", func(t *testing.T) {
+		home := setHome(t)
+
+		_, stderr := execInstall(t, "--shell", "powershell", "--modify-rc")
+		script := filepath.Join(powerShellDir(home), "Completions", "ory.ps1")
+		assert.FileExists(t, script)
+		rc, err := os.ReadFile(filepath.Join(powerShellDir(home), "Microsoft.PowerShell_profile.ps1"))
+		require.NoError(t, err)
+		assert.Contains(t, string(rc), `. "`+script+`"`)
+		assert.Contains(t, stderr, "Added the following line")
+	})
+
+	t.Run("case=prints the script", func(t *testing.T) {
+		home := setHome(t)
+
+		stdout, _ := execInstall(t, "--shell", "fish", "--print")
+		assert.Contains(t, stdout, "complete -c ory")
+		assert.NoDirExists(t, filepath.Join(home, ".config"))
+	})
+
+	t.Run("case=fails for unknown shells", func(t *testing.T) {
+		setHome(t)
+		root := &cobra.Command{Use: "ory"}
+		root.AddCommand(NewCompletionCmd())
+		root.SetOut(new(bytes.Buffer))
+		root.SetErr(new(bytes.Buffer))
+		root.SetArgs([]string{"completion", "install", "--shell", "tcsh"})
+		assert.ErrorContains(t, root.Execute(), `unsupported shell "tcsh", expected one of bash, zsh, fish, powershell`)
+	})
+}
@@ -0,0 +1,139 @@
+package completion
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// shell knows how to generate and where to install the completion script of a shell.
+type shell struct {
+	name     string
+	generate func(root *cobra.Command, w io.Writer, descriptions bool) error
+	// script is the conventional location of the completion script, which the shell loads automatically or which is
+	// loaded by the rc line.
+	script func(home, name string) string
+	// rcFile is the file which needs to contain the rc line. It is empty if the shell loads the script by itself.
+	rcFile func(home string) string
+	rcLine func(script string) string
+}
+
+var shells = []*shell{
+	{
+		name: "bash",
+		generate: func(root *cobra.Command, w io.Writer, descriptions bool) error {
+			return root.GenBashCompletionV2(w, descriptions)
+		},
+		// The bash-completion package loads scripts from this directory when the command is completed first.
+		script: func(home, name string) string {
+			return filepath.Join(xdgDir("XDG_DATA_HOME", home, ".local", "share"), "bash-completion", "completions", name)
+		},
+	},
+	{
+		name: "zsh",
+		generate: func(root *cobra.Command, w io.Writer, descriptions bool) error {
+			if descriptions {
+				return root.GenZshCompletion(w)
+			}
+			return root.GenZshCompletionNoDesc(w)
+		},
+		script: func(home, name string) string {
+			return filepath.Join(home, ".zsh", "completions", "_"+name)
+		},
+		rcFile: func(home string) string {
+			if dir := os.Getenv("ZDOTDIR"); dir != "" {
+				return filepath.Join(dir, ".zshrc")
+			}
+			return filepath.Join(home, ".zshrc")
+		},
+		rcLine: func(script string) string {
+			return "fpath=(" + filepath.Dir(script) + " $fpath); autoload -U compinit; compinit"
+		},
+	},
+	{
+		name: "fish",
+		generate: func(root *cobra.Command, w io.Writer, descriptions bool) error {
+			return root.GenFishCompletion(w, descriptions)
+		},
+		script: func(home, name string) string {
+			return filepath.Join(xdgDir("XDG_CONFIG_HOME", home, ".config"), "fish", "completions", name+".fish")
+		},
+	},
+	{
+		name: "powershell",
+		generate: func(root *cobra.Command, w io.Writer, descriptions bool) error {
+			if descriptions {
+				return root.GenPowerShellCompletionWithDesc(w)
+			}
+			return root.GenPowerShellCompletion(w)
+		},
+		script: func(home, name string) string {
+			return filepath.Join(powerShellDir(home), "Completions", name+".ps1")
+		},
+		rcFile: func(home string) string {
+			return filepath.Join(powerShellDir(home), "Microsoft.PowerShell_profile.ps1")
+		},
+		rcLine: func(script string) string {
+			return `. "` + script + `"`
+		},
+	},
+}
+
+func shellNames() []string {
+	names := make([]string, len(shells))
+	for k, s := range shells {
+		names[k] = s.name
+	}
+	return names
+}
+
+func findShell(name string) (*shell, error) {
+	for _, s := range shells {
+		if s.name == name {
+			return s, nil
+		}
+	}
+	return nil, errors.Errorf("unsupported shell %q, expected one of %s", name, strings.Join(shellNames(), ", "))
+}
+
+// detectShell returns the login shell of the user. On Windows, where $SHELL is usually not set, it returns PowerShell.
+func detectShell() (*shell, error) {
+	name := strings.TrimSuffix(filepath.Base(os.Getenv("SHELL")), ".exe")
+	switch {
+	case name == "pwsh":
+		name = "powershell"
+	case name == "." && runtime.GOOS == "windows":
+		name = "powershell"
+	case name == ".":
+		return nil, errors.New("could not detect your shell because $SHELL is not set, please use the --shell flag")
+	}
+	return findShell(name)
+}
+
+func xdgDir(env, home string, fallback ...string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	return filepath.Join(append([]string{home}, fallback...)...)
+}
+
+// powerShellDir is the directory of the PowerShell profile. Windows PowerShell 5 uses a different directory than
+// PowerShell 7, which is only used if it exists.
+func powerShellDir(home string) string {
+	if runtime.GOOS != "windows" {
+		return filepath.Join(xdgDir("XDG_CONFIG_HOME", home, ".config"), "powershell")
+	}
+	dir := filepath.Join(home, "Documents", "PowerShell")
+	legacy := filepath.Join(home, "Documents", "WindowsPowerShell")
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return dir
+}
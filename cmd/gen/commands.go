@@ -0,0 +1,173 @@
+package gen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+// CommandsSchemaVersion is the version of the CommandTree schema. It is increased whenever fields are removed or
+// change their meaning, adding fields is not a breaking change.
+const CommandsSchemaVersion = 1
+
+// CommandTree describes all commands of the CLI for tooling, e.g. documentation generators.
+type CommandTree struct {
+	SchemaVersion int           `json:"schema_version"`
+	Commands      []CommandInfo `json:"commands"`
+}
+
+// CommandInfo describes a command. Hidden and deprecated commands are included and marked as such.
+type CommandInfo struct {
+	Path       string     `json:"path"`
+	Use        string     `json:"use"`
+	Short      string     `json:"short"`
+	Aliases    []string   `json:"aliases"`
+	Group      string     `json:"group,omitempty"`
+	Runnable   bool       `json:"runnable"`
+	Hidden     bool       `json:"hidden"`
+	Deprecated string     `json:"deprecated,omitempty"`
+	Args       []ArgInfo  `json:"args"`
+	Flags      []FlagInfo `json:"flags"`
+	Examples   []string   `json:"examples"`
+}
+
+// ArgInfo describes a positional argument, as documented by the usage line of the command.
+type ArgInfo struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Variadic bool   `json:"variadic"`
+}
+
+// FlagInfo describes a flag defined by a command. Persistent flags are inherited by all sub commands, they are only
+// listed for the command defining them.
+type FlagInfo struct {
+	Name       string `json:"name"`
+	Shorthand  string `json:"shorthand,omitempty"`
+	Type       string `json:"type"`
+	Default    string `json:"default"`
+	Usage      string `json:"usage"`
+	Persistent bool   `json:"persistent"`
+	Hidden     bool   `json:"hidden"`
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+func NewCommandsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commands",
+		Args:  client.NoArgs,
+		Short: "Print all commands with their flags, arguments, and examples",
+		Long: `Print all commands with their flags, arguments, and examples for tooling, e.g. documentation generators.
+
+The output is ordered by command path and flag name, so it can be committed and diffed. Hidden and deprecated commands
+and flags are included and marked as such. The schema_version field is increased on breaking changes of the schema.`,
+		Example: `$ ory gen commands --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client.PrintJSONAble(cmd, DescribeCommands(cmd.Root()))
+			return nil
+		},
+	}
+	client.RegisterFormatFlag(cmd.Flags())
+	return cmd
+}
+
+// DescribeCommands walks the tree of root and describes all commands sorted by their path.
+func DescribeCommands(root *cobra.Command) *CommandTree {
+	tree := &CommandTree{SchemaVersion: CommandsSchemaVersion, Commands: []CommandInfo{}}
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		tree.Commands = append(tree.Commands, describeCommand(cmd))
+		for _, c := range cmd.Commands() {
+			walk(c)
+		}
+	}
+	walk(root)
+	sort.Slice(tree.Commands, func(i, j int) bool { return tree.Commands[i].Path < tree.Commands[j].Path })
+	return tree
+}
+
+func describeCommand(cmd *cobra.Command) CommandInfo {
+	// Cobra adds the help flag lazily when a command is executed.
+	cmd.InitDefaultHelpFlag()
+
+	info := CommandInfo{
+		Path:       cmd.CommandPath(),
+		Use:        cmd.Use,
+		Short:      cmd.Short,
+		Aliases:    append([]string{}, cmd.Aliases...),
+		Group:      cmd.Annotations[client.GroupAnnotation],
+		Runnable:   cmd.Runnable(),
+		Hidden:     cmd.Hidden,
+		Deprecated: cmd.Deprecated,
+		Args:       describeArgs(cmd.Use),
+		Flags:      []FlagInfo{},
+		Examples:   splitExamples(cmd.Example),
+	}
+
+	persistent := cmd.PersistentFlags()
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		info.Flags = append(info.Flags, FlagInfo{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Persistent: persistent.Lookup(f.Name) != nil,
+			Hidden:     f.Hidden,
+			Deprecated: f.Deprecated,
+		})
+	})
+	sort.Slice(info.Flags, func(i, j int) bool { return info.Flags[i].Name < info.Flags[j].Name })
+	return info
+}
+
+// describeArgs parses the arguments from a usage line such as "project <id>" or "exec -- COMMAND [ARGS...]".
+// Arguments in brackets are optional, and arguments containing "..." are variadic.
+func describeArgs(use string) []ArgInfo {
+	args := []ArgInfo{}
+	fields := strings.Fields(use)
+	if len(fields) == 0 {
+		return args
+	}
+	depth := 0
+	for _, field := range fields[1:] {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		opens := strings.Count(field, "[")
+		optional := depth > 0 || opens > 0
+		depth += opens - strings.Count(field, "]")
+
+		name := strings.Trim(field, "[]<>")
+		variadic := strings.Contains(name, "...")
+		name = strings.Trim(strings.ReplaceAll(name, "...", ""), "[]<>")
+		if name != "" {
+			args = append(args, ArgInfo{Name: name, Required: !optional, Variadic: variadic})
+		}
+	}
+	return args
+}
+
+// splitExamples splits examples at the lines starting with "$ ". The output following a command belongs to its
+// example. Examples without such lines are returned as a single example.
+func splitExamples(example string) []string {
+	examples := []string{}
+	var current []string
+	flush := func() {
+		if e := strings.TrimSpace(strings.Join(current, "\n")); e != "" {
+			examples = append(examples, e)
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(example, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "$ ") {
+			flush()
+		}
+		current = append(current, line)
+	}
+	flush()
+	return examples
+}
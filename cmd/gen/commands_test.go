@@ -0,0 +1,72 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestDescribeCommands(t *testing.T) {
+	root := newTree()
+	root.AddCommand(&cobra.Command{Use: "legacy", Deprecated: "use get instead", Run: func(*cobra.Command, []string) {}})
+	tree := DescribeCommands(root)
+
+	assert.Equal(t, CommandsSchemaVersion, tree.SchemaVersion)
+	var paths []string
+	for _, c := range tree.Commands {
+		paths = append(paths, c.Path)
+	}
+	assert.Equal(t, []string{"ory", "ory dev", "ory get", "ory get project", "ory legacy"}, paths)
+
+	dev, legacy := tree.Commands[1], tree.Commands[4]
+	assert.True(t, dev.Hidden, "hidden commands are marked, not omitted")
+	assert.Equal(t, "use get instead", legacy.Deprecated)
+
+	get := tree.Commands[2]
+	assert.Equal(t, []FlagInfo{
+		{Name: "format", Type: "string", Usage: "Set the output format.", Persistent: true},
+		{Name: "help", Shorthand: "h", Type: "bool", Default: "false", Usage: "help for get"},
+	}, get.Flags)
+
+	project := tree.Commands[3]
+	assert.Equal(t, []ArgInfo{{Name: "id", Required: true}}, project.Args)
+	assert.Equal(t, []string{"$ ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format json"}, project.Examples)
+	assert.Equal(t, []string{"help", "secret"}, []string{project.Flags[0].Name, project.Flags[1].Name}, "inherited flags are listed for their command only")
+	assert.True(t, project.Flags[1].Hidden)
+}
+
+func TestDescribeArgs(t *testing.T) {
+	for use, args := range map[string][]ArgInfo{
+		"version":                           {},
+		"exec -- COMMAND [ARGS...]":         {{Name: "COMMAND", Required: true}, {Name: "ARGS", Variadic: true}},
+		"self-service-error <id> [<id>...]": {{Name: "id", Required: true}, {Name: "id", Variadic: true}},
+		"allowed [<subject> <relation>]":    {{Name: "subject"}, {Name: "relation"}},
+		"api METHOD PATH":                   {{Name: "METHOD", Required: true}, {Name: "PATH", Required: true}},
+	} {
+		assert.Equal(t, args, describeArgs(use), use)
+	}
+}
+
+func TestSplitExamples(t *testing.T) {
+	assert.Equal(t, []string{"$ ory ping\nCHECK\tSTATUS", "$ ory ping --format json"}, splitExamples("$ ory ping\nCHECK\tSTATUS\n\n$ ory ping --format json"))
+	assert.Equal(t, []string{"ory version"}, splitExamples("ory version"))
+	assert.Equal(t, []string{}, splitExamples(""))
+}
+
+func TestCommandsCmd(t *testing.T) {
+	root := newTree()
+	root.AddCommand(NewCommandsCmd())
+	var stdout bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetArgs([]string{"commands", "--format", "json"})
+	require.NoError(t, root.Execute())
+
+	require.True(t, json.Valid(stdout.Bytes()), stdout.String())
+	assert.EqualValues(t, CommandsSchemaVersion, gjson.Get(stdout.String(), "schema_version").Int())
+	assert.Equal(t, `["format","help","query"]`, gjson.Get(stdout.String(), `commands.#(path=="ory commands").flags.#.name`).Raw)
+}
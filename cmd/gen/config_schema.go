@@ -0,0 +1,28 @@
+package gen
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+)
+
+func NewConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "config-schema",
+		Args:  client.NoArgs,
+		Short: "Print the JSON Schema of the configuration file",
+		Long: `Print the JSON Schema of the configuration file (~/.ory-cloud.json), so that editors can validate and complete it.
+
+The schema is derived from the configuration the CLI reads, and is published at ` + client.ConfigSchemaID + `.
+Commands validate the configuration file against it if --` + client.StrictConfigFlag + ` is set.`,
+		Example: `$ ory gen config-schema > spec/config.schema.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := client.ConfigSchemaJSON()
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+}
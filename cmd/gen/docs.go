@@ -0,0 +1,125 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"github.com/spf13/pflag"
+
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/flagx"
+)
+
+const (
+	FormatMan      = "man"
+	FormatMarkdown = "markdown"
+
+	formatFlag        = "format"
+	dirFlag           = "dir"
+	includeHiddenFlag = "include-hidden"
+)
+
+func NewGenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "gen",
+		Short:  "Generate files from the Ory CLI, e.g. its documentation",
+		Hidden: true,
+	}
+	cmd.AddCommand(NewDocsCmd())
+	cmd.AddCommand(NewCommandsCmd())
+	cmd.AddCommand(NewConfigSchemaCmd())
+	return cmd
+}
+
+func NewDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Args:  client.NoArgs,
+		Short: "Generate man pages or a markdown reference of all commands",
+		Long: `Generate one file per command containing its flags, examples, and links to its parent and sub commands.
+
+The output does not contain timestamps, so the generated files can be committed and diffed. The date of man pages is
+taken from SOURCE_DATE_EPOCH and defaults to the Unix epoch. Hidden commands, e.g. the developer tools, and hidden
+flags are skipped unless --include-hidden is set.`,
+		Example: `$ ory gen docs --format man --dir ./man
+
+$ ory gen docs --format markdown --dir ./docs/cli --include-hidden`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := flagx.MustGetString(cmd, formatFlag)
+			dir := flagx.MustGetString(cmd, dirFlag)
+			if format != FormatMan && format != FormatMarkdown {
+				return client.NewValidationError(errors.Errorf("unknown format %q, expected %s or %s", format, FormatMan, FormatMarkdown), nil)
+			}
+
+			if err := GenerateDocs(cmd.Root(), format, dir, flagx.MustGetBool(cmd, includeHiddenFlag)); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Generated the %s documentation in %s.\n", format, dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(formatFlag, FormatMarkdown, fmt.Sprintf("The format of the documentation (%s or %s).", FormatMan, FormatMarkdown))
+	cmd.Flags().String(dirFlag, ".", "The directory to write the files to.")
+	cmd.Flags().Bool(includeHiddenFlag, false, "Include hidden commands and flags.")
+	return cmd
+}
+
+// GenerateDocs writes the documentation of root and all its sub commands to dir. The flags registered by the Enable*
+// functions of the client package are part of the tree already, so they are documented like all other flags.
+func GenerateDocs(root *cobra.Command, format, dir string, includeHidden bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	prepareTree(root, includeHidden)
+
+	switch format {
+	case FormatMan:
+		date, err := sourceDate()
+		if err != nil {
+			return err
+		}
+		return errors.WithStack(doc.GenManTree(root, &doc.GenManHeader{
+			Section: "1",
+			Date:    &date,
+			Source:  "Ory CLI",
+			Manual:  "Ory Manual",
+		}, dir))
+	case FormatMarkdown:
+		return errors.WithStack(doc.GenMarkdownTreeCustom(root, dir, func(string) string { return "" }, func(name string) string { return name }))
+	}
+	return errors.Errorf("unknown format %q", format)
+}
+
+// prepareTree removes the timestamps cobra adds to the generated files and, if includeHidden is set, reveals all
+// hidden commands and flags.
+func prepareTree(cmd *cobra.Command, includeHidden bool) {
+	cmd.DisableAutoGenTag = true
+	if includeHidden {
+		cmd.Hidden = false
+		reveal := func(f *pflag.Flag) { f.Hidden = false }
+		cmd.Flags().VisitAll(reveal)
+		cmd.PersistentFlags().VisitAll(reveal)
+	}
+	for _, c := range cmd.Commands() {
+		prepareTree(c, includeHidden)
+	}
+}
+
+// sourceDate returns the date of reproducible builds, see https://reproducible-builds.org/specs/source-date-epoch/.
+func sourceDate() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Unix(0, 0).UTC(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, client.NewValidationError(errors.Errorf("invalid SOURCE_DATE_EPOCH %q: %s", epoch, err), nil)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
@@ -0,0 +1,89 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTree() *cobra.Command {
+	root := &cobra.Command{Use: "ory", Short: "The ORY CLI"}
+	get := &cobra.Command{Use: "get", Short: "Get a resource"}
+	get.PersistentFlags().String("format", "", "Set the output format.")
+	project := &cobra.Command{
+		Use:     "project <id>",
+		Short:   "Get an Ory Cloud project",
+		Example: "$ ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format json",
+		Run:     func(*cobra.Command, []string) {},
+	}
+	project.Flags().Bool("secret", false, "A hidden flag.")
+	_ = project.Flags().MarkHidden("secret")
+	get.AddCommand(project)
+	dev := &cobra.Command{Use: "dev", Short: "Developer tools", Hidden: true, Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(get, dev)
+	return root
+}
+
+func readDir(t *testing.T, dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, e := range entries {
+		contents, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		require.NoError(t, err)
+		files[e.Name()] = string(contents)
+	}
+	return files
+}
+
+func TestGenerateDocs(t *testing.T) {
+	t.Run("case=markdown", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, GenerateDocs(newTree(), FormatMarkdown, dir, false))
+		files := readDir(t, dir)
+
+		assert.Contains(t, files, "ory.md")
+		assert.Contains(t, files, "ory_get.md")
+		assert.NotContains(t, files, "ory_dev.md")
+
+		project := files["ory_get_project.md"]
+		assert.Contains(t, project, "ory get project ecaaa3cb-0730-4ee8-a6df-9553cdfeef89 --format json")
+		assert.Contains(t, project, "--format string")
+		assert.Contains(t, project, "[ory get](ory_get.md)")
+		assert.NotContains(t, project, "--secret")
+		assert.NotContains(t, project, "Auto generated")
+		assert.Contains(t, files["ory_get.md"], "[ory get project](ory_get_project.md)")
+	})
+
+	t.Run("case=include hidden", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, GenerateDocs(newTree(), FormatMarkdown, dir, true))
+		files := readDir(t, dir)
+
+		assert.Contains(t, files, "ory_dev.md")
+		assert.Contains(t, files["ory_get_project.md"], "--secret")
+	})
+
+	t.Run("case=man", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, GenerateDocs(newTree(), FormatMan, dir, false))
+		files := readDir(t, dir)
+
+		assert.Contains(t, files, "ory-get-project.1")
+		assert.NotContains(t, files, "ory-dev.1")
+		assert.Contains(t, files["ory-get-project.1"], `"Jan 1970" "Ory CLI" "Ory Manual"`)
+	})
+
+	t.Run("case=is deterministic", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "1654084800")
+		first, second := t.TempDir(), t.TempDir()
+		require.NoError(t, GenerateDocs(newTree(), FormatMan, first, false))
+		require.NoError(t, GenerateDocs(newTree(), FormatMan, second, false))
+		assert.Equal(t, readDir(t, first), readDir(t, second))
+		assert.Contains(t, readDir(t, first)["ory-get-project.1"], `"Jun 2022"`)
+	})
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/ory/cli/cmd/cloudx"
 	"github.com/ory/cli/cmd/cloudx/client"
 	"github.com/ory/cli/cmd/cloudx/proxy"
+	"github.com/ory/cli/cmd/cloudx/relationtuple"
+	"github.com/ory/cli/cmd/completion"
+	"github.com/ory/cli/cmd/gen"
 	"github.com/ory/kratos/cmd/jsonnet"
 	"github.com/ory/x/cmdx"
 )
@@ -20,36 +23,58 @@ func NewRootCmd() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "ory",
 		Short: "The ORY CLI",
+		Long:  "The ORY CLI\n\n" + client.GettingStartedHelp + "\n\n" + client.ExitCodeHelp,
 	}
 
 	c.AddCommand(devCommands...)
-	c.AddCommand(
-		cloudx.NewAuthCmd(),
+	client.AddGroupedCommands(c, client.GroupResources,
 		cloudx.NewCreateCmd(),
-		jsonnet.NewFormatCmd(),
-		jsonnet.NewLintCmd(),
 		cloudx.NewDeleteCmd(c),
 		cloudx.NewGetCmd(c),
 		cloudx.NewListCmd(c),
 		cloudx.NewImportCmd(c),
+		cloudx.NewExportCmd(),
+		cloudx.NewIsCmd(),
+		cloudx.NewIntrospectCmd(),
+		cloudx.NewTestCmd(),
 		cloudx.NewPatchCmd(),
-		proxy.NewProxyCommand("ory", buildinfo.Version),
-		proxy.NewTunnelCommand("ory", buildinfo.Version),
 		cloudx.NewUpdateCmd(),
+		cloudx.NewUseCmd(),
 		cloudx.NewValidateCmd(),
-		versionCmd,
 	)
+	client.AddGroupedCommands(c, client.GroupAuth, cloudx.NewAuthCmd())
+	c.AddCommand(cloudx.NewConfigCmd())
+	c.AddCommand(cloudx.NewPingCmd())
+	c.AddCommand(cloudx.NewDoctorCmd())
+	c.AddCommand(cloudx.NewExecCmd())
+	c.AddCommand(cloudx.NewAPICmd())
+	c.AddCommand(cloudx.NewPluginsCmd())
+	client.AddGroupedCommands(c, client.GroupPermissions, relationtuple.NewExpandCmd())
+	c.AddCommand(
+		jsonnet.NewFormatCmd(),
+		jsonnet.NewLintCmd(),
+		proxy.NewProxyCommand("ory", buildinfo.Version),
+		proxy.NewTunnelCommand("ory", buildinfo.Version),
+		newVersionCmd(),
+		newSelfUpdateCmd(),
+		gen.NewGenCmd(),
+		completion.NewCompletionCmd(),
+	)
+	client.EnableGlobalFlags(c)
 
 	return c
 }
 
 func Execute() {
-	ctx := client.ContextWithClient(context.Background())
+	ctx, stop := client.ContextWithInterrupt(client.ContextWithClient(context.Background()))
 	rootCmd := NewRootCmd()
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
+	client.AddPluginCommand(rootCmd, os.Args[1:])
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	stop()
+	if err != nil {
 		if !errors.Is(err, cmdx.ErrNoPrintButFail) {
-			_, _ = fmt.Fprintln(rootCmd.ErrOrStderr(), err)
+			_, _ = fmt.Fprintln(rootCmd.ErrOrStderr(), client.NewColors(cmd, rootCmd.ErrOrStderr()).Error(err.Error()))
 		}
-		os.Exit(1)
+		os.Exit(client.ExitCode(err))
 	}
 }
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ory/cli/cmd/cloudx"
+)
+
+func TestRootCommandsShareGlobalFlags(t *testing.T) {
+	names := func(f *pflag.FlagSet) (names []string) {
+		f.VisitAll(func(f *pflag.Flag) { names = append(names, f.Name) })
+		return names
+	}
+
+	ory := NewRootCmd()
+	cloud := cloudx.NewRootCommand(ory, "Ory", "master")
+	assert.ElementsMatch(t, names(ory.PersistentFlags()), names(cloud.PersistentFlags()), "the tests run cloudx.NewRootCommand, so it must have the flags of the ory command")
+}
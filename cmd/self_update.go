@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/buildinfo"
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
+	"github.com/ory/x/flagx"
+)
+
+const toFlag = "to"
+
+func newSelfUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Args:  client.NoArgs,
+		Short: "Update this binary to the latest release",
+		Long: `Update this binary to the latest release, or to the release set by --to.
+
+The release archive for this platform is downloaded from GitHub and verified against the checksums published with the
+release. The binary is only replaced once the download is complete and verified. If the binary is not writable, e.g.
+because it was installed by a package manager, update it with the package manager instead.
+
+Use --dry-run to show what would be downloaded without replacing the binary.`,
+		Example: `$ ory self-update
+$ ory self-update --to v0.2.3
+$ ory self-update --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			h, err := client.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			to := flagx.MustGetString(cmd, toFlag)
+			u, err := h.PlanSelfUpdate(to)
+			if err != nil {
+				return err
+			}
+			if to == "" && !isNewer(u.Version, buildinfo.Version) {
+				h.Log.Infof("ory %s is the latest release.", buildinfo.Version)
+				return nil
+			}
+
+			if client.IsDryRun(cmd) {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Dry run: would download %s, verify it against %s, and replace %s with ory %s\n", u.URL, u.ChecksumsURL, u.Binary, u.Version)
+				return nil
+			}
+
+			h.Log.Infof("Downloading ory %s from %s", u.Version, u.URL)
+			if err := h.ApplySelfUpdate(u); err != nil {
+				return err
+			}
+			h.Log.Infof("Updated %s from %s to %s.", u.Binary, buildinfo.Version, u.Version)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(toFlag, "", "Install this version instead of the latest release, e.g. v0.2.3.")
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	return client.MarkDryRunCapable(cmd)
+}
+
+// isNewer returns true if the release is newer than the version. Development builds are always updated.
+func isNewer(release, version string) bool {
+	r, err := semver.NewVersion(strings.TrimPrefix(release, "v"))
+	if err != nil {
+		return true
+	}
+	v, err := semver.NewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return true
+	}
+	return r.GreaterThan(v)
+}
@@ -2,18 +2,87 @@ package cmd
 
 import (
 	"fmt"
-
-	"github.com/ory/cli/buildinfo"
+	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ory/cli/buildinfo"
+	"github.com/ory/cli/cmd/cloudx/client"
+	"github.com/ory/x/cmdx"
 )
 
-var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Display this binary's version, build time, and git hash of this build",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Version:    %s\n", buildinfo.Version)
-		fmt.Printf("Git Hash:   %s\n", buildinfo.GitHash)
-		fmt.Printf("Build Time: %s\n", buildinfo.Time)
-	},
+// breakingChanges lists the changes of this version which may break scripts using the CLI.
+var breakingChanges = []string{
+	"Prompts, warnings, and banners such as \"You are authenticated as: ...\" are printed to stderr instead of stdout. Stdout only contains the data of the command, e.g. the JSON of `ory get project --format json`, and --quiet only silences stderr.",
+}
+
+type versionInfo struct {
+	Version         string              `json:"version"`
+	GitHash         string              `json:"git_hash"`
+	BuildTime       string              `json:"build_time"`
+	GoVersion       string              `json:"go_version"`
+	Platform        string              `json:"platform"`
+	Update          *client.UpdateCheck `json:"update_check"`
+	BreakingChanges []string            `json:"breaking_changes,omitempty"`
+}
+
+func (v *versionInfo) String() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "Version:    %s\n", v.Version)
+	_, _ = fmt.Fprintf(&b, "Git Hash:   %s\n", v.GitHash)
+	_, _ = fmt.Fprintf(&b, "Build Time: %s\n", v.BuildTime)
+	_, _ = fmt.Fprintf(&b, "Go Version: %s\n", v.GoVersion)
+	_, _ = fmt.Fprintf(&b, "Platform:   %s\n", v.Platform)
+	_, _ = fmt.Fprintf(&b, "Update:     %s\n", v.Update)
+
+	if len(v.BreakingChanges) > 0 {
+		_, _ = fmt.Fprintln(&b, "\nBreaking changes:")
+		for _, c := range v.BreakingChanges {
+			_, _ = fmt.Fprintf(&b, "  - %s\n", c)
+		}
+	}
+	return b.String()
+}
+
+func newVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Args:  client.NoArgs,
+		Short: "Display this binary's version, build time, and git hash of this build",
+		Long: fmt.Sprintf(`Display this binary's version, build time, git hash, and Go version, and check whether a newer release exists.
+
+The latest release is looked up on GitHub and cached for a day. The check never fails the command: if GitHub is
+unreachable, e.g. in an offline environment, it is reported as skipped. Use --%s or set %s to disable it.`, client.NoCheckFlag, client.NoUpdateCheckEnv),
+		Example: `$ ory version
+$ ory version --no-check --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := &versionInfo{
+				Version:         buildinfo.Version,
+				GitHash:         buildinfo.GitHash,
+				BuildTime:       buildinfo.Time,
+				GoVersion:       runtime.Version(),
+				Platform:        runtime.GOOS + "/" + runtime.GOARCH,
+				BreakingChanges: breakingChanges,
+			}
+
+			if reason := client.UpdateCheckDisabled(cmd.Flags()); reason != "" {
+				v.Update = &client.UpdateCheck{Status: client.UpdateCheckSkipped, Reason: reason}
+			} else if h, err := client.NewCommandHelper(cmd); err != nil {
+				v.Update = &client.UpdateCheck{Status: client.UpdateCheckSkipped, Reason: err.Error()}
+			} else {
+				v.Update = h.CheckForUpdate(buildinfo.Version)
+			}
+
+			client.PrintJSONAble(cmd, v)
+			return nil
+		},
+	}
+
+	client.RegisterNoCheckFlag(cmd.Flags())
+	client.RegisterConfigFlag(cmd.PersistentFlags())
+	client.RegisterYesFlag(cmd.PersistentFlags())
+	cmdx.RegisterNoiseFlags(cmd.PersistentFlags())
+	client.RegisterFormatFlag(cmd.PersistentFlags())
+	return cmd
 }